@@ -19,7 +19,9 @@ package app
 import (
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/kubernetes"
@@ -30,6 +32,10 @@ import (
 	"github.com/cert-manager/trust-manager/cmd/trust-manager/app/options"
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/bundle"
+	"github.com/cert-manager/trust-manager/pkg/bundlecheck"
+	"github.com/cert-manager/trust-manager/pkg/cainjector"
+	"github.com/cert-manager/trust-manager/pkg/consumerrestart"
+	"github.com/cert-manager/trust-manager/pkg/crdcheck"
 	"github.com/cert-manager/trust-manager/pkg/webhook"
 )
 
@@ -72,11 +78,11 @@ func NewCommand() *cobra.Command {
 				LeaderElectionID:              "trust-manager-leader-election",
 				LeaderElectionReleaseOnCancel: true,
 				ReadinessEndpointName:         opts.ReadyzPath,
-				HealthProbeBindAddress:        fmt.Sprintf("0.0.0.0:%d", opts.ReadyzPort),
+				HealthProbeBindAddress:        net.JoinHostPort(opts.ReadyzHost, strconv.Itoa(opts.ReadyzPort)),
 				Port:                          opts.Webhook.Port,
 				Host:                          opts.Webhook.Host,
 				CertDir:                       opts.Webhook.CertDir,
-				MetricsBindAddress:            fmt.Sprintf("0.0.0.0:%d", opts.MetricsPort),
+				MetricsBindAddress:            net.JoinHostPort(opts.MetricsHost, strconv.Itoa(opts.MetricsPort)),
 				Logger:                        mlog,
 			})
 			if err != nil {
@@ -98,8 +104,41 @@ func NewCommand() *cobra.Command {
 				return fmt.Errorf("failed to register Bundle controller: %w", err)
 			}
 
+			// Add BundleCheck controller to manager.
+			if err := bundlecheck.AddController(ctx, mgr, opts.Logr.WithName("bundlecheck"), opts.Bundle.Naming); err != nil {
+				return fmt.Errorf("failed to register BundleCheck controller: %w", err)
+			}
+
+			// Add CA injector controllers to manager.
+			if opts.EnableCAInjection {
+				if err := cainjector.AddControllers(ctx, mgr, opts.Logr.WithName("cainjector"), opts.Bundle.Namespace, opts.Bundle.Naming); err != nil {
+					return fmt.Errorf("failed to register CA injector controllers: %w", err)
+				}
+			}
+
+			// Add consumer restart controllers to manager.
+			if opts.EnableConsumerRestart {
+				if err := consumerrestart.AddControllers(ctx, mgr, opts.Logr.WithName("consumerrestart")); err != nil {
+					return fmt.Errorf("failed to register consumer restart controllers: %w", err)
+				}
+			}
+
+			// Check that the installed Bundle CRD is compatible with the
+			// controller once the manager has started.
+			if err := crdcheck.AddToManager(mgr, opts.Logr.WithName("crdcheck")); err != nil {
+				return fmt.Errorf("failed to register CRD schema check: %w", err)
+			}
+
 			// Register webhook handlers with manager.
-			webhook.Register(mgr, webhook.Options{Log: opts.Logr.WithName("webhook")})
+			if err := webhook.Register(mgr, webhook.Options{
+				Log:                      opts.Logr.WithName("webhook"),
+				Namespace:                opts.Bundle.Namespace,
+				ClientCABundle:           opts.Webhook.ClientCABundle,
+				Naming:                   opts.Bundle.Naming,
+				DefaultAdditionalFormats: opts.Webhook.DefaultAdditionalFormats,
+			}); err != nil {
+				return fmt.Errorf("failed to register webhook: %w", err)
+			}
 
 			// Start all runnables and controller
 			return mgr.Start(ctx)
@@ -108,5 +147,13 @@ func NewCommand() *cobra.Command {
 
 	opts = opts.Prepare(cmd)
 
+	cmd.AddCommand(newRBACCommand())
+	cmd.AddCommand(newImportCommand())
+	cmd.AddCommand(newPublishNodeCAsCommand())
+	cmd.AddCommand(newWriteNodeTrustCommand())
+	cmd.AddCommand(newCSIDriverCommand())
+	cmd.AddCommand(newMirrorSecretsCommand())
+	cmd.AddCommand(newMigrateStorageCommand())
+
 	return cmd
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2/klogr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/csi"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+)
+
+// newCSIDriverCommand returns a command which runs the CSI driver mounting
+// Bundles into pods as ephemeral inline volumes.
+func newCSIDriverCommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+	var (
+		trustNamespace     string
+		nodeName           string
+		endpoint           string
+		targetNameTemplate string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "csi-driver",
+		Short: "Run the CSI driver mounting Bundles into pods",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(nodeName) == 0 {
+				return errors.New("--node-name must be set, or the NODE_NAME environment variable defined")
+			}
+
+			conventions, err := naming.New(naming.Options{TargetNameTemplate: targetNameTemplate})
+			if err != nil {
+				return err
+			}
+
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			log := klogr.New().WithName("csi-driver")
+			ctrl.SetLogger(log)
+
+			// Bundles and their targets in the trust Namespace are read from
+			// the cache, since volumes are republished periodically.
+			mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+				Scheme:             trustapi.GlobalScheme,
+				Namespace:          trustNamespace,
+				MetricsBindAddress: "0",
+				Logger:             log,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			driver := &csi.Driver{
+				Reader:         mgr.GetClient(),
+				Naming:         conventions,
+				TrustNamespace: trustNamespace,
+				NodeID:         nodeName,
+				Log:            log,
+			}
+			if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+				return driver.Run(ctx, endpoint)
+			})); err != nil {
+				return fmt.Errorf("failed to add CSI driver to manager: %w", err)
+			}
+
+			return mgr.Start(ctrl.SetupSignalHandler())
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+	// Targets are always read from the trust Namespace.
+	_ = fs.MarkHidden("namespace")
+	fs.StringVar(&trustNamespace,
+		"trust-namespace", "cert-manager",
+		"Namespace trust-manager sources trust bundles from. Mounted Bundles must sync their targets to this Namespace.")
+	fs.StringVar(&nodeName,
+		"node-name", os.Getenv("NODE_NAME"),
+		"Name of the node the driver runs on. Defaults to the NODE_NAME environment variable.")
+	fs.StringVar(&endpoint,
+		"csi-endpoint", "unix:///csi/csi.sock",
+		"Unix socket URL the CSI driver is served on.")
+	fs.StringVar(&targetNameTemplate,
+		"target-name-template", naming.DefaultTargetNameTemplate,
+		"Go template rendering the names of Bundle targets, which must match the template of the controller.")
+
+	return cmd
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/importer"
+	"github.com/cert-manager/trust-manager/pkg/rbac"
+)
+
+// newImportCommand returns a command which prints Bundles equivalent to the
+// trust configuration of other tools, to ease migrating to trust-manager.
+func newImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Print Bundles equivalent to the trust configuration of other tools",
+		Args:  cobra.NoArgs,
+	}
+
+	cmd.AddCommand(newImportCSIDriverSPIFFECommand())
+
+	// List the available importers, rather than the controller's flags
+	// printed by the help func inherited from the root command.
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\nUsage:\n  %s [command]\n\nAvailable Commands:\n", cmd.Short, cmd.CommandPath())
+		for _, sub := range cmd.Commands() {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %-20s %s\n", sub.Name(), sub.Short)
+		}
+	})
+
+	return cmd
+}
+
+// newImportCSIDriverSPIFFECommand returns a command which prints Bundles with
+// SPIFFE trust bundle targets, equivalent to the source CA bundles configured
+// for the cert-manager csi-driver-spiffe DaemonSets in a Namespace.
+func newImportCSIDriverSPIFFECommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+	var trustNamespace string
+
+	cmd := &cobra.Command{
+		Use:   "csi-driver-spiffe",
+		Short: "Print Bundles equivalent to the trust bundle configuration of cert-manager csi-driver-spiffe",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			cl, err := client.New(restConfig, client.Options{})
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes client: %w", err)
+			}
+
+			namespace := *kubeConfigFlags.Namespace
+			if len(namespace) == 0 {
+				namespace = "cert-manager"
+			}
+
+			var daemonSets appsv1.DaemonSetList
+			if err := cl.List(cmd.Context(), &daemonSets, client.InNamespace(namespace)); err != nil {
+				return fmt.Errorf("failed to list DaemonSets in Namespace %q: %w", namespace, err)
+			}
+
+			bundles, warnings := importer.FromCSIDriverSPIFFE(daemonSets.Items, trustNamespace)
+			for _, warning := range warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: %s\n", warning)
+			}
+
+			if len(bundles) == 0 {
+				return fmt.Errorf("no csi-driver-spiffe source CA bundle configuration found in Namespace %q", namespace)
+			}
+
+			objs := make([]client.Object, 0, len(bundles))
+			for _, bundle := range bundles {
+				objs = append(objs, bundle)
+			}
+
+			data, err := rbac.Encode(objs)
+			if err != nil {
+				return fmt.Errorf("failed to encode Bundles: %w", err)
+			}
+
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+	fs.Lookup("namespace").Usage = `Namespace of the csi-driver-spiffe DaemonSets. Defaults to "cert-manager".`
+	fs.StringVar(&trustNamespace,
+		"trust-namespace", "cert-manager",
+		"Namespace trust-manager sources trust bundles from.")
+
+	return cmd
+}
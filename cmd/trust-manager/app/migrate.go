@@ -0,0 +1,83 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2/klogr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/storagemigration"
+)
+
+// newMigrateStorageCommand returns a command which migrates the stored
+// objects of the trust-manager CRDs to the storage version of the installed
+// CRDs, so that upgrades which drop an API version don't require manual
+// changes to the CRDs.
+func newMigrateStorageCommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+
+	cmd := &cobra.Command{
+		Use:   "migrate-storage",
+		Short: "Migrate stored trust-manager objects to the storage version of the installed CRDs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			// Objects and CRDs are only handled as unstructured objects, so
+			// that objects of any stored version can be migrated.
+			c, err := client.New(restConfig, client.Options{Scheme: runtime.NewScheme()})
+			if err != nil {
+				return fmt.Errorf("failed to create client: %w", err)
+			}
+
+			log := klogr.New().WithName("migrate-storage")
+			ctx := ctrl.SetupSignalHandler()
+
+			for _, crd := range storagemigration.CRDNames {
+				result, err := storagemigration.Migrate(ctx, c, log, crd)
+				if err != nil {
+					return err
+				}
+
+				if result.UpToDate {
+					fmt.Fprintf(cmd.OutOrStdout(), "%s: all objects are stored as %s\n", result.CRD, result.StorageVersion)
+					continue
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: migrated %d objects to %s\n", result.CRD, result.Migrated, result.StorageVersion)
+			}
+
+			return nil
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+
+	return cmd
+}
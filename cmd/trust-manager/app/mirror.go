@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/klog/v2/klogr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/mirror"
+)
+
+// newMirrorSecretsCommand returns a command which runs the helper mirroring
+// the certificates of labeled Secrets in the trust Namespace into ConfigMaps,
+// so that Bundles can be built from TLS Secrets without the controller
+// reading Secrets.
+func newMirrorSecretsCommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+	var (
+		trustNamespace string
+		leaderElection bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "mirror-secrets",
+		Short: "Mirror the certificates of labeled Secrets in the trust Namespace into ConfigMaps",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			log := klogr.New().WithName("mirror")
+			ctrl.SetLogger(log)
+
+			mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+				Scheme:                        trustapi.GlobalScheme,
+				Namespace:                     trustNamespace,
+				LeaderElection:                leaderElection,
+				LeaderElectionNamespace:       trustNamespace,
+				LeaderElectionID:              "trust-manager-secret-mirror-leader-election",
+				LeaderElectionReleaseOnCancel: true,
+				MetricsBindAddress:            "0",
+				Logger:                        log,
+				// Only cache Secrets which are requested to be mirrored.
+				NewCache: cache.BuilderWithOptions(cache.Options{
+					SelectorsByObject: cache.SelectorsByObject{
+						new(corev1.Secret): {Label: labels.SelectorFromSet(labels.Set{trustapi.SecretMirrorLabelKey: "true"})},
+					},
+				}),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create manager: %w", err)
+			}
+
+			if err := mirror.AddController(mgr, log); err != nil {
+				return fmt.Errorf("failed to register Secret mirror controller: %w", err)
+			}
+
+			return mgr.Start(ctrl.SetupSignalHandler())
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+	// Secrets are always mirrored within the trust Namespace.
+	_ = fs.MarkHidden("namespace")
+	fs.StringVar(&trustNamespace,
+		"trust-namespace", "cert-manager",
+		"Namespace trust-manager sources trust bundles from, whose labeled Secrets are mirrored.")
+	fs.BoolVar(&leaderElection,
+		"leader-elect", true,
+		"Whether to use leader election, allowing multiple replicas of the helper to run.")
+
+	return cmd
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/nodecas"
+)
+
+// newPublishNodeCAsCommand returns a command which runs the node agent
+// publishing the system CA bundle of the node it runs on into the trust
+// Namespace, for inclusion in Bundles using the useNodeOSCAs source.
+func newPublishNodeCAsCommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+	var (
+		trustNamespace  string
+		nodeName        string
+		hostRoot        string
+		bundlePaths     []string
+		refreshInterval time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "publish-node-cas",
+		Short: "Publish the system CA bundle of this node into the trust Namespace",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(nodeName) == 0 {
+				return errors.New("--node-name must be set, or the NODE_NAME environment variable defined")
+			}
+
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			cl, err := client.New(restConfig, client.Options{})
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes client: %w", err)
+			}
+
+			for {
+				data, path, err := nodecas.ReadBundle(hostRoot, bundlePaths)
+				if err == nil {
+					var published bool
+					published, err = nodecas.Publish(cmd.Context(), cl, trustNamespace, nodeName, path, data)
+					if published {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Published system CA bundle %q of node %q to ConfigMap %s/%s\n", path, nodeName, trustNamespace, nodecas.ConfigMapName)
+					}
+				}
+
+				if refreshInterval == 0 {
+					return err
+				}
+				// Keep the last published bundle if the host bundle is broken
+				// or the API server is unavailable, and retry on the next tick.
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(refreshInterval):
+				}
+			}
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+	// The ConfigMap is always published to the trust Namespace.
+	_ = fs.MarkHidden("namespace")
+	fs.StringVar(&trustNamespace,
+		"trust-namespace", "cert-manager",
+		"Namespace trust-manager sources trust bundles from, which the system CA bundle is published to.")
+	fs.StringVar(&nodeName,
+		"node-name", os.Getenv("NODE_NAME"),
+		"Name of the node the agent runs on, recorded on the published ConfigMap. Defaults to the NODE_NAME environment variable.")
+	fs.StringVar(&hostRoot,
+		"host-root", "/host",
+		"Directory the node's root filesystem is mounted at. Bundle paths are resolved relative to this directory.")
+	fs.StringSliceVar(&bundlePaths,
+		"bundle-path", nodecas.DefaultBundlePaths,
+		"Paths on the node of the system CA bundle. The first path which exists is published.")
+	fs.DurationVar(&refreshInterval,
+		"refresh-interval", time.Hour,
+		"How often the system CA bundle is re-read and published. If 0, the bundle is published once and the agent exits.")
+
+	return cmd
+}
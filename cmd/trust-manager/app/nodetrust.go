@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/nodetrust"
+)
+
+// newWriteNodeTrustCommand returns a command which runs the node agent
+// writing the content of selected Bundles into the trust store of the node it
+// runs on.
+func newWriteNodeTrustCommand() *cobra.Command {
+	kubeConfigFlags := genericclioptions.NewConfigFlags(true)
+	var (
+		targetNamespace    string
+		bundleNames        []string
+		layoutName         string
+		hostRoot           string
+		targetNameTemplate string
+		refreshInterval    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "write-node-trust",
+		Short: "Write the content of Bundles into the trust store of this node",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(bundleNames) == 0 {
+				return errors.New("at least one --bundle must be set")
+			}
+
+			layout, ok := nodetrust.Layouts[layoutName]
+			if !ok {
+				return fmt.Errorf("unknown --layout %q, must be one of %s", layoutName, strings.Join(layoutNames(), ", "))
+			}
+
+			conventions, err := naming.New(naming.Options{TargetNameTemplate: targetNameTemplate})
+			if err != nil {
+				return err
+			}
+
+			restConfig, err := kubeConfigFlags.ToRESTConfig()
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes rest config: %w", err)
+			}
+
+			cl, err := client.New(restConfig, client.Options{Scheme: trustapi.GlobalScheme})
+			if err != nil {
+				return fmt.Errorf("failed to build kubernetes client: %w", err)
+			}
+
+			for {
+				bundles, err := nodetrust.ReadBundles(cmd.Context(), cl, conventions, targetNamespace, bundleNames)
+				if err == nil {
+					var changed []string
+					changed, err = nodetrust.Write(hostRoot, layout, bundles)
+					for _, path := range changed {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Updated trust anchor %q\n", path)
+					}
+				}
+
+				if refreshInterval == 0 {
+					return err
+				}
+				// Keep the last written anchors if a Bundle target is broken
+				// or the API server is unavailable, and retry on the next tick.
+				if err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Error: %s\n", err)
+				}
+
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(refreshInterval):
+				}
+			}
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	kubeConfigFlags.AddFlags(fs)
+	// Targets are always read from the target Namespace.
+	_ = fs.MarkHidden("namespace")
+	fs.StringVar(&targetNamespace,
+		"target-namespace", "cert-manager",
+		"Namespace the targets of the Bundles are read from. The Bundles must sync their targets to this Namespace.")
+	fs.StringSliceVar(&bundleNames,
+		"bundle", nil,
+		"Names of the Bundles written into the node's trust store.")
+	fs.StringVar(&layoutName,
+		"layout", "debian",
+		fmt.Sprintf("Layout of the node's trust store, one of %s. The node must regenerate its trust store from the written anchors.", strings.Join(layoutNames(), ", ")))
+	fs.StringVar(&hostRoot,
+		"host-root", "/host",
+		"Directory the node's root filesystem is mounted at. The layout's anchor directory is resolved relative to this directory.")
+	fs.StringVar(&targetNameTemplate,
+		"target-name-template", naming.DefaultTargetNameTemplate,
+		"Go template rendering the names of Bundle targets, which must match the template of the controller.")
+	fs.DurationVar(&refreshInterval,
+		"refresh-interval", time.Minute,
+		"How often the Bundles are re-read and written. If 0, the Bundles are written once and the agent exits.")
+
+	return cmd
+}
+
+// layoutNames returns the sorted names of the node trust store layouts.
+func layoutNames() []string {
+	names := make([]string, 0, len(nodetrust.Layouts))
+	for name := range nodetrust.Layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -17,12 +17,16 @@ limitations under the License.
 package options
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/rest"
@@ -30,7 +34,11 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/klog/v2/klogr"
 
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/bundle"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/webhook"
 )
 
 // Options is a struct to hold options for trust-manager
@@ -38,15 +46,28 @@ type Options struct {
 	logLevel        string
 	kubeConfigFlags *genericclioptions.ConfigFlags
 
+	// ReadyzHost is the host used to expose the readiness probe.
+	ReadyzHost string
 	// ReadyzPort if the port used to expose Prometheus metrics.
 	ReadyzPort int
 	// ReadyzPath if the HTTP path used to expose Prometheus metrics.
 	ReadyzPath string
 
-	// MetricsPort is the port for exposing Prometheus metrics on 0.0.0.0 on the
-	// path '/metrics'.
+	// MetricsHost is the host for exposing Prometheus metrics.
+	MetricsHost string
+	// MetricsPort is the port for exposing Prometheus metrics on the path
+	// '/metrics'.
 	MetricsPort int
 
+	// EnableCAInjection enables writing the data of Bundles to the caBundle
+	// fields of annotated webhook configurations, CustomResourceDefinitions
+	// and APIServices.
+	EnableCAInjection bool
+
+	// EnableConsumerRestart enables rolling out the pods of annotated
+	// workloads whenever the Bundles they consume change.
+	EnableConsumerRestart bool
+
 	// Logr is the shared base logger.
 	Logr logr.Logger
 
@@ -59,6 +80,18 @@ type Options struct {
 
 	// Bundle are options specific to the Bundle controller.
 	Bundle bundle.Options
+
+	// passwordProviderPlugins maps the names of password provider plugins to
+	// the paths of their binaries.
+	passwordProviderPlugins map[string]string
+
+	// defaultWeakCryptoAction is the default action for certificates with
+	// weak keys or signatures.
+	defaultWeakCryptoAction string
+
+	// naming configures the names, labels and annotations of the objects
+	// created by trust-manager.
+	naming naming.Options
 }
 
 // Webhook holds options specific to running the trust Webhook service.
@@ -66,6 +99,18 @@ type Webhook struct {
 	Host    string
 	Port    int
 	CertDir string
+
+	// ClientCABundle is the name of a Bundle supplying the CAs used to verify
+	// client certificates presented to the webhook.
+	ClientCABundle string
+
+	// DefaultAdditionalFormats are the additional formats set on Bundles
+	// which don't set them themselves.
+	DefaultAdditionalFormats *trustapi.AdditionalFormats
+
+	// defaultAdditionalFormats maps the names of default additional formats
+	// to the keys they are written to.
+	defaultAdditionalFormats map[string]string
 }
 
 // New constructs a new Options.
@@ -95,6 +140,45 @@ func (o *Options) Complete() error {
 
 	o.Bundle.Log = o.Logr.WithName("bundle")
 
+	switch action := trustapi.WeakCryptoAction(o.defaultWeakCryptoAction); action {
+	case trustapi.WeakCryptoActionIgnore, trustapi.WeakCryptoActionWarn, trustapi.WeakCryptoActionEnforce:
+		o.Bundle.DefaultWeakCryptoAction = action
+	default:
+		return fmt.Errorf("invalid default weak crypto action %q: must be one of Ignore, Warn or Enforce", o.defaultWeakCryptoAction)
+	}
+
+	o.Bundle.PasswordProviders = make(map[string]bundle.PasswordProvider, len(o.passwordProviderPlugins))
+	for name, path := range o.passwordProviderPlugins {
+		if len(name) == 0 || len(path) == 0 {
+			return fmt.Errorf("invalid password provider plugin %q=%q: name and path must be defined", name, path)
+		}
+
+		o.Bundle.PasswordProviders[name] = bundle.NewExecPasswordProvider(path)
+	}
+
+	for _, key := range o.Bundle.PropagatedBundleLabels {
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			return fmt.Errorf("invalid propagated bundle label %q: %s", key, strings.Join(msgs, ", "))
+		}
+		if strings.HasPrefix(key, trust.GroupName+"/") {
+			return fmt.Errorf("invalid propagated bundle label %q: labels in the %s domain are reserved", key, trust.GroupName)
+		}
+	}
+
+	o.Bundle.Naming, err = naming.New(o.naming)
+	if err != nil {
+		return fmt.Errorf("invalid naming conventions: %w", err)
+	}
+
+	o.Webhook.DefaultAdditionalFormats, err = webhook.ParseDefaultAdditionalFormats(o.Webhook.defaultAdditionalFormats)
+	if err != nil {
+		return fmt.Errorf("invalid default additional formats: %w", err)
+	}
+
+	if (len(o.Bundle.DistributionCertFile) == 0) != (len(o.Bundle.DistributionKeyFile) == 0) {
+		return errors.New("invalid distribution TLS configuration: both or neither of --distribution-tls-cert-file and --distribution-tls-key-file must be set")
+	}
+
 	return nil
 }
 
@@ -131,6 +215,10 @@ func (o *Options) addAppFlags(fs *pflag.FlagSet) {
 		"log-level", "v", "1",
 		"Log level (1-5).")
 
+	fs.StringVar(&o.ReadyzHost,
+		"readiness-probe-host", "0.0.0.0",
+		"Host to expose the readiness probe on.")
+
 	fs.IntVar(&o.ReadyzPort,
 		"readiness-probe-port", 6060,
 		"Port to expose the readiness probe.")
@@ -139,9 +227,25 @@ func (o *Options) addAppFlags(fs *pflag.FlagSet) {
 		"readiness-probe-path", "/readyz",
 		"HTTP path to expose the readiness probe server.")
 
+	fs.StringVar(&o.MetricsHost,
+		"metrics-host", "0.0.0.0",
+		"Host to expose Prometheus metrics on.")
+
 	fs.IntVar(&o.MetricsPort,
 		"metrics-port", 9402,
-		"Port to expose Prometheus metrics on 0.0.0.0 on path '/metrics'.")
+		"Port to expose Prometheus metrics on path '/metrics'. Metrics including exemplars "+
+			"are additionally exposed in the OpenMetrics format on path '"+bundle.OpenMetricsPath+"'.")
+
+	fs.BoolVar(&o.EnableCAInjection,
+		"enable-ca-injection", false,
+		"Write the data of Bundles to the caBundle fields of ValidatingWebhookConfigurations, "+
+			"MutatingWebhookConfigurations, CustomResourceDefinition conversion webhooks and APIServices annotated "+
+			"with '"+trustapi.CAInjectionAnnotationKey+"'. The referenced Bundle must sync to the trust namespace.")
+
+	fs.BoolVar(&o.EnableConsumerRestart,
+		"enable-consumer-restart", false,
+		"Roll out the pods of Deployments, StatefulSets and DaemonSets annotated with '"+
+			trustapi.RestartOnBundleChangeAnnotationKey+"' whenever the content of any of the listed Bundles changes.")
 }
 
 func (o *Options) addBundleFlags(fs *pflag.FlagSet) {
@@ -152,6 +256,101 @@ func (o *Options) addBundleFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.Bundle.DefaultPackageLocation,
 		"default-package-location", "",
 		"Path to a JSON file containing the default certificate package. If set, must be a valid package.")
+
+	fs.StringSliceVar(&o.Bundle.NamedDefaultPackageLocations,
+		"named-default-package-location", nil,
+		"Paths to JSON files containing additional default certificate packages, which Bundles can refer to by "+
+			"the name given in the package. May be given multiple times. Each must be a valid package with a unique name.")
+
+	fs.DurationVar(&o.Bundle.SourceResyncPeriod,
+		"source-resync-period", 10*time.Hour,
+		"Period at which informers for source resources in the trust namespace are resynced. "+
+			"Defaults to the resync period of the controller's other informers. "+
+			"Set to 0 to disable periodic resyncs and rely only on watch events.")
+
+	fs.StringToStringVar(&o.passwordProviderPlugins,
+		"password-provider-plugin", nil,
+		"Password provider plugins which Bundles can reference by name to source truststore target passwords, "+
+			"given as <name>=<path to plugin binary>. The plugin is executed with the password key as its only "+
+			"argument, and must write the password to stdout. Resolved passwords are reused for five minutes.")
+
+	fs.DurationVar(&o.Bundle.ExternalSourceRefreshPeriod,
+		"external-source-refresh-period", time.Hour,
+		"Period at which Bundles with sources outside of the trust namespace, such as object storage and remote "+
+			"cluster sources, are re-synced to pick up changes to the referenced objects. Unchanged objects in "+
+			"object storage are detected using their ETag and are not downloaded again. Sources may override "+
+			"this period using their refreshInterval field.")
+
+	fs.DurationVar(&o.Bundle.SourceHealthProbePeriod,
+		"source-health-probe-period", 0,
+		"Period at which the availability of object storage and remote cluster sources is probed, independently "+
+			"of their refresh. The result is exposed in the Bundle status and the trust_manager_bundle_source_healthy "+
+			"metric. Setting to 0 disables probing.")
+
+	fs.BoolVar(&o.Bundle.EnableClusterPlacement,
+		"enable-cluster-placement", false,
+		"Distribute Bundles with a placement to the managed clusters selected by the referenced Open Cluster "+
+			"Management Placement, using ManifestWorks. Requires the Open Cluster Management hub APIs to be installed.")
+
+	fs.IntVar(&o.Bundle.TargetWriteBudget,
+		"target-write-budget", 0,
+		"Maximum number of targets written when reconciling a single Bundle. If more targets need to be written, "+
+			"the rollout of the Bundle is continued in a later reconcile, so that a single Bundle update can't "+
+			"monopolise the controller's API client. Bundles with the Critical priority class are exempt from the "+
+			"budget, and Bundles with the Low priority class may use half of it. Zero disables the budget.")
+
+	fs.StringVar(&o.defaultWeakCryptoAction,
+		"default-weak-crypto-action", string(trustapi.WeakCryptoActionIgnore),
+		"Action taken for certificates with weak keys or signatures, such as RSA keys smaller than 2048 bits or "+
+			"SHA-1 signatures, in Bundles which don't set the action of their weakCrypto filter. One of Ignore, "+
+			"Warn or Enforce.")
+
+	fs.IntVar(&o.Bundle.StatusContentMaxBytes,
+		"status-content-max-bytes", bundle.DefaultStatusContentMaxBytes,
+		"Maximum size in bytes of bundle data which is published in the content field of the Bundle status. "+
+			"The hash and size of larger bundle data are still published. Zero omits the data of all Bundles.")
+
+	fs.StringSliceVar(&o.Bundle.PropagatedBundleLabels,
+		"propagate-bundle-labels", nil,
+		"Keys of the labels of Bundles, such as ownership or team labels, which are applied to the target "+
+			"ConfigMaps of every Bundle. Bundles may propagate further labels using their target's metadata.")
+
+	fs.StringVar(&o.naming.TargetNameTemplate,
+		"target-name-template", naming.DefaultTargetNameTemplate,
+		"Go template rendering the name of the target ConfigMaps of a Bundle, with the name of the Bundle "+
+			"available as .Name. The template is validated at startup.")
+
+	fs.StringVar(&o.naming.BundleLabelKey,
+		"bundle-label-key", naming.DefaultBundleLabelKey,
+		"Label which is set to the name of the Bundle on objects created for it, such as ManifestWorks.")
+
+	fs.StringVar(&o.naming.TargetHashAnnotationKey,
+		"target-hash-annotation-key", naming.DefaultTargetHashAnnotationKey,
+		"Annotation written to target ConfigMaps with the hash of their bundle data.")
+
+	fs.IntVar(&o.Bundle.SyncFailureDetailLimit,
+		"metrics-sync-failure-detail-limit", bundle.DefaultSyncFailureDetailLimit,
+		"Maximum number of failing Bundle and namespace pairs exposed by the "+
+			"trust_manager_bundle_sync_failing metric, bounding its cardinality.")
+
+	fs.StringVar(&o.Bundle.DistributionAddress,
+		"distribution-address", "",
+		"Address of the HTTP(S) endpoint serving the data of each Bundle to consumers outside of the cluster, at "+
+			"'"+bundle.DistributionPathPrefix+"<bundle>.pem', '.jks', '.p12' and, as a SPIFFE bundle endpoint, '.spiffe'. "+
+			"Bundles are served from their target in the trust namespace, with an ETag supporting conditional requests. Empty disables the endpoint.")
+
+	fs.StringVar(&o.Bundle.DistributionCertFile,
+		"distribution-tls-cert-file", "",
+		"Path of the TLS certificate of the distribution endpoint. If unset, the endpoint serves plain HTTP.")
+
+	fs.StringVar(&o.Bundle.DistributionKeyFile,
+		"distribution-tls-key-file", "",
+		"Path of the TLS private key of the distribution endpoint.")
+
+	fs.BoolVar(&o.Bundle.FIPS,
+		"fips", false,
+		"Restrict the encodings of Bundle targets to FIPS-approved algorithms. Bundles writing the JKS format, "+
+			"or the PKCS#12 format with a legacy profile, fail to sync.")
 }
 
 func (o *Options) addWebhookFlags(fs *pflag.FlagSet) {
@@ -166,4 +365,15 @@ func (o *Options) addWebhookFlags(fs *pflag.FlagSet) {
 		"Directory where the Webhook certificate and private key are located. "+
 			"Certificate and private key must be named 'tls.crt' and 'tls.key' "+
 			"respectively.")
+	fs.StringToStringVar(&o.Webhook.defaultAdditionalFormats,
+		"default-additional-formats", nil,
+		"Additional formats set on Bundles which don't set them themselves, given as <format>=<key>, for example "+
+			"jks=cacerts. Supported formats are gzip, jks, metadata, pkcs7, pkcs12, provenance, spiffe and sst. A "+
+			"default format isn't set if its key is already used by the Bundle's target. Requires the Bundle "+
+			"defaulting webhook to be registered.")
+	fs.StringVar(&o.Webhook.ClientCABundle,
+		"webhook-client-ca-bundle", "",
+		"Name of a Bundle whose target ConfigMap in the trust namespace contains the CAs used to verify "+
+			"client certificates presented to the webhook. If set, clients such as the Kubernetes API server "+
+			"must present a certificate issued by one of these CAs.")
 }
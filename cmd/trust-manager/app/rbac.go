@@ -0,0 +1,84 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cert-manager/trust-manager/pkg/rbac"
+)
+
+// newRBACCommand returns a command which prints the minimal RBAC manifests
+// required to run trust-manager with the given configuration.
+func newRBACCommand() *cobra.Command {
+	var opts rbac.Options
+
+	cmd := &cobra.Command{
+		Use:   "rbac",
+		Short: "Print the RBAC manifests required to run trust-manager with the given configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := rbac.Encode(rbac.Generate(opts))
+			if err != nil {
+				return fmt.Errorf("failed to generate RBAC manifests: %w", err)
+			}
+
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+
+	setSubcommandUsage(cmd)
+
+	fs := cmd.Flags()
+	fs.StringVar(&opts.Name,
+		"name", "trust-manager",
+		"Name of the trust-manager ServiceAccount, also used for all generated RBAC objects.")
+	fs.StringVar(&opts.Namespace,
+		"namespace", "cert-manager",
+		"Namespace of the trust-manager ServiceAccount.")
+	fs.StringVar(&opts.TrustNamespace,
+		"trust-namespace", "cert-manager",
+		"Namespace to source trust bundles from.")
+	fs.BoolVar(&opts.ClusterPlacement,
+		"enable-cluster-placement", false,
+		"Whether trust-manager distributes Bundles to managed clusters using Open Cluster Management.")
+	fs.BoolVar(&opts.CAInjection,
+		"enable-ca-injection", false,
+		"Whether trust-manager writes the data of Bundles to the caBundle fields of annotated webhook configurations, "+
+			"CustomResourceDefinitions and APIServices.")
+	fs.BoolVar(&opts.ConsumerRestart,
+		"enable-consumer-restart", false,
+		"Whether trust-manager rolls out the pods of annotated Deployments, StatefulSets and DaemonSets "+
+			"whenever the Bundles they consume change.")
+
+	return cmd
+}
+
+// setSubcommandUsage overrides the help and usage funcs inherited from the
+// root command, which print the controller's flags.
+func setSubcommandUsage(cmd *cobra.Command) {
+	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
+		fmt.Fprintf(cmd.OutOrStderr(), "Usage:\n  %s\n\nFlags:\n%s", cmd.UseLine(), cmd.Flags().FlagUsages())
+		return nil
+	})
+	cmd.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\nUsage:\n  %s\n\nFlags:\n%s", cmd.Short, cmd.UseLine(), cmd.Flags().FlagUsages())
+	})
+}
@@ -58,57 +58,1383 @@ type BundleSpec struct {
 
 	// Target is the target location in all namespaces to sync source data to.
 	Target BundleTarget `json:"target"`
+
+	// Filters, if set, excludes certificates from the bundle which match the
+	// filters.
+	// +optional
+	Filters *BundleFilters `json:"filters,omitempty"`
+
+	// MaintenanceWindows, if set, restricts when changes to the content of
+	// the Bundle's targets are applied. Outside of all maintenance windows,
+	// targets continue to be created and repaired using the previously
+	// applied content, and content changes are deferred until the next
+	// maintenance window opens.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// Placement, if set, additionally distributes the Bundle to the managed
+	// clusters selected by an Open Cluster Management Placement on this hub
+	// cluster. For each selected cluster, a ManifestWork containing a copy of
+	// the Bundle, with its sources resolved to a single inLine source, is
+	// created in the cluster's Namespace. The work agent of the managed
+	// cluster applies the copy, which is then synced to targets by
+	// trust-manager running in the managed cluster. Placements are only
+	// honoured if the controller was started with the
+	// "--enable-cluster-placement" flag.
+	// +optional
+	Placement *PlacementReference `json:"placement,omitempty"`
+
+	// TrackAcknowledgments, when true, enables the acknowledgment protocol for
+	// the Bundle's targets. The controller writes the hash of the bundle data
+	// to the "trust.cert-manager.io/hash" annotation of each target, and
+	// consumers, such as agents or sidecars in the target Namespaces, set the
+	// "trust.cert-manager.io/acknowledged-hash" annotation of the target to
+	// that hash once they have loaded the bundle data. The acknowledgments of
+	// all targets are aggregated into the acknowledgments field of the
+	// Bundle's status field.
+	// +optional
+	TrackAcknowledgments bool `json:"trackAcknowledgments,omitempty"`
+
+	// PriorityClass is one of `Critical`, `Normal` or `Low`, and controls the
+	// order in which the Bundle is reconciled relative to other Bundles when
+	// a change affects many Bundles, such as a Namespace change. Critical
+	// Bundles are reconciled first and are exempt from the controller's target
+	// write budget, so that the cluster's primary trust bundle propagates
+	// without delay. Low priority Bundles are reconciled last and may only use
+	// half of the target write budget per reconcile. Defaults to `Normal`.
+	// +kubebuilder:validation:Enum=Critical;Normal;Low
+	// +optional
+	PriorityClass BundlePriorityClass `json:"priorityClass,omitempty"`
+
+	// StripPrivateKeys, when true, removes PEM private key blocks found in the
+	// data of the Bundle's sources, so that the Bundle is synced with the
+	// remaining certificates. By default, a Bundle with a source containing a
+	// private key is not synced, and the PrivateKeyDetected condition is set,
+	// so that key material is never distributed to the Bundle's targets.
+	// +optional
+	StripPrivateKeys bool `json:"stripPrivateKeys,omitempty"`
+
+	// ParsingPolicy is one of `Strict` or `Lenient`, and controls how PEM
+	// blocks in the data of the Bundle's sources which aren't valid
+	// certificates are handled. With `Strict`, any invalid block fails the
+	// source. With `Lenient`, invalid blocks are skipped and counted in the
+	// invalidPEMBlocks field of the Bundle's status, as long as the source
+	// contains at least one valid certificate. Private keys are handled
+	// according to stripPrivateKeys under either policy. Defaults to
+	// `Strict`.
+	// +kubebuilder:validation:Enum=Strict;Lenient
+	// +optional
+	ParsingPolicy PEMParsingPolicy `json:"parsingPolicy,omitempty"`
+}
+
+// PEMParsingPolicy is the policy for handling invalid PEM blocks in the data
+// of a Bundle's sources.
+type PEMParsingPolicy string
+
+const (
+	// PEMParsingPolicyStrict fails a source containing any invalid PEM block.
+	PEMParsingPolicyStrict PEMParsingPolicy = "Strict"
+
+	// PEMParsingPolicyLenient skips invalid PEM blocks in a source.
+	PEMParsingPolicyLenient PEMParsingPolicy = "Lenient"
+)
+
+// BundlePriorityClass is the priority of a Bundle relative to other Bundles.
+type BundlePriorityClass string
+
+const (
+	// BundlePriorityClassCritical Bundles are reconciled before all other
+	// Bundles, and are exempt from the target write budget.
+	BundlePriorityClassCritical BundlePriorityClass = "Critical"
+
+	// BundlePriorityClassNormal is the default priority of Bundles.
+	BundlePriorityClassNormal BundlePriorityClass = "Normal"
+
+	// BundlePriorityClassLow Bundles are reconciled after all other Bundles,
+	// and may only use half of the target write budget.
+	BundlePriorityClassLow BundlePriorityClass = "Low"
+)
+
+// BundleFilters selects certificates to exclude from a bundle.
+type BundleFilters struct {
+	// ExcludeExpired, when true, excludes certificates whose notAfter time has
+	// passed from the bundle. It may be overridden for individual sources.
+	// The number of excluded certificates is stored in the
+	// excludedExpiredCertificates field of the Bundle's status field.
+	// +optional
+	ExcludeExpired bool `json:"excludeExpired,omitempty"`
+
+	// ExcludeExpiringWithin, if set, additionally excludes certificates which
+	// expire within the given duration from the bundle, so that trust anchors
+	// can be removed before their expiry breaks clients. Sources which set
+	// excludeExpired to false are not filtered. The number of certificates
+	// excluded before they expired is stored in the
+	// excludedExpiringCertificates field of the Bundle's status field.
+	// +optional
+	ExcludeExpiringWithin *metav1.Duration `json:"excludeExpiringWithin,omitempty"`
+
+	// Include, if set, restricts the bundle to the certificates matching at
+	// least one of the given rules, for example to select a single root from
+	// the default CAs. The number of certificates excluded by the include,
+	// exclude and fingerprint filters is stored in the
+	// excludedMatchedCertificates field of the Bundle's status field.
+	// +optional
+	Include []CertificateMatch `json:"include,omitempty"`
+
+	// Exclude excludes the certificates matching any of the given rules from
+	// the bundle. Exclude rules take precedence over include rules.
+	// +optional
+	Exclude []CertificateMatch `json:"exclude,omitempty"`
+
+	// AllowFingerprints, if set, restricts the bundle to the certificates with
+	// the given hex encoded SHA-256 fingerprints, regardless of which source
+	// they came from. Fingerprints may be given in upper or lower case, with
+	// or without colon separators.
+	// +optional
+	AllowFingerprints []string `json:"allowFingerprints,omitempty"`
+
+	// DenyFingerprints excludes the certificates with the given hex encoded
+	// SHA-256 fingerprints from the bundle, regardless of which source they
+	// came from, for example when a CA is distrusted. DenyFingerprints takes
+	// precedence over all other filters.
+	// +optional
+	DenyFingerprints []string `json:"denyFingerprints,omitempty"`
+
+	// KeyUsages, if set, restricts the bundle to the certificates which are
+	// valid for all of the given key usages. Certificates without the key
+	// usage extension are valid for any key usage.
+	// +optional
+	KeyUsages []KeyUsage `json:"keyUsages,omitempty"`
+
+	// ExtendedKeyUsages, if set, restricts the bundle to the certificates
+	// which are valid for all of the given extended key usages, for example so
+	// that trust distributed to TLS clients only includes anchors valid for
+	// ServerAuth. Certificates without the extended key usage extension, or
+	// with the any extended key usage, are valid for any extended key usage.
+	// The number of certificates excluded by the key usage filters is
+	// included in the excludedMatchedCertificates field of the Bundle's status
+	// field.
+	// +optional
+	ExtendedKeyUsages []ExtendedKeyUsage `json:"extendedKeyUsages,omitempty"`
+
+	// NonCACertificates is one of `Warn` or `Enforce`, and controls how
+	// certificates without the `CA:true` basic constraint, such as leaf
+	// certificates, are handled. In `Warn` mode, which is the default, they
+	// are included in the bundle and a warning event is emitted. In `Enforce`
+	// mode, they are excluded from the bundle. The number of such
+	// certificates is stored in the nonCACertificates field of the Bundle's
+	// status field.
+	// +kubebuilder:validation:Enum=Warn;Enforce
+	// +optional
+	NonCACertificates NonCACertificatePolicy `json:"nonCACertificates,omitempty"`
+
+	// DeduplicateByPublicKey, when true, additionally treats certificates with
+	// the same subject public key info as duplicates, such as a CA which was
+	// re-issued with a new validity period. Byte-identical certificates are
+	// always deduplicated across sources, keeping the first occurrence in the
+	// bundle. The number of omitted duplicates is stored in the
+	// duplicateCertificates field of the Bundle's status field.
+	// +optional
+	DeduplicateByPublicKey bool `json:"deduplicateByPublicKey,omitempty"`
+
+	// CrossSigned controls which of multiple certificates with the same
+	// subject and subject key identifier, such as cross-signed or re-issued
+	// CAs, are included in the bundle. One of `KeepAll`, `KeepNewest` or
+	// `KeepLongestValidity`. `KeepNewest` keeps the certificate issued most
+	// recently, and `KeepLongestValidity` keeps the certificate which expires
+	// last. Defaults to `KeepAll`. The number of such certificates besides the
+	// kept one is stored in the crossSignedCertificates field of the Bundle's
+	// status field.
+	// +kubebuilder:validation:Enum=KeepAll;KeepNewest;KeepLongestValidity
+	// +optional
+	CrossSigned CrossSignedPolicy `json:"crossSigned,omitempty"`
+
+	// WeakCrypto controls how certificates with weak keys or signatures, such
+	// as RSA keys smaller than 2048 bits or SHA-1 signatures, are handled,
+	// overriding the default policy of the trust-manager controller. The
+	// number of such certificates is stored in the weakCryptoCertificates
+	// field of the Bundle's status field.
+	// +optional
+	WeakCrypto *WeakCryptoFilter `json:"weakCrypto,omitempty"`
+}
+
+// WeakCryptoFilter is a policy for certificates with weak keys or signatures.
+type WeakCryptoFilter struct {
+	// Action is one of `Ignore`, `Warn` or `Enforce`. In `Warn` mode, weak
+	// certificates are included in the bundle and a warning event is emitted.
+	// In `Enforce` mode, they are excluded from the bundle. If unset, the
+	// default action of the trust-manager controller is used, which is set
+	// using the "--default-weak-crypto-action" flag.
+	// +kubebuilder:validation:Enum=Ignore;Warn;Enforce
+	// +optional
+	Action WeakCryptoAction `json:"action,omitempty"`
+
+	// MinRSAKeySize is the minimum size in bits of the RSA keys of
+	// certificates which are not weak. Defaults to 2048.
+	// +optional
+	MinRSAKeySize int32 `json:"minRSAKeySize,omitempty"`
+
+	// WeakSignatureAlgorithms are the signature algorithms of certificates
+	// which are weak, using the names of Go's crypto/x509 package, such as
+	// "SHA1-RSA" or "ECDSA-SHA1". Defaults to the algorithms using MD5 or
+	// SHA-1.
+	// +optional
+	WeakSignatureAlgorithms []string `json:"weakSignatureAlgorithms,omitempty"`
+}
+
+// WeakCryptoAction controls how certificates with weak keys or signatures are
+// handled.
+type WeakCryptoAction string
+
+const (
+	// WeakCryptoActionIgnore includes certificates with weak keys or
+	// signatures in the bundle without a warning.
+	WeakCryptoActionIgnore WeakCryptoAction = "Ignore"
+
+	// WeakCryptoActionWarn includes certificates with weak keys or signatures
+	// in the bundle, and emits a warning event.
+	WeakCryptoActionWarn WeakCryptoAction = "Warn"
+
+	// WeakCryptoActionEnforce excludes certificates with weak keys or
+	// signatures from the bundle.
+	WeakCryptoActionEnforce WeakCryptoAction = "Enforce"
+)
+
+// NonCACertificatePolicy controls how certificates which are not CAs are
+// handled.
+type NonCACertificatePolicy string
+
+const (
+	// NonCACertificatePolicyWarn includes certificates which are not CAs in
+	// the bundle, and emits a warning event.
+	NonCACertificatePolicyWarn NonCACertificatePolicy = "Warn"
+
+	// NonCACertificatePolicyEnforce excludes certificates which are not CAs
+	// from the bundle.
+	NonCACertificatePolicyEnforce NonCACertificatePolicy = "Enforce"
+)
+
+// CrossSignedPolicy controls which of multiple certificates with the same
+// subject and subject key identifier are included in the bundle.
+type CrossSignedPolicy string
+
+const (
+	// CrossSignedPolicyKeepAll includes all certificates with the same subject
+	// and subject key identifier in the bundle.
+	CrossSignedPolicyKeepAll CrossSignedPolicy = "KeepAll"
+
+	// CrossSignedPolicyKeepNewest includes only the certificate with the
+	// latest notBefore time.
+	CrossSignedPolicyKeepNewest CrossSignedPolicy = "KeepNewest"
+
+	// CrossSignedPolicyKeepLongestValidity includes only the certificate with
+	// the latest notAfter time.
+	CrossSignedPolicyKeepLongestValidity CrossSignedPolicy = "KeepLongestValidity"
+)
+
+// KeyUsage is a key usage of a certificate, as defined in RFC 5280 section
+// 4.2.1.3.
+// +kubebuilder:validation:Enum=DigitalSignature;ContentCommitment;KeyEncipherment;DataEncipherment;KeyAgreement;CertSign;CRLSign;EncipherOnly;DecipherOnly
+type KeyUsage string
+
+const (
+	// KeyUsageDigitalSignature is the digitalSignature key usage.
+	KeyUsageDigitalSignature KeyUsage = "DigitalSignature"
+
+	// KeyUsageContentCommitment is the contentCommitment key usage, formerly
+	// known as nonRepudiation.
+	KeyUsageContentCommitment KeyUsage = "ContentCommitment"
+
+	// KeyUsageKeyEncipherment is the keyEncipherment key usage.
+	KeyUsageKeyEncipherment KeyUsage = "KeyEncipherment"
+
+	// KeyUsageDataEncipherment is the dataEncipherment key usage.
+	KeyUsageDataEncipherment KeyUsage = "DataEncipherment"
+
+	// KeyUsageKeyAgreement is the keyAgreement key usage.
+	KeyUsageKeyAgreement KeyUsage = "KeyAgreement"
+
+	// KeyUsageCertSign is the keyCertSign key usage, required to sign
+	// certificates.
+	KeyUsageCertSign KeyUsage = "CertSign"
+
+	// KeyUsageCRLSign is the cRLSign key usage, required to sign certificate
+	// revocation lists.
+	KeyUsageCRLSign KeyUsage = "CRLSign"
+
+	// KeyUsageEncipherOnly is the encipherOnly key usage.
+	KeyUsageEncipherOnly KeyUsage = "EncipherOnly"
+
+	// KeyUsageDecipherOnly is the decipherOnly key usage.
+	KeyUsageDecipherOnly KeyUsage = "DecipherOnly"
+)
+
+// ExtendedKeyUsage is an extended key usage of a certificate, as defined in
+// RFC 5280 section 4.2.1.12.
+// +kubebuilder:validation:Enum=ServerAuth;ClientAuth;CodeSigning;EmailProtection;TimeStamping;OCSPSigning
+type ExtendedKeyUsage string
+
+const (
+	// ExtendedKeyUsageServerAuth is used for TLS server authentication.
+	ExtendedKeyUsageServerAuth ExtendedKeyUsage = "ServerAuth"
+
+	// ExtendedKeyUsageClientAuth is used for TLS client authentication.
+	ExtendedKeyUsageClientAuth ExtendedKeyUsage = "ClientAuth"
+
+	// ExtendedKeyUsageCodeSigning is used for signing executable code.
+	ExtendedKeyUsageCodeSigning ExtendedKeyUsage = "CodeSigning"
+
+	// ExtendedKeyUsageEmailProtection is used for S/MIME email protection.
+	ExtendedKeyUsageEmailProtection ExtendedKeyUsage = "EmailProtection"
+
+	// ExtendedKeyUsageTimeStamping is used for trusted timestamping.
+	ExtendedKeyUsageTimeStamping ExtendedKeyUsage = "TimeStamping"
+
+	// ExtendedKeyUsageOCSPSigning is used for signing OCSP responses.
+	ExtendedKeyUsageOCSPSigning ExtendedKeyUsage = "OCSPSigning"
+)
+
+// CertificateMatch is a rule matching certificates by their subject or issuer
+// distinguished name. At least one of Subject or Issuer must be set, and a
+// certificate matches the rule if it matches all of those which are set.
+type CertificateMatch struct {
+	// Subject matches the subject distinguished name of the certificate.
+	// +optional
+	Subject *DistinguishedNameMatch `json:"subject,omitempty"`
+
+	// Issuer matches the issuer distinguished name of the certificate.
+	// +optional
+	Issuer *DistinguishedNameMatch `json:"issuer,omitempty"`
+}
+
+// DistinguishedNameMatch matches a distinguished name in its RFC 2253 string
+// form, for example "CN=ISRG Root X1,O=Internet Security Research Group,C=US".
+// Exactly one of Exact or Regex must be set.
+type DistinguishedNameMatch struct {
+	// Exact matches a distinguished name equal to the given value.
+	// +optional
+	Exact string `json:"exact,omitempty"`
+
+	// Regex matches a distinguished name containing a match of the given
+	// regular expression, in RE2 syntax. Use ^ and $ to match the whole name.
+	// +optional
+	Regex string `json:"regex,omitempty"`
+}
+
+// PlacementReference is a reference to an Open Cluster Management Placement.
+type PlacementReference struct {
+	// Name is the name of the Placement.
+	Name string `json:"name"`
+
+	// Namespace is the Namespace of the Placement on the hub cluster.
+	Namespace string `json:"namespace"`
+}
+
+// MaintenanceWindow is a recurring period of time during which changes to the
+// content of a Bundle's targets may be applied.
+type MaintenanceWindow struct {
+	// Schedule is a cron expression in the standard 5-field format, at which
+	// the maintenance window opens. Times are in UTC, unless the expression is
+	// prefixed with a time zone such as "CRON_TZ=Europe/Oslo".
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the maintenance window remains open for.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// BundleSource is the set of sources whose data will be appended and synced to
+// the BundleTarget in all Namespaces.
+type BundleSource struct {
+	// ConfigMap is a reference to a ConfigMap's `data` or `binaryData` key, in
+	// the trust Namespace. The data may be PEM or DER-encoded certificates,
+	// or a PKCS#7 certificate bundle.
+	// +optional
+	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
+
+	// Secret is a reference to a Secrets's `data` key, in the trust
+	// Namespace. The data may be PEM or DER-encoded certificates, or a PKCS#7
+	// certificate bundle.
+	// +optional
+	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+
+	// TLSSecret is a reference to a `kubernetes.io/tls` Secret in the trust
+	// Namespace. Only the CA certificates found in the Secret's `tls.crt` and
+	// `ca.crt` keys are appended to the bundle; leaf certificates are skipped.
+	// +optional
+	TLSSecret *SourceObjectSelector `json:"tlsSecret,omitempty"`
+
+	// IstioCACertsSecret is a reference to a Secret in the trust Namespace
+	// using the layout of the Istio `cacerts` Secret. Only the root
+	// certificates are appended to the bundle: those in the Secret's
+	// `root-cert.pem` key, and any self-signed certificates in its
+	// `cert-chain.pem` key. Intermediate CAs and keys are skipped.
+	// +optional
+	IstioCACertsSecret *SourceObjectSelector `json:"istioCACertsSecret,omitempty"`
+
+	// TruststoreSecret is a reference to a binary JKS or PKCS#12 truststore
+	// stored at a key of a Secret in the trust Namespace. All trusted
+	// certificates contained in the truststore are converted to PEM and
+	// appended to the bundle.
+	// +optional
+	TruststoreSecret *SourceTruststoreSelector `json:"truststoreSecret,omitempty"`
+
+	// ObjectStorage is a reference to an object in a blob store, such as S3,
+	// GCS or Azure Blob Storage, containing PEM data. The object is polled
+	// periodically, using its ETag to detect changes.
+	// +optional
+	ObjectStorage *SourceObjectStorage `json:"objectStorage,omitempty"`
+
+	// RemoteCluster is a reference to a ConfigMap or Secret in another
+	// cluster, read using a kubeconfig stored in a Secret in the trust
+	// Namespace. The object is polled periodically for changes.
+	// +optional
+	RemoteCluster *SourceRemoteCluster `json:"remoteCluster,omitempty"`
+
+	// InLine is a simple string to append as the source data.
+	// +optional
+	InLine *string `json:"inLine,omitempty"`
+
+	// InLineDER is a list of base64-encoded DER certificates to append as the
+	// source data, for use where certificates are produced in DER form and
+	// converting them to PEM beforehand is inconvenient.
+	// +optional
+	InLineDER [][]byte `json:"inLineDER,omitempty"`
+
+	// UseDefaultCAs, when true, requests the default CA bundle to be used as a source.
+	// Default CAs are available if trust-manager was installed via Helm
+	// or was otherwise set up to include a package-injecting init container by using the
+	// "--default-package-location" flag when starting the trust-manager controller.
+	// If default CAs were not configured at start-up, any request to use the default
+	// CAs will fail.
+	// The version of the default CA package which is used for a Bundle is stored in the
+	// defaultCAPackageVersion field of the Bundle's status field.
+	// To exclude particular CAs of the default CA package, use defaultCAs instead.
+	// +optional
+	UseDefaultCAs *bool `json:"useDefaultCAs,omitempty"`
+
+	// UseClusterAPIServerCA, when true, requests the CA of the cluster's own
+	// Kubernetes API server to be used as a source. The CA is read from the
+	// "ca.crt" key of the "kube-root-ca.crt" ConfigMap which Kubernetes
+	// publishes in every Namespace, including the trust Namespace.
+	// +optional
+	UseClusterAPIServerCA *bool `json:"useClusterAPIServerCA,omitempty"`
+
+	// UseNodeOSCAs, when true, requests the system CA bundle of a designated
+	// node's operating system to be used as a source, for clusters which must
+	// mirror the host OS trust exactly. The bundle is read from the
+	// "ca-certificates.crt" key of the "trust-manager-node-os-cas" ConfigMap in
+	// the trust Namespace, which is published by the node agent run using the
+	// "trust-manager publish-node-cas" command. Any request to use the node OS
+	// CAs will fail until the bundle has been published.
+	// +optional
+	UseNodeOSCAs *bool `json:"useNodeOSCAs,omitempty"`
+
+	// UseOpenShiftTrustedCA, when true, requests the cluster-wide trusted CA
+	// bundle of an OpenShift cluster to be used as a source. This bundle
+	// contains the CAs of the node operating system and of the ConfigMap
+	// referenced by the trustedCA field of the cluster Proxy. The bundle is
+	// read from the "ca-bundle.crt" key of the
+	// "trust-manager-openshift-trusted-ca" ConfigMap in the trust Namespace,
+	// which is labelled "config.openshift.io/inject-trusted-cabundle: true" so
+	// that OpenShift's Cluster Network Operator injects the bundle into it.
+	// Any request to use the OpenShift trusted CAs will fail until the bundle
+	// has been injected.
+	// +optional
+	UseOpenShiftTrustedCA *bool `json:"useOpenShiftTrustedCA,omitempty"`
+
+	// DefaultCAs requests a default CA package loaded when trust-manager was
+	// started to be used as a source. Named packages are available if they
+	// were loaded using the "--named-default-package-location" flag when
+	// starting the trust-manager controller.
+	// The version of each named default CA package which is used for a Bundle
+	// is stored in the defaultCAPackages field of the Bundle's status field.
+	// +optional
+	DefaultCAs *DefaultCAsSource `json:"defaultCAs,omitempty"`
+
+	// Labels are logical labels, such as `purpose: mtls-internal`, attached to
+	// each certificate from this source. Labels are not written to the PEM
+	// bundle, but are carried into the metadata target format, so that
+	// consumers which support selective trust can subset the bundle.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Weight orders the certificates of this source relative to those of the
+	// other sources, for consumers which are sensitive to the order of trust
+	// anchors. Certificates of sources with a higher weight appear first in
+	// the bundle, and sources of equal weight appear in the order they are
+	// listed. Within a source, certificates are sorted by the SHA-256 digest of
+	// their DER encoding. Defaults to 0.
+	// +optional
+	Weight int32 `json:"weight,omitempty"`
+
+	// ExcludeExpired, if set, overrides the excludeExpired filter of the
+	// Bundle for the certificates of this source. If false, the certificates
+	// of this source are also not subject to the excludeExpiringWithin filter.
+	// +optional
+	ExcludeExpired *bool `json:"excludeExpired,omitempty"`
+
+	// Filters, if set, are applied to the certificates of this source instead
+	// of the filters of the Bundle, such as to pass an internal source
+	// through untouched while filtering the default CAs. The
+	// deduplicateByPublicKey and crossSigned filters apply across sources, and
+	// may only be set on the Bundle.
+	// +optional
+	Filters *BundleFilters `json:"filters,omitempty"`
+
+	// Purposes, if set, are the purposes the certificates of this source are
+	// trusted for, which select the certificates written to the profiles of
+	// the Bundle's target. If unset, the certificates of default CA packages
+	// are trusted for the purposes published in the package, and all other
+	// certificates for the purposes allowed by their extended key usage
+	// extension. Certificates without the extension are trusted for any
+	// purpose.
+	// +optional
+	Purposes []TrustPurpose `json:"purposes,omitempty"`
+}
+
+// TrustPurpose is a purpose for which a certificate is trusted.
+// +kubebuilder:validation:Enum=ServerAuth;ClientAuth;Any
+type TrustPurpose string
+
+const (
+	// TrustPurposeServerAuth is trust in certificates for verifying TLS
+	// servers.
+	TrustPurposeServerAuth TrustPurpose = "ServerAuth"
+
+	// TrustPurposeClientAuth is trust in certificates for validating TLS
+	// client certificates.
+	TrustPurposeClientAuth TrustPurpose = "ClientAuth"
+
+	// TrustPurposeAny is trust in certificates for any purpose.
+	TrustPurposeAny TrustPurpose = "Any"
+)
+
+// DefaultCAsSource selects a default CA package loaded when trust-manager was
+// started.
+type DefaultCAsSource struct {
+	// Package is the name of the default CA package to use, as given in the
+	// package's "name" field. If unset, the default CA package loaded using the
+	// "--default-package-location" flag is used, equivalent to useDefaultCAs.
+	// +optional
+	Package string `json:"package,omitempty"`
+
+	// Fallback is an ordered list of names of default CA packages to use if
+	// the requested package was not loaded when trust-manager was started.
+	// The first loaded package in the list is used. The selected package and
+	// its version are reflected in the Bundle's status.
+	// +optional
+	Fallback []string `json:"fallback,omitempty"`
+
+	// Exclude lists CAs of the selected package which are not used by this
+	// source, such as public roots distrusted by corporate policy, without
+	// maintaining a forked package.
+	// +optional
+	Exclude *DefaultCAsExclusions `json:"exclude,omitempty"`
+}
+
+// DefaultCAsExclusions identifies CAs of a default CA package which are
+// excluded from a source. A CA is excluded if it matches any of the given
+// common names or fingerprints.
+type DefaultCAsExclusions struct {
+	// CommonNames are the subject common names of the CAs to exclude, such as
+	// "DST Root CA X3". Common names are compared exactly.
+	// +optional
+	CommonNames []string `json:"commonNames,omitempty"`
+
+	// Fingerprints are the SHA-256 fingerprints of the DER encoding of the CAs
+	// to exclude, given as hex, optionally separated by colons.
+	// +optional
+	Fingerprints []string `json:"fingerprints,omitempty"`
+}
+
+// BundleTarget is the target resource that the Bundle will sync all source
+// data to.
+type BundleTarget struct {
+	// ConfigMap is the target ConfigMap in Namespaces that all Bundle source
+	// data will be synced to.
+	ConfigMap *TargetKeySelector `json:"configMap,omitempty"`
+
+	// AdditionalFormats specifies any additional formats to write to the target
+	// +optional
+	AdditionalFormats *AdditionalFormats `json:"additionalFormats,omitempty"`
+
+	// Mode is one of `Namespaces` or `Local`. In `Namespaces` mode, the
+	// target is synced to all Namespaces selected by NamespaceSelector,
+	// Namespaces and NamespaceExcludeSelector. In `Local` mode, the target is
+	// only synced to the trust Namespace, so that a Bundle can be composed
+	// for other Bundles or for the distribution endpoint without creating a
+	// ConfigMap in every Namespace. Namespace selection is not allowed in
+	// `Local` mode. Defaults to `Namespaces`.
+	// +kubebuilder:validation:Enum=Namespaces;Local
+	// +optional
+	Mode TargetMode `json:"mode,omitempty"`
+
+	// NamespaceSelector will, if set, only sync the target resource in
+	// Namespaces which match the selector.
+	// +optional
+	NamespaceSelector *NamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// Namespaces will, if set, only sync the target resource in the
+	// Namespaces with the given names, so that a fixed set of Namespaces can
+	// be targeted without labelling them. Namespaces are matched by the
+	// "kubernetes.io/metadata.name" label which Kubernetes sets on every
+	// Namespace. Mutually exclusive with NamespaceSelector.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceExcludeSelector will, if set, not sync the target resource in
+	// Namespaces which match the selector, even if they are selected by
+	// NamespaceSelector or Namespaces, so that a few Namespaces can be
+	// excluded without labelling all other Namespaces. Targets which already
+	// exist in excluded Namespaces are deleted.
+	// +optional
+	NamespaceExcludeSelector *NamespaceSelector `json:"namespaceExcludeSelector,omitempty"`
+
+	// BuildInfo controls whether informative build metadata is embedded in
+	// the target. If unset, no build metadata is embedded.
+	// +optional
+	BuildInfo *BuildInfo `json:"buildInfo,omitempty"`
+
+	// SizeLimit limits the size of the bundle data written to the target,
+	// since a ConfigMap can't be larger than 1MiB. If unset, bundle data
+	// larger than 1MiB fails to sync.
+	// +optional
+	SizeLimit *TargetSizeLimit `json:"sizeLimit,omitempty"`
+
+	// OCI, if set, pushes the bundle data as an OCI artifact to a container
+	// registry whenever it changes, so that it can be distributed, mirrored
+	// and signed using registry tooling. The tag and digest of the last
+	// pushed artifact are recorded in the Bundle's status.
+	// +optional
+	OCI *TargetOCI `json:"oci,omitempty"`
+
+	// ObjectStorage, if set, publishes the bundle data to an object in a blob
+	// store, such as S3, Google Cloud Storage or Azure Blob Storage, for
+	// systems outside of Kubernetes which consume the same trust. The object
+	// is only written when its content differs from the bundle data.
+	// +optional
+	ObjectStorage *TargetObjectStorage `json:"objectStorage,omitempty"`
+
+	// Istio, if set, additionally writes the bundle data to the ConfigMap
+	// which Istio's proxies read the mesh trust anchors from in each target
+	// Namespace, so that trust-manager owns the distribution of the mesh roots
+	// during root rotation. Existing ConfigMaps written by istiod are adopted.
+	// istiod must be configured not to write these ConfigMaps itself,
+	// otherwise both controllers overwrite each other's data.
+	// +optional
+	Istio *TargetIstio `json:"istio,omitempty"`
+
+	// OpenShiftTrustedCA, if set, additionally writes the bundle data to a
+	// ConfigMap in the "openshift-config" Namespace which can be referenced
+	// by the trustedCA field of an OpenShift cluster's Proxy. OpenShift then
+	// merges the bundle into the cluster-wide trusted CA bundle, which it
+	// injects into nodes and into every ConfigMap labelled
+	// "config.openshift.io/inject-trusted-cabundle: true".
+	// +optional
+	OpenShiftTrustedCA *TargetOpenShiftTrustedCA `json:"openShiftTrustedCA,omitempty"`
+
+	// Immutable, if set, additionally writes a copy of the target ConfigMap
+	// to an immutable ConfigMap named after the hash of its content whenever
+	// the content changes, so that consumers can mount a version of the
+	// bundle which never changes underneath them, and roll back to a
+	// previous version. The name of the current version is written to the
+	// "trust.cert-manager.io/current-version" annotation of the target
+	// ConfigMap, which acts as a stable pointer to it.
+	// +optional
+	Immutable *TargetImmutable `json:"immutable,omitempty"`
+
+	// Metadata, if set, are labels and annotations which are applied to the
+	// target ConfigMap in every Namespace, so that other tooling, such as
+	// backup selectors, policy exceptions or reloaders, can select targets.
+	// Labels and annotations removed from Metadata are removed from the
+	// targets.
+	// +optional
+	Metadata *TargetMetadata `json:"metadata,omitempty"`
+
+	// DeletionPolicy is one of `Delete` or `Orphan`, and controls what
+	// happens to the target ConfigMaps when the Bundle is deleted. If set,
+	// the Bundle is given the "trust.cert-manager.io/target-cleanup"
+	// finalizer, and is only removed once its targets have been cleaned up.
+	// In `Delete` mode, the target ConfigMaps are deleted, within the target
+	// write budget of the controller and with the trust Namespace last. In
+	// `Orphan` mode, the Bundle's ownership of the target ConfigMaps is
+	// removed, so that they are left in place with the last synced data. If
+	// unset, the targets are deleted by the Kubernetes garbage collector.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy TargetDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// ConflictPolicy is one of `Fail`, `Adopt` or `Overwrite`, and controls
+	// what happens when a target ConfigMap already exists in a Namespace
+	// without being owned by the Bundle. In `Fail` mode, the Bundle isn't
+	// synced while any target isn't owned by it, and the conflicting
+	// Namespaces are named by its `CollisionDetected` condition. In `Adopt`
+	// mode, the Bundle takes ownership of the target, keeping the entries
+	// written by others. In `Overwrite` mode, the Bundle takes ownership of
+	// the target and replaces all of its entries. Defaults to `Fail`.
+	// +kubebuilder:validation:Enum=Fail;Adopt;Overwrite
+	// +optional
+	ConflictPolicy TargetConflictPolicy `json:"conflictPolicy,omitempty"`
+}
+
+// TargetConflictPolicy controls what happens when a target of a Bundle
+// already exists without being owned by it.
+type TargetConflictPolicy string
+
+const (
+	// TargetConflictPolicyFail fails the sync of a Bundle while any of its
+	// targets isn't owned by it.
+	TargetConflictPolicyFail TargetConflictPolicy = "Fail"
+
+	// TargetConflictPolicyAdopt takes ownership of existing targets, keeping
+	// the entries written by others.
+	TargetConflictPolicyAdopt TargetConflictPolicy = "Adopt"
+
+	// TargetConflictPolicyOverwrite takes ownership of existing targets and
+	// replaces all of their entries.
+	TargetConflictPolicyOverwrite TargetConflictPolicy = "Overwrite"
+)
+
+// TargetDeletionPolicy controls what happens to the targets of a Bundle when
+// it is deleted.
+type TargetDeletionPolicy string
+
+const (
+	// TargetDeletionPolicyDelete deletes the targets of a deleted Bundle.
+	TargetDeletionPolicyDelete TargetDeletionPolicy = "Delete"
+
+	// TargetDeletionPolicyOrphan leaves the targets of a deleted Bundle in
+	// place.
+	TargetDeletionPolicyOrphan TargetDeletionPolicy = "Orphan"
+
+	// TargetCleanupFinalizer is the finalizer of Bundles with a target
+	// deletion policy, which is removed once their targets have been cleaned
+	// up.
+	TargetCleanupFinalizer = "trust.cert-manager.io/target-cleanup"
+)
+
+// TargetMetadata are labels and annotations applied to target ConfigMaps.
+type TargetMetadata struct {
+	// Labels are the labels applied to the target ConfigMaps. Labels in the
+	// trust.cert-manager.io domain are reserved.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are the annotations applied to the target ConfigMaps.
+	// Annotations in the trust.cert-manager.io domain are reserved.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PropagateLabels are the keys of the labels of the Bundle which are
+	// applied to the target ConfigMaps, such as ownership or team labels, in
+	// addition to those propagated by the trust-manager controller, which are
+	// set using the "--propagate-bundle-labels" flag. Labels set in Labels
+	// take precedence over propagated labels.
+	// +optional
+	PropagateLabels []string `json:"propagateLabels,omitempty"`
+}
+
+// TargetImmutable configures the immutable versions of a target ConfigMap.
+// Versions are named "<target>-<hash>", where hash is the first ten hex
+// characters of the SHA-256 digest of the target's content.
+type TargetImmutable struct {
+	// HistoryLimit is the number of versions which are kept in each
+	// Namespace, including the current version. The oldest versions are
+	// deleted first. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HistoryLimit int32 `json:"historyLimit,omitempty"`
+}
+
+const (
+	// DefaultImmutableHistoryLimit is the default number of immutable
+	// versions of a target kept in each Namespace.
+	DefaultImmutableHistoryLimit = 3
+
+	// TargetCurrentVersionAnnotationKey is the annotation of a target
+	// ConfigMap whose value is the name of the immutable ConfigMap holding
+	// the current version of the target's content.
+	TargetCurrentVersionAnnotationKey = "trust.cert-manager.io/current-version"
+
+	// TargetVersionOfLabelKey is the label of an immutable version of a
+	// target, whose value is the name of the target ConfigMap.
+	TargetVersionOfLabelKey = "trust.cert-manager.io/version-of"
+)
+
+// TargetObjectStorage is an object in a blob store which bundle data is
+// published to.
+type TargetObjectStorage struct {
+	// Provider is the blob store provider, one of `S3`, `GCS` or `AzureBlob`.
+	// +kubebuilder:validation:Enum=S3;GCS;AzureBlob
+	Provider ObjectStorageProvider `json:"provider"`
+
+	// Bucket is the name of the bucket which the object is written to, or the
+	// name of the container for AzureBlob.
+	Bucket string `json:"bucket"`
+
+	// Key is the key of the object, or the name of the blob for AzureBlob.
+	Key string `json:"key"`
+
+	// Region is the region of the S3 bucket. Defaults to "us-east-1".
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Account is the name of the storage account. Required for AzureBlob.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// Endpoint, if set, overrides the URL of the provider's API, for example
+	// to use an S3-compatible blob store. Objects are addressed using
+	// path-style URLs below the endpoint.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CredentialsSecret is the name of a Secret in the trust Namespace
+	// containing credentials for the blob store, with the same keys as the
+	// credentials of an object storage source. If unset, the object is
+	// written anonymously.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// TargetMode controls which Namespaces a target is synced to.
+type TargetMode string
+
+const (
+	// TargetModeNamespaces syncs the target to all selected Namespaces.
+	TargetModeNamespaces TargetMode = "Namespaces"
+
+	// TargetModeLocal syncs the target only to the trust Namespace.
+	TargetModeLocal TargetMode = "Local"
+)
+
+// TargetOpenShiftTrustedCA is the ConfigMap in the "openshift-config"
+// Namespace which the bundle data is written to, at the "ca-bundle.crt" key
+// which OpenShift reads the trusted CAs of the cluster Proxy from.
+type TargetOpenShiftTrustedCA struct {
+	// Name is the name of the ConfigMap. An existing ConfigMap which isn't
+	// controlled by the Bundle, such as the "user-ca-bundle" ConfigMap written
+	// by the OpenShift installer, is never overwritten.
+	// Defaults to "trust-manager-trusted-ca".
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+const (
+	// OpenShiftConfigNamespace is the Namespace which OpenShift reads the
+	// ConfigMap referenced by the trustedCA field of the cluster Proxy from.
+	OpenShiftConfigNamespace = "openshift-config"
+
+	// OpenShiftTrustedCAConfigMapKey is the key of the trusted CAs in the
+	// ConfigMaps read and written by OpenShift.
+	OpenShiftTrustedCAConfigMapKey = "ca-bundle.crt"
+
+	// OpenShiftInjectTrustedCABundleLabelKey is the label which requests
+	// OpenShift to inject the cluster-wide trusted CA bundle into a ConfigMap.
+	OpenShiftInjectTrustedCABundleLabelKey = "config.openshift.io/inject-trusted-cabundle"
+
+	// DefaultOpenShiftTrustedCAConfigMapName is the default name of the
+	// ConfigMap written by the OpenShiftTrustedCA target.
+	DefaultOpenShiftTrustedCAConfigMapName = "trust-manager-trusted-ca"
+)
+
+// TargetIstio is the layout of the ConfigMaps which Istio's proxies read the
+// mesh trust anchors from. The bundle data is written to the
+// "root-cert.pem" key of the "istio-ca-root-cert" ConfigMap in each target
+// Namespace, labelled "istio.io/config: true" as istiod does.
+type TargetIstio struct{}
+
+const (
+	// IstioRootCertConfigMapName is the name of the ConfigMap which Istio's
+	// proxies read the mesh trust anchors from.
+	IstioRootCertConfigMapName = "istio-ca-root-cert"
+
+	// IstioRootCertConfigMapKey is the key of the mesh trust anchors in the
+	// Istio root certificate ConfigMap.
+	IstioRootCertConfigMapKey = "root-cert.pem"
+)
+
+// TargetOCI is an OCI artifact which bundle data is pushed to.
+type TargetOCI struct {
+	// Repository is the repository which the artifact is pushed to, including
+	// the registry host, for example "registry.example.com/trust/bundle".
+	Repository string `json:"repository"`
+
+	// Tag is the tag which the artifact is pushed to. Defaults to "latest".
+	// +optional
+	Tag string `json:"tag,omitempty"`
+
+	// CredentialsSecret is the name of a Secret in the trust Namespace
+	// containing the `username` and `password` used to authenticate with the
+	// registry. If unset, the artifact is pushed anonymously.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// PlainHTTP, when true, pushes to the registry using plain HTTP rather
+	// than HTTPS. Only intended for registries inside of the cluster.
+	// +optional
+	PlainHTTP bool `json:"plainHTTP,omitempty"`
+}
+
+// TargetSizeLimit limits the size of the bundle data written to a target.
+type TargetSizeLimit struct {
+	// MaxBytes is the maximum size in bytes of the PEM-encoded bundle data
+	// written to the target. Defaults to 1048576, the maximum size of a
+	// ConfigMap.
+	// +optional
+	MaxBytes int32 `json:"maxBytes,omitempty"`
+
+	// MaxCertificates is the maximum number of certificates in the bundle data
+	// written to the target. If unset, the number of certificates isn't
+	// limited.
+	// +optional
+	MaxCertificates int32 `json:"maxCertificates,omitempty"`
+
+	// Policy is one of `Fail`, `Warn`, `Truncate` or `Partition`, and controls what happens
+	// when the bundle data exceeds the limits. Defaults to `Fail`.
+	// +kubebuilder:validation:Enum=Fail;Warn;Truncate;Partition
+	// +optional
+	Policy TargetSizeLimitPolicy `json:"policy,omitempty"`
+
+	// PartitionIndexKey is the key of the target which lists the partitions
+	// of the bundle data when it is partitioned by the `Partition` policy.
+	// Defaults to "partitions.txt".
+	// +optional
+	PartitionIndexKey string `json:"partitionIndexKey,omitempty"`
+}
+
+// TargetSizeLimitPolicy is the action taken when the bundle data exceeds the
+// size limit of a target.
+type TargetSizeLimitPolicy string
+
+const (
+	// TargetSizeLimitPolicyFail doesn't sync the Bundle's targets, and sets the
+	// Bundle's Synced condition to false.
+	TargetSizeLimitPolicyFail TargetSizeLimitPolicy = "Fail"
+
+	// TargetSizeLimitPolicyWarn syncs the bundle data regardless, emitting a
+	// warning event when the targets are written.
+	TargetSizeLimitPolicyWarn TargetSizeLimitPolicy = "Warn"
+
+	// TargetSizeLimitPolicyTruncate omits the certificates at the end of the
+	// bundle data which exceed the limits. Since sources are ordered by
+	// weight, the certificates of the sources with the lowest weight are
+	// omitted first.
+	TargetSizeLimitPolicyTruncate TargetSizeLimitPolicy = "Truncate"
+
+	// TargetSizeLimitPolicyPartition splits the bundle data into partitions
+	// within the limits, in bundle order, instead of writing it to the
+	// target's key. Partition i is written to the key of the target with
+	// "-i" inserted before its extension, such as "ca-bundle-1.crt". The
+	// first partition is written to the target, and each further partition
+	// to a ConfigMap named after the target with the suffix "-i", in the same
+	// Namespace. The partition index key of the target lists the partitions
+	// as "<configmap>/<key>", one per line. Additional formats are built from
+	// the complete bundle data and written to the target.
+	TargetSizeLimitPolicyPartition TargetSizeLimitPolicy = "Partition"
+)
+
+// DefaultPartitionIndexKey is the default key of the target listing the
+// partitions of bundle data partitioned by the Partition size limit policy.
+const DefaultPartitionIndexKey = "partitions.txt"
+
+// BuildInfo controls the build metadata embedded in a target.
+type BuildInfo struct {
+	// Mode is one of `Reproducible` or `Informative`. In `Reproducible` mode,
+	// which is the default, no build metadata is embedded and the target is
+	// byte-for-byte reproducible from the Bundle sources. In `Informative`
+	// mode, the time at which the target data was last built is written to
+	// the timestampKey entry, and is used as the creation time of entries in
+	// any JKS truststore.
+	// +kubebuilder:validation:Enum=Reproducible;Informative
+	// +optional
+	Mode BuildInfoMode `json:"mode,omitempty"`
+
+	// TimestampKey is the key of the entry in the target's `data` field that
+	// the build time is written to in `Informative` mode, in RFC 3339 format.
+	// Defaults to "build-timestamp".
+	// +optional
+	TimestampKey string `json:"timestampKey,omitempty"`
+}
+
+// BuildInfoMode controls whether build metadata is embedded in a target.
+type BuildInfoMode string
+
+const (
+	// BuildInfoModeReproducible embeds no build metadata in the target.
+	BuildInfoModeReproducible BuildInfoMode = "Reproducible"
+
+	// BuildInfoModeInformative embeds the build time in the target.
+	BuildInfoModeInformative BuildInfoMode = "Informative"
+
+	// DefaultBuildTimestampKey is the default key the build time is written
+	// to in Informative mode.
+	DefaultBuildTimestampKey = "build-timestamp"
+)
+
+// AdditionalFormats specifies any additional formats to write to the target
+type AdditionalFormats struct {
+	JKS *JKS `json:"jks,omitempty"`
+
+	// PKCS12, if set, writes a binary PKCS#12 truststore of the bundle to the
+	// target's `binaryData` field, for consumers such as Java applications
+	// which can't read PEM. The truststore is encrypted using the algorithms
+	// of its profile and is only rebuilt when the bundle data, the password
+	// or the profile changes, since PKCS#12 encoding is randomly salted.
+	// +optional
+	PKCS12 *PKCS12 `json:"pkcs12,omitempty"`
+
+	// PKCS7 is the key of the entry in the target's `binaryData` field which a
+	// DER encoded, certificate-only PKCS#7 bundle (.p7b) of the bundle is
+	// written to, for consumers such as some Java and Windows applications
+	// which require PKCS#7 rather than PEM or PKCS#12.
+	// +optional
+	PKCS7 *KeySelector `json:"pkcs7,omitempty"`
+
+	// SST is the key of the entry in the target's `binaryData` field which a
+	// Microsoft serialized certificate store (.sst) of the bundle is written
+	// to, for Windows hosts and containers which import trust anchors with
+	// tools such as `certutil` or Group Policy.
+	// +optional
+	SST *KeySelector `json:"sst,omitempty"`
+
+	// Gzip, if set, writes the gzip-compressed bundle data to the target's
+	// `binaryData` field, for very large bundles. The hex encoded SHA-256
+	// digest of the uncompressed bundle data is written to the
+	// "trust.cert-manager.io/uncompressed-hash" annotation of the target, so
+	// that consumers can verify the data after decompressing it.
+	// +optional
+	Gzip *Gzip `json:"gzip,omitempty"`
+
+	// Metadata is the key of the entry in the target's `data` field which a
+	// JSON document describing each certificate in the bundle is written to.
+	// The document includes the SHA-256 fingerprint, subject and expiry of
+	// each certificate, along with the labels of the sources it came from,
+	// and optionally its PEM encoding.
+	// +optional
+	Metadata *Metadata `json:"metadata,omitempty"`
+
+	// SPIFFE is the key of the entry in the target's `data` field which a
+	// SPIFFE trust bundle is written to. The SPIFFE trust bundle is a JWK set
+	// containing each certificate in the bundle as an X.509 authority, as
+	// consumed by SPIFFE workloads such as those using cert-manager
+	// csi-driver-spiffe. If the distribution endpoint is enabled, the SPIFFE
+	// trust bundle is also served at '/bundles/<bundle>.spiffe', so that other
+	// trust domains can federate with the Bundle.
+	// +optional
+	SPIFFE *KeySelector `json:"spiffe,omitempty"`
+
+	// Provenance is the key of the entry in the target's `data` field which a
+	// JSON document recording the sources each certificate in the bundle came
+	// from is written to, such as the name and key of a source ConfigMap or
+	// the version of a default CA package, so that the origin of a trust
+	// anchor can be traced during audits.
+	// +optional
+	Provenance *KeySelector `json:"provenance,omitempty"`
+
+	// PEMDirectory, if set, writes each certificate in the bundle to its own
+	// entry of the target's `data` field, along with an index entry listing
+	// the keys of those entries. Paired with the `items` field of a ConfigMap
+	// or projected volume, this allows consumers to mount a directory of
+	// individual trust anchors with stable paths.
+	// +optional
+	PEMDirectory *PEMDirectory `json:"pemDirectory,omitempty"`
+
+	// HashedDirectory, if set, writes the certificates of the bundle in the
+	// layout of an OpenSSL hashed certificate directory, as created by
+	// c_rehash or "openssl rehash", for applications which look up trust
+	// anchors by the hash of their subject name in a CApath or the directory
+	// named by the SSL_CERT_DIR environment variable.
+	// +optional
+	HashedDirectory *HashedDirectory `json:"hashedDirectory,omitempty"`
+
+	// Profiles, if set, writes additional entries to the target's `data`
+	// field, each containing only the certificates of the bundle which are
+	// trusted for a purpose, so that distinct bundles for verifying servers
+	// and validating client certificates are built from the same sources.
+	// The purposes of each certificate are set by the purposes field of its
+	// sources.
+	// +optional
+	Profiles []TrustProfile `json:"profiles,omitempty"`
+}
+
+// TrustProfile is an entry of a target containing the certificates of the
+// bundle which are trusted for a purpose.
+type TrustProfile struct {
+	// Key is the key of the entry in the target's `data` field the PEM
+	// certificates are written to.
+	Key string `json:"key"`
+
+	// Purpose is one of `ServerAuth`, `ClientAuth` or `Any`, and selects the
+	// certificates trusted for that purpose. `Any` selects all certificates
+	// of the bundle.
+	Purpose TrustPurpose `json:"purpose"`
+}
+
+// PEMDirectory specifies the keys of the entries of a target that the
+// certificates of the bundle are individually written to.
+type PEMDirectory struct {
+	// KeyPrefix is the prefix of the keys of the entries the certificates are
+	// written to. Each key is the prefix followed by the name of the
+	// certificate, as set by keyNaming, and a ".pem" suffix, for example
+	// "ca-0000.pem". Defaults to "ca-".
+	// +optional
+	KeyPrefix string `json:"keyPrefix,omitempty"`
+
+	// KeyNaming is how the certificates are named in the keys of their
+	// entries, one of `Position`, `Fingerprint` or `SubjectHash`. `Position`
+	// names each certificate by its position in the bundle, zero-padded to
+	// four digits. `Fingerprint` names each certificate by the hex encoded
+	// SHA-256 digest of its DER encoding, so that the key of a certificate
+	// doesn't change when other certificates are added to or removed from the
+	// bundle. `SubjectHash` names each certificate by the OpenSSL hash of its
+	// subject name and a sequence number, as in a hashed certificate
+	// directory, for example "ca-5ed36f99.0.pem". Defaults to `Position`.
+	// +kubebuilder:validation:Enum=Position;Fingerprint;SubjectHash
+	// +optional
+	KeyNaming PEMDirectoryKeyNaming `json:"keyNaming,omitempty"`
+
+	// IndexKey is the key of the entry listing the keys of the certificate
+	// entries, one per line in bundle order. Defaults to "index.txt".
+	// +optional
+	IndexKey string `json:"indexKey,omitempty"`
+}
+
+// PEMDirectoryKeyNaming is how the certificates of a PEM directory are named in
+// the keys of their entries.
+type PEMDirectoryKeyNaming string
+
+const (
+	// PEMDirectoryKeyNamingPosition names certificates by their position in
+	// the bundle.
+	PEMDirectoryKeyNamingPosition PEMDirectoryKeyNaming = "Position"
+
+	// PEMDirectoryKeyNamingFingerprint names certificates by their SHA-256
+	// fingerprint.
+	PEMDirectoryKeyNamingFingerprint PEMDirectoryKeyNaming = "Fingerprint"
+
+	// PEMDirectoryKeyNamingSubjectHash names certificates by the OpenSSL hash
+	// of their subject name.
+	PEMDirectoryKeyNamingSubjectHash PEMDirectoryKeyNaming = "SubjectHash"
+)
+
+const (
+	// DefaultPEMDirectoryKeyPrefix is the default prefix of the keys the
+	// certificates of a PEM directory are written to.
+	DefaultPEMDirectoryKeyPrefix = "ca-"
+
+	// DefaultPEMDirectoryIndexKey is the default key of the index of a PEM
+	// directory.
+	DefaultPEMDirectoryIndexKey = "index.txt"
+)
+
+// HashedDirectory specifies how an OpenSSL hashed certificate directory of the
+// bundle is written to a target.
+type HashedDirectory struct {
+	// Packaging is how the hashed directory is written to the target, one of
+	// `Keys` or `Tarball`. In `Keys` packaging, each certificate is written to
+	// its own entry of the target's `data` field, keyed by the hash of its
+	// subject name and a sequence number, for example "5ed36f99.0", so that
+	// the target can be mounted as the directory. In `Tarball` packaging, the
+	// directory is written as a tar archive to a single entry of the target's
+	// `binaryData` field. Defaults to `Keys`.
+	// +kubebuilder:validation:Enum=Keys;Tarball
+	// +optional
+	Packaging HashedDirectoryPackaging `json:"packaging,omitempty"`
+
+	// IndexKey is the key of the entry listing the keys of the certificate
+	// entries in `Keys` packaging, one per line. Defaults to "hashes.txt".
+	// +optional
+	IndexKey string `json:"indexKey,omitempty"`
+
+	// TarballKey is the key of the entry in the target's `binaryData` field
+	// the tar archive is written to in `Tarball` packaging. Defaults to
+	// "certs.tar".
+	// +optional
+	TarballKey string `json:"tarballKey,omitempty"`
 }
 
-// BundleSource is the set of sources whose data will be appended and synced to
-// the BundleTarget in all Namespaces.
-type BundleSource struct {
-	// ConfigMap is a reference to a ConfigMap's `data` key, in the trust
-	// Namespace.
+// HashedDirectoryPackaging is how a hashed certificate directory is written to
+// a target.
+type HashedDirectoryPackaging string
+
+const (
+	// HashedDirectoryPackagingKeys writes each certificate to its own entry.
+	HashedDirectoryPackagingKeys HashedDirectoryPackaging = "Keys"
+
+	// HashedDirectoryPackagingTarball writes the directory as a tar archive.
+	HashedDirectoryPackagingTarball HashedDirectoryPackaging = "Tarball"
+
+	// DefaultHashedDirectoryIndexKey is the default key of the index of a
+	// hashed directory in Keys packaging.
+	DefaultHashedDirectoryIndexKey = "hashes.txt"
+
+	// DefaultHashedDirectoryTarballKey is the default key of the tar archive
+	// of a hashed directory in Tarball packaging.
+	DefaultHashedDirectoryTarballKey = "certs.tar"
+)
+
+// JKS specifies the key and password of a binary JKS truststore written to the
+// target.
+type JKS struct {
+	// KeySelector is the key of the entry in the target's `binaryData` field
+	// the JKS truststore is written to.
+	KeySelector `json:",inline"`
+
+	// Password is the plaintext password used to encrypt the JKS truststore.
+	// Mutually exclusive with PasswordFrom. If neither is set, the default
+	// Java password "changeit" is used.
 	// +optional
-	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
+	Password *string `json:"password,omitempty"`
 
-	// Secret is a reference to a Secrets's `data` key, in the trust
-	// Namespace.
+	// PasswordFrom sources the password used to encrypt the JKS truststore
+	// from outside of the Bundle. Mutually exclusive with Password.
 	// +optional
-	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+	PasswordFrom *PasswordSource `json:"passwordFrom,omitempty"`
 
-	// InLine is a simple string to append as the source data.
+	// AliasNaming is the naming strategy of the aliases of the truststore's
+	// entries, one of `Fingerprint`, `SubjectCN` or `Sequential`. Aliases are
+	// lower case, and stable across rebuilds of an unchanged bundle.
+	// `Fingerprint` names each entry by the first 8 hex characters of the
+	// SHA-256 fingerprint of its certificate followed by the certificate's
+	// subject. `SubjectCN` names each entry by the common name of its
+	// certificate's subject, or by the whole subject if it has no common
+	// name; entries whose names clash are suffixed by their fingerprint.
+	// `Sequential` names each entry by its zero-padded position in the
+	// bundle. Defaults to `Fingerprint`.
+	// +kubebuilder:validation:Enum=Fingerprint;SubjectCN;Sequential
 	// +optional
-	InLine *string `json:"inLine,omitempty"`
+	AliasNaming JKSAliasNaming `json:"aliasNaming,omitempty"`
+}
 
-	// UseDefaultCAs, when true, requests the default CA bundle to be used as a source.
-	// Default CAs are available if trust-manager was installed via Helm
-	// or was otherwise set up to include a package-injecting init container by using the
-	// "--default-package-location" flag when starting the trust-manager controller.
-	// If default CAs were not configured at start-up, any request to use the default
-	// CAs will fail.
-	// The version of the default CA package which is used for a Bundle is stored in the
-	// defaultCAPackageVersion field of the Bundle's status field.
+// JKSAliasNaming is the naming strategy of the aliases of the entries of a JKS
+// truststore.
+type JKSAliasNaming string
+
+const (
+	// JKSAliasNamingFingerprint names entries by their certificate's
+	// fingerprint and subject.
+	JKSAliasNamingFingerprint JKSAliasNaming = "Fingerprint"
+
+	// JKSAliasNamingSubjectCN names entries by their certificate's subject
+	// common name.
+	JKSAliasNamingSubjectCN JKSAliasNaming = "SubjectCN"
+
+	// JKSAliasNamingSequential names entries by their position in the bundle.
+	JKSAliasNamingSequential JKSAliasNaming = "Sequential"
+)
+
+// PKCS12 specifies the key and password of a binary PKCS#12 truststore written
+// to the target.
+type PKCS12 struct {
+	// KeySelector is the key of the entry in the target's `binaryData` field
+	// the PKCS#12 truststore is written to.
+	KeySelector `json:",inline"`
+
+	// Password is the plaintext password used to encrypt the PKCS#12
+	// truststore. Mutually exclusive with PasswordFrom. If neither is set,
+	// the truststore is encrypted with an empty password.
 	// +optional
-	UseDefaultCAs *bool `json:"useDefaultCAs,omitempty"`
+	Password *string `json:"password,omitempty"`
+
+	// PasswordFrom sources the password used to encrypt the PKCS#12
+	// truststore from outside of the Bundle, so that the password isn't
+	// readable by anyone who can read Bundles. Mutually exclusive with
+	// Password.
+	// +optional
+	PasswordFrom *PasswordSource `json:"passwordFrom,omitempty"`
+
+	// Profile is the encryption profile of the PKCS#12 truststore, one of
+	// `LegacyRC2`, `LegacyDES` or `Modern2023`. `LegacyRC2` encrypts the
+	// truststore with RC2 and `LegacyDES` with 3DES, both protected by an
+	// HMAC-SHA-1 MAC, for consumers such as older Java releases which don't
+	// support modern algorithms. `Modern2023` encrypts the truststore with
+	// AES-256-CBC, protected by an HMAC-SHA-256 MAC, and requires Java 12 or
+	// OpenSSL 1.1.1 and higher. Defaults to `Modern2023`. The legacy profiles
+	// can't be used if the trust-manager controller runs in FIPS mode, which
+	// is set using the "--fips" flag.
+	// +kubebuilder:validation:Enum=LegacyRC2;LegacyDES;Modern2023
+	// +optional
+	Profile PKCS12Profile `json:"profile,omitempty"`
 }
 
-// BundleTarget is the target resource that the Bundle will sync all source
-// data to.
-type BundleTarget struct {
-	// ConfigMap is the target ConfigMap in Namespaces that all Bundle source
-	// data will be synced to.
-	ConfigMap *KeySelector `json:"configMap,omitempty"`
+// PKCS12Profile is the encryption profile of a PKCS#12 truststore.
+type PKCS12Profile string
 
-	// AdditionalFormats specifies any additional formats to write to the target
+const (
+	// PKCS12ProfileLegacyRC2 encrypts PKCS#12 truststores with RC2.
+	PKCS12ProfileLegacyRC2 PKCS12Profile = "LegacyRC2"
+
+	// PKCS12ProfileLegacyDES encrypts PKCS#12 truststores with 3DES.
+	PKCS12ProfileLegacyDES PKCS12Profile = "LegacyDES"
+
+	// PKCS12ProfileModern2023 encrypts PKCS#12 truststores with AES-256-CBC.
+	PKCS12ProfileModern2023 PKCS12Profile = "Modern2023"
+)
+
+// Metadata specifies the key of the JSON metadata document written to the
+// target.
+type Metadata struct {
+	// KeySelector is the key of the entry in the target's `data` field the
+	// metadata document is written to.
+	KeySelector `json:",inline"`
+
+	// IncludePEM, when true, includes the PEM encoding of each certificate in
+	// the metadata document, so that consumers such as UIs can read the
+	// certificates of the bundle from the document alone.
 	// +optional
-	AdditionalFormats *AdditionalFormats `json:"additionalFormats,omitempty"`
+	IncludePEM bool `json:"includePEM,omitempty"`
+}
 
-	// NamespaceSelector will, if set, only sync the target resource in
-	// Namespaces which match the selector.
+// Gzip specifies the key of the gzip-compressed bundle data written to the
+// target.
+type Gzip struct {
+	// KeySelector is the key of the entry in the target's `binaryData` field
+	// the compressed bundle data is written to, for example
+	// "ca-bundle.crt.gz".
+	KeySelector `json:",inline"`
+
+	// OmitUncompressed, when true, writes only the compressed bundle data,
+	// and not the uncompressed bundle data to the target's key, so that
+	// bundles which would otherwise exceed the size limit of a ConfigMap can
+	// be synced. The maximum size in bytes of the target's size limit then
+	// applies to the compressed data. Consumers which read the uncompressed
+	// bundle data from the target's key are not supported.
 	// +optional
-	NamespaceSelector *NamespaceSelector `json:"namespaceSelector,omitempty"`
+	OmitUncompressed bool `json:"omitUncompressed,omitempty"`
 }
 
-// AdditionalFormats specifies any additional formats to write to the target
-type AdditionalFormats struct {
-	JKS *KeySelector `json:"jks,omitempty"`
+// TargetUncompressedHashAnnotationKey is the annotation written to targets
+// with the gzip additional format. Its value is the hex encoded SHA-256
+// digest of the uncompressed bundle data.
+const TargetUncompressedHashAnnotationKey = "trust.cert-manager.io/uncompressed-hash"
+
+// PasswordSource is a reference to a password held outside of the Bundle.
+// Exactly one field must be set.
+type PasswordSource struct {
+	// Secret is a reference to a key of a Secret in the trust Namespace whose
+	// value is the password.
+	// +optional
+	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+
+	// Provider is a reference to a password held by an external password
+	// provider plugin, such as a key management system, registered with
+	// trust-manager.
+	// +optional
+	Provider *PasswordProviderSelector `json:"provider,omitempty"`
+}
+
+// PasswordProviderSelector is a reference to a password held by an external
+// password provider plugin.
+type PasswordProviderSelector struct {
+	// Name is the name the password provider plugin is registered with in
+	// trust-manager.
+	Name string `json:"name"`
+
+	// Key identifies the password within the password provider, for example
+	// the ID of a secret in a key management system. The key is passed to the
+	// plugin verbatim.
+	Key string `json:"key"`
 }
 
 // NamespaceSelector defines selectors to match on Namespaces.
@@ -117,17 +1443,165 @@ type NamespaceSelector struct {
 	// Namespace for the Bundle target to be synced there.
 	// +optional
 	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchExpressions matches on a list of label selector requirements which
+	// must all be met by the labels of a Namespace, such as a `NotIn`
+	// requirement on the "kubernetes.io/metadata.name" label to select all
+	// Namespaces except the listed ones.
+	// +optional
+	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty"`
 }
 
 // SourceObjectKeySelector is a reference to a source object and its `data` key
-// in the trust Namespace.
+// in the trust Namespace. Exactly one of Key or KeyPattern must be set, and
+// KeyPattern is only supported by ConfigMap and Secret sources.
 type SourceObjectKeySelector struct {
 	// Name is the name of the source object in the trust Namespace.
 	Name string `json:"name"`
 
-	// KeySelector is the key of the entry in the objects' `data` field to be
-	// referenced.
-	KeySelector `json:",inline"`
+	// Key is the key of the entry in the object's `data` field to be used.
+	// +optional
+	Key string `json:"key,omitempty"`
+
+	// KeyPattern, if set, selects all entries in the object's `data` field
+	// whose keys match the given glob pattern, such as "*.crt", so that
+	// objects which hold other data alongside certificates can be used as a
+	// source. Patterns use the syntax of Go's path.Match. The data of the
+	// matching keys is included in alphabetical order of the keys. At least
+	// one key must match.
+	// +optional
+	KeyPattern string `json:"keyPattern,omitempty"`
+
+	// ExcludeKeys are keys of the object's `data` field which are skipped
+	// even if they match KeyPattern, such as "tls.key", so that known
+	// non-certificate entries don't cause the source to fail validation. Keys
+	// are compared exactly. Only valid if KeyPattern is set.
+	// +optional
+	ExcludeKeys []string `json:"excludeKeys,omitempty"`
+}
+
+// SourceTruststoreSelector is a reference to a binary truststore stored at a
+// key of a source object in the trust Namespace.
+type SourceTruststoreSelector struct {
+	// SourceObjectKeySelector is the reference to the key of the source object
+	// containing the truststore.
+	SourceObjectKeySelector `json:",inline"`
+
+	// Format is the format of the truststore, one of `JKS` or `PKCS12`.
+	// +kubebuilder:validation:Enum=JKS;PKCS12
+	Format TruststoreFormat `json:"format"`
+
+	// PasswordKey is an optional key in the same source object whose value is
+	// the password used to decode the truststore. If unset, JKS truststores
+	// are decoded using the default Java password "changeit" and PKCS#12
+	// truststores are decoded using an empty password.
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// TruststoreFormat is the binary format of a truststore.
+type TruststoreFormat string
+
+const (
+	// TruststoreFormatJKS is a Java KeyStore truststore.
+	TruststoreFormatJKS TruststoreFormat = "JKS"
+
+	// TruststoreFormatPKCS12 is a PKCS#12 truststore.
+	TruststoreFormatPKCS12 TruststoreFormat = "PKCS12"
+)
+
+// SourceRemoteCluster is a reference to a ConfigMap or Secret in another
+// cluster. Exactly one of ConfigMap or Secret must be defined.
+type SourceRemoteCluster struct {
+	// KubeconfigSecret is a reference to a key of a Secret in the trust
+	// Namespace containing a kubeconfig for the remote cluster. The current
+	// context of the kubeconfig is used.
+	KubeconfigSecret SourceObjectKeySelector `json:"kubeconfigSecret"`
+
+	// Namespace is the Namespace in the remote cluster containing the source
+	// object.
+	Namespace string `json:"namespace"`
+
+	// ConfigMap is a reference to a ConfigMap's `data` or `binaryData` key in
+	// the remote Namespace.
+	// +optional
+	ConfigMap *SourceObjectKeySelector `json:"configMap,omitempty"`
+
+	// Secret is a reference to a Secret's `data` key in the remote Namespace.
+	// +optional
+	Secret *SourceObjectKeySelector `json:"secret,omitempty"`
+
+	// RefreshInterval is the interval at which the remote object is re-fetched,
+	// overriding the controller's "--external-source-refresh-period" for this
+	// source. When a Bundle has several external sources, it is refreshed at
+	// the shortest of their intervals.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// SourceObjectStorage is a reference to an object in a blob store.
+type SourceObjectStorage struct {
+	// Provider is the blob store provider, one of `S3`, `GCS` or `AzureBlob`.
+	// +kubebuilder:validation:Enum=S3;GCS;AzureBlob
+	Provider ObjectStorageProvider `json:"provider"`
+
+	// Bucket is the name of the bucket containing the object, or the name of
+	// the container for AzureBlob.
+	Bucket string `json:"bucket"`
+
+	// Key is the key of the object, or the name of the blob for AzureBlob.
+	Key string `json:"key"`
+
+	// Region is the region of the S3 bucket. Defaults to "us-east-1".
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Account is the name of the storage account. Required for AzureBlob.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// Endpoint, if set, overrides the URL of the provider's API, for example
+	// to use an S3-compatible blob store. Objects are addressed using
+	// path-style URLs below the endpoint.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// CredentialsSecret is the name of a Secret in the trust Namespace
+	// containing credentials for the blob store. For S3 and GCS (using HMAC
+	// keys), the Secret must contain the keys `accessKeyID` and
+	// `secretAccessKey`, and may contain `sessionToken`. For AzureBlob, the
+	// Secret must contain the key `sasToken`. If unset, the object is fetched
+	// anonymously.
+	// +optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// RefreshInterval is the interval at which the object is re-fetched,
+	// overriding the controller's "--external-source-refresh-period" for this
+	// source. When a Bundle has several external sources, it is refreshed at
+	// the shortest of their intervals.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// ObjectStorageProvider is a blob store provider.
+type ObjectStorageProvider string
+
+const (
+	// ObjectStorageProviderS3 is Amazon S3, or an S3-compatible blob store.
+	ObjectStorageProviderS3 ObjectStorageProvider = "S3"
+
+	// ObjectStorageProviderGCS is Google Cloud Storage.
+	ObjectStorageProviderGCS ObjectStorageProvider = "GCS"
+
+	// ObjectStorageProviderAzureBlob is Azure Blob Storage.
+	ObjectStorageProviderAzureBlob ObjectStorageProvider = "AzureBlob"
+)
+
+// SourceObjectSelector is a reference to a source object in the trust
+// Namespace.
+type SourceObjectSelector struct {
+	// Name is the name of the source object in the trust Namespace.
+	Name string `json:"name"`
 }
 
 // KeySelector is a reference to a key for some map data object.
@@ -136,6 +1610,59 @@ type KeySelector struct {
 	Key string `json:"key"`
 }
 
+// TargetKeySelector is a reference to a key of a target object, which is
+// optionally named explicitly.
+type TargetKeySelector struct {
+	// Name is the name of the target object in each Namespace. Defaults to the
+	// name rendered from the Bundle's name, which is the name of the Bundle
+	// unless trust-manager is configured with a different naming convention.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Key is the key of the entry in the object's `data` field to be used, or
+	// of its `binaryData` field in the `DER` format.
+	Key string `json:"key"`
+
+	// Format is the format the bundle is written to the key in, one of `PEM`
+	// or `DER`. In the `DER` format, the certificates of the bundle are
+	// written as concatenated DER certificates to the object's `binaryData`
+	// field, for consumers such as embedded and Windows-based applications
+	// which can't parse PEM. Defaults to `PEM`.
+	// +kubebuilder:validation:Enum=PEM;DER
+	// +optional
+	Format TargetFormat `json:"format,omitempty"`
+
+	// Comments, when true, prefixes each certificate of the bundle written
+	// to the key with comment lines stating its subject, issuer, expiry and
+	// SHA-256 fingerprint, so that the bundle can be audited without external
+	// tooling. The comments are ignored by PEM parsers, and aren't counted
+	// towards the target's size limit. Only valid in the `PEM` format, and
+	// not with the Partition sizeLimit policy.
+	// +optional
+	Comments bool `json:"comments,omitempty"`
+
+	// PreviousKeyRetention, if set, is the duration for which the bundle data
+	// continues to be written to the previous key after Key is changed, so
+	// that consumers can migrate to the new key without a hard cutover. While
+	// previous keys are retained, the Bundle's `Deprecated` condition is true
+	// and names them. If unset, the data is removed from the previous key
+	// immediately. Only valid in the `PEM` format, and not with the Partition
+	// sizeLimit policy or compressed-only targets.
+	// +optional
+	PreviousKeyRetention *metav1.Duration `json:"previousKeyRetention,omitempty"`
+}
+
+// TargetFormat is the format the bundle is written to a target key in.
+type TargetFormat string
+
+const (
+	// TargetFormatPEM writes the bundle as PEM-encoded certificates.
+	TargetFormatPEM TargetFormat = "PEM"
+
+	// TargetFormatDER writes the bundle as concatenated DER certificates.
+	TargetFormatDER TargetFormat = "DER"
+)
+
 // BundleStatus defines the observed state of the Bundle.
 type BundleStatus struct {
 	// Target is the current Target that the Bundle is attempting or has
@@ -153,11 +1680,367 @@ type BundleStatus struct {
 	// source. This should only be set if useDefaultCAs was set to "true" on a source,
 	// and will be the same for the same version of a bundle with identical certificates.
 	DefaultCAPackageVersion *string `json:"defaultCAVersion,omitempty"`
+
+	// DefaultCAPackages, if set, indicates the version information of each
+	// named default CA package which was requested in the bundle sources.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	DefaultCAPackages []DefaultCAPackageStatus `json:"defaultCAPackages,omitempty"`
+
+	// AppliedContentHash, if set, is the hash of the bundle data which was last
+	// synced to the targets. This is only set if maintenance windows are
+	// defined, and is used to defer content changes outside of them.
+	// +optional
+	AppliedContentHash string `json:"appliedContentHash,omitempty"`
+
+	// Content, if set, is the bundle data which was last synced to the
+	// Bundle's targets, so that tooling can retrieve the canonical content of
+	// the Bundle without reading the target of an arbitrary Namespace.
+	// +optional
+	Content *BundleContent `json:"content,omitempty"`
+
+	// PermissionCheck, if set, is the result of the last check of whether the
+	// controller has the permissions needed to sync this Bundle. A check is
+	// requested by setting the "trust.cert-manager.io/check-permissions"
+	// annotation on the Bundle to a new value.
+	// +optional
+	PermissionCheck *BundlePermissionCheck `json:"permissionCheck,omitempty"`
+
+	// ManagedClusters, if set, is the sorted list of managed clusters which the
+	// Bundle has been distributed to through its placement.
+	// +optional
+	ManagedClusters []string `json:"managedClusters,omitempty"`
+
+	// ExcludedExpiredCertificates is the number of expired certificates which
+	// were excluded from the bundle by the excludeExpired filter.
+	// +optional
+	ExcludedExpiredCertificates int32 `json:"excludedExpiredCertificates,omitempty"`
+
+	// ExcludedExpiringCertificates is the number of certificates which had not
+	// yet expired, but were excluded from the bundle by the
+	// excludeExpiringWithin filter.
+	// +optional
+	ExcludedExpiringCertificates int32 `json:"excludedExpiringCertificates,omitempty"`
+
+	// ExcludedMatchedCertificates is the number of certificates which were
+	// excluded from the bundle by the include, exclude and fingerprint
+	// filters, or by the exclusions of default CA sources.
+	// +optional
+	ExcludedMatchedCertificates int32 `json:"excludedMatchedCertificates,omitempty"`
+
+	// DuplicateCertificates is the number of certificates which were omitted
+	// from the bundle because the same certificate, or a certificate with the
+	// same public key if the deduplicateByPublicKey filter is set, was already
+	// included from the same or another source.
+	// +optional
+	DuplicateCertificates int32 `json:"duplicateCertificates,omitempty"`
+
+	// CrossSignedCertificates is the number of certificates in the bundle
+	// sources which have the same subject and subject key identifier as a
+	// preferred certificate, such as cross-signed or re-issued CAs. They are
+	// omitted from the bundle unless the crossSigned filter is `KeepAll`.
+	// +optional
+	CrossSignedCertificates int32 `json:"crossSignedCertificates,omitempty"`
+
+	// DistrustedCertificates is the number of certificates of default CA
+	// packages which were excluded from the bundle since their distrust-after
+	// time, as published in the package, has passed.
+	// +optional
+	DistrustedCertificates int32 `json:"distrustedCertificates,omitempty"`
+
+	// NonCACertificates is the number of certificates from the Bundle's
+	// sources without the `CA:true` basic constraint. They were excluded from
+	// the bundle if the nonCACertificates filter is `Enforce`, and included
+	// otherwise.
+	// +optional
+	NonCACertificates int32 `json:"nonCACertificates,omitempty"`
+
+	// WeakCryptoCertificates is the number of certificates from the Bundle's
+	// sources with weak keys or signatures. They were excluded from the bundle
+	// if the effective weak crypto action is `Enforce`, and included
+	// otherwise. Only counted if the effective action is `Warn` or `Enforce`.
+	// +optional
+	WeakCryptoCertificates int32 `json:"weakCryptoCertificates,omitempty"`
+
+	// InvalidPEMBlocks is the number of PEM blocks in the data of the
+	// Bundle's sources which aren't valid certificates, and were skipped
+	// since the Bundle's parsing policy is `Lenient`.
+	// +optional
+	InvalidPEMBlocks int32 `json:"invalidPEMBlocks,omitempty"`
+
+	// TruncatedCertificates is the number of certificates omitted from the
+	// Bundle's targets because the bundle data exceeded the target's size
+	// limit, if the size limit policy is `Truncate`.
+	// +optional
+	TruncatedCertificates int32 `json:"truncatedCertificates,omitempty"`
+
+	// SourceHealth is the result of the last probe of each source outside of
+	// the cluster's trust Namespace, such as object storage and remote
+	// cluster sources. Sources are probed periodically, independently of
+	// their refresh, so that outages are visible before they affect a
+	// refresh. Only set if source health probing is enabled on the
+	// controller.
+	// +optional
+	SourceHealth []SourceHealth `json:"sourceHealth,omitempty"`
+
+	// Acknowledgments, if set, is the aggregated acknowledgment of the
+	// current bundle data by the consumers of the Bundle's targets. Only set
+	// if the Bundle tracks acknowledgments.
+	// +optional
+	Acknowledgments *BundleAcknowledgments `json:"acknowledgments,omitempty"`
+
+	// OCI, if set, is the OCI artifact which the bundle data was last pushed
+	// to. Only set if the Bundle has an OCI target.
+	// +optional
+	OCI *BundleOCIStatus `json:"oci,omitempty"`
+
+	// RetainedKeys are the previous keys of the target which the bundle data
+	// is still written to, following a change of the target key with a
+	// previous key retention.
+	// +optional
+	RetainedKeys []RetainedTargetKey `json:"retainedKeys,omitempty"`
+}
+
+// RetainedTargetKey is a previous key of a target which the bundle data is
+// still written to.
+type RetainedTargetKey struct {
+	// Key is the previous key of the target.
+	Key string `json:"key"`
+
+	// Until is the time after which the bundle data is removed from the key.
+	Until metav1.Time `json:"until"`
+}
+
+// BundleOCIStatus is an OCI artifact which bundle data was pushed to.
+type BundleOCIStatus struct {
+	// Reference is the repository and tag which the artifact was pushed to.
+	Reference string `json:"reference"`
+
+	// Digest is the digest of the artifact's manifest, which can be used to
+	// pull or sign the exact artifact.
+	Digest string `json:"digest"`
+}
+
+// BundleAcknowledgments is the aggregated acknowledgment of the bundle data
+// by the consumers of a Bundle's targets.
+type BundleAcknowledgments struct {
+	// Hash is the hash of the bundle data which consumers acknowledge, as
+	// written to the "trust.cert-manager.io/hash" annotation of each target.
+	Hash string `json:"hash"`
+
+	// Targets is the number of targets of the Bundle.
+	Targets int32 `json:"targets"`
+
+	// Acknowledged is the number of targets whose consumers have acknowledged
+	// the hash.
+	Acknowledged int32 `json:"acknowledged"`
+
+	// PendingNamespaces lists the Namespaces of targets whose consumers have
+	// not yet acknowledged the hash, in alphabetical order. At most 10
+	// Namespaces are listed.
+	// +optional
+	PendingNamespaces []string `json:"pendingNamespaces,omitempty"`
+}
+
+// SourceHealth is the result of probing the availability of a source of a
+// Bundle.
+type SourceHealth struct {
+	// Index is the index of the source in the Bundle's sources.
+	Index int32 `json:"index"`
+
+	// Healthy is true if the last probe of the source succeeded.
+	Healthy bool `json:"healthy"`
+
+	// LastTransitionTime is the time the source last became healthy or
+	// unhealthy.
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// Message is the reason the last probe of the source failed.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// BundlePermissionCheck is the result of checking whether the controller has
+// the permissions needed to sync a Bundle.
+type BundlePermissionCheck struct {
+	// Request is the value of the check permissions annotation which requested
+	// this check.
+	Request string `json:"request"`
+
+	// CheckTime is the time at which the check was performed.
+	CheckTime metav1.Time `json:"checkTime"`
+
+	// Allowed is true if the controller has every permission checked, in every
+	// class of Namespace.
+	Allowed bool `json:"allowed"`
+
+	// NamespaceClasses holds the result of the check for each class of
+	// Namespace the controller acts in for this Bundle.
+	// +optional
+	// +listType=map
+	// +listMapKey=class
+	NamespaceClasses []NamespaceClassPermissions `json:"namespaceClasses,omitempty"`
+}
+
+// NamespaceClassPermissions is the result of checking the permissions of the
+// controller for a class of Namespace.
+type NamespaceClassPermissions struct {
+	// Class is the class of Namespace which was checked.
+	Class NamespaceClass `json:"class"`
+
+	// Namespace is the Namespace, representative of the class, in which the
+	// permissions were checked.
+	Namespace string `json:"namespace"`
+
+	// Allowed is true if the controller has every permission checked in this
+	// class of Namespace.
+	Allowed bool `json:"allowed"`
+
+	// Denied lists the permissions the controller is missing, formatted as
+	// "<verb> <resource>".
+	// +optional
+	Denied []string `json:"denied,omitempty"`
+}
+
+// NamespaceClass is a class of Namespace which the controller acts in for a
+// Bundle.
+type NamespaceClass string
+
+const (
+	// NamespaceClassSource is the trust Namespace, which the sources of a
+	// Bundle are read from.
+	NamespaceClassSource NamespaceClass = "Source"
+
+	// NamespaceClassTarget is the Namespaces matched by the namespace
+	// selector of a Bundle, which targets are written to.
+	NamespaceClassTarget NamespaceClass = "Target"
+
+	// NamespaceClassExcluded is the Namespaces not matched by the namespace
+	// selector of a Bundle, which stale targets are removed from.
+	NamespaceClassExcluded NamespaceClass = "Excluded"
+)
+
+// BundleCheckPermissionsAnnotationKey is the annotation which requests the
+// controller to check whether it has the permissions needed to sync a Bundle.
+// A new check is performed whenever the value of the annotation changes, the
+// result of which is written to the permissionCheck status field.
+const BundleCheckPermissionsAnnotationKey = "trust.cert-manager.io/check-permissions"
+
+const (
+	// TargetHashAnnotationKey is the annotation written to the targets of
+	// Bundles which track acknowledgments. Its value is the hex encoded
+	// SHA-256 digest of the bundle data written to the target, as computed by
+	// the github.com/cert-manager/trust-manager/pkg/targethash package.
+	TargetHashAnnotationKey = "trust.cert-manager.io/hash"
+
+	// TargetAcknowledgedHashAnnotationKey is the annotation which consumers
+	// of a target set to the value of the target's hash annotation once they
+	// have loaded the bundle data, acknowledging that the data is in use.
+	TargetAcknowledgedHashAnnotationKey = "trust.cert-manager.io/acknowledged-hash"
+)
+
+const (
+	// NamespaceSkipTargetsAnnotationKey is the annotation which, when set to
+	// "true" on a Namespace, excludes the Namespace from the targets of all
+	// Bundles, regardless of their namespace selectors. Targets which already
+	// exist in the Namespace are removed.
+	NamespaceSkipTargetsAnnotationKey = "trust.cert-manager.io/skip-targets"
+
+	// NamespaceTargetKeyAnnotationKey is the annotation which, when set on a
+	// Namespace, overrides the key of the target ConfigMap entry which the
+	// bundle data of all Bundles is written to in the Namespace. The keys of
+	// additional formats are not affected.
+	NamespaceTargetKeyAnnotationKey = "trust.cert-manager.io/target-key"
+)
+
+// RestartOnBundleChangeAnnotationKey is the annotation which, when set on a
+// Deployment, StatefulSet or DaemonSet to a comma separated list of names of
+// Bundles, requests the consumer restarter to roll out the workload's pods
+// whenever the content of any of the Bundles changes.
+const RestartOnBundleChangeAnnotationKey = "trust.cert-manager.io/restart-on-bundle-change"
+
+// BundleHashesAnnotationKey is the annotation of the pod template of a
+// workload restarted on Bundle changes, recording the content hash of each of
+// the Bundles it consumes as a comma separated list of "<bundle>=<hash>".
+// Changing the annotation rolls out the workload's pods.
+const BundleHashesAnnotationKey = "trust.cert-manager.io/bundle-hashes"
+
+// CAInjectionAnnotationKey is the annotation which, when set to the name of a
+// Bundle on a ValidatingWebhookConfiguration, MutatingWebhookConfiguration,
+// CustomResourceDefinition or APIService, requests the CA injector to write
+// the Bundle's data to the object's caBundle fields. The Bundle must sync to
+// the trust Namespace.
+const CAInjectionAnnotationKey = "trust.cert-manager.io/inject-ca-from-bundle"
+
+const (
+	// PodInjectBundleAnnotationKey is the annotation which, when set on a Pod
+	// to the name of a Bundle, requests the injection webhook to mount the
+	// Bundle's target ConfigMap into every container of the Pod.
+	PodInjectBundleAnnotationKey = "trust.cert-manager.io/inject-bundle"
+
+	// PodInjectMountPathAnnotationKey is the annotation which sets the
+	// directory the injected Bundle is mounted at. Defaults to
+	// DefaultInjectMountPath.
+	PodInjectMountPathAnnotationKey = "trust.cert-manager.io/inject-mount-path"
+
+	// PodInjectFormatAnnotationKey is the annotation which sets the format of
+	// the injected Bundle, one of "pem" or "jks". Defaults to "pem". The JKS
+	// format requires the Bundle to write the JKS additional format.
+	PodInjectFormatAnnotationKey = "trust.cert-manager.io/inject-format"
+
+	// DefaultInjectMountPath is the directory injected Bundles are mounted at
+	// if the Pod doesn't set a mount path.
+	DefaultInjectMountPath = "/etc/trust-manager/bundle"
+)
+
+// SecretMirrorLabelKey is the label which, when set to "true" on a Secret in
+// the trust Namespace, requests the certificates in the Secret to be mirrored
+// into a ConfigMap of the same name by the "trust-manager mirror-secrets"
+// helper. Private keys and other data which aren't certificates are never
+// mirrored, so Bundles can use TLS Secrets as sources via a configMap source
+// without the controller reading Secrets.
+const SecretMirrorLabelKey = "trust.cert-manager.io/mirror"
+
+// DefaultCAPackageStatus is the version information of a named default CA
+// package used by a Bundle.
+type DefaultCAPackageStatus struct {
+	// Name is the name of the default CA package.
+	Name string `json:"name"`
+
+	// Version is the version information which was retrieved for the default
+	// CA package. It will be the same for the same version of a package with
+	// identical certificates.
+	Version string `json:"version"`
+
+	// Fallback is true if the default CA package was selected from the
+	// fallback list of a source, because the requested package was not
+	// loaded.
+	// +optional
+	Fallback bool `json:"fallback,omitempty"`
+}
+
+// BundleContent is the bundle data which was last synced to a Bundle's
+// targets.
+type BundleContent struct {
+	// Data is the PEM-encoded bundle data, exactly as written to the targets.
+	// It is omitted if the data is larger than the maximum size of content in
+	// the status, which is set using the "--status-content-max-bytes" flag of
+	// the trust-manager controller.
+	// +optional
+	Data string `json:"data,omitempty"`
+
+	// Hash is the lower case hex encoded SHA-256 digest of the bundle data.
+	Hash string `json:"hash"`
+
+	// Size is the size of the bundle data in bytes, which is set even if the
+	// data is omitted.
+	Size int32 `json:"size"`
 }
 
 // BundleCondition contains condition information for a Bundle.
 type BundleCondition struct {
-	// Type of the condition, known values are (`Synced`).
+	// Type of the condition, known values are (`Synced`, `CollisionDetected`).
 	Type BundleConditionType `json:"type"`
 
 	// Status of the condition, one of ('True', 'False', 'Unknown').
@@ -194,4 +2077,24 @@ const (
 	// BundleConditionSynced indicates that the Bundle has successfully synced
 	// all source bundle data to the Bundle target in all Namespaces.
 	BundleConditionSynced BundleConditionType = "Synced"
+
+	// BundleConditionCollisionDetected indicates that a target already
+	// existed in one or more Namespaces without being owned by the Bundle,
+	// and how the conflict was handled according to the target conflict
+	// policy.
+	BundleConditionCollisionDetected BundleConditionType = "CollisionDetected"
+
+	// BundleConditionPrivateKeyDetected indicates that the data of one or more
+	// of the Bundle's sources contains a PEM private key.
+	BundleConditionPrivateKeyDetected BundleConditionType = "PrivateKeyDetected"
+
+	// BundleConditionDegraded indicates that one or more of the Bundle's
+	// sources could not be read or parsed, and that the targets are synced
+	// with the content which was last successfully synced instead.
+	BundleConditionDegraded BundleConditionType = "Degraded"
+
+	// BundleConditionDeprecated indicates that the bundle data is still
+	// written to previous keys of the target, which consumers should migrate
+	// away from before they are removed.
+	BundleConditionDeprecated BundleConditionType = "Deprecated"
 )
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Bundle",type="string",JSONPath=".spec.bundleName",description="Checked Bundle"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".spec.namespace",description="Namespace of the checked Bundle target"
+// +kubebuilder:printcolumn:name="Passed",type="string",JSONPath=`.status.conditions[?(@.type == "Passed")].status`,description="Bundle target meets all expectations"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Timestamp BundleCheck was created"
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+
+// BundleCheck declares expectations of the content which a Bundle renders to
+// its target, which are continuously evaluated by the controller and reported
+// as conditions.
+type BundleCheck struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Desired state of the BundleCheck resource.
+	Spec BundleCheckSpec `json:"spec"`
+
+	// Status of the BundleCheck. This is set and managed automatically.
+	// +optional
+	Status BundleCheckStatus `json:"status"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type BundleCheckList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BundleCheck `json:"items"`
+}
+
+// BundleCheckSpec defines the desired state of a BundleCheck.
+type BundleCheckSpec struct {
+	// BundleName is the name of the Bundle whose rendered content is checked.
+	BundleName string `json:"bundleName"`
+
+	// Namespace is the Namespace of the Bundle's target ConfigMap whose
+	// content is checked.
+	Namespace string `json:"namespace"`
+
+	// Expectations are the expectations of the certificates in the Bundle's
+	// target. The check passes if all of the set expectations are met.
+	Expectations BundleCheckExpectations `json:"expectations"`
+}
+
+// BundleCheckExpectations are expectations of the certificates in a Bundle's
+// target.
+type BundleCheckExpectations struct {
+	// ContainsFingerprints are hex encoded SHA-256 fingerprints of
+	// certificates which the target must contain. Fingerprints may be given in
+	// upper or lower case, with or without colon separators.
+	// +optional
+	ContainsFingerprints []string `json:"containsFingerprints,omitempty"`
+
+	// NoExpiredCertificates, when true, expects the target to contain no
+	// certificates which have expired.
+	// +optional
+	NoExpiredCertificates bool `json:"noExpiredCertificates,omitempty"`
+
+	// MaxCertificates, if set, is the maximum number of certificates which the
+	// target may contain.
+	// +optional
+	MaxCertificates *int32 `json:"maxCertificates,omitempty"`
+}
+
+// BundleCheckStatus defines the observed state of a BundleCheck.
+type BundleCheckStatus struct {
+	// List of status conditions to indicate the status of the BundleCheck.
+	// Known condition types are `Passed`, `ContainsFingerprints`,
+	// `NoExpiredCertificates` and `MaxCertificates`.
+	// +optional
+	Conditions []BundleCheckCondition `json:"conditions,omitempty"`
+
+	// LastCheckTime is the time at which the Bundle's target was last checked.
+	// +optional
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+}
+
+// BundleCheckCondition contains condition information for a BundleCheck.
+type BundleCheckCondition struct {
+	// Type of the condition, known values are (`Passed`,
+	// `ContainsFingerprints`, `NoExpiredCertificates`, `MaxCertificates`).
+	Type BundleCheckConditionType `json:"type"`
+
+	// Status of the condition, one of ('True', 'False', 'Unknown').
+	Status corev1.ConditionStatus `json:"status"`
+
+	// LastTransitionTime is the timestamp corresponding to the last status
+	// change of this condition.
+	// +optional
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Reason is a brief machine readable explanation for the condition's last
+	// transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of the details of the last
+	// transition, complementing reason.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// If set, this represents the .metadata.generation that the condition was
+	// set based upon.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// BundleCheckConditionType represents a BundleCheck condition value.
+type BundleCheckConditionType string
+
+const (
+	// BundleCheckConditionPassed indicates that the Bundle's target meets all
+	// of the BundleCheck's expectations.
+	BundleCheckConditionPassed BundleCheckConditionType = "Passed"
+
+	// BundleCheckConditionContainsFingerprints indicates that the Bundle's
+	// target contains all of the expected certificates.
+	BundleCheckConditionContainsFingerprints BundleCheckConditionType = "ContainsFingerprints"
+
+	// BundleCheckConditionNoExpiredCertificates indicates that the Bundle's
+	// target contains no expired certificates.
+	BundleCheckConditionNoExpiredCertificates BundleCheckConditionType = "NoExpiredCertificates"
+
+	// BundleCheckConditionMaxCertificates indicates that the Bundle's target
+	// contains no more than the maximum number of certificates.
+	BundleCheckConditionMaxCertificates BundleCheckConditionType = "MaxCertificates"
+)
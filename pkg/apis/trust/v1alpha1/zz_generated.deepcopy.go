@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -30,9 +31,59 @@ func (in *AdditionalFormats) DeepCopyInto(out *AdditionalFormats) {
 	*out = *in
 	if in.JKS != nil {
 		in, out := &in.JKS, &out.JKS
+		*out = new(JKS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PKCS12 != nil {
+		in, out := &in.PKCS12, &out.PKCS12
+		*out = new(PKCS12)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PKCS7 != nil {
+		in, out := &in.PKCS7, &out.PKCS7
+		*out = new(KeySelector)
+		**out = **in
+	}
+	if in.SST != nil {
+		in, out := &in.SST, &out.SST
+		*out = new(KeySelector)
+		**out = **in
+	}
+	if in.Gzip != nil {
+		in, out := &in.Gzip, &out.Gzip
+		*out = new(Gzip)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		**out = **in
+	}
+	if in.SPIFFE != nil {
+		in, out := &in.SPIFFE, &out.SPIFFE
+		*out = new(KeySelector)
+		**out = **in
+	}
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
 		*out = new(KeySelector)
 		**out = **in
 	}
+	if in.PEMDirectory != nil {
+		in, out := &in.PEMDirectory, &out.PEMDirectory
+		*out = new(PEMDirectory)
+		**out = **in
+	}
+	if in.HashedDirectory != nil {
+		in, out := &in.HashedDirectory, &out.HashedDirectory
+		*out = new(HashedDirectory)
+		**out = **in
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]TrustProfile, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -46,6 +97,22 @@ func (in *AdditionalFormats) DeepCopy() *AdditionalFormats {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuildInfo) DeepCopyInto(out *BuildInfo) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuildInfo.
+func (in *BuildInfo) DeepCopy() *BuildInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(BuildInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bundle) DeepCopyInto(out *Bundle) {
 	*out = *in
@@ -75,52 +142,48 @@ func (in *Bundle) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleCondition) DeepCopyInto(out *BundleCondition) {
+func (in *BundleAcknowledgments) DeepCopyInto(out *BundleAcknowledgments) {
 	*out = *in
-	if in.LastTransitionTime != nil {
-		in, out := &in.LastTransitionTime, &out.LastTransitionTime
-		*out = (*in).DeepCopy()
+	if in.PendingNamespaces != nil {
+		in, out := &in.PendingNamespaces, &out.PendingNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCondition.
-func (in *BundleCondition) DeepCopy() *BundleCondition {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleAcknowledgments.
+func (in *BundleAcknowledgments) DeepCopy() *BundleAcknowledgments {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleCondition)
+	out := new(BundleAcknowledgments)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleList) DeepCopyInto(out *BundleList) {
+func (in *BundleCheck) DeepCopyInto(out *BundleCheck) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Bundle, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleList.
-func (in *BundleList) DeepCopy() *BundleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheck.
+func (in *BundleCheck) DeepCopy() *BundleCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleList)
+	out := new(BundleCheck)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *BundleList) DeepCopyObject() runtime.Object {
+func (in *BundleCheck) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -128,181 +191,1306 @@ func (in *BundleList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleSource) DeepCopyInto(out *BundleSource) {
+func (in *BundleCheckCondition) DeepCopyInto(out *BundleCheckCondition) {
 	*out = *in
-	if in.ConfigMap != nil {
-		in, out := &in.ConfigMap, &out.ConfigMap
-		*out = new(SourceObjectKeySelector)
-		**out = **in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
 	}
-	if in.Secret != nil {
-		in, out := &in.Secret, &out.Secret
-		*out = new(SourceObjectKeySelector)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheckCondition.
+func (in *BundleCheckCondition) DeepCopy() *BundleCheckCondition {
+	if in == nil {
+		return nil
 	}
-	if in.InLine != nil {
-		in, out := &in.InLine, &out.InLine
-		*out = new(string)
-		**out = **in
+	out := new(BundleCheckCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleCheckExpectations) DeepCopyInto(out *BundleCheckExpectations) {
+	*out = *in
+	if in.ContainsFingerprints != nil {
+		in, out := &in.ContainsFingerprints, &out.ContainsFingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	if in.UseDefaultCAs != nil {
-		in, out := &in.UseDefaultCAs, &out.UseDefaultCAs
-		*out = new(bool)
+	if in.MaxCertificates != nil {
+		in, out := &in.MaxCertificates, &out.MaxCertificates
+		*out = new(int32)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleSource.
-func (in *BundleSource) DeepCopy() *BundleSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheckExpectations.
+func (in *BundleCheckExpectations) DeepCopy() *BundleCheckExpectations {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleSource)
+	out := new(BundleCheckExpectations)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleSpec) DeepCopyInto(out *BundleSpec) {
+func (in *BundleCheckList) DeepCopyInto(out *BundleCheckList) {
 	*out = *in
-	if in.Sources != nil {
-		in, out := &in.Sources, &out.Sources
-		*out = make([]BundleSource, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BundleCheck, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	in.Target.DeepCopyInto(&out.Target)
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleSpec.
-func (in *BundleSpec) DeepCopy() *BundleSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheckList.
+func (in *BundleCheckList) DeepCopy() *BundleCheckList {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleSpec)
+	out := new(BundleCheckList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BundleCheckList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleStatus) DeepCopyInto(out *BundleStatus) {
+func (in *BundleCheckSpec) DeepCopyInto(out *BundleCheckSpec) {
 	*out = *in
-	if in.Target != nil {
-		in, out := &in.Target, &out.Target
-		*out = new(BundleTarget)
-		(*in).DeepCopyInto(*out)
+	in.Expectations.DeepCopyInto(&out.Expectations)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheckSpec.
+func (in *BundleCheckSpec) DeepCopy() *BundleCheckSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(BundleCheckSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleCheckStatus) DeepCopyInto(out *BundleCheckStatus) {
+	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]BundleCondition, len(*in))
+		*out = make([]BundleCheckCondition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.DefaultCAPackageVersion != nil {
-		in, out := &in.DefaultCAPackageVersion, &out.DefaultCAPackageVersion
-		*out = new(string)
-		**out = **in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleStatus.
-func (in *BundleStatus) DeepCopy() *BundleStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCheckStatus.
+func (in *BundleCheckStatus) DeepCopy() *BundleCheckStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleStatus)
+	out := new(BundleCheckStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *BundleTarget) DeepCopyInto(out *BundleTarget) {
+func (in *BundleCondition) DeepCopyInto(out *BundleCondition) {
 	*out = *in
-	if in.ConfigMap != nil {
-		in, out := &in.ConfigMap, &out.ConfigMap
-		*out = new(KeySelector)
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleCondition.
+func (in *BundleCondition) DeepCopy() *BundleCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleContent) DeepCopyInto(out *BundleContent) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleContent.
+func (in *BundleContent) DeepCopy() *BundleContent {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleContent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleFilters) DeepCopyInto(out *BundleFilters) {
+	*out = *in
+	if in.ExcludeExpiringWithin != nil {
+		in, out := &in.ExcludeExpiringWithin, &out.ExcludeExpiringWithin
+		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.AdditionalFormats != nil {
-		in, out := &in.AdditionalFormats, &out.AdditionalFormats
-		*out = new(AdditionalFormats)
-		(*in).DeepCopyInto(*out)
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]CertificateMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.NamespaceSelector != nil {
-		in, out := &in.NamespaceSelector, &out.NamespaceSelector
-		*out = new(NamespaceSelector)
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]CertificateMatch, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllowFingerprints != nil {
+		in, out := &in.AllowFingerprints, &out.AllowFingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DenyFingerprints != nil {
+		in, out := &in.DenyFingerprints, &out.DenyFingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeyUsages != nil {
+		in, out := &in.KeyUsages, &out.KeyUsages
+		*out = make([]KeyUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtendedKeyUsages != nil {
+		in, out := &in.ExtendedKeyUsages, &out.ExtendedKeyUsages
+		*out = make([]ExtendedKeyUsage, len(*in))
+		copy(*out, *in)
+	}
+	if in.WeakCrypto != nil {
+		in, out := &in.WeakCrypto, &out.WeakCrypto
+		*out = new(WeakCryptoFilter)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleTarget.
-func (in *BundleTarget) DeepCopy() *BundleTarget {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleFilters.
+func (in *BundleFilters) DeepCopy() *BundleFilters {
 	if in == nil {
 		return nil
 	}
-	out := new(BundleTarget)
+	out := new(BundleFilters)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KeySelector) DeepCopyInto(out *KeySelector) {
+func (in *BundleList) DeepCopyInto(out *BundleList) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Bundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySelector.
-func (in *KeySelector) DeepCopy() *KeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleList.
+func (in *BundleList) DeepCopy() *BundleList {
 	if in == nil {
 		return nil
 	}
-	out := new(KeySelector)
+	out := new(BundleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NamespaceSelector) DeepCopyInto(out *NamespaceSelector) {
+func (in *BundleOCIStatus) DeepCopyInto(out *BundleOCIStatus) {
 	*out = *in
-	if in.MatchLabels != nil {
-		in, out := &in.MatchLabels, &out.MatchLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSelector.
-func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleOCIStatus.
+func (in *BundleOCIStatus) DeepCopy() *BundleOCIStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(NamespaceSelector)
+	out := new(BundleOCIStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SourceObjectKeySelector) DeepCopyInto(out *SourceObjectKeySelector) {
+func (in *BundlePermissionCheck) DeepCopyInto(out *BundlePermissionCheck) {
 	*out = *in
-	out.KeySelector = in.KeySelector
+	in.CheckTime.DeepCopyInto(&out.CheckTime)
+	if in.NamespaceClasses != nil {
+		in, out := &in.NamespaceClasses, &out.NamespaceClasses
+		*out = make([]NamespaceClassPermissions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceObjectKeySelector.
-func (in *SourceObjectKeySelector) DeepCopy() *SourceObjectKeySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundlePermissionCheck.
+func (in *BundlePermissionCheck) DeepCopy() *BundlePermissionCheck {
 	if in == nil {
 		return nil
 	}
-	out := new(SourceObjectKeySelector)
+	out := new(BundlePermissionCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleSource) DeepCopyInto(out *BundleSource) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(SourceObjectKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SourceObjectKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSSecret != nil {
+		in, out := &in.TLSSecret, &out.TLSSecret
+		*out = new(SourceObjectSelector)
+		**out = **in
+	}
+	if in.IstioCACertsSecret != nil {
+		in, out := &in.IstioCACertsSecret, &out.IstioCACertsSecret
+		*out = new(SourceObjectSelector)
+		**out = **in
+	}
+	if in.TruststoreSecret != nil {
+		in, out := &in.TruststoreSecret, &out.TruststoreSecret
+		*out = new(SourceTruststoreSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(SourceObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RemoteCluster != nil {
+		in, out := &in.RemoteCluster, &out.RemoteCluster
+		*out = new(SourceRemoteCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InLine != nil {
+		in, out := &in.InLine, &out.InLine
+		*out = new(string)
+		**out = **in
+	}
+	if in.InLineDER != nil {
+		in, out := &in.InLineDER, &out.InLineDER
+		*out = make([][]byte, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make([]byte, len(*in))
+				copy(*out, *in)
+			}
+		}
+	}
+	if in.UseDefaultCAs != nil {
+		in, out := &in.UseDefaultCAs, &out.UseDefaultCAs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseClusterAPIServerCA != nil {
+		in, out := &in.UseClusterAPIServerCA, &out.UseClusterAPIServerCA
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseNodeOSCAs != nil {
+		in, out := &in.UseNodeOSCAs, &out.UseNodeOSCAs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.UseOpenShiftTrustedCA != nil {
+		in, out := &in.UseOpenShiftTrustedCA, &out.UseOpenShiftTrustedCA
+		*out = new(bool)
+		**out = **in
+	}
+	if in.DefaultCAs != nil {
+		in, out := &in.DefaultCAs, &out.DefaultCAs
+		*out = new(DefaultCAsSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExcludeExpired != nil {
+		in, out := &in.ExcludeExpired, &out.ExcludeExpired
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(BundleFilters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Purposes != nil {
+		in, out := &in.Purposes, &out.Purposes
+		*out = make([]TrustPurpose, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleSource.
+func (in *BundleSource) DeepCopy() *BundleSource {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleSpec) DeepCopyInto(out *BundleSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]BundleSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(BundleFilters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.Placement != nil {
+		in, out := &in.Placement, &out.Placement
+		*out = new(PlacementReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleSpec.
+func (in *BundleSpec) DeepCopy() *BundleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleStatus) DeepCopyInto(out *BundleStatus) {
+	*out = *in
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(BundleTarget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]BundleCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DefaultCAPackageVersion != nil {
+		in, out := &in.DefaultCAPackageVersion, &out.DefaultCAPackageVersion
+		*out = new(string)
+		**out = **in
+	}
+	if in.DefaultCAPackages != nil {
+		in, out := &in.DefaultCAPackages, &out.DefaultCAPackages
+		*out = make([]DefaultCAPackageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Content != nil {
+		in, out := &in.Content, &out.Content
+		*out = new(BundleContent)
+		**out = **in
+	}
+	if in.PermissionCheck != nil {
+		in, out := &in.PermissionCheck, &out.PermissionCheck
+		*out = new(BundlePermissionCheck)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedClusters != nil {
+		in, out := &in.ManagedClusters, &out.ManagedClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SourceHealth != nil {
+		in, out := &in.SourceHealth, &out.SourceHealth
+		*out = make([]SourceHealth, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Acknowledgments != nil {
+		in, out := &in.Acknowledgments, &out.Acknowledgments
+		*out = new(BundleAcknowledgments)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(BundleOCIStatus)
+		**out = **in
+	}
+	if in.RetainedKeys != nil {
+		in, out := &in.RetainedKeys, &out.RetainedKeys
+		*out = make([]RetainedTargetKey, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleStatus.
+func (in *BundleStatus) DeepCopy() *BundleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BundleTarget) DeepCopyInto(out *BundleTarget) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(TargetKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalFormats != nil {
+		in, out := &in.AdditionalFormats, &out.AdditionalFormats
+		*out = new(AdditionalFormats)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(NamespaceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceExcludeSelector != nil {
+		in, out := &in.NamespaceExcludeSelector, &out.NamespaceExcludeSelector
+		*out = new(NamespaceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BuildInfo != nil {
+		in, out := &in.BuildInfo, &out.BuildInfo
+		*out = new(BuildInfo)
+		**out = **in
+	}
+	if in.SizeLimit != nil {
+		in, out := &in.SizeLimit, &out.SizeLimit
+		*out = new(TargetSizeLimit)
+		**out = **in
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(TargetOCI)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(TargetObjectStorage)
+		**out = **in
+	}
+	if in.Istio != nil {
+		in, out := &in.Istio, &out.Istio
+		*out = new(TargetIstio)
+		**out = **in
+	}
+	if in.OpenShiftTrustedCA != nil {
+		in, out := &in.OpenShiftTrustedCA, &out.OpenShiftTrustedCA
+		*out = new(TargetOpenShiftTrustedCA)
+		**out = **in
+	}
+	if in.Immutable != nil {
+		in, out := &in.Immutable, &out.Immutable
+		*out = new(TargetImmutable)
+		**out = **in
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(TargetMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BundleTarget.
+func (in *BundleTarget) DeepCopy() *BundleTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(BundleTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateMatch) DeepCopyInto(out *CertificateMatch) {
+	*out = *in
+	if in.Subject != nil {
+		in, out := &in.Subject, &out.Subject
+		*out = new(DistinguishedNameMatch)
+		**out = **in
+	}
+	if in.Issuer != nil {
+		in, out := &in.Issuer, &out.Issuer
+		*out = new(DistinguishedNameMatch)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateMatch.
+func (in *CertificateMatch) DeepCopy() *CertificateMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCAPackageStatus) DeepCopyInto(out *DefaultCAPackageStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCAPackageStatus.
+func (in *DefaultCAPackageStatus) DeepCopy() *DefaultCAPackageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCAPackageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCAsExclusions) DeepCopyInto(out *DefaultCAsExclusions) {
+	*out = *in
+	if in.CommonNames != nil {
+		in, out := &in.CommonNames, &out.CommonNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Fingerprints != nil {
+		in, out := &in.Fingerprints, &out.Fingerprints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCAsExclusions.
+func (in *DefaultCAsExclusions) DeepCopy() *DefaultCAsExclusions {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCAsExclusions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultCAsSource) DeepCopyInto(out *DefaultCAsSource) {
+	*out = *in
+	if in.Fallback != nil {
+		in, out := &in.Fallback, &out.Fallback
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = new(DefaultCAsExclusions)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultCAsSource.
+func (in *DefaultCAsSource) DeepCopy() *DefaultCAsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultCAsSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DistinguishedNameMatch) DeepCopyInto(out *DistinguishedNameMatch) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DistinguishedNameMatch.
+func (in *DistinguishedNameMatch) DeepCopy() *DistinguishedNameMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(DistinguishedNameMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Gzip) DeepCopyInto(out *Gzip) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Gzip.
+func (in *Gzip) DeepCopy() *Gzip {
+	if in == nil {
+		return nil
+	}
+	out := new(Gzip)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HashedDirectory) DeepCopyInto(out *HashedDirectory) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HashedDirectory.
+func (in *HashedDirectory) DeepCopy() *HashedDirectory {
+	if in == nil {
+		return nil
+	}
+	out := new(HashedDirectory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JKS) DeepCopyInto(out *JKS) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordFrom != nil {
+		in, out := &in.PasswordFrom, &out.PasswordFrom
+		*out = new(PasswordSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JKS.
+func (in *JKS) DeepCopy() *JKS {
+	if in == nil {
+		return nil
+	}
+	out := new(JKS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeySelector) DeepCopyInto(out *KeySelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeySelector.
+func (in *KeySelector) DeepCopy() *KeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(KeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metadata.
+func (in *Metadata) DeepCopy() *Metadata {
+	if in == nil {
+		return nil
+	}
+	out := new(Metadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceClassPermissions) DeepCopyInto(out *NamespaceClassPermissions) {
+	*out = *in
+	if in.Denied != nil {
+		in, out := &in.Denied, &out.Denied
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceClassPermissions.
+func (in *NamespaceClassPermissions) DeepCopy() *NamespaceClassPermissions {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceClassPermissions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NamespaceSelector) DeepCopyInto(out *NamespaceSelector) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NamespaceSelector.
+func (in *NamespaceSelector) DeepCopy() *NamespaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(NamespaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PEMDirectory) DeepCopyInto(out *PEMDirectory) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PEMDirectory.
+func (in *PEMDirectory) DeepCopy() *PEMDirectory {
+	if in == nil {
+		return nil
+	}
+	out := new(PEMDirectory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PKCS12) DeepCopyInto(out *PKCS12) {
+	*out = *in
+	out.KeySelector = in.KeySelector
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(string)
+		**out = **in
+	}
+	if in.PasswordFrom != nil {
+		in, out := &in.PasswordFrom, &out.PasswordFrom
+		*out = new(PasswordSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PKCS12.
+func (in *PKCS12) DeepCopy() *PKCS12 {
+	if in == nil {
+		return nil
+	}
+	out := new(PKCS12)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordProviderSelector) DeepCopyInto(out *PasswordProviderSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordProviderSelector.
+func (in *PasswordProviderSelector) DeepCopy() *PasswordProviderSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordProviderSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordSource) DeepCopyInto(out *PasswordSource) {
+	*out = *in
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SourceObjectKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Provider != nil {
+		in, out := &in.Provider, &out.Provider
+		*out = new(PasswordProviderSelector)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordSource.
+func (in *PasswordSource) DeepCopy() *PasswordSource {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PlacementReference) DeepCopyInto(out *PlacementReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlacementReference.
+func (in *PlacementReference) DeepCopy() *PlacementReference {
+	if in == nil {
+		return nil
+	}
+	out := new(PlacementReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetainedTargetKey) DeepCopyInto(out *RetainedTargetKey) {
+	*out = *in
+	in.Until.DeepCopyInto(&out.Until)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetainedTargetKey.
+func (in *RetainedTargetKey) DeepCopy() *RetainedTargetKey {
+	if in == nil {
+		return nil
+	}
+	out := new(RetainedTargetKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceHealth) DeepCopyInto(out *SourceHealth) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceHealth.
+func (in *SourceHealth) DeepCopy() *SourceHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceObjectKeySelector) DeepCopyInto(out *SourceObjectKeySelector) {
+	*out = *in
+	if in.ExcludeKeys != nil {
+		in, out := &in.ExcludeKeys, &out.ExcludeKeys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceObjectKeySelector.
+func (in *SourceObjectKeySelector) DeepCopy() *SourceObjectKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceObjectKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceObjectSelector) DeepCopyInto(out *SourceObjectSelector) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceObjectSelector.
+func (in *SourceObjectSelector) DeepCopy() *SourceObjectSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceObjectSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceObjectStorage) DeepCopyInto(out *SourceObjectStorage) {
+	*out = *in
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceObjectStorage.
+func (in *SourceObjectStorage) DeepCopy() *SourceObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceRemoteCluster) DeepCopyInto(out *SourceRemoteCluster) {
+	*out = *in
+	in.KubeconfigSecret.DeepCopyInto(&out.KubeconfigSecret)
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(SourceObjectKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(SourceObjectKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceRemoteCluster.
+func (in *SourceRemoteCluster) DeepCopy() *SourceRemoteCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceRemoteCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceTruststoreSelector) DeepCopyInto(out *SourceTruststoreSelector) {
+	*out = *in
+	in.SourceObjectKeySelector.DeepCopyInto(&out.SourceObjectKeySelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceTruststoreSelector.
+func (in *SourceTruststoreSelector) DeepCopy() *SourceTruststoreSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceTruststoreSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetImmutable) DeepCopyInto(out *TargetImmutable) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetImmutable.
+func (in *TargetImmutable) DeepCopy() *TargetImmutable {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetImmutable)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetIstio) DeepCopyInto(out *TargetIstio) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetIstio.
+func (in *TargetIstio) DeepCopy() *TargetIstio {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetIstio)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetKeySelector) DeepCopyInto(out *TargetKeySelector) {
+	*out = *in
+	if in.PreviousKeyRetention != nil {
+		in, out := &in.PreviousKeyRetention, &out.PreviousKeyRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetKeySelector.
+func (in *TargetKeySelector) DeepCopy() *TargetKeySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetKeySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetMetadata) DeepCopyInto(out *TargetMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PropagateLabels != nil {
+		in, out := &in.PropagateLabels, &out.PropagateLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetMetadata.
+func (in *TargetMetadata) DeepCopy() *TargetMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetOCI) DeepCopyInto(out *TargetOCI) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetOCI.
+func (in *TargetOCI) DeepCopy() *TargetOCI {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetOCI)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetObjectStorage) DeepCopyInto(out *TargetObjectStorage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetObjectStorage.
+func (in *TargetObjectStorage) DeepCopy() *TargetObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetOpenShiftTrustedCA) DeepCopyInto(out *TargetOpenShiftTrustedCA) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetOpenShiftTrustedCA.
+func (in *TargetOpenShiftTrustedCA) DeepCopy() *TargetOpenShiftTrustedCA {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetOpenShiftTrustedCA)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetSizeLimit) DeepCopyInto(out *TargetSizeLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSizeLimit.
+func (in *TargetSizeLimit) DeepCopy() *TargetSizeLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetSizeLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustProfile) DeepCopyInto(out *TrustProfile) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustProfile.
+func (in *TrustProfile) DeepCopy() *TrustProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WeakCryptoFilter) DeepCopyInto(out *WeakCryptoFilter) {
+	*out = *in
+	if in.WeakSignatureAlgorithms != nil {
+		in, out := &in.WeakSignatureAlgorithms, &out.WeakSignatureAlgorithms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WeakCryptoFilter.
+func (in *WeakCryptoFilter) DeepCopy() *WeakCryptoFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(WeakCryptoFilter)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"sort"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// maxPendingAcknowledgments is the maximum number of Namespaces listed in the
+// pending Namespaces of a Bundle's acknowledgments, bounding the size of the
+// Bundle status for Bundles with very many targets.
+const maxPendingAcknowledgments = 10
+
+// newBundleAcknowledgments aggregates the acknowledgments of the given hash by
+// the consumers of a Bundle's targets. pending are the Namespaces of targets
+// whose consumers have not acknowledged the hash.
+func newBundleAcknowledgments(hash string, targets int, pending []string) *trustapi.BundleAcknowledgments {
+	acknowledgments := &trustapi.BundleAcknowledgments{
+		Hash:         hash,
+		Targets:      int32(targets),
+		Acknowledged: int32(targets - len(pending)),
+	}
+
+	if len(pending) > 0 {
+		sorted := append([]string(nil), pending...)
+		sort.Strings(sorted)
+		if len(sorted) > maxPendingAcknowledgments {
+			sorted = sorted[:maxPendingAcknowledgments]
+		}
+		acknowledgments.PendingNamespaces = sorted
+	}
+
+	return acknowledgments
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_newBundleAcknowledgments(t *testing.T) {
+	tests := map[string]struct {
+		targets int
+		pending []string
+
+		expAcknowledgments *trustapi.BundleAcknowledgments
+	}{
+		"all targets acknowledged should have no pending Namespaces": {
+			targets:            3,
+			expAcknowledgments: &trustapi.BundleAcknowledgments{Hash: "hash", Targets: 3, Acknowledged: 3},
+		},
+		"pending Namespaces should be sorted": {
+			targets: 3,
+			pending: []string{"ns-b", "ns-a"},
+			expAcknowledgments: &trustapi.BundleAcknowledgments{
+				Hash: "hash", Targets: 3, Acknowledged: 1,
+				PendingNamespaces: []string{"ns-a", "ns-b"},
+			},
+		},
+		"pending Namespaces should be truncated": {
+			targets: 12,
+			pending: []string{"ns-l", "ns-k", "ns-j", "ns-i", "ns-h", "ns-g", "ns-f", "ns-e", "ns-d", "ns-c", "ns-b", "ns-a"},
+			expAcknowledgments: &trustapi.BundleAcknowledgments{
+				Hash: "hash", Targets: 12, Acknowledged: 0,
+				PendingNamespaces: []string{"ns-a", "ns-b", "ns-c", "ns-d", "ns-e", "ns-f", "ns-g", "ns-h", "ns-i", "ns-j"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expAcknowledgments, newBundleAcknowledgments("hash", test.targets, test.pending))
+		})
+	}
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// appliedTargetData returns the data of any target of the given Bundle which
+// is controlled by the Bundle and whose data has the given content hash.
+// Returns false if no such target was found.
+func (b *bundle) appliedTargetData(ctx context.Context,
+	bundle *trustapi.Bundle,
+	namespaces []corev1.Namespace,
+	hash string,
+) (string, bool, error) {
+	if bundle.Spec.Target.ConfigMap == nil {
+		return "", false, nil
+	}
+
+	targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, namespace := range namespaces {
+		var configMap corev1.ConfigMap
+		err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: targetName}, &configMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace.Name, targetName, err)
+		}
+
+		if !metav1.IsControlledBy(&configMap, bundle) {
+			continue
+		}
+
+		applied, ok, err := b.targetData(ctx, bundle, &namespace, &configMap)
+		if err != nil {
+			return "", false, err
+		}
+		if ok && contentHash(applied) == hash {
+			return applied, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// targetData returns the bundle data written to the given target of the
+// Bundle in the given Namespace, decoded from whichever format it was
+// written in: partitioned across the target and its partition ConfigMaps, in
+// the DER format, or only in the gzip format. Returns false if the target
+// doesn't contain the complete bundle data.
+func (b *bundle) targetData(ctx context.Context, bundle *trustapi.Bundle, namespace *corev1.Namespace, configMap *corev1.ConfigMap) (string, bool, error) {
+	target := bundle.Spec.Target
+	key := namespaceTargetKey(namespace, target)
+
+	if index, ok := configMap.Data[partitionIndexKey(target)]; ok {
+		return b.partitionedTargetData(ctx, bundle, configMap, index)
+	}
+
+	if data, ok := util.TargetData(configMap, key, target.ConfigMap.Format); ok {
+		return data, true, nil
+	}
+
+	// Targets which omit the uncompressed bundle data only hold it in the
+	// gzip format.
+	if gzipTarget := gzipFormat(target); gzipTarget != nil {
+		if compressed, ok := configMap.BinaryData[gzipTarget.Key]; ok {
+			data, err := decodeGzip(compressed)
+			if err != nil {
+				return "", false, nil
+			}
+			return data, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// partitionedTargetData returns the bundle data partitioned across the given
+// target and the partition ConfigMaps listed in its partition index. Returns
+// false if any partition is missing or not controlled by the Bundle.
+func (b *bundle) partitionedTargetData(ctx context.Context, bundle *trustapi.Bundle, configMap *corev1.ConfigMap, index string) (string, bool, error) {
+	partitions := parsePartitionIndex(index)
+	if len(partitions) == 0 {
+		return "", false, nil
+	}
+
+	var data strings.Builder
+	for _, partition := range partitions {
+		name, key := partition[0], partition[1]
+
+		partitionConfigMap := configMap
+		if name != configMap.Name {
+			partitionConfigMap = new(corev1.ConfigMap)
+			err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: configMap.Namespace, Name: name}, partitionConfigMap)
+			if apierrors.IsNotFound(err) {
+				return "", false, nil
+			}
+			if err != nil {
+				return "", false, fmt.Errorf("failed to get partition configmap %s/%s: %w", configMap.Namespace, name, err)
+			}
+			if !metav1.IsControlledBy(partitionConfigMap, bundle) {
+				return "", false, nil
+			}
+		}
+
+		partitionData, ok := partitionConfigMap.Data[key]
+		if !ok {
+			return "", false, nil
+		}
+		data.WriteString(partitionData)
+	}
+
+	return data.String(), true, nil
+}
@@ -20,6 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -34,6 +38,8 @@ import (
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/targethash"
 )
 
 // Options hold options for the Bundle controller.
@@ -49,6 +55,88 @@ type Options struct {
 	// loaded in order for the controller to start. If unset, referring to the default
 	// certificate package in a `Bundle` resource will cause that Bundle to error.
 	DefaultPackageLocation string
+
+	// NamedDefaultPackageLocations are the locations on the filesystem from
+	// which additional default certificate packages should be loaded. Each
+	// package must be successfully loaded in order for the controller to
+	// start, and can be referred to in a `Bundle` resource by the name given
+	// in the package.
+	NamedDefaultPackageLocations []string
+
+	// SourceResyncPeriod is the period at which the informers watching source
+	// resources in the trust Namespace are resynced. Setting to zero disables
+	// periodic resyncs, relying entirely on watch events and bookmarks to keep
+	// the source cache up to date.
+	SourceResyncPeriod time.Duration
+
+	// PasswordProviders are the password providers which Bundles may reference
+	// by name to source the passwords of binary truststore targets.
+	PasswordProviders map[string]PasswordProvider
+
+	// ExternalSourceRefreshPeriod is the period at which Bundles with sources
+	// outside of the cluster's trust Namespace, such as object storage and
+	// remote cluster sources, are re-reconciled, so that changes to the
+	// referenced objects are picked up.
+	ExternalSourceRefreshPeriod time.Duration
+
+	// SourceHealthProbePeriod is the period at which the availability of
+	// object storage and remote cluster sources is probed, independently of
+	// their refresh, and exposed in the Bundle status and metrics. Setting to
+	// zero disables probing.
+	SourceHealthProbePeriod time.Duration
+
+	// SyncFailureDetailLimit is the maximum number of failing Bundle and
+	// Namespace pairs exposed by the sync failure detail metric.
+	SyncFailureDetailLimit int
+
+	// EnableClusterPlacement enables distributing Bundles to the managed
+	// clusters selected by their Open Cluster Management placement. Requires
+	// the Open Cluster Management PlacementDecision and ManifestWork APIs to be
+	// installed.
+	EnableClusterPlacement bool
+
+	// TargetWriteBudget is the maximum number of targets written when
+	// reconciling a Bundle. If more targets need to be written, the rollout of
+	// the Bundle is continued in a later reconcile, so that a single Bundle
+	// can't monopolise the controller's client. The budget is adjusted by the
+	// Bundle's priority class. Zero disables the budget.
+	TargetWriteBudget int
+
+	// DefaultWeakCryptoAction is the action taken for certificates with weak
+	// keys or signatures in Bundles which don't set the action of their
+	// weakCrypto filter. Defaults to Ignore.
+	DefaultWeakCryptoAction trustapi.WeakCryptoAction
+
+	// StatusContentMaxBytes is the maximum size of bundle data which is
+	// included in the content field of the Bundle status. The hash and size
+	// of larger bundle data are still included. Zero omits the data of all
+	// Bundles.
+	StatusContentMaxBytes int
+
+	// Naming are the conventions for the names, labels and annotations of
+	// the objects created by the controller. If nil, the defaults are used.
+	Naming *naming.Conventions
+
+	// DistributionAddress is the address the HTTP(S) endpoint serving each
+	// Bundle's data to consumers outside of the cluster listens on. Bundles
+	// are served from their target in the trust Namespace. Empty disables the
+	// endpoint.
+	DistributionAddress string
+
+	// DistributionCertFile and DistributionKeyFile are the paths of the TLS
+	// certificate and private key of the distribution endpoint. If unset,
+	// the endpoint serves plain HTTP.
+	DistributionCertFile, DistributionKeyFile string
+
+	// FIPS restricts the encodings of targets to FIPS-approved algorithms.
+	// Bundles with additional formats which can't be encoded with approved
+	// algorithms, such as JKS or the legacy PKCS#12 profiles, fail to sync.
+	FIPS bool
+
+	// PropagatedBundleLabels are the keys of the labels of Bundles which are
+	// applied to the target ConfigMaps of every Bundle, in addition to those
+	// propagated by the Bundle itself.
+	PropagatedBundleLabels []string
 }
 
 // bundle is a controller-runtime controller. Implements the actual controller
@@ -67,12 +155,49 @@ type bundle struct {
 	// at startup.
 	defaultPackage *fspkg.Package
 
+	// namedDefaultPackages holds the loaded default certificate packages which
+	// can be referred to by name, keyed by package name.
+	namedDefaultPackages map[string]*fspkg.Package
+
 	// recorder is used for create Kubernetes Events for reconciled Bundles.
 	recorder record.EventRecorder
 
 	// clock returns time which can be overwritten for testing.
 	clock clock.Clock
 
+	// objectCache caches objects fetched from blob stores by object storage
+	// sources.
+	objectCache objectStorageCache
+
+	// objectStorageClient is the HTTP client used to fetch objects from blob
+	// stores and to push artifacts to OCI registries. If nil,
+	// http.DefaultClient is used.
+	objectStorageClient *http.Client
+
+	// remoteClients caches clients for the clusters of remote cluster
+	// sources.
+	remoteClients remoteClusterClients
+
+	// newRemoteClient builds clients for the clusters of remote cluster
+	// sources. If nil, clients are built from the kubeconfig directly.
+	newRemoteClient remoteClientFunc
+
+	// metrics holds the metrics of the controller. If nil, no metrics are
+	// recorded.
+	metrics *metrics
+
+	// reviewAccess reviews the access of the controller when checking
+	// permissions. If nil, a SelfSubjectAccessReview is used.
+	reviewAccess accessReviewFunc
+
+	// rollouts holds the continuation state of rollouts interrupted by the
+	// target write budget.
+	rollouts rollouts
+
+	// sourceHealth holds the result of the last probe of the external sources
+	// of each Bundle.
+	sourceHealth sourceHealthStore
+
 	// Options holds options for the Bundle controller.
 	Options
 }
@@ -88,6 +213,11 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 	err := b.sourceLister.Get(ctx, req.NamespacedName, &bundle)
 	if apierrors.IsNotFound(err) {
 		log.V(2).Info("bundle no longer exists, ignoring")
+		b.metrics.syncSucceeded(req.NamespacedName.Name)
+		b.rollouts.delete(req.NamespacedName.Name)
+		b.sourceHealth.set(req.NamespacedName.Name, nil)
+		b.objectCache.setReferences(req.NamespacedName.Name, nil)
+		b.metrics.sourceHealthDeleted(req.NamespacedName.Name)
 		return ctrl.Result{}, nil
 	}
 
@@ -96,13 +226,20 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, fmt.Errorf("failed to get %q: %s", req.NamespacedName, err)
 	}
 
-	namespaceSelector := labels.Everything()
-	if nsSelector := bundle.Spec.Target.NamespaceSelector; nsSelector != nil && nsSelector.MatchLabels != nil {
-		namespaceSelector, err = metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSelector.MatchLabels})
-		if err != nil {
-			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "NamespaceSelectorError", "Failed to build namespace match labels selector: %s", err)
-			return ctrl.Result{}, fmt.Errorf("failed to build NamespaceSelector: %w", err)
-		}
+	// Bundles with a target deletion policy clean up their targets before
+	// they are deleted.
+	if done, result, err := b.reconcileDeletionPolicy(ctx, log, &bundle); done {
+		return result, err
+	}
+
+	// Expose the result of the last probe of the Bundle's external sources
+	// with any status update made below.
+	sourceHealthChanged := b.setBundleStatusSourceHealth(&bundle)
+
+	namespaceSelector, err := targetNamespaceSelector(bundle.Spec.Target, b.Namespace)
+	if err != nil {
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "NamespaceSelectorError", "Failed to build namespace match labels selector: %s", err)
+		return ctrl.Result{}, fmt.Errorf("failed to build NamespaceSelector: %w", err)
 	}
 
 	var namespaceList corev1.NamespaceList
@@ -112,15 +249,86 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		return ctrl.Result{}, fmt.Errorf("failed to list Namespaces: %w", err)
 	}
 
-	// If the target has changed on the Spec, delete the old targets first.
-	if bundle.Status.Target != nil && !apiequality.Semantic.DeepEqual(*bundle.Status.Target, bundle.Spec.Target) {
+	// If a permission check has been requested, perform it and record the
+	// result before syncing.
+	if request, ok := needsPermissionCheck(&bundle); ok {
+		check, err := b.checkPermissions(ctx, request, namespaceSelector, namespaceList.Items)
+		if err != nil {
+			log.Error(err, "failed to check permissions")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PermissionCheckError", "Failed to check permissions: %s", err)
+			return ctrl.Result{}, fmt.Errorf("failed to check permissions: %w", err)
+		}
+
+		if check.Allowed {
+			b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "PermissionCheckPassed", "Controller has all permissions needed to sync Bundle")
+		} else {
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PermissionCheckFailed", "Controller is missing permissions needed to sync Bundle, see status.permissionCheck")
+		}
+
+		// Return with update here, so targets are synced on the next Reconcile.
+		bundle.Status.PermissionCheck = check
+		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	// If the keys of the target have changed on the Spec, delete the old
+	// targets first.
+	if bundle.Status.Target != nil && targetKeysChanged(*bundle.Status.Target, bundle.Spec.Target) {
 		log.Info("deleting old targets", "old_target", bundle.Status.Target)
 		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "DeleteOldTarget", "Deleting old targets as Bundle target has been modified")
 
+		targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		oldTargetName, err := b.Naming.BundleTargetName(bundle.Name, *bundle.Status.Target)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// The bundle data is kept in the previous key of a target which
+		// wasn't renamed if the Bundle retains previous keys, so that
+		// consumers can migrate to the new key.
+		var retainPrevious bool
+		if oldTargetName == targetName {
+			retainPrevious = retainPreviousKey(&bundle, *bundle.Status.Target, b.clock.Now())
+		}
+
+		// The OpenShift trusted CA ConfigMap is deleted once the Bundle no
+		// longer writes it, or writes it under another name.
+		if old := bundle.Status.Target.OpenShiftTrustedCA; old != nil {
+			if current := bundle.Spec.Target.OpenShiftTrustedCA; current == nil || openShiftTrustedCAName(old) != openShiftTrustedCAName(current) {
+				if err := b.deleteOpenShiftTrustedCATarget(ctx, &bundle, old); err != nil {
+					log.Error(err, "failed to delete old OpenShift trusted CA ConfigMap")
+					b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old OpenShift trusted CA ConfigMap: %s", err)
+					return ctrl.Result{}, fmt.Errorf("failed to delete old OpenShift trusted CA ConfigMap: %w", err)
+				}
+			}
+		}
+
 		for _, namespace := range namespaceList.Items {
+			// Istio root certificate ConfigMaps are deleted once the Bundle
+			// no longer writes them.
+			if bundle.Status.Target.Istio != nil && bundle.Spec.Target.Istio == nil {
+				if _, err := b.deleteIstioTarget(ctx, &bundle, namespace.Name); err != nil {
+					log.Error(err, "failed to delete old Istio root certificate ConfigMap")
+					b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old Istio root certificate ConfigMap: %s", err)
+					return ctrl.Result{}, fmt.Errorf("failed to delete old Istio root certificate ConfigMap: %w", err)
+				}
+			}
+
+			// Immutable versions of the old target are deleted once the Bundle
+			// no longer writes them, or writes them for another target.
+			if bundle.Status.Target.Immutable != nil && (bundle.Spec.Target.Immutable == nil || oldTargetName != targetName) {
+				if _, err := b.pruneImmutableVersions(ctx, &bundle, namespace.Name, oldTargetName, "", 0); err != nil {
+					log.Error(err, "failed to delete old immutable target versions")
+					b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old immutable target versions: %s", err)
+					return ctrl.Result{}, fmt.Errorf("failed to delete old immutable target versions: %w", err)
+				}
+			}
+
 			configMap := &corev1.ConfigMap{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      bundle.Name,
+					Name:      oldTargetName,
 					Namespace: namespace.Name,
 				},
 			}
@@ -139,10 +347,68 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 				return ctrl.Result{}, fmt.Errorf("failed to get target ConfigMap: %w", err)
 			}
 
-			delete(configMap.Data, bundle.Status.Target.ConfigMap.Key)
+			// If the target has been renamed, the old target is deleted
+			// entirely, unless it isn't owned by the Bundle.
+			if oldTargetName != targetName && metav1.IsControlledBy(configMap, &bundle) {
+				if err := b.targetDirectClient.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+					log.Error(err, "failed to delete old ConfigMap target")
+					b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetDeleteError", "Failed to delete old ConfigMap target: %s", err)
+					return ctrl.Result{}, fmt.Errorf("failed to delete old ConfigMap target: %w", err)
+				}
+
+				log.V(2).Info("deleted old target", "old_target", bundle.Status.Target, "namespace", namespace.Name)
+				continue
+			}
+
+			if !retainPrevious {
+				delete(configMap.Data, bundle.Status.Target.ConfigMap.Key)
+			}
+			if bundle.Status.Target.ConfigMap.Format == trustapi.TargetFormatDER {
+				delete(configMap.BinaryData, bundle.Status.Target.ConfigMap.Key)
+			}
 			if bundle.Status.Target.AdditionalFormats != nil && bundle.Status.Target.AdditionalFormats.JKS != nil {
 				delete(configMap.BinaryData, bundle.Status.Target.AdditionalFormats.JKS.Key)
 			}
+			if bundle.Status.Target.AdditionalFormats != nil && bundle.Status.Target.AdditionalFormats.PKCS12 != nil {
+				delete(configMap.BinaryData, bundle.Status.Target.AdditionalFormats.PKCS12.Key)
+			}
+			if pkcs7Key, ok := pkcs7Key(*bundle.Status.Target); ok {
+				delete(configMap.BinaryData, pkcs7Key)
+			}
+			if sstKey, ok := sstKey(*bundle.Status.Target); ok {
+				delete(configMap.BinaryData, sstKey)
+			}
+			if gzipTarget := gzipFormat(*bundle.Status.Target); gzipTarget != nil {
+				delete(configMap.BinaryData, gzipTarget.Key)
+			}
+			if timestampKey, ok := buildTimestampKey(*bundle.Status.Target); ok {
+				delete(configMap.Data, timestampKey)
+			}
+			if metadataKey, ok := metadataKey(*bundle.Status.Target); ok {
+				delete(configMap.Data, metadataKey)
+			}
+			if spiffeKey, ok := spiffeBundleKey(*bundle.Status.Target); ok {
+				delete(configMap.Data, spiffeKey)
+			}
+			if provenanceKey, ok := provenanceKey(*bundle.Status.Target); ok {
+				delete(configMap.Data, provenanceKey)
+			}
+			if _, indexKey, ok := pemDirectoryKeys(*bundle.Status.Target); ok {
+				syncPEMDirectory(configMap, indexKey, nil)
+			}
+			if packaging, indexKey, tarballKey, ok := hashedDirectoryFormat(*bundle.Status.Target); ok {
+				if packaging == trustapi.HashedDirectoryPackagingTarball {
+					delete(configMap.BinaryData, tarballKey)
+				} else {
+					syncPEMDirectory(configMap, indexKey, nil)
+				}
+			}
+			for _, profile := range targetProfiles(*bundle.Status.Target) {
+				delete(configMap.Data, profile.Key)
+			}
+			if bundle.Spec.Target.Immutable == nil {
+				delete(configMap.Annotations, trustapi.TargetCurrentVersionAnnotationKey)
+			}
 
 			if err := b.targetDirectClient.Update(ctx, configMap); err != nil {
 				log.Error(err, "failed to delete old ConfigMap target key")
@@ -160,6 +426,22 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 
 	resolvedBundle, err := b.buildSourceBundle(ctx, &bundle)
 
+	// If a source can't be read or parsed, keep syncing the content which was
+	// last successfully synced, so that the temporary loss of a source
+	// doesn't disrupt trust distribution.
+	var sourceErr error
+	if err != nil && !errors.As(err, &privateKeyError{}) {
+		lastKnownGood, ok, lookupErr := b.lastKnownGoodData(ctx, &bundle, namespaceList.Items)
+		if lookupErr != nil {
+			log.Error(lookupErr, "failed to look up last known good bundle content")
+		}
+		if ok {
+			log.Error(err, "failed to build source bundle, syncing last known good content")
+			sourceErr = err
+			resolvedBundle, err = bundleData{data: lastKnownGood}, nil
+		}
+	}
+
 	// If any source is not found, update the Bundle status to an unready state.
 	if errors.As(err, &notFoundError{}) {
 		log.Error(err, "bundle source was not found")
@@ -171,17 +453,329 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		})
 
 		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceNotFound", "Bundle source was not found: %s", err)
-		return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+		b.metrics.syncFailed(bundle.Name, "", "SourceNotFound")
+		return b.externalSourceRefresh(&bundle, ctrl.Result{}), b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	// If any source contains a private key, refuse to sync the Bundle so that
+	// the key isn't distributed to the targets.
+	if errors.As(err, &privateKeyError{}) {
+		message := fmt.Sprintf("Refusing to sync Bundle since %s; set stripPrivateKeys to remove private keys from the sources", err)
+		log.Error(err, "bundle source contains a private key")
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionPrivateKeyDetected,
+			Status:  corev1.ConditionTrue,
+			Reason:  "SyncRefused",
+			Message: message,
+		})
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "PrivateKeyDetected",
+			Message: message,
+		})
+
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PrivateKeyDetected", message)
+		b.metrics.syncFailed(bundle.Name, "", "PrivateKeyDetected")
+		return b.externalSourceRefresh(&bundle, ctrl.Result{}), b.targetDirectClient.Status().Update(ctx, &bundle)
 	}
 
 	if err != nil {
 		log.Error(err, "failed to build source bundle")
 		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceBuildError", "Failed to build bundle sources: %s", err)
+		b.metrics.syncFailed(bundle.Name, "", "SourceBuildError")
 		return ctrl.Result{}, fmt.Errorf("failed to build bundle source: %w", err)
 	}
 
-	var needsUpdate bool
-	for _, namespace := range namespaceList.Items {
+	var collisionConditionChanged bool
+
+	// On every sync, check that no target already exists without being owned
+	// by this Bundle, so that all collisions are surfaced at once rather than
+	// clobbering objects managed by something else. Targets may collide after
+	// the first sync, such as in a Namespace created with a ConfigMap of the
+	// target's name.
+	collisions, err := b.targetCollisions(ctx, &bundle, namespaceSelector, namespaceList.Items)
+	if err != nil {
+		log.Error(err, "failed to check for target collisions")
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetGetError", "Failed to check for target collisions: %s", err)
+		return ctrl.Result{}, fmt.Errorf("failed to check for target collisions: %w", err)
+	}
+
+	targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// With the Adopt and Overwrite conflict policies, the conflicting
+	// targets are taken over when they are synced.
+	if policy := bundle.Spec.Target.ConflictPolicy; len(collisions) > 0 && (policy == trustapi.TargetConflictPolicyAdopt || policy == trustapi.TargetConflictPolicyOverwrite) {
+		reason, verb := "TargetAdopted", "adopted"
+		if policy == trustapi.TargetConflictPolicyOverwrite {
+			reason, verb = "TargetOverwritten", "overwritten"
+		}
+
+		message := fmt.Sprintf("Target ConfigMap %q already existed without being owned by the Bundle and was %s in namespaces: %s", targetName, verb, strings.Join(collisions, ", "))
+		log.Info("taking over existing targets", "policy", policy, "namespaces", collisions)
+
+		condition := trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionCollisionDetected,
+			Status:  corev1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		}
+		if !bundleHasCondition(&bundle, condition) {
+			b.setBundleCondition(&bundle, condition)
+			collisionConditionChanged = true
+		}
+	} else if len(collisions) > 0 {
+		message := fmt.Sprintf("Target ConfigMap %q already exists and is not owned by the Bundle in namespaces: %s", targetName, strings.Join(collisions, ", "))
+		log.Info("target collision detected", "namespaces", collisions)
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "CollisionDetected", message)
+		for _, namespace := range collisions {
+			b.metrics.syncFailed(bundle.Name, namespace, "CollisionDetected")
+		}
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionCollisionDetected,
+			Status:  corev1.ConditionTrue,
+			Reason:  "TargetNotOwned",
+			Message: message,
+		})
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "CollisionDetected",
+			Message: message,
+		})
+
+		return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	} else if bundleHasConditionType(&bundle, trustapi.BundleConditionCollisionDetected) {
+		condition := trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionCollisionDetected,
+			Status:  corev1.ConditionFalse,
+			Reason:  "NoCollision",
+			Message: "No target collisions detected",
+		}
+		if !bundleHasCondition(&bundle, condition) {
+			b.setBundleCondition(&bundle, condition)
+			collisionConditionChanged = true
+		}
+	}
+
+	// Outside of maintenance windows, content changes are deferred and targets
+	// are synced with the previously applied content.
+	data := resolvedBundle.data
+	var deferredUntil *time.Time
+	if len(bundle.Spec.MaintenanceWindows) > 0 {
+		data, deferredUntil, err = b.maintenanceWindowData(ctx, log, &bundle, namespaceList.Items, resolvedBundle.data)
+		if err != nil {
+			log.Error(err, "failed to evaluate maintenance windows")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "MaintenanceWindowError", "Failed to evaluate maintenance windows: %s", err)
+			return ctrl.Result{}, fmt.Errorf("failed to evaluate maintenance windows: %w", err)
+		}
+
+		// If the previously applied content is unknown, the targets can't be
+		// synced without applying the deferred change.
+		if deferredUntil != nil && len(data) == 0 {
+			condition := trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "AppliedContentUnknown",
+				Message: fmt.Sprintf("Content change is deferred until the next maintenance window opens at %s, and targets are not synced since the previously applied content was not found in any target", deferredUntil.UTC().Format(time.RFC3339)),
+			}
+
+			result := ctrl.Result{RequeueAfter: deferredUntil.Sub(b.clock.Now())}
+			if bundleHasCondition(&bundle, condition) {
+				return result, nil
+			}
+
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "AppliedContentUnknown", condition.Message)
+			b.setBundleCondition(&bundle, condition)
+			return result, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+	}
+
+	// Check the size of the bundle data before writing it to the targets,
+	// rather than failing to write oversized ConfigMaps.
+	maxBytes, maxCertificates, sizeLimitPolicy := targetSizeLimit(bundle.Spec.Target)
+	var compressed bool
+	if gzipTarget := gzipFormat(bundle.Spec.Target); gzipTarget != nil {
+		compressed = gzipTarget.OmitUncompressed
+	}
+	data, truncatedCertificates, sizeLimitExceeded, err := checkSizeLimit(data, compressed, maxBytes, maxCertificates, sizeLimitPolicy == trustapi.TargetSizeLimitPolicyTruncate)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to check bundle size limit: %w", err)
+	}
+
+	if len(sizeLimitExceeded) > 0 && (sizeLimitPolicy == trustapi.TargetSizeLimitPolicyFail || len(data) == 0) {
+		message := fmt.Sprintf("Refusing to sync Bundle since the %s", sizeLimitExceeded)
+		log.Info("bundle size limit exceeded", "reason", sizeLimitExceeded)
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SizeLimitExceeded", message)
+		b.metrics.syncFailed(bundle.Name, "", "SizeLimitExceeded")
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SizeLimitExceeded",
+			Message: message,
+		})
+
+		return b.externalSourceRefresh(&bundle, ctrl.Result{}), b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	// Oversized bundle data of Bundles with the Partition policy is split into
+	// partitions within the limits, rather than failing to sync.
+	var partitions []string
+	if len(sizeLimitExceeded) > 0 && sizeLimitPolicy == trustapi.TargetSizeLimitPolicyPartition {
+		partitions, err = partitionBundleData(data, maxBytes, maxCertificates)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to partition bundle data: %w", err)
+		}
+	}
+
+	if b.FIPS {
+		if nonCompliant := fipsNonCompliantFormats(bundle.Spec.Target); len(nonCompliant) > 0 {
+			message := fmt.Sprintf("Refusing to sync Bundle in FIPS mode since its target formats aren't FIPS compliant: %s", strings.Join(nonCompliant, ", "))
+			log.Info("bundle target formats aren't FIPS compliant", "formats", nonCompliant)
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "FIPSNonCompliant", message)
+			b.metrics.syncFailed(bundle.Name, "", "FIPSNonCompliant")
+
+			b.setBundleCondition(&bundle, trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "FIPSNonCompliant",
+				Message: message,
+			})
+
+			return b.externalSourceRefresh(&bundle, ctrl.Result{}), b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+	}
+
+	var jksPassword []byte
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.JKS != nil {
+		jksPassword, err = b.jksPassword(ctx, formats.JKS)
+		if err != nil {
+			log.Error(err, "failed to resolve JKS target password")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetPasswordError", "Failed to resolve JKS target password: %s", err)
+			b.metrics.syncFailed(bundle.Name, "", "TargetPasswordError")
+
+			b.setBundleCondition(&bundle, trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "TargetPasswordError",
+				Message: "Failed to resolve JKS target password: " + err.Error(),
+			})
+
+			return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+	}
+
+	var pkcs12Password string
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PKCS12 != nil {
+		pkcs12Password, err = b.pkcs12Password(ctx, formats.PKCS12)
+		if err != nil {
+			log.Error(err, "failed to resolve PKCS12 target password")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "TargetPasswordError", "Failed to resolve PKCS12 target password: %s", err)
+			b.metrics.syncFailed(bundle.Name, "", "TargetPasswordError")
+
+			b.setBundleCondition(&bundle, trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "TargetPasswordError",
+				Message: "Failed to resolve PKCS12 target password: " + err.Error(),
+			})
+
+			return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+	}
+
+	var metadata string
+	if _, ok := metadataKey(bundle.Spec.Target); ok {
+		metadata, err = encodeMetadata(data, resolvedBundle.certificateLabels, bundle.Spec.Target.AdditionalFormats.Metadata.IncludePEM)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to build bundle metadata: %w", err)
+		}
+	}
+
+	var spiffe string
+	if _, ok := spiffeBundleKey(bundle.Spec.Target); ok {
+		spiffe, err = encodeSPIFFEBundle(data)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to build SPIFFE trust bundle: %w", err)
+		}
+	}
+
+	var provenance string
+	if _, ok := provenanceKey(bundle.Spec.Target); ok {
+		provenance, err = encodeProvenance(data, resolvedBundle.certificateSources)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to build bundle provenance: %w", err)
+		}
+	}
+
+	var directory map[string]string
+	if prefix, indexKey, ok := pemDirectoryKeys(bundle.Spec.Target); ok {
+		directory, err = encodePEMDirectory(data, prefix, indexKey, bundle.Spec.Target.AdditionalFormats.PEMDirectory.KeyNaming)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to build PEM directory: %w", err)
+		}
+	}
+
+	var profiles map[string]string
+	if trustProfiles := targetProfiles(bundle.Spec.Target); len(trustProfiles) > 0 {
+		profiles, err = encodeProfiles(data, trustProfiles, resolvedBundle.certificatePurposes)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to build trust profiles: %w", err)
+		}
+	}
+
+	// If a rollout of this content was interrupted by the target write
+	// budget, resume it from the first Namespace which wasn't synced.
+	namespaces := namespacesByName(namespaceList.Items)
+	rolloutHash := contentHash(data + metadata + spiffe + provenance)
+	var resumed bool
+	budget := targetWriteBudget(b.TargetWriteBudget, bundle.Spec.PriorityClass)
+	if budget > 0 {
+		if interrupted, ok := b.rollouts.get(bundle.Name, rolloutHash); ok {
+			namespaces = namespaces[sort.Search(len(namespaces), func(i int) bool { return namespaces[i].Name >= interrupted.next }):]
+			resumed = true
+		}
+	}
+
+	// Bundles which track acknowledgments annotate their targets with the
+	// hash of the bundle data, which consumers acknowledge once loaded.
+	var ackHash string
+	if bundle.Spec.TrackAcknowledgments {
+		ackHash = targethash.Sum(data)
+	}
+
+	needsUpdate := collisionConditionChanged
+	var writes, targets int
+	var pendingAcknowledgments []string
+	for i, namespace := range namespaces {
+		if budget > 0 && writes >= budget {
+			b.rollouts.set(bundle.Name, rollout{hash: rolloutHash, next: namespace.Name})
+
+			log.V(2).Info("interrupting rollout as target write budget was reached", "next", namespace.Name, "remaining", len(namespaces)-i)
+
+			// The condition doesn't name the next Namespace, so that it stays
+			// the same for every pass of the rollout. Updating the status on
+			// each pass would requeue the Bundle immediately through its watch,
+			// bypassing the continuation delay.
+			rolloutCondition := trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "RolloutInProgress",
+				Message: fmt.Sprintf("Target write budget of %d reached, rollout continues in a later reconcile", budget),
+			}
+			if !collisionConditionChanged && !sourceHealthChanged && bundleHasCondition(&bundle, rolloutCondition) {
+				return ctrl.Result{RequeueAfter: rolloutContinuationDelay}, nil
+			}
+
+			b.setBundleCondition(&bundle, rolloutCondition)
+			return ctrl.Result{RequeueAfter: rolloutContinuationDelay}, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+
 		log = log.WithValues("namespace", namespace.Name)
 
 		// Don't reconcile target for Namespaces that are being terminated.
@@ -190,10 +784,11 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 			continue
 		}
 
-		synced, err := b.syncTarget(ctx, log, &bundle, namespaceSelector, &namespace, resolvedBundle.data)
+		synced, acknowledged, err := b.syncTarget(ctx, log, &bundle, namespaceSelector, &namespace, data, metadata, spiffe, provenance, ackHash, directory, profiles, partitions, jksPassword, pkcs12Password)
 		if err != nil {
 			log.Error(err, "failed sync bundle to target namespace")
 			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncTargetFailed", "Failed to sync target in Namespace %q: %s", namespace.Name, err)
+			b.metrics.syncFailed(bundle.Name, namespace.Name, "SyncTargetFailed")
 
 			b.setBundleCondition(&bundle, trustapi.BundleCondition{
 				Type:    trustapi.BundleConditionSynced,
@@ -205,25 +800,349 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 			return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
 		}
 
+		if bundle.Spec.Target.Istio != nil {
+			istioSynced, err := b.syncIstioTarget(ctx, log, &bundle, namespaceSelector, &namespace, data)
+			if err != nil {
+				log.Error(err, "failed sync bundle to Istio root certificate configmap")
+				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncIstioTargetFailed", "Failed to sync Istio root certificate ConfigMap in Namespace %q: %s", namespace.Name, err)
+				b.metrics.syncFailed(bundle.Name, namespace.Name, "SyncIstioTargetFailed")
+
+				b.setBundleCondition(&bundle, trustapi.BundleCondition{
+					Type:    trustapi.BundleConditionSynced,
+					Status:  corev1.ConditionFalse,
+					Reason:  "SyncIstioTargetFailed",
+					Message: fmt.Sprintf("Failed to sync bundle to Istio root certificate ConfigMap in namespace %q: %s", namespace.Name, err),
+				})
+
+				return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+			}
+			synced = synced || istioSynced
+		}
+
+		if bundle.Spec.Target.Immutable != nil {
+			versionSynced, err := b.syncImmutableTarget(ctx, log, &bundle, namespaceSelector, &namespace)
+			if err != nil {
+				log.Error(err, "failed sync bundle to immutable target version")
+				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncImmutableTargetFailed", "Failed to sync immutable target version in Namespace %q: %s", namespace.Name, err)
+				b.metrics.syncFailed(bundle.Name, namespace.Name, "SyncImmutableTargetFailed")
+
+				b.setBundleCondition(&bundle, trustapi.BundleCondition{
+					Type:    trustapi.BundleConditionSynced,
+					Status:  corev1.ConditionFalse,
+					Reason:  "SyncImmutableTargetFailed",
+					Message: fmt.Sprintf("Failed to sync immutable target version in namespace %q: %s", namespace.Name, err),
+				})
+
+				return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+			}
+			synced = synced || versionSynced
+		}
+
 		if synced {
 			// We need to update if any target is synced.
 			needsUpdate = true
+			writes++
+		}
+
+		if len(ackHash) > 0 && namespaceSelector.Matches(labels.Set(namespace.Labels)) && !namespaceSkipsTargets(&namespace) {
+			targets++
+			if !acknowledged {
+				pendingAcknowledgments = append(pendingAcknowledgments, namespace.Name)
+			}
 		}
 	}
 
+	b.rollouts.delete(bundle.Name)
+
+	// Only warn about oversized bundle data when it is written to targets,
+	// rather than on every sync.
+	if len(sizeLimitExceeded) > 0 && sizeLimitPolicy == trustapi.TargetSizeLimitPolicyWarn && writes > 0 {
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SizeLimitExceeded", "Synced Bundle although the %s", sizeLimitExceeded)
+	}
+	if len(partitions) > 0 && writes > 0 {
+		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "Partitioned", "Split the bundle data into %d partitions since the %s", len(partitions), sizeLimitExceeded)
+	}
+
+	if truncated := int32(truncatedCertificates); bundle.Status.TruncatedCertificates != truncated {
+		// Only warn when the number changes, rather than on every sync.
+		if truncated > 0 {
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SizeLimitExceeded", "Omitted %d certificates from the Bundle's targets since the %s", truncated, sizeLimitExceeded)
+		}
+		bundle.Status.TruncatedCertificates = truncated
+		needsUpdate = true
+	}
+
+	if b.EnableClusterPlacement {
+		clusters, err := b.syncPlacement(ctx, &bundle, data)
+		if err != nil {
+			log.Error(err, "failed to sync bundle to managed clusters")
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PlacementSyncFailed", "Failed to sync Bundle to managed clusters: %s", err)
+			b.metrics.syncFailed(bundle.Name, "", "PlacementSyncFailed")
+
+			b.setBundleCondition(&bundle, trustapi.BundleCondition{
+				Type:    trustapi.BundleConditionSynced,
+				Status:  corev1.ConditionFalse,
+				Reason:  "PlacementSyncFailed",
+				Message: "Failed to sync Bundle to managed clusters: " + err.Error(),
+			})
+
+			return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+
+		if !apiequality.Semantic.DeepEqual(bundle.Status.ManagedClusters, clusters) {
+			bundle.Status.ManagedClusters = clusters
+			needsUpdate = true
+		}
+	} else if bundle.Spec.Placement != nil {
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PlacementDisabled", "Bundle placement is ignored as cluster placement is not enabled on the controller")
+	}
+
+	ociStatus, err := b.syncOCI(ctx, &bundle, data)
+	if err != nil {
+		log.Error(err, "failed to push bundle to OCI target")
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "OCIPushFailed", "Failed to push Bundle to OCI target: %s", err)
+		b.metrics.syncFailed(bundle.Name, "", "OCIPushFailed")
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "OCIPushFailed",
+			Message: "Failed to push Bundle to OCI target: " + err.Error(),
+		})
+
+		return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if !apiequality.Semantic.DeepEqual(bundle.Status.OCI, ociStatus) {
+		if ociStatus != nil {
+			b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "OCIPushed", "Pushed Bundle to %s@%s", ociStatus.Reference, ociStatus.Digest)
+		}
+		bundle.Status.OCI = ociStatus
+		needsUpdate = true
+	}
+
+	published, err := b.syncObjectStorageTarget(ctx, &bundle, data)
+	if err != nil {
+		log.Error(err, "failed to publish bundle to object storage target")
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "ObjectStoragePublishFailed", "Failed to publish Bundle to object storage target: %s", err)
+		b.metrics.syncFailed(bundle.Name, "", "ObjectStoragePublishFailed")
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ObjectStoragePublishFailed",
+			Message: "Failed to publish Bundle to object storage target: " + err.Error(),
+		})
+
+		return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if published {
+		target := bundle.Spec.Target.ObjectStorage
+		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "ObjectStoragePublished", "Published Bundle to object %q in bucket %q", target.Key, target.Bucket)
+	}
+
+	openShiftSynced, err := b.syncOpenShiftTrustedCATarget(ctx, &bundle, data)
+	if err != nil {
+		log.Error(err, "failed to sync bundle to OpenShift trusted CA target")
+		b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SyncOpenShiftTrustedCAFailed", "Failed to sync OpenShift trusted CA ConfigMap: %s", err)
+		b.metrics.syncFailed(bundle.Name, trustapi.OpenShiftConfigNamespace, "SyncOpenShiftTrustedCAFailed")
+
+		b.setBundleCondition(&bundle, trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "SyncOpenShiftTrustedCAFailed",
+			Message: "Failed to sync bundle to OpenShift trusted CA ConfigMap: " + err.Error(),
+		})
+
+		return ctrl.Result{Requeue: true}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	}
+
+	if openShiftSynced {
+		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "OpenShiftTrustedCASynced", "Synced Bundle to ConfigMap %s/%s", trustapi.OpenShiftConfigNamespace, openShiftTrustedCAName(bundle.Spec.Target.OpenShiftTrustedCA))
+	}
+
+	// All targets have been synced, so clear any previously recorded failures.
+	b.metrics.syncSucceeded(bundle.Name)
+
 	if bundle.Status.Target == nil || !apiequality.Semantic.DeepEqual(*bundle.Status.Target, bundle.Spec.Target) {
 		bundle.Status.Target = &bundle.Spec.Target
 		needsUpdate = true
 	}
 
-	if b.setBundleStatusDefaultCAVersion(&bundle, resolvedBundle.defaultCAPackageStringID) {
+	var appliedContentHash string
+	if len(bundle.Spec.MaintenanceWindows) > 0 {
+		appliedContentHash = contentHash(data)
+	}
+
+	if bundle.Status.AppliedContentHash != appliedContentHash {
+		bundle.Status.AppliedContentHash = appliedContentHash
+		needsUpdate = true
+	}
+
+	if content := statusContent(data, b.StatusContentMaxBytes); bundle.Status.Content == nil || *bundle.Status.Content != *content {
+		bundle.Status.Content = content
+		needsUpdate = true
+	}
+
+	// A resumed rollout only visited some of the targets, so acknowledgments
+	// are aggregated on the next reconcile which visits all of them.
+	if !resumed {
+		var acknowledgments *trustapi.BundleAcknowledgments
+		if len(ackHash) > 0 {
+			acknowledgments = newBundleAcknowledgments(ackHash, targets, pendingAcknowledgments)
+		}
+
+		if !apiequality.Semantic.DeepEqual(bundle.Status.Acknowledgments, acknowledgments) {
+			bundle.Status.Acknowledgments = acknowledgments
+			needsUpdate = true
+		}
+	}
+
+	// The default CA package versions are only updated once the content they
+	// were resolved for has been applied, and are kept while the last known
+	// good content is synced.
+	if deferredUntil == nil && sourceErr == nil {
+		if b.setBundleStatusDefaultCAVersion(&bundle, resolvedBundle.defaultCAPackageStringID) {
+			needsUpdate = true
+		}
+
+		if b.setBundleStatusDefaultCAPackages(&bundle, resolvedBundle.namedDefaultCAPackages) {
+			needsUpdate = true
+		}
+
+		if excluded := int32(resolvedBundle.excludedExpiredCertificates); bundle.Status.ExcludedExpiredCertificates != excluded {
+			bundle.Status.ExcludedExpiredCertificates = excluded
+			needsUpdate = true
+		}
+
+		if excluded := int32(resolvedBundle.excludedExpiringCertificates); bundle.Status.ExcludedExpiringCertificates != excluded {
+			bundle.Status.ExcludedExpiringCertificates = excluded
+			needsUpdate = true
+		}
+
+		if excluded := int32(resolvedBundle.excludedMatchedCertificates); bundle.Status.ExcludedMatchedCertificates != excluded {
+			bundle.Status.ExcludedMatchedCertificates = excluded
+			needsUpdate = true
+		}
+
+		if duplicates := int32(resolvedBundle.duplicateCertificates); bundle.Status.DuplicateCertificates != duplicates {
+			bundle.Status.DuplicateCertificates = duplicates
+			needsUpdate = true
+		}
+
+		if crossSigned := int32(resolvedBundle.crossSignedCertificates); bundle.Status.CrossSignedCertificates != crossSigned {
+			bundle.Status.CrossSignedCertificates = crossSigned
+			needsUpdate = true
+		}
+
+		if distrusted := int32(resolvedBundle.distrustedCertificates); bundle.Status.DistrustedCertificates != distrusted {
+			bundle.Status.DistrustedCertificates = distrusted
+			needsUpdate = true
+		}
+
+		if nonCA := int32(resolvedBundle.nonCACertificates); bundle.Status.NonCACertificates != nonCA {
+			// Only warn when the number changes, rather than on every sync.
+			if nonCA > 0 && !sourcesEnforceCACertificates(&bundle) {
+				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "NonCACertificates", "Bundle includes %d certificates without the CA:true basic constraint; set the nonCACertificates filter to Enforce to exclude them", nonCA)
+			}
+			bundle.Status.NonCACertificates = nonCA
+			needsUpdate = true
+		}
+
+		if weak := int32(resolvedBundle.weakCryptoCertificates); bundle.Status.WeakCryptoCertificates != weak {
+			// Only warn when the number changes, rather than on every sync.
+			if weak > 0 && b.sourcesWarnWeakCrypto(&bundle) {
+				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "WeakCryptoCertificates", "Bundle includes %d certificates with weak keys or signatures; set the weakCrypto filter action to Enforce to exclude them", weak)
+			}
+			bundle.Status.WeakCryptoCertificates = weak
+			needsUpdate = true
+		}
+
+		if invalid := int32(resolvedBundle.invalidPEMBlocks); bundle.Status.InvalidPEMBlocks != invalid {
+			// Only warn when the number changes, rather than on every sync.
+			if invalid > 0 {
+				b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "InvalidPEMBlocks", "Skipped %d PEM blocks in the Bundle's sources which aren't valid certificates", invalid)
+			}
+			bundle.Status.InvalidPEMBlocks = invalid
+			needsUpdate = true
+		}
+	}
+
+	// Previous keys of the target are dropped from the status once their
+	// retention has expired and they were removed from all of the targets.
+	if bundle.Spec.Target.ConfigMap != nil && (len(bundle.Status.RetainedKeys) > 0 || bundleHasConditionType(&bundle, trustapi.BundleConditionDeprecated)) {
+		if !resumed && pruneRetainedKeys(&bundle, b.clock.Now()) {
+			needsUpdate = true
+		}
+
+		if condition := deprecatedCondition(&bundle); !bundleHasCondition(&bundle, condition) {
+			if condition.Status == corev1.ConditionTrue {
+				b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "PreviousKeyRetained", condition.Message)
+			}
+			b.setBundleCondition(&bundle, condition)
+			needsUpdate = true
+		}
+	}
+
+	privateKeyCondition := trustapi.BundleCondition{
+		Type:    trustapi.BundleConditionPrivateKeyDetected,
+		Status:  corev1.ConditionFalse,
+		Reason:  "NoPrivateKey",
+		Message: "No private keys detected in the Bundle's sources",
+	}
+	if resolvedBundle.privateKeySources > 0 {
+		privateKeyCondition = trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionPrivateKeyDetected,
+			Status:  corev1.ConditionTrue,
+			Reason:  "PrivateKeyStripped",
+			Message: fmt.Sprintf("Private keys were stripped from %d of the Bundle's sources", resolvedBundle.privateKeySources),
+		}
+	}
+	if sourceErr == nil && (resolvedBundle.privateKeySources > 0 || bundleHasConditionType(&bundle, trustapi.BundleConditionPrivateKeyDetected)) && !bundleHasCondition(&bundle, privateKeyCondition) {
+		if resolvedBundle.privateKeySources > 0 {
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "PrivateKeyDetected", privateKeyCondition.Message)
+		}
+		b.setBundleCondition(&bundle, privateKeyCondition)
+		needsUpdate = true
+	}
+
+	degradedCondition := trustapi.BundleCondition{
+		Type:    trustapi.BundleConditionDegraded,
+		Status:  corev1.ConditionFalse,
+		Reason:  "SourcesAvailable",
+		Message: "All of the Bundle's sources were read successfully",
+	}
+	if sourceErr != nil {
+		degradedCondition = trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionDegraded,
+			Status:  corev1.ConditionTrue,
+			Reason:  "SourceUnavailable",
+			Message: "Syncing last known good content since a Bundle source failed: " + sourceErr.Error(),
+		}
+	}
+	if (sourceErr != nil || bundleHasConditionType(&bundle, trustapi.BundleConditionDegraded)) && !bundleHasCondition(&bundle, degradedCondition) {
+		if sourceErr != nil {
+			b.recorder.Eventf(&bundle, corev1.EventTypeWarning, "SourceUnavailable", degradedCondition.Message)
+		}
+		b.setBundleCondition(&bundle, degradedCondition)
 		needsUpdate = true
 	}
 
 	message := "Successfully synced Bundle to all namespaces"
-	if nsSelector := bundle.Spec.Target.NamespaceSelector; nsSelector != nil && nsSelector.MatchLabels != nil {
+	if bundle.Spec.Target.Mode == trustapi.TargetModeLocal {
+		message = fmt.Sprintf("Successfully synced Bundle to the trust namespace %q", b.Namespace)
+	} else if nsSelector := bundle.Spec.Target.NamespaceSelector; nsSelector != nil && len(nsSelector.MatchExpressions) > 0 {
+		selector, _, _ := namespaceLabelSelector(nsSelector)
+		message = fmt.Sprintf("Successfully synced Bundle to namespaces with selector [%s]", selector)
+	} else if nsSelector != nil && nsSelector.MatchLabels != nil {
 		message = fmt.Sprintf("Successfully synced Bundle to namespaces with selector [matchLabels:%v]",
 			nsSelector.MatchLabels)
+	} else if namespaces := bundle.Spec.Target.Namespaces; len(namespaces) > 0 {
+		message = fmt.Sprintf("Successfully synced Bundle to namespaces %v", namespaces)
+	}
+	if exclude, ok, _ := namespaceLabelSelector(bundle.Spec.Target.NamespaceExcludeSelector); ok {
+		message += fmt.Sprintf(", excluding namespaces with selector [%s]", exclude)
 	}
 
 	syncedCondition := trustapi.BundleCondition{
@@ -233,8 +1152,64 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 		Message: message,
 	}
 
+	var result ctrl.Result
+	if deferredUntil != nil {
+		syncedCondition = trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionSynced,
+			Status:  corev1.ConditionFalse,
+			Reason:  "ContentChangeDeferred",
+			Message: fmt.Sprintf("Content change is deferred until the next maintenance window opens at %s", deferredUntil.UTC().Format(time.RFC3339)),
+		}
+
+		// Reconcile again when the next maintenance window opens to apply the
+		// deferred change.
+		result.RequeueAfter = deferredUntil.Sub(b.clock.Now())
+	}
+
+	result = b.externalSourceRefresh(&bundle, result)
+
+	// Retry the failed sources with backoff, unless they're retried sooner.
+	if sourceErr != nil {
+		result.Requeue = true
+	}
+
+	// A resumed rollout skipped the Namespaces synced before it was
+	// interrupted, so check them again in case they changed in the meantime.
+	if resumed && (result.RequeueAfter == 0 || rolloutContinuationDelay < result.RequeueAfter) {
+		result.RequeueAfter = rolloutContinuationDelay
+	}
+
+	// Reconcile again once the next certificate subject to the expiry filters
+	// or a distrust-after time is due to be excluded, so that it is removed
+	// from the targets.
+	if !resolvedBundle.nextExclusion.IsZero() {
+		excludedIn := resolvedBundle.nextExclusion.Sub(b.clock.Now()) + time.Second
+		if result.RequeueAfter == 0 || excludedIn < result.RequeueAfter {
+			result.RequeueAfter = excludedIn
+		}
+	}
+
+	// Reconcile again once the retention of the next previous key of the
+	// target expires, so that the bundle data is removed from it.
+	if next := nextRetainedKeyExpiry(&bundle, b.clock.Now()); !next.IsZero() {
+		expiresIn := next.Sub(b.clock.Now()) + time.Second
+		if result.RequeueAfter == 0 || expiresIn < result.RequeueAfter {
+			result.RequeueAfter = expiresIn
+		}
+	}
+
 	if !needsUpdate && bundleHasCondition(&bundle, syncedCondition) {
-		return ctrl.Result{}, nil
+		if sourceHealthChanged {
+			return result, b.targetDirectClient.Status().Update(ctx, &bundle)
+		}
+		return result, nil
+	}
+
+	if deferredUntil != nil {
+		log.V(2).Info("synced bundle with content change deferred", "until", deferredUntil)
+		b.setBundleCondition(&bundle, syncedCondition)
+		b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "ContentChangeDeferred", syncedCondition.Message)
+		return result, b.targetDirectClient.Status().Update(ctx, &bundle)
 	}
 
 	log.V(2).Info("successfully synced bundle")
@@ -243,5 +1218,5 @@ func (b *bundle) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result,
 
 	b.recorder.Eventf(&bundle, corev1.EventTypeNormal, "Synced", message)
 
-	return ctrl.Result{}, b.targetDirectClient.Status().Update(ctx, &bundle)
+	return result, b.targetDirectClient.Status().Update(ctx, &bundle)
 }
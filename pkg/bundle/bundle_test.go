@@ -88,16 +88,18 @@ func Test_Reconcile(t *testing.T) {
 			},
 			Spec: trustapi.BundleSpec{
 				Sources: []trustapi.BundleSource{
-					{ConfigMap: &trustapi.SourceObjectKeySelector{Name: sourceConfigMapName, KeySelector: trustapi.KeySelector{Key: sourceConfigMapKey}}},
-					{Secret: &trustapi.SourceObjectKeySelector{Name: sourceSecretName, KeySelector: trustapi.KeySelector{Key: sourceSecretKey}}},
+					{ConfigMap: &trustapi.SourceObjectKeySelector{Name: sourceConfigMapName, Key: sourceConfigMapKey}},
+					{Secret: &trustapi.SourceObjectKeySelector{Name: sourceSecretName, Key: sourceSecretKey}},
 					{InLine: pointer.String(dummy.TestCertificate3)},
 				},
-				Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+				Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 			},
 		}
 
 		baseBundleOwnerRef = []metav1.OwnerReference{*metav1.NewControllerRef(baseBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))}
 
+		managedKeys = map[string]string{managedTargetKeysAnnotation: "data:" + targetKey}
+
 		namespaces = []runtime.Object{
 			&corev1.Namespace{TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: trustNamespace}},
 			&corev1.Namespace{TypeMeta: metav1.TypeMeta{Kind: "Namespace", APIVersion: "v1"}, ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
@@ -199,6 +201,45 @@ func Test_Reconcile(t *testing.T) {
 			),
 			expEvent: `Warning SourceNotFound Bundle source was not found: failed to retrieve bundle from source: secrets "source-secret" not found`,
 		},
+		"if Bundle references a Secret containing a private key, should not sync and update PrivateKeyDetected": {
+			existingObjects: append(namespaces, sourceConfigMap,
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: sourceSecretName},
+					Data:       map[string][]byte{sourceSecretKey: []byte(dummy.JoinCerts(dummy.TestCertificate2, testPrivateKey))},
+				},
+				gen.BundleFrom(baseBundle)),
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap,
+				&corev1.Secret{
+					TypeMeta:   metav1.TypeMeta{Kind: "Secret", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: sourceSecretName, ResourceVersion: "999"},
+					Data:       map[string][]byte{sourceSecretKey: []byte(dummy.JoinCerts(dummy.TestCertificate2, testPrivateKey))},
+				},
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{Conditions: []trustapi.BundleCondition{
+						{
+							Type:               trustapi.BundleConditionPrivateKeyDetected,
+							Status:             corev1.ConditionTrue,
+							Reason:             "SyncRefused",
+							Message:            "Refusing to sync Bundle since source 1 contains a PEM private key; set stripPrivateKeys to remove private keys from the sources",
+							ObservedGeneration: bundleGeneration,
+							LastTransitionTime: fixedmetatime,
+						},
+						{
+							Type:               trustapi.BundleConditionSynced,
+							Status:             corev1.ConditionFalse,
+							Reason:             "PrivateKeyDetected",
+							Message:            "Refusing to sync Bundle since source 1 contains a PEM private key; set stripPrivateKeys to remove private keys from the sources",
+							ObservedGeneration: bundleGeneration,
+							LastTransitionTime: fixedmetatime,
+						},
+					}}),
+				),
+			),
+			expEvent: "Warning PrivateKeyDetected Refusing to sync Bundle since source 1 contains a PEM private key; set stripPrivateKeys to remove private keys from the sources",
+		},
 		"if Bundle references a Secret whose key doesn't exist, update with 'not found'": {
 			existingObjects: append(namespaces, sourceConfigMap,
 				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: sourceSecretName}},
@@ -229,7 +270,7 @@ func Test_Reconcile(t *testing.T) {
 		"if Bundle Status Target doesn't match the Spec Target, delete old targets and update": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "old-target"}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "old-target"}}}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
@@ -245,7 +286,7 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}}}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
@@ -261,10 +302,10 @@ func Test_Reconcile(t *testing.T) {
 		"if Bundle Status Target doesn't match the Spec Target, delete all old targets and update": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: "old-target"},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "old-target"},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
@@ -281,10 +322,10 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.TargetKeySelector{Key: targetKey},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
@@ -301,10 +342,10 @@ func Test_Reconcile(t *testing.T) {
 		"if Bundle Status Target.AdditionalFormats.JKS doesn't match the Spec Target.AdditionalFormats.JKS, delete old targets and update": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "old-target.jks"}},
+						ConfigMap:         &trustapi.TargetKeySelector{Key: targetKey},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "old-target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
@@ -321,10 +362,10 @@ func Test_Reconcile(t *testing.T) {
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
-					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}}),
+					gen.SetBundleTargetAdditionalFormats(trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}}),
 					gen.SetBundleStatus(trustapi.BundleStatus{Target: &trustapi.BundleTarget{
-						ConfigMap:         &trustapi.KeySelector{Key: targetKey},
-						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.KeySelector{Key: "target.jks"}},
+						ConfigMap:         &trustapi.TargetKeySelector{Key: targetKey},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "target.jks"}}},
 					}}),
 				),
 				&corev1.ConfigMap{
@@ -346,7 +387,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -357,21 +398,181 @@ func Test_Reconcile(t *testing.T) {
 								ObservedGeneration: bundleGeneration,
 							},
 						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle not synced yet and target exists without owner, should not sync and update CollisionDetected": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret, gen.BundleFrom(baseBundle),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name},
+					Data:       map[string]string{"foo": "bar"},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name},
+					Data:       map[string]string{"foo": "bar"},
+				},
+			),
+			expResult: ctrl.Result{Requeue: true},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionCollisionDetected,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "TargetNotOwned",
+								Message:            `Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-1, ns-2`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "CollisionDetected",
+								Message:            `Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-1, ns-2`,
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, ResourceVersion: "999"},
+					Data:       map[string]string{"foo": "bar"},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, ResourceVersion: "999"},
+					Data:       map[string]string{"foo": "bar"},
+				},
+			),
+			expEvent: `Warning CollisionDetected Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-1, ns-2`,
+		},
+		"if Bundle not synced yet and target exists without owner with the Overwrite conflict policy, should overwrite target and sync": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle, gen.SetBundleTargetConflictPolicy(trustapi.TargetConflictPolicyOverwrite)),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name},
+					Data:       map[string]string{"foo": "bar"},
+				},
+			),
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleTargetConflictPolicy(trustapi.TargetConflictPolicyOverwrite),
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{
+							ConfigMap:      &trustapi.TargetKeySelector{Key: targetKey},
+							ConflictPolicy: trustapi.TargetConflictPolicyOverwrite,
+						},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionCollisionDetected,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "TargetOverwritten",
+								Message:            `Target ConfigMap "test-bundle" already existed without being owned by the Bundle and was overwritten in namespaces: ns-1`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle not synced yet and previous collision has been resolved, should sync and clear CollisionDetected": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionCollisionDetected,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "TargetNotOwned",
+								Message:            `Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-1`,
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+					}),
+				),
+			),
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionCollisionDetected,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "NoCollision",
+								Message:            "No target collisions detected",
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -391,7 +592,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{{
 							Type:               trustapi.BundleConditionSynced,
 							Status:             corev1.ConditionTrue,
@@ -400,21 +601,22 @@ func Test_Reconcile(t *testing.T) {
 							Message:            "Successfully synced Bundle to all namespaces",
 							ObservedGeneration: bundleGeneration,
 						}},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -447,7 +649,7 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleTargetNamespaceSelectorMatchLabels(map[string]string{"foo": "bar"}),
 					gen.SetBundleStatus(trustapi.BundleStatus{
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.TargetKeySelector{Key: targetKey},
 							NamespaceSelector: &trustapi.NamespaceSelector{
 								MatchLabels: map[string]string{"foo": "bar"},
 							},
@@ -460,16 +662,17 @@ func Test_Reconcile(t *testing.T) {
 							Message:            "Successfully synced Bundle to namespaces with selector [matchLabels:map[foo:bar]]",
 							ObservedGeneration: bundleGeneration,
 						}},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "random-namespace", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "another-random-namespace", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "another-random-namespace", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
@@ -503,7 +706,7 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleTargetNamespaceSelectorMatchLabels(map[string]string{"foo": "bar"}),
 					gen.SetBundleStatus(trustapi.BundleStatus{
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.TargetKeySelector{Key: targetKey},
 							NamespaceSelector: &trustapi.NamespaceSelector{
 								MatchLabels: map[string]string{"foo": "bar"},
 							},
@@ -516,17 +719,18 @@ func Test_Reconcile(t *testing.T) {
 							Message:            "Successfully synced Bundle to namespaces with selector [matchLabels:map[foo:bar]]",
 							ObservedGeneration: bundleGeneration,
 						}},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 			),
 			expEvent: "Normal Synced Successfully synced Bundle to namespaces with selector [matchLabels:map[foo:bar]]",
 		},
-		"if Bundle synced but doesn't have owner reference, should sync and update": {
+		"if Bundle synced and a Namespace created since already has a target without owner, should not sync and update CollisionDetected": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleStatus(trustapi.BundleStatus{
 						Target: &trustapi.BundleTarget{
-							ConfigMap: &trustapi.KeySelector{Key: targetKey},
+							ConfigMap: &trustapi.TargetKeySelector{Key: targetKey},
 						},
 						Conditions: []trustapi.BundleCondition{
 							{
@@ -535,38 +739,46 @@ func Test_Reconcile(t *testing.T) {
 								LastTransitionTime: fixedmetatime,
 								Reason:             "Synced",
 								Message:            "Successfully synced Bundle to all namespaces",
-								ObservedGeneration: bundleGeneration - 1,
+								ObservedGeneration: bundleGeneration,
 							},
 						},
 					}),
 				),
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name},
-					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+					Data:       map[string]string{"foo": "bar"},
 				},
 			),
-			expResult: ctrl.Result{},
+			expResult: ctrl.Result{Requeue: true},
 			expError:  false,
 			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
-								Type:               trustapi.BundleConditionSynced,
+								Type:               trustapi.BundleConditionCollisionDetected,
 								Status:             corev1.ConditionTrue,
 								LastTransitionTime: fixedmetatime,
-								Reason:             "Synced",
-								Message:            "Successfully synced Bundle to all namespaces",
+								Reason:             "TargetNotOwned",
+								Message:            `Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-2`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "CollisionDetected",
+								Message:            `Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-2`,
 								ObservedGeneration: bundleGeneration,
 							},
 						},
@@ -574,21 +786,21 @@ func Test_Reconcile(t *testing.T) {
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
-					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, ResourceVersion: "999"},
+					Data:       map[string]string{"foo": "bar"},
 				},
 			),
-			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+			expEvent: `Warning CollisionDetected Target ConfigMap "test-bundle" already exists and is not owned by the Bundle in namespaces: ns-2`,
 		},
 		"if Bundle synced but doesn't have condition, should add condition": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret, gen.BundleFrom(baseBundle),
@@ -614,7 +826,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -625,6 +837,7 @@ func Test_Reconcile(t *testing.T) {
 								ObservedGeneration: bundleGeneration,
 							},
 						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
@@ -649,7 +862,7 @@ func Test_Reconcile(t *testing.T) {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -660,6 +873,7 @@ func Test_Reconcile(t *testing.T) {
 								ObservedGeneration: bundleGeneration,
 							},
 						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
@@ -681,7 +895,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1000"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -692,6 +906,7 @@ func Test_Reconcile(t *testing.T) {
 								ObservedGeneration: bundleGeneration,
 							},
 						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
@@ -712,6 +927,337 @@ func Test_Reconcile(t *testing.T) {
 			),
 			expEvent: "",
 		},
+		"if a source of a degraded Bundle is still not found, should keep syncing the last known good content": {
+			existingObjects: append(namespaces, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionDegraded,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SourceUnavailable",
+								Message:            `Syncing last known good content since a Bundle source failed: failed to retrieve bundle from source: configmaps "source-configmap" not found`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expResult: ctrl.Result{Requeue: true},
+			expError:  false,
+			expObjects: append(namespaces, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionDegraded,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SourceUnavailable",
+								Message:            `Syncing last known good content since a Bundle source failed: failed to retrieve bundle from source: configmaps "source-configmap" not found`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if the sources of a degraded Bundle are available again, should sync and clear Degraded": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionDegraded,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SourceUnavailable",
+								Message:            `Syncing last known good content since a Bundle source failed: failed to retrieve bundle from source: configmaps "source-configmap" not found`,
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionDegraded,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "SourcesAvailable",
+								Message:            "All of the Bundle's sources were read successfully",
+								ObservedGeneration: bundleGeneration,
+							},
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						Content: statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
+		"if Bundle content changed outside of maintenance windows, should sync previously applied content and defer change": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration - 1,
+							},
+						},
+						AppliedContentHash: contentHash(dummy.JoinCerts(dummy.TestCertificate4)),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+			),
+			expResult: ctrl.Result{RequeueAfter: 25 * time.Hour},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "ContentChangeDeferred",
+								Message:            "Content change is deferred until the next maintenance window opens at 2021-01-02T02:00:00Z",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						AppliedContentHash: contentHash(dummy.JoinCerts(dummy.TestCertificate4)),
+						Content:            statusContent(dummy.JoinCerts(dummy.TestCertificate4), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+			),
+			expEvent: "Normal ContentChangeDeferred Content change is deferred until the next maintenance window opens at 2021-01-02T02:00:00Z",
+		},
+		"if Bundle content changed outside of maintenance windows and the applied content is unknown, should not sync targets": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target:             &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						AppliedContentHash: contentHash(dummy.JoinCerts(dummy.TestCertificate4)),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate3)},
+				},
+			),
+			expResult: ctrl.Result{RequeueAfter: 25 * time.Hour},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionFalse,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "AppliedContentUnknown",
+								Message:            "Content change is deferred until the next maintenance window opens at 2021-01-02T02:00:00Z, and targets are not synced since the previously applied content was not found in any target",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						AppliedContentHash: contentHash(dummy.JoinCerts(dummy.TestCertificate4)),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "999"},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate3)},
+				},
+			),
+			expEvent: "Warning AppliedContentUnknown Content change is deferred until the next maintenance window opens at 2021-01-02T02:00:00Z, and targets are not synced since the previously applied content was not found in any target",
+		},
+		"if Bundle content changed inside a maintenance window, should sync new content": {
+			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 0 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration - 1,
+							},
+						},
+						AppliedContentHash: contentHash(dummy.JoinCerts(dummy.TestCertificate4)),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef},
+					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate4)},
+				},
+			),
+			expResult: ctrl.Result{},
+			expError:  false,
+			expObjects: append(namespaces, sourceConfigMap, sourceSecret,
+				gen.BundleFrom(baseBundle,
+					gen.SetBundleResourceVersion("1001"),
+					gen.SetBundleMaintenanceWindows(trustapi.MaintenanceWindow{Schedule: "0 0 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}),
+					gen.SetBundleStatus(trustapi.BundleStatus{
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
+						Conditions: []trustapi.BundleCondition{
+							{
+								Type:               trustapi.BundleConditionSynced,
+								Status:             corev1.ConditionTrue,
+								LastTransitionTime: fixedmetatime,
+								Reason:             "Synced",
+								Message:            "Successfully synced Bundle to all namespaces",
+								ObservedGeneration: bundleGeneration,
+							},
+						},
+						AppliedContentHash: contentHash(dummy.DefaultJoinedCerts()),
+						Content:            statusContent(dummy.DefaultJoinedCerts(), 0),
+					}),
+				),
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+				&corev1.ConfigMap{
+					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
+				},
+			),
+			expEvent: "Normal Synced Successfully synced Bundle to all namespaces",
+		},
 		"if Bundle references default CAs but it wasn't configured at startup, update with error": {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle, gen.AppendBundleUsesDefaultPackage())),
@@ -740,7 +1286,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.AppendBundleUsesDefaultPackage(),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -774,7 +1320,7 @@ func Test_Reconcile(t *testing.T) {
 					gen.SetBundleResourceVersion("1001"),
 					gen.AppendBundleUsesDefaultPackage(),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -786,21 +1332,22 @@ func Test_Reconcile(t *testing.T) {
 							},
 						},
 						DefaultCAPackageVersion: pointer.String(testDefaultPackage.StringID()),
+						Content:                 statusContent(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5), 0),
 					}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3, dummy.TestCertificate5)},
 				},
 			),
@@ -810,7 +1357,7 @@ func Test_Reconcile(t *testing.T) {
 			existingObjects: append(namespaces, sourceConfigMap, sourceSecret,
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -844,7 +1391,7 @@ func Test_Reconcile(t *testing.T) {
 				gen.BundleFrom(baseBundle,
 					gen.SetBundleResourceVersion("1001"),
 					gen.SetBundleStatus(trustapi.BundleStatus{
-						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: targetKey}},
+						Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: targetKey}},
 						Conditions: []trustapi.BundleCondition{
 							{
 								Type:               trustapi.BundleConditionSynced,
@@ -856,21 +1403,22 @@ func Test_Reconcile(t *testing.T) {
 							},
 						},
 						DefaultCAPackageVersion: nil,
+						Content:                 statusContent(dummy.DefaultJoinedCerts(), 0),
 					}),
 				),
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: trustNamespace, Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 				&corev1.ConfigMap{
 					TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
-					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: baseBundle.Name, Annotations: managedKeys, OwnerReferences: baseBundleOwnerRef, ResourceVersion: "1000"},
 					Data:       map[string]string{targetKey: dummy.DefaultJoinedCerts()},
 				},
 			),
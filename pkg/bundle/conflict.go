@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// adoptTarget makes the given Bundle the controller of the target ConfigMap.
+// Other owners are kept, but are no longer the controller, since an object
+// can only have one. With the Overwrite conflict policy, the entries written
+// by others are removed, so that the target only contains the bundle data.
+func adoptTarget(configMap *corev1.ConfigMap, bundle *trustapi.Bundle) {
+	for i := range configMap.OwnerReferences {
+		if ref := &configMap.OwnerReferences[i]; ref.Controller != nil && *ref.Controller {
+			ref.Controller = pointer.Bool(false)
+		}
+	}
+	configMap.OwnerReferences = append(configMap.OwnerReferences, *metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle")))
+
+	if bundle.Spec.Target.ConflictPolicy == trustapi.TargetConflictPolicyOverwrite {
+		configMap.Data = nil
+		configMap.BinaryData = nil
+	}
+}
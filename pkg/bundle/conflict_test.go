@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_adoptTarget(t *testing.T) {
+	otherOwner := metav1.OwnerReference{APIVersion: "v1", Kind: "Secret", Name: "other", Controller: pointer.Bool(true)}
+
+	tests := map[string]struct {
+		policy        trustapi.TargetConflictPolicy
+		expData       map[string]string
+		expBinaryData map[string][]byte
+	}{
+		"adopted target should keep the entries of others": {
+			policy:        trustapi.TargetConflictPolicyAdopt,
+			expData:       map[string]string{"foo": "bar"},
+			expBinaryData: map[string][]byte{"foo.bin": []byte("bar")},
+		},
+		"overwritten target should have the entries of others removed": {
+			policy: trustapi.TargetConflictPolicyOverwrite,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			bundle := &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
+				Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{ConflictPolicy: test.policy}},
+			}
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{otherOwner}},
+				Data:       map[string]string{"foo": "bar"},
+				BinaryData: map[string][]byte{"foo.bin": []byte("bar")},
+			}
+
+			adoptTarget(configMap, bundle)
+
+			assert.True(t, metav1.IsControlledBy(configMap, bundle))
+			if assert.Len(t, configMap.OwnerReferences, 2) {
+				assert.Equal(t, "other", configMap.OwnerReferences[0].Name)
+				assert.False(t, *configMap.OwnerReferences[0].Controller, "other owner should no longer be the controller")
+			}
+			assert.Equal(t, test.expData, configMap.Data)
+			assert.Equal(t, test.expBinaryData, configMap.BinaryData)
+		})
+	}
+}
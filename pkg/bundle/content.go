@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// DefaultStatusContentMaxBytes is the default maximum size of bundle data
+// which is included in the Bundle status, leaving room for the rest of the
+// Bundle within the maximum size of an object.
+const DefaultStatusContentMaxBytes = 256 * 1024
+
+// statusContent returns the content of the Bundle status for the given bundle
+// data. The data itself is only included if it is no larger than maxBytes.
+func statusContent(data string, maxBytes int) *trustapi.BundleContent {
+	content := &trustapi.BundleContent{
+		Hash: contentHash(data),
+		Size: int32(len(data)),
+	}
+
+	if len(data) <= maxBytes {
+		content.Data = data
+	}
+
+	return content
+}
+
+// lastKnownGoodData returns the bundle data which was last successfully
+// synced to the targets of the given Bundle, taken from its status or, if the
+// data was too large to be included in the status, read back from any
+// unmodified target in whichever format it was written. Returns false if the data is unknown or empty, in which case
+// targets can't be synced without the Bundle's sources.
+func (b *bundle) lastKnownGoodData(ctx context.Context, bundle *trustapi.Bundle, namespaces []corev1.Namespace) (string, bool, error) {
+	content := bundle.Status.Content
+	if content == nil || content.Size == 0 {
+		return "", false, nil
+	}
+
+	if len(content.Data) > 0 && contentHash(content.Data) == content.Hash {
+		return content.Data, true, nil
+	}
+
+	return b.appliedTargetData(ctx, bundle, namespaces, content.Hash)
+}
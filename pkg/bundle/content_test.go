@@ -0,0 +1,216 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_statusContent(t *testing.T) {
+	data := dummy.DefaultJoinedCerts()
+
+	tests := map[string]struct {
+		maxBytes int
+
+		expContent *trustapi.BundleContent
+	}{
+		"data within the limit should be included": {
+			maxBytes: len(data),
+			expContent: &trustapi.BundleContent{
+				Data: data,
+				Hash: contentHash(data),
+				Size: int32(len(data)),
+			},
+		},
+		"data exceeding the limit should be omitted": {
+			maxBytes: len(data) - 1,
+			expContent: &trustapi.BundleContent{
+				Hash: contentHash(data),
+				Size: int32(len(data)),
+			},
+		},
+		"zero limit should omit the data": {
+			maxBytes: 0,
+			expContent: &trustapi.BundleContent{
+				Hash: contentHash(data),
+				Size: int32(len(data)),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expContent, statusContent(data, test.maxBytes))
+		})
+	}
+}
+
+func Test_lastKnownGoodData(t *testing.T) {
+	data := dummy.DefaultJoinedCerts()
+
+	gzipData, err := encodeGzip(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partitions, err := partitionBundleData(data, len(data), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, partitions, 2) {
+		return
+	}
+
+	baseBundle := &trustapi.Bundle{
+		TypeMeta:   metav1.TypeMeta{Kind: "Bundle", APIVersion: "trust.cert-manager.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-bundle", UID: "123"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "target-key"}},
+		},
+	}
+	ownerRefs := []metav1.OwnerReference{*metav1.NewControllerRef(baseBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))}
+
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "ns-2"}},
+	}
+
+	tests := map[string]struct {
+		content         *trustapi.BundleContent
+		modifyTarget    func(target *trustapi.BundleTarget)
+		existingObjects []runtime.Object
+
+		expData string
+		expOK   bool
+	}{
+		"no content in the status should not return data": {
+			expOK: false,
+		},
+		"empty content in the status should not return data": {
+			content: statusContent("", len(data)),
+			expOK:   false,
+		},
+		"data in the status should be returned": {
+			content: statusContent(data, len(data)),
+			expData: data,
+			expOK:   true,
+		},
+		"data omitted from the status should be returned from an unmodified target": {
+			content: statusContent(data, 0),
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key": dummy.TestCertificate1},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-2", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key": data},
+				},
+			},
+			expData: data,
+			expOK:   true,
+		},
+		"data omitted from the status should be returned from an unmodified DER target": {
+			content: statusContent(data, 0),
+			modifyTarget: func(target *trustapi.BundleTarget) {
+				target.ConfigMap.Format = trustapi.TargetFormatDER
+			},
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					BinaryData: map[string][]byte{"target-key": util.EncodeDERBundle([]byte(data))},
+				},
+			},
+			expData: data,
+			expOK:   true,
+		},
+		"data omitted from the status should be returned from an unmodified gzip target omitting the uncompressed data": {
+			content: statusContent(data, 0),
+			modifyTarget: func(target *trustapi.BundleTarget) {
+				target.AdditionalFormats = &trustapi.AdditionalFormats{
+					Gzip: &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: "target-key.gz"}, OmitUncompressed: true},
+				}
+			},
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					BinaryData: map[string][]byte{"target-key.gz": gzipData},
+				},
+			},
+			expData: data,
+			expOK:   true,
+		},
+		"data omitted from the status should be returned from an unmodified partitioned target": {
+			content: statusContent(data, 0),
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       partitionEntries("test-bundle", "target-key", trustapi.DefaultPartitionIndexKey, partitions),
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle-1", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key-1": partitions[1]},
+				},
+			},
+			expData: data,
+			expOK:   true,
+		},
+		"data omitted from the status should not be returned from a target not owned by the Bundle": {
+			content: statusContent(data, 0),
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle"},
+					Data:       map[string]string{"target-key": data},
+				},
+			},
+			expOK: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{
+				targetDirectClient: fakeclient.NewClientBuilder().
+					WithScheme(trustapi.GlobalScheme).
+					WithRuntimeObjects(test.existingObjects...).
+					Build(),
+			}
+
+			bundle := baseBundle.DeepCopy()
+			bundle.Status.Content = test.content
+			if test.modifyTarget != nil {
+				test.modifyTarget(&bundle.Spec.Target)
+			}
+
+			data, ok, err := b.lastKnownGoodData(context.TODO(), bundle, namespaces)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expOK, ok)
+			assert.Equal(t, test.expData, data)
+		})
+	}
+}
@@ -19,9 +19,12 @@ package bundle
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
 	toolscache "k8s.io/client-go/tools/cache"
 	"k8s.io/utils/clock"
@@ -29,13 +32,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/pkg/nodecas"
 )
 
 // AddBundleController will register the Bundle controller with the
@@ -57,6 +64,12 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		Mapper:    mgr.GetRESTMapper(),
 		Namespace: opts.Namespace,
 
+		// Watches are always established with bookmarks enabled by the
+		// underlying reflector, so a resync is not needed to keep the cache
+		// consistent. Trust Namespaces can contain very many unrelated Secrets,
+		// so allow periodic resyncs to be turned down or off entirely.
+		Resync: &opts.SourceResyncPeriod,
+
 		// These transforms are used as a safety check to ensure that only
 		// resources of the expected types are cached.
 		TransformByObject: map[client.Object]toolscache.TransformFunc{
@@ -89,7 +102,29 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		sourceLister:       sourceCache,
 		recorder:           mgr.GetEventRecorderFor("bundles"),
 		clock:              clock.RealClock{},
-		Options:            opts,
+		objectStorageClient: &http.Client{
+			Timeout: objectStorageTimeout,
+		},
+		Options: opts,
+	}
+
+	syncFailureDetailLimit := b.Options.SyncFailureDetailLimit
+	if syncFailureDetailLimit <= 0 {
+		syncFailureDetailLimit = DefaultSyncFailureDetailLimit
+	}
+
+	b.metrics, err = newMetrics(ctrlmetrics.Registry, syncFailureDetailLimit)
+	if err != nil {
+		return fmt.Errorf("failed to register metrics: %w", err)
+	}
+
+	// The default metrics endpoint doesn't serve exemplars, so additionally
+	// serve metrics in the OpenMetrics format.
+	if err := mgr.AddMetricsExtraHandler(OpenMetricsPath, promhttp.HandlerFor(ctrlmetrics.Registry, promhttp.HandlerOpts{
+		ErrorHandling:     promhttp.HTTPErrorOnError,
+		EnableOpenMetrics: true,
+	})); err != nil {
+		return fmt.Errorf("failed to add OpenMetrics handler: %w", err)
 	}
 
 	if b.Options.DefaultPackageLocation != "" {
@@ -103,8 +138,27 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		b.Options.Log.Info("successfully loaded default package from filesystem", "path", b.Options.DefaultPackageLocation)
 	}
 
+	for _, location := range b.Options.NamedDefaultPackageLocations {
+		pkg, err := fspkg.LoadPackageFromFile(location)
+		if err != nil {
+			return fmt.Errorf("must load named default package successfully: %w", err)
+		}
+
+		if b.namedDefaultPackages == nil {
+			b.namedDefaultPackages = make(map[string]*fspkg.Package)
+		}
+
+		if _, ok := b.namedDefaultPackages[pkg.Name]; ok {
+			return fmt.Errorf("named default package %q at %q has the same name as another named default package", pkg.Name, location)
+		}
+
+		b.namedDefaultPackages[pkg.Name] = &pkg
+
+		b.Options.Log.Info("successfully loaded named default package from filesystem", "path", location, "name", pkg.Name)
+	}
+
 	// Only reconcile config maps that match the well known name
-	if err := ctrl.NewControllerManagedBy(mgr).
+	controller := ctrl.NewControllerManagedBy(mgr).
 		Named("bundles").
 
 		////// Targets //////
@@ -122,7 +176,9 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 		Watches(source.NewKindWithCache(new(trustapi.Bundle), sourceCache), &handler.EnqueueRequestForObject{}).
 
 		// Watch all Namespaces. Cache whole Namespaces to include Phase Status.
-		// Reconcile all Bundles on a Namespace change.
+		// Reconcile all Bundles on a Namespace change. Ignore update events
+		// which don't change the ResourceVersion, such as those generated by
+		// an informer resync, so that they don't reconcile every Bundle.
 		Watches(source.NewKindWithCache(new(corev1.Namespace), sourceCache), handler.EnqueueRequestsFromMapFunc(
 			func(obj client.Object) []reconcile.Request {
 				// If an error happens here and we do nothing, we run the risk of
@@ -138,10 +194,12 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 
 				return requests
 			},
-		)).
+		), builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 
 		// Watch ConfigMaps in trust Namespace. Only cache metadata.
-		// Reconcile Bundles who reference a modified source ConfigMap.
+		// Reconcile Bundles who reference a modified source ConfigMap. Ignore
+		// update events which don't change the ResourceVersion, such as those
+		// generated by an informer resync.
 		Watches(source.NewKindWithCache(new(corev1.ConfigMap), sourceCache), handler.EnqueueRequestsFromMapFunc(
 			func(obj client.Object) []reconcile.Request {
 				// If an error happens here and we do nothing, we run the risk of
@@ -154,12 +212,22 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 				var requests []reconcile.Request
 				for _, bundle := range bundleList.Items {
 					for _, source := range bundle.Spec.Sources {
-						if source.ConfigMap == nil {
+						var name string
+						switch {
+						case source.ConfigMap != nil:
+							name = source.ConfigMap.Name
+						case source.UseClusterAPIServerCA != nil && *source.UseClusterAPIServerCA:
+							name = ClusterAPIServerCAConfigMapName
+						case source.UseNodeOSCAs != nil && *source.UseNodeOSCAs:
+							name = nodecas.ConfigMapName
+						case source.UseOpenShiftTrustedCA != nil && *source.UseOpenShiftTrustedCA:
+							name = OpenShiftTrustedCAConfigMapName
+						default:
 							continue
 						}
 
 						// Bundle references this ConfigMap as a source. Add to request.
-						if source.ConfigMap.Name == obj.GetName() {
+						if name == obj.GetName() {
 							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
 							break
 						}
@@ -168,10 +236,12 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 
 				return requests
 			},
-		)).
+		), builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 
 		// Watch Secrets in trust Namespace. Only cache metadata.
-		// Reconcile Bundles who reference a modified source Secret.
+		// Reconcile Bundles who reference a modified source Secret. Ignore
+		// update events which don't change the ResourceVersion, such as those
+		// generated by an informer resync.
 		Watches(source.NewKindWithCache(new(corev1.Secret), sourceCache), handler.EnqueueRequestsFromMapFunc(
 			func(obj client.Object) []reconcile.Request {
 				// If an error happens here and we do nothing, we run the risk of
@@ -182,13 +252,59 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 
 				var requests []reconcile.Request
 				for _, bundle := range bundleList.Items {
+					// Bundle references this Secret as the password of its JKS
+					// target. Add to request.
+					if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.JKS != nil &&
+						formats.JKS.PasswordFrom != nil && formats.JKS.PasswordFrom.Secret != nil &&
+						formats.JKS.PasswordFrom.Secret.Name == obj.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						continue
+					}
+
+					// Bundle references this Secret as the password of its
+					// PKCS#12 target. Add to request.
+					if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PKCS12 != nil &&
+						formats.PKCS12.PasswordFrom != nil && formats.PKCS12.PasswordFrom.Secret != nil &&
+						formats.PKCS12.PasswordFrom.Secret.Name == obj.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						continue
+					}
+
+					// Bundle references this Secret as the credentials of its
+					// OCI target. Add to request.
+					if oci := bundle.Spec.Target.OCI; oci != nil && len(oci.CredentialsSecret) > 0 && oci.CredentialsSecret == obj.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						continue
+					}
+
+					// Bundle references this Secret as the credentials of its
+					// object storage target. Add to request.
+					if objectStorage := bundle.Spec.Target.ObjectStorage; objectStorage != nil && len(objectStorage.CredentialsSecret) > 0 && objectStorage.CredentialsSecret == obj.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						continue
+					}
+
 					for _, source := range bundle.Spec.Sources {
-						if source.Secret == nil {
+						var name string
+						switch {
+						case source.Secret != nil:
+							name = source.Secret.Name
+						case source.TLSSecret != nil:
+							name = source.TLSSecret.Name
+						case source.IstioCACertsSecret != nil:
+							name = source.IstioCACertsSecret.Name
+						case source.TruststoreSecret != nil:
+							name = source.TruststoreSecret.Name
+						case source.ObjectStorage != nil:
+							name = source.ObjectStorage.CredentialsSecret
+						case source.RemoteCluster != nil:
+							name = source.RemoteCluster.KubeconfigSecret.Name
+						default:
 							continue
 						}
 
 						// Bundle references this Secret as a source. Add to request.
-						if source.Secret.Name == obj.GetName() {
+						if name == obj.GetName() {
 							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
 							break
 						}
@@ -197,18 +313,88 @@ func AddBundleController(ctx context.Context, mgr manager.Manager, opts Options)
 
 				return requests
 			},
-		)).
+		), builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}))
+
+	////// Placement //////
+
+	if b.EnableClusterPlacement {
+		placementDecision := new(unstructured.Unstructured)
+		placementDecision.SetGroupVersionKind(placementDecisionGVK)
+
+		manifestWork := new(unstructured.Unstructured)
+		manifestWork.SetGroupVersionKind(manifestWorkGVK)
+
+		controller = controller.
+			// Watch PlacementDecisions in all Namespaces. Only cache metadata.
+			// Reconcile Bundles whose placement is the Placement of a modified
+			// PlacementDecision.
+			Watches(&source.Kind{Type: placementDecision}, handler.EnqueueRequestsFromMapFunc(
+				func(obj client.Object) []reconcile.Request {
+					placement := obj.GetLabels()[placementLabel]
+					if len(placement) == 0 {
+						return nil
+					}
+
+					bundleList := b.mustBundleList(ctx)
+
+					var requests []reconcile.Request
+					for _, bundle := range bundleList.Items {
+						if ref := bundle.Spec.Placement; ref != nil && ref.Name == placement && ref.Namespace == obj.GetNamespace() {
+							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: bundle.Name}})
+						}
+					}
+
+					return requests
+				},
+			), builder.OnlyMetadata).
 
-		// Complete controller.
-		Complete(b); err != nil {
+			// Reconcile over owned ManifestWorks in all managed cluster
+			// Namespaces. Only cache metadata.
+			Watches(&source.Kind{Type: manifestWork}, &handler.EnqueueRequestForOwner{
+				OwnerType:    new(trustapi.Bundle),
+				IsController: true,
+			}, builder.OnlyMetadata)
+	}
+
+	////// Distribution //////
+
+	if len(b.DistributionAddress) > 0 {
+		if err := mgr.Add(&distributionServer{
+			reader:    sourceCache,
+			naming:    b.Naming,
+			namespace: b.Namespace,
+			address:   b.DistributionAddress,
+			certFile:  b.DistributionCertFile,
+			keyFile:   b.DistributionKeyFile,
+		}); err != nil {
+			return fmt.Errorf("failed to add bundle distribution server to manager: %w", err)
+		}
+	}
+
+	////// Source health //////
+
+	if b.SourceHealthProbePeriod > 0 {
+		events := make(chan event.GenericEvent)
+		if err := mgr.Add(&sourceHealthProber{bundle: b, period: b.SourceHealthProbePeriod, events: events}); err != nil {
+			return fmt.Errorf("failed to add source health prober to manager: %w", err)
+		}
+
+		// Reconcile Bundles whose source health changed, so that the change is
+		// written to their status.
+		controller = controller.Watches(&source.Channel{Source: events}, &handler.EnqueueRequestForObject{})
+	}
+
+	// Complete controller.
+	if err := controller.Complete(b); err != nil {
 		return fmt.Errorf("failed to create Bundle controller: %s", err)
 	}
 
 	return nil
 }
 
-// mustBundleList will return a BundleList of all Bundles in the cluster. If an
-// error occurs, will exit error the program.
+// mustBundleList will return a BundleList of all Bundles in the cluster,
+// sorted by priority class so that higher priority Bundles are enqueued first.
+// If an error occurs, will exit error the program.
 func (b *bundle) mustBundleList(ctx context.Context) *trustapi.BundleList {
 	var bundleList trustapi.BundleList
 	if err := b.sourceLister.List(ctx, &bundleList); err != nil {
@@ -216,5 +402,7 @@ func (b *bundle) mustBundleList(ctx context.Context) *trustapi.BundleList {
 		os.Exit(-1)
 	}
 
+	sortBundlesByPriority(bundleList.Items)
+
 	return &bundleList
 }
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// reconcileDeletionPolicy adds the target cleanup finalizer to Bundles with a
+// target deletion policy, and removes it from those without one. Deleted
+// Bundles with the finalizer have their targets cleaned up according to their
+// policy before the finalizer is removed. Returns true if the Bundle must not
+// be synced further in this reconcile, along with the result to return.
+func (b *bundle) reconcileDeletionPolicy(ctx context.Context, log logr.Logger, bundle *trustapi.Bundle) (bool, ctrl.Result, error) {
+	policy := bundle.Spec.Target.DeletionPolicy
+	hasFinalizer := controllerutil.ContainsFinalizer(bundle, trustapi.TargetCleanupFinalizer)
+
+	if bundle.DeletionTimestamp == nil {
+		switch {
+		case len(policy) > 0 && !hasFinalizer:
+			controllerutil.AddFinalizer(bundle, trustapi.TargetCleanupFinalizer)
+		case len(policy) == 0 && hasFinalizer:
+			controllerutil.RemoveFinalizer(bundle, trustapi.TargetCleanupFinalizer)
+		default:
+			return false, ctrl.Result{}, nil
+		}
+
+		// The update triggers another reconcile, which syncs the Bundle.
+		return true, ctrl.Result{}, b.targetDirectClient.Update(ctx, bundle)
+	}
+
+	// Deleted Bundles are never synced, since their targets are either being
+	// cleaned up or garbage collected.
+	if !hasFinalizer {
+		log.V(2).Info("bundle is being deleted, ignoring")
+		return true, ctrl.Result{}, nil
+	}
+
+	remaining, err := b.cleanupTargets(ctx, bundle, policy)
+	if err != nil {
+		log.Error(err, "failed to clean up targets")
+		b.recorder.Eventf(bundle, corev1.EventTypeWarning, "TargetCleanupError", "Failed to clean up targets: %s", err)
+		return true, ctrl.Result{}, fmt.Errorf("failed to clean up targets: %w", err)
+	}
+
+	if remaining > 0 {
+		log.V(2).Info("target cleanup continues as target write budget was reached", "remaining", remaining)
+		return true, ctrl.Result{RequeueAfter: rolloutContinuationDelay}, nil
+	}
+
+	log.Info("cleaned up targets, removing finalizer", "policy", policy)
+	controllerutil.RemoveFinalizer(bundle, trustapi.TargetCleanupFinalizer)
+	return true, ctrl.Result{}, b.targetDirectClient.Update(ctx, bundle)
+}
+
+// cleanupTargets deletes or orphans the ConfigMaps controlled by the given
+// Bundle, according to the deletion policy. ConfigMaps in the trust Namespace
+// are cleaned up last, since other Bundles and the distribution endpoint may
+// read them. At most the target write budget of ConfigMaps are cleaned up,
+// returning the number of ConfigMaps which remain.
+func (b *bundle) cleanupTargets(ctx context.Context, bundle *trustapi.Bundle, policy trustapi.TargetDeletionPolicy) (int, error) {
+	var configMapList corev1.ConfigMapList
+	if err := b.targetDirectClient.List(ctx, &configMapList); err != nil {
+		return 0, fmt.Errorf("failed to list ConfigMaps: %w", err)
+	}
+
+	var configMaps []corev1.ConfigMap
+	for _, configMap := range configMapList.Items {
+		if metav1.IsControlledBy(&configMap, bundle) {
+			configMaps = append(configMaps, configMap)
+		}
+	}
+
+	sort.SliceStable(configMaps, func(i, j int) bool {
+		if trustI, trustJ := configMaps[i].Namespace == b.Namespace, configMaps[j].Namespace == b.Namespace; trustI != trustJ {
+			return trustJ
+		}
+		if configMaps[i].Namespace != configMaps[j].Namespace {
+			return configMaps[i].Namespace < configMaps[j].Namespace
+		}
+		return configMaps[i].Name < configMaps[j].Name
+	})
+
+	budget := targetWriteBudget(b.TargetWriteBudget, bundle.Spec.PriorityClass)
+	for i := range configMaps {
+		if budget > 0 && i >= budget {
+			return len(configMaps) - i, nil
+		}
+
+		configMap := &configMaps[i]
+		switch policy {
+		case trustapi.TargetDeletionPolicyOrphan:
+			ownerReferences := configMap.OwnerReferences[:0]
+			for _, ref := range configMap.OwnerReferences {
+				if ref.UID != bundle.UID {
+					ownerReferences = append(ownerReferences, ref)
+				}
+			}
+			configMap.OwnerReferences = ownerReferences
+
+			if err := b.targetDirectClient.Update(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+				return len(configMaps) - i, fmt.Errorf("failed to orphan ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+			}
+		default:
+			if err := b.targetDirectClient.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+				return len(configMaps) - i, fmt.Errorf("failed to delete ConfigMap %s/%s: %w", configMap.Namespace, configMap.Name, err)
+			}
+		}
+	}
+
+	return 0, nil
+}
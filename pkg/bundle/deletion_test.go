@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_reconcileDeletionPolicy(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	deleted := metav1.Now()
+
+	bundleWith := func(policy trustapi.TargetDeletionPolicy, finalizers []string, deletionTimestamp *metav1.Time) *trustapi.Bundle {
+		return &trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-bundle", UID: "test-uid", Finalizers: finalizers, DeletionTimestamp: deletionTimestamp},
+			Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{DeletionPolicy: policy}},
+		}
+	}
+
+	targets := func(bundle *trustapi.Bundle) []client.Object {
+		owned := func(namespace, name string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       namespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+			}}
+		}
+		return []client.Object{
+			owned(trustNamespace, "test-bundle"),
+			owned("ns-a", "test-bundle"),
+			owned("ns-b", "test-bundle"),
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "ns-a"}},
+		}
+	}
+
+	tests := map[string]struct {
+		bundle *trustapi.Bundle
+		budget int
+
+		expDone          bool
+		expResult        ctrl.Result
+		expFinalizer     bool
+		expOwnedTargets  []string
+		expExistingCount int
+	}{
+		"Bundle without policy or finalizer should be synced": {
+			bundle:           bundleWith("", nil, nil),
+			expDone:          false,
+			expOwnedTargets:  []string{"ns-a", "ns-b", trustNamespace},
+			expExistingCount: 4,
+		},
+		"Bundle with policy should have the finalizer added": {
+			bundle:           bundleWith(trustapi.TargetDeletionPolicyOrphan, nil, nil),
+			expDone:          true,
+			expFinalizer:     true,
+			expOwnedTargets:  []string{"ns-a", "ns-b", trustNamespace},
+			expExistingCount: 4,
+		},
+		"Bundle without policy should have the finalizer removed": {
+			bundle:           bundleWith("", []string{trustapi.TargetCleanupFinalizer}, nil),
+			expDone:          true,
+			expFinalizer:     false,
+			expOwnedTargets:  []string{"ns-a", "ns-b", trustNamespace},
+			expExistingCount: 4,
+		},
+		"deleted Bundle with Delete policy should have its targets deleted": {
+			bundle:           bundleWith(trustapi.TargetDeletionPolicyDelete, []string{trustapi.TargetCleanupFinalizer}, &deleted),
+			expDone:          true,
+			expFinalizer:     false,
+			expExistingCount: 1,
+		},
+		"deleted Bundle with Orphan policy should have its targets orphaned": {
+			bundle:           bundleWith(trustapi.TargetDeletionPolicyOrphan, []string{trustapi.TargetCleanupFinalizer}, &deleted),
+			expDone:          true,
+			expFinalizer:     false,
+			expExistingCount: 4,
+		},
+		"deleted Bundle should clean up targets within the budget, trust Namespace last": {
+			bundle:           bundleWith(trustapi.TargetDeletionPolicyDelete, []string{trustapi.TargetCleanupFinalizer}, &deleted),
+			budget:           2,
+			expDone:          true,
+			expResult:        ctrl.Result{RequeueAfter: rolloutContinuationDelay},
+			expFinalizer:     true,
+			expOwnedTargets:  []string{trustNamespace},
+			expExistingCount: 2,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithObjects(append(targets(test.bundle), test.bundle.DeepCopy())...).
+				Build()
+
+			b := &bundle{
+				targetDirectClient: fakeclient,
+				recorder:           record.NewFakeRecorder(10),
+				Options:            Options{Namespace: trustNamespace, TargetWriteBudget: test.budget},
+			}
+
+			var bundle trustapi.Bundle
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKeyFromObject(test.bundle), &bundle))
+
+			done, result, err := b.reconcileDeletionPolicy(context.TODO(), klogr.New(), &bundle)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expDone, done)
+			assert.Equal(t, test.expResult, result)
+			assert.Equal(t, test.expFinalizer, controllerutil.ContainsFinalizer(&bundle, trustapi.TargetCleanupFinalizer))
+
+			var configMaps corev1.ConfigMapList
+			assert.NoError(t, fakeclient.List(context.TODO(), &configMaps))
+			assert.Len(t, configMaps.Items, test.expExistingCount)
+
+			var ownedTargets []string
+			for _, configMap := range configMaps.Items {
+				if metav1.IsControlledBy(&configMap, &bundle) {
+					ownedTargets = append(ownedTargets, configMap.Namespace)
+				}
+			}
+			assert.Equal(t, test.expOwnedTargets, ownedTargets)
+		})
+	}
+}
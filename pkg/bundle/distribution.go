@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// DistributionPathPrefix is the path prefix under which the distribution
+// endpoint serves Bundles, at <prefix><bundle name>.<pem|jks|p12|spiffe>.
+// Bundles which write the SPIFFE format are served at
+// <prefix><bundle name>.spiffe as a SPIFFE bundle endpoint, so that other
+// trust domains can federate with the Bundle's trust anchors.
+const DistributionPathPrefix = "/bundles/"
+
+// distributionServer serves the bundle data of each Bundle over HTTP(S), so
+// that consumers outside of the cluster, such as VMs and CI runners, can fetch
+// the same trust as workloads. Bundles are served from their target in the
+// trust Namespace, which is read from the source cache, so that every replica
+// of the controller can serve them.
+type distributionServer struct {
+	reader    client.Reader
+	naming    *naming.Conventions
+	namespace string
+
+	address           string
+	certFile, keyFile string
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that
+// every replica serves Bundles.
+func (s *distributionServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves Bundles until the context is cancelled.
+func (s *distributionServer) Start(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.address,
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	var err error
+	if len(s.certFile) > 0 {
+		err = server.ListenAndServeTLS(s.certFile, s.keyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// ServeHTTP serves the requested format of a Bundle. The ETag of the response
+// is the hash of the bundle data, so that clients can poll cheaply using
+// If-None-Match.
+func (s *distributionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	file := strings.TrimPrefix(r.URL.Path, DistributionPathPrefix)
+	name, format, ok := strings.Cut(file, ".")
+	if !strings.HasPrefix(r.URL.Path, DistributionPathPrefix) || !ok || len(name) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	data, contentType, etag, err := s.bundleFile(r.Context(), name, format)
+	if err != nil {
+		var statusErr *distributionError
+		if errors.As(err, &statusErr) {
+			http.Error(w, statusErr.message, statusErr.code)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", etag)
+	// http.ServeContent handles If-None-Match using the ETag header.
+	http.ServeContent(w, r, file, time.Time{}, bytes.NewReader(data))
+}
+
+// distributionError is an error which is served with the given status code.
+type distributionError struct {
+	code    int
+	message string
+}
+
+func (e *distributionError) Error() string {
+	return e.message
+}
+
+// bundleFile returns the named Bundle in the given format, along with its
+// content type and ETag.
+func (s *distributionServer) bundleFile(ctx context.Context, name, format string) ([]byte, string, string, error) {
+	var bundle trustapi.Bundle
+	if err := s.reader.Get(ctx, client.ObjectKey{Name: name}, &bundle); apierrors.IsNotFound(err) {
+		return nil, "", "", &distributionError{http.StatusNotFound, fmt.Sprintf("Bundle %q not found", name)}
+	} else if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get Bundle %q: %w", name, err)
+	}
+
+	target := bundle.Spec.Target
+	if target.ConfigMap == nil {
+		return nil, "", "", &distributionError{http.StatusNotFound, fmt.Sprintf("Bundle %q has no ConfigMap target", name)}
+	}
+
+	switch format {
+	case "pem", "p12":
+	case "jks":
+		if target.AdditionalFormats == nil || target.AdditionalFormats.JKS == nil {
+			return nil, "", "", &distributionError{http.StatusNotFound, fmt.Sprintf("Bundle %q does not write the JKS format", name)}
+		}
+	case "spiffe":
+		if target.AdditionalFormats == nil || target.AdditionalFormats.SPIFFE == nil {
+			return nil, "", "", &distributionError{http.StatusNotFound, fmt.Sprintf("Bundle %q does not write the SPIFFE format", name)}
+		}
+	default:
+		return nil, "", "", &distributionError{http.StatusNotFound, fmt.Sprintf("unsupported format %q, must be one of pem, jks, p12 or spiffe", format)}
+	}
+
+	targetName, err := s.naming.BundleTargetName(bundle.Name, target)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var configMap corev1.ConfigMap
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.namespace, Name: targetName}, &configMap); apierrors.IsNotFound(err) {
+		return nil, "", "", &distributionError{http.StatusServiceUnavailable, fmt.Sprintf("Bundle %q is not synced to the trust namespace", name)}
+	} else if err != nil {
+		return nil, "", "", fmt.Errorf("failed to get target of Bundle %q: %w", name, err)
+	}
+
+	data, ok := util.TargetData(&configMap, target.ConfigMap.Key, target.ConfigMap.Format)
+	if !ok {
+		return nil, "", "", &distributionError{http.StatusServiceUnavailable, fmt.Sprintf("Bundle %q is not synced to the trust namespace", name)}
+	}
+
+	switch format {
+	case "pem":
+		return []byte(data), "application/x-pem-file", `"` + contentHash(data) + `"`, nil
+
+	case "jks":
+		jks, ok := configMap.BinaryData[target.AdditionalFormats.JKS.Key]
+		if !ok {
+			return nil, "", "", &distributionError{http.StatusServiceUnavailable, fmt.Sprintf("Bundle %q is not synced to the trust namespace", name)}
+		}
+		return jks, "application/x-java-keystore", `"` + contentHash(string(jks)) + `"`, nil
+
+	case "spiffe":
+		// The SPIFFE bundle endpoint profile serves the JWK set as JSON.
+		spiffe, ok := configMap.Data[target.AdditionalFormats.SPIFFE.Key]
+		if !ok {
+			return nil, "", "", &distributionError{http.StatusServiceUnavailable, fmt.Sprintf("Bundle %q is not synced to the trust namespace", name)}
+		}
+		return []byte(spiffe), "application/json", `"` + contentHash(spiffe) + `"`, nil
+
+	default:
+		p12, err := encodePKCS12(data, DefaultJKSPassword, "")
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to encode Bundle %q as PKCS#12: %w", name, err)
+		}
+		// PKCS#12 encoding is randomly salted, so the ETag is weak.
+		return p12, "application/x-pkcs12", `W/"` + contentHash(data) + `"`, nil
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"software.sslmate.com/src/go-pkcs12"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_distributionServer(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+	etag := `"` + contentHash(data) + `"`
+
+	server := &distributionServer{
+		reader: fakeclient.NewClientBuilder().
+			WithScheme(trustapi.GlobalScheme).
+			WithRuntimeObjects(
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "bundle-a"},
+					Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							JKS:    &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "ca.jks"}},
+							SPIFFE: &trustapi.KeySelector{Key: "bundle.spiffe"},
+						},
+					}},
+				},
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "bundle-b"},
+					Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+					}},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "bundle-a", Namespace: trustNamespace},
+					Data:       map[string]string{"ca.crt": data, "bundle.spiffe": `{"keys":[]}`},
+					BinaryData: map[string][]byte{"ca.jks": []byte("jks")},
+				},
+			).
+			Build(),
+		namespace: trustNamespace,
+	}
+
+	tests := map[string]struct {
+		method      string
+		path        string
+		ifNoneMatch string
+
+		expCode        int
+		expContentType string
+		expETag        string
+		expBody        string
+	}{
+		"PEM format should be served": {
+			path:           "/bundles/bundle-a.pem",
+			expCode:        http.StatusOK,
+			expContentType: "application/x-pem-file",
+			expETag:        etag,
+			expBody:        data,
+		},
+		"HEAD request should be served without body": {
+			method:         http.MethodHead,
+			path:           "/bundles/bundle-a.pem",
+			expCode:        http.StatusOK,
+			expContentType: "application/x-pem-file",
+			expETag:        etag,
+		},
+		"matching If-None-Match should not be modified": {
+			path:        "/bundles/bundle-a.pem",
+			ifNoneMatch: etag,
+			expCode:     http.StatusNotModified,
+			expETag:     etag,
+		},
+		"different If-None-Match should be served": {
+			path:           "/bundles/bundle-a.pem",
+			ifNoneMatch:    `"other"`,
+			expCode:        http.StatusOK,
+			expContentType: "application/x-pem-file",
+			expETag:        etag,
+			expBody:        data,
+		},
+		"JKS format should be served": {
+			path:           "/bundles/bundle-a.jks",
+			expCode:        http.StatusOK,
+			expContentType: "application/x-java-keystore",
+			expETag:        `"` + contentHash("jks") + `"`,
+			expBody:        "jks",
+		},
+		"JKS format of Bundle without JKS should not be found": {
+			path:    "/bundles/bundle-b.jks",
+			expCode: http.StatusNotFound,
+		},
+		"SPIFFE format should be served as JSON": {
+			path:           "/bundles/bundle-a.spiffe",
+			expCode:        http.StatusOK,
+			expContentType: "application/json",
+			expETag:        `"` + contentHash(`{"keys":[]}`) + `"`,
+			expBody:        `{"keys":[]}`,
+		},
+		"SPIFFE format of Bundle without SPIFFE should not be found": {
+			path:    "/bundles/bundle-b.spiffe",
+			expCode: http.StatusNotFound,
+		},
+		"Bundle not synced to the trust namespace should be unavailable": {
+			path:    "/bundles/bundle-b.pem",
+			expCode: http.StatusServiceUnavailable,
+		},
+		"missing Bundle should not be found": {
+			path:    "/bundles/bundle-c.pem",
+			expCode: http.StatusNotFound,
+		},
+		"unsupported format should not be found": {
+			path:    "/bundles/bundle-a.der",
+			expCode: http.StatusNotFound,
+		},
+		"path outside of the prefix should not be found": {
+			path:    "/bundle-a.pem",
+			expCode: http.StatusNotFound,
+		},
+		"POST request should not be allowed": {
+			method:  http.MethodPost,
+			path:    "/bundles/bundle-a.pem",
+			expCode: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			method := test.method
+			if len(method) == 0 {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, test.path, nil)
+			if len(test.ifNoneMatch) > 0 {
+				req.Header.Set("If-None-Match", test.ifNoneMatch)
+			}
+			rec := httptest.NewRecorder()
+
+			server.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.expCode, rec.Code, rec.Body.String())
+			if test.expCode != http.StatusOK && test.expCode != http.StatusNotModified {
+				return
+			}
+			assert.Equal(t, test.expETag, rec.Header().Get("ETag"))
+			if test.expCode == http.StatusOK {
+				assert.Equal(t, test.expContentType, rec.Header().Get("Content-Type"))
+				assert.Equal(t, test.expBody, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("PKCS#12 format should be served", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bundles/bundle-a.p12", nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, "application/x-pkcs12", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "W/"+etag, rec.Header().Get("ETag"))
+
+		certificates, err := pkcs12.DecodeTrustStore(rec.Body.Bytes(), DefaultJKSPassword)
+		assert.NoError(t, err)
+		assert.Len(t, certificates, 2)
+	})
+}
@@ -0,0 +1,468 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// hasMatchFilters returns true if the given filters select certificates by
+// their names, fingerprints or key usages.
+func hasMatchFilters(filters *trustapi.BundleFilters) bool {
+	return filters != nil && (len(filters.Include) > 0 || len(filters.Exclude) > 0 ||
+		len(filters.AllowFingerprints) > 0 || len(filters.DenyFingerprints) > 0 ||
+		len(filters.KeyUsages) > 0 || len(filters.ExtendedKeyUsages) > 0)
+}
+
+// enforceCACertificates returns true if certificates which are not CAs are
+// excluded by the given filters.
+func enforceCACertificates(filters *trustapi.BundleFilters) bool {
+	return filters != nil && filters.NonCACertificates == trustapi.NonCACertificatePolicyEnforce
+}
+
+// sourcesEnforceCACertificates returns true if certificates which are not CAs
+// are excluded by the effective filters of every source of the Bundle.
+func sourcesEnforceCACertificates(bundle *trustapi.Bundle) bool {
+	for _, source := range bundle.Spec.Sources {
+		if !enforceCACertificates(sourceFilters(bundle, source)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// excludeMatchedCertificates returns the given PEM bundle without the
+// certificates excluded by the include, exclude, fingerprint and key usage
+// filters. The number of excluded certificates is recorded in the resolved
+// bundle.
+func excludeMatchedCertificates(data []byte, filters *trustapi.BundleFilters, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed, err := fingerprintSet(filters.AllowFingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid allowFingerprints filter: %w", err)
+	}
+	denied, err := fingerprintSet(filters.DenyFingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid denyFingerprints filter: %w", err)
+	}
+
+	var included [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		fingerprint := certificateFingerprint(block.Bytes)
+		if denied.Has(fingerprint) || (allowed.Len() > 0 && !allowed.Has(fingerprint)) {
+			resolvedBundle.excludedMatchedCertificates++
+			continue
+		}
+
+		if !util.HasKeyUsages(cert, filters.KeyUsages) || !util.HasExtendedKeyUsages(cert, filters.ExtendedKeyUsages) {
+			resolvedBundle.excludedMatchedCertificates++
+			continue
+		}
+
+		include := len(filters.Include) == 0
+		if !include {
+			include, err = matchesAny(filters.Include, cert)
+			if err != nil {
+				return nil, fmt.Errorf("invalid include filter: %w", err)
+			}
+		}
+
+		if include {
+			exclude, err := matchesAny(filters.Exclude, cert)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude filter: %w", err)
+			}
+			include = !exclude
+		}
+
+		if !include {
+			resolvedBundle.excludedMatchedCertificates++
+			continue
+		}
+
+		included = append(included, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(included, nil)), nil
+}
+
+// excludeDefaultCAs returns the given PEM bundle of a default CA package
+// without the CAs matching the given exclusions. The number of excluded CAs is
+// recorded in the resolved bundle.
+func excludeDefaultCAs(data []byte, exclude *trustapi.DefaultCAsExclusions, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	fingerprints, err := fingerprintSet(exclude.Fingerprints)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fingerprint exclusion: %w", err)
+	}
+	commonNames := sets.New(exclude.CommonNames...)
+
+	var included [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		if fingerprints.Has(certificateFingerprint(block.Bytes)) || commonNames.Has(cert.Subject.CommonName) {
+			resolvedBundle.excludedMatchedCertificates++
+			continue
+		}
+
+		included = append(included, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(included, nil)), nil
+}
+
+// excludeNonCACertificates returns the given PEM bundle without the
+// certificates lacking the CA basic constraint if enforce is true, or
+// unchanged otherwise. The number of such certificates is recorded in the
+// resolved bundle in either case.
+func excludeNonCACertificates(data []byte, enforce bool, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var included [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		if !cert.BasicConstraintsValid || !cert.IsCA {
+			resolvedBundle.nonCACertificates++
+			if enforce {
+				continue
+			}
+		}
+
+		included = append(included, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(included, nil)), nil
+}
+
+// weakCryptoPolicy returns the effective weak crypto action of the given
+// filters, falling back to the given default action, along with the minimum
+// RSA key size and the weak signature algorithms of the policy.
+func weakCryptoPolicy(filters *trustapi.BundleFilters, defaultAction trustapi.WeakCryptoAction) (trustapi.WeakCryptoAction, int, []x509.SignatureAlgorithm) {
+	action := defaultAction
+	minRSAKeySize := util.DefaultMinRSAKeySize
+	weakSignatureAlgorithms := util.DefaultWeakSignatureAlgorithms
+
+	if filters != nil && filters.WeakCrypto != nil {
+		weakCrypto := filters.WeakCrypto
+		if len(weakCrypto.Action) > 0 {
+			action = weakCrypto.Action
+		}
+		if weakCrypto.MinRSAKeySize > 0 {
+			minRSAKeySize = int(weakCrypto.MinRSAKeySize)
+		}
+		if len(weakCrypto.WeakSignatureAlgorithms) > 0 {
+			weakSignatureAlgorithms = make([]x509.SignatureAlgorithm, 0, len(weakCrypto.WeakSignatureAlgorithms))
+			for _, name := range weakCrypto.WeakSignatureAlgorithms {
+				if algorithm, ok := util.SignatureAlgorithms[name]; ok {
+					weakSignatureAlgorithms = append(weakSignatureAlgorithms, algorithm)
+				}
+			}
+		}
+	}
+
+	if len(action) == 0 {
+		action = trustapi.WeakCryptoActionIgnore
+	}
+
+	return action, minRSAKeySize, weakSignatureAlgorithms
+}
+
+// sourcesWarnWeakCrypto returns true if the effective weak crypto action of
+// the filters of any source of the Bundle is Warn.
+func (b *bundle) sourcesWarnWeakCrypto(bundle *trustapi.Bundle) bool {
+	for _, source := range bundle.Spec.Sources {
+		if action, _, _ := weakCryptoPolicy(sourceFilters(bundle, source), b.DefaultWeakCryptoAction); action == trustapi.WeakCryptoActionWarn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// excludeWeakCryptoCertificates returns the given PEM bundle without the
+// certificates with RSA keys smaller than minRSAKeySize bits or signed using
+// one of the weak signature algorithms if enforce is true, or unchanged
+// otherwise. The number of such certificates is recorded in the resolved
+// bundle in either case.
+func excludeWeakCryptoCertificates(data []byte, enforce bool, minRSAKeySize int, weakSignatureAlgorithms []x509.SignatureAlgorithm, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var included [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		if util.HasWeakCrypto(cert, minRSAKeySize, weakSignatureAlgorithms) {
+			resolvedBundle.weakCryptoCertificates++
+			if enforce {
+				continue
+			}
+		}
+
+		included = append(included, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(included, nil)), nil
+}
+
+// deduplicateCertificates returns the given source bundles, which must be in
+// their final order, without certificates which were already included from an
+// earlier source or earlier in the same source. Certificates are duplicates if
+// they are byte-identical, or if byPublicKey is true, if they have the same
+// subject public key info. Sources left without certificates are dropped. The
+// number of omitted duplicates is recorded in the resolved bundle.
+func deduplicateCertificates(bundles []weightedBundle, byPublicKey bool, resolvedBundle *bundleData) ([]weightedBundle, error) {
+	seen := sets.New[string]()
+
+	deduplicated := make([]weightedBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		certificates, err := util.ValidateAndSplitPEMBundle([]byte(bundle.data))
+		if err != nil {
+			return nil, err
+		}
+
+		var included [][]byte
+		for _, certificate := range certificates {
+			block, _ := pem.Decode(certificate)
+
+			identity := certificateFingerprint(block.Bytes)
+			if byPublicKey {
+				cert, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse certificate: %w", err)
+				}
+				identity = certificateFingerprint(cert.RawSubjectPublicKeyInfo)
+			}
+
+			if seen.Has(identity) {
+				resolvedBundle.duplicateCertificates++
+				continue
+			}
+			seen.Insert(identity)
+
+			included = append(included, certificate)
+		}
+
+		if len(included) == 0 {
+			continue
+		}
+
+		deduplicated = append(deduplicated, weightedBundle{
+			weight: bundle.weight,
+			data:   string(bytes.TrimSpace(bytes.Join(included, nil))),
+		})
+	}
+
+	return deduplicated, nil
+}
+
+// selectCrossSignedCertificates returns the given source bundles, which must be
+// in their final order and deduplicated, keeping only the preferred one of
+// certificates with the same subject and subject key identifier according to
+// the given policy, such as the newest of a CA's cross-signed certificates.
+// Certificates without a subject key identifier are always kept. Sources left
+// without certificates are dropped. The number of certificates besides the
+// preferred ones is recorded in the resolved bundle, even if they are kept.
+func selectCrossSignedCertificates(bundles []weightedBundle, policy trustapi.CrossSignedPolicy, resolvedBundle *bundleData) ([]weightedBundle, error) {
+	type parsedCertificate struct {
+		pem  []byte
+		cert *x509.Certificate
+	}
+
+	parsed := make([][]parsedCertificate, len(bundles))
+	preferred := make(map[string]*x509.Certificate)
+	for i, bundle := range bundles {
+		certificates, err := util.ValidateAndSplitPEMBundle([]byte(bundle.data))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, certificate := range certificates {
+			block, _ := pem.Decode(certificate)
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse certificate: %w", err)
+			}
+			parsed[i] = append(parsed[i], parsedCertificate{pem: certificate, cert: cert})
+
+			if len(cert.SubjectKeyId) == 0 {
+				continue
+			}
+
+			identity := crossSignedIdentity(cert)
+			current, ok := preferred[identity]
+			if !ok {
+				preferred[identity] = cert
+				continue
+			}
+
+			resolvedBundle.crossSignedCertificates++
+
+			switch policy {
+			case trustapi.CrossSignedPolicyKeepNewest:
+				if cert.NotBefore.After(current.NotBefore) {
+					preferred[identity] = cert
+				}
+			case trustapi.CrossSignedPolicyKeepLongestValidity:
+				if cert.NotAfter.After(current.NotAfter) {
+					preferred[identity] = cert
+				}
+			}
+		}
+	}
+
+	if len(policy) == 0 || policy == trustapi.CrossSignedPolicyKeepAll {
+		return bundles, nil
+	}
+
+	selected := make([]weightedBundle, 0, len(bundles))
+	for i, bundle := range bundles {
+		var included [][]byte
+		for _, certificate := range parsed[i] {
+			if len(certificate.cert.SubjectKeyId) > 0 && preferred[crossSignedIdentity(certificate.cert)] != certificate.cert {
+				continue
+			}
+
+			included = append(included, certificate.pem)
+		}
+
+		if len(included) == 0 {
+			continue
+		}
+
+		selected = append(selected, weightedBundle{
+			weight: bundle.weight,
+			data:   string(bytes.TrimSpace(bytes.Join(included, nil))),
+		})
+	}
+
+	return selected, nil
+}
+
+// crossSignedIdentity returns the identity shared by the cross-signed or
+// re-issued certificates of a CA, which is its subject and subject key
+// identifier.
+func crossSignedIdentity(cert *x509.Certificate) string {
+	return certificateFingerprint(cert.RawSubject) + "/" + certificateFingerprint(cert.SubjectKeyId)
+}
+
+// fingerprintSet returns the set of the given SHA-256 fingerprints, in the
+// form returned by certificateFingerprint.
+func fingerprintSet(fingerprints []string) (sets.Set[string], error) {
+	set := sets.New[string]()
+	for _, fingerprint := range fingerprints {
+		parsed, err := util.ParseFingerprint(fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", fingerprint, err)
+		}
+		set.Insert(parsed)
+	}
+
+	return set, nil
+}
+
+// matchesAny returns true if the given certificate matches any of the given
+// rules.
+func matchesAny(rules []trustapi.CertificateMatch, cert *x509.Certificate) (bool, error) {
+	for _, rule := range rules {
+		if rule.Subject == nil && rule.Issuer == nil {
+			continue
+		}
+
+		matches := true
+		for _, match := range []struct {
+			dn   *trustapi.DistinguishedNameMatch
+			name string
+		}{
+			{rule.Subject, cert.Subject.String()},
+			{rule.Issuer, cert.Issuer.String()},
+		} {
+			if match.dn == nil {
+				continue
+			}
+
+			ok, err := matchesDistinguishedName(*match.dn, match.name)
+			if err != nil {
+				return false, err
+			}
+			matches = matches && ok
+		}
+
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// matchesDistinguishedName returns true if the given distinguished name, in
+// its RFC 2253 string form, matches the given rule.
+func matchesDistinguishedName(match trustapi.DistinguishedNameMatch, name string) (bool, error) {
+	if len(match.Regex) > 0 {
+		regex, err := regexp.Compile(match.Regex)
+		if err != nil {
+			return false, err
+		}
+		return regex.MatchString(name), nil
+	}
+
+	return match.Exact == name, nil
+}
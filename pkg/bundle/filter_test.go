@@ -0,0 +1,576 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_excludeMatchedCertificates(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate5)
+
+	fingerprint := func(t *testing.T, certificate string) string {
+		block, _ := pem.Decode([]byte(certificate))
+		if block == nil {
+			t.Fatal("failed to decode PEM certificate")
+		}
+		return certificateFingerprint(block.Bytes)
+	}
+
+	tests := map[string]struct {
+		// data overrides the filtered bundle, if set.
+		data    string
+		filters trustapi.BundleFilters
+
+		expData     string
+		expExcluded int
+		expError    bool
+	}{
+		"no rules should keep all certificates": {
+			expData: data,
+		},
+		"exact subject include should keep only the matching certificate": {
+			filters: trustapi.BundleFilters{
+				Include: []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Exact: "CN=GTS Root R1,O=Google Trust Services LLC,C=US"}}},
+			},
+			expData:     dummy.TestCertificate5,
+			expExcluded: 2,
+		},
+		"exact match should not match part of a name": {
+			filters: trustapi.BundleFilters{
+				Include: []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Exact: "CN=GTS Root R1"}}},
+			},
+			expData:     "",
+			expExcluded: 3,
+		},
+		"regex include rules should keep certificates matching any rule": {
+			filters: trustapi.BundleFilters{
+				Include: []trustapi.CertificateMatch{
+					{Subject: &trustapi.DistinguishedNameMatch{Regex: "cmct-test"}},
+					{Issuer: &trustapi.DistinguishedNameMatch{Regex: "Root R[0-9]"}},
+				},
+			},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate5),
+			expExcluded: 1,
+		},
+		"rule with subject and issuer should only match certificates matching both": {
+			filters: trustapi.BundleFilters{
+				Exclude: []trustapi.CertificateMatch{{
+					Subject: &trustapi.DistinguishedNameMatch{Regex: "Root"},
+					Issuer:  &trustapi.DistinguishedNameMatch{Regex: "O=Google"},
+				}},
+			},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3),
+			expExcluded: 1,
+		},
+		"exclude rules should take precedence over include rules": {
+			filters: trustapi.BundleFilters{
+				Include: []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Regex: "C=US$"}}},
+				Exclude: []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Regex: "ISRG"}}},
+			},
+			expData:     dummy.TestCertificate5,
+			expExcluded: 2,
+		},
+		"allowFingerprints should keep only the given certificates": {
+			filters: trustapi.BundleFilters{
+				AllowFingerprints: []string{fingerprint(t, dummy.TestCertificate1), strings.ToUpper(fingerprint(t, dummy.TestCertificate5))},
+			},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate5),
+			expExcluded: 1,
+		},
+		"denyFingerprints should take precedence over all other filters": {
+			filters: trustapi.BundleFilters{
+				Include:           []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Regex: "Root"}}},
+				AllowFingerprints: []string{fingerprint(t, dummy.TestCertificate3), fingerprint(t, dummy.TestCertificate5)},
+				DenyFingerprints:  []string{fingerprint(t, dummy.TestCertificate3)},
+			},
+			expData:     dummy.TestCertificate5,
+			expExcluded: 2,
+		},
+		"keyUsages should keep certificates valid for all usages or without key usages": {
+			filters: trustapi.BundleFilters{
+				KeyUsages: []trustapi.KeyUsage{trustapi.KeyUsageCertSign, trustapi.KeyUsageDigitalSignature},
+			},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate5),
+			expExcluded: 1,
+		},
+		"extendedKeyUsages should exclude certificates restricted to other usages": {
+			data: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestLeafCertificate),
+			filters: trustapi.BundleFilters{
+				ExtendedKeyUsages: []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageClientAuth},
+			},
+			expData:     dummy.TestCertificate1,
+			expExcluded: 1,
+		},
+		"extendedKeyUsages should keep certificates valid for the usage": {
+			data: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestLeafCertificate),
+			filters: trustapi.BundleFilters{
+				ExtendedKeyUsages: []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageServerAuth},
+			},
+			expData: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestLeafCertificate),
+		},
+		"invalid fingerprint should error": {
+			filters: trustapi.BundleFilters{
+				DenyFingerprints: []string{"not a fingerprint"},
+			},
+			expError: true,
+		},
+		"invalid regex should error": {
+			filters: trustapi.BundleFilters{
+				Exclude: []trustapi.CertificateMatch{{Subject: &trustapi.DistinguishedNameMatch{Regex: "("}}},
+			},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			input := data
+			if len(test.data) > 0 {
+				input = test.data
+			}
+
+			var resolvedBundle bundleData
+			filtered, err := excludeMatchedCertificates([]byte(input), &test.filters, &resolvedBundle)
+			if test.expError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, strings.TrimSpace(test.expData), string(filtered))
+			assert.Equal(t, test.expExcluded, resolvedBundle.excludedMatchedCertificates)
+		})
+	}
+}
+
+func Test_deduplicateCertificates(t *testing.T) {
+	// Two issuances of the same CA, sharing a key.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reissuedCA := func(serial int64) string {
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: "reissued-ca"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	}
+	first, second := reissuedCA(1), reissuedCA(2)
+
+	trim := func(data string) string {
+		return strings.TrimSpace(data)
+	}
+
+	tests := map[string]struct {
+		bundles     []weightedBundle
+		byPublicKey bool
+
+		expBundles    []weightedBundle
+		expDuplicates int
+	}{
+		"distinct certificates should be kept": {
+			bundles: []weightedBundle{
+				{data: trim(dummy.TestCertificate1)},
+				{data: trim(dummy.TestCertificate2)},
+			},
+			expBundles: []weightedBundle{
+				{data: trim(dummy.TestCertificate1)},
+				{data: trim(dummy.TestCertificate2)},
+			},
+		},
+		"identical certificates should be kept in the first source only": {
+			bundles: []weightedBundle{
+				{weight: 10, data: trim(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2))},
+				{weight: 5, data: trim(dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate3))},
+				{data: trim(dummy.TestCertificate1)},
+			},
+			expBundles: []weightedBundle{
+				{weight: 10, data: trim(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2))},
+				{weight: 5, data: trim(dummy.TestCertificate3)},
+			},
+			expDuplicates: 2,
+		},
+		"identical certificates within a source should be kept once": {
+			bundles: []weightedBundle{
+				{data: trim(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate1))},
+			},
+			expBundles: []weightedBundle{
+				{data: trim(dummy.TestCertificate1)},
+			},
+			expDuplicates: 1,
+		},
+		"certificates sharing a public key should be kept by default": {
+			bundles: []weightedBundle{
+				{data: first},
+				{data: second},
+			},
+			expBundles: []weightedBundle{
+				{data: first},
+				{data: second},
+			},
+		},
+		"certificates sharing a public key should be deduplicated by public key": {
+			bundles: []weightedBundle{
+				{data: first},
+				{data: second},
+			},
+			byPublicKey: true,
+			expBundles: []weightedBundle{
+				{data: first},
+			},
+			expDuplicates: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resolvedBundle bundleData
+			bundles, err := deduplicateCertificates(test.bundles, test.byPublicKey, &resolvedBundle)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expBundles, bundles)
+			assert.Equal(t, test.expDuplicates, resolvedBundle.duplicateCertificates)
+		})
+	}
+}
+
+func Test_selectCrossSignedCertificates(t *testing.T) {
+	// Certificates of the same CA with the same subject and key, as issued
+	// when a CA is cross-signed or re-issued.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	crossSignedCA := func(serial int64, notBefore, notAfter time.Time, subjectKeyID []byte) string {
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: "cross-signed-ca"},
+			NotBefore:             notBefore,
+			NotAfter:              notAfter,
+			SubjectKeyId:          subjectKeyID,
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	}
+	oldest := crossSignedCA(1, now.Add(-3*time.Hour), now.Add(3*time.Hour), []byte{1})
+	newest := crossSignedCA(2, now.Add(-time.Hour), now.Add(time.Hour), []byte{1})
+	longest := crossSignedCA(3, now.Add(-2*time.Hour), now.Add(5*time.Hour), []byte{1})
+	otherKeyID := crossSignedCA(4, now.Add(-time.Hour), now.Add(time.Hour), []byte{2})
+
+	tests := map[string]struct {
+		bundles []weightedBundle
+		policy  trustapi.CrossSignedPolicy
+
+		expBundles     []weightedBundle
+		expCrossSigned int
+	}{
+		"cross-signed certificates should be kept by default": {
+			bundles: []weightedBundle{
+				{data: oldest},
+				{data: newest},
+			},
+			expBundles: []weightedBundle{
+				{data: oldest},
+				{data: newest},
+			},
+			expCrossSigned: 1,
+		},
+		"cross-signed certificates should be kept with KeepAll": {
+			bundles: []weightedBundle{
+				{data: dummy.JoinCerts(oldest, newest, longest)},
+			},
+			policy: trustapi.CrossSignedPolicyKeepAll,
+			expBundles: []weightedBundle{
+				{data: dummy.JoinCerts(oldest, newest, longest)},
+			},
+			expCrossSigned: 2,
+		},
+		"newest certificate should be kept with KeepNewest": {
+			bundles: []weightedBundle{
+				{weight: 10, data: oldest},
+				{weight: 5, data: dummy.JoinCerts(newest, longest)},
+			},
+			policy: trustapi.CrossSignedPolicyKeepNewest,
+			expBundles: []weightedBundle{
+				{weight: 5, data: newest},
+			},
+			expCrossSigned: 2,
+		},
+		"longest valid certificate should be kept with KeepLongestValidity": {
+			bundles: []weightedBundle{
+				{data: dummy.JoinCerts(oldest, newest, longest)},
+			},
+			policy: trustapi.CrossSignedPolicyKeepLongestValidity,
+			expBundles: []weightedBundle{
+				{data: longest},
+			},
+			expCrossSigned: 2,
+		},
+		"certificates with different subject key identifiers should be kept": {
+			bundles: []weightedBundle{
+				{data: dummy.JoinCerts(newest, otherKeyID)},
+			},
+			policy: trustapi.CrossSignedPolicyKeepNewest,
+			expBundles: []weightedBundle{
+				{data: strings.TrimSpace(dummy.JoinCerts(newest, otherKeyID))},
+			},
+		},
+		"certificates without subject key identifiers should be kept": {
+			bundles: []weightedBundle{
+				{data: strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2))},
+			},
+			policy: trustapi.CrossSignedPolicyKeepNewest,
+			expBundles: []weightedBundle{
+				{data: strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2))},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resolvedBundle bundleData
+			bundles, err := selectCrossSignedCertificates(test.bundles, test.policy, &resolvedBundle)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expBundles, bundles)
+			assert.Equal(t, test.expCrossSigned, resolvedBundle.crossSignedCertificates)
+		})
+	}
+}
+
+func Test_excludeDefaultCAs(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate5)
+
+	block, _ := pem.Decode([]byte(dummy.TestCertificate3))
+	fingerprint := certificateFingerprint(block.Bytes)
+
+	tests := map[string]struct {
+		exclude trustapi.DefaultCAsExclusions
+
+		expData     string
+		expExcluded int
+		expError    bool
+	}{
+		"no exclusions should keep all certificates": {
+			expData: data,
+		},
+		"excluded common name should be removed": {
+			exclude:     trustapi.DefaultCAsExclusions{CommonNames: []string{"GTS Root R1"}},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3),
+			expExcluded: 1,
+		},
+		"common name should not match part of a name": {
+			exclude: trustapi.DefaultCAsExclusions{CommonNames: []string{"GTS Root"}},
+			expData: data,
+		},
+		"excluded fingerprint should be removed": {
+			exclude:     trustapi.DefaultCAsExclusions{Fingerprints: []string{strings.ToUpper(fingerprint)}},
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate5),
+			expExcluded: 1,
+		},
+		"certificates matching either a common name or a fingerprint should be removed": {
+			exclude:     trustapi.DefaultCAsExclusions{CommonNames: []string{"cmct-test-root"}, Fingerprints: []string{fingerprint}},
+			expData:     dummy.TestCertificate5,
+			expExcluded: 2,
+		},
+		"invalid fingerprint should error": {
+			exclude:  trustapi.DefaultCAsExclusions{Fingerprints: []string{"not a fingerprint"}},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resolvedBundle bundleData
+			filtered, err := excludeDefaultCAs([]byte(data), &test.exclude, &resolvedBundle)
+			if test.expError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, strings.TrimSpace(test.expData), string(filtered))
+			assert.Equal(t, test.expExcluded, resolvedBundle.excludedMatchedCertificates)
+		})
+	}
+}
+
+func Test_excludeNonCACertificates(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestLeafCertificate, dummy.TestCertificate5)
+
+	tests := map[string]struct {
+		enforce bool
+
+		expData  string
+		expNonCA int
+	}{
+		"leaf certificates should be counted but kept if not enforced": {
+			enforce:  false,
+			expData:  data,
+			expNonCA: 1,
+		},
+		"leaf certificates should be excluded if enforced": {
+			enforce:  true,
+			expData:  dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate5),
+			expNonCA: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resolvedBundle bundleData
+			filtered, err := excludeNonCACertificates([]byte(data), test.enforce, &resolvedBundle)
+			assert.NoError(t, err)
+
+			assert.Equal(t, strings.TrimSpace(test.expData), string(filtered))
+			assert.Equal(t, test.expNonCA, resolvedBundle.nonCACertificates)
+		})
+	}
+}
+
+func Test_excludeWeakCryptoCertificates(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "weak-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &weakKey.PublicKey, weakKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	weakCA := strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, weakCA, dummy.TestCertificate2)
+
+	tests := map[string]struct {
+		enforce                 bool
+		weakSignatureAlgorithms []x509.SignatureAlgorithm
+
+		expData string
+		expWeak int
+	}{
+		"weak certificates should be counted but kept if not enforced": {
+			enforce: false,
+			expData: data,
+			expWeak: 1,
+		},
+		"weak certificates should be excluded if enforced": {
+			enforce: true,
+			expData: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expWeak: 1,
+		},
+		"certificates with configured weak signature algorithms should be excluded": {
+			enforce:                 true,
+			weakSignatureAlgorithms: []x509.SignatureAlgorithm{x509.ECDSAWithSHA256},
+			expData:                 dummy.JoinCerts(dummy.TestCertificate2),
+			expWeak:                 2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var resolvedBundle bundleData
+			filtered, err := excludeWeakCryptoCertificates([]byte(data), test.enforce, 2048, test.weakSignatureAlgorithms, &resolvedBundle)
+			assert.NoError(t, err)
+
+			assert.Equal(t, strings.TrimSpace(test.expData), string(filtered))
+			assert.Equal(t, test.expWeak, resolvedBundle.weakCryptoCertificates)
+		})
+	}
+}
+
+func Test_weakCryptoPolicy(t *testing.T) {
+	tests := map[string]struct {
+		filters       *trustapi.BundleFilters
+		defaultAction trustapi.WeakCryptoAction
+
+		expAction                  trustapi.WeakCryptoAction
+		expMinRSAKeySize           int
+		expWeakSignatureAlgorithms []x509.SignatureAlgorithm
+	}{
+		"no default action or filter should ignore weak certificates": {
+			expAction:                  trustapi.WeakCryptoActionIgnore,
+			expMinRSAKeySize:           2048,
+			expWeakSignatureAlgorithms: util.DefaultWeakSignatureAlgorithms,
+		},
+		"default action should be used if the filter doesn't set an action": {
+			filters:                    &trustapi.BundleFilters{WeakCrypto: &trustapi.WeakCryptoFilter{MinRSAKeySize: 3072}},
+			defaultAction:              trustapi.WeakCryptoActionWarn,
+			expAction:                  trustapi.WeakCryptoActionWarn,
+			expMinRSAKeySize:           3072,
+			expWeakSignatureAlgorithms: util.DefaultWeakSignatureAlgorithms,
+		},
+		"filter should override the default action and algorithms": {
+			filters: &trustapi.BundleFilters{WeakCrypto: &trustapi.WeakCryptoFilter{
+				Action:                  trustapi.WeakCryptoActionIgnore,
+				WeakSignatureAlgorithms: []string{"SHA1-RSA", "SHA256-RSA"},
+			}},
+			defaultAction:              trustapi.WeakCryptoActionEnforce,
+			expAction:                  trustapi.WeakCryptoActionIgnore,
+			expMinRSAKeySize:           2048,
+			expWeakSignatureAlgorithms: []x509.SignatureAlgorithm{x509.SHA1WithRSA, x509.SHA256WithRSA},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			action, minRSAKeySize, weakSignatureAlgorithms := weakCryptoPolicy(test.filters, test.defaultAction)
+			assert.Equal(t, test.expAction, action)
+			assert.Equal(t, test.expMinRSAKeySize, minRSAKeySize)
+			assert.Equal(t, test.expWeakSignatureAlgorithms, weakSignatureAlgorithms)
+		})
+	}
+}
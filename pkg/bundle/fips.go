@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// fipsNonCompliantFormats returns the additional formats of the given target
+// which are encoded with algorithms that aren't FIPS approved. JKS
+// truststores are integrity protected with a proprietary SHA-1 based keyed
+// hash, and the legacy PKCS#12 profiles encrypt with RC2 or 3DES. The
+// Modern2023 PKCS#12 profile, which is the default, uses AES-256-CBC with
+// PBKDF2 and an HMAC-SHA-256 MAC, all of which are approved.
+func fipsNonCompliantFormats(target trustapi.BundleTarget) []string {
+	formats := target.AdditionalFormats
+	if formats == nil {
+		return nil
+	}
+
+	var nonCompliant []string
+	if formats.JKS != nil {
+		nonCompliant = append(nonCompliant, "JKS")
+	}
+
+	if formats.PKCS12 != nil {
+		switch profile := formats.PKCS12.Profile; profile {
+		case trustapi.PKCS12ProfileLegacyRC2, trustapi.PKCS12ProfileLegacyDES:
+			nonCompliant = append(nonCompliant, fmt.Sprintf("PKCS12 with the %s profile", profile))
+		}
+	}
+
+	return nonCompliant
+}
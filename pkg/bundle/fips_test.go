@@ -0,0 +1,70 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_fipsNonCompliantFormats(t *testing.T) {
+	tests := map[string]struct {
+		formats         *trustapi.AdditionalFormats
+		expNonCompliant []string
+	}{
+		"no additional formats are compliant": {
+			formats: nil,
+		},
+		"default PKCS12 profile is compliant": {
+			formats: &trustapi.AdditionalFormats{PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}}},
+		},
+		"Modern2023 PKCS12 profile is compliant": {
+			formats: &trustapi.AdditionalFormats{PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}, Profile: trustapi.PKCS12ProfileModern2023}},
+		},
+		"unencrypted formats are compliant": {
+			formats: &trustapi.AdditionalFormats{
+				PKCS7: &trustapi.KeySelector{Key: "bundle.p7b"},
+				SST:   &trustapi.KeySelector{Key: "bundle.sst"},
+			},
+		},
+		"JKS is not compliant": {
+			formats:         &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "bundle.jks"}}},
+			expNonCompliant: []string{"JKS"},
+		},
+		"JKS and legacy PKCS12 profile are not compliant": {
+			formats: &trustapi.AdditionalFormats{
+				JKS:    &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "bundle.jks"}},
+				PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}, Profile: trustapi.PKCS12ProfileLegacyDES},
+			},
+			expNonCompliant: []string{"JKS", "PKCS12 with the LegacyDES profile"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			nonCompliant := fipsNonCompliantFormats(trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: "trust.pem"},
+				AdditionalFormats: test.formats,
+			})
+			assert.Equal(t, test.expNonCompliant, nonCompliant)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// encodeGzip compresses the given bundle data. The gzip header carries no
+// modification time or name, so that the same data is always compressed to
+// the same bytes.
+func encodeGzip(data string) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write([]byte(data)); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle data: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decodeGzip decompresses bundle data compressed by encodeGzip.
+func decodeGzip(data []byte) (string, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress bundle data: %w", err)
+	}
+	defer r.Close()
+
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to decompress bundle data: %w", err)
+	}
+
+	return string(decompressed), nil
+}
+
+// gzipFormat returns the gzip additional format of the target, or nil if it
+// isn't written.
+func gzipFormat(target trustapi.BundleTarget) *trustapi.Gzip {
+	if target.AdditionalFormats == nil {
+		return nil
+	}
+	return target.AdditionalFormats.Gzip
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_encodeGzip(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	compressed, err := encodeGzip(data)
+	assert.NoError(t, err)
+
+	again, err := encodeGzip(data)
+	assert.NoError(t, err)
+	assert.Equal(t, compressed, again, "expected compressed data to be deterministic")
+
+	assert.Equal(t, data, gunzip(t, compressed))
+}
+
+func Test_syncTarget_gzip(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		gzipKey    = "trust.pem.gz"
+		data       = dummy.TestCertificate1
+	)
+
+	compressed, err := encodeGzip(data)
+	assert.NoError(t, err)
+
+	targetConfigMap := func(annotations map[string]string, data map[string]string, binaryData map[string][]byte) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        bundleName,
+				Namespace:   "test-namespace",
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: bundleName}}, trustapi.SchemeGroupVersion.WithKind("Bundle")),
+				},
+			},
+			Data:       data,
+			BinaryData: binaryData,
+		}
+	}
+	uncompressedHash := map[string]string{trustapi.TargetUncompressedHashAnnotationKey: contentHash(data)}
+
+	tests := map[string]struct {
+		object           runtime.Object
+		omitUncompressed bool
+
+		expNeedsUpdate  bool
+		expUncompressed bool
+	}{
+		"missing target should be created with compressed data": {
+			expNeedsUpdate:  true,
+			expUncompressed: true,
+		},
+		"up to date compressed data should not be updated": {
+			object:          targetConfigMap(uncompressedHash, map[string]string{key: data}, map[string][]byte{gzipKey: compressed}),
+			expUncompressed: true,
+		},
+		"stale compressed data should be updated": {
+			object:          targetConfigMap(uncompressedHash, map[string]string{key: data}, map[string][]byte{gzipKey: []byte("stale")}),
+			expNeedsUpdate:  true,
+			expUncompressed: true,
+		},
+		"missing uncompressed hash annotation should be added": {
+			object:          targetConfigMap(nil, map[string]string{key: data}, map[string][]byte{gzipKey: compressed}),
+			expNeedsUpdate:  true,
+			expUncompressed: true,
+		},
+		"missing target should be created without uncompressed data if omitted": {
+			omitUncompressed: true,
+			expNeedsUpdate:   true,
+		},
+		"uncompressed data should be removed if omitted": {
+			object:           targetConfigMap(uncompressedHash, map[string]string{key: data}, map[string][]byte{gzipKey: compressed}),
+			omitUncompressed: true,
+			expNeedsUpdate:   true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeclient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1)}
+
+			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: key},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					Gzip: &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: gzipKey}, OmitUncompressed: test.omitUncompressed},
+				},
+			}}
+
+			namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+			needsUpdate, _, err := b.syncTarget(context.TODO(), klogr.New(), &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+				Spec:       spec,
+			}, labels.Everything(), &namespace, data, "", "", "", "", nil, nil, nil, []byte(DefaultJKSPassword), "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNeedsUpdate, needsUpdate)
+
+			var configMap corev1.ConfigMap
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: namespace.Name, Name: bundleName}, &configMap))
+
+			assert.Equal(t, data, gunzip(t, configMap.BinaryData[gzipKey]))
+			assert.Equal(t, contentHash(data), configMap.Annotations[trustapi.TargetUncompressedHashAnnotationKey])
+
+			uncompressed, ok := configMap.Data[key]
+			assert.Equal(t, test.expUncompressed, ok)
+			if test.expUncompressed {
+				assert.Equal(t, data, uncompressed)
+			}
+		})
+	}
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	t.Helper()
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if !assert.NoError(t, err) {
+		return ""
+	}
+	defer r.Close()
+
+	uncompressed, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return string(uncompressed)
+}
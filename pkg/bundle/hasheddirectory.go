@@ -0,0 +1,289 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// hashedDirectoryFormat returns the packaging, the index key and the tarball
+// key of the target's hashed directory, with defaults applied, and whether the
+// target has the hashed directory format.
+func hashedDirectoryFormat(target trustapi.BundleTarget) (trustapi.HashedDirectoryPackaging, string, string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.HashedDirectory == nil {
+		return "", "", "", false
+	}
+
+	packaging := target.AdditionalFormats.HashedDirectory.Packaging
+	if len(packaging) == 0 {
+		packaging = trustapi.HashedDirectoryPackagingKeys
+	}
+
+	indexKey := target.AdditionalFormats.HashedDirectory.IndexKey
+	if len(indexKey) == 0 {
+		indexKey = trustapi.DefaultHashedDirectoryIndexKey
+	}
+
+	tarballKey := target.AdditionalFormats.HashedDirectory.TarballKey
+	if len(tarballKey) == 0 {
+		tarballKey = trustapi.DefaultHashedDirectoryTarballKey
+	}
+
+	return packaging, indexKey, tarballKey, true
+}
+
+// hashedDirectoryFile is a file of a hashed certificate directory.
+type hashedDirectoryFile struct {
+	name string
+	data string
+}
+
+// hashedDirectoryFiles returns the files of the OpenSSL hashed certificate
+// directory of the given PEM bundle. As with c_rehash, each file is named by
+// the hash of the certificate's subject name and a sequence number which
+// distinguishes certificates with the same hash, assigned in bundle order.
+func hashedDirectoryFiles(data string) ([]hashedDirectoryFile, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	sequence := make(map[uint32]int, len(certificates))
+	files := make([]hashedDirectoryFile, len(certificates))
+	for i, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		if block == nil {
+			return nil, fmt.Errorf("invalid PEM block at position %d", i)
+		}
+
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate at position %d: %w", i, err)
+		}
+
+		hash, err := opensslNameHash(c.RawSubject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash subject of certificate at position %d: %w", i, err)
+		}
+
+		files[i] = hashedDirectoryFile{name: fmt.Sprintf("%08x.%d", hash, sequence[hash]), data: string(certificate)}
+		sequence[hash]++
+	}
+
+	return files, nil
+}
+
+// encodeHashedDirectory returns the entries of the hashed directory of the
+// given PEM bundle in Keys packaging, keyed by entry key. The index entry lists
+// the keys of the certificate entries, one per line.
+func encodeHashedDirectory(data, indexKey string) (map[string]string, error) {
+	files, err := hashedDirectoryFiles(data)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := make(map[string]string, len(files)+1)
+	keys := make([]string, len(files))
+	for i, file := range files {
+		keys[i] = file.name
+		directory[file.name] = file.data
+	}
+
+	directory[indexKey] = strings.Join(keys, "\n") + "\n"
+
+	return directory, nil
+}
+
+// encodeHashedDirectoryTarball returns a tar archive of the hashed directory
+// of the given PEM bundle. The archive carries no modification times or
+// ownership, so that the same bundle is always archived to the same bytes.
+func encodeHashedDirectoryTarball(data string) ([]byte, error) {
+	files, err := hashedDirectoryFiles(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, file := range files {
+		if err := w.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Mode:     0o644,
+			Size:     int64(len(file.data)),
+			ModTime:  time.Unix(0, 0),
+			Format:   tar.FormatUSTAR,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to write hashed directory tarball: %w", err)
+		}
+		if _, err := w.Write([]byte(file.data)); err != nil {
+			return nil, fmt.Errorf("failed to write hashed directory tarball: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write hashed directory tarball: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// opensslNameHash returns the hash of the given DER encoded X.509 name, as
+// computed by OpenSSL's X509_NAME_hash and used to name the files of hashed
+// certificate directories. The hash is the first four bytes, read as a little
+// endian integer, of the SHA-1 digest of the canonical encoding of the name.
+func opensslNameHash(rawName []byte) (uint32, error) {
+	canonical, err := opensslCanonicalName(rawName)
+	if err != nil {
+		return 0, err
+	}
+
+	sum := sha1.Sum(canonical)
+	return binary.LittleEndian.Uint32(sum[:4]), nil
+}
+
+// opensslCanonicalName returns the canonical encoding of the given DER encoded
+// X.509 name, as defined by OpenSSL's x509_name_canon. The canonical encoding
+// is the concatenation of the encoded relative distinguished names, without
+// the enclosing sequence, in which string values are converted to
+// UTF8String, lowercased and stripped of redundant whitespace.
+func opensslCanonicalName(rawName []byte) ([]byte, error) {
+	var rdns []asn1.RawValue
+	if rest, err := asn1.Unmarshal(rawName, &rdns); err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, fmt.Errorf("trailing data after name")
+	}
+
+	type attributeTypeAndValue struct {
+		Type  asn1.ObjectIdentifier
+		Value asn1.RawValue
+	}
+
+	var canonical []byte
+	for _, rdn := range rdns {
+		var attributes []attributeTypeAndValue
+		if _, err := asn1.UnmarshalWithParams(rdn.FullBytes, &attributes, "set"); err != nil {
+			return nil, err
+		}
+
+		encoded := make([][]byte, len(attributes))
+		for i, attribute := range attributes {
+			if value, ok := opensslCanonicalString(attribute.Value); ok {
+				attribute.Value = asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagUTF8String, Bytes: []byte(value)}
+			}
+
+			var err error
+			encoded[i], err = asn1.Marshal(attribute)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// The elements of a DER encoded set are sorted by their encoding.
+		sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+
+		set, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: bytes.Join(encoded, nil)})
+		if err != nil {
+			return nil, err
+		}
+		canonical = append(canonical, set...)
+	}
+
+	return canonical, nil
+}
+
+// opensslCanonicalString returns the canonical form of the given string value
+// of a name attribute, as defined by OpenSSL's asn1_string_canon: leading and
+// trailing whitespace is removed, runs of whitespace are replaced with a
+// single space and ASCII characters are lowercased. Returns false if the value
+// isn't a string type which OpenSSL canonicalizes.
+func opensslCanonicalString(value asn1.RawValue) (string, bool) {
+	if value.Class != asn1.ClassUniversal {
+		return "", false
+	}
+
+	var s string
+	switch value.Tag {
+	case asn1.TagUTF8String, asn1.TagPrintableString, asn1.TagIA5String, 26: // VisibleString
+		s = string(value.Bytes)
+	case asn1.TagT61String:
+		// OpenSSL reads T61String values as ISO 8859-1.
+		runes := make([]rune, len(value.Bytes))
+		for i, b := range value.Bytes {
+			runes[i] = rune(b)
+		}
+		s = string(runes)
+	case asn1.TagBMPString:
+		units := make([]uint16, len(value.Bytes)/2)
+		for i := range units {
+			units[i] = binary.BigEndian.Uint16(value.Bytes[2*i:])
+		}
+		s = string(utf16.Decode(units))
+	case 28: // UniversalString
+		runes := make([]rune, len(value.Bytes)/4)
+		for i := range runes {
+			runes[i] = rune(binary.BigEndian.Uint32(value.Bytes[4*i:]))
+		}
+		s = string(runes)
+	default:
+		return "", false
+	}
+
+	if !utf8.ValidString(s) {
+		return "", false
+	}
+
+	isSpace := func(b byte) bool {
+		return b == ' ' || b == '\t' || b == '\n' || b == '\v' || b == '\f' || b == '\r'
+	}
+
+	s = strings.TrimFunc(s, func(r rune) bool { return r < utf8.RuneSelf && isSpace(byte(r)) })
+
+	var canonical strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; {
+		case b >= utf8.RuneSelf:
+			canonical.WriteByte(b)
+		case isSpace(b):
+			canonical.WriteByte(' ')
+			for i+1 < len(s) && isSpace(s[i+1]) {
+				i++
+			}
+		case 'A' <= b && b <= 'Z':
+			canonical.WriteByte(b + 'a' - 'A')
+		default:
+			canonical.WriteByte(b)
+		}
+	}
+
+	return canonical.String(), true
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/asn1"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_hashedDirectoryFiles(t *testing.T) {
+	// The expected names were computed with "openssl x509 -hash".
+	files, err := hashedDirectoryFiles(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate2))
+	assert.NoError(t, err)
+
+	assert.Equal(t, []hashedDirectoryFile{
+		{name: "f69c9054.0", data: dummy.TestCertificate1 + "\n"},
+		{name: "4042bcee.0", data: dummy.TestCertificate3 + "\n"},
+		{name: "f69c9054.1", data: dummy.TestCertificate2 + "\n"},
+	}, files)
+}
+
+func Test_opensslCanonicalString(t *testing.T) {
+	tests := map[string]struct {
+		value asn1.RawValue
+
+		expString string
+		expOK     bool
+	}{
+		"UTF8String should be lowercased": {
+			value:     asn1.RawValue{Tag: asn1.TagUTF8String, Bytes: []byte("Cert-Manager")},
+			expString: "cert-manager",
+			expOK:     true,
+		},
+		"whitespace should be trimmed and collapsed": {
+			value:     asn1.RawValue{Tag: asn1.TagPrintableString, Bytes: []byte("  Foo \t\n BAR  ")},
+			expString: "foo bar",
+			expOK:     true,
+		},
+		"non-ASCII characters should not be lowercased": {
+			value:     asn1.RawValue{Tag: asn1.TagUTF8String, Bytes: []byte("ÜNÏCODE Ab")},
+			expString: "ÜnÏcode ab",
+			expOK:     true,
+		},
+		"T61String should be read as ISO 8859-1": {
+			value:     asn1.RawValue{Tag: asn1.TagT61String, Bytes: []byte{'T', 0xeb, 'S', 'T'}},
+			expString: "tëst",
+			expOK:     true,
+		},
+		"BMPString should be read as UTF-16": {
+			value:     asn1.RawValue{Tag: asn1.TagBMPString, Bytes: []byte{0, 'A', 0, 'b'}},
+			expString: "ab",
+			expOK:     true,
+		},
+		"other types should not be canonicalized": {
+			value: asn1.RawValue{Tag: asn1.TagOctetString, Bytes: []byte("ABC")},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			s, ok := opensslCanonicalString(test.value)
+			assert.Equal(t, test.expOK, ok)
+			if ok {
+				assert.Equal(t, test.expString, s)
+			}
+		})
+	}
+}
+
+func Test_encodeHashedDirectoryTarball(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	tarball, err := encodeHashedDirectoryTarball(data)
+	assert.NoError(t, err)
+
+	again, err := encodeHashedDirectoryTarball(data)
+	assert.NoError(t, err)
+	assert.Equal(t, tarball, again, "expected tarball to be deterministic")
+
+	files := make(map[string]string)
+	r := tar.NewReader(bytes.NewReader(tarball))
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		files[header.Name] = string(content)
+	}
+
+	assert.Equal(t, map[string]string{
+		"f69c9054.0": dummy.TestCertificate1 + "\n",
+		"f69c9054.1": dummy.TestCertificate2 + "\n",
+	}, files)
+}
+
+func Test_syncTarget_hashedDirectory(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		data       = dummy.TestCertificate1
+	)
+
+	tarball, err := encodeHashedDirectoryTarball(data)
+	assert.NoError(t, err)
+
+	targetConfigMap := func(entries map[string]string, binaryData map[string][]byte) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundleName,
+				Namespace: "test-namespace",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: bundleName}}, trustapi.SchemeGroupVersion.WithKind("Bundle")),
+				},
+			},
+			Data:       map[string]string{key: data},
+			BinaryData: binaryData,
+		}
+		for k, v := range entries {
+			configMap.Data[k] = v
+		}
+		return configMap
+	}
+
+	tests := map[string]struct {
+		object    runtime.Object
+		packaging trustapi.HashedDirectoryPackaging
+
+		expNeedsUpdate bool
+		expData        map[string]string
+		expBinaryData  map[string][]byte
+	}{
+		"missing target should be created with hashed keys": {
+			expNeedsUpdate: true,
+			expData:        map[string]string{key: data, "f69c9054.0": data + "\n", "hashes.txt": "f69c9054.0\n"},
+		},
+		"up to date hashed keys should not be updated": {
+			object:  targetConfigMap(map[string]string{"f69c9054.0": data + "\n", "hashes.txt": "f69c9054.0\n"}, nil),
+			expData: map[string]string{key: data, "f69c9054.0": data + "\n", "hashes.txt": "f69c9054.0\n"},
+		},
+		"hashed keys of removed certificates should be removed": {
+			object:         targetConfigMap(map[string]string{"f69c9054.0": data + "\n", "4042bcee.0": "old", "hashes.txt": "f69c9054.0\n4042bcee.0\n"}, nil),
+			expNeedsUpdate: true,
+			expData:        map[string]string{key: data, "f69c9054.0": data + "\n", "hashes.txt": "f69c9054.0\n"},
+		},
+		"missing target should be created with tarball": {
+			packaging:      trustapi.HashedDirectoryPackagingTarball,
+			expNeedsUpdate: true,
+			expData:        map[string]string{key: data},
+			expBinaryData:  map[string][]byte{"certs.tar": tarball},
+		},
+		"up to date tarball should not be updated": {
+			object:        targetConfigMap(nil, map[string][]byte{"certs.tar": tarball}),
+			packaging:     trustapi.HashedDirectoryPackagingTarball,
+			expData:       map[string]string{key: data},
+			expBinaryData: map[string][]byte{"certs.tar": tarball},
+		},
+		"stale tarball should be updated": {
+			object:         targetConfigMap(nil, map[string][]byte{"certs.tar": []byte("stale")}),
+			packaging:      trustapi.HashedDirectoryPackagingTarball,
+			expNeedsUpdate: true,
+			expData:        map[string]string{key: data},
+			expBinaryData:  map[string][]byte{"certs.tar": tarball},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeclient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1)}
+
+			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: key},
+				AdditionalFormats: &trustapi.AdditionalFormats{HashedDirectory: &trustapi.HashedDirectory{Packaging: test.packaging}},
+			}}
+
+			namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+			needsUpdate, _, err := b.syncTarget(context.TODO(), klogr.New(), &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+				Spec:       spec,
+			}, labels.Everything(), &namespace, data, "", "", "", "", nil, nil, nil, []byte(DefaultJKSPassword), "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNeedsUpdate, needsUpdate)
+
+			var configMap corev1.ConfigMap
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: namespace.Name, Name: bundleName}, &configMap))
+
+			assert.Equal(t, test.expData, configMap.Data)
+			assert.Equal(t, test.expBinaryData, configMap.BinaryData)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// sourceHealthStore holds the result of the last probe of the external
+// sources of each Bundle in memory, keyed by Bundle name.
+type sourceHealthStore struct {
+	lock   sync.Mutex
+	health map[string][]trustapi.SourceHealth
+}
+
+func (s *sourceHealthStore) get(bundle string) []trustapi.SourceHealth {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.health[bundle]
+}
+
+// set stores the health of the sources of the given Bundle, returning the
+// previously stored health.
+func (s *sourceHealthStore) set(bundle string, health []trustapi.SourceHealth) []trustapi.SourceHealth {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.health == nil {
+		s.health = make(map[string][]trustapi.SourceHealth)
+	}
+	previous := s.health[bundle]
+	if len(health) == 0 {
+		delete(s.health, bundle)
+	} else {
+		s.health[bundle] = health
+	}
+	return previous
+}
+
+// sourceHealthProber periodically probes the availability of the external
+// sources of all Bundles, independently of their refresh. Bundles whose
+// source health changed are sent to the events channel, so that the change is
+// written to their status.
+type sourceHealthProber struct {
+	bundle *bundle
+	period time.Duration
+	events chan<- event.GenericEvent
+}
+
+// Start probes the sources of all Bundles periodically until the context is
+// cancelled.
+func (p *sourceHealthProber) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, p.probe, p.period)
+	return nil
+}
+
+// NeedLeaderElection returns true, since only the leader reconciles Bundles.
+func (p *sourceHealthProber) NeedLeaderElection() bool {
+	return true
+}
+
+// probe probes the sources of all Bundles once.
+func (p *sourceHealthProber) probe(ctx context.Context) {
+	var bundleList trustapi.BundleList
+	if err := p.bundle.sourceLister.List(ctx, &bundleList); err != nil {
+		p.bundle.Log.Error(err, "failed to list Bundles to probe source health")
+		return
+	}
+
+	for i := range bundleList.Items {
+		bundle := &bundleList.Items[i]
+
+		health := p.bundle.probeSources(ctx, bundle, p.bundle.sourceHealth.get(bundle.Name))
+		previous := p.bundle.sourceHealth.set(bundle.Name, health)
+		p.bundle.metrics.sourceHealthProbed(bundle.Name, previous, health)
+
+		if apiequality.Semantic.DeepEqual(previous, health) {
+			continue
+		}
+
+		select {
+		case p.events <- event.GenericEvent{Object: bundle}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// probeSources probes the availability of each external source of the given
+// Bundle. The transition time of sources whose health is unchanged from the
+// given previous health is kept.
+func (b *bundle) probeSources(ctx context.Context, bundle *trustapi.Bundle, previous []trustapi.SourceHealth) []trustapi.SourceHealth {
+	var health []trustapi.SourceHealth
+	for i, source := range bundle.Spec.Sources {
+		var err error
+		switch {
+		case source.ObjectStorage != nil:
+			err = b.probeObjectStorage(ctx, source.ObjectStorage)
+		case source.RemoteCluster != nil:
+			err = b.probeRemoteCluster(ctx, source.RemoteCluster)
+		default:
+			continue
+		}
+
+		sourceHealth := trustapi.SourceHealth{
+			Index:              int32(i),
+			Healthy:            err == nil,
+			LastTransitionTime: metav1.NewTime(b.clock.Now().UTC().Truncate(time.Second)),
+		}
+		if err != nil {
+			sourceHealth.Message = err.Error()
+		}
+
+		for _, prev := range previous {
+			if prev.Index == sourceHealth.Index && prev.Healthy == sourceHealth.Healthy {
+				sourceHealth.LastTransitionTime = prev.LastTransitionTime
+			}
+		}
+
+		health = append(health, sourceHealth)
+	}
+
+	return health
+}
+
+// probeObjectStorage checks that the object referenced by the object storage
+// source exists and is accessible, without downloading it.
+func (b *bundle) probeObjectStorage(ctx context.Context, ref *trustapi.SourceObjectStorage) error {
+	ctx, cancel := context.WithTimeout(ctx, objectStorageTimeout)
+	defer cancel()
+
+	req, objectURL, err := b.objectStorageRequest(ctx, http.MethodHead, ref, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to probe object %s: %w", objectURL.Redacted(), err)
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return fmt.Errorf("object %s was not found", objectURL.Redacted())
+	default:
+		return fmt.Errorf("failed to probe object %s: unexpected status %q", objectURL.Redacted(), resp.Status)
+	}
+}
+
+// probeRemoteCluster checks that the source object in the remote cluster can
+// be read.
+func (b *bundle) probeRemoteCluster(ctx context.Context, ref *trustapi.SourceRemoteCluster) error {
+	ctx, cancel := context.WithTimeout(ctx, objectStorageTimeout)
+	defer cancel()
+
+	_, err := b.remoteClusterBundle(ctx, ref)
+	return err
+}
+
+// setBundleStatusSourceHealth sets the source health of the Bundle's status
+// to the result of the last probe of its sources. Returns true if the status
+// was changed.
+func (b *bundle) setBundleStatusSourceHealth(bundle *trustapi.Bundle) bool {
+	health := b.sourceHealth.get(bundle.Name)
+	if apiequality.Semantic.DeepEqual(bundle.Status.SourceHealth, health) {
+		return false
+	}
+
+	bundle.Status.SourceHealth = health
+	return true
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclock "k8s.io/utils/clock/testing"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_probeSources(t *testing.T) {
+	fixedTime := time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
+	previousTime := metav1.NewTime(fixedTime.Add(-time.Hour))
+
+	objectStorageSource := func(key string) trustapi.BundleSource {
+		return trustapi.BundleSource{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: key}}
+	}
+
+	tests := map[string]struct {
+		sources  []trustapi.BundleSource
+		previous []trustapi.SourceHealth
+
+		expHealth   []trustapi.SourceHealth
+		expMessages []string
+	}{
+		"Bundle without external sources should have no source health": {
+			sources: []trustapi.BundleSource{{InLine: new(string)}},
+		},
+		"existing object should be healthy": {
+			sources: []trustapi.BundleSource{{InLine: new(string)}, objectStorageSource("ca.pem")},
+			expHealth: []trustapi.SourceHealth{
+				{Index: 1, Healthy: true, LastTransitionTime: metav1.NewTime(fixedTime)},
+			},
+			expMessages: []string{""},
+		},
+		"missing and failing objects should be unhealthy": {
+			sources: []trustapi.BundleSource{objectStorageSource("missing.pem"), objectStorageSource("broken.pem")},
+			expHealth: []trustapi.SourceHealth{
+				{Index: 0, Healthy: false, LastTransitionTime: metav1.NewTime(fixedTime)},
+				{Index: 1, Healthy: false, LastTransitionTime: metav1.NewTime(fixedTime)},
+			},
+			expMessages: []string{"was not found", "unexpected status"},
+		},
+		"unchanged health should keep its transition time": {
+			sources: []trustapi.BundleSource{objectStorageSource("ca.pem"), objectStorageSource("missing.pem")},
+			previous: []trustapi.SourceHealth{
+				{Index: 0, Healthy: true, LastTransitionTime: previousTime},
+				{Index: 1, Healthy: true, LastTransitionTime: previousTime},
+			},
+			expHealth: []trustapi.SourceHealth{
+				{Index: 0, Healthy: true, LastTransitionTime: previousTime},
+				{Index: 1, Healthy: false, LastTransitionTime: metav1.NewTime(fixedTime)},
+			},
+			expMessages: []string{"", "was not found"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotMethods []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotMethods = append(gotMethods, r.Method)
+				switch {
+				case strings.HasSuffix(r.URL.Path, "missing.pem"):
+					w.WriteHeader(http.StatusNotFound)
+				case strings.HasSuffix(r.URL.Path, "broken.pem"):
+					w.WriteHeader(http.StatusInternalServerError)
+				}
+			}))
+			defer server.Close()
+
+			for _, source := range test.sources {
+				if source.ObjectStorage != nil {
+					source.ObjectStorage.Endpoint = server.URL
+				}
+			}
+
+			b := &bundle{
+				sourceLister:        fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).Build(),
+				clock:               fakeclock.NewFakeClock(fixedTime),
+				objectStorageClient: server.Client(),
+			}
+
+			health := b.probeSources(context.TODO(), &trustapi.Bundle{Spec: trustapi.BundleSpec{Sources: test.sources}}, test.previous)
+
+			var gotMessages []string
+			for i := range health {
+				gotMessages = append(gotMessages, health[i].Message)
+				health[i].Message = ""
+			}
+
+			assert.Equal(t, test.expHealth, health)
+			assert.Equal(t, len(test.expMessages), len(gotMessages))
+			for i := range gotMessages {
+				if len(test.expMessages[i]) == 0 {
+					assert.Empty(t, gotMessages[i])
+				} else {
+					assert.Contains(t, gotMessages[i], test.expMessages[i])
+				}
+			}
+
+			// Probing should not download the objects.
+			for _, method := range gotMethods {
+				assert.Equal(t, http.MethodHead, method)
+			}
+		})
+	}
+}
+
+func Test_setBundleStatusSourceHealth(t *testing.T) {
+	health := []trustapi.SourceHealth{{Index: 0, Healthy: true}}
+
+	b := new(bundle)
+	bundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"}}
+
+	assert.False(t, b.setBundleStatusSourceHealth(bundle), "Bundle without probed sources should not be changed")
+
+	b.sourceHealth.set("test-bundle", health)
+	assert.True(t, b.setBundleStatusSourceHealth(bundle))
+	assert.Equal(t, health, bundle.Status.SourceHealth)
+	assert.False(t, b.setBundleStatusSourceHealth(bundle), "unchanged source health should not change the Bundle")
+
+	b.sourceHealth.set("test-bundle", nil)
+	assert.True(t, b.setBundleStatusSourceHealth(bundle))
+	assert.Empty(t, bundle.Status.SourceHealth)
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// immutableHistoryLimit returns the number of immutable versions of the
+// target which are kept in each Namespace.
+func immutableHistoryLimit(target *trustapi.TargetImmutable) int {
+	if target.HistoryLimit > 0 {
+		return int(target.HistoryLimit)
+	}
+	return trustapi.DefaultImmutableHistoryLimit
+}
+
+// immutableVersionName returns the name of the immutable version of the given
+// target ConfigMap, which includes the hash of the ConfigMap's data and
+// binary data.
+func immutableVersionName(targetName string, configMap *corev1.ConfigMap) (string, error) {
+	hash := sha256.New()
+	for _, key := range sortedKeys(configMap.Data) {
+		fmt.Fprintf(hash, "%s\x00%s\x00", key, configMap.Data[key])
+	}
+
+	binaryKeys := make([]string, 0, len(configMap.BinaryData))
+	for key := range configMap.BinaryData {
+		binaryKeys = append(binaryKeys, key)
+	}
+	sort.Strings(binaryKeys)
+	for _, key := range binaryKeys {
+		fmt.Fprintf(hash, "%s\x00%s\x00", key, configMap.BinaryData[key])
+	}
+
+	name := targetName + "-" + hex.EncodeToString(hash.Sum(nil))[:10]
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return "", fmt.Errorf("invalid name %q of immutable target version: %s", name, strings.Join(errs, ", "))
+	}
+
+	return name, nil
+}
+
+// sortedKeys returns the keys of the given map in sorted order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// syncImmutableTarget writes the content of the Bundle's target ConfigMap in
+// the given Namespace to an immutable ConfigMap named after its hash, and
+// points the target ConfigMap's current version annotation at it. Versions
+// beyond the target's history limit are deleted, oldest first. If the
+// Namespace doesn't match the target's selector, all versions are deleted.
+// Returns true if any ConfigMap was changed.
+func (b *bundle) syncImmutableTarget(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	namespaceSelector namespaceMatcher,
+	namespace *corev1.Namespace,
+) (bool, error) {
+	targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return false, err
+	}
+
+	if !namespaceSelector.Matches(labels.Set(namespace.Labels)) || namespaceSkipsTargets(namespace) {
+		return b.pruneImmutableVersions(ctx, bundle, namespace.Name, targetName, "", 0)
+	}
+
+	var target corev1.ConfigMap
+	err = b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: targetName}, &target)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace.Name, targetName, err)
+	}
+
+	versionName, err := immutableVersionName(targetName, &target)
+	if err != nil {
+		return false, err
+	}
+
+	var changed bool
+	var version corev1.ConfigMap
+	err = b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: versionName}, &version)
+	switch {
+	case apierrors.IsNotFound(err):
+		immutable := true
+		version = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      versionName,
+				Namespace: namespace.Name,
+				Labels: map[string]string{
+					b.Naming.BundleLabelKey():        bundle.Name,
+					trustapi.TargetVersionOfLabelKey: targetName,
+				},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+			},
+			Immutable:  &immutable,
+			Data:       target.Data,
+			BinaryData: target.BinaryData,
+		}
+
+		if err := b.targetDirectClient.Create(ctx, &version); err != nil {
+			return false, fmt.Errorf("failed to create immutable configmap %s/%s: %w", namespace.Name, versionName, err)
+		}
+
+		log.V(2).Info("created immutable target version", "version", versionName)
+		changed = true
+
+	case err != nil:
+		return false, fmt.Errorf("failed to get immutable configmap %s/%s: %w", namespace.Name, versionName, err)
+
+	case !metav1.IsControlledBy(&version, bundle):
+		return false, fmt.Errorf("immutable configmap %s/%s already exists and is not controlled by the Bundle", namespace.Name, versionName)
+	}
+
+	if target.Annotations[trustapi.TargetCurrentVersionAnnotationKey] != versionName {
+		metav1.SetMetaDataAnnotation(&target.ObjectMeta, trustapi.TargetCurrentVersionAnnotationKey, versionName)
+		if err := b.targetDirectClient.Update(ctx, &target); err != nil {
+			return changed, fmt.Errorf("failed to update configmap %s/%s: %w", namespace.Name, targetName, err)
+		}
+		changed = true
+	}
+
+	pruned, err := b.pruneImmutableVersions(ctx, bundle, namespace.Name, targetName, versionName, immutableHistoryLimit(bundle.Spec.Target.Immutable))
+	return changed || pruned, err
+}
+
+// pruneImmutableVersions deletes the immutable versions of the named target
+// in the given Namespace which are controlled by the Bundle, keeping the
+// current version and the most recent older versions up to the given limit,
+// including the current version. Returns true if any version was deleted.
+func (b *bundle) pruneImmutableVersions(ctx context.Context, bundle *trustapi.Bundle, namespace, targetName, current string, limit int) (bool, error) {
+	var versionList corev1.ConfigMapList
+	if err := b.targetDirectClient.List(ctx, &versionList, client.InNamespace(namespace), client.MatchingLabels{trustapi.TargetVersionOfLabelKey: targetName}); err != nil {
+		return false, fmt.Errorf("failed to list immutable versions of configmap %s/%s: %w", namespace, targetName, err)
+	}
+
+	var versions []corev1.ConfigMap
+	for _, version := range versionList.Items {
+		if version.Name == current || !metav1.IsControlledBy(&version, bundle) {
+			continue
+		}
+		versions = append(versions, version)
+	}
+
+	// Keep the most recent versions, ordered by name for versions created at
+	// the same time.
+	sort.Slice(versions, func(i, j int) bool {
+		if !versions[i].CreationTimestamp.Equal(&versions[j].CreationTimestamp) {
+			return versions[j].CreationTimestamp.Before(&versions[i].CreationTimestamp)
+		}
+		return versions[i].Name < versions[j].Name
+	})
+
+	keep := limit
+	if len(current) > 0 {
+		keep--
+	}
+	if keep < 0 {
+		keep = 0
+	}
+
+	var pruned bool
+	for i := keep; i < len(versions); i++ {
+		if err := b.targetDirectClient.Delete(ctx, &versions[i]); err != nil && !apierrors.IsNotFound(err) {
+			return pruned, fmt.Errorf("failed to delete immutable configmap %s/%s: %w", namespace, versions[i].Name, err)
+		}
+		pruned = true
+	}
+
+	return pruned, nil
+}
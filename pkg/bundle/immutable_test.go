@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_syncImmutableTarget(t *testing.T) {
+	trustBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust", UID: "bundle-uid"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				Immutable: &trustapi.TargetImmutable{HistoryLimit: 2},
+			},
+		},
+	}
+	ownerRef := *metav1.NewControllerRef(trustBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))
+
+	target := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust", Namespace: "app", OwnerReferences: []metav1.OwnerReference{ownerRef}},
+		Data:       map[string]string{"ca.crt": "data"},
+	}
+	current, err := immutableVersionName("trust", target)
+	require.NoError(t, err)
+
+	pointingTarget := target.DeepCopy()
+	pointingTarget.Annotations = map[string]string{trustapi.TargetCurrentVersionAnnotationKey: current}
+
+	version := func(name string, age time.Duration, ownerRefs ...metav1.OwnerReference) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "app",
+				Labels:            map[string]string{trustapi.TargetVersionOfLabelKey: "trust"},
+				OwnerReferences:   ownerRefs,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		objects  []runtime.Object
+		selector labels.Selector
+
+		expVersions []string
+		expPointer  string
+		expChanged  bool
+		expError    bool
+	}{
+		"missing version should be created and pointed to": {
+			objects:     []runtime.Object{target},
+			selector:    labels.Everything(),
+			expVersions: []string{current},
+			expPointer:  current,
+			expChanged:  true,
+		},
+		"up to date version should not be changed": {
+			objects:     []runtime.Object{pointingTarget, version(current, time.Hour, ownerRef)},
+			selector:    labels.Everything(),
+			expVersions: []string{current},
+			expPointer:  current,
+		},
+		"previous version within the history limit should be kept": {
+			objects:     []runtime.Object{target, version("trust-previous", time.Hour, ownerRef)},
+			selector:    labels.Everything(),
+			expVersions: []string{current, "trust-previous"},
+			expPointer:  current,
+			expChanged:  true,
+		},
+		"oldest versions beyond the history limit should be deleted": {
+			objects: []runtime.Object{pointingTarget, version(current, time.Minute, ownerRef),
+				version("trust-older", 2*time.Hour, ownerRef), version("trust-newer", time.Hour, ownerRef),
+			},
+			selector:    labels.Everything(),
+			expVersions: []string{current, "trust-newer"},
+			expPointer:  current,
+			expChanged:  true,
+		},
+		"versions not controlled by the Bundle should be kept": {
+			objects: []runtime.Object{pointingTarget, version(current, time.Minute, ownerRef),
+				version("trust-older", 2*time.Hour), version("trust-newer", time.Hour, ownerRef),
+			},
+			selector:    labels.Everything(),
+			expVersions: []string{current, "trust-newer", "trust-older"},
+			expPointer:  current,
+		},
+		"existing version not controlled by the Bundle should fail": {
+			objects:     []runtime.Object{target, version(current, time.Hour)},
+			selector:    labels.Everything(),
+			expVersions: []string{current},
+			expError:    true,
+		},
+		"versions in unselected Namespace should be deleted": {
+			objects:     []runtime.Object{pointingTarget, version(current, time.Minute, ownerRef), version("trust-unowned", time.Hour)},
+			selector:    labels.Nothing(),
+			expVersions: []string{"trust-unowned"},
+			expPointer:  current,
+			expChanged:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).WithRuntimeObjects(test.objects...).Build()
+
+			b := &bundle{targetDirectClient: fakeClient}
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+
+			changed, err := b.syncImmutableTarget(context.TODO(), logr.Discard(), trustBundle, test.selector, namespace)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expChanged, changed)
+
+			var versionList corev1.ConfigMapList
+			require.NoError(t, fakeClient.List(context.TODO(), &versionList, client.MatchingLabels{trustapi.TargetVersionOfLabelKey: "trust"}))
+
+			var versions []string
+			for _, version := range versionList.Items {
+				versions = append(versions, version.Name)
+			}
+			sort.Strings(versions)
+			expVersions := append([]string(nil), test.expVersions...)
+			sort.Strings(expVersions)
+			assert.Equal(t, expVersions, versions)
+
+			var configMap corev1.ConfigMap
+			require.NoError(t, fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: "trust"}, &configMap))
+			assert.Equal(t, test.expPointer, configMap.Annotations[trustapi.TargetCurrentVersionAnnotationKey])
+		})
+	}
+}
+
+func Test_immutableVersionName(t *testing.T) {
+	configMap := &corev1.ConfigMap{
+		Data:       map[string]string{"ca.crt": "data"},
+		BinaryData: map[string][]byte{"bundle.jks": []byte("jks")},
+	}
+
+	name, err := immutableVersionName("trust", configMap)
+	require.NoError(t, err)
+	assert.Regexp(t, `^trust-[0-9a-f]{10}$`, name)
+
+	changed := configMap.DeepCopy()
+	changed.BinaryData["bundle.jks"] = []byte("other")
+	changedName, err := immutableVersionName("trust", changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, name, changedName, "expected changed binary data to change the version name")
+
+	_, err = immutableVersionName(strings.Repeat("a", 250), configMap)
+	assert.Error(t, err, "expected error for version name which is too long")
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// istioConfigLabelKey is the label which istiod sets on the Istio root
+// certificate ConfigMaps it writes.
+const istioConfigLabelKey = "istio.io/config"
+
+// syncIstioTarget writes the bundle data to the Istio root certificate
+// ConfigMap in the given Namespace, owned by the Bundle. Existing ConfigMaps
+// which aren't controlled by another object, such as those written by
+// istiod, are adopted. If the Namespace doesn't match the target's selector,
+// the ConfigMap is deleted if it is controlled by the Bundle. Returns true if
+// the ConfigMap was changed.
+func (b *bundle) syncIstioTarget(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	namespaceSelector namespaceMatcher,
+	namespace *corev1.Namespace,
+	data string,
+) (bool, error) {
+	if !namespaceSelector.Matches(labels.Set(namespace.Labels)) || namespaceSkipsTargets(namespace) {
+		return b.deleteIstioTarget(ctx, bundle, namespace.Name)
+	}
+
+	var configMap corev1.ConfigMap
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: trustapi.IstioRootCertConfigMapName}, &configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            trustapi.IstioRootCertConfigMapName,
+				Namespace:       namespace.Name,
+				Labels:          map[string]string{istioConfigLabelKey: "true"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+			},
+			Data: map[string]string{trustapi.IstioRootCertConfigMapKey: data},
+		}
+
+		if err := b.targetDirectClient.Create(ctx, &configMap); err != nil {
+			return false, fmt.Errorf("failed to create Istio root certificate configmap %s/%s: %w", namespace.Name, configMap.Name, err)
+		}
+
+		log.V(2).Info("created Istio root certificate configmap")
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get Istio root certificate configmap %s/%s: %w", namespace.Name, trustapi.IstioRootCertConfigMapName, err)
+	}
+
+	var needsUpdate bool
+	if !metav1.IsControlledBy(&configMap, bundle) {
+		if controller := metav1.GetControllerOf(&configMap); controller != nil {
+			return false, fmt.Errorf("Istio root certificate configmap %s/%s is controlled by %s %q", namespace.Name, configMap.Name, controller.Kind, controller.Name)
+		}
+
+		configMap.OwnerReferences = append(configMap.OwnerReferences, *metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle")))
+		needsUpdate = true
+	}
+
+	if configMap.Labels[istioConfigLabelKey] != "true" {
+		metav1.SetMetaDataLabel(&configMap.ObjectMeta, istioConfigLabelKey, "true")
+		needsUpdate = true
+	}
+
+	if existing, ok := configMap.Data[trustapi.IstioRootCertConfigMapKey]; !ok || existing != data {
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[trustapi.IstioRootCertConfigMapKey] = data
+		needsUpdate = true
+	}
+
+	if !needsUpdate {
+		return false, nil
+	}
+
+	if err := b.targetDirectClient.Update(ctx, &configMap); err != nil {
+		return false, fmt.Errorf("failed to update Istio root certificate configmap %s/%s: %w", namespace.Name, configMap.Name, err)
+	}
+
+	log.V(2).Info("synced Istio root certificate configmap")
+	return true, nil
+}
+
+// deleteIstioTarget deletes the Istio root certificate ConfigMap in the given
+// Namespace if it is controlled by the Bundle. Returns true if the ConfigMap
+// was deleted.
+func (b *bundle) deleteIstioTarget(ctx context.Context, bundle *trustapi.Bundle, namespace string) (bool, error) {
+	var configMap corev1.ConfigMap
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: trustapi.IstioRootCertConfigMapName}, &configMap)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get Istio root certificate configmap %s/%s: %w", namespace, trustapi.IstioRootCertConfigMapName, err)
+	}
+
+	if !metav1.IsControlledBy(&configMap, bundle) {
+		return false, nil
+	}
+
+	if err := b.targetDirectClient.Delete(ctx, &configMap); err != nil && !apierrors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to delete Istio root certificate configmap %s/%s: %w", namespace, configMap.Name, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_syncIstioTarget(t *testing.T) {
+	data := dummy.TestCertificate1
+
+	trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "trust", UID: "bundle-uid"}}
+	ownerRef := *metav1.NewControllerRef(trustBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))
+	istioConfigMap := func(data string, ownerRefs ...metav1.OwnerReference) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "istio-ca-root-cert",
+				Namespace:       "app",
+				Labels:          map[string]string{"istio.io/config": "true"},
+				OwnerReferences: ownerRefs,
+			},
+			Data: map[string]string{"root-cert.pem": data},
+		}
+	}
+
+	tests := map[string]struct {
+		object   runtime.Object
+		selector labels.Selector
+
+		expData    *corev1.ConfigMap
+		expChanged bool
+		expError   bool
+	}{
+		"missing ConfigMap should be created": {
+			selector:   labels.Everything(),
+			expData:    istioConfigMap(data, ownerRef),
+			expChanged: true,
+		},
+		"ConfigMap written by istiod should be adopted": {
+			object:     istioConfigMap("istiod-root"),
+			selector:   labels.Everything(),
+			expData:    istioConfigMap(data, ownerRef),
+			expChanged: true,
+		},
+		"up to date ConfigMap should not be changed": {
+			object:   istioConfigMap(data, ownerRef),
+			selector: labels.Everything(),
+			expData:  istioConfigMap(data, ownerRef),
+		},
+		"ConfigMap controlled by another object should fail": {
+			object:   istioConfigMap("other", metav1.OwnerReference{APIVersion: "v1", Kind: "Other", Name: "other", UID: "other-uid", Controller: &[]bool{true}[0]}),
+			selector: labels.Everything(),
+			expData:  istioConfigMap("other", metav1.OwnerReference{APIVersion: "v1", Kind: "Other", Name: "other", UID: "other-uid", Controller: &[]bool{true}[0]}),
+			expError: true,
+		},
+		"ConfigMap in unselected Namespace should be deleted": {
+			object:     istioConfigMap(data, ownerRef),
+			selector:   labels.Nothing(),
+			expChanged: true,
+		},
+		"ConfigMap not owned by the Bundle in unselected Namespace should be kept": {
+			object:   istioConfigMap("istiod-root"),
+			selector: labels.Nothing(),
+			expData:  istioConfigMap("istiod-root"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeClient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeClient}
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "app"}}
+
+			changed, err := b.syncIstioTarget(context.TODO(), logr.Discard(), trustBundle, test.selector, namespace, data)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expChanged, changed)
+
+			var configMap corev1.ConfigMap
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "app", Name: "istio-ca-root-cert"}, &configMap)
+			if test.expData == nil {
+				assert.True(t, apierrors.IsNotFound(err), "expected ConfigMap to be deleted: %v", err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData.Labels, configMap.Labels)
+			assert.Equal(t, test.expData.OwnerReferences, configMap.OwnerReferences)
+			assert.Equal(t, test.expData.Data, configMap.Data)
+		})
+	}
+}
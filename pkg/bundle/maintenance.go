@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// contentHash returns the hash of the given bundle data, as stored in the
+// Bundle status to track the content applied to targets.
+func contentHash(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// maintenanceWindowOpen returns true if any of the given maintenance windows
+// is open at the given time. If no window is open, the time at which the next
+// window opens is also returned.
+func maintenanceWindowOpen(windows []trustapi.MaintenanceWindow, now time.Time) (bool, time.Time, error) {
+	var next time.Time
+	for _, window := range windows {
+		schedule, err := util.ParseSchedule(window.Schedule)
+		if err != nil {
+			return false, time.Time{}, fmt.Errorf("invalid maintenance window %q: %w", window.Schedule, err)
+		}
+
+		if util.WindowOpen(schedule, window.Duration.Duration, now) {
+			return true, time.Time{}, nil
+		}
+
+		if opens := schedule.Next(now); next.IsZero() || opens.Before(next) {
+			next = opens
+		}
+	}
+
+	return false, next, nil
+}
+
+// maintenanceWindowData returns the bundle data which should be synced to the
+// targets of the given Bundle, which defines maintenance windows. If the
+// resolved data differs from the previously applied content and no
+// maintenance window is open, the previously applied content is returned so
+// that targets can still be created and repaired, along with the time at
+// which the deferred change may be applied. If the previously applied content
+// can't be read back from any target, no data is returned along with that
+// time, in which case the targets must not be synced until the change may be
+// applied.
+func (b *bundle) maintenanceWindowData(ctx context.Context, log logr.Logger,
+	bundle *trustapi.Bundle,
+	namespaces []corev1.Namespace,
+	data string,
+) (string, *time.Time, error) {
+	appliedHash := bundle.Status.AppliedContentHash
+	if len(appliedHash) == 0 || appliedHash == contentHash(data) {
+		return data, nil, nil
+	}
+
+	open, next, err := maintenanceWindowOpen(bundle.Spec.MaintenanceWindows, b.clock.Now())
+	if err != nil {
+		return "", nil, err
+	}
+
+	if open {
+		log.V(2).Info("maintenance window is open, applying content change")
+		return data, nil, nil
+	}
+
+	// The previously applied content isn't stored, so look it up from any
+	// unmodified target.
+	applied, ok, err := b.appliedTargetData(ctx, bundle, namespaces, appliedHash)
+	if err != nil {
+		return "", nil, err
+	}
+	if ok {
+		log.V(2).Info("deferring content change until next maintenance window", "next", next)
+		return applied, &next, nil
+	}
+
+	// Without the previously applied content, targets can't be repaired
+	// without applying the change, so they aren't synced at all until the
+	// next maintenance window.
+	log.Info("previously applied content was not found in any target, deferring sync until next maintenance window", "next", next)
+
+	return "", &next, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	fakeclock "k8s.io/utils/clock/testing"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_maintenanceWindowData(t *testing.T) {
+	applied := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+	data := dummy.DefaultJoinedCerts()
+
+	appliedGzip, err := encodeGzip(applied)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appliedPartitions, err := partitionBundleData(applied, len(applied), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, appliedPartitions, 2) {
+		return
+	}
+	appliedEntries := partitionEntries("test-bundle", "target-key", trustapi.DefaultPartitionIndexKey, appliedPartitions)
+
+	// The maintenance window opens on Saturdays, and the clock is set to a
+	// Friday.
+	now := time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
+	nextWindow := time.Date(2021, 01, 02, 02, 0, 0, 0, time.UTC)
+
+	baseBundle := &trustapi.Bundle{
+		TypeMeta:   metav1.TypeMeta{Kind: "Bundle", APIVersion: "trust.cert-manager.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "test-bundle", UID: "123"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "target-key"}},
+			MaintenanceWindows: []trustapi.MaintenanceWindow{
+				{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			},
+		},
+		Status: trustapi.BundleStatus{AppliedContentHash: contentHash(applied)},
+	}
+	ownerRefs := []metav1.OwnerReference{*metav1.NewControllerRef(baseBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))}
+
+	namespaces := []corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "ns-1"}}}
+
+	tests := map[string]struct {
+		modifyTarget    func(target *trustapi.BundleTarget)
+		existingObjects []runtime.Object
+
+		expData          string
+		expDeferredUntil *time.Time
+	}{
+		"content change should be deferred with the applied content of a PEM target": {
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key": applied},
+				},
+			},
+			expData:          applied,
+			expDeferredUntil: &nextWindow,
+		},
+		"content change should be deferred with the applied content of a DER target": {
+			modifyTarget: func(target *trustapi.BundleTarget) {
+				target.ConfigMap.Format = trustapi.TargetFormatDER
+			},
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					BinaryData: map[string][]byte{"target-key": dummy.JoinCertsDER(dummy.TestCertificate1, dummy.TestCertificate2)},
+				},
+			},
+			expData:          applied,
+			expDeferredUntil: &nextWindow,
+		},
+		"content change should be deferred with the applied content of a gzip target omitting the uncompressed data": {
+			modifyTarget: func(target *trustapi.BundleTarget) {
+				target.AdditionalFormats = &trustapi.AdditionalFormats{
+					Gzip: &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: "target-key.gz"}, OmitUncompressed: true},
+				}
+			},
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					BinaryData: map[string][]byte{"target-key.gz": appliedGzip},
+				},
+			},
+			expData:          applied,
+			expDeferredUntil: &nextWindow,
+		},
+		"content change should be deferred with the applied content of a partitioned target": {
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       appliedEntries,
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle-1", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key-1": appliedPartitions[1]},
+				},
+			},
+			expData:          applied,
+			expDeferredUntil: &nextWindow,
+		},
+		"content change should be deferred without data if a partition of the applied content is missing": {
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       appliedEntries,
+				},
+			},
+			expData:          "",
+			expDeferredUntil: &nextWindow,
+		},
+		"content change should be deferred without data if no target holds the applied content": {
+			existingObjects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns-1", Name: "test-bundle", OwnerReferences: ownerRefs},
+					Data:       map[string]string{"target-key": dummy.JoinCerts(dummy.TestCertificate3)},
+				},
+			},
+			expData:          "",
+			expDeferredUntil: &nextWindow,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{
+				targetDirectClient: fakeclient.NewClientBuilder().
+					WithScheme(trustapi.GlobalScheme).
+					WithRuntimeObjects(test.existingObjects...).
+					Build(),
+				clock: fakeclock.NewFakeClock(now),
+			}
+
+			bundle := baseBundle.DeepCopy()
+			if test.modifyTarget != nil {
+				test.modifyTarget(&bundle.Spec.Target)
+			}
+
+			gotData, deferredUntil, err := b.maintenanceWindowData(context.TODO(), klogr.New(), bundle, namespaces, data)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData, gotData)
+			assert.Equal(t, test.expDeferredUntil, deferredUntil)
+		})
+	}
+}
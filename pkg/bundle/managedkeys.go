@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// managedTargetKeysAnnotation is the annotation set on target ConfigMaps
+// recording the keys written by the controller, as a sorted, comma separated
+// list of "data:<key>", "binaryData:<key>" and "directory:<index key>"
+// entries. It is used to prune the keys of formats which are removed from the
+// Bundle's target, without touching keys written by others.
+const managedTargetKeysAnnotation = "trust.cert-manager.io/managed-keys"
+
+// managedTargetKeys returns the entries of the managed keys annotation for the
+// given target, whose bundle data is written to the given data entries, and
+// whose binary formats are written to the given binary keys.
+func managedTargetKeys(target trustapi.BundleTarget, entries map[string]string, binaryKeys []string) []string {
+	managed := make([]string, 0, len(entries)+len(binaryKeys))
+	for key := range entries {
+		managed = append(managed, "data:"+key)
+	}
+	for _, key := range binaryKeys {
+		managed = append(managed, "binaryData:"+key)
+	}
+
+	if key, ok := buildTimestampKey(target); ok {
+		managed = append(managed, "data:"+key)
+	}
+	if key, ok := metadataKey(target); ok {
+		managed = append(managed, "data:"+key)
+	}
+	if key, ok := spiffeBundleKey(target); ok {
+		managed = append(managed, "data:"+key)
+	}
+	if key, ok := provenanceKey(target); ok {
+		managed = append(managed, "data:"+key)
+	}
+	for _, profile := range targetProfiles(target) {
+		managed = append(managed, "data:"+profile.Key)
+	}
+
+	// The entries of directories are tracked by their index, so only the
+	// index is recorded.
+	if _, indexKey, ok := pemDirectoryKeys(target); ok {
+		managed = append(managed, "directory:"+indexKey)
+	}
+	if packaging, indexKey, _, ok := hashedDirectoryFormat(target); ok && packaging != trustapi.HashedDirectoryPackagingTarball {
+		managed = append(managed, "directory:"+indexKey)
+	}
+
+	sort.Strings(managed)
+
+	return managed
+}
+
+// pruneManagedTargetKeys removes the keys which were previously written to
+// the target ConfigMap by the controller, but are no longer among the given
+// managed keys. Returns true if the ConfigMap was changed.
+func pruneManagedTargetKeys(configMap *corev1.ConfigMap, managed []string) bool {
+	current := make(map[string]struct{}, len(managed))
+	for _, entry := range managed {
+		current[entry] = struct{}{}
+	}
+
+	var changed bool
+
+	for _, entry := range strings.Split(configMap.Annotations[managedTargetKeysAnnotation], ",") {
+		if _, ok := current[entry]; ok {
+			continue
+		}
+
+		kind, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "data":
+			if _, ok := configMap.Data[key]; ok {
+				delete(configMap.Data, key)
+				changed = true
+			}
+		case "binaryData":
+			if _, ok := configMap.BinaryData[key]; ok {
+				delete(configMap.BinaryData, key)
+				changed = true
+			}
+		case "directory":
+			if syncPEMDirectory(configMap, key, nil) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
+
+// setManagedTargetKeys records the given managed keys on the target
+// ConfigMap. It is only called when the ConfigMap is written anyway, so that
+// targets written before the keys were recorded aren't all updated at once.
+func setManagedTargetKeys(configMap *corev1.ConfigMap, managed []string) {
+	if len(managed) == 0 {
+		delete(configMap.Annotations, managedTargetKeysAnnotation)
+		return
+	}
+
+	metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, managedTargetKeysAnnotation, strings.Join(managed, ","))
+}
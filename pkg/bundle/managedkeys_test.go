@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_managedTargetKeys(t *testing.T) {
+	target := trustapi.BundleTarget{
+		ConfigMap: &trustapi.TargetKeySelector{Key: "trust.pem"},
+		AdditionalFormats: &trustapi.AdditionalFormats{
+			JKS:          &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "trust.jks"}},
+			PEMDirectory: &trustapi.PEMDirectory{},
+		},
+	}
+
+	managed := managedTargetKeys(target, map[string]string{"trust.pem": "data"}, targetBinaryKeys(target))
+	_, indexKey, _ := pemDirectoryKeys(target)
+	assert.Equal(t, []string{"binaryData:trust.jks", "data:trust.pem", "directory:" + indexKey}, managed)
+}
+
+func Test_pruneManagedTargetKeys(t *testing.T) {
+	tests := map[string]struct {
+		annotations   map[string]string
+		data          map[string]string
+		binaryData    map[string][]byte
+		managed       []string
+		expData       map[string]string
+		expBinaryData map[string][]byte
+		expChanged    bool
+	}{
+		"target without managed keys should not be pruned": {
+			data:       map[string]string{"trust.pem": "data", "other": "value"},
+			binaryData: map[string][]byte{"trust.jks": []byte("jks")},
+			managed:    []string{"data:trust.pem"},
+			expData:    map[string]string{"trust.pem": "data", "other": "value"},
+			expBinaryData: map[string][]byte{
+				"trust.jks": []byte("jks"),
+			},
+		},
+		"managed keys which are still managed should be kept": {
+			annotations:   map[string]string{managedTargetKeysAnnotation: "binaryData:trust.jks,data:trust.pem"},
+			data:          map[string]string{"trust.pem": "data"},
+			binaryData:    map[string][]byte{"trust.jks": []byte("jks")},
+			managed:       []string{"binaryData:trust.jks", "data:trust.pem"},
+			expData:       map[string]string{"trust.pem": "data"},
+			expBinaryData: map[string][]byte{"trust.jks": []byte("jks")},
+		},
+		"keys which are no longer managed should be pruned, leaving keys of others": {
+			annotations: map[string]string{managedTargetKeysAnnotation: "binaryData:trust.jks,data:metadata.json,data:trust.pem"},
+			data:        map[string]string{"trust.pem": "data", "metadata.json": "{}", "other": "value"},
+			binaryData:  map[string][]byte{"trust.jks": []byte("jks"), "other.bin": []byte("value")},
+			managed:     []string{"data:trust.pem"},
+			expData:     map[string]string{"trust.pem": "data", "other": "value"},
+			expBinaryData: map[string][]byte{
+				"other.bin": []byte("value"),
+			},
+			expChanged: true,
+		},
+		"directories which are no longer managed should be pruned": {
+			annotations: map[string]string{managedTargetKeysAnnotation: "data:trust.pem,directory:index"},
+			data:        map[string]string{"trust.pem": "data", "index": "cert-0.pem cert-1.pem", "cert-0.pem": "0", "cert-1.pem": "1"},
+			managed:     []string{"data:trust.pem"},
+			expData:     map[string]string{"trust.pem": "data"},
+			expChanged:  true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Data:       test.data,
+				BinaryData: test.binaryData,
+			}
+
+			changed := pruneManagedTargetKeys(configMap, test.managed)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expData, configMap.Data)
+			assert.Equal(t, test.expBinaryData, configMap.BinaryData)
+
+			setManagedTargetKeys(configMap, test.managed)
+			assert.Equal(t, strings.Join(test.managed, ","), configMap.Annotations[managedTargetKeysAnnotation])
+		})
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// bundleMetadata is the JSON document written to the metadata target format.
+type bundleMetadata struct {
+	Certificates []certificateMetadata `json:"certificates"`
+}
+
+// certificateMetadata describes a single certificate in the bundle.
+type certificateMetadata struct {
+	// Fingerprint is the hex encoded SHA-256 digest of the DER certificate.
+	Fingerprint string `json:"fingerprint"`
+
+	Subject  string    `json:"subject"`
+	NotAfter time.Time `json:"notAfter"`
+
+	// Labels are the labels of the sources the certificate came from.
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// PEM is the PEM encoding of the certificate, if requested.
+	PEM string `json:"pem,omitempty"`
+}
+
+// certificateFingerprint returns the hex encoded SHA-256 digest of the given
+// DER certificate.
+func certificateFingerprint(der []byte) string {
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:])
+}
+
+// addCertificateLabels records the given source labels against each
+// certificate in the given PEM bundle. Where the same certificate is provided
+// by multiple sources, the labels are merged, with later sources taking
+// precedence.
+func addCertificateLabels(certificateLabels map[string]map[string]string, data []byte, labels map[string]string) error {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return err
+	}
+
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		fingerprint := certificateFingerprint(block.Bytes)
+
+		if certificateLabels[fingerprint] == nil {
+			certificateLabels[fingerprint] = make(map[string]string, len(labels))
+		}
+		for k, v := range labels {
+			certificateLabels[fingerprint][k] = v
+		}
+	}
+
+	return nil
+}
+
+// encodeMetadata returns the JSON metadata document describing each
+// certificate in the given PEM bundle, in bundle order. Certificates appearing
+// more than once in the bundle are only described once. If includePEM is true,
+// the PEM encoding of each certificate is included in its description.
+func encodeMetadata(data string, certificateLabels map[string]map[string]string, includePEM bool) (string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	metadata := bundleMetadata{Certificates: []certificateMetadata{}}
+	seen := make(map[string]struct{}, len(certificates))
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		fingerprint := certificateFingerprint(block.Bytes)
+		if _, ok := seen[fingerprint]; ok {
+			continue
+		}
+		seen[fingerprint] = struct{}{}
+
+		description := certificateMetadata{
+			Fingerprint: fingerprint,
+			Subject:     cert.Subject.String(),
+			NotAfter:    cert.NotAfter.UTC(),
+			Labels:      certificateLabels[fingerprint],
+		}
+		if includePEM {
+			description.PEM = string(certificate)
+		}
+
+		metadata.Certificates = append(metadata.Certificates, description)
+	}
+
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	return string(encoded) + "\n", nil
+}
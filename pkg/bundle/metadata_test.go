@@ -0,0 +1,130 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_encodeMetadata(t *testing.T) {
+	fingerprint := func(t *testing.T, certificate string) string {
+		block, _ := pem.Decode([]byte(certificate))
+		if block == nil {
+			t.Fatal("failed to decode PEM certificate")
+		}
+		return certificateFingerprint(block.Bytes)
+	}
+
+	tests := map[string]struct {
+		sources    [][]string
+		labels     []map[string]string
+		includePEM bool
+
+		expFingerprints []string
+		expSubjects     []string
+		expLabels       []map[string]string
+		expPEMs         []string
+	}{
+		"unlabelled source should produce certificates without labels": {
+			sources:         [][]string{{dummy.TestCertificate1, dummy.TestCertificate3}},
+			labels:          []map[string]string{nil},
+			expFingerprints: []string{fingerprint(t, dummy.TestCertificate1), fingerprint(t, dummy.TestCertificate3)},
+			expSubjects:     []string{"CN=cmct-test-root,O=cert-manager", "CN=ISRG Root X1,O=Internet Security Research Group,C=US"},
+			expLabels:       []map[string]string{nil, nil},
+			expPEMs:         []string{"", ""},
+		},
+		"includePEM should include the PEM encoding of each certificate": {
+			sources:         [][]string{{dummy.TestCertificate1, dummy.TestCertificate3}},
+			labels:          []map[string]string{nil},
+			includePEM:      true,
+			expFingerprints: []string{fingerprint(t, dummy.TestCertificate1), fingerprint(t, dummy.TestCertificate3)},
+			expSubjects:     []string{"CN=cmct-test-root,O=cert-manager", "CN=ISRG Root X1,O=Internet Security Research Group,C=US"},
+			expLabels:       []map[string]string{nil, nil},
+			expPEMs:         []string{dummy.TestCertificate1 + "\n", dummy.TestCertificate3 + "\n"},
+		},
+		"labelled sources should carry labels onto their certificates": {
+			sources: [][]string{{dummy.TestCertificate1}, {dummy.TestCertificate3}},
+			labels: []map[string]string{
+				{"purpose": "mtls-internal"},
+				{"purpose": "public"},
+			},
+			expFingerprints: []string{fingerprint(t, dummy.TestCertificate1), fingerprint(t, dummy.TestCertificate3)},
+			expSubjects:     []string{"CN=cmct-test-root,O=cert-manager", "CN=ISRG Root X1,O=Internet Security Research Group,C=US"},
+			expLabels: []map[string]string{
+				{"purpose": "mtls-internal"},
+				{"purpose": "public"},
+			},
+			expPEMs: []string{"", ""},
+		},
+		"certificate from multiple sources should be described once with merged labels": {
+			sources: [][]string{{dummy.TestCertificate1}, {dummy.TestCertificate1}},
+			labels: []map[string]string{
+				{"purpose": "mtls-internal", "team": "platform"},
+				{"purpose": "mtls-external"},
+			},
+			expFingerprints: []string{fingerprint(t, dummy.TestCertificate1)},
+			expSubjects:     []string{"CN=cmct-test-root,O=cert-manager"},
+			expLabels: []map[string]string{
+				{"purpose": "mtls-external", "team": "platform"},
+			},
+			expPEMs: []string{""},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificateLabels := make(map[string]map[string]string)
+			var data string
+			for i, source := range test.sources {
+				sourceData := dummy.JoinCerts(source...)
+				if err := addCertificateLabels(certificateLabels, []byte(sourceData), test.labels[i]); err != nil {
+					t.Fatal(err)
+				}
+				data += sourceData + "\n"
+			}
+
+			encoded, err := encodeMetadata(data, certificateLabels, test.includePEM)
+			assert.NoError(t, err)
+
+			var metadata bundleMetadata
+			if err := json.Unmarshal([]byte(encoded), &metadata); err != nil {
+				t.Fatal(err)
+			}
+
+			var fingerprints, subjects, pems []string
+			var labels []map[string]string
+			for _, certificate := range metadata.Certificates {
+				fingerprints = append(fingerprints, certificate.Fingerprint)
+				subjects = append(subjects, certificate.Subject)
+				labels = append(labels, certificate.Labels)
+				pems = append(pems, certificate.PEM)
+				assert.False(t, certificate.NotAfter.IsZero(), "expected notAfter to be set")
+			}
+
+			assert.Equal(t, test.expFingerprints, fingerprints)
+			assert.Equal(t, test.expSubjects, subjects)
+			assert.Equal(t, test.expLabels, labels)
+			assert.Equal(t, test.expPEMs, pems)
+		})
+	}
+}
@@ -0,0 +1,227 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// OpenMetricsPath is the path on the metrics server which serves metrics in
+// the OpenMetrics format, including exemplars.
+const OpenMetricsPath = "/metrics/openmetrics"
+
+// DefaultSyncFailureDetailLimit is the default maximum number of failing
+// Bundle and Namespace pairs exposed by the sync failure detail metric.
+const DefaultSyncFailureDetailLimit = 100
+
+// failingTarget identifies a Bundle which failed to sync, and the Namespace
+// it failed to sync to. Namespace is empty for failures affecting the whole
+// Bundle.
+type failingTarget struct {
+	bundle    string
+	namespace string
+}
+
+// metrics holds the metrics of the Bundle controller. The sync failure counter
+// is partitioned only by reason, with exemplars linking each increment to the
+// failing Bundle and Namespace. The failing detail gauge exposes the failing
+// Bundle and Namespace pairs themselves, bounded by a limit to keep the
+// cardinality of the metric under control.
+type metrics struct {
+	syncFailures           *prometheus.CounterVec
+	syncFailing            *prometheus.GaugeVec
+	syncFailingDropped     prometheus.Counter
+	sourceHealthy          *prometheus.GaugeVec
+	syncFailureDetailLimit int
+
+	lock    sync.Mutex
+	failing map[failingTarget]struct{}
+}
+
+// newMetrics returns the metrics of the Bundle controller, registered with
+// the given registerer.
+func newMetrics(registerer prometheus.Registerer, syncFailureDetailLimit int) (*metrics, error) {
+	m := &metrics{
+		syncFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "trust_manager",
+			Subsystem: "bundle",
+			Name:      "sync_failures_total",
+			Help:      "Number of failed Bundle syncs by reason. Exemplars identify the failing Bundle and Namespace.",
+		}, []string{"reason"}),
+		syncFailing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "trust_manager",
+			Subsystem: "bundle",
+			Name:      "sync_failing",
+			Help:      "Set to 1 for each Bundle and target Namespace which is currently failing to sync. The Namespace is empty for failures affecting the whole Bundle.",
+		}, []string{"bundle", "namespace", "reason"}),
+		syncFailingDropped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: "trust_manager",
+			Subsystem: "bundle",
+			Name:      "sync_failing_dropped_total",
+			Help:      "Number of failing Bundle and Namespace pairs not exposed by trust_manager_bundle_sync_failing because the detail limit was reached.",
+		}),
+		sourceHealthy: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "trust_manager",
+			Subsystem: "bundle",
+			Name:      "source_healthy",
+			Help:      "Set to 1 if the last probe of a Bundle's external source succeeded, and 0 otherwise. The source is the index of the source in the Bundle.",
+		}, []string{"bundle", "source"}),
+		syncFailureDetailLimit: syncFailureDetailLimit,
+		failing:                make(map[failingTarget]struct{}),
+	}
+
+	var err error
+	if m.syncFailures, err = register(registerer, m.syncFailures); err != nil {
+		return nil, err
+	}
+	if m.syncFailing, err = register(registerer, m.syncFailing); err != nil {
+		return nil, err
+	}
+	if m.syncFailingDropped, err = register(registerer, m.syncFailingDropped); err != nil {
+		return nil, err
+	}
+	if m.sourceHealthy, err = register(registerer, m.sourceHealthy); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// register registers the given collector with the registerer. If an
+// equivalent collector has already been registered, for example by a previous
+// controller in the same process, the existing collector is returned instead.
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) (T, error) {
+	err := registerer.Register(collector)
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+			return existing, nil
+		}
+	}
+
+	return collector, err
+}
+
+// syncFailed records a failure to sync the given Bundle to the given
+// Namespace, or the whole Bundle if namespace is empty.
+func (m *metrics) syncFailed(bundle, namespace, reason string) {
+	if m == nil {
+		return
+	}
+
+	counter := m.syncFailures.WithLabelValues(reason)
+	exemplar := prometheus.Labels{"bundle": bundle}
+	if len(namespace) > 0 {
+		exemplar["namespace"] = namespace
+	}
+
+	// Exemplars which are too large are rejected, so only increment the
+	// counter in that case.
+	if adder, ok := counter.(prometheus.ExemplarAdder); ok && exemplarRunes(exemplar) <= prometheus.ExemplarMaxRunes {
+		adder.AddWithExemplar(1, exemplar)
+	} else {
+		counter.Inc()
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	target := failingTarget{bundle: bundle, namespace: namespace}
+	if _, ok := m.failing[target]; !ok {
+		if len(m.failing) >= m.syncFailureDetailLimit {
+			m.syncFailingDropped.Inc()
+			return
+		}
+		m.failing[target] = struct{}{}
+	}
+
+	// Only the latest reason is exposed for each failing pair.
+	m.syncFailing.DeletePartialMatch(prometheus.Labels{"bundle": bundle, "namespace": namespace})
+	m.syncFailing.WithLabelValues(bundle, namespace, reason).Set(1)
+}
+
+// syncSucceeded clears all recorded failures of the given Bundle, which has
+// either synced successfully or no longer exists.
+func (m *metrics) syncSucceeded(bundle string) {
+	if m == nil {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for target := range m.failing {
+		if target.bundle == bundle {
+			delete(m.failing, target)
+		}
+	}
+
+	m.syncFailing.DeletePartialMatch(prometheus.Labels{"bundle": bundle})
+}
+
+// sourceHealthProbed records the health of the external sources of the given
+// Bundle, removing sources which were previously probed but no longer are.
+func (m *metrics) sourceHealthProbed(bundle string, previous, current []trustapi.SourceHealth) {
+	if m == nil {
+		return
+	}
+
+	probed := make(map[int32]struct{}, len(current))
+	for _, health := range current {
+		probed[health.Index] = struct{}{}
+
+		var healthy float64
+		if health.Healthy {
+			healthy = 1
+		}
+		m.sourceHealthy.WithLabelValues(bundle, strconv.Itoa(int(health.Index))).Set(healthy)
+	}
+
+	for _, health := range previous {
+		if _, ok := probed[health.Index]; !ok {
+			m.sourceHealthy.DeleteLabelValues(bundle, strconv.Itoa(int(health.Index)))
+		}
+	}
+}
+
+// sourceHealthDeleted removes the recorded source health of the given Bundle,
+// which no longer exists.
+func (m *metrics) sourceHealthDeleted(bundle string) {
+	if m == nil {
+		return
+	}
+
+	m.sourceHealthy.DeletePartialMatch(prometheus.Labels{"bundle": bundle})
+}
+
+// exemplarRunes returns the number of runes in the names and values of the
+// given exemplar labels.
+func exemplarRunes(labels prometheus.Labels) int {
+	var runes int
+	for name, value := range labels {
+		runes += utf8.RuneCountInString(name) + utf8.RuneCountInString(value)
+	}
+	return runes
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_metrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := newMetrics(registry, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.syncFailed("bundle-a", "ns-1", "SyncTargetFailed")
+	m.syncFailed("bundle-a", "ns-1", "SyncTargetFailed")
+	m.syncFailed("bundle-b", "", "SourceNotFound")
+	// Exceeds the detail limit, so is only counted.
+	m.syncFailed("bundle-c", "ns-2", "SyncTargetFailed")
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP trust_manager_bundle_sync_failing Set to 1 for each Bundle and target Namespace which is currently failing to sync. The Namespace is empty for failures affecting the whole Bundle.
+# TYPE trust_manager_bundle_sync_failing gauge
+trust_manager_bundle_sync_failing{bundle="bundle-a",namespace="ns-1",reason="SyncTargetFailed"} 1
+trust_manager_bundle_sync_failing{bundle="bundle-b",namespace="",reason="SourceNotFound"} 1
+# HELP trust_manager_bundle_sync_failing_dropped_total Number of failing Bundle and Namespace pairs not exposed by trust_manager_bundle_sync_failing because the detail limit was reached.
+# TYPE trust_manager_bundle_sync_failing_dropped_total counter
+trust_manager_bundle_sync_failing_dropped_total 1
+# HELP trust_manager_bundle_sync_failures_total Number of failed Bundle syncs by reason. Exemplars identify the failing Bundle and Namespace.
+# TYPE trust_manager_bundle_sync_failures_total counter
+trust_manager_bundle_sync_failures_total{reason="SourceNotFound"} 1
+trust_manager_bundle_sync_failures_total{reason="SyncTargetFailed"} 3
+`)))
+
+	// The latest exemplar links the counter to the failing Bundle and Namespace.
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "trust_manager_bundle_sync_failures_total" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			if metric.GetLabel()[0].GetValue() != "SyncTargetFailed" {
+				continue
+			}
+			labels := make(map[string]string)
+			for _, label := range metric.GetCounter().GetExemplar().GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			assert.Equal(t, map[string]string{"bundle": "bundle-c", "namespace": "ns-2"}, labels)
+		}
+	}
+
+	// A failure with a new reason replaces the previous reason, and
+	// successful syncs clear the Bundle's failures, freeing up detail slots.
+	m.syncFailed("bundle-b", "", "SourceBuildError")
+	m.syncSucceeded("bundle-a")
+	m.syncFailed("bundle-c", "ns-2", "SyncTargetFailed")
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP trust_manager_bundle_sync_failing Set to 1 for each Bundle and target Namespace which is currently failing to sync. The Namespace is empty for failures affecting the whole Bundle.
+# TYPE trust_manager_bundle_sync_failing gauge
+trust_manager_bundle_sync_failing{bundle="bundle-b",namespace="",reason="SourceBuildError"} 1
+trust_manager_bundle_sync_failing{bundle="bundle-c",namespace="ns-2",reason="SyncTargetFailed"} 1
+`), "trust_manager_bundle_sync_failing"))
+}
+
+func Test_metrics_sourceHealth(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := newMetrics(registry, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	probed := []trustapi.SourceHealth{{Index: 0, Healthy: true}, {Index: 2, Healthy: false}}
+	m.sourceHealthProbed("bundle-a", nil, probed)
+	m.sourceHealthProbed("bundle-b", nil, []trustapi.SourceHealth{{Index: 1, Healthy: true}})
+
+	// Sources which are no longer probed are removed.
+	m.sourceHealthProbed("bundle-a", probed, []trustapi.SourceHealth{{Index: 0, Healthy: false}})
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP trust_manager_bundle_source_healthy Set to 1 if the last probe of a Bundle's external source succeeded, and 0 otherwise. The source is the index of the source in the Bundle.
+# TYPE trust_manager_bundle_source_healthy gauge
+trust_manager_bundle_source_healthy{bundle="bundle-a",source="0"} 0
+trust_manager_bundle_source_healthy{bundle="bundle-b",source="1"} 1
+`), "trust_manager_bundle_source_healthy"))
+
+	m.sourceHealthDeleted("bundle-a")
+
+	assert.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP trust_manager_bundle_source_healthy Set to 1 if the last probe of a Bundle's external source succeeded, and 0 otherwise. The source is the index of the source in the Bundle.
+# TYPE trust_manager_bundle_source_healthy gauge
+trust_manager_bundle_source_healthy{bundle="bundle-b",source="1"} 1
+`), "trust_manager_bundle_source_healthy"))
+}
+
+func Test_metrics_nil(t *testing.T) {
+	var m *metrics
+	m.syncFailed("bundle", "namespace", "SyncTargetFailed")
+	m.syncSucceeded("bundle")
+	m.sourceHealthProbed("bundle", nil, []trustapi.SourceHealth{{Index: 0, Healthy: true}})
+	m.sourceHealthDeleted("bundle")
+}
+
+func Test_newMetrics_alreadyRegistered(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first, err := newMetrics(registry, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := newMetrics(registry, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second.syncFailed("bundle", "", "SourceNotFound")
+	assert.Equal(t, float64(1), testutil.ToFloat64(first.syncFailures.WithLabelValues("SourceNotFound")))
+}
@@ -0,0 +1,417 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// objectStorageTimeout is the maximum time taken to fetch an object from
+	// a blob store.
+	objectStorageTimeout = 30 * time.Second
+
+	// objectStorageMaxSize is the maximum size of an object fetched from a
+	// blob store.
+	objectStorageMaxSize = 10 << 20
+
+	// defaultS3Region is the region used for S3 buckets when none is given.
+	defaultS3Region = "us-east-1"
+
+	// Keys of the credentials Secret of an object storage source.
+	objectStorageAccessKeyIDKey     = "accessKeyID"
+	objectStorageSecretAccessKeyKey = "secretAccessKey"
+	objectStorageSessionTokenKey    = "sessionToken"
+	objectStorageSASTokenKey        = "sasToken"
+
+	// emptyPayloadHash is the hex encoded SHA-256 digest of an empty payload.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// cachedObject is an object fetched from a blob store, along with the ETag
+// with which it was served.
+type cachedObject struct {
+	etag string
+	data string
+}
+
+// objectStorageCache caches objects fetched from blob stores by URL, so that
+// unchanged objects are not downloaded again. Objects are evicted once no
+// Bundle references them.
+type objectStorageCache struct {
+	lock    sync.Mutex
+	objects map[string]cachedObject
+
+	// references holds the URLs of the objects referenced by each Bundle.
+	references map[string]sets.Set[string]
+}
+
+func (c *objectStorageCache) get(url string) (cachedObject, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	object, ok := c.objects[url]
+	return object, ok
+}
+
+func (c *objectStorageCache) set(url string, object cachedObject) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.objects == nil {
+		c.objects = make(map[string]cachedObject)
+	}
+	c.objects[url] = object
+}
+
+// setReferences sets the URLs of the objects referenced by the given Bundle,
+// and evicts the objects which are no longer referenced by any Bundle.
+func (c *objectStorageCache) setReferences(bundle string, urls sets.Set[string]) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.references == nil {
+		c.references = make(map[string]sets.Set[string])
+	}
+	if urls.Len() == 0 {
+		delete(c.references, bundle)
+	} else {
+		c.references[bundle] = urls
+	}
+
+	for url := range c.objects {
+		var referenced bool
+		for _, references := range c.references {
+			if references.Has(url) {
+				referenced = true
+				break
+			}
+		}
+		if !referenced {
+			delete(c.objects, url)
+		}
+	}
+}
+
+// objectStorageURLs returns the URLs of the objects referenced by the object
+// storage sources of the given Bundle.
+func objectStorageURLs(bundle *trustapi.Bundle) sets.Set[string] {
+	urls := sets.New[string]()
+	for _, source := range bundle.Spec.Sources {
+		if source.ObjectStorage == nil {
+			continue
+		}
+		// Invalid sources fail when they are fetched.
+		if objectURL, _, err := objectStorageURL(source.ObjectStorage); err == nil {
+			urls.Insert(objectURL.String())
+		}
+	}
+	return urls
+}
+
+// objectStorageCredentials are the credentials used to fetch an object from a
+// blob store.
+type objectStorageCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	sasToken        string
+}
+
+// objectStorageBundle returns the data of the object referenced by the
+// object storage source. The ETag of previously fetched objects is sent with
+// the request, so that unchanged objects are served from the cache.
+func (b *bundle) objectStorageBundle(ctx context.Context, ref *trustapi.SourceObjectStorage) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, objectStorageTimeout)
+	defer cancel()
+
+	req, objectURL, err := b.objectStorageRequest(ctx, http.MethodGet, ref, nil, nil)
+	if err != nil {
+		return "", err
+	}
+
+	cached, isCached := b.objectCache.get(objectURL.String())
+	if isCached && len(cached.etag) > 0 {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch object %s: %w", objectURL.Redacted(), err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		if isCached {
+			return cached.data, nil
+		}
+		return "", fmt.Errorf("object %s was not modified, but is not cached", objectURL.Redacted())
+	case http.StatusNotFound:
+		return "", notFoundError{fmt.Errorf("object %s was not found", objectURL.Redacted())}
+	default:
+		return "", fmt.Errorf("failed to fetch object %s: unexpected status %q", objectURL.Redacted(), resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, objectStorageMaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read object %s: %w", objectURL.Redacted(), err)
+	}
+	if len(data) > objectStorageMaxSize {
+		return "", fmt.Errorf("object %s is larger than the maximum size of %d bytes", objectURL.Redacted(), objectStorageMaxSize)
+	}
+
+	object := cachedObject{etag: resp.Header.Get("ETag"), data: decodeSourceData(data)}
+	b.objectCache.set(objectURL.String(), object)
+
+	return object.data, nil
+}
+
+// objectStorageRequest returns an authenticated request with the given method,
+// body and headers for the object referenced by the object storage source,
+// along with the URL of the object.
+func (b *bundle) objectStorageRequest(ctx context.Context, method string, ref *trustapi.SourceObjectStorage, body []byte, header http.Header) (*http.Request, *url.URL, error) {
+	creds, err := b.objectStorageCredentials(ctx, ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objectURL, region, err := objectStorageURL(ref)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build request for object %s: %w", objectURL.Redacted(), err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	switch {
+	case ref.Provider == trustapi.ObjectStorageProviderAzureBlob:
+		req.Header.Set("x-ms-version", "2021-08-06")
+		if len(creds.sasToken) > 0 {
+			req.URL.RawQuery = strings.TrimPrefix(creds.sasToken, "?")
+		}
+	case len(creds.accessKeyID) > 0:
+		if len(body) > 0 {
+			req.Header.Set("x-amz-content-sha256", contentHash(string(body)))
+		}
+		signV4(req, creds, region, b.clock.Now())
+	}
+
+	return req, objectURL, nil
+}
+
+// objectStorageCredentials returns the credentials referenced by the object
+// storage source, if any.
+func (b *bundle) objectStorageCredentials(ctx context.Context, ref *trustapi.SourceObjectStorage) (objectStorageCredentials, error) {
+	if len(ref.CredentialsSecret) == 0 {
+		return objectStorageCredentials{}, nil
+	}
+
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.CredentialsSecret}, &secret)
+	if apierrors.IsNotFound(err) {
+		return objectStorageCredentials{}, notFoundError{err}
+	}
+	if err != nil {
+		return objectStorageCredentials{}, fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.CredentialsSecret, err)
+	}
+
+	creds := objectStorageCredentials{
+		accessKeyID:     string(secret.Data[objectStorageAccessKeyIDKey]),
+		secretAccessKey: string(secret.Data[objectStorageSecretAccessKeyKey]),
+		sessionToken:    string(secret.Data[objectStorageSessionTokenKey]),
+		sasToken:        string(secret.Data[objectStorageSASTokenKey]),
+	}
+
+	var required []string
+	if ref.Provider == trustapi.ObjectStorageProviderAzureBlob {
+		required = []string{objectStorageSASTokenKey}
+	} else {
+		required = []string{objectStorageAccessKeyIDKey, objectStorageSecretAccessKeyKey}
+	}
+	for _, key := range required {
+		if len(secret.Data[key]) == 0 {
+			return objectStorageCredentials{}, notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, ref.CredentialsSecret, key)}
+		}
+	}
+
+	return creds, nil
+}
+
+// httpClient returns the HTTP client used to fetch objects from blob stores
+// and to push artifacts to OCI registries.
+func (b *bundle) httpClient() *http.Client {
+	if b.objectStorageClient != nil {
+		return b.objectStorageClient
+	}
+	return http.DefaultClient
+}
+
+// objectStorageURL returns the URL of the object referenced by the object
+// storage source, along with the region used to sign requests.
+func objectStorageURL(ref *trustapi.SourceObjectStorage) (*url.URL, string, error) {
+	region := ref.Region
+	if len(region) == 0 {
+		region = defaultS3Region
+	}
+
+	key := strings.TrimPrefix(ref.Key, "/")
+
+	if len(ref.Endpoint) > 0 {
+		endpoint, err := url.Parse(ref.Endpoint)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid object storage endpoint %q: %w", ref.Endpoint, err)
+		}
+		if ref.Provider == trustapi.ObjectStorageProviderGCS && len(ref.Region) == 0 {
+			region = "auto"
+		}
+		return endpoint.JoinPath(ref.Bucket, key), region, nil
+	}
+
+	var base string
+	switch ref.Provider {
+	case trustapi.ObjectStorageProviderS3:
+		base = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", ref.Bucket, region)
+	case trustapi.ObjectStorageProviderGCS:
+		base, region = fmt.Sprintf("https://storage.googleapis.com/%s", ref.Bucket), "auto"
+	case trustapi.ObjectStorageProviderAzureBlob:
+		base = fmt.Sprintf("https://%s.blob.core.windows.net/%s", ref.Account, ref.Bucket)
+	default:
+		return nil, "", fmt.Errorf("unknown object storage provider %q", ref.Provider)
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid object storage URL %q: %w", base, err)
+	}
+
+	return u.JoinPath(key), region, nil
+}
+
+// signV4 signs the request using AWS Signature Version 4, which is
+// supported by both S3 and the GCS XML API when using HMAC keys. The payload
+// is signed using the x-amz-content-sha256 header if set, otherwise the
+// request is signed as having an empty payload.
+func signV4(req *http.Request, creds objectStorageCredentials, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	payloadHash := req.Header.Get("x-amz-content-sha256")
+	if len(payloadHash) == 0 {
+		payloadHash = emptyPayloadHash
+		req.Header.Set("x-amz-content-sha256", payloadHash)
+	}
+	req.Header.Set("x-amz-date", amzDate)
+	if len(creds.sessionToken) > 0 {
+		req.Header.Set("x-amz-security-token", creds.sessionToken)
+	}
+
+	// S3 requires all x-amz-* headers of the request to be signed, such as
+	// object metadata, along with the host.
+	names := []string{"host"}
+	for name := range req.Header {
+		if name := strings.ToLower(name); strings.HasPrefix(name, "x-amz-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		value := req.URL.Host
+		if name != "host" {
+			value = strings.TrimSpace(req.Header.Get(name))
+		}
+		canonicalHeaders.WriteString(name + ":" + value + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Each path segment must be strictly URI encoded, and the request must be
+	// sent with the same encoding that was signed.
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	req.URL.RawPath = strings.Join(segments, "/")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := []byte("AWS4" + creds.secretAccessKey)
+	for _, part := range []string{date, region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.accessKeyID, scope, signedHeaders, signature))
+}
+
+// uriEncode percent-encodes every byte of s other than the unreserved
+// characters of RFC 3986, as required by Signature Version 4.
+func uriEncode(s string) string {
+	var encoded strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			encoded.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&encoded, "%%%02X", c)
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
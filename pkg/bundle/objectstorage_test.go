@@ -0,0 +1,258 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	fakeclock "k8s.io/utils/clock/testing"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_objectStorageBundle(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	fixedTime := time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
+
+	s3Creds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: trustNamespace},
+		Data: map[string][]byte{
+			"accessKeyID":     []byte("AKIDEXAMPLE"),
+			"secretAccessKey": []byte("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"),
+		},
+	}
+	azureCreds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure-creds", Namespace: trustNamespace},
+		Data:       map[string][]byte{"sasToken": []byte("?sv=2021-08-06&sig=abc")},
+	}
+
+	tests := map[string]struct {
+		ref     trustapi.SourceObjectStorage
+		objects []runtime.Object
+		cached  *cachedObject
+
+		expPath          string
+		expAuthorization string
+		expQuery         string
+		expIfNoneMatch   string
+		expData          string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"anonymous S3 object should be fetched": {
+			ref:     trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			expPath: "/certs/ca.pem",
+			expData: dummy.TestCertificate1,
+		},
+		"S3 object with credentials should be fetched with a V4 signature": {
+			ref:              trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", Region: "eu-west-1", CredentialsSecret: "s3-creds"},
+			objects:          []runtime.Object{s3Creds},
+			expPath:          "/certs/ca.pem",
+			expAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20210101/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=",
+			expData:          dummy.TestCertificate1,
+		},
+		"GCS object with HMAC credentials should be signed for the auto region": {
+			ref:              trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderGCS, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "s3-creds"},
+			objects:          []runtime.Object{s3Creds},
+			expPath:          "/certs/ca.pem",
+			expAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20210101/auto/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=",
+			expData:          dummy.TestCertificate1,
+		},
+		"AzureBlob object with credentials should be fetched with the SAS token": {
+			ref:      trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "azure-creds"},
+			objects:  []runtime.Object{azureCreds},
+			expPath:  "/certs/ca.pem",
+			expQuery: "sv=2021-08-06&sig=abc",
+			expData:  dummy.TestCertificate1,
+		},
+		"cached object which is unchanged should be returned from the cache": {
+			ref:            trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			cached:         &cachedObject{etag: `"v1"`, data: dummy.TestCertificate2},
+			expPath:        "/certs/ca.pem",
+			expIfNoneMatch: `"v1"`,
+			expData:        dummy.TestCertificate2,
+		},
+		"cached object which has changed should be fetched again": {
+			ref:            trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			cached:         &cachedObject{etag: `"v0"`, data: dummy.TestCertificate2},
+			expPath:        "/certs/ca.pem",
+			expIfNoneMatch: `"v0"`,
+			expData:        dummy.TestCertificate1,
+		},
+		"object which doesn't exist should return not found error": {
+			ref:              trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "missing.pem"},
+			expPath:          "/certs/missing.pem",
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"credentials Secret which doesn't exist should return not found error": {
+			ref:              trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "s3-creds"},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"credentials Secret missing required keys should return not found error": {
+			ref:              trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "azure-creds"},
+			objects:          []runtime.Object{azureCreds},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"object which is forbidden should return error": {
+			ref:      trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "forbidden.pem"},
+			expPath:  "/certs/forbidden.pem",
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPath, gotAuthorization, gotQuery, gotIfNoneMatch string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath, gotAuthorization, gotQuery, gotIfNoneMatch = r.URL.Path, r.Header.Get("Authorization"), r.URL.RawQuery, r.Header.Get("If-None-Match")
+				switch {
+				case strings.HasSuffix(r.URL.Path, "missing.pem"):
+					w.WriteHeader(http.StatusNotFound)
+				case strings.HasSuffix(r.URL.Path, "forbidden.pem"):
+					w.WriteHeader(http.StatusForbidden)
+				case r.Header.Get("If-None-Match") == `"v1"`:
+					w.WriteHeader(http.StatusNotModified)
+				default:
+					w.Header().Set("ETag", `"v1"`)
+					_, _ = w.Write([]byte(dummy.TestCertificate1))
+				}
+			}))
+			defer server.Close()
+
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(test.objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{
+				sourceLister:        fakeclient,
+				clock:               fakeclock.NewFakeClock(fixedTime),
+				objectStorageClient: server.Client(),
+				Options:             Options{Namespace: trustNamespace},
+			}
+
+			test.ref.Endpoint = server.URL
+			if test.cached != nil {
+				objectURL, _, err := objectStorageURL(&test.ref)
+				if err != nil {
+					t.Fatal(err)
+				}
+				b.objectCache.set(objectURL.String(), *test.cached)
+			}
+
+			data, err := b.objectStorageBundle(context.TODO(), &test.ref)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected notFoundError: %v", err)
+			assert.Equal(t, test.expData, data)
+			assert.Equal(t, test.expPath, gotPath)
+			assert.Equal(t, test.expQuery, gotQuery)
+			assert.Equal(t, test.expIfNoneMatch, gotIfNoneMatch)
+			assert.True(t, strings.HasPrefix(gotAuthorization, test.expAuthorization), "unexpected Authorization header: %q", gotAuthorization)
+			if len(test.expAuthorization) == 0 {
+				assert.Empty(t, gotAuthorization)
+			}
+		})
+	}
+}
+
+func Test_objectStorageCache_setReferences(t *testing.T) {
+	var cache objectStorageCache
+	cache.set("https://a", cachedObject{data: "a"})
+	cache.set("https://b", cachedObject{data: "b"})
+	cache.set("https://c", cachedObject{data: "c"})
+
+	cached := func() []string {
+		var urls []string
+		for _, url := range []string{"https://a", "https://b", "https://c"} {
+			if _, ok := cache.get(url); ok {
+				urls = append(urls, url)
+			}
+		}
+		return urls
+	}
+
+	cache.setReferences("bundle-1", sets.New("https://a", "https://b"))
+	cache.setReferences("bundle-2", sets.New("https://b"))
+	assert.Equal(t, []string{"https://a", "https://b"}, cached(), "unreferenced objects should be evicted")
+
+	cache.setReferences("bundle-1", sets.New("https://a"))
+	assert.Equal(t, []string{"https://a", "https://b"}, cached(), "objects referenced by another Bundle should be kept")
+
+	cache.setReferences("bundle-2", nil)
+	assert.Equal(t, []string{"https://a"}, cached(), "objects of deleted Bundles should be evicted")
+}
+
+func Test_objectStorageURL(t *testing.T) {
+	tests := map[string]struct {
+		ref trustapi.SourceObjectStorage
+
+		expURL    string
+		expRegion string
+	}{
+		"S3 without a region should use the default region": {
+			ref:       trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			expURL:    "https://certs.s3.us-east-1.amazonaws.com/ca.pem",
+			expRegion: "us-east-1",
+		},
+		"S3 with a region should use the regional endpoint": {
+			ref:       trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "/pki/ca.pem", Region: "eu-west-1"},
+			expURL:    "https://certs.s3.eu-west-1.amazonaws.com/pki/ca.pem",
+			expRegion: "eu-west-1",
+		},
+		"GCS should use the XML API endpoint": {
+			ref:       trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderGCS, Bucket: "certs", Key: "ca.pem"},
+			expURL:    "https://storage.googleapis.com/certs/ca.pem",
+			expRegion: "auto",
+		},
+		"AzureBlob should use the account endpoint": {
+			ref:       trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Account: "corp", Bucket: "certs", Key: "ca.pem"},
+			expURL:    "https://corp.blob.core.windows.net/certs/ca.pem",
+			expRegion: "us-east-1",
+		},
+		"custom endpoint should use path-style URLs": {
+			ref:       trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", Endpoint: "https://minio.example.com:9000"},
+			expURL:    "https://minio.example.com:9000/certs/ca.pem",
+			expRegion: "us-east-1",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			objectURL, region, err := objectStorageURL(&test.ref)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expURL, objectURL.String())
+			assert.Equal(t, test.expRegion, region)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// objectStorageHashHeader is the object metadata header holding the hash
+	// of the bundle data written to an S3 or GCS object storage target.
+	objectStorageHashHeader = "x-amz-meta-trust-manager-hash"
+
+	// azureObjectStorageHashHeader is the object metadata header holding the
+	// hash of the bundle data written to an AzureBlob object storage target.
+	// Azure metadata names must be valid C# identifiers.
+	azureObjectStorageHashHeader = "x-ms-meta-trustmanagerhash"
+)
+
+// syncObjectStorageTarget writes the given bundle data to the Bundle's object
+// storage target, unless the object already contains it. The hash of the
+// bundle data is stored in the object's metadata, so that changes are
+// detected by the blob store without downloading the object. Returns true if
+// the object was written.
+func (b *bundle) syncObjectStorageTarget(ctx context.Context, bundle *trustapi.Bundle, data string) (bool, error) {
+	target := bundle.Spec.Target.ObjectStorage
+	if target == nil {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, objectStorageTimeout)
+	defer cancel()
+
+	// The object storage target is addressed and authenticated in the same
+	// way as an object storage source.
+	ref := &trustapi.SourceObjectStorage{
+		Provider:          target.Provider,
+		Bucket:            target.Bucket,
+		Key:               target.Key,
+		Region:            target.Region,
+		Account:           target.Account,
+		Endpoint:          target.Endpoint,
+		CredentialsSecret: target.CredentialsSecret,
+	}
+
+	hashHeader := objectStorageHashHeader
+	if target.Provider == trustapi.ObjectStorageProviderAzureBlob {
+		hashHeader = azureObjectStorageHashHeader
+	}
+	hash := contentHash(data)
+
+	req, objectURL, err := b.objectStorageRequest(ctx, http.MethodHead, ref, nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to get object %s: %w", objectURL.Redacted(), err)
+	}
+	resp.Body.Close()
+
+	// Objects which can't be read, such as when the credentials only allow
+	// writing, are always written.
+	if resp.StatusCode == http.StatusOK && resp.Header.Get(hashHeader) == hash {
+		return false, nil
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-pem-file")
+	header.Set(hashHeader, hash)
+	if target.Provider == trustapi.ObjectStorageProviderAzureBlob {
+		header.Set("x-ms-blob-type", "BlockBlob")
+	}
+
+	req, _, err = b.objectStorageRequest(ctx, http.MethodPut, ref, []byte(data), header)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err = b.httpClient().Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to write object %s: %w", objectURL.Redacted(), err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return false, fmt.Errorf("failed to write object %s: unexpected status %q", objectURL.Redacted(), resp.Status)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclock "k8s.io/utils/clock/testing"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_syncObjectStorageTarget(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	fixedTime := time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	s3Creds := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "s3-creds", Namespace: trustNamespace},
+		Data: map[string][]byte{
+			"accessKeyID":     []byte("AKIDEXAMPLE"),
+			"secretAccessKey": []byte("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"),
+		},
+	}
+
+	tests := map[string]struct {
+		target     *trustapi.TargetObjectStorage
+		objects    []runtime.Object
+		headStatus int
+		headHash   string
+		putStatus  int
+
+		expPublished     bool
+		expPut           bool
+		expHashHeader    string
+		expBlobType      string
+		expAuthorization string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"Bundle without object storage target should not publish": {},
+		"missing object should be written": {
+			target:        &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			headStatus:    http.StatusNotFound,
+			putStatus:     http.StatusOK,
+			expPublished:  true,
+			expPut:        true,
+			expHashHeader: "x-amz-meta-trust-manager-hash",
+		},
+		"object with a different hash should be written": {
+			target:        &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			headStatus:    http.StatusOK,
+			headHash:      "outdated",
+			putStatus:     http.StatusOK,
+			expPublished:  true,
+			expPut:        true,
+			expHashHeader: "x-amz-meta-trust-manager-hash",
+		},
+		"object with the same hash should not be written": {
+			target:     &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			headStatus: http.StatusOK,
+			headHash:   contentHash(data),
+		},
+		"object which can't be read should be written": {
+			target:        &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			headStatus:    http.StatusForbidden,
+			putStatus:     http.StatusOK,
+			expPublished:  true,
+			expPut:        true,
+			expHashHeader: "x-amz-meta-trust-manager-hash",
+		},
+		"S3 object with credentials should be written with a signed payload and metadata": {
+			target:           &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "s3-creds"},
+			objects:          []runtime.Object{s3Creds},
+			headStatus:       http.StatusNotFound,
+			putStatus:        http.StatusOK,
+			expPublished:     true,
+			expPut:           true,
+			expHashHeader:    "x-amz-meta-trust-manager-hash",
+			expAuthorization: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20210101/us-east-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-meta-trust-manager-hash, Signature=",
+		},
+		"AzureBlob object should be written as a block blob": {
+			target:        &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Bucket: "certs", Key: "ca.pem"},
+			headStatus:    http.StatusNotFound,
+			putStatus:     http.StatusCreated,
+			expPublished:  true,
+			expPut:        true,
+			expHashHeader: "x-ms-meta-trustmanagerhash",
+			expBlobType:   "BlockBlob",
+		},
+		"rejected write should error": {
+			target:        &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem"},
+			headStatus:    http.StatusNotFound,
+			putStatus:     http.StatusForbidden,
+			expPut:        true,
+			expHashHeader: "x-amz-meta-trust-manager-hash",
+			expError:      true,
+		},
+		"credentials Secret which doesn't exist should return not found error": {
+			target:           &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", CredentialsSecret: "s3-creds"},
+			expError:         true,
+			expNotFoundError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gotPut bool
+			var gotBody, gotHash, gotBlobType, gotAuthorization string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/certs/ca.pem", r.URL.Path)
+				switch r.Method {
+				case http.MethodHead:
+					if len(test.headHash) > 0 {
+						w.Header().Set("x-amz-meta-trust-manager-hash", test.headHash)
+					}
+					w.WriteHeader(test.headStatus)
+				case http.MethodPut:
+					body, _ := io.ReadAll(r.Body)
+					gotPut, gotBody, gotAuthorization = true, string(body), r.Header.Get("Authorization")
+					gotHash = r.Header.Get(test.expHashHeader)
+					gotBlobType = r.Header.Get("x-ms-blob-type")
+					w.WriteHeader(test.putStatus)
+				default:
+					w.WriteHeader(http.StatusMethodNotAllowed)
+				}
+			}))
+			defer server.Close()
+
+			trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"}}
+			if test.target != nil {
+				target := *test.target
+				target.Endpoint = server.URL
+				trustBundle.Spec.Target.ObjectStorage = &target
+			}
+
+			b := &bundle{
+				sourceLister: fakeclient.NewClientBuilder().
+					WithRuntimeObjects(test.objects...).
+					WithScheme(trustapi.GlobalScheme).
+					Build(),
+				clock:               fakeclock.NewFakeClock(fixedTime),
+				objectStorageClient: server.Client(),
+				Options:             Options{Namespace: trustNamespace},
+			}
+
+			published, err := b.syncObjectStorageTarget(context.TODO(), trustBundle, data)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected notFoundError: %v", err)
+			assert.Equal(t, test.expPublished, published)
+			assert.Equal(t, test.expPut, gotPut)
+			if !test.expPut {
+				return
+			}
+
+			assert.Equal(t, data, gotBody)
+			assert.Equal(t, contentHash(data), gotHash)
+			assert.Equal(t, test.expBlobType, gotBlobType)
+			assert.True(t, strings.HasPrefix(gotAuthorization, test.expAuthorization), "unexpected Authorization header: %q", gotAuthorization)
+			if len(test.expAuthorization) == 0 {
+				assert.Empty(t, gotAuthorization)
+			}
+		})
+	}
+}
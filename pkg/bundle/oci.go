@@ -0,0 +1,392 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// ociTimeout is the maximum time taken to push an artifact to a registry.
+	ociTimeout = 30 * time.Second
+
+	// defaultOCITag is the tag which artifacts are pushed to when none is
+	// given.
+	defaultOCITag = "latest"
+
+	// Keys of the credentials Secret of an OCI target.
+	ociUsernameKey = "username"
+	ociPasswordKey = "password"
+
+	// Media types of the artifact pushed to an OCI target.
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociArtifactType      = "application/vnd.cert-manager.trust.bundle.v1"
+	ociEmptyMediaType    = "application/vnd.oci.empty.v1+json"
+	ociBundleMediaType   = "application/x-pem-file"
+
+	// ociBundleTitle is the title of the layer containing the bundle data,
+	// used as its file name by tools such as oras.
+	ociBundleTitle = "ca-certificates.crt"
+)
+
+// ociDescriptor describes content in an OCI registry.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ociManifest is an OCI image manifest describing an artifact.
+type ociManifest struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	MediaType     string          `json:"mediaType"`
+	ArtifactType  string          `json:"artifactType"`
+	Config        ociDescriptor   `json:"config"`
+	Layers        []ociDescriptor `json:"layers"`
+}
+
+// ociArtifact is an artifact to push to a registry, made of its manifest and
+// the blobs which the manifest references.
+type ociArtifact struct {
+	manifest []byte
+	digest   string
+	blobs    map[string][]byte
+}
+
+// newOCIArtifact returns the artifact containing the given bundle data. The
+// artifact contains no timestamps, so that the same bundle data always
+// results in the same digest.
+func newOCIArtifact(data string) (ociArtifact, error) {
+	config := []byte("{}")
+	layer := []byte(data)
+
+	manifest, err := json.Marshal(ociManifest{
+		SchemaVersion: 2,
+		MediaType:     ociManifestMediaType,
+		ArtifactType:  ociArtifactType,
+		Config:        ociDescriptor{MediaType: ociEmptyMediaType, Digest: ociDigest(config), Size: int64(len(config))},
+		Layers: []ociDescriptor{{
+			MediaType:   ociBundleMediaType,
+			Digest:      ociDigest(layer),
+			Size:        int64(len(layer)),
+			Annotations: map[string]string{"org.opencontainers.image.title": ociBundleTitle},
+		}},
+	})
+	if err != nil {
+		return ociArtifact{}, fmt.Errorf("failed to encode OCI manifest: %w", err)
+	}
+
+	return ociArtifact{
+		manifest: manifest,
+		digest:   ociDigest(manifest),
+		blobs: map[string][]byte{
+			ociDigest(config): config,
+			ociDigest(layer):  layer,
+		},
+	}, nil
+}
+
+// ociDigest returns the OCI digest of the given content.
+func ociDigest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(hash[:])
+}
+
+// syncOCI pushes the given bundle data to the Bundle's OCI target, and
+// returns the status of the pushed artifact. The artifact is only pushed if
+// its reference or digest differ from those in the Bundle's status, so that
+// the registry is only written to when the bundle data changes. Returns nil
+// if the Bundle has no OCI target.
+func (b *bundle) syncOCI(ctx context.Context, bundle *trustapi.Bundle, data string) (*trustapi.BundleOCIStatus, error) {
+	target := bundle.Spec.Target.OCI
+	if target == nil {
+		return nil, nil
+	}
+
+	tag := target.Tag
+	if len(tag) == 0 {
+		tag = defaultOCITag
+	}
+
+	artifact, err := newOCIArtifact(data)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &trustapi.BundleOCIStatus{
+		Reference: target.Repository + ":" + tag,
+		Digest:    artifact.digest,
+	}
+	if bundle.Status.OCI != nil && *bundle.Status.OCI == *status {
+		return status, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ociTimeout)
+	defer cancel()
+
+	registry, err := b.ociRegistry(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	for digest, blob := range artifact.blobs {
+		if err := registry.pushBlob(ctx, digest, blob); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := registry.pushManifest(ctx, tag, artifact.manifest); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// ociRegistry returns a client for the repository of the OCI target,
+// authenticated with the credentials it references, if any.
+func (b *bundle) ociRegistry(ctx context.Context, target *trustapi.TargetOCI) (*ociRegistry, error) {
+	host, name, ok := strings.Cut(target.Repository, "/")
+	if !ok || len(host) == 0 || len(name) == 0 {
+		return nil, fmt.Errorf("invalid OCI repository %q, must include the registry host", target.Repository)
+	}
+
+	scheme := "https"
+	if target.PlainHTTP {
+		scheme = "http"
+	}
+
+	registry := &ociRegistry{
+		client: b.httpClient(),
+		base:   &url.URL{Scheme: scheme, Host: host, Path: "/v2/" + name},
+		name:   name,
+	}
+
+	if len(target.CredentialsSecret) > 0 {
+		var secret corev1.Secret
+		err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: target.CredentialsSecret}, &secret)
+		if apierrors.IsNotFound(err) {
+			return nil, notFoundError{err}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, target.CredentialsSecret, err)
+		}
+
+		for _, key := range []string{ociUsernameKey, ociPasswordKey} {
+			if len(secret.Data[key]) == 0 {
+				return nil, notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, target.CredentialsSecret, key)}
+			}
+		}
+
+		registry.username = string(secret.Data[ociUsernameKey])
+		registry.password = string(secret.Data[ociPasswordKey])
+	}
+
+	return registry, nil
+}
+
+// ociRegistry pushes content to a repository of an OCI registry, using the
+// OCI distribution API.
+type ociRegistry struct {
+	client *http.Client
+	base   *url.URL
+	name   string
+
+	username, password string
+
+	// authorization is the Authorization header sent with requests, once
+	// the registry has challenged for authentication.
+	authorization string
+}
+
+// pushBlob uploads the given blob, unless the repository already contains it.
+func (r *ociRegistry) pushBlob(ctx context.Context, digest string, blob []byte) error {
+	resp, err := r.do(ctx, http.MethodHead, r.base.JoinPath("blobs", digest).String(), "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = r.do(ctx, http.MethodPost, r.base.JoinPath("blobs", "uploads").String()+"/", "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		return fmt.Errorf("failed to start upload of blob %s to %s: unexpected status %q", digest, r.base.Host+"/"+r.name, resp.Status)
+	}
+
+	location, err := r.base.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location of blob %s: %w", digest, err)
+	}
+	query := location.Query()
+	query.Set("digest", digest)
+	location.RawQuery = query.Encode()
+
+	resp, err = r.do(ctx, http.MethodPut, location.String(), "application/octet-stream", blob)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to upload blob %s to %s: unexpected status %q", digest, r.base.Host+"/"+r.name, resp.Status)
+	}
+
+	return nil
+}
+
+// pushManifest uploads the given manifest to the given tag.
+func (r *ociRegistry) pushManifest(ctx context.Context, tag string, manifest []byte) error {
+	resp, err := r.do(ctx, http.MethodPut, r.base.JoinPath("manifests", tag).String(), ociManifestMediaType, manifest)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("failed to push manifest to %s:%s: unexpected status %q", r.base.Host+"/"+r.name, tag, resp.Status)
+	}
+
+	return nil
+}
+
+// do sends a request to the registry. If the registry challenges for
+// authentication, the request is retried once with authorization.
+func (r *ociRegistry) do(ctx context.Context, method, target, contentType string, body []byte) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to build registry request: %w", err)
+		}
+		if len(contentType) > 0 {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if len(r.authorization) > 0 {
+			req.Header.Set("Authorization", r.authorization)
+		}
+
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to reach registry %s: %w", r.base.Host, err)
+		}
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		if err := r.authorize(ctx, resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// ociChallengeParam matches a parameter of a WWW-Authenticate challenge.
+var ociChallengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// authorize sets the authorization sent with requests, in response to the
+// given challenge of the registry. Basic challenges are answered with the
+// credentials directly, and Bearer challenges with a token fetched from the
+// challenge's realm.
+func (r *ociRegistry) authorize(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch {
+	case strings.EqualFold(scheme, "Basic"):
+		if len(r.username) == 0 {
+			return fmt.Errorf("registry %s requires credentials", r.base.Host)
+		}
+		req := &http.Request{Header: http.Header{}}
+		req.SetBasicAuth(r.username, r.password)
+		r.authorization = req.Header.Get("Authorization")
+		return nil
+
+	case strings.EqualFold(scheme, "Bearer"):
+	default:
+		return fmt.Errorf("registry %s requested unsupported authentication %q", r.base.Host, challenge)
+	}
+
+	values := make(map[string]string)
+	for _, match := range ociChallengeParam.FindAllStringSubmatch(params, -1) {
+		values[strings.ToLower(match[1])] = match[2]
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || len(values["realm"]) == 0 {
+		return fmt.Errorf("registry %s returned invalid token realm %q", r.base.Host, values["realm"])
+	}
+
+	query := realm.Query()
+	if service := values["service"]; len(service) > 0 {
+		query.Set("service", service)
+	}
+	query.Set("scope", "repository:"+r.name+":pull,push")
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to build token request: %w", err)
+	}
+	if len(r.username) > 0 {
+		req.SetBasicAuth(r.username, r.password)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch token for registry %s: %w", r.base.Host, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch token for registry %s: unexpected status %q", r.base.Host, resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode token for registry %s: %w", r.base.Host, err)
+	}
+	if len(token.Token) == 0 {
+		token.Token = token.AccessToken
+	}
+	if len(token.Token) == 0 {
+		return fmt.Errorf("registry %s returned an empty token", r.base.Host)
+	}
+
+	r.authorization = "Bearer " + token.Token
+	return nil
+}
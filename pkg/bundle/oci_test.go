@@ -0,0 +1,250 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+// fakeRegistry is a minimal OCI registry, which requires a bearer token
+// issued for the given credentials if a username is set.
+type fakeRegistry struct {
+	lock      sync.Mutex
+	username  string
+	password  string
+	blobs     map[string][]byte
+	manifests map[string][]byte
+	requests  int
+	uploads   int
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+
+	if r.URL.Path == "/token" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != f.username || password != f.password || r.URL.Query().Get("scope") != "repository:trust/bundle:pull,push" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "registry-token"})
+		return
+	}
+
+	if len(f.username) > 0 && r.Header.Get("Authorization") != "Bearer registry-token" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="http://`+r.Host+`/token",service="registry"`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/v2/trust/bundle/")
+	switch {
+	case r.Method == http.MethodHead && strings.HasPrefix(path, "blobs/"):
+		if _, ok := f.blobs[strings.TrimPrefix(path, "blobs/")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case r.Method == http.MethodPost && path == "blobs/uploads/":
+		w.Header().Set("Location", "/v2/trust/bundle/blobs/uploads/session?state=1")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPut && path == "blobs/uploads/session":
+		body, _ := io.ReadAll(r.Body)
+		if r.URL.Query().Get("state") != "1" || ociDigest(body) != r.URL.Query().Get("digest") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[r.URL.Query().Get("digest")] = body
+		f.uploads++
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "manifests/"):
+		if r.Header.Get("Content-Type") != ociManifestMediaType {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var manifest ociManifest
+		if err := json.Unmarshal(body, &manifest); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, descriptor := range append(manifest.Layers, manifest.Config) {
+			if _, ok := f.blobs[descriptor.Digest]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+		f.manifests[strings.TrimPrefix(path, "manifests/")] = body
+		w.WriteHeader(http.StatusCreated)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func Test_syncOCI(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+	artifact, err := newOCIArtifact(data)
+	assert.NoError(t, err)
+
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "registry-credentials", Namespace: trustNamespace},
+		Data:       map[string][]byte{"username": []byte("user"), "password": []byte("pass")},
+	}
+
+	tests := map[string]struct {
+		target           *trustapi.TargetOCI
+		status           *trustapi.BundleOCIStatus
+		objects          []runtime.Object
+		registryUsername string
+		existingBlobs    bool
+
+		expStatus        bool
+		expTag           string
+		expRequests      bool
+		expUploads       int
+		expError         bool
+		expNotFoundError bool
+	}{
+		"Bundle without OCI target should not push": {},
+		"anonymous push should push blobs and manifest to the latest tag": {
+			target:      &trustapi.TargetOCI{},
+			expStatus:   true,
+			expTag:      "latest",
+			expRequests: true,
+			expUploads:  2,
+		},
+		"push with credentials should authenticate using a bearer token": {
+			target:           &trustapi.TargetOCI{Tag: "v1", CredentialsSecret: "registry-credentials"},
+			objects:          []runtime.Object{credentials},
+			registryUsername: "user",
+			expStatus:        true,
+			expTag:           "v1",
+			expRequests:      true,
+			expUploads:       2,
+		},
+		"existing blobs should not be uploaded again": {
+			target:        &trustapi.TargetOCI{},
+			existingBlobs: true,
+			expStatus:     true,
+			expTag:        "latest",
+			expRequests:   true,
+		},
+		"unchanged artifact should not be pushed again": {
+			target:    &trustapi.TargetOCI{},
+			status:    &trustapi.BundleOCIStatus{Digest: artifact.digest},
+			expStatus: true,
+			expTag:    "latest",
+		},
+		"changed tag should be pushed": {
+			target:      &trustapi.TargetOCI{Tag: "v2"},
+			status:      &trustapi.BundleOCIStatus{Digest: artifact.digest},
+			expStatus:   true,
+			expTag:      "v2",
+			expRequests: true,
+			expUploads:  2,
+		},
+		"push without credentials to registry requiring them should error": {
+			target:           &trustapi.TargetOCI{},
+			registryUsername: "user",
+			expRequests:      true,
+			expError:         true,
+		},
+		"credentials Secret which doesn't exist should return not found error": {
+			target:           &trustapi.TargetOCI{CredentialsSecret: "registry-credentials"},
+			expError:         true,
+			expNotFoundError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			registry := &fakeRegistry{
+				username:  test.registryUsername,
+				password:  "pass",
+				blobs:     make(map[string][]byte),
+				manifests: make(map[string][]byte),
+			}
+			if test.existingBlobs {
+				for digest, blob := range artifact.blobs {
+					registry.blobs[digest] = blob
+				}
+			}
+			server := httptest.NewServer(registry)
+			defer server.Close()
+
+			repository := strings.TrimPrefix(server.URL, "http://") + "/trust/bundle"
+
+			trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"}}
+			if test.target != nil {
+				target := *test.target
+				target.Repository = repository
+				target.PlainHTTP = true
+				trustBundle.Spec.Target.OCI = &target
+			}
+			if test.status != nil {
+				status := *test.status
+				status.Reference = repository + ":latest"
+				trustBundle.Status.OCI = &status
+			}
+
+			b := &bundle{
+				sourceLister: fakeclient.NewClientBuilder().
+					WithScheme(trustapi.GlobalScheme).
+					WithRuntimeObjects(test.objects...).
+					Build(),
+				objectStorageClient: server.Client(),
+				Options:             Options{Namespace: trustNamespace},
+			}
+
+			status, err := b.syncOCI(context.TODO(), trustBundle, data)
+			assert.Equal(t, test.expError, err != nil, err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), err)
+			assert.Equal(t, test.expRequests, registry.requests > 0)
+			assert.Equal(t, test.expUploads, registry.uploads)
+
+			if !test.expStatus {
+				assert.Nil(t, status)
+				return
+			}
+
+			assert.Equal(t, &trustapi.BundleOCIStatus{Reference: repository + ":" + test.expTag, Digest: artifact.digest}, status)
+			if test.expRequests {
+				assert.Equal(t, string(artifact.manifest), string(registry.manifests[test.expTag]))
+				assert.Equal(t, data, string(registry.blobs[ociDigest([]byte(data))]))
+			}
+		})
+	}
+}
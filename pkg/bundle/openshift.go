@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// openShiftTrustedCAName returns the name of the ConfigMap written by the
+// given OpenShiftTrustedCA target.
+func openShiftTrustedCAName(target *trustapi.TargetOpenShiftTrustedCA) string {
+	if len(target.Name) > 0 {
+		return target.Name
+	}
+	return trustapi.DefaultOpenShiftTrustedCAConfigMapName
+}
+
+// syncOpenShiftTrustedCATarget writes the bundle data to the ConfigMap of the
+// Bundle's OpenShiftTrustedCA target in the "openshift-config" Namespace,
+// owned by the Bundle. Returns true if the ConfigMap was changed. Returns an
+// error if the ConfigMap exists but isn't controlled by the Bundle, so that
+// CAs written by the OpenShift installer or by an administrator are never
+// overwritten.
+func (b *bundle) syncOpenShiftTrustedCATarget(ctx context.Context, bundle *trustapi.Bundle, data string) (bool, error) {
+	target := bundle.Spec.Target.OpenShiftTrustedCA
+	if target == nil {
+		return false, nil
+	}
+
+	name := openShiftTrustedCAName(target)
+
+	var configMap corev1.ConfigMap
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: trustapi.OpenShiftConfigNamespace, Name: name}, &configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       trustapi.OpenShiftConfigNamespace,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+			},
+			Data: map[string]string{trustapi.OpenShiftTrustedCAConfigMapKey: data},
+		}
+
+		if err := b.targetDirectClient.Create(ctx, &configMap); err != nil {
+			return false, fmt.Errorf("failed to create OpenShift trusted CA configmap %s/%s: %w", trustapi.OpenShiftConfigNamespace, name, err)
+		}
+
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get OpenShift trusted CA configmap %s/%s: %w", trustapi.OpenShiftConfigNamespace, name, err)
+	}
+
+	if !metav1.IsControlledBy(&configMap, bundle) {
+		return false, fmt.Errorf("OpenShift trusted CA configmap %s/%s already exists and is not controlled by the Bundle", trustapi.OpenShiftConfigNamespace, name)
+	}
+
+	if existing, ok := configMap.Data[trustapi.OpenShiftTrustedCAConfigMapKey]; ok && existing == data {
+		return false, nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[trustapi.OpenShiftTrustedCAConfigMapKey] = data
+
+	if err := b.targetDirectClient.Update(ctx, &configMap); err != nil {
+		return false, fmt.Errorf("failed to update OpenShift trusted CA configmap %s/%s: %w", trustapi.OpenShiftConfigNamespace, name, err)
+	}
+
+	return true, nil
+}
+
+// deleteOpenShiftTrustedCATarget deletes the ConfigMap of the given
+// OpenShiftTrustedCA target if it is controlled by the Bundle.
+func (b *bundle) deleteOpenShiftTrustedCATarget(ctx context.Context, bundle *trustapi.Bundle, target *trustapi.TargetOpenShiftTrustedCA) error {
+	name := openShiftTrustedCAName(target)
+
+	var configMap corev1.ConfigMap
+	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: trustapi.OpenShiftConfigNamespace, Name: name}, &configMap)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get OpenShift trusted CA configmap %s/%s: %w", trustapi.OpenShiftConfigNamespace, name, err)
+	}
+
+	if !metav1.IsControlledBy(&configMap, bundle) {
+		return nil
+	}
+
+	if err := b.targetDirectClient.Delete(ctx, &configMap); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete OpenShift trusted CA configmap %s/%s: %w", trustapi.OpenShiftConfigNamespace, name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_syncOpenShiftTrustedCATarget(t *testing.T) {
+	data := dummy.TestCertificate1
+
+	trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "trust", UID: "bundle-uid"}}
+	ownerRef := *metav1.NewControllerRef(trustBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))
+	trustedCAConfigMap := func(name, data string, ownerRefs ...metav1.OwnerReference) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "openshift-config",
+				OwnerReferences: ownerRefs,
+			},
+			Data: map[string]string{"ca-bundle.crt": data},
+		}
+	}
+
+	tests := map[string]struct {
+		target *trustapi.TargetOpenShiftTrustedCA
+		object runtime.Object
+
+		expName    string
+		expData    *corev1.ConfigMap
+		expChanged bool
+		expError   bool
+	}{
+		"no target should do nothing": {
+			expName: "trust-manager-trusted-ca",
+		},
+		"missing ConfigMap should be created with the default name": {
+			target:     &trustapi.TargetOpenShiftTrustedCA{},
+			expName:    "trust-manager-trusted-ca",
+			expData:    trustedCAConfigMap("trust-manager-trusted-ca", data, ownerRef),
+			expChanged: true,
+		},
+		"missing ConfigMap should be created with the given name": {
+			target:     &trustapi.TargetOpenShiftTrustedCA{Name: "custom-ca"},
+			expName:    "custom-ca",
+			expData:    trustedCAConfigMap("custom-ca", data, ownerRef),
+			expChanged: true,
+		},
+		"outdated ConfigMap should be updated": {
+			target:     &trustapi.TargetOpenShiftTrustedCA{},
+			object:     trustedCAConfigMap("trust-manager-trusted-ca", "old", ownerRef),
+			expName:    "trust-manager-trusted-ca",
+			expData:    trustedCAConfigMap("trust-manager-trusted-ca", data, ownerRef),
+			expChanged: true,
+		},
+		"up to date ConfigMap should not be changed": {
+			target:  &trustapi.TargetOpenShiftTrustedCA{},
+			object:  trustedCAConfigMap("trust-manager-trusted-ca", data, ownerRef),
+			expName: "trust-manager-trusted-ca",
+			expData: trustedCAConfigMap("trust-manager-trusted-ca", data, ownerRef),
+		},
+		"ConfigMap not controlled by the Bundle should not be overwritten": {
+			target:   &trustapi.TargetOpenShiftTrustedCA{Name: "user-ca-bundle"},
+			object:   trustedCAConfigMap("user-ca-bundle", "installer"),
+			expName:  "user-ca-bundle",
+			expData:  trustedCAConfigMap("user-ca-bundle", "installer"),
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeClient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeClient}
+			trustBundle := trustBundle.DeepCopy()
+			trustBundle.Spec.Target.OpenShiftTrustedCA = test.target
+
+			changed, err := b.syncOpenShiftTrustedCATarget(context.TODO(), trustBundle, data)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expChanged, changed)
+
+			var configMap corev1.ConfigMap
+			err = fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "openshift-config", Name: test.expName}, &configMap)
+			if test.expData == nil {
+				assert.True(t, apierrors.IsNotFound(err), "expected no ConfigMap: %v", err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData.OwnerReferences, configMap.OwnerReferences)
+			assert.Equal(t, test.expData.Data, configMap.Data)
+		})
+	}
+}
+
+func Test_deleteOpenShiftTrustedCATarget(t *testing.T) {
+	trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "trust", UID: "bundle-uid"}}
+	ownerRef := *metav1.NewControllerRef(trustBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))
+
+	tests := map[string]struct {
+		ownerRefs []metav1.OwnerReference
+		expExists bool
+	}{
+		"ConfigMap controlled by the Bundle should be deleted": {
+			ownerRefs: []metav1.OwnerReference{ownerRef},
+			expExists: false,
+		},
+		"ConfigMap not controlled by the Bundle should be kept": {
+			expExists: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).WithRuntimeObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "trust-manager-trusted-ca", Namespace: "openshift-config", OwnerReferences: test.ownerRefs},
+			}).Build()
+
+			b := &bundle{targetDirectClient: fakeClient}
+			assert.NoError(t, b.deleteOpenShiftTrustedCATarget(context.TODO(), trustBundle, &trustapi.TargetOpenShiftTrustedCA{}))
+
+			err := fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: "openshift-config", Name: "trust-manager-trusted-ca"}, &corev1.ConfigMap{})
+			assert.Equal(t, test.expExists, err == nil, "unexpected error: %v", err)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// partitionIndexKey returns the partition index key of the target, with the
+// default applied.
+func partitionIndexKey(target trustapi.BundleTarget) string {
+	if target.SizeLimit != nil && len(target.SizeLimit.PartitionIndexKey) > 0 {
+		return target.SizeLimit.PartitionIndexKey
+	}
+
+	return trustapi.DefaultPartitionIndexKey
+}
+
+// partitionKey returns the key partition i of the bundle data is written to,
+// which is the target key with "-i" inserted before its extension.
+func partitionKey(key string, i int) string {
+	ext := path.Ext(key)
+	return fmt.Sprintf("%s-%d%s", strings.TrimSuffix(key, ext), i, ext)
+}
+
+// partitionName returns the name of the ConfigMap partition i of the bundle
+// data is written to. The first partition is written to the target itself.
+func partitionName(targetName string, i int) string {
+	if i == 0 {
+		return targetName
+	}
+
+	return fmt.Sprintf("%s-%d", targetName, i)
+}
+
+// partitionBundleData splits the given bundle data into partitions of at
+// most maxBytes and maxCertificates, in bundle order. A maximum number of
+// certificates of zero means the number isn't limited. A certificate which
+// alone exceeds maxBytes is written to a partition of its own.
+func partitionBundleData(data string, maxBytes, maxCertificates int) ([]string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []string
+	var partition strings.Builder
+	var count int
+	for _, certificate := range certificates {
+		if count > 0 && (partition.Len()+len(certificate) > maxBytes || (maxCertificates > 0 && count == maxCertificates)) {
+			partitions = append(partitions, partition.String())
+			partition.Reset()
+			count = 0
+		}
+
+		partition.Write(certificate)
+		count++
+	}
+
+	if count > 0 {
+		partitions = append(partitions, partition.String())
+	}
+
+	return partitions, nil
+}
+
+// partitionEntries returns the entries written to the target for the given
+// partitions, which are the first partition and the partition index. The
+// index lists the partitions as "<configmap>/<key>", one per line.
+func partitionEntries(targetName, key, indexKey string, partitions []string) map[string]string {
+	index := make([]string, len(partitions))
+	for i := range partitions {
+		index[i] = partitionName(targetName, i) + "/" + partitionKey(key, i)
+	}
+
+	return map[string]string{
+		partitionKey(key, 0): partitions[0],
+		indexKey:             strings.Join(index, "\n") + "\n",
+	}
+}
+
+// parsePartitionIndex returns the ConfigMap names and keys of the partitions
+// listed in the given partition index, ignoring malformed lines.
+func parsePartitionIndex(index string) [][2]string {
+	var partitions [][2]string
+	for _, line := range strings.Fields(index) {
+		if name, key, ok := strings.Cut(line, "/"); ok {
+			partitions = append(partitions, [2]string{name, key})
+		}
+	}
+
+	return partitions
+}
+
+// syncPartitionEntries writes the given entries of the bundle data to the
+// target ConfigMap, which are either the complete bundle data under the
+// target key or the entries of partitioned bundle data. Entries of the target
+// listed in the ConfigMap's existing partition index which are no longer
+// written are removed, as is the index itself, so that the target switches
+// cleanly between partitioned and complete bundle data. Returns true if the
+// ConfigMap was changed.
+func syncPartitionEntries(configMap *corev1.ConfigMap, key, indexKey string, entries map[string]string) bool {
+	var changed bool
+
+	if index, ok := configMap.Data[indexKey]; ok {
+		for _, partition := range parsePartitionIndex(index) {
+			if partition[0] != configMap.Name {
+				continue
+			}
+			if _, ok := entries[partition[1]]; ok {
+				continue
+			}
+			if _, ok := configMap.Data[partition[1]]; ok {
+				delete(configMap.Data, partition[1])
+				changed = true
+			}
+		}
+
+		if _, ok := entries[indexKey]; !ok {
+			delete(configMap.Data, indexKey)
+			changed = true
+		}
+	}
+
+	// The complete bundle data is removed once it is partitioned.
+	if _, ok := entries[key]; !ok {
+		if _, ok := configMap.Data[key]; ok {
+			delete(configMap.Data, key)
+			changed = true
+		}
+	}
+
+	for entryKey, value := range entries {
+		if existing, ok := configMap.Data[entryKey]; ok && existing == value {
+			continue
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[entryKey] = value
+		changed = true
+	}
+
+	return changed
+}
+
+// syncPartitions writes the second and later partitions of the bundle data
+// to their ConfigMaps in the given Namespace, owned by the Bundle. Partition
+// ConfigMaps listed in the target's previous partition index which are no
+// longer needed are deleted. Returns true if any ConfigMap was changed.
+func (b *bundle) syncPartitions(ctx context.Context, bundle *trustapi.Bundle, namespace, targetName, key string, partitions []string, previousIndex string) (bool, error) {
+	var changed bool
+
+	names := make(map[string]bool, len(partitions))
+	for i := 1; i < len(partitions); i++ {
+		name := partitionName(targetName, i)
+		names[name] = true
+		data := map[string]string{partitionKey(key, i): partitions[i]}
+
+		var configMap corev1.ConfigMap
+		err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &configMap)
+		if apierrors.IsNotFound(err) {
+			configMap = corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            name,
+					Namespace:       namespace,
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
+				},
+				Data: data,
+			}
+
+			if err := b.targetDirectClient.Create(ctx, &configMap); err != nil {
+				return changed, fmt.Errorf("failed to create partition configmap %s/%s: %w", namespace, name, err)
+			}
+
+			changed = true
+			continue
+		}
+		if err != nil {
+			return changed, fmt.Errorf("failed to get partition configmap %s/%s: %w", namespace, name, err)
+		}
+
+		if !metav1.IsControlledBy(&configMap, bundle) {
+			return changed, fmt.Errorf("partition configmap %s/%s is not owned by the Bundle", namespace, name)
+		}
+
+		if len(configMap.Data) == 1 && configMap.Data[partitionKey(key, i)] == partitions[i] {
+			continue
+		}
+
+		configMap.Data = data
+		if err := b.targetDirectClient.Update(ctx, &configMap); err != nil {
+			return changed, fmt.Errorf("failed to update partition configmap %s/%s: %w", namespace, name, err)
+		}
+		changed = true
+	}
+
+	for _, partition := range parsePartitionIndex(previousIndex) {
+		name := partition[0]
+		if name == targetName || names[name] {
+			continue
+		}
+
+		var configMap corev1.ConfigMap
+		err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &configMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return changed, fmt.Errorf("failed to get partition configmap %s/%s: %w", namespace, name, err)
+		}
+
+		// Only delete ConfigMaps which were written for the Bundle.
+		if !metav1.IsControlledBy(&configMap, bundle) {
+			continue
+		}
+
+		if err := b.targetDirectClient.Delete(ctx, &configMap); err != nil && !apierrors.IsNotFound(err) {
+			return changed, fmt.Errorf("failed to delete partition configmap %s/%s: %w", namespace, name, err)
+		}
+		changed = true
+	}
+
+	return changed, nil
+}
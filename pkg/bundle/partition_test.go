@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_partitionKey(t *testing.T) {
+	assert.Equal(t, "ca-bundle-0.crt", partitionKey("ca-bundle.crt", 0))
+	assert.Equal(t, "ca-bundle-1.crt", partitionKey("ca-bundle.crt", 1))
+	assert.Equal(t, "trust-2", partitionKey("trust", 2))
+
+	assert.Equal(t, "trust-bundle", partitionName("trust-bundle", 0))
+	assert.Equal(t, "trust-bundle-1", partitionName("trust-bundle", 1))
+}
+
+func Test_partitionBundleData(t *testing.T) {
+	cert1 := strings.TrimSpace(dummy.TestCertificate1) + "\n"
+	cert2 := strings.TrimSpace(dummy.TestCertificate2) + "\n"
+	cert3 := strings.TrimSpace(dummy.TestCertificate3) + "\n"
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	tests := map[string]struct {
+		maxBytes        int
+		maxCertificates int
+
+		expPartitions []string
+	}{
+		"data within the limits should be a single partition": {
+			maxBytes:      len(data),
+			expPartitions: []string{cert1 + cert2 + cert3},
+		},
+		"data should be split by size": {
+			maxBytes:      len(cert1) + len(cert2),
+			expPartitions: []string{cert1 + cert2, cert3},
+		},
+		"data should be split by number of certificates": {
+			maxBytes:        len(data),
+			maxCertificates: 1,
+			expPartitions:   []string{cert1, cert2, cert3},
+		},
+		"certificates exceeding the size alone should have their own partition": {
+			maxBytes:      1,
+			expPartitions: []string{cert1, cert2, cert3},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			partitions, err := partitionBundleData(data, test.maxBytes, test.maxCertificates)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expPartitions, partitions)
+		})
+	}
+}
+
+func Test_syncPartitionEntries(t *testing.T) {
+	partitioned := partitionEntries("trust", "ca.crt", "partitions.txt", []string{"part-0", "part-1"})
+	assert.Equal(t, map[string]string{
+		"ca-0.crt":       "part-0",
+		"partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n",
+	}, partitioned)
+
+	tests := map[string]struct {
+		data    map[string]string
+		entries map[string]string
+
+		expData    map[string]string
+		expChanged bool
+	}{
+		"complete data should be written": {
+			entries:    map[string]string{"ca.crt": "bundle"},
+			expData:    map[string]string{"ca.crt": "bundle"},
+			expChanged: true,
+		},
+		"up to date data should not be changed": {
+			data:    map[string]string{"ca.crt": "bundle", "other": "value"},
+			entries: map[string]string{"ca.crt": "bundle"},
+			expData: map[string]string{"ca.crt": "bundle", "other": "value"},
+		},
+		"partitioning should remove the complete data": {
+			data:       map[string]string{"ca.crt": "bundle", "other": "value"},
+			entries:    partitioned,
+			expData:    map[string]string{"ca-0.crt": "part-0", "partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n", "other": "value"},
+			expChanged: true,
+		},
+		"up to date partitions should not be changed": {
+			data:    map[string]string{"ca-0.crt": "part-0", "partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n"},
+			entries: partitioned,
+			expData: map[string]string{"ca-0.crt": "part-0", "partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n"},
+		},
+		"removing the partitions should remove the partition entries and the index": {
+			data:       map[string]string{"ca-0.crt": "part-0", "partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n", "other": "value"},
+			entries:    map[string]string{"ca.crt": "bundle"},
+			expData:    map[string]string{"ca.crt": "bundle", "other": "value"},
+			expChanged: true,
+		},
+		"partition entries of a previous key should be removed": {
+			data:       map[string]string{"old-0.crt": "part-0", "partitions.txt": "trust/old-0.crt\ntrust-1/old-1.crt\n"},
+			entries:    partitioned,
+			expData:    map[string]string{"ca-0.crt": "part-0", "partitions.txt": "trust/ca-0.crt\ntrust-1/ca-1.crt\n"},
+			expChanged: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "trust"}, Data: test.data}
+
+			changed := syncPartitionEntries(configMap, "ca.crt", "partitions.txt", test.entries)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expData, configMap.Data)
+		})
+	}
+}
+
+func Test_syncPartitions(t *testing.T) {
+	const namespace = "ns"
+
+	trustBundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "trust", UID: "bundle-uid"}}
+	partition := func(name string, data map[string]string, owned bool) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       data,
+		}
+		if owned {
+			configMap.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(trustBundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))}
+		}
+		return configMap
+	}
+
+	tests := map[string]struct {
+		objects       []runtime.Object
+		partitions    []string
+		previousIndex string
+
+		expData    map[string]map[string]string
+		expChanged bool
+		expError   bool
+	}{
+		"missing partitions should be created": {
+			partitions: []string{"part-0", "part-1", "part-2"},
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "part-1"},
+				"trust-2": {"ca-2.crt": "part-2"},
+			},
+			expChanged: true,
+		},
+		"up to date partitions should not be changed": {
+			objects:    []runtime.Object{partition("trust-1", map[string]string{"ca-1.crt": "part-1"}, true)},
+			partitions: []string{"part-0", "part-1"},
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "part-1"},
+			},
+		},
+		"changed partitions should be updated": {
+			objects:    []runtime.Object{partition("trust-1", map[string]string{"ca-1.crt": "old", "other": "value"}, true)},
+			partitions: []string{"part-0", "part-1"},
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "part-1"},
+			},
+			expChanged: true,
+		},
+		"partitions no longer needed should be deleted": {
+			objects: []runtime.Object{
+				partition("trust-1", map[string]string{"ca-1.crt": "part-1"}, true),
+				partition("trust-2", map[string]string{"ca-2.crt": "part-2"}, true),
+			},
+			partitions:    []string{"part-0", "part-1"},
+			previousIndex: "trust/ca-0.crt\ntrust-1/ca-1.crt\ntrust-2/ca-2.crt\n",
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "part-1"},
+				"trust-2": nil,
+			},
+			expChanged: true,
+		},
+		"previous partitions not owned by the Bundle should not be deleted": {
+			objects:       []runtime.Object{partition("trust-1", map[string]string{"ca-1.crt": "other"}, false)},
+			previousIndex: "trust/ca-0.crt\ntrust-1/ca-1.crt\n",
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "other"},
+			},
+		},
+		"existing ConfigMap not owned by the Bundle should fail": {
+			objects:    []runtime.Object{partition("trust-1", map[string]string{"ca-1.crt": "other"}, false)},
+			partitions: []string{"part-0", "part-1"},
+			expData: map[string]map[string]string{
+				"trust-1": {"ca-1.crt": "other"},
+			},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(test.objects...).
+				Build()
+
+			b := &bundle{targetDirectClient: fakeClient}
+
+			changed, err := b.syncPartitions(context.TODO(), trustBundle, namespace, "trust", "ca.crt", test.partitions, test.previousIndex)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expChanged, changed)
+
+			for name, expData := range test.expData {
+				var configMap corev1.ConfigMap
+				err := fakeClient.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: name}, &configMap)
+				if expData == nil {
+					assert.True(t, apierrors.IsNotFound(err), "expected ConfigMap %s to be deleted: %v", name, err)
+					continue
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, expData, configMap.Data)
+			}
+		})
+	}
+}
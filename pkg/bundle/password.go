@@ -0,0 +1,198 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// execPasswordProviderTimeout is the maximum time a password provider plugin
+// may run before it is killed.
+const execPasswordProviderTimeout = 30 * time.Second
+
+// execPasswordProviderCacheTTL is the time for which passwords resolved by a
+// password provider plugin are reused, rather than executing the plugin on
+// every reconcile.
+const execPasswordProviderCacheTTL = 5 * time.Minute
+
+// PasswordProvider resolves passwords used to encrypt binary truststore
+// targets, allowing passwords to be held outside of the cluster, for example
+// in a key management system.
+type PasswordProvider interface {
+	// Password returns the password identified by the given key.
+	Password(ctx context.Context, key string) ([]byte, error)
+}
+
+// execPasswordProvider is a PasswordProvider which executes a plugin binary
+// to resolve passwords.
+type execPasswordProvider struct {
+	path  string
+	clock clock.PassiveClock
+
+	lock sync.Mutex
+	// cache holds the passwords last resolved by the plugin, by key.
+	cache map[string]cachedPassword
+}
+
+// cachedPassword is a password resolved by a password provider plugin.
+type cachedPassword struct {
+	password []byte
+	expires  time.Time
+}
+
+// NewExecPasswordProvider returns a PasswordProvider which executes the plugin
+// binary at the given path to resolve passwords. The plugin is invoked with
+// the password key as its only argument, and must write the password to
+// stdout and exit zero. A single trailing newline is removed from the output.
+// Resolved passwords are reused for a few minutes before the plugin is
+// executed again.
+func NewExecPasswordProvider(path string) PasswordProvider {
+	return &execPasswordProvider{path: path, clock: clock.RealClock{}}
+}
+
+func (e *execPasswordProvider) Password(ctx context.Context, key string) ([]byte, error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	now := e.clock.Now()
+	if cached, ok := e.cache[key]; ok && now.Before(cached.expires) {
+		return cached.password, nil
+	}
+
+	password, err := e.exec(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.cache == nil {
+		e.cache = make(map[string]cachedPassword)
+	}
+	e.cache[key] = cachedPassword{password: password, expires: now.Add(execPasswordProviderCacheTTL)}
+
+	return password, nil
+}
+
+// exec executes the plugin to resolve the password identified by the given
+// key.
+func (e *execPasswordProvider) exec(ctx context.Context, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, execPasswordProviderTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.path, key)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("password provider plugin %q failed: %w: %s", e.path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	password := stdout.Bytes()
+	password = bytes.TrimSuffix(password, []byte("\n"))
+	password = bytes.TrimSuffix(password, []byte("\r"))
+
+	return password, nil
+}
+
+// jksPassword returns the password used to encrypt the JKS target of the
+// given Bundle. Returns the default Java password if no password is
+// configured.
+func (b *bundle) jksPassword(ctx context.Context, jks *trustapi.JKS) ([]byte, error) {
+	switch {
+	case jks.Password != nil:
+		return []byte(*jks.Password), nil
+
+	case jks.PasswordFrom != nil:
+		return b.resolvePassword(ctx, jks.PasswordFrom)
+
+	default:
+		return []byte(DefaultJKSPassword), nil
+	}
+}
+
+// pkcs12Password returns the password used to encrypt the PKCS#12 target of
+// the given Bundle. Returns an empty password if no password is configured.
+func (b *bundle) pkcs12Password(ctx context.Context, pkcs12 *trustapi.PKCS12) (string, error) {
+	switch {
+	case pkcs12.Password != nil:
+		return *pkcs12.Password, nil
+
+	case pkcs12.PasswordFrom != nil:
+		password, err := b.resolvePassword(ctx, pkcs12.PasswordFrom)
+		return string(password), err
+
+	default:
+		return "", nil
+	}
+}
+
+// resolvePassword returns the password referenced by the given
+// PasswordSource.
+func (b *bundle) resolvePassword(ctx context.Context, source *trustapi.PasswordSource) ([]byte, error) {
+	switch {
+	case source.Secret != nil:
+		ref := source.Secret
+
+		var secret corev1.Secret
+		err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+		if apierrors.IsNotFound(err) {
+			return nil, notFoundError{err}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+		}
+
+		password, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, notFoundError{fmt.Errorf("no password found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+		}
+
+		return password, nil
+
+	case source.Provider != nil:
+		ref := source.Provider
+
+		provider, ok := b.PasswordProviders[ref.Name]
+		if !ok {
+			return nil, fmt.Errorf("password provider %q is not registered", ref.Name)
+		}
+
+		password, err := provider.Password(ctx, ref.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get password %q from password provider %q: %w", ref.Key, ref.Name, err)
+		}
+
+		return password, nil
+
+	default:
+		return nil, errors.New("no password source defined")
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// fakePasswordProvider is a PasswordProvider returning passwords from a map.
+type fakePasswordProvider map[string]string
+
+func (f fakePasswordProvider) Password(_ context.Context, key string) ([]byte, error) {
+	password, ok := f[key]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+
+	return []byte(password), nil
+}
+
+func Test_execPasswordProvider(t *testing.T) {
+	tests := map[string]struct {
+		script string
+
+		expPassword string
+		expError    bool
+	}{
+		"plugin writing the key suffixed with a newline should return the key": {
+			script:      "#!/bin/sh\necho \"password-$1\"\n",
+			expPassword: "password-my-key",
+		},
+		"plugin writing without a trailing newline should return the output verbatim": {
+			script:      "#!/bin/sh\nprintf ' pass word '\n",
+			expPassword: " pass word ",
+		},
+		"plugin exiting non-zero should return an error": {
+			script:   "#!/bin/sh\necho 'access denied' >&2\nexit 1\n",
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plugin")
+			if err := os.WriteFile(path, []byte(test.script), 0o700); err != nil {
+				t.Fatal(err)
+			}
+
+			password, err := NewExecPasswordProvider(path).Password(context.TODO(), "my-key")
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expPassword, string(password))
+		})
+	}
+}
+
+func Test_execPasswordProvider_cache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plugin")
+	// The plugin appends a line to a file on every execution, so that the
+	// executions can be counted.
+	script := "#!/bin/sh\necho \"$1\" >> \"" + filepath.Join(dir, "executions") + "\"\necho \"password-$1\"\n"
+	if err := os.WriteFile(path, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	executions := func() []string {
+		data, err := os.ReadFile(filepath.Join(dir, "executions"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Fields(string(data))
+	}
+
+	fakeclock := fakeclock.NewFakeClock(time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC))
+	provider := &execPasswordProvider{path: path, clock: fakeclock}
+
+	password := func(key string) string {
+		password, err := provider.Password(context.TODO(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(password)
+	}
+
+	assert.Equal(t, "password-a", password("a"))
+	assert.Equal(t, "password-a", password("a"))
+	assert.Equal(t, "password-b", password("b"))
+	assert.Equal(t, []string{"a", "b"}, executions(), "passwords should be reused within the TTL")
+
+	fakeclock.Step(execPasswordProviderCacheTTL)
+	assert.Equal(t, "password-a", password("a"))
+	assert.Equal(t, []string{"a", "b", "a"}, executions(), "passwords should be resolved again once the TTL has passed")
+}
+
+func Test_jksPassword(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "password", Namespace: trustNamespace},
+		Data:       map[string][]byte{"password": []byte("secret-password")},
+	}
+
+	tests := map[string]struct {
+		jks     *trustapi.JKS
+		objects []runtime.Object
+
+		expPassword      string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"if no password is defined, should return the default password": {
+			jks:         &trustapi.JKS{},
+			expPassword: DefaultJKSPassword,
+		},
+		"if inline password is defined, should return it": {
+			jks:         &trustapi.JKS{Password: pointer.String("inline-password")},
+			expPassword: "inline-password",
+		},
+		"if Secret password is defined, should return it": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Secret: &trustapi.SourceObjectKeySelector{Name: "password", Key: "password"},
+			}},
+			objects:     []runtime.Object{secret},
+			expPassword: "secret-password",
+		},
+		"if Secret password doesn't exist, should return not found error": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Secret: &trustapi.SourceObjectKeySelector{Name: "password", Key: "password"},
+			}},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if Secret password key doesn't exist, should return not found error": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Secret: &trustapi.SourceObjectKeySelector{Name: "password", Key: "other"},
+			}},
+			objects:          []runtime.Object{secret},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if provider password is defined, should return it": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Provider: &trustapi.PasswordProviderSelector{Name: "kms", Key: "truststore"},
+			}},
+			expPassword: "kms-password",
+		},
+		"if provider fails, should return error": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Provider: &trustapi.PasswordProviderSelector{Name: "kms", Key: "unknown"},
+			}},
+			expError: true,
+		},
+		"if provider isn't registered, should return error": {
+			jks: &trustapi.JKS{PasswordFrom: &trustapi.PasswordSource{
+				Provider: &trustapi.PasswordProviderSelector{Name: "vault", Key: "truststore"},
+			}},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(test.objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{
+				sourceLister: fakeclient,
+				Options: Options{
+					Namespace: trustNamespace,
+					PasswordProviders: map[string]PasswordProvider{
+						"kms": fakePasswordProvider{"truststore": "kms-password"},
+					},
+				},
+			}
+
+			password, err := b.jksPassword(context.TODO(), test.jks)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected notFoundError: %v", err)
+			assert.Equal(t, test.expPassword, string(password))
+		})
+	}
+}
+
+func Test_pkcs12Password(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "password", Namespace: trustNamespace},
+		Data:       map[string][]byte{"password": []byte("secret-password")},
+	}
+
+	tests := map[string]struct {
+		pkcs12  *trustapi.PKCS12
+		objects []runtime.Object
+
+		expPassword      string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"if no password is defined, should return an empty password": {
+			pkcs12:      &trustapi.PKCS12{},
+			expPassword: "",
+		},
+		"if inline password is defined, should return it": {
+			pkcs12:      &trustapi.PKCS12{Password: pointer.String("inline-password")},
+			expPassword: "inline-password",
+		},
+		"if Secret password is defined, should return it": {
+			pkcs12: &trustapi.PKCS12{PasswordFrom: &trustapi.PasswordSource{
+				Secret: &trustapi.SourceObjectKeySelector{Name: "password", Key: "password"},
+			}},
+			objects:     []runtime.Object{secret},
+			expPassword: "secret-password",
+		},
+		"if Secret password doesn't exist, should return not found error": {
+			pkcs12: &trustapi.PKCS12{PasswordFrom: &trustapi.PasswordSource{
+				Secret: &trustapi.SourceObjectKeySelector{Name: "password", Key: "password"},
+			}},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"if provider password is defined, should return it": {
+			pkcs12: &trustapi.PKCS12{PasswordFrom: &trustapi.PasswordSource{
+				Provider: &trustapi.PasswordProviderSelector{Name: "kms", Key: "truststore"},
+			}},
+			expPassword: "kms-password",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(test.objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{
+				sourceLister: fakeclient,
+				Options: Options{
+					Namespace: trustNamespace,
+					PasswordProviders: map[string]PasswordProvider{
+						"kms": fakePasswordProvider{"truststore": "kms-password"},
+					},
+				},
+			}
+
+			password, err := b.pkcs12Password(context.TODO(), test.pkcs12)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected notFoundError: %v", err)
+			assert.Equal(t, test.expPassword, password)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// pemDirectoryKeys returns the key prefix and the index key of the target's
+// PEM directory, with defaults applied, and whether the target has the PEM
+// directory format.
+func pemDirectoryKeys(target trustapi.BundleTarget) (string, string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.PEMDirectory == nil {
+		return "", "", false
+	}
+
+	prefix := target.AdditionalFormats.PEMDirectory.KeyPrefix
+	if len(prefix) == 0 {
+		prefix = trustapi.DefaultPEMDirectoryKeyPrefix
+	}
+
+	indexKey := target.AdditionalFormats.PEMDirectory.IndexKey
+	if len(indexKey) == 0 {
+		indexKey = trustapi.DefaultPEMDirectoryIndexKey
+	}
+
+	return prefix, indexKey, true
+}
+
+// pemDirectoryKey returns the key of the PEM directory entry of the
+// certificate at the given position in the bundle.
+func pemDirectoryKey(prefix string, i int) string {
+	return fmt.Sprintf("%s%04d.pem", prefix, i)
+}
+
+// encodePEMDirectory returns the entries of the PEM directory of the given PEM
+// bundle, keyed by entry key. Each certificate is written to its own entry,
+// named as set by the given key naming, and the index entry lists the keys of
+// the certificate entries, one per line in bundle order.
+func encodePEMDirectory(data, prefix, indexKey string, naming trustapi.PEMDirectoryKeyNaming) (map[string]string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	var hashedFiles []hashedDirectoryFile
+	if naming == trustapi.PEMDirectoryKeyNamingSubjectHash {
+		hashedFiles, err = hashedDirectoryFiles(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	directory := make(map[string]string, len(certificates)+1)
+	keys := make([]string, 0, len(certificates))
+	for i, certificate := range certificates {
+		var key string
+		switch naming {
+		case trustapi.PEMDirectoryKeyNamingFingerprint:
+			block, _ := pem.Decode(certificate)
+			fingerprint := sha256.Sum256(block.Bytes)
+			key = prefix + hex.EncodeToString(fingerprint[:]) + ".pem"
+		case trustapi.PEMDirectoryKeyNamingSubjectHash:
+			key = prefix + hashedFiles[i].name + ".pem"
+		default:
+			key = pemDirectoryKey(prefix, i)
+		}
+
+		// Identical certificates have the same fingerprint, and are only
+		// written once.
+		if _, ok := directory[key]; ok {
+			continue
+		}
+
+		keys = append(keys, key)
+		directory[key] = string(certificate)
+	}
+
+	directory[indexKey] = strings.Join(keys, "\n") + "\n"
+
+	return directory, nil
+}
+
+// syncPEMDirectory writes the given PEM directory entries to the ConfigMap.
+// Entries listed in the ConfigMap's existing index which are not part of the
+// directory are removed, as is the index itself if the directory is empty, so
+// that certificates removed from the bundle don't linger in the target.
+// Returns true if the ConfigMap was changed.
+func syncPEMDirectory(configMap *corev1.ConfigMap, indexKey string, directory map[string]string) bool {
+	var changed bool
+
+	for _, key := range strings.Fields(configMap.Data[indexKey]) {
+		if _, ok := directory[key]; ok {
+			continue
+		}
+		if _, ok := configMap.Data[key]; ok {
+			delete(configMap.Data, key)
+			changed = true
+		}
+	}
+
+	if _, ok := directory[indexKey]; !ok {
+		if _, ok := configMap.Data[indexKey]; ok {
+			delete(configMap.Data, indexKey)
+			changed = true
+		}
+	}
+
+	for key, value := range directory {
+		if existing, ok := configMap.Data[key]; ok && existing == value {
+			continue
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = make(map[string]string)
+		}
+		configMap.Data[key] = value
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_pemDirectoryKeys(t *testing.T) {
+	_, _, ok := pemDirectoryKeys(trustapi.BundleTarget{})
+	assert.False(t, ok)
+
+	prefix, indexKey, ok := pemDirectoryKeys(trustapi.BundleTarget{AdditionalFormats: &trustapi.AdditionalFormats{PEMDirectory: &trustapi.PEMDirectory{}}})
+	assert.True(t, ok)
+	assert.Equal(t, "ca-", prefix)
+	assert.Equal(t, "index.txt", indexKey)
+
+	prefix, indexKey, ok = pemDirectoryKeys(trustapi.BundleTarget{AdditionalFormats: &trustapi.AdditionalFormats{PEMDirectory: &trustapi.PEMDirectory{KeyPrefix: "anchor-", IndexKey: "anchors"}}})
+	assert.True(t, ok)
+	assert.Equal(t, "anchor-", prefix)
+	assert.Equal(t, "anchors", indexKey)
+}
+
+func Test_encodePEMDirectory(t *testing.T) {
+	directory, err := encodePEMDirectory(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2), "ca-", "index.txt", "")
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"ca-0000.pem": strings.TrimSpace(dummy.TestCertificate1) + "\n",
+		"ca-0001.pem": strings.TrimSpace(dummy.TestCertificate2) + "\n",
+		"index.txt":   "ca-0000.pem\nca-0001.pem\n",
+	}, directory)
+
+	_, err = encodePEMDirectory(dummy.TestCertificate1+"\n-----BEGIN CERTIFICATE-----\naW52YWxpZA==\n-----END CERTIFICATE-----\n", "ca-", "index.txt", "")
+	assert.Error(t, err)
+}
+
+func Test_encodePEMDirectoryKeyNaming(t *testing.T) {
+	fingerprint := func(cert string) string {
+		sum := sha256.Sum256(dummy.JoinCertsDER(cert))
+		return hex.EncodeToString(sum[:])
+	}
+
+	tests := map[string]struct {
+		naming trustapi.PEMDirectoryKeyNaming
+
+		expKeys []string
+	}{
+		"Position naming should name certificates by position": {
+			naming:  trustapi.PEMDirectoryKeyNamingPosition,
+			expKeys: []string{"ca-0000.pem", "ca-0001.pem", "ca-0002.pem"},
+		},
+		"Fingerprint naming should name certificates by fingerprint": {
+			naming: trustapi.PEMDirectoryKeyNamingFingerprint,
+			expKeys: []string{
+				"ca-" + fingerprint(dummy.TestCertificate1) + ".pem",
+				"ca-" + fingerprint(dummy.TestCertificate3) + ".pem",
+				"ca-" + fingerprint(dummy.TestCertificate2) + ".pem",
+			},
+		},
+		"SubjectHash naming should name certificates by subject hash": {
+			naming:  trustapi.PEMDirectoryKeyNamingSubjectHash,
+			expKeys: []string{"ca-f69c9054.0.pem", "ca-4042bcee.0.pem", "ca-f69c9054.1.pem"},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			directory, err := encodePEMDirectory(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate2), "ca-", "index.txt", test.naming)
+			assert.NoError(t, err)
+
+			assert.Equal(t, strings.Join(test.expKeys, "\n")+"\n", directory["index.txt"])
+			for i, cert := range []string{dummy.TestCertificate1, dummy.TestCertificate3, dummy.TestCertificate2} {
+				assert.Equal(t, strings.TrimSpace(cert)+"\n", directory[test.expKeys[i]])
+			}
+		})
+	}
+}
+
+func Test_syncPEMDirectory(t *testing.T) {
+	directory := map[string]string{
+		"ca-0000.pem": "cert-0",
+		"index.txt":   "ca-0000.pem\n",
+	}
+
+	tests := map[string]struct {
+		data      map[string]string
+		directory map[string]string
+
+		expData    map[string]string
+		expChanged bool
+	}{
+		"empty ConfigMap should have the directory written": {
+			directory:  directory,
+			expData:    directory,
+			expChanged: true,
+		},
+		"up to date directory should not be changed": {
+			data:      map[string]string{"trust.pem": "bundle", "ca-0000.pem": "cert-0", "index.txt": "ca-0000.pem\n"},
+			directory: directory,
+			expData:   map[string]string{"trust.pem": "bundle", "ca-0000.pem": "cert-0", "index.txt": "ca-0000.pem\n"},
+		},
+		"entries no longer in the directory should be removed": {
+			data:       map[string]string{"trust.pem": "bundle", "ca-0000.pem": "old-0", "ca-0001.pem": "old-1", "index.txt": "ca-0000.pem\nca-0001.pem\n"},
+			directory:  directory,
+			expData:    map[string]string{"trust.pem": "bundle", "ca-0000.pem": "cert-0", "index.txt": "ca-0000.pem\n"},
+			expChanged: true,
+		},
+		"entries not listed in the index should be kept": {
+			data:       map[string]string{"ca-0005.pem": "other"},
+			directory:  directory,
+			expData:    map[string]string{"ca-0005.pem": "other", "ca-0000.pem": "cert-0", "index.txt": "ca-0000.pem\n"},
+			expChanged: true,
+		},
+		"removing the directory should remove all entries and the index": {
+			data:       map[string]string{"trust.pem": "bundle", "ca-0000.pem": "cert-0", "index.txt": "ca-0000.pem\n"},
+			expData:    map[string]string{"trust.pem": "bundle"},
+			expChanged: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{Data: test.data}
+
+			changed := syncPEMDirectory(configMap, "index.txt", test.directory)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expData, configMap.Data)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// permission is a verb on a core resource which the controller needs.
+type permission struct {
+	verb     string
+	resource string
+}
+
+// namespaceClassPermissions are the permissions the controller needs in each
+// class of Namespace.
+var namespaceClassPermissions = map[trustapi.NamespaceClass][]permission{
+	trustapi.NamespaceClassSource: {
+		{"get", "configmaps"}, {"list", "configmaps"}, {"watch", "configmaps"},
+		{"get", "secrets"}, {"list", "secrets"}, {"watch", "secrets"},
+	},
+	trustapi.NamespaceClassTarget: {
+		{"get", "configmaps"}, {"create", "configmaps"}, {"update", "configmaps"},
+	},
+	trustapi.NamespaceClassExcluded: {
+		{"get", "configmaps"}, {"delete", "configmaps"},
+	},
+}
+
+// accessReviewFunc returns whether the controller is allowed to perform the
+// action described by the given attributes.
+type accessReviewFunc func(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error)
+
+// needsPermissionCheck returns true if a permission check has been requested
+// on the Bundle which has not yet been performed.
+func needsPermissionCheck(bundle *trustapi.Bundle) (string, bool) {
+	request, ok := bundle.Annotations[trustapi.BundleCheckPermissionsAnnotationKey]
+	if !ok {
+		return "", false
+	}
+
+	if bundle.Status.PermissionCheck != nil && bundle.Status.PermissionCheck.Request == request {
+		return "", false
+	}
+
+	return request, true
+}
+
+// checkPermissions checks whether the controller has the permissions needed
+// to sync the Bundle, in a representative Namespace of each class.
+func (b *bundle) checkPermissions(ctx context.Context, request string, namespaceSelector namespaceMatcher, namespaces []corev1.Namespace) (*trustapi.BundlePermissionCheck, error) {
+	classNamespaces := map[trustapi.NamespaceClass]string{
+		trustapi.NamespaceClassSource: b.Namespace,
+	}
+
+	sorted := make([]corev1.Namespace, len(namespaces))
+	copy(sorted, namespaces)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	for _, namespace := range sorted {
+		if namespace.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+
+		class := trustapi.NamespaceClassExcluded
+		if namespaceSelector.Matches(labels.Set(namespace.Labels)) && !namespaceSkipsTargets(&namespace) {
+			class = trustapi.NamespaceClassTarget
+		}
+
+		if _, ok := classNamespaces[class]; !ok {
+			classNamespaces[class] = namespace.Name
+		}
+	}
+
+	reviewAccess := b.reviewAccess
+	if reviewAccess == nil {
+		reviewAccess = b.selfSubjectAccessReview
+	}
+
+	check := &trustapi.BundlePermissionCheck{
+		Request:   request,
+		CheckTime: metav1.NewTime(b.clock.Now()),
+		Allowed:   true,
+	}
+
+	for _, class := range []trustapi.NamespaceClass{trustapi.NamespaceClassSource, trustapi.NamespaceClassTarget, trustapi.NamespaceClassExcluded} {
+		namespace, ok := classNamespaces[class]
+		if !ok {
+			continue
+		}
+
+		result := trustapi.NamespaceClassPermissions{
+			Class:     class,
+			Namespace: namespace,
+			Allowed:   true,
+		}
+
+		for _, perm := range namespaceClassPermissions[class] {
+			allowed, err := reviewAccess(ctx, authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      perm.verb,
+				Resource:  perm.resource,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to review access to %s %s in Namespace %q: %w", perm.verb, perm.resource, namespace, err)
+			}
+
+			if !allowed {
+				result.Allowed = false
+				result.Denied = append(result.Denied, perm.verb+" "+perm.resource)
+			}
+		}
+
+		check.Allowed = check.Allowed && result.Allowed
+		check.NamespaceClasses = append(check.NamespaceClasses, result)
+	}
+
+	return check, nil
+}
+
+// selfSubjectAccessReview reviews the access of the controller using a
+// SelfSubjectAccessReview.
+func (b *bundle) selfSubjectAccessReview(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+		},
+	}
+
+	if err := b.targetDirectClient.Create(ctx, review); err != nil {
+		return false, err
+	}
+
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	fakeclock "k8s.io/utils/clock/testing"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_needsPermissionCheck(t *testing.T) {
+	tests := map[string]struct {
+		annotations map[string]string
+		check       *trustapi.BundlePermissionCheck
+
+		expRequest string
+		expNeeds   bool
+	}{
+		"no annotation should not need a check": {
+			expNeeds: false,
+		},
+		"annotation without a previous check should need a check": {
+			annotations: map[string]string{trustapi.BundleCheckPermissionsAnnotationKey: "1"},
+			expRequest:  "1",
+			expNeeds:    true,
+		},
+		"annotation with a previous check for the same request should not need a check": {
+			annotations: map[string]string{trustapi.BundleCheckPermissionsAnnotationKey: "1"},
+			check:       &trustapi.BundlePermissionCheck{Request: "1"},
+			expNeeds:    false,
+		},
+		"annotation with a previous check for another request should need a check": {
+			annotations: map[string]string{trustapi.BundleCheckPermissionsAnnotationKey: "2"},
+			check:       &trustapi.BundlePermissionCheck{Request: "1"},
+			expRequest:  "2",
+			expNeeds:    true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bundle := &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Annotations: test.annotations},
+				Status:     trustapi.BundleStatus{PermissionCheck: test.check},
+			}
+
+			request, needs := needsPermissionCheck(bundle)
+			assert.Equal(t, test.expRequest, request)
+			assert.Equal(t, test.expNeeds, needs)
+		})
+	}
+}
+
+func Test_checkPermissions(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	fixedTime := time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)
+
+	namespaces := []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-b", Labels: map[string]string{"trust": "enabled"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"trust": "enabled"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "deleted"}, Status: corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating}},
+	}
+
+	allowAll := func(context.Context, authorizationv1.ResourceAttributes) (bool, error) {
+		return true, nil
+	}
+
+	tests := map[string]struct {
+		selector     labels.Selector
+		reviewAccess accessReviewFunc
+
+		expCheck *trustapi.BundlePermissionCheck
+		expError bool
+	}{
+		"all permissions allowed with a selector should check every class": {
+			selector:     labels.SelectorFromSet(labels.Set{"trust": "enabled"}),
+			reviewAccess: allowAll,
+			expCheck: &trustapi.BundlePermissionCheck{
+				Request:   "1",
+				CheckTime: metav1.NewTime(fixedTime),
+				Allowed:   true,
+				NamespaceClasses: []trustapi.NamespaceClassPermissions{
+					{Class: trustapi.NamespaceClassSource, Namespace: trustNamespace, Allowed: true},
+					{Class: trustapi.NamespaceClassTarget, Namespace: "team-a", Allowed: true},
+					{Class: trustapi.NamespaceClassExcluded, Namespace: "other", Allowed: true},
+				},
+			},
+		},
+		"all permissions allowed without a selector should not check excluded Namespaces": {
+			selector:     labels.Everything(),
+			reviewAccess: allowAll,
+			expCheck: &trustapi.BundlePermissionCheck{
+				Request:   "1",
+				CheckTime: metav1.NewTime(fixedTime),
+				Allowed:   true,
+				NamespaceClasses: []trustapi.NamespaceClassPermissions{
+					{Class: trustapi.NamespaceClassSource, Namespace: trustNamespace, Allowed: true},
+					{Class: trustapi.NamespaceClassTarget, Namespace: "other", Allowed: true},
+				},
+			},
+		},
+		"missing permissions should be listed for their class": {
+			selector: labels.SelectorFromSet(labels.Set{"trust": "enabled"}),
+			reviewAccess: func(_ context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+				switch {
+				case attributes.Namespace == trustNamespace && attributes.Resource == "secrets" && attributes.Verb == "list":
+					return false, nil
+				case attributes.Namespace == "other" && attributes.Verb == "delete":
+					return false, nil
+				}
+				return true, nil
+			},
+			expCheck: &trustapi.BundlePermissionCheck{
+				Request:   "1",
+				CheckTime: metav1.NewTime(fixedTime),
+				Allowed:   false,
+				NamespaceClasses: []trustapi.NamespaceClassPermissions{
+					{Class: trustapi.NamespaceClassSource, Namespace: trustNamespace, Allowed: false, Denied: []string{"list secrets"}},
+					{Class: trustapi.NamespaceClassTarget, Namespace: "team-a", Allowed: true},
+					{Class: trustapi.NamespaceClassExcluded, Namespace: "other", Allowed: false, Denied: []string{"delete configmaps"}},
+				},
+			},
+		},
+		"failing access review should return error": {
+			selector: labels.Everything(),
+			reviewAccess: func(context.Context, authorizationv1.ResourceAttributes) (bool, error) {
+				return false, errors.New("connection refused")
+			},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{
+				clock:        fakeclock.NewFakeClock(fixedTime),
+				reviewAccess: test.reviewAccess,
+				Options:      Options{Namespace: trustNamespace},
+			}
+
+			check, err := b.checkPermissions(context.TODO(), "1", test.selector, namespaces)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expCheck, check)
+		})
+	}
+}
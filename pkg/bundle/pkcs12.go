@@ -0,0 +1,121 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/asn1"
+
+	"software.sslmate.com/src/go-pkcs12"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+var (
+	// oidDataContentType is the PKCS#7 data content type, which wraps the
+	// authenticated safe of a PKCS#12 file.
+	oidDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+
+	// oidEncryptedDataContentType is the PKCS#7 encryptedData content type,
+	// which holds the encrypted certificates of a PKCS#12 truststore.
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+)
+
+// pkcs12ProfileAlgorithms are the algorithms used to encrypt the certificates
+// of PKCS#12 truststores, by profile.
+var pkcs12ProfileAlgorithms = map[trustapi.PKCS12Profile]asn1.ObjectIdentifier{
+	trustapi.PKCS12ProfileLegacyRC2:  {1, 2, 840, 113549, 1, 12, 1, 6},
+	trustapi.PKCS12ProfileLegacyDES:  {1, 2, 840, 113549, 1, 12, 1, 3},
+	trustapi.PKCS12ProfileModern2023: {1, 2, 840, 113549, 1, 5, 13},
+}
+
+// pkcs12PFX is the top level PKCS#12 structure, as defined in RFC 7292.
+type pkcs12PFX struct {
+	Version  int
+	AuthSafe pkcs12ContentInfo
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+// pkcs12ContentInfo is the PKCS#7 ContentInfo structure, as defined in RFC 2315.
+type pkcs12ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// pkcs12EncryptedData is the PKCS#7 EncryptedData structure, as defined in
+// RFC 2315. Only the encryption algorithm is of interest to trust-manager.
+type pkcs12EncryptedData struct {
+	Version              int
+	EncryptedContentInfo struct {
+		ContentType                asn1.ObjectIdentifier
+		ContentEncryptionAlgorithm struct {
+			Algorithm  asn1.ObjectIdentifier
+			Parameters asn1.RawValue `asn1:"optional"`
+		}
+		EncryptedContent asn1.RawValue `asn1:"optional,tag:0"`
+	}
+}
+
+// pkcs12Encoder returns the PKCS#12 encoder of the given profile. Defaults to
+// the Modern2023 profile.
+func pkcs12Encoder(profile trustapi.PKCS12Profile) *pkcs12.Encoder {
+	switch profile {
+	case trustapi.PKCS12ProfileLegacyRC2:
+		return pkcs12.LegacyRC2
+	case trustapi.PKCS12ProfileLegacyDES:
+		return pkcs12.LegacyDES
+	default:
+		return pkcs12.Modern2023
+	}
+}
+
+// pkcs12HasProfile returns true if the certificates of the given binary
+// PKCS#12 truststore are encrypted with the algorithm of the given profile.
+func pkcs12HasProfile(data []byte, profile trustapi.PKCS12Profile) bool {
+	if len(profile) == 0 {
+		profile = trustapi.PKCS12ProfileModern2023
+	}
+
+	var pfx pkcs12PFX
+	if _, err := asn1.Unmarshal(data, &pfx); err != nil || !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return false
+	}
+
+	var authSafeData []byte
+	if _, err := asn1.Unmarshal(pfx.AuthSafe.Content.Bytes, &authSafeData); err != nil {
+		return false
+	}
+
+	var authSafe []pkcs12ContentInfo
+	if _, err := asn1.Unmarshal(authSafeData, &authSafe); err != nil {
+		return false
+	}
+
+	for _, contentInfo := range authSafe {
+		if !contentInfo.ContentType.Equal(oidEncryptedDataContentType) {
+			continue
+		}
+
+		var encryptedData pkcs12EncryptedData
+		if _, err := asn1.Unmarshal(contentInfo.Content.Bytes, &encryptedData); err != nil {
+			return false
+		}
+
+		return encryptedData.EncryptedContentInfo.ContentEncryptionAlgorithm.Algorithm.Equal(pkcs12ProfileAlgorithms[profile])
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_pkcs12HasProfile(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3)
+
+	profiles := []trustapi.PKCS12Profile{
+		trustapi.PKCS12ProfileLegacyRC2,
+		trustapi.PKCS12ProfileLegacyDES,
+		trustapi.PKCS12ProfileModern2023,
+	}
+
+	for _, encodedProfile := range profiles {
+		p12, err := encodePKCS12(data, "password", encodedProfile)
+		assert.NoError(t, err)
+
+		assert.True(t, pkcs12HasPassword(p12, "password"), "%s: expected truststore to be decodable", encodedProfile)
+
+		for _, profile := range profiles {
+			assert.Equal(t, encodedProfile == profile, pkcs12HasProfile(p12, profile), "encoded with %s, checked against %s", encodedProfile, profile)
+		}
+	}
+
+	modern, err := encodePKCS12(data, "", "")
+	assert.NoError(t, err)
+	assert.True(t, pkcs12HasProfile(modern, ""), "expected the default profile to be Modern2023")
+	assert.True(t, pkcs12HasProfile(modern, trustapi.PKCS12ProfileModern2023), "expected the default profile to be Modern2023")
+
+	assert.False(t, pkcs12HasProfile([]byte("not a truststore"), ""), "expected invalid data to have no profile")
+}
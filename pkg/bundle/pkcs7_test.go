@@ -0,0 +1,115 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_syncTarget_pkcs7(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "trust.pem"
+		pkcs7Key   = "trust.p7b"
+		data       = dummy.TestCertificate1
+	)
+
+	p7b, err := util.EncodePKCS7Bundle([]byte(data))
+	assert.NoError(t, err)
+
+	targetConfigMap := func(binaryData map[string][]byte) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundleName,
+				Namespace: "test-namespace",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: bundleName}}, trustapi.SchemeGroupVersion.WithKind("Bundle")),
+				},
+			},
+			Data:       map[string]string{key: data},
+			BinaryData: binaryData,
+		}
+	}
+
+	tests := map[string]struct {
+		object runtime.Object
+
+		expNeedsUpdate bool
+	}{
+		"missing target should be created with PKCS#7 bundle": {
+			expNeedsUpdate: true,
+		},
+		"up to date PKCS#7 bundle should not be updated": {
+			object: targetConfigMap(map[string][]byte{pkcs7Key: p7b}),
+		},
+		"missing PKCS#7 bundle should be added": {
+			object:         targetConfigMap(nil),
+			expNeedsUpdate: true,
+		},
+		"stale PKCS#7 bundle should be updated": {
+			object:         targetConfigMap(map[string][]byte{pkcs7Key: []byte("stale")}),
+			expNeedsUpdate: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeclient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1)}
+
+			spec := trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: key},
+				AdditionalFormats: &trustapi.AdditionalFormats{PKCS7: &trustapi.KeySelector{Key: pkcs7Key}},
+			}}
+
+			namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+			needsUpdate, _, err := b.syncTarget(context.TODO(), klogr.New(), &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+				Spec:       spec,
+			}, labels.Everything(), &namespace, data, "", "", "", "", nil, nil, nil, []byte(DefaultJKSPassword), "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNeedsUpdate, needsUpdate)
+
+			var configMap corev1.ConfigMap
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: namespace.Name, Name: bundleName}, &configMap))
+
+			assert.Equal(t, data, configMap.Data[key])
+			assert.Equal(t, p7b, configMap.BinaryData[pkcs7Key])
+		})
+	}
+}
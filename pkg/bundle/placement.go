@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+var (
+	// placementDecisionGVK is the GroupVersionKind of Open Cluster Management
+	// PlacementDecisions, which list the clusters selected by a Placement.
+	placementDecisionGVK = schema.GroupVersionKind{Group: "cluster.open-cluster-management.io", Version: "v1beta1", Kind: "PlacementDecision"}
+
+	// manifestWorkGVK is the GroupVersionKind of Open Cluster Management
+	// ManifestWorks, which are applied to a managed cluster by its work agent.
+	manifestWorkGVK = schema.GroupVersionKind{Group: "work.open-cluster-management.io", Version: "v1", Kind: "ManifestWork"}
+)
+
+const (
+	// placementLabel is the label set by Open Cluster Management on
+	// PlacementDecisions, with the name of the Placement they belong to.
+	placementLabel = "cluster.open-cluster-management.io/placement"
+
+	// manifestWorkHashAnnotation is the annotation set on ManifestWorks created
+	// for a Bundle's placement, with the hash of their manifests. It is used to
+	// detect whether a ManifestWork needs to be updated.
+	manifestWorkHashAnnotation = "trust.cert-manager.io/manifest-hash"
+)
+
+// manifestWorkName returns the name of the ManifestWork distributing the
+// given Bundle to a managed cluster.
+func manifestWorkName(bundle string) string {
+	return "trust-manager-" + bundle
+}
+
+// syncPlacement distributes the given bundle data to the managed clusters
+// selected by the Bundle's placement, and removes it from managed clusters
+// which are no longer selected. Returns the sorted names of the managed
+// clusters the Bundle is distributed to.
+func (b *bundle) syncPlacement(ctx context.Context, bundle *trustapi.Bundle, data string) ([]string, error) {
+	var clusters []string
+	if bundle.Spec.Placement != nil {
+		var err error
+		clusters, err = b.placementClusters(ctx, bundle.Spec.Placement)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, cluster := range clusters {
+		if err := b.syncManifestWork(ctx, bundle, cluster, data); err != nil {
+			return nil, fmt.Errorf("failed to sync ManifestWork for managed cluster %q: %w", cluster, err)
+		}
+	}
+
+	// Only look for ManifestWorks to remove if the Bundle may have been
+	// distributed before, to avoid listing them for every Bundle.
+	if bundle.Spec.Placement == nil && len(bundle.Status.ManagedClusters) == 0 {
+		return nil, nil
+	}
+
+	var works unstructured.UnstructuredList
+	works.SetGroupVersionKind(manifestWorkGVK.GroupVersion().WithKind(manifestWorkGVK.Kind + "List"))
+	if err := b.targetDirectClient.List(ctx, &works, client.MatchingLabels{b.Naming.BundleLabelKey(): bundle.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list ManifestWorks: %w", err)
+	}
+
+	selected := sets.New(clusters...)
+	for i := range works.Items {
+		work := &works.Items[i]
+		if selected.Has(work.GetNamespace()) || !metav1.IsControlledBy(work, bundle) {
+			continue
+		}
+
+		if err := b.targetDirectClient.Delete(ctx, work); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to delete ManifestWork for managed cluster %q: %w", work.GetNamespace(), err)
+		}
+	}
+
+	return clusters, nil
+}
+
+// placementClusters returns the sorted names of the managed clusters selected
+// by the referenced Placement, as listed in its PlacementDecisions.
+func (b *bundle) placementClusters(ctx context.Context, ref *trustapi.PlacementReference) ([]string, error) {
+	var decisions unstructured.UnstructuredList
+	decisions.SetGroupVersionKind(placementDecisionGVK.GroupVersion().WithKind(placementDecisionGVK.Kind + "List"))
+	if err := b.targetDirectClient.List(ctx, &decisions, client.InNamespace(ref.Namespace), client.MatchingLabels{placementLabel: ref.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list PlacementDecisions: %w", err)
+	}
+
+	clusters := sets.New[string]()
+	for _, decision := range decisions.Items {
+		entries, _, err := unstructured.NestedSlice(decision.Object, "status", "decisions")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read decisions of PlacementDecision %q: %w", decision.GetName(), err)
+		}
+
+		for _, entry := range entries {
+			entry, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			if name, ok := entry["clusterName"].(string); ok && len(name) > 0 {
+				clusters.Insert(name)
+			}
+		}
+	}
+
+	return sets.List(clusters), nil
+}
+
+// syncManifestWork ensures the ManifestWork distributing the given bundle data
+// to the given managed cluster is up to date.
+func (b *bundle) syncManifestWork(ctx context.Context, bundle *trustapi.Bundle, cluster, data string) error {
+	desired, err := placementManifestWork(bundle, cluster, data, b.Naming.BundleLabelKey())
+	if err != nil {
+		return err
+	}
+
+	existing := new(unstructured.Unstructured)
+	existing.SetGroupVersionKind(manifestWorkGVK)
+	err = b.targetDirectClient.Get(ctx, client.ObjectKeyFromObject(desired), existing)
+	if apierrors.IsNotFound(err) {
+		return b.targetDirectClient.Create(ctx, desired)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !metav1.IsControlledBy(existing, bundle) {
+		return fmt.Errorf("ManifestWork %s/%s already exists and is not owned by the Bundle", cluster, desired.GetName())
+	}
+
+	if existing.GetAnnotations()[manifestWorkHashAnnotation] == desired.GetAnnotations()[manifestWorkHashAnnotation] {
+		return nil
+	}
+
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	return b.targetDirectClient.Update(ctx, desired)
+}
+
+// placementManifestWork returns the ManifestWork distributing the given bundle
+// data to the given managed cluster. The ManifestWork contains a copy of the
+// Bundle whose sources are replaced with the resolved bundle data, so that the
+// copy can be synced without access to the sources on the hub cluster. The
+// ManifestWork is labelled with the Bundle's name using the given label key.
+func placementManifestWork(bundle *trustapi.Bundle, cluster, data, bundleLabelKey string) (*unstructured.Unstructured, error) {
+	placed := &trustapi.Bundle{
+		TypeMeta:   metav1.TypeMeta{APIVersion: trustapi.SchemeGroupVersion.String(), Kind: "Bundle"},
+		ObjectMeta: metav1.ObjectMeta{Name: bundle.Name},
+		Spec: trustapi.BundleSpec{
+			Sources: []trustapi.BundleSource{{InLine: &data}},
+			Target:  bundle.Spec.Target,
+		},
+	}
+
+	manifest, err := runtime.DefaultUnstructuredConverter.ToUnstructured(placed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert Bundle to unstructured: %w", err)
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode Bundle manifest: %w", err)
+	}
+
+	work := new(unstructured.Unstructured)
+	work.SetGroupVersionKind(manifestWorkGVK)
+	work.SetName(manifestWorkName(bundle.Name))
+	work.SetNamespace(cluster)
+	work.SetLabels(map[string]string{bundleLabelKey: bundle.Name})
+	work.SetAnnotations(map[string]string{manifestWorkHashAnnotation: contentHash(string(manifestJSON))})
+	work.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))})
+
+	if err := unstructured.SetNestedSlice(work.Object, []any{manifest}, "spec", "workload", "manifests"); err != nil {
+		return nil, fmt.Errorf("failed to set ManifestWork manifests: %w", err)
+	}
+
+	return work, nil
+}
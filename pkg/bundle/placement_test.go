@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_syncPlacement(t *testing.T) {
+	placementDecision := func(placement string, clusters ...string) *unstructured.Unstructured {
+		var decisions []any
+		for _, cluster := range clusters {
+			decisions = append(decisions, map[string]any{"clusterName": cluster, "reason": ""})
+		}
+
+		decision := new(unstructured.Unstructured)
+		decision.SetGroupVersionKind(placementDecisionGVK)
+		decision.SetName(placement + "-decision-1")
+		decision.SetNamespace("trust")
+		decision.SetLabels(map[string]string{placementLabel: placement})
+		if err := unstructured.SetNestedSlice(decision.Object, decisions, "status", "decisions"); err != nil {
+			t.Fatal(err)
+		}
+		return decision
+	}
+
+	placedBundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-bundle", UID: "test-uid"},
+		Spec: trustapi.BundleSpec{
+			Sources:   []trustapi.BundleSource{{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"}}},
+			Target:    trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			Placement: &trustapi.PlacementReference{Name: "all-clusters", Namespace: "trust"},
+		},
+		Status: trustapi.BundleStatus{ManagedClusters: []string{"cluster-3"}},
+	}
+
+	staleWork, err := placementManifestWork(placedBundle, "cluster-3", dummy.TestCertificate2, naming.DefaultBundleLabelKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outdatedWork, err := placementManifestWork(placedBundle, "cluster-2", dummy.TestCertificate2, naming.DefaultBundleLabelKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unownedWork, err := placementManifestWork(placedBundle, "cluster-4", dummy.TestCertificate2, naming.DefaultBundleLabelKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unownedWork.SetOwnerReferences(nil)
+
+	b := &bundle{
+		targetDirectClient: fakeclient.NewClientBuilder().
+			WithScheme(trustapi.GlobalScheme).
+			WithRuntimeObjects([]runtime.Object{
+				placementDecision("all-clusters", "cluster-2", "cluster-1"),
+				placementDecision("other-clusters", "cluster-5"),
+				staleWork,
+				outdatedWork,
+				unownedWork,
+			}...).
+			Build(),
+	}
+
+	clusters, err := b.syncPlacement(context.TODO(), placedBundle, dummy.TestCertificate1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{"cluster-1", "cluster-2"}, clusters)
+
+	var works unstructured.UnstructuredList
+	works.SetGroupVersionKind(manifestWorkGVK.GroupVersion().WithKind(manifestWorkGVK.Kind + "List"))
+	if err := b.targetDirectClient.List(context.TODO(), &works); err != nil {
+		t.Fatal(err)
+	}
+
+	namespaces := make(map[string]*unstructured.Unstructured)
+	for i := range works.Items {
+		namespaces[works.Items[i].GetNamespace()] = &works.Items[i]
+	}
+	assert.NotContains(t, namespaces, "cluster-3", "ManifestWork of deselected cluster should be deleted")
+	assert.Contains(t, namespaces, "cluster-4", "ManifestWork not owned by the Bundle should not be deleted")
+
+	for _, cluster := range []string{"cluster-1", "cluster-2"} {
+		if !assert.Contains(t, namespaces, cluster) {
+			continue
+		}
+
+		manifests, _, err := unstructured.NestedSlice(namespaces[cluster].Object, "spec", "workload", "manifests")
+		if !assert.NoError(t, err) || !assert.Len(t, manifests, 1) {
+			continue
+		}
+
+		var placed trustapi.Bundle
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(manifests[0].(map[string]any), &placed); err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "Bundle", placed.Kind)
+		assert.Equal(t, placedBundle.Name, placed.Name)
+		assert.Equal(t, []trustapi.BundleSource{{InLine: pointer.String(dummy.TestCertificate1)}}, placed.Spec.Sources)
+		assert.Equal(t, placedBundle.Spec.Target, placed.Spec.Target)
+		assert.Nil(t, placed.Spec.Placement)
+	}
+
+	// Removing the placement should remove the Bundle from all clusters.
+	placedBundle.Spec.Placement = nil
+	placedBundle.Status.ManagedClusters = clusters
+	clusters, err = b.syncPlacement(context.TODO(), placedBundle, dummy.TestCertificate1)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Empty(t, clusters)
+
+	if err := b.targetDirectClient.List(context.TODO(), &works, client.MatchingLabels{naming.DefaultBundleLabelKey: placedBundle.Name}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, works.Items, 1, "only the ManifestWork not owned by the Bundle should remain")
+}
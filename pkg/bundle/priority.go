@@ -0,0 +1,65 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"sort"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// priorityRank returns the rank of the given priority class, where Bundles of
+// a lower rank are reconciled first.
+func priorityRank(class trustapi.BundlePriorityClass) int {
+	switch class {
+	case trustapi.BundlePriorityClassCritical:
+		return 0
+	case trustapi.BundlePriorityClassLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// sortBundlesByPriority sorts the given Bundles by their priority class,
+// highest priority first, so that requests for them are enqueued in that
+// order when a change affects many Bundles. Bundles of the same priority class
+// keep their order.
+func sortBundlesByPriority(bundles []trustapi.Bundle) {
+	sort.SliceStable(bundles, func(i, j int) bool {
+		return priorityRank(bundles[i].Spec.PriorityClass) < priorityRank(bundles[j].Spec.PriorityClass)
+	})
+}
+
+// targetWriteBudget returns the target write budget of a Bundle of the given
+// priority class, given the controller's target write budget. Zero disables
+// the budget.
+func targetWriteBudget(budget int, class trustapi.BundlePriorityClass) int {
+	if budget <= 0 {
+		return 0
+	}
+
+	switch class {
+	case trustapi.BundlePriorityClassCritical:
+		return 0
+	case trustapi.BundlePriorityClassLow:
+		// Round up, so that a budget of one still allows progress.
+		return (budget + 1) / 2
+	default:
+		return budget
+	}
+}
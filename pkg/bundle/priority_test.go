@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_sortBundlesByPriority(t *testing.T) {
+	newBundle := func(name string, class trustapi.BundlePriorityClass) trustapi.Bundle {
+		return trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: trustapi.BundleSpec{PriorityClass: class}}
+	}
+
+	bundles := []trustapi.Bundle{
+		newBundle("low", trustapi.BundlePriorityClassLow),
+		newBundle("default", ""),
+		newBundle("critical", trustapi.BundlePriorityClassCritical),
+		newBundle("normal", trustapi.BundlePriorityClassNormal),
+	}
+
+	sortBundlesByPriority(bundles)
+
+	var names []string
+	for _, bundle := range bundles {
+		names = append(names, bundle.Name)
+	}
+	assert.Equal(t, []string{"critical", "default", "normal", "low"}, names)
+}
+
+func Test_mustBundleList(t *testing.T) {
+	b := &bundle{
+		sourceLister: fakeclient.NewClientBuilder().
+			WithScheme(trustapi.GlobalScheme).
+			WithObjects(
+				&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "a-low"}, Spec: trustapi.BundleSpec{PriorityClass: trustapi.BundlePriorityClassLow}},
+				&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "b-normal"}},
+				&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "c-critical"}, Spec: trustapi.BundleSpec{PriorityClass: trustapi.BundlePriorityClassCritical}},
+			).
+			Build(),
+	}
+
+	// Requests are enqueued in the order of the returned list, so Critical
+	// Bundles must come first to be reconciled first.
+	var names []string
+	for _, bundle := range b.mustBundleList(context.TODO()).Items {
+		names = append(names, bundle.Name)
+	}
+	assert.Equal(t, []string{"c-critical", "b-normal", "a-low"}, names)
+}
+
+func Test_targetWriteBudget(t *testing.T) {
+	tests := map[string]struct {
+		budget int
+		class  trustapi.BundlePriorityClass
+
+		expBudget int
+	}{
+		"disabled budget should stay disabled": {
+			budget:    0,
+			class:     trustapi.BundlePriorityClassLow,
+			expBudget: 0,
+		},
+		"default priority class should use the budget": {
+			budget:    10,
+			expBudget: 10,
+		},
+		"critical priority class should be exempt from the budget": {
+			budget:    10,
+			class:     trustapi.BundlePriorityClassCritical,
+			expBudget: 0,
+		},
+		"low priority class should use half of the budget": {
+			budget:    10,
+			class:     trustapi.BundlePriorityClassLow,
+			expBudget: 5,
+		},
+		"low priority class should be able to write with a budget of one": {
+			budget:    1,
+			class:     trustapi.BundlePriorityClassLow,
+			expBudget: 1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expBudget, targetWriteBudget(test.budget, test.class))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// targetProfiles returns the profiles of the target.
+func targetProfiles(target trustapi.BundleTarget) []trustapi.TrustProfile {
+	if target.AdditionalFormats == nil {
+		return nil
+	}
+
+	return target.AdditionalFormats.Profiles
+}
+
+// addCertificatePurposes records the purposes each certificate in the given
+// PEM bundle is trusted for, keyed by certificate fingerprint. The purposes of
+// the source take precedence over the purposes published in a default CA
+// package. Certificates without either aren't recorded, so that their
+// extended key usage extension applies. Where the same certificate is
+// provided by multiple sources, it is trusted for the purposes of all of them.
+func addCertificatePurposes(certificatePurposes map[string]sets.Set[trustapi.TrustPurpose], data []byte, sourcePurposes []trustapi.TrustPurpose, packagePurposes map[string][]trustapi.TrustPurpose) error {
+	if len(sourcePurposes) == 0 && len(packagePurposes) == 0 {
+		return nil
+	}
+
+	published := make(map[string][]trustapi.TrustPurpose, len(packagePurposes))
+	for fingerprint, purposes := range packagePurposes {
+		parsed, err := util.ParseFingerprint(fingerprint)
+		if err != nil {
+			return fmt.Errorf("invalid purposes fingerprint %q: %w", fingerprint, err)
+		}
+		published[parsed] = purposes
+	}
+
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return err
+	}
+
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		fingerprint := certificateFingerprint(block.Bytes)
+
+		purposes := sourcePurposes
+		if len(purposes) == 0 {
+			var ok bool
+			if purposes, ok = published[fingerprint]; !ok {
+				continue
+			}
+		}
+
+		if certificatePurposes[fingerprint] == nil {
+			certificatePurposes[fingerprint] = sets.New[trustapi.TrustPurpose]()
+		}
+		certificatePurposes[fingerprint].Insert(purposes...)
+	}
+
+	return nil
+}
+
+// trustedFor returns true if the given certificate is trusted for the given
+// purpose. Certificates whose purposes were recorded from their sources are
+// trusted for those purposes, and all other certificates for the purposes
+// allowed by their extended key usage extension.
+func trustedFor(cert *x509.Certificate, fingerprint string, purpose trustapi.TrustPurpose, certificatePurposes map[string]sets.Set[trustapi.TrustPurpose]) bool {
+	if purpose == trustapi.TrustPurposeAny {
+		return true
+	}
+
+	if purposes, ok := certificatePurposes[fingerprint]; ok {
+		return purposes.HasAny(trustapi.TrustPurposeAny, purpose)
+	}
+
+	return util.HasExtendedKeyUsages(cert, []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsage(purpose)})
+}
+
+// encodeProfiles returns the data of each of the given profiles of the given
+// PEM bundle, keyed by entry key. Each profile contains the certificates of
+// the bundle which are trusted for its purpose, in bundle order, and is empty
+// if there are none.
+func encodeProfiles(data string, profiles []trustapi.TrustProfile, certificatePurposes map[string]sets.Set[trustapi.TrustPurpose]) (map[string]string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	parsed := make([]*x509.Certificate, len(certificates))
+	fingerprints := make([]string, len(certificates))
+	for i, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+
+		parsed[i], err = x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		fingerprints[i] = certificateFingerprint(block.Bytes)
+	}
+
+	encoded := make(map[string]string, len(profiles))
+	for _, profile := range profiles {
+		var trusted [][]byte
+		for i, certificate := range certificates {
+			if trustedFor(parsed[i], fingerprints[i], profile.Purpose, certificatePurposes) {
+				trusted = append(trusted, certificate)
+			}
+		}
+
+		encoded[profile.Key] = string(bytes.Join(trusted, nil))
+	}
+
+	return encoded, nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// newPurposeTestCA returns a self-signed PEM CA certificate with the given
+// extended key usages, along with its fingerprint.
+func newPurposeTestCA(t *testing.T, name string, usages ...x509.ExtKeyUsage) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		ExtKeyUsage:           usages,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), certificateFingerprint(der)
+}
+
+func Test_encodeProfiles(t *testing.T) {
+	serverCA, serverFingerprint := newPurposeTestCA(t, "server-ca", x509.ExtKeyUsageServerAuth)
+	clientCA, _ := newPurposeTestCA(t, "client-ca", x509.ExtKeyUsageClientAuth)
+	anyCA, _ := newPurposeTestCA(t, "any-ca")
+
+	data := serverCA + clientCA + anyCA
+
+	tests := map[string]struct {
+		purpose             trustapi.TrustPurpose
+		certificatePurposes map[string]sets.Set[trustapi.TrustPurpose]
+
+		expData string
+	}{
+		"Any profile should contain all certificates": {
+			purpose: trustapi.TrustPurposeAny,
+			expData: data,
+		},
+		"ServerAuth profile should contain certificates valid for server authentication": {
+			purpose: trustapi.TrustPurposeServerAuth,
+			expData: serverCA + anyCA,
+		},
+		"ClientAuth profile should contain certificates valid for client authentication": {
+			purpose: trustapi.TrustPurposeClientAuth,
+			expData: clientCA + anyCA,
+		},
+		"purposes of sources should take precedence over extended key usages": {
+			purpose: trustapi.TrustPurposeClientAuth,
+			certificatePurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				serverFingerprint: sets.New(trustapi.TrustPurposeClientAuth),
+			},
+			expData: serverCA + clientCA + anyCA,
+		},
+		"certificates whose sources set no matching purpose should be omitted": {
+			purpose: trustapi.TrustPurposeServerAuth,
+			certificatePurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				serverFingerprint: sets.New(trustapi.TrustPurposeClientAuth),
+			},
+			expData: anyCA,
+		},
+		"certificates whose sources trust them for any purpose should be included": {
+			purpose: trustapi.TrustPurposeClientAuth,
+			certificatePurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				serverFingerprint: sets.New(trustapi.TrustPurposeAny),
+			},
+			expData: serverCA + clientCA + anyCA,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			profiles, err := encodeProfiles(data, []trustapi.TrustProfile{{Key: "profile.pem", Purpose: test.purpose}}, test.certificatePurposes)
+			assert.NoError(t, err)
+
+			assert.Equal(t, map[string]string{"profile.pem": test.expData}, profiles)
+		})
+	}
+}
+
+func Test_addCertificatePurposes(t *testing.T) {
+	firstCA, firstFingerprint := newPurposeTestCA(t, "first-ca")
+	secondCA, secondFingerprint := newPurposeTestCA(t, "second-ca")
+
+	data := []byte(firstCA + secondCA)
+
+	tests := map[string]struct {
+		existing        map[string]sets.Set[trustapi.TrustPurpose]
+		sourcePurposes  []trustapi.TrustPurpose
+		packagePurposes map[string][]trustapi.TrustPurpose
+
+		expPurposes map[string]sets.Set[trustapi.TrustPurpose]
+	}{
+		"no purposes should record nothing": {
+			expPurposes: map[string]sets.Set[trustapi.TrustPurpose]{},
+		},
+		"purposes of the source should be recorded for all certificates": {
+			sourcePurposes: []trustapi.TrustPurpose{trustapi.TrustPurposeServerAuth},
+			expPurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				firstFingerprint:  sets.New(trustapi.TrustPurposeServerAuth),
+				secondFingerprint: sets.New(trustapi.TrustPurposeServerAuth),
+			},
+		},
+		"purposes of the package should only be recorded for listed certificates": {
+			packagePurposes: map[string][]trustapi.TrustPurpose{
+				strings.ToUpper(firstFingerprint): {trustapi.TrustPurposeClientAuth},
+			},
+			expPurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				firstFingerprint: sets.New(trustapi.TrustPurposeClientAuth),
+			},
+		},
+		"purposes of the source should take precedence over the package": {
+			sourcePurposes: []trustapi.TrustPurpose{trustapi.TrustPurposeServerAuth},
+			packagePurposes: map[string][]trustapi.TrustPurpose{
+				firstFingerprint: {trustapi.TrustPurposeClientAuth},
+			},
+			expPurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				firstFingerprint:  sets.New(trustapi.TrustPurposeServerAuth),
+				secondFingerprint: sets.New(trustapi.TrustPurposeServerAuth),
+			},
+		},
+		"purposes of multiple sources should be merged": {
+			existing: map[string]sets.Set[trustapi.TrustPurpose]{
+				firstFingerprint: sets.New(trustapi.TrustPurposeClientAuth),
+			},
+			sourcePurposes: []trustapi.TrustPurpose{trustapi.TrustPurposeServerAuth},
+			expPurposes: map[string]sets.Set[trustapi.TrustPurpose]{
+				firstFingerprint:  sets.New(trustapi.TrustPurposeClientAuth, trustapi.TrustPurposeServerAuth),
+				secondFingerprint: sets.New(trustapi.TrustPurposeServerAuth),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificatePurposes := make(map[string]sets.Set[trustapi.TrustPurpose])
+			for fingerprint, purposes := range test.existing {
+				certificatePurposes[fingerprint] = purposes.Clone()
+			}
+
+			err := addCertificatePurposes(certificatePurposes, data, test.sourcePurposes, test.packagePurposes)
+			assert.NoError(t, err)
+
+			assert.Equal(t, test.expPurposes, certificatePurposes)
+		})
+	}
+}
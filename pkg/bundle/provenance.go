@@ -0,0 +1,205 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// bundleProvenance is the JSON document written to the provenance target
+// format.
+type bundleProvenance struct {
+	Certificates []certificateProvenance `json:"certificates"`
+}
+
+// certificateProvenance records the sources a single certificate in the
+// bundle came from.
+type certificateProvenance struct {
+	// Fingerprint is the hex encoded SHA-256 digest of the DER certificate.
+	Fingerprint string `json:"fingerprint"`
+
+	Subject string `json:"subject"`
+
+	// Sources are the sources of the Bundle which provided the certificate,
+	// in the order they are defined. A certificate provided by several sources
+	// is only included in the bundle once.
+	Sources []sourceProvenance `json:"sources"`
+}
+
+// sourceProvenance identifies a source of a Bundle.
+type sourceProvenance struct {
+	// Index is the index of the source in the Bundle's sources.
+	Index int `json:"index"`
+
+	// Type is the type of the source, named after its field in the Bundle's
+	// spec, such as "configMap".
+	Type string `json:"type"`
+
+	// Namespace is the Namespace of the source object in a remote cluster.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the source object, or the bucket of an object
+	// storage source.
+	Name string `json:"name,omitempty"`
+
+	// Key is the key or key pattern of the source object's data, or the key
+	// of the object in an object storage bucket.
+	Key string `json:"key,omitempty"`
+
+	// Package is the ID of the default CA package used by the source,
+	// including its name and version.
+	Package string `json:"package,omitempty"`
+}
+
+// provenanceKey returns the key of the target entry the provenance document
+// is written to, and whether the target has the provenance format.
+func provenanceKey(target trustapi.BundleTarget) (string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.Provenance == nil {
+		return "", false
+	}
+
+	return target.AdditionalFormats.Provenance.Key, true
+}
+
+// describeSource returns the provenance of the certificates of the source
+// with the given index, which used the given default CA package, if any.
+func describeSource(index int, source trustapi.BundleSource, pkg *fspkg.Package) sourceProvenance {
+	provenance := sourceProvenance{Index: index}
+
+	objectKey := func(ref *trustapi.SourceObjectKeySelector) string {
+		if len(ref.KeyPattern) > 0 {
+			return ref.KeyPattern
+		}
+		return ref.Key
+	}
+
+	switch {
+	case source.ConfigMap != nil:
+		provenance.Type, provenance.Name, provenance.Key = "configMap", source.ConfigMap.Name, objectKey(source.ConfigMap)
+	case source.Secret != nil:
+		provenance.Type, provenance.Name, provenance.Key = "secret", source.Secret.Name, objectKey(source.Secret)
+	case source.TLSSecret != nil:
+		provenance.Type, provenance.Name = "tlsSecret", source.TLSSecret.Name
+	case source.IstioCACertsSecret != nil:
+		provenance.Type, provenance.Name = "istioCACertsSecret", source.IstioCACertsSecret.Name
+	case source.TruststoreSecret != nil:
+		provenance.Type, provenance.Name, provenance.Key = "truststoreSecret", source.TruststoreSecret.Name, objectKey(&source.TruststoreSecret.SourceObjectKeySelector)
+	case source.ObjectStorage != nil:
+		provenance.Type, provenance.Name, provenance.Key = "objectStorage", source.ObjectStorage.Bucket, source.ObjectStorage.Key
+	case source.RemoteCluster != nil:
+		provenance.Type, provenance.Namespace = "remoteCluster", source.RemoteCluster.Namespace
+		if ref := source.RemoteCluster.ConfigMap; ref != nil {
+			provenance.Name, provenance.Key = ref.Name, objectKey(ref)
+		} else if ref := source.RemoteCluster.Secret; ref != nil {
+			provenance.Name, provenance.Key = ref.Name, objectKey(ref)
+		}
+	case source.InLine != nil:
+		provenance.Type = "inLine"
+	case len(source.InLineDER) > 0:
+		provenance.Type = "inLineDER"
+	case source.UseClusterAPIServerCA != nil && *source.UseClusterAPIServerCA:
+		provenance.Type = "useClusterAPIServerCA"
+	case source.UseNodeOSCAs != nil && *source.UseNodeOSCAs:
+		provenance.Type = "useNodeOSCAs"
+	case source.UseOpenShiftTrustedCA != nil && *source.UseOpenShiftTrustedCA:
+		provenance.Type = "useOpenShiftTrustedCA"
+	case source.UseDefaultCAs != nil && *source.UseDefaultCAs:
+		provenance.Type = "useDefaultCAs"
+	case source.DefaultCAs != nil:
+		provenance.Type = "defaultCAs"
+	}
+
+	if pkg != nil {
+		provenance.Package = pkg.StringID()
+	}
+
+	return provenance
+}
+
+// addCertificateProvenance records the given source against each certificate
+// in the given PEM bundle, keyed by certificate fingerprint.
+func addCertificateProvenance(certificateSources map[string][]sourceProvenance, data []byte, source sourceProvenance) error {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return err
+	}
+
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		fingerprint := certificateFingerprint(block.Bytes)
+
+		sources := certificateSources[fingerprint]
+		if len(sources) > 0 && sources[len(sources)-1].Index == source.Index {
+			continue
+		}
+		certificateSources[fingerprint] = append(sources, source)
+	}
+
+	return nil
+}
+
+// encodeProvenance returns the JSON provenance document of each certificate
+// in the given PEM bundle, in bundle order. Certificates appearing more than
+// once in the bundle are only described once.
+func encodeProvenance(data string, certificateSources map[string][]sourceProvenance) (string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	provenance := bundleProvenance{Certificates: []certificateProvenance{}}
+	seen := make(map[string]struct{}, len(certificates))
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		fingerprint := certificateFingerprint(block.Bytes)
+		if _, ok := seen[fingerprint]; ok {
+			continue
+		}
+		seen[fingerprint] = struct{}{}
+
+		sources := certificateSources[fingerprint]
+		if sources == nil {
+			sources = []sourceProvenance{}
+		}
+
+		provenance.Certificates = append(provenance.Certificates, certificateProvenance{
+			Fingerprint: fingerprint,
+			Subject:     cert.Subject.String(),
+			Sources:     sources,
+		})
+	}
+
+	encoded, err := json.Marshal(provenance)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode provenance: %w", err)
+	}
+
+	return string(encoded) + "\n", nil
+}
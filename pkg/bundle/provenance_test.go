@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_describeSource(t *testing.T) {
+	pkg := &fspkg.Package{Name: "cas", Version: "1", Bundle: dummy.TestCertificate5}
+
+	tests := map[string]struct {
+		source trustapi.BundleSource
+		pkg    *fspkg.Package
+
+		expProvenance sourceProvenance
+	}{
+		"ConfigMap source should record its name and key": {
+			source:        trustapi.BundleSource{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "team-a", Key: "ca.crt"}},
+			expProvenance: sourceProvenance{Index: 2, Type: "configMap", Name: "team-a", Key: "ca.crt"},
+		},
+		"Secret source should record its key pattern": {
+			source:        trustapi.BundleSource{Secret: &trustapi.SourceObjectKeySelector{Name: "team-b", KeyPattern: "*.crt"}},
+			expProvenance: sourceProvenance{Index: 2, Type: "secret", Name: "team-b", Key: "*.crt"},
+		},
+		"remote cluster source should record its Namespace": {
+			source: trustapi.BundleSource{RemoteCluster: &trustapi.SourceRemoteCluster{
+				Namespace: "remote",
+				Secret:    &trustapi.SourceObjectKeySelector{Name: "team-c", Key: "ca.crt"},
+			}},
+			expProvenance: sourceProvenance{Index: 2, Type: "remoteCluster", Namespace: "remote", Name: "team-c", Key: "ca.crt"},
+		},
+		"inLine source should record its type": {
+			source:        trustapi.BundleSource{InLine: pointer.String(dummy.TestCertificate1)},
+			expProvenance: sourceProvenance{Index: 2, Type: "inLine"},
+		},
+		"default CAs source should record the package used": {
+			source:        trustapi.BundleSource{UseDefaultCAs: pointer.Bool(true)},
+			pkg:           pkg,
+			expProvenance: sourceProvenance{Index: 2, Type: "useDefaultCAs", Package: pkg.StringID()},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expProvenance, describeSource(2, test.source, test.pkg))
+		})
+	}
+}
+
+func Test_encodeProvenance(t *testing.T) {
+	fingerprint := func(t *testing.T, certificate string) string {
+		block, _ := pem.Decode([]byte(certificate))
+		if block == nil {
+			t.Fatal("failed to decode PEM certificate")
+		}
+		return certificateFingerprint(block.Bytes)
+	}
+
+	teamA := sourceProvenance{Index: 0, Type: "configMap", Name: "team-a", Key: "ca.crt"}
+	teamB := sourceProvenance{Index: 1, Type: "secret", Name: "team-b", Key: "ca.crt"}
+
+	tests := map[string]struct {
+		sources    []sourceProvenance
+		sourceData []string
+		data       string
+
+		expCertificates []certificateProvenance
+	}{
+		"certificates should record the source they came from": {
+			sources:    []sourceProvenance{teamA, teamB},
+			sourceData: []string{dummy.TestCertificate1, dummy.TestCertificate3},
+			data:       dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3),
+			expCertificates: []certificateProvenance{
+				{Fingerprint: fingerprint(t, dummy.TestCertificate1), Subject: "CN=cmct-test-root,O=cert-manager", Sources: []sourceProvenance{teamA}},
+				{Fingerprint: fingerprint(t, dummy.TestCertificate3), Subject: "CN=ISRG Root X1,O=Internet Security Research Group,C=US", Sources: []sourceProvenance{teamB}},
+			},
+		},
+		"certificates provided by several sources should record all of them": {
+			sources:    []sourceProvenance{teamA, teamB},
+			sourceData: []string{dummy.TestCertificate1, dummy.TestCertificate1},
+			data:       dummy.JoinCerts(dummy.TestCertificate1),
+			expCertificates: []certificateProvenance{
+				{Fingerprint: fingerprint(t, dummy.TestCertificate1), Subject: "CN=cmct-test-root,O=cert-manager", Sources: []sourceProvenance{teamA, teamB}},
+			},
+		},
+		"certificates repeated within a source should record it once": {
+			sources:    []sourceProvenance{teamA},
+			sourceData: []string{dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate1)},
+			data:       dummy.JoinCerts(dummy.TestCertificate1),
+			expCertificates: []certificateProvenance{
+				{Fingerprint: fingerprint(t, dummy.TestCertificate1), Subject: "CN=cmct-test-root,O=cert-manager", Sources: []sourceProvenance{teamA}},
+			},
+		},
+		"certificates without recorded sources should have no sources": {
+			data: dummy.JoinCerts(dummy.TestCertificate1),
+			expCertificates: []certificateProvenance{
+				{Fingerprint: fingerprint(t, dummy.TestCertificate1), Subject: "CN=cmct-test-root,O=cert-manager", Sources: []sourceProvenance{}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			certificateSources := make(map[string][]sourceProvenance)
+			for i, source := range test.sources {
+				assert.NoError(t, addCertificateProvenance(certificateSources, []byte(test.sourceData[i]), source))
+			}
+
+			encoded, err := encodeProvenance(test.data, certificateSources)
+			assert.NoError(t, err)
+
+			var provenance bundleProvenance
+			if err := json.Unmarshal([]byte(encoded), &provenance); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expCertificates, provenance.Certificates)
+		})
+	}
+}
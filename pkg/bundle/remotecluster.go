@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// remoteClientFunc returns a client for the cluster described by the given
+// kubeconfig.
+type remoteClientFunc func(kubeconfig []byte) (client.Reader, error)
+
+// remoteClusterClient is a client for a remote cluster, along with the
+// kubeconfig it was built from.
+type remoteClusterClient struct {
+	kubeconfig []byte
+	reader     client.Reader
+}
+
+// remoteClusterClients caches clients for remote clusters by the kubeconfig
+// Secret they were built from, so that clients are only rebuilt when the
+// kubeconfig changes.
+type remoteClusterClients struct {
+	lock    sync.Mutex
+	clients map[client.ObjectKey]remoteClusterClient
+}
+
+// remoteClusterBundle returns the data in the source ConfigMap or Secret in
+// the remote cluster.
+func (b *bundle) remoteClusterBundle(ctx context.Context, ref *trustapi.SourceRemoteCluster) (string, error) {
+	reader, err := b.remoteClusterReader(ctx, &ref.KubeconfigSecret)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case ref.ConfigMap != nil:
+		return readConfigMapBundle(ctx, reader, ref.Namespace, ref.ConfigMap)
+	case ref.Secret != nil:
+		return readSecretBundle(ctx, reader, ref.Namespace, ref.Secret)
+	default:
+		return "", fmt.Errorf("remote cluster source must define a configMap or secret")
+	}
+}
+
+// remoteClusterReader returns a client for the remote cluster described by
+// the kubeconfig in the referenced Secret in the trust Namespace.
+func (b *bundle) remoteClusterReader(ctx context.Context, ref *trustapi.SourceObjectKeySelector) (client.Reader, error) {
+	key := client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}
+
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, key, &secret)
+	if apierrors.IsNotFound(err) {
+		return nil, notFoundError{err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	kubeconfig, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+	}
+
+	b.remoteClients.lock.Lock()
+	defer b.remoteClients.lock.Unlock()
+
+	if cached, ok := b.remoteClients.clients[key]; ok && bytes.Equal(cached.kubeconfig, kubeconfig) {
+		return cached.reader, nil
+	}
+
+	newRemoteClient := b.newRemoteClient
+	if newRemoteClient == nil {
+		newRemoteClient = newKubeconfigClient
+	}
+
+	reader, err := newRemoteClient(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build client from kubeconfig in Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	if b.remoteClients.clients == nil {
+		b.remoteClients.clients = make(map[client.ObjectKey]remoteClusterClient)
+	}
+	b.remoteClients.clients[key] = remoteClusterClient{kubeconfig: kubeconfig, reader: reader}
+
+	return reader, nil
+}
+
+// newKubeconfigClient returns a client for the cluster described by the
+// current context of the given kubeconfig.
+func newKubeconfigClient(kubeconfig []byte) (client.Reader, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.New(restConfig, client.Options{Scheme: trustapi.GlobalScheme})
+}
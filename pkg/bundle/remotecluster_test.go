@@ -0,0 +1,207 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_remoteClusterBundle(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	kubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spoke", Namespace: trustNamespace},
+		Data:       map[string][]byte{"kubeconfig": []byte("spoke-kubeconfig")},
+	}
+	kubeconfigRef := trustapi.SourceObjectKeySelector{Name: "spoke", Key: "kubeconfig"}
+
+	remoteObjects := []runtime.Object{
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "cert-manager"},
+			Data:       map[string]string{"ca.crt": dummy.TestCertificate1},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "cert-manager"},
+			Data:       map[string][]byte{"ca.crt": []byte(dummy.TestCertificate2)},
+		},
+	}
+
+	tests := map[string]struct {
+		ref     trustapi.SourceRemoteCluster
+		objects []runtime.Object
+
+		expData          string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"remote ConfigMap should be read from the remote cluster": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: kubeconfigRef,
+				Namespace:        "cert-manager",
+				ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			objects: []runtime.Object{kubeconfigSecret},
+			expData: dummy.TestCertificate1,
+		},
+		"remote Secret should be read from the remote cluster": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: kubeconfigRef,
+				Namespace:        "cert-manager",
+				Secret:           &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			objects: []runtime.Object{kubeconfigSecret},
+			expData: dummy.TestCertificate2,
+		},
+		"remote ConfigMap which doesn't exist should return not found error": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: kubeconfigRef,
+				Namespace:        "other",
+				ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			objects:          []runtime.Object{kubeconfigSecret},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"kubeconfig Secret which doesn't exist should return not found error": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: kubeconfigRef,
+				Namespace:        "cert-manager",
+				ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"kubeconfig Secret key which doesn't exist should return not found error": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: trustapi.SourceObjectKeySelector{Name: "spoke", Key: "other"},
+				Namespace:        "cert-manager",
+				ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			objects:          []runtime.Object{kubeconfigSecret},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"invalid kubeconfig should return error": {
+			ref: trustapi.SourceRemoteCluster{
+				KubeconfigSecret: kubeconfigRef,
+				Namespace:        "cert-manager",
+				ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			objects: []runtime.Object{&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "spoke", Namespace: trustNamespace},
+				Data:       map[string][]byte{"kubeconfig": []byte("invalid")},
+			}},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects(test.objects...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			remoteClient := newFakeRemoteClient(remoteObjects...)
+
+			b := &bundle{
+				sourceLister:    fakeclient,
+				newRemoteClient: remoteClient.new,
+				Options:         Options{Namespace: trustNamespace},
+			}
+
+			data, err := b.remoteClusterBundle(context.TODO(), &test.ref)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expNotFoundError, errors.As(err, &notFoundError{}), "unexpected notFoundError: %v", err)
+			assert.Equal(t, test.expData, data)
+		})
+	}
+}
+
+func Test_remoteClusterReader_cache(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "spoke", Namespace: trustNamespace},
+		Data:       map[string][]byte{"kubeconfig": []byte("spoke-kubeconfig")},
+	}
+	ref := &trustapi.SourceObjectKeySelector{Name: "spoke", Key: "kubeconfig"}
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithRuntimeObjects(secret).
+		WithScheme(trustapi.GlobalScheme).
+		Build()
+
+	remoteClient := newFakeRemoteClient()
+	b := &bundle{
+		sourceLister:    fakeclient,
+		newRemoteClient: remoteClient.new,
+		Options:         Options{Namespace: trustNamespace},
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.remoteClusterReader(context.TODO(), ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+	assert.Equal(t, 1, remoteClient.built, "expected client to be built once for an unchanged kubeconfig")
+
+	secret.Data["kubeconfig"] = []byte("rotated-kubeconfig")
+	if err := fakeclient.Update(context.TODO(), secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := b.remoteClusterReader(context.TODO(), ref); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, remoteClient.built, "expected client to be rebuilt for a changed kubeconfig")
+}
+
+// fakeRemoteClient builds fake clients for remote clusters, failing for the
+// kubeconfig "invalid".
+type fakeRemoteClient struct {
+	objects []runtime.Object
+	built   int
+}
+
+func newFakeRemoteClient(objects ...runtime.Object) *fakeRemoteClient {
+	return &fakeRemoteClient{objects: objects}
+}
+
+func (f *fakeRemoteClient) new(kubeconfig []byte) (client.Reader, error) {
+	if string(kubeconfig) == "invalid" {
+		return nil, errors.New("invalid kubeconfig")
+	}
+
+	f.built++
+	return fakeclient.NewClientBuilder().
+		WithRuntimeObjects(f.objects...).
+		WithScheme(trustapi.GlobalScheme).
+		Build(), nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// retainedTargetKeys returns the previous keys of the Bundle's target which
+// the bundle data is still written to at the given time, and those whose
+// retention has expired, so that the data is removed from them.
+func retainedTargetKeys(bundle *trustapi.Bundle, now time.Time) ([]string, []string) {
+	var retained, expired []string
+	for _, retainedKey := range bundle.Status.RetainedKeys {
+		if bundle.Spec.Target.ConfigMap != nil && retainedKey.Key == bundle.Spec.Target.ConfigMap.Key {
+			continue
+		}
+		if bundle.Spec.Target.ConfigMap != nil && now.Before(retainedKey.Until.Time) {
+			retained = append(retained, retainedKey.Key)
+		} else {
+			expired = append(expired, retainedKey.Key)
+		}
+	}
+
+	return retained, expired
+}
+
+// retainPreviousKey records the previous key of the Bundle's target as
+// retained until the Bundle's previous key retention has passed, if the key
+// was changed and the Bundle retains previous keys. Returns true if the
+// previous key is retained, in which case the bundle data must not be removed
+// from it.
+func retainPreviousKey(bundle *trustapi.Bundle, previous trustapi.BundleTarget, now time.Time) bool {
+	current := bundle.Spec.Target.ConfigMap
+	if current == nil || current.PreviousKeyRetention == nil || previous.ConfigMap == nil {
+		return false
+	}
+
+	previousKey := previous.ConfigMap.Key
+	if previousKey == current.Key || previous.ConfigMap.Format == trustapi.TargetFormatDER {
+		return false
+	}
+
+	// A key which is retained again restarts its retention, and the current
+	// key is no longer retained.
+	var retainedKeys []trustapi.RetainedTargetKey
+	for _, retainedKey := range bundle.Status.RetainedKeys {
+		if retainedKey.Key != previousKey && retainedKey.Key != current.Key {
+			retainedKeys = append(retainedKeys, retainedKey)
+		}
+	}
+
+	bundle.Status.RetainedKeys = append(retainedKeys, trustapi.RetainedTargetKey{
+		Key:   previousKey,
+		Until: metav1.NewTime(now.Add(current.PreviousKeyRetention.Duration).Truncate(time.Second)),
+	})
+
+	return true
+}
+
+// pruneRetainedKeys removes the previous keys which are no longer retained at
+// the given time from the Bundle's status. Returns true if any were removed.
+func pruneRetainedKeys(bundle *trustapi.Bundle, now time.Time) bool {
+	retained, _ := retainedTargetKeys(bundle, now)
+	if len(retained) == len(bundle.Status.RetainedKeys) {
+		return false
+	}
+
+	var retainedKeys []trustapi.RetainedTargetKey
+	for _, retainedKey := range bundle.Status.RetainedKeys {
+		if bundle.Spec.Target.ConfigMap != nil && retainedKey.Key != bundle.Spec.Target.ConfigMap.Key && now.Before(retainedKey.Until.Time) {
+			retainedKeys = append(retainedKeys, retainedKey)
+		}
+	}
+	bundle.Status.RetainedKeys = retainedKeys
+
+	return true
+}
+
+// nextRetainedKeyExpiry returns the time after the given time at which the
+// retention of the next previous key of the Bundle's target expires, or the
+// zero time if no previous keys are retained.
+func nextRetainedKeyExpiry(bundle *trustapi.Bundle, now time.Time) time.Time {
+	var next time.Time
+	for _, retainedKey := range bundle.Status.RetainedKeys {
+		if !now.Before(retainedKey.Until.Time) {
+			continue
+		}
+		if next.IsZero() || retainedKey.Until.Time.Before(next) {
+			next = retainedKey.Until.Time
+		}
+	}
+
+	return next
+}
+
+// deprecatedCondition returns the Deprecated condition of the Bundle, which is
+// true while the bundle data is still written to previous keys of the target.
+func deprecatedCondition(bundle *trustapi.Bundle) trustapi.BundleCondition {
+	if len(bundle.Status.RetainedKeys) == 0 {
+		return trustapi.BundleCondition{
+			Type:    trustapi.BundleConditionDeprecated,
+			Status:  corev1.ConditionFalse,
+			Reason:  "NoPreviousKeys",
+			Message: "Bundle data is only written to the current target key",
+		}
+	}
+
+	retained := make([]string, 0, len(bundle.Status.RetainedKeys))
+	for _, retainedKey := range bundle.Status.RetainedKeys {
+		retained = append(retained, fmt.Sprintf("%q until %s", retainedKey.Key, retainedKey.Until.UTC().Format(time.RFC3339)))
+	}
+	sort.Strings(retained)
+
+	return trustapi.BundleCondition{
+		Type:    trustapi.BundleConditionDeprecated,
+		Status:  corev1.ConditionTrue,
+		Reason:  "PreviousKeyRetained",
+		Message: fmt.Sprintf("Bundle data is still written to the previous target keys %s; consumers should migrate to the key %q", strings.Join(retained, ", "), bundle.Spec.Target.ConfigMap.Key),
+	}
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	fakeclock "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_retainPreviousKey(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	until := metav1.NewTime(now.Add(time.Hour))
+
+	tests := map[string]struct {
+		retention    *metav1.Duration
+		previous     trustapi.TargetKeySelector
+		retainedKeys []trustapi.RetainedTargetKey
+
+		expRetained     bool
+		expRetainedKeys []trustapi.RetainedTargetKey
+	}{
+		"no retention should not retain the previous key": {
+			previous: trustapi.TargetKeySelector{Key: "old.pem"},
+		},
+		"unchanged key should not be retained": {
+			retention: &metav1.Duration{Duration: time.Hour},
+			previous:  trustapi.TargetKeySelector{Key: "new.pem"},
+		},
+		"previous key in the DER format should not be retained": {
+			retention: &metav1.Duration{Duration: time.Hour},
+			previous:  trustapi.TargetKeySelector{Key: "old.der", Format: trustapi.TargetFormatDER},
+		},
+		"changed key should be retained": {
+			retention:       &metav1.Duration{Duration: time.Hour},
+			previous:        trustapi.TargetKeySelector{Key: "old.pem"},
+			expRetained:     true,
+			expRetainedKeys: []trustapi.RetainedTargetKey{{Key: "old.pem", Until: until}},
+		},
+		"key changed back should no longer be retained, and the previous key restarts its retention": {
+			retention: &metav1.Duration{Duration: time.Hour},
+			previous:  trustapi.TargetKeySelector{Key: "old.pem"},
+			retainedKeys: []trustapi.RetainedTargetKey{
+				{Key: "new.pem", Until: metav1.NewTime(now.Add(time.Minute))},
+				{Key: "old.pem", Until: metav1.NewTime(now.Add(time.Minute))},
+				{Key: "older.pem", Until: metav1.NewTime(now.Add(time.Minute))},
+			},
+			expRetained: true,
+			expRetainedKeys: []trustapi.RetainedTargetKey{
+				{Key: "older.pem", Until: metav1.NewTime(now.Add(time.Minute))},
+				{Key: "old.pem", Until: until},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			bundle := &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+					ConfigMap: &trustapi.TargetKeySelector{Key: "new.pem", PreviousKeyRetention: test.retention},
+				}},
+				Status: trustapi.BundleStatus{RetainedKeys: test.retainedKeys},
+			}
+
+			previous := test.previous
+			retained := retainPreviousKey(bundle, trustapi.BundleTarget{ConfigMap: &previous}, now)
+			assert.Equal(t, test.expRetained, retained)
+			assert.Equal(t, test.expRetainedKeys, bundle.Status.RetainedKeys)
+		})
+	}
+}
+
+func Test_pruneRetainedKeys(t *testing.T) {
+	now := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	active := trustapi.RetainedTargetKey{Key: "old.pem", Until: metav1.NewTime(now.Add(time.Hour))}
+	expired := trustapi.RetainedTargetKey{Key: "older.pem", Until: metav1.NewTime(now)}
+
+	bundle := &trustapi.Bundle{
+		Spec:   trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "new.pem"}}},
+		Status: trustapi.BundleStatus{RetainedKeys: []trustapi.RetainedTargetKey{active, expired}},
+	}
+
+	retained, expiredKeys := retainedTargetKeys(bundle, now)
+	assert.Equal(t, []string{"old.pem"}, retained)
+	assert.Equal(t, []string{"older.pem"}, expiredKeys)
+	assert.Equal(t, now.Add(time.Hour), nextRetainedKeyExpiry(bundle, now))
+
+	assert.True(t, pruneRetainedKeys(bundle, now))
+	assert.Equal(t, []trustapi.RetainedTargetKey{active}, bundle.Status.RetainedKeys)
+	assert.False(t, pruneRetainedKeys(bundle, now))
+
+	condition := deprecatedCondition(bundle)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, "PreviousKeyRetained", condition.Reason)
+	assert.Equal(t, `Bundle data is still written to the previous target keys "old.pem" until 2023-01-01T01:00:00Z; consumers should migrate to the key "new.pem"`, condition.Message)
+
+	assert.True(t, pruneRetainedKeys(bundle, now.Add(time.Hour)))
+	assert.Empty(t, bundle.Status.RetainedKeys)
+	assert.True(t, nextRetainedKeyExpiry(bundle, now).IsZero())
+	assert.Equal(t, corev1.ConditionFalse, deprecatedCondition(bundle).Status)
+}
+
+func Test_syncTarget_retainedKeys(t *testing.T) {
+	const (
+		bundleName = "test-bundle"
+		key        = "new.pem"
+		data       = dummy.TestCertificate1
+	)
+
+	now := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	retainedKeys := []trustapi.RetainedTargetKey{
+		{Key: "old.pem", Until: metav1.NewTime(now.Add(time.Hour))},
+		{Key: "older.pem", Until: metav1.NewTime(now)},
+	}
+
+	targetConfigMap := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundleName,
+				Namespace: "test-namespace",
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: bundleName}}, trustapi.SchemeGroupVersion.WithKind("Bundle")),
+				},
+			},
+			Data: data,
+		}
+	}
+
+	tests := map[string]struct {
+		object runtime.Object
+
+		expNeedsUpdate bool
+	}{
+		"missing target should be created with the retained key": {
+			expNeedsUpdate: true,
+		},
+		"retained key should be written and expired key removed": {
+			object:         targetConfigMap(map[string]string{key: data, "old.pem": dummy.TestCertificate2, "older.pem": data}),
+			expNeedsUpdate: true,
+		},
+		"up to date target should not be updated": {
+			object: targetConfigMap(map[string]string{key: data, "old.pem": data}),
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			clientBuilder := fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme)
+			if test.object != nil {
+				clientBuilder.WithRuntimeObjects(test.object)
+			}
+			fakeclient := clientBuilder.Build()
+
+			b := &bundle{targetDirectClient: fakeclient, recorder: record.NewFakeRecorder(1), clock: fakeclock.NewFakeClock(now)}
+
+			namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-namespace"}}
+			needsUpdate, _, err := b.syncTarget(context.TODO(), klogr.New(), &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+				Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+					ConfigMap: &trustapi.TargetKeySelector{Key: key, PreviousKeyRetention: &metav1.Duration{Duration: time.Hour}},
+				}},
+				Status: trustapi.BundleStatus{RetainedKeys: retainedKeys},
+			}, labels.Everything(), &namespace, data, "", "", "", "", nil, nil, nil, []byte(DefaultJKSPassword), "")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expNeedsUpdate, needsUpdate)
+
+			var configMap corev1.ConfigMap
+			assert.NoError(t, fakeclient.Get(context.TODO(), client.ObjectKey{Namespace: namespace.Name, Name: bundleName}, &configMap))
+			assert.Equal(t, map[string]string{key: data, "old.pem": data}, configMap.Data)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// rolloutContinuationDelay is the delay after which a Bundle whose rollout was
+// interrupted by the target write budget is reconciled again. Requeueing after
+// a delay, rather than immediately, places the Bundle behind other Bundles
+// waiting to be reconciled.
+const rolloutContinuationDelay = time.Second
+
+// rollout is the continuation state of a rollout of a Bundle's targets which
+// was interrupted by the target write budget.
+type rollout struct {
+	// hash is the hash of the content being rolled out. A rollout is restarted
+	// from the first Namespace if the content changes.
+	hash string
+
+	// next is the name of the first Namespace which has not yet been synced
+	// in the rollout.
+	next string
+}
+
+// rollouts holds the continuation state of interrupted rollouts in memory,
+// keyed by Bundle name.
+type rollouts struct {
+	lock     sync.Mutex
+	rollouts map[string]rollout
+}
+
+// get returns the interrupted rollout of the given Bundle, if it was rolling
+// out content with the given hash.
+func (r *rollouts) get(bundle, hash string) (rollout, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	state, ok := r.rollouts[bundle]
+	if !ok || state.hash != hash {
+		return rollout{}, false
+	}
+	return state, true
+}
+
+func (r *rollouts) set(bundle string, state rollout) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.rollouts == nil {
+		r.rollouts = make(map[string]rollout)
+	}
+	r.rollouts[bundle] = state
+}
+
+func (r *rollouts) delete(bundle string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.rollouts, bundle)
+}
+
+// namespacesByName returns a copy of the given Namespaces sorted by name, so
+// that an interrupted rollout can be resumed from a given Namespace.
+func namespacesByName(namespaces []corev1.Namespace) []corev1.Namespace {
+	sorted := make([]corev1.Namespace, len(namespaces))
+	copy(sorted, namespaces)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2/klogr"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_Reconcile_targetWriteBudget(t *testing.T) {
+	const bundleName = "test-bundle"
+
+	objects := []runtime.Object{
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+			Spec: trustapi.BundleSpec{
+				Sources: []trustapi.BundleSource{{InLine: pointer.String(dummy.TestCertificate1)}},
+				Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			},
+			Status: trustapi.BundleStatus{
+				Target: &trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			},
+		},
+	}
+	for _, name := range []string{"ns-e", "ns-d", "ns-c", "ns-b", "ns-a"} {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithScheme(trustapi.GlobalScheme).
+		WithRuntimeObjects(objects...).
+		Build()
+
+	b := &bundle{
+		targetDirectClient: fakeclient,
+		sourceLister:       fakeclient,
+		recorder:           record.NewFakeRecorder(10),
+		clock:              fakeclock.NewFakeClock(time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)),
+		Options: Options{
+			Log:               klogr.New(),
+			Namespace:         "ns-a",
+			TargetWriteBudget: 2,
+		},
+	}
+
+	reconcile := func() ctrl.Result {
+		result, err := b.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: bundleName}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	targets := func() []string {
+		var configMaps corev1.ConfigMapList
+		if err := fakeclient.List(context.TODO(), &configMaps); err != nil {
+			t.Fatal(err)
+		}
+
+		var namespaces []string
+		for _, configMap := range configMaps.Items {
+			namespaces = append(namespaces, configMap.Namespace)
+		}
+		return namespaces
+	}
+
+	getBundle := func() trustapi.Bundle {
+		var bundle trustapi.Bundle
+		if err := fakeclient.Get(context.TODO(), types.NamespacedName{Name: bundleName}, &bundle); err != nil {
+			t.Fatal(err)
+		}
+		return bundle
+	}
+
+	bundleResourceVersion := func() string {
+		bundle := getBundle()
+		return bundle.ResourceVersion
+	}
+
+	syncedCondition := func() trustapi.BundleCondition {
+		bundle := getBundle()
+		for _, condition := range bundle.Status.Conditions {
+			if condition.Type == trustapi.BundleConditionSynced {
+				return condition
+			}
+		}
+		return trustapi.BundleCondition{}
+	}
+
+	// Each reconcile writes at most two targets, in Namespace name order, and
+	// continues the rollout in a later reconcile.
+	assert.Equal(t, ctrl.Result{RequeueAfter: rolloutContinuationDelay}, reconcile())
+	assert.ElementsMatch(t, []string{"ns-a", "ns-b"}, targets())
+	assert.Equal(t, "RolloutInProgress", syncedCondition().Reason)
+	assert.Equal(t, "Target write budget of 2 reached, rollout continues in a later reconcile", syncedCondition().Message)
+
+	// The status is not updated again while the rollout continues, as the
+	// update would requeue the Bundle before the continuation delay.
+	resourceVersion := bundleResourceVersion()
+	assert.Equal(t, ctrl.Result{RequeueAfter: rolloutContinuationDelay}, reconcile())
+	assert.ElementsMatch(t, []string{"ns-a", "ns-b", "ns-c", "ns-d"}, targets())
+	assert.Equal(t, "RolloutInProgress", syncedCondition().Reason)
+	assert.Equal(t, resourceVersion, bundleResourceVersion())
+
+	// Once the rollout is complete, the Namespaces synced before it was resumed
+	// are checked again.
+	assert.Equal(t, ctrl.Result{RequeueAfter: rolloutContinuationDelay}, reconcile())
+	assert.ElementsMatch(t, []string{"ns-a", "ns-b", "ns-c", "ns-d", "ns-e"}, targets())
+	assert.Equal(t, "Synced", syncedCondition().Reason)
+
+	assert.Equal(t, ctrl.Result{}, reconcile())
+	assert.Equal(t, "Synced", syncedCondition().Reason)
+}
+
+func Test_Reconcile_targetWriteBudget_criticalPriorityClass(t *testing.T) {
+	const bundleName = "test-bundle"
+
+	objects := []runtime.Object{
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: bundleName},
+			Spec: trustapi.BundleSpec{
+				Sources:       []trustapi.BundleSource{{InLine: pointer.String(dummy.TestCertificate1)}},
+				Target:        trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+				PriorityClass: trustapi.BundlePriorityClassCritical,
+			},
+		},
+	}
+	for _, name := range []string{"ns-c", "ns-b", "ns-a"} {
+		objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+
+	fakeclient := fakeclient.NewClientBuilder().
+		WithScheme(trustapi.GlobalScheme).
+		WithRuntimeObjects(objects...).
+		Build()
+
+	b := &bundle{
+		targetDirectClient: fakeclient,
+		sourceLister:       fakeclient,
+		recorder:           record.NewFakeRecorder(10),
+		clock:              fakeclock.NewFakeClock(time.Date(2021, 01, 01, 01, 0, 0, 0, time.UTC)),
+		Options: Options{
+			Log:               klogr.New(),
+			Namespace:         "ns-a",
+			TargetWriteBudget: 1,
+		},
+	}
+
+	// Critical Bundles are exempt from the budget, so all targets are written
+	// in a single reconcile.
+	result, err := b.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: bundleName}})
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, result)
+
+	var configMaps corev1.ConfigMapList
+	if err := fakeclient.List(context.TODO(), &configMaps); err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, configMaps.Items, 3)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"fmt"
+	"strings"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// defaultTargetMaxBytes is the maximum size of the bundle data written to a
+// target if the Bundle doesn't set one, which is the maximum size of a
+// ConfigMap.
+const defaultTargetMaxBytes = 1024 * 1024
+
+// targetSizeLimit returns the maximum size in bytes and number of certificates
+// of the bundle data written to the given target, along with the effective
+// policy applied when the data exceeds them. A maximum number of certificates
+// of zero means the number isn't limited.
+func targetSizeLimit(target trustapi.BundleTarget) (int, int, trustapi.TargetSizeLimitPolicy) {
+	maxBytes := defaultTargetMaxBytes
+	var maxCertificates int
+	policy := trustapi.TargetSizeLimitPolicyFail
+
+	if limit := target.SizeLimit; limit != nil {
+		if limit.MaxBytes > 0 {
+			maxBytes = int(limit.MaxBytes)
+		}
+		maxCertificates = int(limit.MaxCertificates)
+		if len(limit.Policy) > 0 {
+			policy = limit.Policy
+		}
+	}
+
+	return maxBytes, maxCertificates, policy
+}
+
+// checkSizeLimit checks the given bundle data against the given limits. If
+// the data exceeds them, a description of the exceeded limit is returned.
+// If compressed is true, the maximum size applies to the gzip-compressed
+// data, which is written to the target instead of the data itself.
+// If truncate is true, the certificates at the end of the data which exceed
+// the limits are omitted, and the number of omitted certificates is returned
+// along with the truncated data.
+func checkSizeLimit(data string, compressed bool, maxBytes, maxCertificates int, truncate bool) (string, int, string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return "", 0, "", err
+	}
+
+	dataSize, description := len(data), "bundle data"
+	if compressed {
+		gzipData, err := encodeGzip(data)
+		if err != nil {
+			return "", 0, "", err
+		}
+		dataSize, description = len(gzipData), "compressed bundle data"
+	}
+
+	var exceeded string
+	switch {
+	case dataSize > maxBytes:
+		exceeded = fmt.Sprintf("%s of %d bytes exceeds the target size limit of %d bytes", description, dataSize, maxBytes)
+	case maxCertificates > 0 && len(certificates) > maxCertificates:
+		exceeded = fmt.Sprintf("bundle data of %d certificates exceeds the target limit of %d certificates", len(certificates), maxCertificates)
+	default:
+		return data, 0, "", nil
+	}
+
+	if !truncate {
+		return data, 0, exceeded, nil
+	}
+
+	var size int
+	var included []string
+	for _, certificate := range certificates {
+		if size+len(certificate) > maxBytes || (maxCertificates > 0 && len(included) == maxCertificates) {
+			break
+		}
+
+		size += len(certificate)
+		included = append(included, string(certificate))
+	}
+
+	return strings.Join(included, ""), len(certificates) - len(included), exceeded, nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_targetSizeLimit(t *testing.T) {
+	tests := map[string]struct {
+		target trustapi.BundleTarget
+
+		expMaxBytes        int
+		expMaxCertificates int
+		expPolicy          trustapi.TargetSizeLimitPolicy
+	}{
+		"target without size limit should fail beyond the ConfigMap size limit": {
+			target:      trustapi.BundleTarget{},
+			expMaxBytes: defaultTargetMaxBytes,
+			expPolicy:   trustapi.TargetSizeLimitPolicyFail,
+		},
+		"empty size limit should use the defaults": {
+			target:      trustapi.BundleTarget{SizeLimit: &trustapi.TargetSizeLimit{}},
+			expMaxBytes: defaultTargetMaxBytes,
+			expPolicy:   trustapi.TargetSizeLimitPolicyFail,
+		},
+		"size limit should override the defaults": {
+			target:             trustapi.BundleTarget{SizeLimit: &trustapi.TargetSizeLimit{MaxBytes: 4096, MaxCertificates: 10, Policy: trustapi.TargetSizeLimitPolicyTruncate}},
+			expMaxBytes:        4096,
+			expMaxCertificates: 10,
+			expPolicy:          trustapi.TargetSizeLimitPolicyTruncate,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			maxBytes, maxCertificates, policy := targetSizeLimit(test.target)
+			assert.Equal(t, test.expMaxBytes, maxBytes)
+			assert.Equal(t, test.expMaxCertificates, maxCertificates)
+			assert.Equal(t, test.expPolicy, policy)
+		})
+	}
+}
+
+func Test_checkSizeLimit(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+	twoCertificates := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+
+	tests := map[string]struct {
+		compressed      bool
+		maxBytes        int
+		maxCertificates int
+		truncate        bool
+
+		expData      string
+		expTruncated int
+		expExceeded  bool
+	}{
+		"data within the limits should be unchanged": {
+			maxBytes:        len(data),
+			maxCertificates: 3,
+			expData:         data,
+		},
+		"data exceeding the size limit should be reported": {
+			maxBytes:    len(data) - 1,
+			expData:     data,
+			expExceeded: true,
+		},
+		"data exceeding the certificate limit should be reported": {
+			maxBytes:        len(data),
+			maxCertificates: 2,
+			expData:         data,
+			expExceeded:     true,
+		},
+		"data exceeding the size limit should be truncated": {
+			maxBytes:     len(data) - 1,
+			truncate:     true,
+			expData:      twoCertificates,
+			expTruncated: 1,
+			expExceeded:  true,
+		},
+		"data exceeding the certificate limit should be truncated": {
+			maxBytes:        len(data),
+			maxCertificates: 1,
+			truncate:        true,
+			expData:         dummy.JoinCerts(dummy.TestCertificate1),
+			expTruncated:    2,
+			expExceeded:     true,
+		},
+		"compressed data within the size limit should be unchanged": {
+			compressed: true,
+			maxBytes:   len(data) - 1,
+			expData:    data,
+		},
+		"compressed data exceeding the size limit should be reported": {
+			compressed:  true,
+			maxBytes:    1,
+			expData:     data,
+			expExceeded: true,
+		},
+		"data should be truncated to nothing if the first certificate exceeds the size limit": {
+			maxBytes:     1,
+			truncate:     true,
+			expData:      "",
+			expTruncated: 3,
+			expExceeded:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			data, truncated, exceeded, err := checkSizeLimit(data, test.compressed, test.maxBytes, test.maxCertificates, test.truncate)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expData, data)
+			assert.Equal(t, test.expTruncated, truncated)
+			assert.Equal(t, test.expExceeded, len(exceeded) > 0, "unexpected exceeded limit: %q", exceeded)
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// spiffeX509SVIDUse is the JWK "use" parameter value identifying an X.509
+// authority in a SPIFFE trust bundle.
+const spiffeX509SVIDUse = "x509-svid"
+
+// spiffeBundle is the JWK set written to the SPIFFE target format, as
+// described by the SPIFFE Trust Domain and Bundle specification.
+type spiffeBundle struct {
+	Keys []spiffeKey `json:"keys"`
+}
+
+// spiffeKey is a JWK describing a single X.509 authority.
+type spiffeKey struct {
+	Use string `json:"use"`
+	Kty string `json:"kty"`
+
+	// Crv, X and Y are set for elliptic curve keys. Only Crv and X are set
+	// for Ed25519 keys.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+
+	// N and E are set for RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// X5c holds the standard base64 encoded DER certificate.
+	X5c []string `json:"x5c"`
+}
+
+// encodeSPIFFEBundle returns the SPIFFE trust bundle containing each
+// certificate in the given PEM bundle, in bundle order. Certificates appearing
+// more than once in the bundle are only included once.
+func encodeSPIFFEBundle(data string) (string, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return "", fmt.Errorf("invalid PEM bundle: %w", err)
+	}
+
+	bundle := spiffeBundle{Keys: []spiffeKey{}}
+	seen := make(map[string]struct{}, len(certificates))
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+
+		fingerprint := certificateFingerprint(block.Bytes)
+		if _, ok := seen[fingerprint]; ok {
+			continue
+		}
+		seen[fingerprint] = struct{}{}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		key := spiffeKey{
+			Use: spiffeX509SVIDUse,
+			X5c: []string{base64.StdEncoding.EncodeToString(block.Bytes)},
+		}
+
+		switch pub := cert.PublicKey.(type) {
+		case *ecdsa.PublicKey:
+			size := (pub.Curve.Params().BitSize + 7) / 8
+			key.Kty = "EC"
+			key.Crv = pub.Curve.Params().Name
+			key.X = base64.RawURLEncoding.EncodeToString(pub.X.FillBytes(make([]byte, size)))
+			key.Y = base64.RawURLEncoding.EncodeToString(pub.Y.FillBytes(make([]byte, size)))
+		case ed25519.PublicKey:
+			key.Kty = "OKP"
+			key.Crv = "Ed25519"
+			key.X = base64.RawURLEncoding.EncodeToString(pub)
+		case *rsa.PublicKey:
+			key.Kty = "RSA"
+			key.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		default:
+			return "", fmt.Errorf("unsupported public key type %T of certificate %q", cert.PublicKey, cert.Subject)
+		}
+
+		bundle.Keys = append(bundle.Keys, key)
+	}
+
+	encoded, err := json.Marshal(bundle)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode SPIFFE trust bundle: %w", err)
+	}
+
+	return string(encoded) + "\n", nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_encodeSPIFFEBundle(t *testing.T) {
+	der := func(t *testing.T, certificate string) string {
+		block, _ := pem.Decode([]byte(certificate))
+		if block == nil {
+			t.Fatal("failed to decode PEM certificate")
+		}
+		return base64.StdEncoding.EncodeToString(block.Bytes)
+	}
+
+	tests := map[string]struct {
+		data string
+
+		expKeyTypes []string
+		expCurves   []string
+		expX5c      []string
+		expError    bool
+	}{
+		"empty bundle should produce an empty key set": {
+			data:        "",
+			expKeyTypes: nil,
+		},
+		"keys of all supported types should be described": {
+			data:        dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3),
+			expKeyTypes: []string{"EC", "OKP", "RSA"},
+			expCurves:   []string{"P-256", "Ed25519", ""},
+			expX5c:      []string{der(t, dummy.TestCertificate1), der(t, dummy.TestCertificate2), der(t, dummy.TestCertificate3)},
+		},
+		"certificate appearing more than once should be described once": {
+			data:        dummy.JoinCerts(dummy.TestCertificate4, dummy.TestCertificate4),
+			expKeyTypes: []string{"EC"},
+			expCurves:   []string{"P-384"},
+			expX5c:      []string{der(t, dummy.TestCertificate4)},
+		},
+		"invalid certificate should error": {
+			data:     string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})),
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := encodeSPIFFEBundle(test.data)
+			if test.expError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			var bundle spiffeBundle
+			if err := json.Unmarshal([]byte(encoded), &bundle); err != nil {
+				t.Fatal(err)
+			}
+
+			var keyTypes, curves, x5c []string
+			for _, key := range bundle.Keys {
+				assert.Equal(t, spiffeX509SVIDUse, key.Use)
+				keyTypes = append(keyTypes, key.Kty)
+				curves = append(curves, key.Crv)
+				x5c = append(x5c, key.X5c...)
+
+				switch key.Kty {
+				case "RSA":
+					assert.NotEmpty(t, key.N)
+					assert.Equal(t, "AQAB", key.E)
+				default:
+					assert.NotEmpty(t, key.X)
+				}
+			}
+
+			assert.Equal(t, test.expKeyTypes, keyTypes)
+			assert.Equal(t, test.expCurves, curves)
+			assert.Equal(t, test.expX5c, x5c)
+		})
+	}
+}
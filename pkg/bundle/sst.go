@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// sstMagic is the magic number of the header of a serialized certificate
+	// store, which is "CERT" in little-endian byte order.
+	sstMagic = 0x54524543
+
+	// sstCertificateElement is the ID of the elements of a serialized
+	// certificate store which hold a DER certificate.
+	sstCertificateElement = 0x20
+
+	// sstX509Encoding is the X509_ASN_ENCODING encoding type of the
+	// certificates of a serialized certificate store.
+	sstX509Encoding = 1
+)
+
+// encodeSST returns the Microsoft serialized certificate store (.sst)
+// containing each certificate in the given PEM bundle, in bundle order. The
+// store is made up of a header, an element per certificate and an empty
+// trailing element, each element being its ID, encoding type and length as
+// little-endian 32-bit integers followed by the DER certificate.
+func encodeSST(data string) []byte {
+	var buf bytes.Buffer
+	writeUint32s(&buf, 0, sstMagic)
+
+	remaining := []byte(data)
+	for {
+		var block *pem.Block
+		block, remaining = pem.Decode(remaining)
+		if block == nil {
+			break
+		}
+
+		writeUint32s(&buf, sstCertificateElement, sstX509Encoding, uint32(len(block.Bytes)))
+		buf.Write(block.Bytes)
+	}
+
+	writeUint32s(&buf, 0, 0, 0)
+
+	return buf.Bytes()
+}
+
+// writeUint32s writes the given integers to buf in little-endian byte order.
+func writeUint32s(buf *bytes.Buffer, values ...uint32) {
+	for _, value := range values {
+		buf.Write(binary.LittleEndian.AppendUint32(nil, value))
+	}
+}
+
+// sstKey returns the key of the target entry the serialized certificate
+// store is written to, and whether the target has the SST format.
+func sstKey(target trustapi.BundleTarget) (string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.SST == nil {
+		return "", false
+	}
+
+	return target.AdditionalFormats.SST.Key, true
+}
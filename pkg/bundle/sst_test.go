@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"crypto/x509"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_encodeSST(t *testing.T) {
+	tests := map[string]struct {
+		data     string
+		expCerts []string
+	}{
+		"empty bundle has only header and trailer": {
+			data: "",
+		},
+		"single certificate": {
+			data:     dummy.TestCertificate1,
+			expCerts: []string{dummy.TestCertificate1},
+		},
+		"certificates are written in bundle order": {
+			data:     dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate1),
+			expCerts: []string{dummy.TestCertificate2, dummy.TestCertificate1},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			sst := encodeSST(test.data)
+
+			if !assert.GreaterOrEqual(t, len(sst), 20) {
+				return
+			}
+			assert.Equal(t, []byte("\x00\x00\x00\x00CERT"), sst[:8])
+			assert.Equal(t, make([]byte, 12), sst[len(sst)-12:])
+
+			var certs []*x509.Certificate
+			for rest := sst[8 : len(sst)-12]; len(rest) > 0; {
+				if !assert.GreaterOrEqual(t, len(rest), 12) {
+					return
+				}
+				assert.Equal(t, uint32(sstCertificateElement), binary.LittleEndian.Uint32(rest[0:4]))
+				assert.Equal(t, uint32(sstX509Encoding), binary.LittleEndian.Uint32(rest[4:8]))
+				length := binary.LittleEndian.Uint32(rest[8:12])
+				if !assert.GreaterOrEqual(t, uint32(len(rest)-12), length) {
+					return
+				}
+
+				cert, err := x509.ParseCertificate(rest[12 : 12+length])
+				assert.NoError(t, err)
+				certs = append(certs, cert)
+				rest = rest[12+length:]
+			}
+
+			var expCerts []*x509.Certificate
+			for _, c := range test.expCerts {
+				cert, err := x509.ParseCertificate(dummy.JoinCertsDER(c))
+				assert.NoError(t, err)
+				expCerts = append(expCerts, cert)
+			}
+			assert.Equal(t, expCerts, certs)
+		})
+	}
+}
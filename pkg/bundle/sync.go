@@ -25,29 +25,70 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"software.sslmate.com/src/go-pkcs12"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/fspkg"
+	"github.com/cert-manager/trust-manager/pkg/nodecas"
 	"github.com/cert-manager/trust-manager/pkg/util"
 )
 
 const (
+	// ClusterAPIServerCAConfigMapName is the name of the ConfigMap Kubernetes
+	// publishes in every Namespace containing the CA of the API server.
+	ClusterAPIServerCAConfigMapName = "kube-root-ca.crt"
+
+	// IstioRootCertKey is the key of the root certificates in an Istio
+	// `cacerts` Secret.
+	IstioRootCertKey = "root-cert.pem"
+
+	// IstioCertChainKey is the key of the certificate chain in an Istio
+	// `cacerts` Secret.
+	IstioCertChainKey = "cert-chain.pem"
+
+	// ClusterAPIServerCAKey is the key of the API server CA in the
+	// ClusterAPIServerCAConfigMapName ConfigMap.
+	ClusterAPIServerCAKey = "ca.crt"
+
+	// OpenShiftTrustedCAConfigMapName is the name of the ConfigMap in the
+	// trust Namespace which OpenShift injects the cluster-wide trusted CA
+	// bundle into.
+	OpenShiftTrustedCAConfigMapName = "trust-manager-openshift-trusted-ca"
+
 	// DefaultJKSPassword is the default password that Java uses; it's a Java convention to use this exact password.
 	// Since we're not storing anything secret in the JKS files we generate, this password is not a meaningful security measure
 	// but seems often to be expected by applications consuming JKS files
 	DefaultJKSPassword = "changeit"
 )
 
+// appliedTargetKeyAnnotation is the annotation set on target ConfigMaps
+// whose bundle data is written to a key overridden by the Namespace's target
+// key annotation, recording that key. It is used to remove the data from the
+// previous key when the override changes.
+const appliedTargetKeyAnnotation = "trust.cert-manager.io/applied-target-key"
+
 type notFoundError struct{ error }
 
+// privateKeyError is returned when the data of a source contains a private
+// key, and the Bundle doesn't strip private keys.
+type privateKeyError struct{ error }
+
 // bundleData holds the result of a call to buildSourceBundle. It contains both the resulting PEM-encoded
 // certificate data from concatenating all of the sources together and any metadata from the sources which
 // needs to be exposed on the Bundle resource's status field.
@@ -55,19 +96,108 @@ type bundleData struct {
 	data string
 
 	defaultCAPackageStringID string
+
+	// namedDefaultCAPackages holds the status of the named default CA packages
+	// used, keyed by package name.
+	namedDefaultCAPackages map[string]trustapi.DefaultCAPackageStatus
+
+	// certificateLabels holds the source labels of each certificate, keyed by
+	// certificate fingerprint.
+	certificateLabels map[string]map[string]string
+
+	// certificatePurposes holds the purposes each certificate is trusted for,
+	// keyed by certificate fingerprint, for the certificates of sources or
+	// default CA packages which set purposes.
+	certificatePurposes map[string]sets.Set[trustapi.TrustPurpose]
+
+	// certificateSources holds the sources each certificate came from, keyed
+	// by certificate fingerprint. Only recorded if the Bundle's target has
+	// the provenance format.
+	certificateSources map[string][]sourceProvenance
+
+	// excludedExpiredCertificates is the number of expired certificates which
+	// were excluded from the bundle by the excludeExpired filter.
+	excludedExpiredCertificates int
+
+	// excludedExpiringCertificates is the number of certificates which had not
+	// expired, but were excluded from the bundle by the excludeExpiringWithin
+	// filter.
+	excludedExpiringCertificates int
+
+	// excludedMatchedCertificates is the number of certificates which were
+	// excluded from the bundle by the include and exclude filters.
+	excludedMatchedCertificates int
+
+	// duplicateCertificates is the number of certificates which were omitted
+	// from the bundle as duplicates of certificates already included.
+	duplicateCertificates int
+
+	// crossSignedCertificates is the number of certificates with the same
+	// subject and subject key identifier as a preferred certificate, which
+	// were omitted from the bundle unless the crossSigned filter is KeepAll.
+	crossSignedCertificates int
+
+	// nonCACertificates is the number of certificates without the CA basic
+	// constraint, which were excluded from the bundle if the nonCACertificates
+	// filter is enforced.
+	nonCACertificates int
+
+	// weakCryptoCertificates is the number of certificates with weak keys or
+	// signatures, which were excluded from the bundle if the effective weak
+	// crypto action is Enforce.
+	weakCryptoCertificates int
+
+	// distrustedCertificates is the number of certificates of default CA
+	// packages which were excluded from the bundle since their distrust-after
+	// time has passed.
+	distrustedCertificates int
+
+	// privateKeySources is the number of sources whose data contained private
+	// keys, which were stripped from the data.
+	privateKeySources int
+
+	// invalidPEMBlocks is the number of PEM blocks in the data of the sources
+	// which weren't valid certificates, and were skipped since the Bundle's
+	// parsing policy is Lenient.
+	invalidPEMBlocks int
+
+	// nextExclusion is the earliest time at which a certificate which remains
+	// in the bundle will be excluded by the expiry filters or its distrust-after
+	// time, or zero if there are none.
+	nextExclusion time.Time
+}
+
+// weightedBundle is the validated PEM data of a single source, along with the
+// source's weight.
+type weightedBundle struct {
+	weight int32
+	data   string
 }
 
 // buildSourceBundle retrieves and concatenates all source bundle data for this Bundle object.
 // Each source data is validated and pruned to ensure that all certificates within are valid, and
 // is each bundle is concatenated together with a new line character.
+// The order of the output is deterministic: sources are ordered by weight, highest first, with
+// sources of equal weight kept in the order they are defined, and the certificates within each
+// source are sorted by the SHA-256 digest of their DER encoding.
 func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle) (bundleData, error) {
 	var resolvedBundle bundleData
-	var bundles []string
+	var bundles []weightedBundle
+
+	_, recordProvenance := provenanceKey(bundle.Spec.Target)
+
+	// Cached objects which are no longer referenced by any Bundle are evicted.
+	b.objectCache.setReferences(bundle.Name, objectStorageURLs(bundle))
+
+	for i, source := range bundle.Spec.Sources {
+		filters := sourceFilters(bundle, source)
 
-	for _, source := range bundle.Spec.Sources {
 		var (
-			sourceData string
-			err        error
+			sourceData      string
+			distrustAfter   map[string]time.Time
+			packagePurposes map[string][]trustapi.TrustPurpose
+			sourcePackage   *fspkg.Package
+			err             error
 		)
 
 		switch {
@@ -77,28 +207,179 @@ func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle)
 		case source.Secret != nil:
 			sourceData, err = b.secretBundle(ctx, source.Secret)
 
+		case source.TLSSecret != nil:
+			sourceData, err = b.tlsSecretBundle(ctx, source.TLSSecret)
+
+		case source.IstioCACertsSecret != nil:
+			sourceData, err = b.istioCACertsSecretBundle(ctx, source.IstioCACertsSecret)
+
+		case source.TruststoreSecret != nil:
+			sourceData, err = b.truststoreSecretBundle(ctx, source.TruststoreSecret)
+
+		case source.ObjectStorage != nil:
+			sourceData, err = b.objectStorageBundle(ctx, source.ObjectStorage)
+
+		case source.RemoteCluster != nil:
+			sourceData, err = b.remoteClusterBundle(ctx, source.RemoteCluster)
+
 		case source.InLine != nil:
 			sourceData = *source.InLine
 
+		case len(source.InLineDER) > 0:
+			sourceData, err = inLineDERBundle(source.InLineDER)
+
+		case source.UseClusterAPIServerCA != nil && *source.UseClusterAPIServerCA:
+			sourceData, err = b.configMapBundle(ctx, &trustapi.SourceObjectKeySelector{
+				Name: ClusterAPIServerCAConfigMapName,
+				Key:  ClusterAPIServerCAKey,
+			})
+
+		case source.UseNodeOSCAs != nil && *source.UseNodeOSCAs:
+			sourceData, err = b.configMapBundle(ctx, &trustapi.SourceObjectKeySelector{
+				Name: nodecas.ConfigMapName,
+				Key:  nodecas.Key,
+			})
+
+		case source.UseOpenShiftTrustedCA != nil && *source.UseOpenShiftTrustedCA:
+			sourceData, err = b.configMapBundle(ctx, &trustapi.SourceObjectKeySelector{
+				Name: OpenShiftTrustedCAConfigMapName,
+				Key:  trustapi.OpenShiftTrustedCAConfigMapKey,
+			})
+
 		case source.UseDefaultCAs != nil && *source.UseDefaultCAs:
 			if b.defaultPackage == nil {
 				err = notFoundError{fmt.Errorf("no default package was specified when trust-manager was started; default CAs not available")}
 			} else {
+				sourcePackage = b.defaultPackage
 				sourceData = b.defaultPackage.Bundle
+				distrustAfter = b.defaultPackage.DistrustAfter
+				packagePurposes = b.defaultPackage.Purposes
 				resolvedBundle.defaultCAPackageStringID = b.defaultPackage.StringID()
 			}
+
+		case source.DefaultCAs != nil:
+			sourcePackage, err = b.defaultCAsBundle(source.DefaultCAs, &resolvedBundle)
+			if err == nil {
+				sourceData = sourcePackage.Bundle
+				distrustAfter = sourcePackage.DistrustAfter
+				packagePurposes = sourcePackage.Purposes
+			}
 		}
 
 		if err != nil {
 			return bundleData{}, fmt.Errorf("failed to retrieve bundle from source: %w", err)
 		}
 
-		sanitizedBundle, err := util.ValidateAndSanitizePEMBundle([]byte(sourceData))
+		// Refuse to sync private keys found in the source, since the Bundle's
+		// targets would distribute them to every matching Namespace.
+		if util.ContainsPrivateKey([]byte(sourceData)) {
+			if !bundle.Spec.StripPrivateKeys {
+				return bundleData{}, privateKeyError{fmt.Errorf("source %d contains a PEM private key", i)}
+			}
+
+			sourceData = string(util.StripPrivateKeys([]byte(sourceData)))
+			resolvedBundle.privateKeySources++
+		}
+
+		var sanitizedBundle []byte
+		if bundle.Spec.ParsingPolicy == trustapi.PEMParsingPolicyLenient {
+			certificates, skipped := util.SplitValidPEMBundle([]byte(sourceData))
+			if len(certificates) == 0 {
+				return bundleData{}, fmt.Errorf("invalid PEM data in source: bundle contains no valid PEM certificates")
+			}
+
+			sanitizedBundle = bytes.TrimSpace(bytes.Join(certificates, nil))
+			resolvedBundle.invalidPEMBlocks += skipped
+		} else {
+			sanitizedBundle, err = util.ValidateAndSanitizePEMBundle([]byte(sourceData))
+			if err != nil {
+				return bundleData{}, fmt.Errorf("invalid PEM data in source: %w", err)
+			}
+		}
+
+		// Sort the certificates of the source, so that their order in the
+		// output doesn't change when a source reorders its certificates.
+		sanitizedBundle, err = util.SortPEMBundle(sanitizedBundle)
 		if err != nil {
-			return bundleData{}, fmt.Errorf("invalid PEM data in source: %w", err)
+			return bundleData{}, fmt.Errorf("failed to sort PEM data in source: %w", err)
+		}
+
+		if source.DefaultCAs != nil && source.DefaultCAs.Exclude != nil {
+			sanitizedBundle, err = excludeDefaultCAs(sanitizedBundle, source.DefaultCAs.Exclude, &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to exclude default CAs in source: %w", err)
+			}
+		}
+
+		if len(distrustAfter) > 0 && len(sanitizedBundle) > 0 {
+			sanitizedBundle, err = b.excludeDistrustedCertificates(sanitizedBundle, distrustAfter, &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to exclude distrusted default CAs in source: %w", err)
+			}
+		}
+
+		if hasMatchFilters(filters) {
+			sanitizedBundle, err = excludeMatchedCertificates(sanitizedBundle, filters, &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to filter certificates in source: %w", err)
+			}
+		}
+
+		if within, ok := expiryFilter(filters, source); ok && len(sanitizedBundle) > 0 {
+			sanitizedBundle, err = b.excludeExpiredCertificates(sanitizedBundle, within, &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to exclude expired certificates in source: %w", err)
+			}
+		}
+
+		if len(sanitizedBundle) > 0 {
+			sanitizedBundle, err = excludeNonCACertificates(sanitizedBundle, enforceCACertificates(filters), &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to check basic constraints of certificates in source: %w", err)
+			}
+		}
+
+		weakCryptoAction, minRSAKeySize, weakSignatureAlgorithms := weakCryptoPolicy(filters, b.DefaultWeakCryptoAction)
+		if weakCryptoAction != trustapi.WeakCryptoActionIgnore && len(sanitizedBundle) > 0 {
+			sanitizedBundle, err = excludeWeakCryptoCertificates(sanitizedBundle, weakCryptoAction == trustapi.WeakCryptoActionEnforce, minRSAKeySize, weakSignatureAlgorithms, &resolvedBundle)
+			if err != nil {
+				return bundleData{}, fmt.Errorf("failed to check keys and signatures of certificates in source: %w", err)
+			}
+		}
+
+		// Skip sources whose certificates have all been excluded.
+		if len(sanitizedBundle) == 0 {
+			continue
+		}
+
+		if len(source.Labels) > 0 {
+			if resolvedBundle.certificateLabels == nil {
+				resolvedBundle.certificateLabels = make(map[string]map[string]string)
+			}
+			if err := addCertificateLabels(resolvedBundle.certificateLabels, sanitizedBundle, source.Labels); err != nil {
+				return bundleData{}, fmt.Errorf("failed to label certificates in source: %w", err)
+			}
+		}
+
+		if len(source.Purposes) > 0 || len(packagePurposes) > 0 {
+			if resolvedBundle.certificatePurposes == nil {
+				resolvedBundle.certificatePurposes = make(map[string]sets.Set[trustapi.TrustPurpose])
+			}
+			if err := addCertificatePurposes(resolvedBundle.certificatePurposes, sanitizedBundle, source.Purposes, packagePurposes); err != nil {
+				return bundleData{}, fmt.Errorf("failed to record purposes of certificates in source: %w", err)
+			}
+		}
+
+		if recordProvenance {
+			if resolvedBundle.certificateSources == nil {
+				resolvedBundle.certificateSources = make(map[string][]sourceProvenance)
+			}
+			if err := addCertificateProvenance(resolvedBundle.certificateSources, sanitizedBundle, describeSource(i, source, sourcePackage)); err != nil {
+				return bundleData{}, fmt.Errorf("failed to record provenance of certificates in source: %w", err)
+			}
 		}
 
-		bundles = append(bundles, string(sanitizedBundle))
+		bundles = append(bundles, weightedBundle{weight: source.Weight, data: string(sanitizedBundle)})
 	}
 
 	// NB: bundles should never be empty here, since ValidateAndSanitizePEMBundle errors when a bundle source
@@ -110,60 +391,479 @@ func (b *bundle) buildSourceBundle(ctx context.Context, bundle *trustapi.Bundle)
 		return bundleData{}, fmt.Errorf("couldn't find any valid certificates in bundle")
 	}
 
-	resolvedBundle.data = strings.Join(bundles, "\n") + "\n"
+	sort.SliceStable(bundles, func(i, j int) bool {
+		return bundles[i].weight > bundles[j].weight
+	})
+
+	// Certificates provided by overlapping sources are only included once,
+	// from the first source in the output which provides them.
+	bundles, err := deduplicateCertificates(bundles, bundle.Spec.Filters != nil && bundle.Spec.Filters.DeduplicateByPublicKey, &resolvedBundle)
+	if err != nil {
+		return bundleData{}, fmt.Errorf("failed to deduplicate certificates: %w", err)
+	}
+
+	var crossSignedPolicy trustapi.CrossSignedPolicy
+	if bundle.Spec.Filters != nil {
+		crossSignedPolicy = bundle.Spec.Filters.CrossSigned
+	}
+	bundles, err = selectCrossSignedCertificates(bundles, crossSignedPolicy, &resolvedBundle)
+	if err != nil {
+		return bundleData{}, fmt.Errorf("failed to select cross-signed certificates: %w", err)
+	}
+
+	data := make([]string, len(bundles))
+	for i, bundle := range bundles {
+		data[i] = bundle.data
+	}
+
+	resolvedBundle.data = strings.Join(data, "\n") + "\n"
 
 	return resolvedBundle, nil
 }
 
+// sourceFilters returns the filters applied to the certificates of the given
+// source of the Bundle, which are the source's own filters if set, and the
+// Bundle's filters otherwise.
+func sourceFilters(bundle *trustapi.Bundle, source trustapi.BundleSource) *trustapi.BundleFilters {
+	if source.Filters != nil {
+		return source.Filters
+	}
+
+	return bundle.Spec.Filters
+}
+
+// expiryFilter returns whether certificates should be excluded from the given
+// source based on their expiry, using the source's effective filters, along
+// with the duration before their expiry at which they are excluded.
+func expiryFilter(filters *trustapi.BundleFilters, source trustapi.BundleSource) (time.Duration, bool) {
+	if source.ExcludeExpired != nil && !*source.ExcludeExpired {
+		return 0, false
+	}
+
+	enabled := source.ExcludeExpired != nil
+
+	var within time.Duration
+	if filters != nil {
+		enabled = enabled || filters.ExcludeExpired
+		if filters.ExcludeExpiringWithin != nil && filters.ExcludeExpiringWithin.Duration > 0 {
+			within = filters.ExcludeExpiringWithin.Duration
+			enabled = true
+		}
+	}
+
+	return within, enabled
+}
+
+// excludeExpiredCertificates returns the given PEM bundle without the
+// certificates which have expired, or which expire within the given duration.
+// The number of excluded certificates and the earliest time at which a
+// remaining certificate will be excluded are recorded in the resolved bundle.
+func (b *bundle) excludeExpiredCertificates(data []byte, within time.Duration, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := b.clock.Now()
+
+	var unexpired [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		if now.After(cert.NotAfter) {
+			resolvedBundle.excludedExpiredCertificates++
+			continue
+		}
+
+		exclusion := cert.NotAfter.Add(-within)
+		if now.After(exclusion) {
+			resolvedBundle.excludedExpiringCertificates++
+			continue
+		}
+
+		if resolvedBundle.nextExclusion.IsZero() || exclusion.Before(resolvedBundle.nextExclusion) {
+			resolvedBundle.nextExclusion = exclusion
+		}
+
+		unexpired = append(unexpired, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(unexpired, nil)), nil
+}
+
+// excludeDistrustedCertificates returns the given PEM bundle of a default CA
+// package without the certificates whose distrust-after time, keyed by
+// fingerprint, has passed. The number of excluded certificates is recorded in
+// the resolved bundle, along with the next time a certificate is distrusted.
+func (b *bundle) excludeDistrustedCertificates(data []byte, distrustAfter map[string]time.Time, resolvedBundle *bundleData) ([]byte, error) {
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	distrusted := make(map[string]time.Time, len(distrustAfter))
+	for fingerprint, t := range distrustAfter {
+		parsed, err := util.ParseFingerprint(fingerprint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid distrust-after fingerprint %q: %w", fingerprint, err)
+		}
+		distrusted[parsed] = t
+	}
+
+	now := b.clock.Now()
+
+	var trusted [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		exclusion, ok := distrusted[certificateFingerprint(block.Bytes)]
+		if ok && now.After(exclusion) {
+			resolvedBundle.distrustedCertificates++
+			continue
+		}
+
+		if ok && (resolvedBundle.nextExclusion.IsZero() || exclusion.Before(resolvedBundle.nextExclusion)) {
+			resolvedBundle.nextExclusion = exclusion
+		}
+
+		trusted = append(trusted, certificate)
+	}
+
+	return bytes.TrimSpace(bytes.Join(trusted, nil)), nil
+}
+
+// defaultCAsBundle returns the default CA package requested by
+// the source. If the requested package was not loaded, the fallback packages
+// are tried in order. The selected package is recorded in the resolved bundle.
+func (b *bundle) defaultCAsBundle(source *trustapi.DefaultCAsSource, resolvedBundle *bundleData) (*fspkg.Package, error) {
+	for i, name := range append([]string{source.Package}, source.Fallback...) {
+		if len(name) == 0 {
+			if b.defaultPackage == nil {
+				continue
+			}
+
+			resolvedBundle.defaultCAPackageStringID = b.defaultPackage.StringID()
+			return b.defaultPackage, nil
+		}
+
+		pkg, ok := b.namedDefaultPackages[name]
+		if !ok {
+			continue
+		}
+
+		if resolvedBundle.namedDefaultCAPackages == nil {
+			resolvedBundle.namedDefaultCAPackages = make(map[string]trustapi.DefaultCAPackageStatus)
+		}
+		resolvedBundle.namedDefaultCAPackages[pkg.Name] = trustapi.DefaultCAPackageStatus{
+			Name:     pkg.Name,
+			Version:  pkg.StringID(),
+			Fallback: i > 0,
+		}
+
+		return pkg, nil
+	}
+
+	switch {
+	case len(source.Fallback) > 0:
+		return nil, notFoundError{fmt.Errorf("neither the requested default package nor any of the fallback packages %q were loaded when trust-manager was started", source.Fallback)}
+	case len(source.Package) == 0:
+		return nil, notFoundError{fmt.Errorf("no default package was specified when trust-manager was started; default CAs not available")}
+	default:
+		return nil, notFoundError{fmt.Errorf("no default package named %q was loaded when trust-manager was started", source.Package)}
+	}
+}
+
+// externalSourceRefresh returns the given result, updated to requeue the
+// Bundle if it has any sources outside of the cluster's trust Namespace, whose
+// changes are not watched. The Bundle is requeued after the shortest refresh
+// interval of its external sources, where sources without a refresh interval
+// use the external source refresh period.
+func (b *bundle) externalSourceRefresh(bundle *trustapi.Bundle, result ctrl.Result) ctrl.Result {
+	for _, source := range bundle.Spec.Sources {
+		var refreshInterval *metav1.Duration
+		switch {
+		case source.ObjectStorage != nil:
+			refreshInterval = source.ObjectStorage.RefreshInterval
+		case source.RemoteCluster != nil:
+			refreshInterval = source.RemoteCluster.RefreshInterval
+		default:
+			continue
+		}
+
+		period := b.ExternalSourceRefreshPeriod
+		if refreshInterval != nil {
+			period = refreshInterval.Duration
+		}
+		if period <= 0 {
+			continue
+		}
+
+		if result.RequeueAfter == 0 || period < result.RequeueAfter {
+			result.RequeueAfter = period
+		}
+	}
+
+	return result
+}
+
 // configMapBundle returns the data in the source ConfigMap within the trust Namespace.
 func (b *bundle) configMapBundle(ctx context.Context, ref *trustapi.SourceObjectKeySelector) (string, error) {
+	return readConfigMapBundle(ctx, b.sourceLister, b.Namespace, ref)
+}
+
+// readConfigMapBundle returns the data in the source ConfigMap within the
+// given Namespace, read using the given reader.
+func readConfigMapBundle(ctx context.Context, reader client.Reader, namespace string, ref *trustapi.SourceObjectKeySelector) (string, error) {
 	var configMap corev1.ConfigMap
-	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &configMap)
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &configMap)
 	if apierrors.IsNotFound(err) {
 		return "", notFoundError{err}
 	}
 
 	if err != nil {
-		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", b.Namespace, ref.Name, err)
+		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, ref.Name, err)
 	}
 
-	data, ok := configMap.Data[ref.Key]
-	if !ok {
-		return "", notFoundError{fmt.Errorf("no data found in ConfigMap %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+	if len(ref.KeyPattern) > 0 {
+		entries := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for key, data := range configMap.Data {
+			entries[key] = []byte(data)
+		}
+		for key, data := range configMap.BinaryData {
+			entries[key] = data
+		}
+		return keyPatternBundle(entries, ref.KeyPattern, ref.ExcludeKeys, fmt.Sprintf("ConfigMap %s/%s", namespace, ref.Name))
+	}
+
+	if data, ok := configMap.Data[ref.Key]; ok {
+		return decodeSourceData([]byte(data)), nil
+	}
+
+	if data, ok := configMap.BinaryData[ref.Key]; ok {
+		return decodeSourceData(data), nil
 	}
 
-	return data, nil
+	return "", notFoundError{fmt.Errorf("no data found in ConfigMap %s/%s at key %q", namespace, ref.Name, ref.Key)}
 }
 
 // secretBundle returns the data in the target Secret within the trust Namespace.
 func (b *bundle) secretBundle(ctx context.Context, ref *trustapi.SourceObjectKeySelector) (string, error) {
+	return readSecretBundle(ctx, b.sourceLister, b.Namespace, ref)
+}
+
+// readSecretBundle returns the data in the source Secret within the given
+// Namespace, read using the given reader.
+func readSecretBundle(ctx context.Context, reader client.Reader, namespace string, ref *trustapi.SourceObjectKeySelector) (string, error) {
 	var secret corev1.Secret
-	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+	err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &secret)
 	if apierrors.IsNotFound(err) {
 		return "", notFoundError{err}
 	}
 	if err != nil {
-		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	if len(ref.KeyPattern) > 0 {
+		return keyPatternBundle(secret.Data, ref.KeyPattern, ref.ExcludeKeys, fmt.Sprintf("Secret %s/%s", namespace, ref.Name))
 	}
 
 	data, ok := secret.Data[ref.Key]
 	if !ok {
-		return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+		return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", namespace, ref.Name, ref.Key)}
+	}
+
+	return decodeSourceData(data), nil
+}
+
+// keyPatternBundle returns the data of the entries of the given source object
+// whose keys match the given glob pattern and are not excluded, joined in
+// alphabetical order of the keys. Returns a not found error if no keys match.
+func keyPatternBundle(entries map[string][]byte, pattern string, excludeKeys []string, object string) (string, error) {
+	excluded := sets.New(excludeKeys...)
+
+	var keys []string
+	for key := range entries {
+		if excluded.Has(key) {
+			continue
+		}
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return "", fmt.Errorf("invalid key pattern %q: %w", pattern, err)
+		}
+		if matched {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", notFoundError{fmt.Errorf("no data found in %s at keys matching %q", object, pattern)}
+	}
+
+	sort.Strings(keys)
+
+	data := make([]string, len(keys))
+	for i, key := range keys {
+		data[i] = decodeSourceData(entries[key])
+	}
+
+	return strings.Join(data, "\n"), nil
+}
+
+// decodeSourceData returns the given source data as a string suitable for
+// PEM validation. DER-encoded certificates and PKCS#7 bundles are converted to
+// PEM; any other data is returned as-is.
+func decodeSourceData(data []byte) string {
+	if pemData, ok := util.DecodeDERBundle(data); ok {
+		return string(pemData)
+	}
+
+	if pemData, ok := util.DecodePKCS7Bundle(data); ok {
+		return string(pemData)
+	}
+
+	return string(data)
+}
+
+// inLineDERBundle returns the given DER-encoded certificates as a PEM bundle.
+func inLineDERBundle(certificates [][]byte) (string, error) {
+	var data []byte
+	for i, der := range certificates {
+		pemData, ok := util.DecodeDERBundle(der)
+		if !ok {
+			return "", fmt.Errorf("inLineDER entry %d is not a DER-encoded certificate", i)
+		}
+		data = append(data, pemData...)
 	}
 
 	return string(data), nil
 }
 
+// tlsSecretBundle returns the CA certificates found in the `tls.crt` and
+// `ca.crt` keys of the target `kubernetes.io/tls` Secret within the trust
+// Namespace. Leaf certificates in the chain are dropped, so that only trust
+// anchors and intermediates are distributed.
+func (b *bundle) tlsSecretBundle(ctx context.Context, ref *trustapi.SourceObjectSelector) (string, error) {
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+	if apierrors.IsNotFound(err) {
+		return "", notFoundError{err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	if secret.Type != corev1.SecretTypeTLS {
+		return "", fmt.Errorf("source Secret %s/%s is of type %q but must be %q", b.Namespace, ref.Name, secret.Type, corev1.SecretTypeTLS)
+	}
+
+	var data []byte
+	for _, key := range []string{corev1.TLSCertKey, corev1.ServiceAccountRootCAKey} {
+		data = append(data, secret.Data[key]...)
+		data = append(data, '\n')
+	}
+
+	certificates, err := util.ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return "", fmt.Errorf("invalid PEM data in Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	var caCertificates [][]byte
+	for _, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+
+		// Certificates have already been validated, so parsing cannot fail here.
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse certificate in Secret %s/%s: %w", b.Namespace, ref.Name, err)
+		}
+
+		if cert.IsCA {
+			caCertificates = append(caCertificates, certificate)
+		}
+	}
+
+	if len(caCertificates) == 0 {
+		return "", notFoundError{fmt.Errorf("no CA certificates found in Secret %s/%s at keys %q or %q", b.Namespace, ref.Name, corev1.TLSCertKey, corev1.ServiceAccountRootCAKey)}
+	}
+
+	return string(bytes.Join(caCertificates, nil)), nil
+}
+
+// istioCACertsSecretBundle returns the root certificates found in the target
+// Secret within the trust Namespace, which uses the layout of the Istio
+// `cacerts` Secret. All CA certificates in `root-cert.pem` are returned, along
+// with any self-signed certificates in `cert-chain.pem`; intermediates are
+// dropped so that only the mesh roots are distributed.
+func (b *bundle) istioCACertsSecretBundle(ctx context.Context, ref *trustapi.SourceObjectSelector) (string, error) {
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+	if apierrors.IsNotFound(err) {
+		return "", notFoundError{err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	var rootCertificates [][]byte
+	seen := make(map[string]struct{})
+	for _, key := range []string{IstioRootCertKey, IstioCertChainKey} {
+		data, ok := secret.Data[key]
+		if !ok {
+			continue
+		}
+
+		certificates, err := util.ValidateAndSplitPEMBundle(data)
+		if err != nil {
+			return "", fmt.Errorf("invalid PEM data in Secret %s/%s at key %q: %w", b.Namespace, ref.Name, key, err)
+		}
+
+		for _, certificate := range certificates {
+			block, _ := pem.Decode(certificate)
+
+			// Certificates have already been validated, so parsing cannot fail here.
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return "", fmt.Errorf("failed to parse certificate in Secret %s/%s: %w", b.Namespace, ref.Name, err)
+			}
+
+			if !cert.IsCA {
+				continue
+			}
+
+			// Only self-signed certificates in the chain are roots.
+			if key == IstioCertChainKey && (!bytes.Equal(cert.RawIssuer, cert.RawSubject) || cert.CheckSignatureFrom(cert) != nil) {
+				continue
+			}
+
+			fingerprint := certificateFingerprint(block.Bytes)
+			if _, ok := seen[fingerprint]; ok {
+				continue
+			}
+			seen[fingerprint] = struct{}{}
+
+			rootCertificates = append(rootCertificates, certificate)
+		}
+	}
+
+	if len(rootCertificates) == 0 {
+		return "", notFoundError{fmt.Errorf("no root certificates found in Secret %s/%s at keys %q or %q", b.Namespace, ref.Name, IstioRootCertKey, IstioCertChainKey)}
+	}
+
+	return string(bytes.Join(rootCertificates, nil)), nil
+}
+
 // encodeJKS creates a binary JKS file from the given PEM-encoded trust bundle and password.
 // Note that the password is not treated securely; JKS files generally seem to expect a password
 // to exist and so we have the option for one.
-func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
+// Entries are named using the given alias naming strategy.
+// If creationTime is non-zero, it is used as the creation time of every entry.
+func encodeJKS(trustBundle string, password []byte, aliasNaming trustapi.JKSAliasNaming, creationTime time.Time) ([]byte, error) {
 	remaining := []byte(trustBundle)
 
-	// WithOrderedAliases ensures that trusted certs are added to the JKS file in order,
-	// which makes the files appear to be reliably deterministic.
-	ks := jks.New(jks.WithOrderedAliases())
-
+	var certs []*x509.Certificate
 	for len(remaining) > 0 {
 		var p *pem.Block
 
@@ -177,7 +877,16 @@ func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 			return nil, fmt.Errorf("got invalid cert when trying to encode JKS: %w", err)
 		}
 
-		alias := jksAlias(c.Raw, c.Subject.String())
+		certs = append(certs, c)
+	}
+
+	// WithOrderedAliases ensures that trusted certs are added to the JKS file in order,
+	// which makes the files appear to be reliably deterministic.
+	ks := jks.New(jks.WithOrderedAliases())
+
+	aliases := jksAliases(certs, aliasNaming)
+	for i, c := range certs {
+		alias := aliases[i]
 
 		// Note on CreationTime:
 		// Debian's JKS trust store sets the creation time to match the time that certs are added to the
@@ -186,13 +895,17 @@ func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 		// two options if we want to maintain determinism:
 		// - Using something from the cert being added (e.g. NotBefore / NotAfter)
 		// - Using a fixed time (i.e. unix epoch)
-		// We use NotBefore here, arbitrarily.
+		// We use NotBefore here, arbitrarily, unless the Bundle opted in to informative build metadata.
+		entryCreationTime := c.NotBefore
+		if !creationTime.IsZero() {
+			entryCreationTime = creationTime
+		}
 
-		err = ks.SetTrustedCertificateEntry(alias, jks.TrustedCertificateEntry{
-			CreationTime: c.NotBefore,
+		err := ks.SetTrustedCertificateEntry(alias, jks.TrustedCertificateEntry{
+			CreationTime: entryCreationTime,
 			Certificate: jks.Certificate{
 				Type:    "X509",
-				Content: p.Bytes,
+				Content: c.Raw,
 			},
 		})
 
@@ -212,6 +925,258 @@ func encodeJKS(trustBundle string, password []byte) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// encodePKCS12 creates a binary PKCS#12 truststore from the given PEM-encoded
+// trust bundle, encrypted with the given password using the algorithms of the
+// given profile. The truststore is randomly salted, so encoding the same bundle
+// twice gives different results.
+func encodePKCS12(trustBundle string, password string, profile trustapi.PKCS12Profile) ([]byte, error) {
+	var certificates []*x509.Certificate
+	remaining := []byte(trustBundle)
+	for {
+		var p *pem.Block
+		p, remaining = pem.Decode(remaining)
+		if p == nil {
+			break
+		}
+
+		c, err := x509.ParseCertificate(p.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid cert when trying to encode PKCS#12: %w", err)
+		}
+		certificates = append(certificates, c)
+	}
+
+	return pkcs12Encoder(profile).EncodeTrustStore(certificates, password)
+}
+
+// buildTimestampKey returns the key of the target entry the build time is
+// written to, and whether the target embeds informative build metadata.
+func buildTimestampKey(target trustapi.BundleTarget) (string, bool) {
+	if target.BuildInfo == nil || target.BuildInfo.Mode != trustapi.BuildInfoModeInformative {
+		return "", false
+	}
+
+	if len(target.BuildInfo.TimestampKey) > 0 {
+		return target.BuildInfo.TimestampKey, true
+	}
+
+	return trustapi.DefaultBuildTimestampKey, true
+}
+
+// namespaceMatcher matches the labels of the Namespaces a target is synced to.
+type namespaceMatcher interface {
+	Matches(labels.Labels) bool
+}
+
+// excludingMatcher matches the labels which are matched by include, but not by
+// exclude.
+type excludingMatcher struct {
+	include, exclude labels.Selector
+}
+
+func (e excludingMatcher) Matches(l labels.Labels) bool {
+	return e.include.Matches(l) && !e.exclude.Matches(l)
+}
+
+// namespaceLabelSelector returns the selector matching the labels and
+// expressions of the given namespace selector. False is returned if the
+// selector is not set or defines neither labels nor expressions.
+func namespaceLabelSelector(nsSelector *trustapi.NamespaceSelector) (labels.Selector, bool, error) {
+	if nsSelector == nil || (nsSelector.MatchLabels == nil && len(nsSelector.MatchExpressions) == 0) {
+		return labels.Everything(), false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      nsSelector.MatchLabels,
+		MatchExpressions: nsSelector.MatchExpressions,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return selector, true, nil
+}
+
+// targetNamespaceSelector returns the matcher of the Namespaces the target is
+// synced to, which matches the labels and expressions of the target's
+// namespace selector, or the names of the target's Namespaces. Namespaces are
+// matched by name using the name label Kubernetes sets on every Namespace. If
+// neither is set, all Namespaces are selected. Namespaces matching the
+// target's namespace exclude selector are never selected. In Local mode, only
+// the trust Namespace is selected.
+func targetNamespaceSelector(target trustapi.BundleTarget, trustNamespace string) (namespaceMatcher, error) {
+	if target.Mode == trustapi.TargetModeLocal {
+		return labels.SelectorFromSet(labels.Set{corev1.LabelMetadataName: trustNamespace}), nil
+	}
+
+	include, ok, err := namespaceLabelSelector(target.NamespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok && len(target.Namespaces) > 0 {
+		include, err = metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      corev1.LabelMetadataName,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   target.Namespaces,
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	exclude, ok, err := namespaceLabelSelector(target.NamespaceExcludeSelector)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return include, nil
+	}
+
+	return excludingMatcher{include: include, exclude: exclude}, nil
+}
+
+// namespaceSkipsTargets returns true if the Namespace is annotated to be
+// excluded from the targets of all Bundles.
+func namespaceSkipsTargets(namespace *corev1.Namespace) bool {
+	return namespace.Annotations[trustapi.NamespaceSkipTargetsAnnotationKey] == "true"
+}
+
+// namespaceTargetKey returns the key of the target entry the bundle data is
+// written to in the given Namespace. This is the key given by the Namespace's
+// target key annotation if it is a valid ConfigMap key, or otherwise the key
+// of the Bundle's target.
+func namespaceTargetKey(namespace *corev1.Namespace, target trustapi.BundleTarget) string {
+	if key, ok := namespace.Annotations[trustapi.NamespaceTargetKeyAnnotationKey]; ok && len(validation.IsConfigMapKey(key)) == 0 {
+		return key
+	}
+
+	return target.ConfigMap.Key
+}
+
+// metadataKey returns the key of the target entry the metadata document is
+// written to, and whether the target has the metadata format.
+func metadataKey(target trustapi.BundleTarget) (string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.Metadata == nil {
+		return "", false
+	}
+
+	return target.AdditionalFormats.Metadata.Key, true
+}
+
+// spiffeBundleKey returns the key of the target entry the SPIFFE trust bundle is
+// written to, and whether the target has the SPIFFE format.
+func spiffeBundleKey(target trustapi.BundleTarget) (string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.SPIFFE == nil {
+		return "", false
+	}
+
+	return target.AdditionalFormats.SPIFFE.Key, true
+}
+
+// pkcs7Key returns the key of the target entry the PKCS#7 bundle is written
+// to, and whether the target has the PKCS#7 format.
+func pkcs7Key(target trustapi.BundleTarget) (string, bool) {
+	if target.AdditionalFormats == nil || target.AdditionalFormats.PKCS7 == nil {
+		return "", false
+	}
+
+	return target.AdditionalFormats.PKCS7.Key, true
+}
+
+// jksHasPassword returns true if the given binary JKS file can be loaded using
+// the given password.
+func jksHasPassword(data, password []byte) bool {
+	return jks.New().Load(bytes.NewReader(data), password) == nil
+}
+
+// targetKeysChanged returns true if the keys written for the given targets
+// differ, in which case the keys of the old target are removed before the new
+// target is synced. Other fields of the target, such as its metadata and
+// policies, don't decide which keys are written, so changing them must not
+// remove the bundle data from the targets.
+func targetKeysChanged(old, current trustapi.BundleTarget) bool {
+	return targetKeySelector(old) != targetKeySelector(current) ||
+		!apiequality.Semantic.DeepEqual(old.AdditionalFormats, current.AdditionalFormats) ||
+		!apiequality.Semantic.DeepEqual(old.BuildInfo, current.BuildInfo) ||
+		!apiequality.Semantic.DeepEqual(old.Immutable, current.Immutable) ||
+		!apiequality.Semantic.DeepEqual(old.Istio, current.Istio) ||
+		!apiequality.Semantic.DeepEqual(old.OpenShiftTrustedCA, current.OpenShiftTrustedCA)
+}
+
+// targetKeySelector returns the fields of the target's ConfigMap key selector
+// which decide the name of the target and the key it is written to.
+func targetKeySelector(target trustapi.BundleTarget) trustapi.TargetKeySelector {
+	if target.ConfigMap == nil {
+		return trustapi.TargetKeySelector{}
+	}
+
+	return trustapi.TargetKeySelector{
+		Name:   target.ConfigMap.Name,
+		Key:    target.ConfigMap.Key,
+		Format: target.ConfigMap.Format,
+	}
+}
+
+// targetBinaryKeys returns the keys of the entries of the target's
+// `binaryData` field which trust-manager writes.
+func targetBinaryKeys(target trustapi.BundleTarget) []string {
+	var keys []string
+	if formats := target.AdditionalFormats; formats != nil {
+		if formats.JKS != nil {
+			keys = append(keys, formats.JKS.Key)
+		}
+		if formats.PKCS12 != nil {
+			keys = append(keys, formats.PKCS12.Key)
+		}
+		if formats.PKCS7 != nil {
+			keys = append(keys, formats.PKCS7.Key)
+		}
+		if formats.SST != nil {
+			keys = append(keys, formats.SST.Key)
+		}
+		if formats.Gzip != nil {
+			keys = append(keys, formats.Gzip.Key)
+		}
+	}
+	if packaging, _, tarballKey, ok := hashedDirectoryFormat(target); ok && packaging == trustapi.HashedDirectoryPackagingTarball {
+		keys = append(keys, tarballKey)
+	}
+
+	return keys
+}
+
+// removeConflictingKeys removes the keys which are present in both the data
+// and binaryData fields of the ConfigMap from the field they aren't written
+// to. Keys of the given binary entries are kept in the binaryData field, and
+// all other keys in the data field. Returns true if the ConfigMap was changed.
+func removeConflictingKeys(configMap *corev1.ConfigMap, binaryKeys []string) bool {
+	var changed bool
+	for key := range configMap.BinaryData {
+		if _, ok := configMap.Data[key]; !ok {
+			continue
+		}
+
+		if sets.NewString(binaryKeys...).Has(key) {
+			delete(configMap.Data, key)
+		} else {
+			delete(configMap.BinaryData, key)
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// pkcs12HasPassword returns true if the given binary PKCS#12 truststore can be
+// decoded using the given password.
+func pkcs12HasPassword(data []byte, password string) bool {
+	_, err := pkcs12.DecodeTrustStore(data, password)
+	return err == nil
+}
+
 // jksAlias creates a JKS-safe alias for the given DER-encoded certificate, such that
 // any two certificates will have a different aliases unless they're identical in every way.
 // This unique alias fixes an issue where we used the Issuer field as an alias, leading to
@@ -230,55 +1195,319 @@ func jksAlias(derData []byte, friendlyName string) string {
 	return certHash[:8] + "|" + friendlyName
 }
 
+// jksAliases returns the aliases of the JKS entries of the given certificates,
+// in order, using the given alias naming strategy. The aliases are lower case,
+// as JKS aliases are case insensitive.
+func jksAliases(certs []*x509.Certificate, aliasNaming trustapi.JKSAliasNaming) []string {
+	aliases := make([]string, len(certs))
+	switch aliasNaming {
+	case trustapi.JKSAliasNamingSubjectCN:
+		names := make([]string, len(certs))
+		counts := make(map[string]int, len(certs))
+		for i, c := range certs {
+			names[i] = strings.ToLower(c.Subject.CommonName)
+			if len(names[i]) == 0 {
+				names[i] = strings.ToLower(c.Subject.String())
+			}
+			counts[names[i]]++
+		}
+
+		// Suffix clashing names by fingerprint rather than by position, so
+		// that an entry's alias doesn't depend on the other certificates in
+		// the bundle with the same name.
+		for i, c := range certs {
+			aliases[i] = names[i]
+			if counts[names[i]] > 1 {
+				certHash := sha256.Sum256(c.Raw)
+				aliases[i] += "-" + hex.EncodeToString(certHash[:])[:8]
+			}
+		}
+
+	case trustapi.JKSAliasNamingSequential:
+		for i := range certs {
+			aliases[i] = fmt.Sprintf("%04d", i)
+		}
+
+	default:
+		for i, c := range certs {
+			aliases[i] = strings.ToLower(jksAlias(c.Raw, c.Subject.String()))
+		}
+	}
+
+	return aliases
+}
+
+// jksHasAliases returns true if the given binary JKS truststore, encrypted
+// with the given password, has the same entry aliases as the expected one.
+func jksHasAliases(data, expected, password []byte) bool {
+	ks, expectedKS := jks.New(), jks.New()
+	if ks.Load(bytes.NewReader(data), password) != nil || expectedKS.Load(bytes.NewReader(expected), password) != nil {
+		return false
+	}
+
+	return sets.NewString(ks.Aliases()...).Equal(sets.NewString(expectedKS.Aliases()...))
+}
+
+// targetCollisions returns the sorted names of the Namespaces selected by the
+// given Bundle in which the target ConfigMap already exists but is not owned
+// by the Bundle.
+func (b *bundle) targetCollisions(ctx context.Context,
+	bundle *trustapi.Bundle,
+	namespaceSelector namespaceMatcher,
+	namespaces []corev1.Namespace,
+) ([]string, error) {
+	targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return nil, err
+	}
+
+	var collisions []string
+	for _, namespace := range namespaces {
+		if namespace.Status.Phase == corev1.NamespaceTerminating || !namespaceSelector.Matches(labels.Set(namespace.Labels)) || namespaceSkipsTargets(&namespace) {
+			continue
+		}
+
+		var configMap corev1.ConfigMap
+		err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: targetName}, &configMap)
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get configmap %s/%s: %w", namespace.Name, targetName, err)
+		}
+
+		if !metav1.IsControlledBy(&configMap, bundle) {
+			collisions = append(collisions, namespace.Name)
+		}
+	}
+
+	sort.Strings(collisions)
+
+	return collisions, nil
+}
+
 // syncTarget syncs the given data to the target ConfigMap in the given namespace.
-// The name of the ConfigMap is the same as the Bundle.
+// The name of the ConfigMap is the name set on the target, or otherwise
+// rendered from the Bundle's name by the naming conventions, and is the same
+// as the Bundle by default.
 // Ensures the ConfigMap is owned by the given Bundle, and the data is up to date.
-// Returns true if the ConfigMap has been created or was updated.
+// Returns true if the ConfigMap has been created or was updated. If hash is
+// set, it is written to the hash annotation of the ConfigMap, and the second
+// return value reports whether the consumers of the ConfigMap have
+// acknowledged it. If partitions are given, they are written instead of the
+// complete data. Binary truststores are encrypted with the given passwords.
 func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 	bundle *trustapi.Bundle,
-	namespaceSelector labels.Selector,
+	namespaceSelector namespaceMatcher,
 	namespace *corev1.Namespace,
-	data string,
-) (bool, error) {
+	data, metadata, spiffe, provenance, hash string,
+	directory, profiles map[string]string,
+	partitions []string,
+	jksPassword []byte,
+	pkcs12Password string,
+) (bool, bool, error) {
 	target := bundle.Spec.Target
 	var binData *[]byte
 
 	if target.ConfigMap == nil {
-		return false, errors.New("target not defined")
+		return false, false, errors.New("target not defined")
+	}
+
+	targetName, err := b.Naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return false, false, err
+	}
+
+	matchNamespace := namespaceSelector.Matches(labels.Set(namespace.Labels)) && !namespaceSkipsTargets(namespace)
+	key := namespaceTargetKey(namespace, target)
+
+	// Partitioned bundle data is written to the partition entries rather than
+	// the target key.
+	indexKey := partitionIndexKey(target)
+	entries := map[string]string{key: data}
+	if len(partitions) > 0 {
+		entries = partitionEntries(targetName, key, indexKey, partitions)
+	}
+
+	// Comments are only written to the complete bundle data, since partitions
+	// are sized to fit the target without them.
+	if target.ConfigMap.Comments && len(partitions) == 0 {
+		commented, err := util.CommentPEMBundle([]byte(data))
+		if err != nil {
+			return false, false, err
+		}
+		entries[key] = string(commented)
+	}
+
+	// The bundle data is also written to the previous keys of the target
+	// which are still retained, and removed from those whose retention has
+	// expired.
+	var retainedKeys, expiredKeys []string
+	if len(bundle.Status.RetainedKeys) > 0 {
+		retainedKeys, expiredKeys = retainedTargetKeys(bundle, b.clock.Now())
+	}
+	if key == target.ConfigMap.Key && len(partitions) == 0 {
+		for _, retainedKey := range retainedKeys {
+			entries[retainedKey] = entries[key]
+		}
+	}
+
+	// Bundles in the DER format are written to the binaryData field rather
+	// than the data field.
+	var derData []byte
+	der := target.ConfigMap.Format == trustapi.TargetFormatDER
+	if der {
+		derData = util.EncodeDERBundle([]byte(data))
+		entries = map[string]string{}
+	}
+
+	// The PKCS#7 bundle, the serialized certificate store and the compressed
+	// bundle data are deterministic, so unlike other binary formats they are
+	// compared with the target to detect changes.
+	var pkcs7Data []byte
+	pkcs7Key, hasPKCS7 := pkcs7Key(target)
+	if hasPKCS7 {
+		pkcs7Data, err = util.EncodePKCS7Bundle([]byte(data))
+		if err != nil {
+			return false, false, err
+		}
+	}
+
+	var sstData []byte
+	sstKey, hasSST := sstKey(target)
+	if hasSST {
+		sstData = encodeSST(data)
+	}
+
+	var hashedDirectory map[string]string
+	var hashedDirectoryTarball []byte
+	hashedPackaging, hashedIndexKey, hashedTarballKey, hasHashedDirectory := hashedDirectoryFormat(target)
+	if hasHashedDirectory {
+		if hashedPackaging == trustapi.HashedDirectoryPackagingTarball {
+			hashedDirectoryTarball, err = encodeHashedDirectoryTarball(data)
+		} else {
+			hashedDirectory, err = encodeHashedDirectory(data, hashedIndexKey)
+		}
+		if err != nil {
+			return false, false, fmt.Errorf("failed to build hashed directory: %w", err)
+		}
 	}
 
-	matchNamespace := namespaceSelector.Matches(labels.Set(namespace.Labels))
+	var gzipData []byte
+	gzipTarget := gzipFormat(target)
+	if gzipTarget != nil {
+		gzipData, err = encodeGzip(data)
+		if err != nil {
+			return false, false, err
+		}
+
+		if gzipTarget.OmitUncompressed {
+			entries = map[string]string{}
+		}
+	}
+
+	binaryKeys := targetBinaryKeys(target)
+	if der {
+		binaryKeys = append(binaryKeys, key)
+	}
+
+	// The keys written to the target are recorded, so that those of formats
+	// removed from the target are pruned.
+	managedKeys := managedTargetKeys(target, entries, binaryKeys)
 
 	var configMap corev1.ConfigMap
-	err := b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: bundle.Name}, &configMap)
+	err = b.targetDirectClient.Get(ctx, client.ObjectKey{Namespace: namespace.Name, Name: targetName}, &configMap)
+
+	// The build time is only embedded in the target when the Bundle opts in
+	// to informative build metadata.
+	var buildTime time.Time
+	timestampKey, informative := buildTimestampKey(target)
+	if informative {
+		buildTime = b.clock.Now().UTC().Truncate(time.Second)
+	}
 
 	if target.AdditionalFormats != nil && target.AdditionalFormats.JKS != nil {
-		j, err := encodeJKS(data, []byte(DefaultJKSPassword))
+		j, err := encodeJKS(data, jksPassword, target.AdditionalFormats.JKS.AliasNaming, buildTime)
 		if err != nil {
-			return false, err
+			return false, false, err
 		}
 
 		binData = &j
 	}
 
+	// PKCS#12 truststores are randomly salted, so they are only encoded when
+	// they need to be written.
+	var pkcs12Key string
+	var pkcs12Profile trustapi.PKCS12Profile
+	hasPKCS12 := target.AdditionalFormats != nil && target.AdditionalFormats.PKCS12 != nil
+	if hasPKCS12 {
+		pkcs12Key = target.AdditionalFormats.PKCS12.Key
+		pkcs12Profile = target.AdditionalFormats.PKCS12.Profile
+	}
+
 	// If the ConfigMap doesn't exist yet, create it.
 	if apierrors.IsNotFound(err) {
 		// If the namespace doesn't match selector we do nothing since we don't
 		// want to create it, and it also doesn't exist.
 		if !matchNamespace {
 			log.V(4).Info("ignoring namespace as it doesn't match selector", "labels", namespace.Labels)
-			return false, nil
+			return false, false, nil
 		}
 
 		configMap = corev1.ConfigMap{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:            bundle.Name,
+				Name:            targetName,
 				Namespace:       namespace.Name,
 				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle"))},
 			},
-			Data: map[string]string{
-				target.ConfigMap.Key: data,
-			},
+			Data: make(map[string]string, len(entries)),
+		}
+
+		for entryKey, value := range entries {
+			configMap.Data[entryKey] = value
+		}
+
+		if key != target.ConfigMap.Key {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, appliedTargetKeyAnnotation, key)
+		}
+
+		if len(hash) > 0 {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, b.Naming.TargetHashAnnotationKey(), hash)
+		}
+
+		if gzipTarget != nil {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, trustapi.TargetUncompressedHashAnnotationKey, contentHash(data))
+		}
+
+		syncTargetMetadata(&configMap, b.targetMetadata(bundle))
+		setManagedTargetKeys(&configMap, managedKeys)
+
+		if informative {
+			configMap.Data[timestampKey] = buildTime.Format(time.RFC3339)
+		}
+
+		if metadataKey, ok := metadataKey(target); ok {
+			configMap.Data[metadataKey] = metadata
+		}
+
+		if spiffeKey, ok := spiffeBundleKey(target); ok {
+			configMap.Data[spiffeKey] = spiffe
+		}
+
+		if provenanceKey, ok := provenanceKey(target); ok {
+			configMap.Data[provenanceKey] = provenance
+		}
+
+		if _, indexKey, ok := pemDirectoryKeys(target); ok {
+			syncPEMDirectory(&configMap, indexKey, directory)
+		}
+
+		if hashedDirectory != nil {
+			syncPEMDirectory(&configMap, hashedIndexKey, hashedDirectory)
+		}
+
+		for profileKey, profile := range profiles {
+			configMap.Data[profileKey] = profile
 		}
 
 		if binData != nil {
@@ -287,11 +1516,63 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 			}
 		}
 
-		return true, b.targetDirectClient.Create(ctx, &configMap)
+		if der {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[key] = derData
+		}
+
+		if hasPKCS12 {
+			p12, err := encodePKCS12(data, pkcs12Password, pkcs12Profile)
+			if err != nil {
+				return false, false, err
+			}
+
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[pkcs12Key] = p12
+		}
+
+		if hasPKCS7 {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[pkcs7Key] = pkcs7Data
+		}
+
+		if hasSST {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[sstKey] = sstData
+		}
+
+		if hashedDirectoryTarball != nil {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[hashedTarballKey] = hashedDirectoryTarball
+		}
+
+		if gzipTarget != nil {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[gzipTarget.Key] = gzipData
+		}
+
+		if err := b.targetDirectClient.Create(ctx, &configMap); err != nil {
+			return true, false, err
+		}
+
+		_, err := b.syncPartitions(ctx, bundle, namespace.Name, targetName, key, partitions, "")
+		return true, false, err
 	}
 
 	if err != nil {
-		return false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace, bundle.Name, err)
+		return false, false, fmt.Errorf("failed to get configmap %s/%s: %w", namespace.Name, targetName, err)
 	}
 
 	// Here, the config map exists, but the selector doesn't match the namespace.
@@ -299,37 +1580,212 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 		// The ConfigMap is owned by this controller- delete it.
 		if metav1.IsControlledBy(&configMap, bundle) {
 			log.V(2).Info("deleting bundle from Namespace since namespaceSelector does not match")
-			return true, b.targetDirectClient.Delete(ctx, &configMap)
+			if _, err := b.syncPartitions(ctx, bundle, namespace.Name, targetName, key, nil, configMap.Data[indexKey]); err != nil {
+				return true, false, err
+			}
+			return true, false, b.targetDirectClient.Delete(ctx, &configMap)
 		}
 		// The ConfigMap isn't owned by us, so we shouldn't delete it. Return that
 		// we did nothing.
 		b.recorder.Eventf(&configMap, corev1.EventTypeWarning, "NotOwned", "ConfigMap is not owned by trust.cert-manager.io so ignoring")
-		return false, nil
+		return false, false, nil
 	}
 
 	var needsUpdate bool
-	// If ConfigMap is missing OwnerReference, add it back.
+	// If ConfigMap is missing OwnerReference, add it back. Targets which
+	// conflict with the Bundle were already checked against its conflict
+	// policy.
 	if !metav1.IsControlledBy(&configMap, bundle) {
-		configMap.OwnerReferences = append(configMap.OwnerReferences, *metav1.NewControllerRef(bundle, trustapi.SchemeGroupVersion.WithKind("Bundle")))
+		adoptTarget(&configMap, bundle)
 		needsUpdate = true
 	}
 
 	needsJKS := false
 	if target.AdditionalFormats != nil && target.AdditionalFormats.JKS != nil {
-		if _, ok := configMap.BinaryData[target.AdditionalFormats.JKS.Key]; !ok {
+		// The JKS file must also be rebuilt if it is no longer encrypted with
+		// the configured password, or its aliases were named using another
+		// alias naming strategy.
+		if jksData, ok := configMap.BinaryData[target.AdditionalFormats.JKS.Key]; !ok || !jksHasPassword(jksData, jksPassword) || !jksHasAliases(jksData, *binData, jksPassword) {
 			needsJKS = true
 		}
 	}
 
-	// If PEM not present, or if JKS required and not present, or configmap PEM doesn't match
+	// As for JKS, the PKCS#12 truststore is rebuilt if it is missing or no
+	// longer encrypted with the configured password and profile.
+	needsPKCS12 := false
+	if hasPKCS12 {
+		if p12Data, ok := configMap.BinaryData[pkcs12Key]; !ok || !pkcs12HasPassword(p12Data, pkcs12Password) || !pkcs12HasProfile(p12Data, pkcs12Profile) {
+			needsPKCS12 = true
+		}
+	}
+
+	// If PEM not present, or if JKS required and not present or encrypted with another password,
+	// or configmap PEM doesn't match.
 	// Generated JKS is not deterministic - best we can do here is update if the pem cert has
-	// changed (hence not checking if JKS matches)
-	if cmdata, ok := configMap.Data[target.ConfigMap.Key]; !ok || needsJKS || cmdata != data {
+	// changed (hence not checking if JKS contents match)
+	// If informative build metadata is requested but the build time is not
+	// present, the build time is written along with the data.
+	needsTimestamp := false
+	if informative {
+		if _, ok := configMap.Data[timestampKey]; !ok {
+			needsTimestamp = true
+		}
+	}
+
+	needsDER := false
+	if der && !bytes.Equal(configMap.BinaryData[key], derData) {
+		needsDER = true
+	}
+
+	needsPKCS7 := false
+	if hasPKCS7 && !bytes.Equal(configMap.BinaryData[pkcs7Key], pkcs7Data) {
+		needsPKCS7 = true
+	}
+
+	needsSST := false
+	if hasSST && !bytes.Equal(configMap.BinaryData[sstKey], sstData) {
+		needsSST = true
+	}
+
+	needsHashedDirectoryTarball := false
+	if hashedDirectoryTarball != nil && !bytes.Equal(configMap.BinaryData[hashedTarballKey], hashedDirectoryTarball) {
+		needsHashedDirectoryTarball = true
+	}
+
+	needsGzip := false
+	if gzipTarget != nil && !bytes.Equal(configMap.BinaryData[gzipTarget.Key], gzipData) {
+		needsGzip = true
+	}
+
+	needsMetadata := false
+	metadataKey, hasMetadata := metadataKey(target)
+	if hasMetadata && configMap.Data[metadataKey] != metadata {
+		needsMetadata = true
+	}
+
+	needsSPIFFE := false
+	spiffeKey, hasSPIFFE := spiffeBundleKey(target)
+	if hasSPIFFE && configMap.Data[spiffeKey] != spiffe {
+		needsSPIFFE = true
+	}
+
+	needsProvenance := false
+	provenanceKey, hasProvenance := provenanceKey(target)
+	if hasProvenance && configMap.Data[provenanceKey] != provenance {
+		needsProvenance = true
+	}
+
+	needsProfiles := false
+	for profileKey, profile := range profiles {
+		if existing, ok := configMap.Data[profileKey]; !ok || existing != profile {
+			needsProfiles = true
+		}
+	}
+
+	// The partitions of the bundle data are written to ConfigMaps of their
+	// own, which are also synced if the target itself is unchanged.
+	previousIndex := configMap.Data[indexKey]
+	partitionsChanged, err := b.syncPartitions(ctx, bundle, namespace.Name, targetName, key, partitions, previousIndex)
+	if err != nil {
+		return partitionsChanged, false, err
+	}
+
+	// The bundle data, or its partition entries, are written directly, along
+	// with the other formats if they changed.
+	needsData := syncPartitionEntries(&configMap, key, indexKey, entries)
+
+	// Certificates removed from the bundle are also removed from the PEM
+	// directory and the hashed directory, whose entries are tracked by an
+	// index in the same way.
+	if _, indexKey, ok := pemDirectoryKeys(target); ok && syncPEMDirectory(&configMap, indexKey, directory) {
+		needsUpdate = true
+	}
+	if hashedDirectory != nil && syncPEMDirectory(&configMap, hashedIndexKey, hashedDirectory) {
+		needsUpdate = true
+	}
+
+	for _, expiredKey := range expiredKeys {
+		if _, ok := entries[expiredKey]; !ok {
+			if _, ok := configMap.Data[expiredKey]; ok {
+				delete(configMap.Data, expiredKey)
+				needsUpdate = true
+			}
+		}
+	}
+
+	// If the key the data is written to has changed since the last sync,
+	// because the Namespace's target key annotation has changed, remove the
+	// data from the previous key.
+	previousKey := target.ConfigMap.Key
+	if applied, ok := configMap.Annotations[appliedTargetKeyAnnotation]; ok {
+		previousKey = applied
+	}
+	if previousKey != key {
+		delete(configMap.Data, previousKey)
+		if der {
+			delete(configMap.BinaryData, previousKey)
+		}
+		if key == target.ConfigMap.Key {
+			delete(configMap.Annotations, appliedTargetKeyAnnotation)
+		} else {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, appliedTargetKeyAnnotation, key)
+		}
+		needsUpdate = true
+	}
+
+	// The hash annotation is only written for Bundles which track
+	// acknowledgments. The consumers of the target have acknowledged the
+	// data once they have copied the hash to the acknowledged hash annotation.
+	var acknowledged bool
+	if len(hash) > 0 {
+		acknowledged = configMap.Annotations[trustapi.TargetAcknowledgedHashAnnotationKey] == hash
+		if configMap.Annotations[b.Naming.TargetHashAnnotationKey()] != hash {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, b.Naming.TargetHashAnnotationKey(), hash)
+			needsUpdate = true
+		}
+	} else if _, ok := configMap.Annotations[b.Naming.TargetHashAnnotationKey()]; ok {
+		delete(configMap.Annotations, b.Naming.TargetHashAnnotationKey())
+		needsUpdate = true
+	}
+
+	// The hash of the uncompressed data is only written along with the
+	// compressed data.
+	if gzipTarget != nil {
+		if uncompressedHash := contentHash(data); configMap.Annotations[trustapi.TargetUncompressedHashAnnotationKey] != uncompressedHash {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, trustapi.TargetUncompressedHashAnnotationKey, uncompressedHash)
+			needsUpdate = true
+		}
+	} else if _, ok := configMap.Annotations[trustapi.TargetUncompressedHashAnnotationKey]; ok {
+		delete(configMap.Annotations, trustapi.TargetUncompressedHashAnnotationKey)
+		needsUpdate = true
+	}
+
+	// Labels and annotations of the target metadata are reapplied if they
+	// were changed by others.
+	if syncTargetMetadata(&configMap, b.targetMetadata(bundle)) {
+		needsUpdate = true
+	}
+
+	if needsDER || needsJKS || needsPKCS12 || needsPKCS7 || needsSST || needsHashedDirectoryTarball || needsGzip || needsTimestamp || needsMetadata || needsSPIFFE || needsProvenance || needsProfiles || needsData {
 		if configMap.Data == nil {
 			configMap.Data = make(map[string]string)
 		}
 
-		configMap.Data[target.ConfigMap.Key] = data
+		if informative {
+			configMap.Data[timestampKey] = buildTime.Format(time.RFC3339)
+		}
+		if hasMetadata {
+			configMap.Data[metadataKey] = metadata
+		}
+		if hasSPIFFE {
+			configMap.Data[spiffeKey] = spiffe
+		}
+		if hasProvenance {
+			configMap.Data[provenanceKey] = provenance
+		}
+		for profileKey, profile := range profiles {
+			configMap.Data[profileKey] = profile
+		}
 		if binData != nil {
 			if configMap.BinaryData == nil {
 				configMap.BinaryData = make(map[string][]byte)
@@ -337,20 +1793,76 @@ func (b *bundle) syncTarget(ctx context.Context, log logr.Logger,
 
 			configMap.BinaryData[target.AdditionalFormats.JKS.Key] = *binData
 		}
+		if der {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[key] = derData
+		}
+		if hasPKCS12 {
+			p12, err := encodePKCS12(data, pkcs12Password, pkcs12Profile)
+			if err != nil {
+				return false, false, err
+			}
 
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[pkcs12Key] = p12
+		}
+		if hasPKCS7 {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[pkcs7Key] = pkcs7Data
+		}
+		if hasSST {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[sstKey] = sstData
+		}
+		if hashedDirectoryTarball != nil {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[hashedTarballKey] = hashedDirectoryTarball
+		}
+		if gzipTarget != nil {
+			if configMap.BinaryData == nil {
+				configMap.BinaryData = make(map[string][]byte)
+			}
+			configMap.BinaryData[gzipTarget.Key] = gzipData
+		}
+
+		needsUpdate = true
+	}
+
+	// Keys which were written by the controller, but are no longer part of
+	// the target, are pruned.
+	if pruneManagedTargetKeys(&configMap, managedKeys) {
+		needsUpdate = true
+	}
+
+	// The API server rejects ConfigMaps with the same key in both the data
+	// and binaryData fields, which happens when a key moves between a text
+	// and a binary format.
+	if removeConflictingKeys(&configMap, binaryKeys) {
 		needsUpdate = true
 	}
 
 	// Exit early if no update is needed
 	if !needsUpdate {
-		return false, nil
+		return partitionsChanged, acknowledged, nil
 	}
 
+	setManagedTargetKeys(&configMap, managedKeys)
+
 	if err := b.targetDirectClient.Update(ctx, &configMap); err != nil {
-		return true, fmt.Errorf("failed to update configmap %s/%s with bundle: %w", namespace, bundle.Name, err)
+		return true, false, fmt.Errorf("failed to update configmap %s/%s with bundle: %w", namespace.Name, targetName, err)
 	}
 
 	log.V(2).Info("synced bundle to namespace")
 
-	return true, nil
+	return true, acknowledged, nil
 }
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// appliedTargetMetadataAnnotation is the annotation set on target ConfigMaps
+// recording the labels and annotations applied from the Bundle's target
+// metadata, as a sorted, comma separated list of "label:<key>" and
+// "annotation:<key>" entries. It is used to remove the labels and
+// annotations which are removed from the target metadata, without touching
+// those set by others.
+const appliedTargetMetadataAnnotation = "trust.cert-manager.io/applied-target-metadata"
+
+// targetMetadata returns the labels and annotations applied to the target
+// ConfigMaps of the given Bundle, which are those of its target metadata along
+// with its propagated labels. Labels of the target metadata take precedence.
+func (b *bundle) targetMetadata(bundle *trustapi.Bundle) *trustapi.TargetMetadata {
+	metadata := bundle.Spec.Target.Metadata.DeepCopy()
+
+	propagate := b.PropagatedBundleLabels
+	if metadata != nil {
+		propagate = append(append([]string(nil), propagate...), metadata.PropagateLabels...)
+	}
+
+	for _, key := range propagate {
+		value, ok := bundle.Labels[key]
+		if !ok {
+			continue
+		}
+
+		if metadata == nil {
+			metadata = new(trustapi.TargetMetadata)
+		}
+		if _, ok := metadata.Labels[key]; ok {
+			continue
+		}
+		if metadata.Labels == nil {
+			metadata.Labels = make(map[string]string)
+		}
+		metadata.Labels[key] = value
+	}
+
+	return metadata
+}
+
+// syncTargetMetadata applies the labels and annotations of the given target
+// metadata to the target ConfigMap, and removes those which were previously
+// applied but are no longer part of the metadata. Returns true if the
+// ConfigMap was changed.
+func syncTargetMetadata(configMap *corev1.ConfigMap, metadata *trustapi.TargetMetadata) bool {
+	var labels, annotations map[string]string
+	if metadata != nil {
+		labels, annotations = metadata.Labels, metadata.Annotations
+	}
+
+	var changed bool
+
+	for _, entry := range strings.Split(configMap.Annotations[appliedTargetMetadataAnnotation], ",") {
+		kind, key, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+
+		switch kind {
+		case "label":
+			if _, ok := labels[key]; ok {
+				continue
+			}
+			if _, ok := configMap.Labels[key]; ok {
+				delete(configMap.Labels, key)
+				changed = true
+			}
+		case "annotation":
+			if _, ok := annotations[key]; ok {
+				continue
+			}
+			if _, ok := configMap.Annotations[key]; ok {
+				delete(configMap.Annotations, key)
+				changed = true
+			}
+		}
+	}
+
+	applied := make([]string, 0, len(labels)+len(annotations))
+	for key, value := range labels {
+		if existing, ok := configMap.Labels[key]; !ok || existing != value {
+			metav1.SetMetaDataLabel(&configMap.ObjectMeta, key, value)
+			changed = true
+		}
+		applied = append(applied, "label:"+key)
+	}
+	for key, value := range annotations {
+		if existing, ok := configMap.Annotations[key]; !ok || existing != value {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, key, value)
+			changed = true
+		}
+		applied = append(applied, "annotation:"+key)
+	}
+	sort.Strings(applied)
+
+	if len(applied) > 0 {
+		if value := strings.Join(applied, ","); configMap.Annotations[appliedTargetMetadataAnnotation] != value {
+			metav1.SetMetaDataAnnotation(&configMap.ObjectMeta, appliedTargetMetadataAnnotation, value)
+			changed = true
+		}
+	} else if _, ok := configMap.Annotations[appliedTargetMetadataAnnotation]; ok {
+		delete(configMap.Annotations, appliedTargetMetadataAnnotation)
+		changed = true
+	}
+
+	return changed
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_syncTargetMetadata(t *testing.T) {
+	tests := map[string]struct {
+		labels      map[string]string
+		annotations map[string]string
+		metadata    *trustapi.TargetMetadata
+
+		expLabels      map[string]string
+		expAnnotations map[string]string
+		expChanged     bool
+	}{
+		"no metadata should not change the ConfigMap": {
+			labels:         map[string]string{"foo": "bar"},
+			expLabels:      map[string]string{"foo": "bar"},
+			expAnnotations: nil,
+		},
+		"metadata should be applied": {
+			labels: map[string]string{"foo": "bar"},
+			metadata: &trustapi.TargetMetadata{
+				Labels:      map[string]string{"backup": "true"},
+				Annotations: map[string]string{"reloader.stakater.com/match": "true"},
+			},
+			expLabels: map[string]string{"foo": "bar", "backup": "true"},
+			expAnnotations: map[string]string{
+				"reloader.stakater.com/match":   "true",
+				appliedTargetMetadataAnnotation: "annotation:reloader.stakater.com/match,label:backup",
+			},
+			expChanged: true,
+		},
+		"applied metadata should not change the ConfigMap": {
+			labels:         map[string]string{"backup": "true"},
+			annotations:    map[string]string{appliedTargetMetadataAnnotation: "label:backup"},
+			metadata:       &trustapi.TargetMetadata{Labels: map[string]string{"backup": "true"}},
+			expLabels:      map[string]string{"backup": "true"},
+			expAnnotations: map[string]string{appliedTargetMetadataAnnotation: "label:backup"},
+		},
+		"changed metadata values should be reapplied": {
+			labels:         map[string]string{"backup": "false"},
+			annotations:    map[string]string{appliedTargetMetadataAnnotation: "label:backup"},
+			metadata:       &trustapi.TargetMetadata{Labels: map[string]string{"backup": "true"}},
+			expLabels:      map[string]string{"backup": "true"},
+			expAnnotations: map[string]string{appliedTargetMetadataAnnotation: "label:backup"},
+			expChanged:     true,
+		},
+		"removed metadata should be removed, leaving metadata of others": {
+			labels: map[string]string{"backup": "true", "foo": "bar"},
+			annotations: map[string]string{
+				"owner":                         "team",
+				"other":                         "value",
+				appliedTargetMetadataAnnotation: "annotation:owner,label:backup",
+			},
+			metadata:       nil,
+			expLabels:      map[string]string{"foo": "bar"},
+			expAnnotations: map[string]string{"other": "value"},
+			expChanged:     true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Labels: test.labels, Annotations: test.annotations}}
+
+			changed := syncTargetMetadata(configMap, test.metadata)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expLabels, configMap.Labels)
+			assert.Equal(t, test.expAnnotations, configMap.Annotations)
+		})
+	}
+}
+
+func Test_targetMetadata(t *testing.T) {
+	tests := map[string]struct {
+		propagated  []string
+		labels      map[string]string
+		metadata    *trustapi.TargetMetadata
+		expMetadata *trustapi.TargetMetadata
+	}{
+		"no metadata or propagated labels should return nil": {
+			labels: map[string]string{"team": "platform"},
+		},
+		"labels propagated by the controller should be applied": {
+			propagated:  []string{"team", "missing"},
+			labels:      map[string]string{"team": "platform", "other": "value"},
+			expMetadata: &trustapi.TargetMetadata{Labels: map[string]string{"team": "platform"}},
+		},
+		"labels propagated by the Bundle should be added to its metadata": {
+			propagated: []string{"team"},
+			labels:     map[string]string{"team": "platform", "owner": "alice"},
+			metadata: &trustapi.TargetMetadata{
+				Annotations:     map[string]string{"note": "value"},
+				PropagateLabels: []string{"owner"},
+			},
+			expMetadata: &trustapi.TargetMetadata{
+				Labels:          map[string]string{"team": "platform", "owner": "alice"},
+				Annotations:     map[string]string{"note": "value"},
+				PropagateLabels: []string{"owner"},
+			},
+		},
+		"metadata labels should take precedence over propagated labels": {
+			propagated: []string{"team"},
+			labels:     map[string]string{"team": "platform"},
+			metadata:   &trustapi.TargetMetadata{Labels: map[string]string{"team": "security"}},
+			expMetadata: &trustapi.TargetMetadata{
+				Labels: map[string]string{"team": "security"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{Options: Options{PropagatedBundleLabels: test.propagated}}
+			bundle := &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle", Labels: test.labels},
+				Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{Metadata: test.metadata}},
+			}
+
+			assert.Equal(t, test.expMetadata, b.targetMetadata(bundle))
+			assert.Equal(t, test.metadata, bundle.Spec.Target.Metadata, "Bundle should not be mutated")
+		})
+	}
+}
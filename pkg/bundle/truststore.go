@@ -0,0 +1,125 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"fmt"
+
+	jks "github.com/pavlo-v-chernykh/keystore-go/v4"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"software.sslmate.com/src/go-pkcs12"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// truststoreSecretBundle returns the trusted certificates contained in the
+// binary truststore stored in the referenced Secret within the trust
+// Namespace, encoded as PEM.
+func (b *bundle) truststoreSecretBundle(ctx context.Context, ref *trustapi.SourceTruststoreSelector) (string, error) {
+	var secret corev1.Secret
+	err := b.sourceLister.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: ref.Name}, &secret)
+	if apierrors.IsNotFound(err) {
+		return "", notFoundError{err}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", b.Namespace, ref.Name, err)
+	}
+
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", notFoundError{fmt.Errorf("no data found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.Key)}
+	}
+
+	var password []byte
+	if len(ref.PasswordKey) > 0 {
+		password, ok = secret.Data[ref.PasswordKey]
+		if !ok {
+			return "", notFoundError{fmt.Errorf("no password found in Secret %s/%s at key %q", b.Namespace, ref.Name, ref.PasswordKey)}
+		}
+	}
+
+	var pemData string
+	switch ref.Format {
+	case trustapi.TruststoreFormatJKS:
+		if password == nil {
+			password = []byte(DefaultJKSPassword)
+		}
+		pemData, err = decodeJKS(data, password)
+
+	case trustapi.TruststoreFormatPKCS12:
+		pemData, err = decodePKCS12(data, string(password))
+
+	default:
+		return "", fmt.Errorf("unsupported truststore format %q", ref.Format)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to decode truststore in Secret %s/%s at key %q: %w", b.Namespace, ref.Name, ref.Key, err)
+	}
+
+	return pemData, nil
+}
+
+// decodeJKS returns the PEM-encoded trusted certificate entries of the given
+// binary JKS file. Private key entries are ignored.
+func decodeJKS(data, password []byte) (string, error) {
+	ks := jks.New(jks.WithOrderedAliases())
+	if err := ks.Load(bytes.NewReader(data), password); err != nil {
+		return "", fmt.Errorf("failed to load JKS file: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, alias := range ks.Aliases() {
+		if !ks.IsTrustedCertificateEntry(alias) {
+			continue
+		}
+
+		entry, err := ks.GetTrustedCertificateEntry(alias)
+		if err != nil {
+			return "", fmt.Errorf("failed to get trusted certificate entry %q: %w", alias, err)
+		}
+
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: entry.Certificate.Content}); err != nil {
+			return "", fmt.Errorf("failed to encode certificate entry %q: %w", alias, err)
+		}
+	}
+
+	return buf.String(), nil
+}
+
+// decodePKCS12 returns the PEM-encoded certificates of the given binary
+// PKCS#12 truststore.
+func decodePKCS12(data []byte, password string) (string, error) {
+	certificates, err := pkcs12.DecodeTrustStore(data, password)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode PKCS#12 file: %w", err)
+	}
+
+	var buf bytes.Buffer
+	for _, cert := range certificates {
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return "", fmt.Errorf("failed to encode certificate: %w", err)
+		}
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"software.sslmate.com/src/go-pkcs12"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/util"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func mustEncodePKCS12(t *testing.T, password string, certs ...string) []byte {
+	t.Helper()
+
+	var x509Certs []*x509.Certificate
+	for _, cert := range certs {
+		block, _ := pem.Decode([]byte(cert))
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("failed to parse certificate: %s", err)
+		}
+		x509Certs = append(x509Certs, c)
+	}
+
+	data, err := pkcs12.Modern2023.EncodeTrustStore(x509Certs, password)
+	if err != nil {
+		t.Fatalf("failed to encode PKCS#12 truststore: %s", err)
+	}
+
+	return data
+}
+
+func mustEncodeJKS(t *testing.T, password string, certs ...string) []byte {
+	t.Helper()
+
+	data, err := encodeJKS(dummy.JoinCerts(certs...), []byte(password), "", time.Time{})
+	if err != nil {
+		t.Fatalf("failed to encode JKS truststore: %s", err)
+	}
+
+	return data
+}
+
+func Test_truststoreSecretBundle(t *testing.T) {
+	truststoreRef := func(format trustapi.TruststoreFormat, passwordKey string) *trustapi.SourceTruststoreSelector {
+		return &trustapi.SourceTruststoreSelector{
+			SourceObjectKeySelector: trustapi.SourceObjectKeySelector{Name: "truststore", Key: "truststore"},
+			Format:                  format,
+			PasswordKey:             passwordKey,
+		}
+	}
+
+	tests := map[string]struct {
+		ref              *trustapi.SourceTruststoreSelector
+		data             map[string][]byte
+		expCerts         []string
+		expError         bool
+		expNotFoundError bool
+	}{
+		"JKS truststore with default password": {
+			ref:      truststoreRef(trustapi.TruststoreFormatJKS, ""),
+			data:     map[string][]byte{"truststore": mustEncodeJKS(t, DefaultJKSPassword, dummy.TestCertificate1, dummy.TestCertificate3)},
+			expCerts: []string{dummy.TestCertificate1, dummy.TestCertificate3},
+		},
+		"JKS truststore with password from key": {
+			ref: truststoreRef(trustapi.TruststoreFormatJKS, "password"),
+			data: map[string][]byte{
+				"truststore": mustEncodeJKS(t, "hunter22", dummy.TestCertificate2),
+				"password":   []byte("hunter22"),
+			},
+			expCerts: []string{dummy.TestCertificate2},
+		},
+		"JKS truststore with wrong password": {
+			ref:      truststoreRef(trustapi.TruststoreFormatJKS, ""),
+			data:     map[string][]byte{"truststore": mustEncodeJKS(t, "hunter22", dummy.TestCertificate2)},
+			expError: true,
+		},
+		"PKCS12 truststore with empty password": {
+			ref:      truststoreRef(trustapi.TruststoreFormatPKCS12, ""),
+			data:     map[string][]byte{"truststore": mustEncodePKCS12(t, "", dummy.TestCertificate1, dummy.TestCertificate2)},
+			expCerts: []string{dummy.TestCertificate1, dummy.TestCertificate2},
+		},
+		"PKCS12 truststore with password from key": {
+			ref: truststoreRef(trustapi.TruststoreFormatPKCS12, "password"),
+			data: map[string][]byte{
+				"truststore": mustEncodePKCS12(t, "hunter22", dummy.TestCertificate4),
+				"password":   []byte("hunter22"),
+			},
+			expCerts: []string{dummy.TestCertificate4},
+		},
+		"PKCS12 truststore with missing password key": {
+			ref:              truststoreRef(trustapi.TruststoreFormatPKCS12, "password"),
+			data:             map[string][]byte{"truststore": mustEncodePKCS12(t, "hunter22", dummy.TestCertificate4)},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"missing truststore key": {
+			ref:              truststoreRef(trustapi.TruststoreFormatPKCS12, ""),
+			data:             map[string][]byte{},
+			expError:         true,
+			expNotFoundError: true,
+		},
+		"PEM data is not a valid truststore": {
+			ref:      truststoreRef(trustapi.TruststoreFormatPKCS12, ""),
+			data:     map[string][]byte{"truststore": []byte(dummy.TestCertificate1)},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeclient := fakeclient.NewClientBuilder().
+				WithRuntimeObjects([]runtime.Object{&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: "truststore"},
+					Data:       test.data,
+				}}...).
+				WithScheme(trustapi.GlobalScheme).
+				Build()
+
+			b := &bundle{sourceLister: fakeclient}
+
+			data, err := b.truststoreSecretBundle(context.TODO(), test.ref)
+			if (err != nil) != test.expError {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expError, err)
+			}
+			if errors.As(err, &notFoundError{}) != test.expNotFoundError {
+				t.Errorf("unexpected notFoundError, exp=%t got=%v", test.expNotFoundError, err)
+			}
+			if test.expError {
+				return
+			}
+
+			got, err := util.ValidateAndSplitPEMBundle([]byte(data))
+			if err != nil {
+				t.Fatalf("decoded truststore is not a valid PEM bundle: %s", err)
+			}
+
+			// JKS entries are ordered by alias rather than insertion order, so
+			// compare as a set of certificates.
+			gotSet := make(map[string]struct{})
+			for _, cert := range got {
+				gotSet[string(cert)] = struct{}{}
+			}
+
+			if len(gotSet) != len(test.expCerts) {
+				t.Fatalf("unexpected number of certificates, exp=%d got=%d", len(test.expCerts), len(gotSet))
+			}
+
+			for _, cert := range test.expCerts {
+				if _, ok := gotSet[cert+"\n"]; !ok {
+					t.Errorf("expected certificate not found in decoded truststore: %s", cert)
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package bundle
 
 import (
+	"sort"
+
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -42,6 +44,18 @@ func bundleHasCondition(bundle *trustapi.Bundle, condition trustapi.BundleCondit
 	return false
 }
 
+// bundleHasConditionType returns true if the bundle has a condition of the
+// given type, regardless of its status.
+func bundleHasConditionType(bundle *trustapi.Bundle, conditionType trustapi.BundleConditionType) bool {
+	for _, existingCondition := range bundle.Status.Conditions {
+		if existingCondition.Type == conditionType {
+			return true
+		}
+	}
+
+	return false
+}
+
 // setBundleCondition updates the bundle with the given condition.
 // Will overwrite any existing condition of the same type.
 // ObservedGeneration of the condition will be set to the Generation of the
@@ -95,3 +109,25 @@ func (b *bundle) setBundleStatusDefaultCAVersion(bundle *trustapi.Bundle, requir
 
 	return false
 }
+
+// setBundleStatusDefaultCAPackages ensures that the given Bundle's Status
+// correctly reflects the named default CA packages in requiredPackages, keyed
+// by package name.
+// Returns true if the bundle status needs updating.
+func (b *bundle) setBundleStatusDefaultCAPackages(bundle *trustapi.Bundle, requiredPackages map[string]trustapi.DefaultCAPackageStatus) bool {
+	var packages []trustapi.DefaultCAPackageStatus
+	for _, pkg := range requiredPackages {
+		packages = append(packages, pkg)
+	}
+
+	sort.Slice(packages, func(i, j int) bool {
+		return packages[i].Name < packages[j].Name
+	})
+
+	if apiequality.Semantic.DeepEqual(bundle.Status.DefaultCAPackages, packages) {
+		return false
+	}
+
+	bundle.Status.DefaultCAPackages = packages
+	return true
+}
@@ -312,3 +312,98 @@ func Test_setBundleStatusDefaultCAVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_setBundleStatusDefaultCAPackages(t *testing.T) {
+	tests := map[string]struct {
+		inputBundle               trustapi.Bundle
+		requiredPackages          map[string]trustapi.DefaultCAPackageStatus
+		expectedDefaultCAPackages []trustapi.DefaultCAPackageStatus
+		expectUpdate              bool
+	}{
+		"requiredPackages empty and status empty; should not update": {
+			inputBundle:               trustapi.Bundle{},
+			requiredPackages:          nil,
+			expectedDefaultCAPackages: nil,
+			expectUpdate:              false,
+		},
+		"requiredPackages empty but status populated; should update": {
+			inputBundle: trustapi.Bundle{
+				Status: trustapi.BundleStatus{
+					DefaultCAPackages: []trustapi.DefaultCAPackageStatus{{Name: "corp", Version: "abc123"}},
+				},
+			},
+			requiredPackages:          nil,
+			expectedDefaultCAPackages: nil,
+			expectUpdate:              true,
+		},
+		"requiredPackages not empty and status empty; should update sorted by name": {
+			inputBundle: trustapi.Bundle{},
+			requiredPackages: map[string]trustapi.DefaultCAPackageStatus{
+				"mozilla": {Name: "mozilla", Version: "def456"},
+				"corp":    {Name: "corp", Version: "abc123"},
+			},
+			expectedDefaultCAPackages: []trustapi.DefaultCAPackageStatus{
+				{Name: "corp", Version: "abc123"},
+				{Name: "mozilla", Version: "def456"},
+			},
+			expectUpdate: true,
+		},
+		"requiredPackages not empty and status populated but incorrect; should update": {
+			inputBundle: trustapi.Bundle{
+				Status: trustapi.BundleStatus{
+					DefaultCAPackages: []trustapi.DefaultCAPackageStatus{{Name: "corp", Version: "def456"}},
+				},
+			},
+			requiredPackages:          map[string]trustapi.DefaultCAPackageStatus{"corp": {Name: "corp", Version: "abc123"}},
+			expectedDefaultCAPackages: []trustapi.DefaultCAPackageStatus{{Name: "corp", Version: "abc123"}},
+			expectUpdate:              true,
+		},
+		"requiredPackages selected as fallback and status not marking fallback; should update": {
+			inputBundle: trustapi.Bundle{
+				Status: trustapi.BundleStatus{
+					DefaultCAPackages: []trustapi.DefaultCAPackageStatus{{Name: "mozilla", Version: "def456"}},
+				},
+			},
+			requiredPackages:          map[string]trustapi.DefaultCAPackageStatus{"mozilla": {Name: "mozilla", Version: "def456", Fallback: true}},
+			expectedDefaultCAPackages: []trustapi.DefaultCAPackageStatus{{Name: "mozilla", Version: "def456", Fallback: true}},
+			expectUpdate:              true,
+		},
+		"requiredPackages not empty and status populated correctly; should not update": {
+			inputBundle: trustapi.Bundle{
+				Status: trustapi.BundleStatus{
+					DefaultCAPackages: []trustapi.DefaultCAPackageStatus{
+						{Name: "corp", Version: "abc123"},
+						{Name: "mozilla", Version: "def456"},
+					},
+				},
+			},
+			requiredPackages: map[string]trustapi.DefaultCAPackageStatus{
+				"mozilla": {Name: "mozilla", Version: "def456"},
+				"corp":    {Name: "corp", Version: "abc123"},
+			},
+			expectedDefaultCAPackages: []trustapi.DefaultCAPackageStatus{
+				{Name: "corp", Version: "abc123"},
+				{Name: "mozilla", Version: "def456"},
+			},
+			expectUpdate: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bundle{}
+
+			shouldUpdate := b.setBundleStatusDefaultCAPackages(&test.inputBundle, test.requiredPackages)
+
+			if shouldUpdate != test.expectUpdate {
+				t.Errorf("expected shouldUpdate=%v got=%v", test.expectUpdate, shouldUpdate)
+			}
+
+			finalPackages := test.inputBundle.Status.DefaultCAPackages
+
+			if !apiequality.Semantic.DeepEqual(finalPackages, test.expectedDefaultCAPackages) {
+				t.Errorf("expected DefaultCAPackages=%v, got=%v", test.expectedDefaultCAPackages, finalPackages)
+			}
+		})
+	}
+}
@@ -0,0 +1,382 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package bundlecheck implements a controller which continuously evaluates
+// the expectations declared by BundleChecks against the content rendered by
+// their Bundle to its target, and reports the results as conditions.
+package bundlecheck
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// recheckPeriod is the period after which a BundleCheck is evaluated again,
+// so that changes to the target which aren't made by the Bundle controller
+// are detected.
+const recheckPeriod = 5 * time.Minute
+
+// AddController registers the BundleCheck controller with the given Manager.
+// BundleChecks are evaluated again whenever their Bundle changes. Targets are
+// read directly from the API server, so that target ConfigMaps in all
+// Namespaces aren't cached. Targets are found using the given naming
+// conventions, which may be nil to use the defaults.
+func AddController(ctx context.Context, mgr manager.Manager, log logr.Logger, conventions *naming.Conventions) error {
+	r := &reconciler{
+		client:       mgr.GetClient(),
+		targetReader: mgr.GetAPIReader(),
+		recorder:     mgr.GetEventRecorderFor("bundlechecks"),
+		clock:        clock.RealClock{},
+		naming:       conventions,
+		log:          log,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("bundlechecks").
+		For(new(trustapi.BundleCheck)).
+
+		// Reconcile the BundleChecks of a modified Bundle.
+		Watches(&source.Kind{Type: new(trustapi.Bundle)}, handler.EnqueueRequestsFromMapFunc(
+			func(obj client.Object) []reconcile.Request {
+				var checkList trustapi.BundleCheckList
+				if err := r.client.List(ctx, &checkList); err != nil {
+					r.log.Error(err, "failed to list BundleChecks")
+					return nil
+				}
+
+				var requests []reconcile.Request
+				for _, check := range checkList.Items {
+					if check.Spec.BundleName == obj.GetName() {
+						requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: check.Name}})
+					}
+				}
+
+				return requests
+			},
+		)).
+		Complete(r)
+}
+
+// reconciler evaluates BundleChecks.
+type reconciler struct {
+	client       client.Client
+	targetReader client.Reader
+	recorder     record.EventRecorder
+	clock        clock.Clock
+	naming       *naming.Conventions
+	log          logr.Logger
+}
+
+// Reconcile evaluates the expectations of a BundleCheck against the target of
+// its Bundle in the BundleCheck's Namespace, and writes the results to its
+// status. The check is evaluated again after the recheck period, or when the
+// next certificate in the target expires if that is sooner.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("bundlecheck", req.Name)
+
+	var check trustapi.BundleCheck
+	if err := r.client.Get(ctx, req.NamespacedName, &check); apierrors.IsNotFound(err) {
+		log.V(2).Info("BundleCheck no longer exists, ignoring")
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get BundleCheck %q: %w", req.Name, err)
+	}
+
+	now := r.clock.Now()
+
+	data, reason, message, err := r.targetData(ctx, &check)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var conditions []trustapi.BundleCheckCondition
+	requeueAfter := recheckPeriod
+	if len(reason) > 0 {
+		conditions = unknownConditions(&check, reason, message)
+	} else {
+		var nextExpiry *time.Time
+		conditions, nextExpiry = evaluate(&check, data, now)
+		if nextExpiry != nil && nextExpiry.Sub(now) < requeueAfter {
+			requeueAfter = nextExpiry.Sub(now)
+		}
+	}
+
+	// Only warn when the check starts failing, rather than on every
+	// evaluation.
+	wasFailing := checkHasConditionStatus(&check, trustapi.BundleCheckConditionPassed, corev1.ConditionFalse)
+	setCheckConditions(&check, conditions, now)
+	check.Status.LastCheckTime = &metav1.Time{Time: now}
+
+	passed := conditions[0]
+	if passed.Status == corev1.ConditionFalse && !wasFailing {
+		r.recorder.Eventf(&check, corev1.EventTypeWarning, passed.Reason, "Bundle %q failed check: %s", check.Spec.BundleName, passed.Message)
+	}
+
+	log.V(2).Info("evaluated BundleCheck", "passed", passed.Status == corev1.ConditionTrue)
+
+	if err := r.client.Status().Update(ctx, &check); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update BundleCheck %q status: %w", check.Name, err)
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// targetData returns the data of the BundleCheck's Bundle target in the
+// BundleCheck's Namespace. If the target can't be checked, a reason and
+// message describing why are returned instead.
+func (r *reconciler) targetData(ctx context.Context, check *trustapi.BundleCheck) (string, string, string, error) {
+	var bundle trustapi.Bundle
+	if err := r.client.Get(ctx, client.ObjectKey{Name: check.Spec.BundleName}, &bundle); apierrors.IsNotFound(err) {
+		return "", "BundleNotFound", fmt.Sprintf("Bundle %q does not exist", check.Spec.BundleName), nil
+	} else if err != nil {
+		return "", "", "", fmt.Errorf("failed to get Bundle %q: %w", check.Spec.BundleName, err)
+	}
+
+	if bundle.Spec.Target.ConfigMap == nil {
+		return "", "NoTarget", fmt.Sprintf("Bundle %q has no ConfigMap target", bundle.Name), nil
+	}
+
+	targetName, err := r.naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var configMap corev1.ConfigMap
+	key := client.ObjectKey{Namespace: check.Spec.Namespace, Name: targetName}
+	if err := r.targetReader.Get(ctx, key, &configMap); apierrors.IsNotFound(err) {
+		return "", "TargetNotFound", fmt.Sprintf("target ConfigMap %s does not exist", key), nil
+	} else if err != nil {
+		return "", "", "", fmt.Errorf("failed to get target ConfigMap %s: %w", key, err)
+	}
+
+	data, ok := util.TargetData(&configMap, bundle.Spec.Target.ConfigMap.Key, bundle.Spec.Target.ConfigMap.Format)
+	if !ok {
+		return "", "TargetNotFound", fmt.Sprintf("target ConfigMap %s has no key %q", key, bundle.Spec.Target.ConfigMap.Key), nil
+	}
+
+	return data, "", "", nil
+}
+
+// evaluate returns the conditions of the given BundleCheck's expectations for
+// the given target data, along with the Passed condition summarising them.
+// The time at which the next certificate in the data expires is returned, so
+// that the check can be evaluated again once it has expired.
+func evaluate(check *trustapi.BundleCheck, data string, now time.Time) ([]trustapi.BundleCheckCondition, *time.Time) {
+	var certificates []*x509.Certificate
+	fingerprints := make(map[string]bool)
+
+	blocks, err := util.ValidateAndSplitPEMBundle([]byte(data))
+	if err != nil {
+		return unknownConditions(check, "InvalidTarget", fmt.Sprintf("target contains invalid certificates: %s", err)), nil
+	}
+	if len(blocks) == 0 {
+		return unknownConditions(check, "InvalidTarget", "target contains no certificates"), nil
+	}
+	for _, block := range blocks {
+		decoded, _ := pem.Decode(block)
+		cert, err := x509.ParseCertificate(decoded.Bytes)
+		if err != nil {
+			return unknownConditions(check, "InvalidTarget", fmt.Sprintf("failed to parse certificate in target: %s", err)), nil
+		}
+		certificates = append(certificates, cert)
+
+		hash := sha256.Sum256(cert.Raw)
+		fingerprints[hex.EncodeToString(hash[:])] = true
+	}
+
+	expectations := check.Spec.Expectations
+
+	var conditions []trustapi.BundleCheckCondition
+	var failed []string
+
+	if len(expectations.ContainsFingerprints) > 0 {
+		var missing []string
+		for _, fingerprint := range expectations.ContainsFingerprints {
+			parsed, err := util.ParseFingerprint(fingerprint)
+			if err != nil || !fingerprints[parsed] {
+				missing = append(missing, fingerprint)
+			}
+		}
+
+		condition := trustapi.BundleCheckCondition{
+			Type:    trustapi.BundleCheckConditionContainsFingerprints,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ExpectationMet",
+			Message: fmt.Sprintf("target contains all %d expected certificates", len(expectations.ContainsFingerprints)),
+		}
+		if len(missing) > 0 {
+			condition.Status = corev1.ConditionFalse
+			condition.Reason = "ExpectationNotMet"
+			condition.Message = fmt.Sprintf("target does not contain the certificates with fingerprints %s", strings.Join(missing, ", "))
+			failed = append(failed, condition.Message)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	var nextExpiry *time.Time
+	if expectations.NoExpiredCertificates {
+		var expired int
+		for _, cert := range certificates {
+			if !now.Before(cert.NotAfter) {
+				expired++
+				continue
+			}
+			if nextExpiry == nil || cert.NotAfter.Before(*nextExpiry) {
+				notAfter := cert.NotAfter
+				nextExpiry = &notAfter
+			}
+		}
+
+		condition := trustapi.BundleCheckCondition{
+			Type:    trustapi.BundleCheckConditionNoExpiredCertificates,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ExpectationMet",
+			Message: "target contains no expired certificates",
+		}
+		if expired > 0 {
+			condition.Status = corev1.ConditionFalse
+			condition.Reason = "ExpectationNotMet"
+			condition.Message = fmt.Sprintf("target contains %d expired certificates", expired)
+			failed = append(failed, condition.Message)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	if maxCertificates := expectations.MaxCertificates; maxCertificates != nil {
+		condition := trustapi.BundleCheckCondition{
+			Type:    trustapi.BundleCheckConditionMaxCertificates,
+			Status:  corev1.ConditionTrue,
+			Reason:  "ExpectationMet",
+			Message: fmt.Sprintf("target contains %d certificates, at most %d are expected", len(certificates), *maxCertificates),
+		}
+		if len(certificates) > int(*maxCertificates) {
+			condition.Status = corev1.ConditionFalse
+			condition.Reason = "ExpectationNotMet"
+			failed = append(failed, condition.Message)
+		}
+		conditions = append(conditions, condition)
+	}
+
+	passed := trustapi.BundleCheckCondition{
+		Type:    trustapi.BundleCheckConditionPassed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "ExpectationsMet",
+		Message: "target meets all expectations",
+	}
+	if len(failed) > 0 {
+		passed.Status = corev1.ConditionFalse
+		passed.Reason = "ExpectationsNotMet"
+		passed.Message = strings.Join(failed, "; ")
+	}
+
+	return append([]trustapi.BundleCheckCondition{passed}, conditions...), nextExpiry
+}
+
+// unknownConditions returns the conditions of the given BundleCheck's
+// expectations when its target can't be checked, with the given reason and
+// message. The Passed condition is False, since the target may be missing.
+func unknownConditions(check *trustapi.BundleCheck, reason, message string) []trustapi.BundleCheckCondition {
+	conditions := []trustapi.BundleCheckCondition{{
+		Type:    trustapi.BundleCheckConditionPassed,
+		Status:  corev1.ConditionFalse,
+		Reason:  reason,
+		Message: message,
+	}}
+
+	expectations := check.Spec.Expectations
+	for _, conditionType := range []struct {
+		set bool
+		typ trustapi.BundleCheckConditionType
+	}{
+		{len(expectations.ContainsFingerprints) > 0, trustapi.BundleCheckConditionContainsFingerprints},
+		{expectations.NoExpiredCertificates, trustapi.BundleCheckConditionNoExpiredCertificates},
+		{expectations.MaxCertificates != nil, trustapi.BundleCheckConditionMaxCertificates},
+	} {
+		if conditionType.set {
+			conditions = append(conditions, trustapi.BundleCheckCondition{
+				Type:    conditionType.typ,
+				Status:  corev1.ConditionUnknown,
+				Reason:  reason,
+				Message: message,
+			})
+		}
+	}
+
+	return conditions
+}
+
+// checkHasConditionStatus returns true if the BundleCheck has a condition of
+// the given type with the given status.
+func checkHasConditionStatus(check *trustapi.BundleCheck, conditionType trustapi.BundleCheckConditionType, status corev1.ConditionStatus) bool {
+	for _, condition := range check.Status.Conditions {
+		if condition.Type == conditionType {
+			return condition.Status == status
+		}
+	}
+
+	return false
+}
+
+// setCheckConditions replaces the conditions of the BundleCheck with the given
+// conditions, so that conditions of expectations which are no longer set are
+// removed. ObservedGeneration of the conditions will be set to the Generation
+// of the BundleCheck. LastTransitionTime will not be updated if an existing
+// condition of the same Type and Status already exists.
+func setCheckConditions(check *trustapi.BundleCheck, conditions []trustapi.BundleCheckCondition, now time.Time) {
+	existing := make(map[trustapi.BundleCheckConditionType]trustapi.BundleCheckCondition, len(check.Status.Conditions))
+	for _, condition := range check.Status.Conditions {
+		existing[condition.Type] = condition
+	}
+
+	updated := make([]trustapi.BundleCheckCondition, len(conditions))
+	for i, condition := range conditions {
+		condition.LastTransitionTime = &metav1.Time{Time: now}
+		condition.ObservedGeneration = check.Generation
+
+		// If the status is the same, don't modify the last transition time.
+		if existingCondition, ok := existing[condition.Type]; ok && existingCondition.Status == condition.Status {
+			condition.LastTransitionTime = existingCondition.LastTransitionTime
+		}
+
+		updated[i] = condition
+	}
+
+	check.Status.Conditions = updated
+}
@@ -0,0 +1,283 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundlecheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	fakeclock "k8s.io/utils/clock/testing"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+const (
+	testCertificate1Fingerprint = "548b988f4bad7bdd0d3b7523de37154ee47f285eee36d3b1f53faa2720fca307"
+	testCertificate3Fingerprint = "96BC:EC:06:26:49:76:F3:74:60:77:9A:CF:28:C5:A7:CF:E8:A3:C0:AA:E1:1A:8F:FC:EE:05:C0:BD:DF:08:C6"
+)
+
+func Test_evaluate(t *testing.T) {
+	// TestCertificate1 and TestCertificate2 expire in November and December
+	// 2032, and TestCertificate3 in June 2035.
+	beforeExpiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	afterExpiry := time.Date(2033, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstExpiry := time.Date(2032, 11, 22, 13, 3, 54, 0, time.UTC)
+	lastExpiry := time.Date(2035, 6, 4, 11, 4, 38, 0, time.UTC)
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)
+
+	met := func(conditionType trustapi.BundleCheckConditionType, message string) trustapi.BundleCheckCondition {
+		return trustapi.BundleCheckCondition{Type: conditionType, Status: corev1.ConditionTrue, Reason: "ExpectationMet", Message: message}
+	}
+	notMet := func(conditionType trustapi.BundleCheckConditionType, message string) trustapi.BundleCheckCondition {
+		return trustapi.BundleCheckCondition{Type: conditionType, Status: corev1.ConditionFalse, Reason: "ExpectationNotMet", Message: message}
+	}
+	passed := trustapi.BundleCheckCondition{
+		Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionTrue, Reason: "ExpectationsMet", Message: "target meets all expectations",
+	}
+	failed := func(message string) trustapi.BundleCheckCondition {
+		return trustapi.BundleCheckCondition{Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionFalse, Reason: "ExpectationsNotMet", Message: message}
+	}
+
+	tests := map[string]struct {
+		expectations trustapi.BundleCheckExpectations
+		data         string
+		now          time.Time
+
+		expConditions []trustapi.BundleCheckCondition
+		expNextExpiry *time.Time
+	}{
+		"no expectations should pass": {
+			data:          data,
+			now:           beforeExpiry,
+			expConditions: []trustapi.BundleCheckCondition{passed},
+		},
+		"all expectations met should pass": {
+			expectations: trustapi.BundleCheckExpectations{
+				ContainsFingerprints:  []string{testCertificate1Fingerprint, testCertificate3Fingerprint},
+				NoExpiredCertificates: true,
+				MaxCertificates:       pointer.Int32(3),
+			},
+			data: data,
+			now:  beforeExpiry,
+			expConditions: []trustapi.BundleCheckCondition{
+				passed,
+				met(trustapi.BundleCheckConditionContainsFingerprints, "target contains all 2 expected certificates"),
+				met(trustapi.BundleCheckConditionNoExpiredCertificates, "target contains no expired certificates"),
+				met(trustapi.BundleCheckConditionMaxCertificates, "target contains 3 certificates, at most 3 are expected"),
+			},
+			expNextExpiry: &firstExpiry,
+		},
+		"missing certificate should fail": {
+			expectations: trustapi.BundleCheckExpectations{
+				ContainsFingerprints: []string{testCertificate1Fingerprint, testCertificate3Fingerprint},
+			},
+			data: dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			now:  beforeExpiry,
+			expConditions: []trustapi.BundleCheckCondition{
+				failed("target does not contain the certificates with fingerprints " + testCertificate3Fingerprint),
+				notMet(trustapi.BundleCheckConditionContainsFingerprints, "target does not contain the certificates with fingerprints "+testCertificate3Fingerprint),
+			},
+		},
+		"expired certificates should fail": {
+			expectations: trustapi.BundleCheckExpectations{NoExpiredCertificates: true},
+			data:         data,
+			now:          afterExpiry,
+			expConditions: []trustapi.BundleCheckCondition{
+				failed("target contains 2 expired certificates"),
+				notMet(trustapi.BundleCheckConditionNoExpiredCertificates, "target contains 2 expired certificates"),
+			},
+			expNextExpiry: &lastExpiry,
+		},
+		"too many certificates should fail": {
+			expectations: trustapi.BundleCheckExpectations{MaxCertificates: pointer.Int32(2)},
+			data:         data,
+			now:          beforeExpiry,
+			expConditions: []trustapi.BundleCheckCondition{
+				failed("target contains 3 certificates, at most 2 are expected"),
+				notMet(trustapi.BundleCheckConditionMaxCertificates, "target contains 3 certificates, at most 2 are expected"),
+			},
+		},
+		"target without certificates should be unknown": {
+			expectations: trustapi.BundleCheckExpectations{MaxCertificates: pointer.Int32(2)},
+			data:         "not a certificate",
+			now:          beforeExpiry,
+			expConditions: []trustapi.BundleCheckCondition{
+				{Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionFalse, Reason: "InvalidTarget", Message: "target contains no certificates"},
+				{Type: trustapi.BundleCheckConditionMaxCertificates, Status: corev1.ConditionUnknown, Reason: "InvalidTarget", Message: "target contains no certificates"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			check := &trustapi.BundleCheck{Spec: trustapi.BundleCheckSpec{Expectations: test.expectations}}
+
+			conditions, nextExpiry := evaluate(check, test.data, test.now)
+			assert.Equal(t, test.expConditions, conditions)
+			if test.expNextExpiry == nil {
+				assert.Nil(t, nextExpiry)
+			} else if assert.NotNil(t, nextExpiry) {
+				assert.True(t, test.expNextExpiry.Equal(*nextExpiry), "expected next expiry %s, got %s", test.expNextExpiry, nextExpiry)
+			}
+		})
+	}
+}
+
+func Test_Reconcile(t *testing.T) {
+	const namespace = "app-namespace"
+
+	now := time.Date(2030, 1, 1, 0, 0, 0, 0, time.Local)
+	earlier := metav1.NewTime(now.Add(-time.Hour))
+
+	bundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-bundle"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+		},
+	}
+	target := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-bundle", Namespace: namespace},
+		Data:       map[string]string{"ca.crt": dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)},
+	}
+	check := func(conditions ...trustapi.BundleCheckCondition) *trustapi.BundleCheck {
+		return &trustapi.BundleCheck{
+			ObjectMeta: metav1.ObjectMeta{Name: "check", Generation: 2},
+			Spec: trustapi.BundleCheckSpec{
+				BundleName:   "trust-bundle",
+				Namespace:    namespace,
+				Expectations: trustapi.BundleCheckExpectations{MaxCertificates: pointer.Int32(2)},
+			},
+			Status: trustapi.BundleCheckStatus{Conditions: conditions},
+		}
+	}
+
+	tests := map[string]struct {
+		objects []runtime.Object
+
+		expConditions []trustapi.BundleCheckCondition
+		expEvent      bool
+	}{
+		"passing check should report conditions": {
+			objects: []runtime.Object{bundle, target, check()},
+			expConditions: []trustapi.BundleCheckCondition{
+				{
+					Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionTrue, Reason: "ExpectationsMet",
+					Message:            "target meets all expectations",
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+				{
+					Type: trustapi.BundleCheckConditionMaxCertificates, Status: corev1.ConditionTrue, Reason: "ExpectationMet",
+					Message:            "target contains 2 certificates, at most 2 are expected",
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+			},
+		},
+		"unchanged status should keep the last transition time": {
+			objects: []runtime.Object{bundle, target, check(
+				trustapi.BundleCheckCondition{Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionTrue, LastTransitionTime: &earlier},
+				trustapi.BundleCheckCondition{Type: trustapi.BundleCheckConditionContainsFingerprints, Status: corev1.ConditionTrue, LastTransitionTime: &earlier},
+			)},
+			expConditions: []trustapi.BundleCheckCondition{
+				{
+					Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionTrue, Reason: "ExpectationsMet",
+					Message:            "target meets all expectations",
+					LastTransitionTime: &earlier, ObservedGeneration: 2,
+				},
+				{
+					Type: trustapi.BundleCheckConditionMaxCertificates, Status: corev1.ConditionTrue, Reason: "ExpectationMet",
+					Message:            "target contains 2 certificates, at most 2 are expected",
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+			},
+		},
+		"missing target should fail with an event": {
+			objects: []runtime.Object{bundle, check()},
+			expConditions: []trustapi.BundleCheckCondition{
+				{
+					Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionFalse, Reason: "TargetNotFound",
+					Message:            "target ConfigMap app-namespace/trust-bundle does not exist",
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+				{
+					Type: trustapi.BundleCheckConditionMaxCertificates, Status: corev1.ConditionUnknown, Reason: "TargetNotFound",
+					Message:            "target ConfigMap app-namespace/trust-bundle does not exist",
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+			},
+			expEvent: true,
+		},
+		"missing Bundle should fail without an event if already failing": {
+			objects: []runtime.Object{check(
+				trustapi.BundleCheckCondition{Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionFalse, LastTransitionTime: &earlier},
+			)},
+			expConditions: []trustapi.BundleCheckCondition{
+				{
+					Type: trustapi.BundleCheckConditionPassed, Status: corev1.ConditionFalse, Reason: "BundleNotFound",
+					Message:            `Bundle "trust-bundle" does not exist`,
+					LastTransitionTime: &earlier, ObservedGeneration: 2,
+				},
+				{
+					Type: trustapi.BundleCheckConditionMaxCertificates, Status: corev1.ConditionUnknown, Reason: "BundleNotFound",
+					Message:            `Bundle "trust-bundle" does not exist`,
+					LastTransitionTime: &metav1.Time{Time: now}, ObservedGeneration: 2,
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(test.objects...).
+				Build()
+			recorder := record.NewFakeRecorder(1)
+
+			r := &reconciler{
+				client:       fakeClient,
+				targetReader: fakeClient,
+				recorder:     recorder,
+				clock:        fakeclock.NewFakeClock(now),
+				log:          logr.Discard(),
+			}
+
+			result, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "check"}})
+			assert.NoError(t, err)
+			assert.Equal(t, ctrl.Result{RequeueAfter: recheckPeriod}, result)
+
+			var check trustapi.BundleCheck
+			assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "check"}, &check))
+			assert.Equal(t, test.expConditions, check.Status.Conditions)
+			assert.Equal(t, &metav1.Time{Time: now}, check.Status.LastCheckTime)
+
+			assert.Equal(t, test.expEvent, len(recorder.Events) == 1)
+		})
+	}
+}
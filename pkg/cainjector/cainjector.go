@@ -0,0 +1,297 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cainjector implements controllers which write the data of Bundles
+// into the caBundle fields of webhook configurations, CustomResourceDefinition
+// conversion webhooks and APIServices annotated with the name of a Bundle,
+// like the cert-manager cainjector does for Certificates.
+package cainjector
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// injectable is a kind of object whose caBundle fields can be injected.
+type injectable struct {
+	// name is the name of the controller injecting objects of the kind.
+	name string
+
+	gvk schema.GroupVersionKind
+
+	// inject sets the caBundle fields of the object to the given base64
+	// encoded bundle data. Returns true if the object was changed.
+	inject func(obj *unstructured.Unstructured, caBundle string) (bool, error)
+}
+
+// injectables are the kinds of objects whose caBundle fields are injected.
+var injectables = []injectable{
+	{
+		name:   "validatingwebhookconfigurations",
+		gvk:    schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "ValidatingWebhookConfiguration"},
+		inject: injectWebhooks,
+	},
+	{
+		name:   "mutatingwebhookconfigurations",
+		gvk:    schema.GroupVersionKind{Group: "admissionregistration.k8s.io", Version: "v1", Kind: "MutatingWebhookConfiguration"},
+		inject: injectWebhooks,
+	},
+	{
+		name:   "customresourcedefinitions",
+		gvk:    schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"},
+		inject: injectConversionWebhook,
+	},
+	{
+		name:   "apiservices",
+		gvk:    schema.GroupVersionKind{Group: "apiregistration.k8s.io", Version: "v1", Kind: "APIService"},
+		inject: injectAPIService,
+	},
+}
+
+// AddControllers registers a CA injector controller for each injectable kind
+// with the given Manager. Objects are injected again whenever the Bundle they
+// reference changes. The data of a Bundle is read from its target in the
+// given trust Namespace, found using the given naming conventions, which may
+// be nil to use the defaults.
+func AddControllers(ctx context.Context, mgr manager.Manager, log logr.Logger, namespace string, conventions *naming.Conventions) error {
+	for _, kind := range injectables {
+		kind := kind
+		r := &reconciler{
+			client:       mgr.GetClient(),
+			targetReader: mgr.GetAPIReader(),
+			recorder:     mgr.GetEventRecorderFor("cainjector"),
+			naming:       conventions,
+			namespace:    namespace,
+			log:          log.WithName(kind.name),
+			injectable:   kind,
+		}
+
+		obj := new(unstructured.Unstructured)
+		obj.SetGroupVersionKind(kind.gvk)
+
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named("cainjector-"+kind.name).
+			For(obj).
+
+			// Reconcile the objects referencing a modified Bundle.
+			Watches(&source.Kind{Type: new(trustapi.Bundle)}, handler.EnqueueRequestsFromMapFunc(
+				func(bundle client.Object) []reconcile.Request {
+					list := new(unstructured.UnstructuredList)
+					list.SetGroupVersionKind(kind.gvk.GroupVersion().WithKind(kind.gvk.Kind + "List"))
+					if err := r.client.List(ctx, list); err != nil {
+						r.log.Error(err, "failed to list objects to inject")
+						return nil
+					}
+
+					var requests []reconcile.Request
+					for _, item := range list.Items {
+						if item.GetAnnotations()[trustapi.CAInjectionAnnotationKey] == bundle.GetName() {
+							requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: item.GetName()}})
+						}
+					}
+
+					return requests
+				},
+			)).
+			Complete(r)
+		if err != nil {
+			return fmt.Errorf("failed to register CA injector for %s: %w", kind.name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconciler injects the data of Bundles into objects of a single kind.
+type reconciler struct {
+	client       client.Client
+	targetReader client.Reader
+	recorder     record.EventRecorder
+	naming       *naming.Conventions
+	namespace    string
+	log          logr.Logger
+
+	injectable
+}
+
+// Reconcile writes the data of the Bundle referenced by the object's
+// annotation to the object's caBundle fields.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("name", req.Name)
+
+	obj := new(unstructured.Unstructured)
+	obj.SetGroupVersionKind(r.gvk)
+	if err := r.client.Get(ctx, req.NamespacedName, obj); apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get %s %q: %w", r.gvk.Kind, req.Name, err)
+	}
+
+	bundleName := obj.GetAnnotations()[trustapi.CAInjectionAnnotationKey]
+	if len(bundleName) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	data, reason, message, err := r.bundleData(ctx, bundleName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(reason) > 0 {
+		// The object is injected once the Bundle changes.
+		log.V(2).Info("unable to inject Bundle", "bundle", bundleName, "reason", message)
+		r.recorder.Eventf(obj, corev1.EventTypeWarning, reason, message)
+		return ctrl.Result{}, nil
+	}
+
+	changed, err := r.inject(obj, base64.StdEncoding.EncodeToString([]byte(data)))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to inject %s %q: %w", r.gvk.Kind, req.Name, err)
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.client.Update(ctx, obj); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update %s %q: %w", r.gvk.Kind, req.Name, err)
+	}
+
+	log.V(2).Info("injected Bundle", "bundle", bundleName)
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, "CAInjected", "Injected the data of Bundle %q", bundleName)
+
+	return ctrl.Result{}, nil
+}
+
+// bundleData returns the data of the named Bundle's target in the trust
+// Namespace. If the data can't be read, a reason and message describing why
+// are returned instead.
+func (r *reconciler) bundleData(ctx context.Context, bundleName string) (string, string, string, error) {
+	var bundle trustapi.Bundle
+	if err := r.client.Get(ctx, client.ObjectKey{Name: bundleName}, &bundle); apierrors.IsNotFound(err) {
+		return "", "BundleNotFound", fmt.Sprintf("Bundle %q does not exist", bundleName), nil
+	} else if err != nil {
+		return "", "", "", fmt.Errorf("failed to get Bundle %q: %w", bundleName, err)
+	}
+
+	if bundle.Spec.Target.ConfigMap == nil {
+		return "", "NoTarget", fmt.Sprintf("Bundle %q has no ConfigMap target", bundle.Name), nil
+	}
+
+	targetName, err := r.naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var configMap corev1.ConfigMap
+	key := client.ObjectKey{Namespace: r.namespace, Name: targetName}
+	if err := r.targetReader.Get(ctx, key, &configMap); apierrors.IsNotFound(err) {
+		return "", "TargetNotFound", fmt.Sprintf("Bundle %q is not synced to the trust namespace", bundle.Name), nil
+	} else if err != nil {
+		return "", "", "", fmt.Errorf("failed to get target ConfigMap %s: %w", key, err)
+	}
+
+	data, ok := util.TargetData(&configMap, bundle.Spec.Target.ConfigMap.Key, bundle.Spec.Target.ConfigMap.Format)
+	if !ok {
+		return "", "TargetNotFound", fmt.Sprintf("Bundle %q is not synced to the trust namespace", bundle.Name), nil
+	}
+
+	return data, "", "", nil
+}
+
+// injectWebhooks sets the caBundle of the client config of every webhook of
+// a ValidatingWebhookConfiguration or MutatingWebhookConfiguration.
+func injectWebhooks(obj *unstructured.Unstructured, caBundle string) (bool, error) {
+	webhooks, _, err := unstructured.NestedSlice(obj.Object, "webhooks")
+	if err != nil {
+		return false, err
+	}
+
+	var changed bool
+	for i, webhook := range webhooks {
+		webhook, ok := webhook.(map[string]any)
+		if !ok {
+			return false, fmt.Errorf("webhook %d is not an object", i)
+		}
+
+		current, _, err := unstructured.NestedString(webhook, "clientConfig", "caBundle")
+		if err != nil {
+			return false, err
+		}
+		if current == caBundle {
+			continue
+		}
+
+		if err := unstructured.SetNestedField(webhook, caBundle, "clientConfig", "caBundle"); err != nil {
+			return false, err
+		}
+		webhooks[i] = webhook
+		changed = true
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	return true, unstructured.SetNestedSlice(obj.Object, webhooks, "webhooks")
+}
+
+// injectConversionWebhook sets the caBundle of the conversion webhook of a
+// CustomResourceDefinition. CustomResourceDefinitions which don't use webhook
+// conversion are left unchanged.
+func injectConversionWebhook(obj *unstructured.Unstructured, caBundle string) (bool, error) {
+	strategy, _, err := unstructured.NestedString(obj.Object, "spec", "conversion", "strategy")
+	if err != nil || strategy != "Webhook" {
+		return false, err
+	}
+
+	return injectField(obj, caBundle, "spec", "conversion", "webhook", "clientConfig", "caBundle")
+}
+
+// injectAPIService sets the caBundle of an APIService.
+func injectAPIService(obj *unstructured.Unstructured, caBundle string) (bool, error) {
+	return injectField(obj, caBundle, "spec", "caBundle")
+}
+
+// injectField sets the string field at the given path to the caBundle.
+func injectField(obj *unstructured.Unstructured, caBundle string, fields ...string) (bool, error) {
+	current, _, err := unstructured.NestedString(obj.Object, fields...)
+	if err != nil {
+		return false, err
+	}
+	if current == caBundle {
+		return false, nil
+	}
+
+	return true, unstructured.SetNestedField(obj.Object, caBundle, fields...)
+}
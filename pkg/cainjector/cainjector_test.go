@@ -0,0 +1,200 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cainjector
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_inject(t *testing.T) {
+	const caBundle = "Y2EtYnVuZGxl"
+
+	tests := map[string]struct {
+		inject func(*unstructured.Unstructured, string) (bool, error)
+		object map[string]any
+
+		expObject  map[string]any
+		expChanged bool
+	}{
+		"webhooks should all be injected": {
+			inject: injectWebhooks,
+			object: map[string]any{"webhooks": []any{
+				map[string]any{"name": "a", "clientConfig": map[string]any{"caBundle": "b2xk"}},
+				map[string]any{"name": "b", "clientConfig": map[string]any{"service": map[string]any{"name": "svc"}}},
+			}},
+			expObject: map[string]any{"webhooks": []any{
+				map[string]any{"name": "a", "clientConfig": map[string]any{"caBundle": caBundle}},
+				map[string]any{"name": "b", "clientConfig": map[string]any{"service": map[string]any{"name": "svc"}, "caBundle": caBundle}},
+			}},
+			expChanged: true,
+		},
+		"injected webhooks should not change": {
+			inject: injectWebhooks,
+			object: map[string]any{"webhooks": []any{
+				map[string]any{"name": "a", "clientConfig": map[string]any{"caBundle": caBundle}},
+			}},
+			expObject: map[string]any{"webhooks": []any{
+				map[string]any{"name": "a", "clientConfig": map[string]any{"caBundle": caBundle}},
+			}},
+		},
+		"configuration without webhooks should not change": {
+			inject:    injectWebhooks,
+			object:    map[string]any{},
+			expObject: map[string]any{},
+		},
+		"CRD conversion webhook should be injected": {
+			inject: injectConversionWebhook,
+			object: map[string]any{"spec": map[string]any{"conversion": map[string]any{"strategy": "Webhook"}}},
+			expObject: map[string]any{"spec": map[string]any{"conversion": map[string]any{
+				"strategy": "Webhook",
+				"webhook":  map[string]any{"clientConfig": map[string]any{"caBundle": caBundle}},
+			}}},
+			expChanged: true,
+		},
+		"CRD without conversion webhook should not change": {
+			inject:    injectConversionWebhook,
+			object:    map[string]any{"spec": map[string]any{"conversion": map[string]any{"strategy": "None"}}},
+			expObject: map[string]any{"spec": map[string]any{"conversion": map[string]any{"strategy": "None"}}},
+		},
+		"APIService should be injected": {
+			inject:     injectAPIService,
+			object:     map[string]any{"spec": map[string]any{"service": map[string]any{"name": "svc"}}},
+			expObject:  map[string]any{"spec": map[string]any{"service": map[string]any{"name": "svc"}, "caBundle": caBundle}},
+			expChanged: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{Object: test.object}
+
+			changed, err := test.inject(obj, caBundle)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expObject, obj.Object)
+		})
+	}
+}
+
+func Test_Reconcile(t *testing.T) {
+	const namespace = "trust-namespace"
+
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)
+	caBundle := base64.StdEncoding.EncodeToString([]byte(data))
+
+	bundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-bundle"},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+		},
+	}
+	target := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "trust-bundle", Namespace: namespace},
+		Data:       map[string]string{"ca.crt": data},
+	}
+	apiService := func(annotations map[string]string, caBundle string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"spec": map[string]any{"caBundle": caBundle},
+		}}
+		obj.SetGroupVersionKind(injectables[3].gvk)
+		obj.SetName("v1.example.com")
+		obj.SetAnnotations(annotations)
+		return obj
+	}
+	annotated := map[string]string{trustapi.CAInjectionAnnotationKey: "trust-bundle"}
+
+	tests := map[string]struct {
+		objects []runtime.Object
+
+		expCABundle string
+		expEvent    string
+	}{
+		"annotated object should be injected": {
+			objects:     []runtime.Object{bundle, target, apiService(annotated, "")},
+			expCABundle: caBundle,
+			expEvent:    "Normal CAInjected Injected the data of Bundle \"trust-bundle\"",
+		},
+		"injected object should not change": {
+			objects:     []runtime.Object{bundle, target, apiService(annotated, caBundle)},
+			expCABundle: caBundle,
+		},
+		"object without annotation should not be injected": {
+			objects:     []runtime.Object{bundle, target, apiService(nil, "b2xk")},
+			expCABundle: "b2xk",
+		},
+		"missing Bundle should emit an event": {
+			objects:     []runtime.Object{target, apiService(annotated, "b2xk")},
+			expCABundle: "b2xk",
+			expEvent:    "Warning BundleNotFound Bundle \"trust-bundle\" does not exist",
+		},
+		"missing target should emit an event": {
+			objects:     []runtime.Object{bundle, apiService(annotated, "b2xk")},
+			expCABundle: "b2xk",
+			expEvent:    "Warning TargetNotFound Bundle \"trust-bundle\" is not synced to the trust namespace",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(test.objects...).
+				Build()
+			recorder := record.NewFakeRecorder(1)
+
+			r := &reconciler{
+				client:       fakeClient,
+				targetReader: fakeClient,
+				recorder:     recorder,
+				namespace:    namespace,
+				log:          logr.Discard(),
+				injectable:   injectables[3],
+			}
+
+			_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Name: "v1.example.com"}})
+			assert.NoError(t, err)
+
+			obj := apiService(nil, "")
+			assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: "v1.example.com"}, obj))
+			got, _, _ := unstructured.NestedString(obj.Object, "spec", "caBundle")
+			assert.Equal(t, test.expCABundle, got)
+
+			var event string
+			select {
+			case event = <-recorder.Events:
+			default:
+			}
+			assert.Equal(t, test.expEvent, event)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package consumerrestart implements controllers which roll out the pods of
+// Deployments, StatefulSets and DaemonSets consuming Bundles whenever the
+// content of the Bundles changes, so that long-running pods which only read
+// their trust anchors at startup pick up rotated trust.
+package consumerrestart
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// workload is a kind of object whose pods can be rolled out by changing its
+// pod template.
+type workload struct {
+	// name is the name of the controller restarting workloads of the kind.
+	name string
+
+	newObject func() client.Object
+	newList   func() client.ObjectList
+
+	// items returns the workloads of a list returned by newList.
+	items func(list client.ObjectList) []client.Object
+
+	// podTemplate returns the pod template of a workload returned by
+	// newObject.
+	podTemplate func(obj client.Object) *corev1.PodTemplateSpec
+}
+
+// workloads are the kinds of workloads which are restarted.
+var workloads = []workload{
+	{
+		name:      "deployments",
+		newObject: func() client.Object { return new(appsv1.Deployment) },
+		newList:   func() client.ObjectList { return new(appsv1.DeploymentList) },
+		items: func(list client.ObjectList) []client.Object {
+			var items []client.Object
+			for i := range list.(*appsv1.DeploymentList).Items {
+				items = append(items, &list.(*appsv1.DeploymentList).Items[i])
+			}
+			return items
+		},
+		podTemplate: func(obj client.Object) *corev1.PodTemplateSpec { return &obj.(*appsv1.Deployment).Spec.Template },
+	},
+	{
+		name:      "statefulsets",
+		newObject: func() client.Object { return new(appsv1.StatefulSet) },
+		newList:   func() client.ObjectList { return new(appsv1.StatefulSetList) },
+		items: func(list client.ObjectList) []client.Object {
+			var items []client.Object
+			for i := range list.(*appsv1.StatefulSetList).Items {
+				items = append(items, &list.(*appsv1.StatefulSetList).Items[i])
+			}
+			return items
+		},
+		podTemplate: func(obj client.Object) *corev1.PodTemplateSpec { return &obj.(*appsv1.StatefulSet).Spec.Template },
+	},
+	{
+		name:      "daemonsets",
+		newObject: func() client.Object { return new(appsv1.DaemonSet) },
+		newList:   func() client.ObjectList { return new(appsv1.DaemonSetList) },
+		items: func(list client.ObjectList) []client.Object {
+			var items []client.Object
+			for i := range list.(*appsv1.DaemonSetList).Items {
+				items = append(items, &list.(*appsv1.DaemonSetList).Items[i])
+			}
+			return items
+		},
+		podTemplate: func(obj client.Object) *corev1.PodTemplateSpec { return &obj.(*appsv1.DaemonSet).Spec.Template },
+	},
+}
+
+// AddControllers registers a consumer restart controller for each kind of
+// workload with the given Manager. Workloads are reconciled whenever a Bundle
+// they consume changes.
+func AddControllers(ctx context.Context, mgr manager.Manager, log logr.Logger) error {
+	for _, kind := range workloads {
+		kind := kind
+		r := &reconciler{
+			client:   mgr.GetClient(),
+			recorder: mgr.GetEventRecorderFor("consumerrestart"),
+			log:      log.WithName(kind.name),
+			workload: kind,
+		}
+
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named("consumerrestart-"+kind.name).
+			For(kind.newObject()).
+
+			// Reconcile the workloads consuming a modified Bundle.
+			Watches(&source.Kind{Type: new(trustapi.Bundle)}, handler.EnqueueRequestsFromMapFunc(
+				func(bundle client.Object) []reconcile.Request {
+					list := kind.newList()
+					if err := r.client.List(ctx, list); err != nil {
+						r.log.Error(err, "failed to list workloads")
+						return nil
+					}
+
+					var requests []reconcile.Request
+					for _, item := range kind.items(list) {
+						for _, name := range consumedBundles(item) {
+							if name == bundle.GetName() {
+								requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: item.GetNamespace(), Name: item.GetName()}})
+								break
+							}
+						}
+					}
+
+					return requests
+				},
+			)).
+			Complete(r)
+		if err != nil {
+			return fmt.Errorf("failed to register consumer restart controller for %s: %w", kind.name, err)
+		}
+	}
+
+	return nil
+}
+
+// reconciler restarts the workloads of a single kind.
+type reconciler struct {
+	client   client.Client
+	recorder record.EventRecorder
+	log      logr.Logger
+
+	workload
+}
+
+// Reconcile records the content hashes of the Bundles consumed by the
+// workload in the annotation of its pod template, which rolls out its pods
+// whenever the content of the Bundles changes.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("namespace", req.Namespace, "name", req.Name)
+
+	obj := r.newObject()
+	if err := r.client.Get(ctx, req.NamespacedName, obj); apierrors.IsNotFound(err) {
+		return ctrl.Result{}, nil
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get workload %s: %w", req.NamespacedName, err)
+	}
+
+	bundleNames := consumedBundles(obj)
+	if len(bundleNames) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	hashes := make([]string, 0, len(bundleNames))
+	for _, name := range bundleNames {
+		var bundle trustapi.Bundle
+		if err := r.client.Get(ctx, client.ObjectKey{Name: name}, &bundle); apierrors.IsNotFound(err) {
+			// The workload is reconciled again once the Bundle is created.
+			log.V(2).Info("consumed Bundle does not exist", "bundle", name)
+			return ctrl.Result{}, nil
+		} else if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get Bundle %q: %w", name, err)
+		}
+
+		// Bundles which haven't been synced yet have no content.
+		if bundle.Status.Content == nil {
+			log.V(2).Info("consumed Bundle has not been synced", "bundle", name)
+			return ctrl.Result{}, nil
+		}
+
+		hashes = append(hashes, name+"="+bundle.Status.Content.Hash)
+	}
+
+	template := r.podTemplate(obj)
+	value := strings.Join(hashes, ",")
+	previous, ok := template.Annotations[trustapi.BundleHashesAnnotationKey]
+	if ok && previous == value {
+		return ctrl.Result{}, nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations[trustapi.BundleHashesAnnotationKey] = value
+
+	if err := r.client.Patch(ctx, obj, patch); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to patch workload %s: %w", req.NamespacedName, err)
+	}
+
+	// Recording the hashes for the first time also rolls out the pods, since
+	// the content they loaded is unknown.
+	log.V(2).Info("restarted workload as consumed Bundles changed", "hashes", value)
+	r.recorder.Eventf(obj, corev1.EventTypeNormal, "BundleChanged", "Rolling out pods since the content of consumed Bundles changed")
+
+	return ctrl.Result{}, nil
+}
+
+// consumedBundles returns the sorted, de-duplicated names of the Bundles
+// consumed by the given workload.
+func consumedBundles(obj client.Object) []string {
+	value := obj.GetAnnotations()[trustapi.RestartOnBundleChangeAnnotationKey]
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if len(name) == 0 || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumerrestart
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_consumedBundles(t *testing.T) {
+	deployment := func(value string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{trustapi.RestartOnBundleChangeAnnotationKey: value},
+		}}
+	}
+
+	assert.Nil(t, consumedBundles(&appsv1.Deployment{}))
+	assert.Equal(t, []string{"trust-bundle"}, consumedBundles(deployment("trust-bundle")))
+	assert.Equal(t, []string{"a", "b"}, consumedBundles(deployment(" b, a,,b ")))
+}
+
+func Test_Reconcile(t *testing.T) {
+	bundle := func(name, hash string) *trustapi.Bundle {
+		bundle := &trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if len(hash) > 0 {
+			bundle.Status.Content = &trustapi.BundleContent{Hash: hash}
+		}
+		return bundle
+	}
+	deployment := func(consumes string, templateAnnotations map[string]string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "server"}}
+		if len(consumes) > 0 {
+			deployment.Annotations = map[string]string{trustapi.RestartOnBundleChangeAnnotationKey: consumes}
+		}
+		deployment.Spec.Template.Annotations = templateAnnotations
+		return deployment
+	}
+
+	tests := map[string]struct {
+		objects []runtime.Object
+
+		expTemplateAnnotations map[string]string
+		expEvent               string
+	}{
+		"consuming workload should have the hashes recorded": {
+			objects: []runtime.Object{bundle("b", "hash-b"), bundle("a", "hash-a"), deployment("b,a", map[string]string{"other": "value"})},
+			expTemplateAnnotations: map[string]string{
+				"other":                            "value",
+				trustapi.BundleHashesAnnotationKey: "a=hash-a,b=hash-b",
+			},
+			expEvent: "Normal BundleChanged Rolling out pods since the content of consumed Bundles changed",
+		},
+		"changed Bundle should restart the workload": {
+			objects: []runtime.Object{bundle("a", "new"), deployment("a", map[string]string{trustapi.BundleHashesAnnotationKey: "a=old"})},
+			expTemplateAnnotations: map[string]string{
+				trustapi.BundleHashesAnnotationKey: "a=new",
+			},
+			expEvent: "Normal BundleChanged Rolling out pods since the content of consumed Bundles changed",
+		},
+		"unchanged Bundle should not restart the workload": {
+			objects: []runtime.Object{bundle("a", "hash-a"), deployment("a", map[string]string{trustapi.BundleHashesAnnotationKey: "a=hash-a"})},
+			expTemplateAnnotations: map[string]string{
+				trustapi.BundleHashesAnnotationKey: "a=hash-a",
+			},
+		},
+		"missing Bundle should not restart the workload": {
+			objects:                []runtime.Object{bundle("a", "hash-a"), deployment("a,b", nil)},
+			expTemplateAnnotations: nil,
+		},
+		"unsynced Bundle should not restart the workload": {
+			objects:                []runtime.Object{bundle("a", ""), deployment("a", nil)},
+			expTemplateAnnotations: nil,
+		},
+		"workload without annotation should not be restarted": {
+			objects:                []runtime.Object{bundle("a", "hash-a"), deployment("", nil)},
+			expTemplateAnnotations: nil,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fakeClient := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(test.objects...).
+				Build()
+			recorder := record.NewFakeRecorder(1)
+
+			r := &reconciler{
+				client:   fakeClient,
+				recorder: recorder,
+				log:      logr.Discard(),
+				workload: workloads[0],
+			}
+
+			_, err := r.Reconcile(context.TODO(), ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "server"}})
+			assert.NoError(t, err)
+
+			var got appsv1.Deployment
+			assert.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: "app", Name: "server"}, &got))
+			assert.Equal(t, test.expTemplateAnnotations, got.Spec.Template.Annotations)
+
+			var event string
+			select {
+			case event = <-recorder.Events:
+			default:
+			}
+			assert.Equal(t, test.expEvent, event)
+		})
+	}
+}
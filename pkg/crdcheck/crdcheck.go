@@ -0,0 +1,245 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crdcheck checks at startup that the installed Bundle
+// CustomResourceDefinition is compatible with the controller. If the CRD
+// wasn't upgraded along with trust-manager, the API server silently prunes
+// fields which are missing from the installed schema, so that newer features
+// appear to be ignored and status fields are never persisted.
+package crdcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// BundleCRDName is the name of the Bundle CustomResourceDefinition.
+const BundleCRDName = "bundles." + trust.GroupName
+
+// crdGVK is the GroupVersionKind of CustomResourceDefinitions, which are read
+// as unstructured objects.
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// schemaCompatible is set to 1 if the installed CRD is compatible with the
+// controller, and 0 otherwise.
+var schemaCompatible = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "trust_manager",
+	Subsystem: "crd",
+	Name:      "schema_compatible",
+	Help:      "Set to 1 if the installed CustomResourceDefinition is compatible with the controller, and 0 if the CRD needs to be upgraded. Only set once the CRD has been checked at startup.",
+}, []string{"crd"})
+
+// AddToManager registers a check of the installed Bundle CRD with the given
+// Manager, which runs once the Manager is started. Incompatibilities are
+// logged, reported as a warning event on the CRD, and exposed by the
+// trust_manager_crd_schema_compatible metric.
+func AddToManager(mgr manager.Manager, log logr.Logger) error {
+	if err := ctrlmetrics.Registry.Register(schemaCompatible); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if !errors.As(err, &alreadyRegistered) {
+			return fmt.Errorf("failed to register CRD schema metric: %w", err)
+		}
+	}
+
+	return mgr.Add(&checker{
+		reader:   mgr.GetAPIReader(),
+		recorder: mgr.GetEventRecorderFor("trust-manager"),
+		log:      log,
+	})
+}
+
+// checker checks the installed Bundle CRD when started.
+type checker struct {
+	reader   client.Reader
+	recorder record.EventRecorder
+	log      logr.Logger
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable, so that
+// every replica reports whether the installed CRD is compatible.
+func (c *checker) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the installed Bundle CRD. Failing to read the CRD, for example
+// due to missing permissions, is logged rather than stopping the Manager.
+func (c *checker) Start(ctx context.Context) error {
+	crd := new(unstructured.Unstructured)
+	crd.SetGroupVersionKind(crdGVK)
+	if err := c.reader.Get(ctx, client.ObjectKey{Name: BundleCRDName}, crd); err != nil {
+		c.log.Error(err, "failed to get CRD to check its schema", "crd", BundleCRDName)
+		return nil
+	}
+
+	problems, err := Check(crd)
+	if err != nil {
+		c.log.Error(err, "failed to check CRD schema", "crd", BundleCRDName)
+		return nil
+	}
+
+	if len(problems) > 0 {
+		message := fmt.Sprintf("Installed CRD is not compatible with this version of trust-manager, upgrade the CRD: %s", strings.Join(problems, "; "))
+		c.log.Error(errors.New(message), "CRD schema check failed", "crd", BundleCRDName)
+		c.recorder.Event(crd, corev1.EventTypeWarning, "SchemaIncompatible", message)
+		schemaCompatible.WithLabelValues(BundleCRDName).Set(0)
+		return nil
+	}
+
+	c.log.V(2).Info("CRD schema is compatible", "crd", BundleCRDName)
+	schemaCompatible.WithLabelValues(BundleCRDName).Set(1)
+	return nil
+}
+
+// Check returns the incompatibilities of the given Bundle CRD with the
+// controller. The version of the Bundle API used by the controller must be
+// served, and its schema must contain every field of the Bundle's spec and
+// status known to the controller.
+func Check(crd *unstructured.Unstructured) ([]string, error) {
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRD versions: %w", err)
+	}
+
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok || version["name"] != trustapi.SchemeGroupVersion.Version {
+			continue
+		}
+
+		if served, _ := version["served"].(bool); !served {
+			return []string{fmt.Sprintf("version %s is not served", trustapi.SchemeGroupVersion.Version)}, nil
+		}
+
+		openAPISchema, _, err := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+		if err != nil {
+			return nil, fmt.Errorf("invalid CRD schema: %w", err)
+		}
+
+		var missing []string
+		for _, field := range []struct {
+			name string
+			typ  reflect.Type
+		}{
+			{"spec", reflect.TypeOf(trustapi.BundleSpec{})},
+			{"status", reflect.TypeOf(trustapi.BundleStatus{})},
+		} {
+			fieldSchema, ok := properties(openAPISchema)[field.name].(map[string]any)
+			if !ok {
+				missing = append(missing, field.name)
+				continue
+			}
+			missing = append(missing, missingFields(field.typ, field.name, fieldSchema)...)
+		}
+
+		sort.Strings(missing)
+
+		var problems []string
+		for _, field := range missing {
+			problems = append(problems, fmt.Sprintf("field %q is missing from the schema", field))
+		}
+
+		return problems, nil
+	}
+
+	return []string{fmt.Sprintf("version %s is not defined", trustapi.SchemeGroupVersion.Version)}, nil
+}
+
+// missingFields returns the paths of the fields of the given type which are
+// missing from the given schema, prefixed with the given path.
+func missingFields(typ reflect.Type, path string, fieldSchema map[string]any) []string {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	// Fields of types with custom JSON encoding, such as times and durations,
+	// and of schemas preserving unknown fields, are not checked further.
+	if typ.Implements(jsonMarshalerType) || reflect.PointerTo(typ).Implements(jsonMarshalerType) {
+		return nil
+	}
+	if preserve, _ := fieldSchema["x-kubernetes-preserve-unknown-fields"].(bool); preserve {
+		return nil
+	}
+
+	switch typ.Kind() {
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		if items, ok := fieldSchema["items"].(map[string]any); ok {
+			return missingFields(typ.Elem(), path+"[]", items)
+		}
+		return []string{path + "[]"}
+
+	case reflect.Map:
+		if values, ok := fieldSchema["additionalProperties"].(map[string]any); ok {
+			return missingFields(typ.Elem(), path+"{}", values)
+		}
+		return nil
+
+	case reflect.Struct:
+		var missing []string
+		props := properties(fieldSchema)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+
+			if opts == "inline" || (field.Anonymous && len(name) == 0) {
+				missing = append(missing, missingFields(field.Type, path, fieldSchema)...)
+				continue
+			}
+
+			childSchema, ok := props[name].(map[string]any)
+			if !ok {
+				missing = append(missing, path+"."+name)
+				continue
+			}
+			missing = append(missing, missingFields(field.Type, path+"."+name, childSchema)...)
+		}
+		return missing
+
+	default:
+		return nil
+	}
+}
+
+// properties returns the properties of the given object schema.
+func properties(objectSchema map[string]any) map[string]any {
+	props, _ := objectSchema["properties"].(map[string]any)
+	return props
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdcheck
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// loadBundleCRD returns the Bundle CRD shipped with trust-manager.
+func loadBundleCRD(t *testing.T) *unstructured.Unstructured {
+	data, err := os.ReadFile("../../deploy/crds/trust.cert-manager.io_bundles.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	crd := new(unstructured.Unstructured)
+	if err := yaml.Unmarshal(data, &crd.Object); err != nil {
+		t.Fatal(err)
+	}
+
+	return crd
+}
+
+func Test_Check(t *testing.T) {
+	tests := map[string]struct {
+		modify func(t *testing.T, crd *unstructured.Unstructured)
+
+		expProblems []string
+	}{
+		"shipped CRD should be compatible": {
+			modify: func(*testing.T, *unstructured.Unstructured) {},
+		},
+		"CRD without the served version should be incompatible": {
+			modify: func(t *testing.T, crd *unstructured.Unstructured) {
+				versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+				versions[0].(map[string]any)["served"] = false
+				assert.NoError(t, unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"))
+			},
+			expProblems: []string{"version v1alpha1 is not served"},
+		},
+		"CRD without the version should be incompatible": {
+			modify: func(t *testing.T, crd *unstructured.Unstructured) {
+				versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+				versions[0].(map[string]any)["name"] = "v1alpha0"
+				assert.NoError(t, unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"))
+			},
+			expProblems: []string{"version v1alpha1 is not defined"},
+		},
+		"CRD missing fields should report them": {
+			modify: func(t *testing.T, crd *unstructured.Unstructured) {
+				versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+				version := versions[0].(map[string]any)
+				unstructured.RemoveNestedField(version, "schema", "openAPIV3Schema", "properties", "spec", "properties", "target", "properties", "sizeLimit")
+				unstructured.RemoveNestedField(version, "schema", "openAPIV3Schema", "properties", "spec", "properties", "sources", "items", "properties", "weight")
+				unstructured.RemoveNestedField(version, "schema", "openAPIV3Schema", "properties", "status", "properties", "conditions", "items", "properties", "reason")
+				assert.NoError(t, unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"))
+			},
+			expProblems: []string{
+				`field "spec.sources[].weight" is missing from the schema`,
+				`field "spec.target.sizeLimit" is missing from the schema`,
+				`field "status.conditions[].reason" is missing from the schema`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			crd := loadBundleCRD(t)
+			test.modify(t, crd)
+
+			problems, err := Check(crd)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expProblems, problems)
+		})
+	}
+}
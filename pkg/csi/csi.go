@@ -0,0 +1,316 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package csi implements a CSI driver which mounts the content of Bundles
+// into pods as ephemeral inline volumes, so that workloads can consume a
+// Bundle without a target ConfigMap being synced to their Namespace. The
+// driver reads the targets of Bundles in the trust Namespace, and is run by
+// the "trust-manager csi-driver" agent on every node.
+package csi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/go-logr/logr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+const (
+	// DriverName is the name of the CSI driver.
+	DriverName = "csi." + trust.GroupName
+
+	// BundleAttributeKey is the volume attribute naming the Bundle which is
+	// mounted.
+	BundleAttributeKey = "bundle"
+
+	// FormatAttributeKey is the volume attribute selecting the format of the
+	// Bundle which is mounted. Defaults to FormatPEM.
+	FormatAttributeKey = "format"
+
+	// FormatPEM mounts the PEM encoded Bundle, in a file named after the key
+	// of the Bundle's target.
+	FormatPEM = "pem"
+
+	// FormatJKS mounts the JKS truststore of the Bundle, in a file named after
+	// the JKS key of the Bundle's target. The Bundle must write the JKS format.
+	FormatJKS = "jks"
+
+	// ephemeralAttributeKey is set by the kubelet to "true" on the volume
+	// context of ephemeral inline volumes.
+	ephemeralAttributeKey = "csi.storage.k8s.io/ephemeral"
+)
+
+// Driver serves the CSI identity and node services, mounting Bundles read
+// from the targets in the trust Namespace.
+type Driver struct {
+	csi.UnimplementedIdentityServer
+	csi.UnimplementedNodeServer
+
+	// Reader reads Bundles and their target ConfigMaps.
+	Reader client.Reader
+
+	// Naming are the naming conventions of the Bundle controller, used to
+	// find the targets of Bundles.
+	Naming *naming.Conventions
+
+	// TrustNamespace is the Namespace which the targets of mounted Bundles
+	// are read from.
+	TrustNamespace string
+
+	// NodeID is the name of the node the driver runs on.
+	NodeID string
+
+	Log logr.Logger
+}
+
+// Run serves the driver on the given endpoint, which is a unix socket URL such
+// as unix:///csi/csi.sock, until the context is cancelled.
+func (d *Driver) Run(ctx context.Context, endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme != "unix" {
+		return fmt.Errorf("invalid CSI endpoint %q, must be a unix socket URL", endpoint)
+	}
+
+	path := u.Path
+	if len(path) == 0 {
+		path = u.Host
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale CSI socket %q: %w", path, err)
+	}
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return fmt.Errorf("failed to listen on CSI socket %q: %w", path, err)
+	}
+
+	server := grpc.NewServer()
+	csi.RegisterIdentityServer(server, d)
+	csi.RegisterNodeServer(server, d)
+
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	d.Log.Info("serving CSI driver", "driver", DriverName, "endpoint", endpoint, "node", d.NodeID)
+	return server.Serve(listener)
+}
+
+// GetPluginInfo returns the name and version of the driver.
+func (d *Driver) GetPluginInfo(context.Context, *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && len(info.Main.Version) > 0 {
+		version = info.Main.Version
+	}
+
+	return &csi.GetPluginInfoResponse{Name: DriverName, VendorVersion: version}, nil
+}
+
+// GetPluginCapabilities returns no capabilities, since the driver only
+// provides the node service.
+func (d *Driver) GetPluginCapabilities(context.Context, *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
+	return &csi.GetPluginCapabilitiesResponse{}, nil
+}
+
+// Probe reports that the driver is ready.
+func (d *Driver) Probe(context.Context, *csi.ProbeRequest) (*csi.ProbeResponse, error) {
+	return &csi.ProbeResponse{}, nil
+}
+
+// NodeGetCapabilities returns no capabilities, since volumes are neither
+// staged nor expanded.
+func (d *Driver) NodeGetCapabilities(context.Context, *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
+	return &csi.NodeGetCapabilitiesResponse{}, nil
+}
+
+// NodeGetInfo returns the ID of the node the driver runs on.
+func (d *Driver) NodeGetInfo(context.Context, *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
+	return &csi.NodeGetInfoResponse{NodeId: d.NodeID}, nil
+}
+
+// NodePublishVolume writes the content of the Bundle named by the volume's
+// attributes into the volume's target path. Since the CSIDriver requires
+// republishing, this is called periodically by the kubelet, so that mounted
+// Bundles are kept up to date.
+func (d *Driver) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume ID must be set")
+	}
+	if len(req.GetTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target path must be set")
+	}
+	if req.GetVolumeCapability().GetMount() == nil {
+		return nil, status.Error(codes.InvalidArgument, "only mount volumes are supported")
+	}
+
+	attributes := req.GetVolumeContext()
+	if attributes[ephemeralAttributeKey] != "true" {
+		return nil, status.Error(codes.InvalidArgument, "only ephemeral inline volumes are supported")
+	}
+
+	bundleName := attributes[BundleAttributeKey]
+	if len(bundleName) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "volume attribute %q must be set", BundleAttributeKey)
+	}
+
+	format := attributes[FormatAttributeKey]
+	if len(format) == 0 {
+		format = FormatPEM
+	}
+
+	name, data, err := d.bundleFile(ctx, bundleName, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeVolume(req.GetTargetPath(), name, data); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to write Bundle %q to volume: %s", bundleName, err)
+	}
+
+	d.Log.V(2).Info("published Bundle to volume", "bundle", bundleName, "format", format, "volume", req.GetVolumeId())
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// NodeUnpublishVolume removes the content of the volume's target path.
+func (d *Driver) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
+	if len(req.GetVolumeId()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "volume ID must be set")
+	}
+	if len(req.GetTargetPath()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "target path must be set")
+	}
+
+	if err := os.RemoveAll(req.GetTargetPath()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to remove volume: %s", err)
+	}
+
+	d.Log.V(2).Info("unpublished volume", "volume", req.GetVolumeId())
+
+	return &csi.NodeUnpublishVolumeResponse{}, nil
+}
+
+// bundleFile returns the name and content of the file of the named Bundle in
+// the given format, read from the Bundle's target in the trust Namespace.
+func (d *Driver) bundleFile(ctx context.Context, bundleName, format string) (string, []byte, error) {
+	var bundle trustapi.Bundle
+	if err := d.Reader.Get(ctx, client.ObjectKey{Name: bundleName}, &bundle); apierrors.IsNotFound(err) {
+		return "", nil, status.Errorf(codes.NotFound, "Bundle %q does not exist", bundleName)
+	} else if err != nil {
+		return "", nil, status.Errorf(codes.Unavailable, "failed to get Bundle %q: %s", bundleName, err)
+	}
+
+	target := bundle.Spec.Target
+	if target.ConfigMap == nil {
+		return "", nil, status.Errorf(codes.FailedPrecondition, "Bundle %q has no ConfigMap target", bundleName)
+	}
+
+	targetName, err := d.Naming.BundleTargetName(bundle.Name, target)
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, err.Error())
+	}
+
+	var configMap corev1.ConfigMap
+	key := client.ObjectKey{Namespace: d.TrustNamespace, Name: targetName}
+	if err := d.Reader.Get(ctx, key, &configMap); apierrors.IsNotFound(err) {
+		return "", nil, status.Errorf(codes.Unavailable, "target ConfigMap %s of Bundle %q does not exist, the Bundle must sync to the trust namespace", key, bundleName)
+	} else if err != nil {
+		return "", nil, status.Errorf(codes.Unavailable, "failed to get target ConfigMap %s of Bundle %q: %s", key, bundleName, err)
+	}
+
+	switch format {
+	case FormatPEM:
+		data, ok := util.TargetData(&configMap, target.ConfigMap.Key, target.ConfigMap.Format)
+		if !ok {
+			return "", nil, status.Errorf(codes.Unavailable, "target ConfigMap %s has no key %q", key, target.ConfigMap.Key)
+		}
+		return target.ConfigMap.Key, []byte(data), nil
+
+	case FormatJKS:
+		if target.AdditionalFormats == nil || target.AdditionalFormats.JKS == nil {
+			return "", nil, status.Errorf(codes.FailedPrecondition, "Bundle %q does not write the JKS format", bundleName)
+		}
+		data, ok := configMap.BinaryData[target.AdditionalFormats.JKS.Key]
+		if !ok {
+			return "", nil, status.Errorf(codes.Unavailable, "target ConfigMap %s has no key %q", key, target.AdditionalFormats.JKS.Key)
+		}
+		return target.AdditionalFormats.JKS.Key, data, nil
+
+	default:
+		return "", nil, status.Errorf(codes.InvalidArgument, "unsupported volume attribute %q value %q, must be one of %q, %q", FormatAttributeKey, format, FormatPEM, FormatJKS)
+	}
+}
+
+// writeVolume writes data to the named file in the volume's target path,
+// replacing any other files. The file is replaced atomically, so that pods
+// never read a partially written Bundle.
+func writeVolume(targetPath, name string, data []byte) error {
+	if err := os.MkdirAll(targetPath, 0o755); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(targetPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() != name {
+			if err := os.RemoveAll(filepath.Join(targetPath, entry.Name())); err != nil {
+				return err
+			}
+		}
+	}
+
+	tmp, err := os.CreateTemp(targetPath, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(targetPath, name))
+}
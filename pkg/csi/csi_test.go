@@ -0,0 +1,194 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package csi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_NodePublishVolume(t *testing.T) {
+	const trustNamespace = "trust-namespace"
+
+	objects := []runtime.Object{
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-a"},
+			Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "ca.jks"}}},
+			}},
+		},
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-b"},
+			Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Name: "ca-certificates", Key: "trust.pem"},
+			}},
+		},
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-c"},
+			Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+			}},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-a", Namespace: trustNamespace},
+			Data:       map[string]string{"ca.crt": dummy.TestCertificate1},
+			BinaryData: map[string][]byte{"ca.jks": []byte("jks")},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ca-certificates", Namespace: trustNamespace},
+			Data:       map[string]string{"trust.pem": dummy.TestCertificate2},
+		},
+	}
+
+	tests := map[string]struct {
+		attributes map[string]string
+		existing   map[string]string
+
+		expFiles map[string]string
+		expCode  codes.Code
+	}{
+		"PEM format should be written by default": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-a"},
+			expFiles:   map[string]string{"ca.crt": dummy.TestCertificate1},
+		},
+		"JKS format should be written": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-a", FormatAttributeKey: FormatJKS},
+			expFiles:   map[string]string{"ca.jks": "jks"},
+		},
+		"named target should be read": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-b", FormatAttributeKey: FormatPEM},
+			expFiles:   map[string]string{"trust.pem": dummy.TestCertificate2},
+		},
+		"republishing should replace existing files": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-a"},
+			existing:   map[string]string{"ca.crt": "old", "ca.jks": "old"},
+			expFiles:   map[string]string{"ca.crt": dummy.TestCertificate1},
+		},
+		"JKS format of Bundle without JKS should error": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-b", FormatAttributeKey: FormatJKS},
+			expCode:    codes.FailedPrecondition,
+		},
+		"unsupported format should error": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-a", FormatAttributeKey: "pkcs12"},
+			expCode:    codes.InvalidArgument,
+		},
+		"missing bundle attribute should error": {
+			attributes: map[string]string{},
+			expCode:    codes.InvalidArgument,
+		},
+		"missing Bundle should error": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-d"},
+			expCode:    codes.NotFound,
+		},
+		"Bundle not synced to the trust namespace should error": {
+			attributes: map[string]string{BundleAttributeKey: "bundle-c"},
+			expCode:    codes.Unavailable,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			driver := &Driver{
+				Reader: fakeclient.NewClientBuilder().
+					WithScheme(trustapi.GlobalScheme).
+					WithRuntimeObjects(objects...).
+					Build(),
+				TrustNamespace: trustNamespace,
+				Log:            logr.Discard(),
+			}
+
+			targetPath := filepath.Join(t.TempDir(), "mount")
+			for name, data := range test.existing {
+				assert.NoError(t, os.MkdirAll(targetPath, 0o755))
+				assert.NoError(t, os.WriteFile(filepath.Join(targetPath, name), []byte(data), 0o644))
+			}
+
+			attributes := map[string]string{ephemeralAttributeKey: "true"}
+			for k, v := range test.attributes {
+				attributes[k] = v
+			}
+
+			_, err := driver.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+				VolumeId:         "volume",
+				TargetPath:       targetPath,
+				VolumeCapability: &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+				VolumeContext:    attributes,
+			})
+			assert.Equal(t, test.expCode, status.Code(err), "%v", err)
+			if test.expCode != codes.OK {
+				return
+			}
+
+			entries, err := os.ReadDir(targetPath)
+			assert.NoError(t, err)
+
+			files := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(targetPath, entry.Name()))
+				assert.NoError(t, err)
+				files[entry.Name()] = string(data)
+			}
+			assert.Equal(t, test.expFiles, files)
+		})
+	}
+}
+
+func Test_NodePublishVolumeNotEphemeral(t *testing.T) {
+	driver := &Driver{Log: logr.Discard()}
+
+	_, err := driver.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+		VolumeId:         "volume",
+		TargetPath:       t.TempDir(),
+		VolumeCapability: &csi.VolumeCapability{AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}}},
+		VolumeContext:    map[string]string{BundleAttributeKey: "bundle-a"},
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func Test_NodeUnpublishVolume(t *testing.T) {
+	driver := &Driver{Log: logr.Discard()}
+
+	targetPath := filepath.Join(t.TempDir(), "mount")
+	assert.NoError(t, os.MkdirAll(targetPath, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(targetPath, "ca.crt"), []byte(dummy.TestCertificate1), 0o644))
+
+	_, err := driver.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "volume", TargetPath: targetPath})
+	assert.NoError(t, err)
+
+	_, err = os.Stat(targetPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// Unpublishing a volume which was already removed succeeds.
+	_, err = driver.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "volume", TargetPath: targetPath})
+	assert.NoError(t, err)
+}
@@ -24,7 +24,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"time"
 
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/pkg/util"
 )
 
@@ -42,6 +44,18 @@ type Package struct {
 
 	// Version identifies the bundle's version, to distinguish updated bundles from older counterparts
 	Version string `json:"version"`
+
+	// DistrustAfter optionally maps the hex encoded SHA-256 fingerprints of certificates in the bundle
+	// to the time after which they are no longer trusted for server authentication, mirroring the
+	// distrust-after metadata of Mozilla's NSS trust store. Once the time has passed, the certificate
+	// is excluded from Bundles using the package.
+	DistrustAfter map[string]time.Time `json:"distrustAfter,omitempty"`
+
+	// Purposes optionally maps the hex encoded SHA-256 fingerprints of certificates in the bundle to
+	// the purposes they are trusted for, mirroring the trust bits of Mozilla's NSS trust store. The
+	// purposes select the certificates written to the profiles of Bundle targets. Certificates which
+	// aren't listed are trusted for the purposes allowed by their extended key usage extension.
+	Purposes map[string][]trustapi.TrustPurpose `json:"purposes,omitempty"`
 }
 
 // StringID returns a human-readable string ID which should allow one package to be easily distinguished from another.
@@ -53,10 +67,28 @@ func (p Package) StringID() string {
 
 // Clone returns a new copy of the given package
 func (p *Package) Clone() *Package {
+	var distrustAfter map[string]time.Time
+	if p.DistrustAfter != nil {
+		distrustAfter = make(map[string]time.Time, len(p.DistrustAfter))
+		for fingerprint, t := range p.DistrustAfter {
+			distrustAfter[fingerprint] = t
+		}
+	}
+
+	var purposes map[string][]trustapi.TrustPurpose
+	if p.Purposes != nil {
+		purposes = make(map[string][]trustapi.TrustPurpose, len(p.Purposes))
+		for fingerprint, certificatePurposes := range p.Purposes {
+			purposes[fingerprint] = append([]trustapi.TrustPurpose(nil), certificatePurposes...)
+		}
+	}
+
 	return &Package{
-		Name:    p.Name,
-		Bundle:  p.Bundle,
-		Version: p.Version,
+		Name:          p.Name,
+		Bundle:        p.Bundle,
+		Version:       p.Version,
+		DistrustAfter: distrustAfter,
+		Purposes:      purposes,
 	}
 }
 
@@ -77,6 +109,26 @@ func (p *Package) Validate() error {
 		return fmt.Errorf("package may not have an empty 'version'")
 	}
 
+	for fingerprint := range p.DistrustAfter {
+		if _, err := util.ParseFingerprint(fingerprint); err != nil {
+			return fmt.Errorf("package has invalid 'distrustAfter' fingerprint %q: %w", fingerprint, err)
+		}
+	}
+
+	for fingerprint, purposes := range p.Purposes {
+		if _, err := util.ParseFingerprint(fingerprint); err != nil {
+			return fmt.Errorf("package has invalid 'purposes' fingerprint %q: %w", fingerprint, err)
+		}
+
+		for _, purpose := range purposes {
+			switch purpose {
+			case trustapi.TrustPurposeServerAuth, trustapi.TrustPurposeClientAuth, trustapi.TrustPurposeAny:
+			default:
+				return fmt.Errorf("package has unsupported purpose %q for fingerprint %q", purpose, fingerprint)
+			}
+		}
+	}
+
 	return nil
 }
 
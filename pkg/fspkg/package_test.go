@@ -19,8 +19,11 @@ package fspkg
 import (
 	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
+	"time"
 
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
 	"github.com/cert-manager/trust-manager/test/dummy"
 )
 
@@ -68,6 +71,42 @@ func Test_LoadPackage(t *testing.T) {
 			}),
 			expError: true,
 		},
+		"package with invalid distrustAfter fingerprint is rejected": {
+			testData: quickJSONFromPackage(Package{
+				Name:          "asd",
+				Version:       "123",
+				Bundle:        dummy.TestCertificate5,
+				DistrustAfter: map[string]time.Time{"not-a-fingerprint": time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			}),
+			expError: true,
+		},
+		"valid package with distrustAfter is loaded without error": {
+			testData: quickJSONFromPackage(Package{
+				Name:          "asd",
+				Version:       "123",
+				Bundle:        dummy.TestCertificate5,
+				DistrustAfter: map[string]time.Time{strings.Repeat("ab", 32): time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			}),
+			expError: false,
+		},
+		"package with unsupported purpose is rejected": {
+			testData: quickJSONFromPackage(Package{
+				Name:     "asd",
+				Version:  "123",
+				Bundle:   dummy.TestCertificate5,
+				Purposes: map[string][]trustapi.TrustPurpose{strings.Repeat("ab", 32): {"EmailProtection"}},
+			}),
+			expError: true,
+		},
+		"valid package with purposes is loaded without error": {
+			testData: quickJSONFromPackage(Package{
+				Name:     "asd",
+				Version:  "123",
+				Bundle:   dummy.TestCertificate5,
+				Purposes: map[string][]trustapi.TrustPurpose{strings.Repeat("ab", 32): {trustapi.TrustPurposeServerAuth}},
+			}),
+			expError: false,
+		},
 		"valid package is loaded without error": {
 			testData: quickJSONFromPackage(Package{
 				Name:    "asd",
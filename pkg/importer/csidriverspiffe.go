@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// csiDriverSPIFFESourceCABundleFlag is the csi-driver-spiffe flag giving
+	// the path of the CA bundle the driver writes to each mounted volume.
+	csiDriverSPIFFESourceCABundleFlag = "--source-ca-bundle"
+
+	// csiDriverSPIFFETrustDomainFlag is the csi-driver-spiffe flag giving the
+	// SPIFFE trust domain of the issued SVIDs.
+	csiDriverSPIFFETrustDomainFlag = "--trust-domain"
+
+	// csiDriverSPIFFEDefaultTrustDomain is the trust domain used by
+	// csi-driver-spiffe if none is configured.
+	csiDriverSPIFFEDefaultTrustDomain = "cluster.local"
+
+	// CSIDriverSPIFFETargetKey is the key of the target entry the PEM bundle
+	// is written to in Bundles imported from csi-driver-spiffe, matching the
+	// file name csi-driver-spiffe writes the CA bundle to.
+	CSIDriverSPIFFETargetKey = "ca.crt"
+
+	// CSIDriverSPIFFESPIFFEKey is the key of the target entry the SPIFFE trust
+	// bundle is written to in Bundles imported from csi-driver-spiffe.
+	CSIDriverSPIFFESPIFFEKey = "bundle.spiffe"
+)
+
+// FromCSIDriverSPIFFE returns Bundles equivalent to the trust bundle
+// configuration of the given csi-driver-spiffe DaemonSets, with one Bundle
+// per SPIFFE trust domain. The returned warnings describe configuration which
+// could not be imported, or which needs manual action before the Bundles can
+// be synced.
+func FromCSIDriverSPIFFE(daemonSets []appsv1.DaemonSet, trustNamespace string) ([]*trustapi.Bundle, []string) {
+	var warnings []string
+	bundles := make(map[string]*trustapi.Bundle)
+
+	for _, daemonSet := range daemonSets {
+		spec := daemonSet.Spec.Template.Spec
+
+		for _, container := range spec.Containers {
+			args := append(append([]string{}, container.Command...), container.Args...)
+
+			caBundle, ok := flagValue(args, csiDriverSPIFFESourceCABundleFlag)
+			if !ok {
+				continue
+			}
+
+			ref := fmt.Sprintf("DaemonSet %s/%s container %q", daemonSet.Namespace, daemonSet.Name, container.Name)
+
+			trustDomain, ok := flagValue(args, csiDriverSPIFFETrustDomainFlag)
+			if !ok {
+				trustDomain = csiDriverSPIFFEDefaultTrustDomain
+			}
+
+			source, err := resolveSource(spec, container, caBundle)
+			if err != nil {
+				warnings = append(warnings, fmt.Sprintf("%s: cannot import source CA bundle %q: %s", ref, caBundle, err))
+				continue
+			}
+
+			if daemonSet.Namespace != trustNamespace {
+				warnings = append(warnings, fmt.Sprintf("%s: source CA bundle is read from Namespace %q, and must be copied to the trust Namespace %q", ref, daemonSet.Namespace, trustNamespace))
+			}
+
+			bundle, ok := bundles[trustDomain]
+			if !ok {
+				bundle = newCSIDriverSPIFFEBundle(trustDomain)
+				bundles[trustDomain] = bundle
+			}
+
+			if !hasSource(bundle.Spec.Sources, source) {
+				bundle.Spec.Sources = append(bundle.Spec.Sources, source)
+			}
+		}
+	}
+
+	result := make([]*trustapi.Bundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		result = append(result, bundle)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+
+	return result, warnings
+}
+
+// newCSIDriverSPIFFEBundle returns a Bundle without sources for the given
+// SPIFFE trust domain.
+func newCSIDriverSPIFFEBundle(trustDomain string) *trustapi.Bundle {
+	return &trustapi.Bundle{
+		TypeMeta:   metav1.TypeMeta{APIVersion: trustapi.SchemeGroupVersion.String(), Kind: "Bundle"},
+		ObjectMeta: metav1.ObjectMeta{Name: "spiffe-" + trustDomain},
+		Spec: trustapi.BundleSpec{
+			Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: CSIDriverSPIFFETargetKey},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					SPIFFE: &trustapi.KeySelector{Key: CSIDriverSPIFFESPIFFEKey},
+				},
+			},
+		},
+	}
+}
+
+// flagValue returns the value of the given flag in the given arguments, given
+// either as "--flag=value" or "--flag value".
+func flagValue(args []string, flag string) (string, bool) {
+	for i, arg := range args {
+		if strings.HasPrefix(arg, flag+"=") {
+			return strings.TrimPrefix(arg, flag+"="), true
+		}
+		if arg == flag && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+
+	return "", false
+}
+
+// resolveSource returns the Bundle source of the file at the given path in the
+// given container, resolved through the container's volume mounts to a key of
+// a ConfigMap or Secret volume.
+func resolveSource(spec corev1.PodSpec, container corev1.Container, file string) (trustapi.BundleSource, error) {
+	file = path.Clean(file)
+
+	var mount *corev1.VolumeMount
+	var volumePath string
+	for i := range container.VolumeMounts {
+		candidate := &container.VolumeMounts[i]
+		mountPath := path.Clean(candidate.MountPath)
+
+		var rel string
+		switch {
+		case file == mountPath && len(candidate.SubPath) > 0:
+			rel = candidate.SubPath
+		case strings.HasPrefix(file, mountPath+"/"):
+			rel = path.Join(candidate.SubPath, strings.TrimPrefix(file, mountPath+"/"))
+		default:
+			continue
+		}
+
+		// Prefer the most specific mount containing the file.
+		if mount == nil || len(mountPath) > len(path.Clean(mount.MountPath)) {
+			mount, volumePath = candidate, rel
+		}
+	}
+
+	if mount == nil {
+		return trustapi.BundleSource{}, errors.New("file is not in a mounted volume")
+	}
+
+	for _, volume := range spec.Volumes {
+		if volume.Name != mount.Name {
+			continue
+		}
+
+		switch {
+		case volume.ConfigMap != nil:
+			key, err := volumeKey(volume.ConfigMap.Items, volumePath)
+			if err != nil {
+				return trustapi.BundleSource{}, err
+			}
+			return trustapi.BundleSource{ConfigMap: &trustapi.SourceObjectKeySelector{Name: volume.ConfigMap.Name, Key: key}}, nil
+
+		case volume.Secret != nil:
+			key, err := volumeKey(volume.Secret.Items, volumePath)
+			if err != nil {
+				return trustapi.BundleSource{}, err
+			}
+			return trustapi.BundleSource{Secret: &trustapi.SourceObjectKeySelector{Name: volume.Secret.SecretName, Key: key}}, nil
+
+		default:
+			return trustapi.BundleSource{}, fmt.Errorf("volume %q is not a ConfigMap or Secret volume", volume.Name)
+		}
+	}
+
+	return trustapi.BundleSource{}, fmt.Errorf("volume %q is not defined", mount.Name)
+}
+
+// volumeKey returns the key of the ConfigMap or Secret projected to the given
+// path of its volume.
+func volumeKey(items []corev1.KeyToPath, volumePath string) (string, error) {
+	if len(items) == 0 {
+		if strings.Contains(volumePath, "/") {
+			return "", fmt.Errorf("path %q does not match a key of the volume", volumePath)
+		}
+		return volumePath, nil
+	}
+
+	for _, item := range items {
+		if path.Clean(item.Path) == volumePath {
+			return item.Key, nil
+		}
+	}
+
+	return "", fmt.Errorf("path %q does not match a key of the volume", volumePath)
+}
+
+// hasSource returns true if the given sources contain the given ConfigMap or
+// Secret source.
+func hasSource(sources []trustapi.BundleSource, source trustapi.BundleSource) bool {
+	for _, existing := range sources {
+		if existing.ConfigMap != nil && source.ConfigMap != nil && apiequality.Semantic.DeepEqual(existing.ConfigMap, source.ConfigMap) {
+			return true
+		}
+		if existing.Secret != nil && source.Secret != nil && apiequality.Semantic.DeepEqual(existing.Secret, source.Secret) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package importer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_FromCSIDriverSPIFFE(t *testing.T) {
+	daemonSet := func(namespace string, args []string, mounts []corev1.VolumeMount, volumes ...corev1.Volume) appsv1.DaemonSet {
+		return appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "csi-driver-spiffe", Namespace: namespace},
+			Spec: appsv1.DaemonSetSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{Name: "node-driver-registrar", Args: []string{"--v=5"}},
+							{Name: "cert-manager-csi-driver-spiffe", Args: args, VolumeMounts: mounts},
+						},
+						Volumes: volumes,
+					},
+				},
+			},
+		}
+	}
+
+	configMapVolume := func(name string, items ...corev1.KeyToPath) corev1.Volume {
+		return corev1.Volume{Name: "ca", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: name},
+			Items:                items,
+		}}}
+	}
+
+	mounts := []corev1.VolumeMount{{Name: "ca", MountPath: "/var/run/secrets/spiffe.io"}}
+
+	bundle := func(trustDomain string, sources ...trustapi.BundleSource) *trustapi.Bundle {
+		b := newCSIDriverSPIFFEBundle(trustDomain)
+		b.Spec.Sources = sources
+		return b
+	}
+
+	configMapSource := func(name, key string) trustapi.BundleSource {
+		return trustapi.BundleSource{ConfigMap: &trustapi.SourceObjectKeySelector{Name: name, Key: key}}
+	}
+
+	tests := map[string]struct {
+		daemonSets []appsv1.DaemonSet
+
+		expBundles  []*trustapi.Bundle
+		expWarnings []string
+	}{
+		"no DaemonSets should produce no Bundles": {
+			expBundles: []*trustapi.Bundle{},
+		},
+		"DaemonSet without a source CA bundle should be ignored": {
+			daemonSets: []appsv1.DaemonSet{daemonSet("cert-manager", []string{"--trust-domain=example.org"}, mounts, configMapVolume("ca"))},
+			expBundles: []*trustapi.Bundle{},
+		},
+		"ConfigMap volume should be imported with the default trust domain": {
+			daemonSets: []appsv1.DaemonSet{daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, configMapVolume("spiffe-ca"))},
+			expBundles: []*trustapi.Bundle{bundle("cluster.local", configMapSource("spiffe-ca", "ca.crt"))},
+		},
+		"ConfigMap volume items should be mapped back to keys": {
+			daemonSets: []appsv1.DaemonSet{daemonSet("cert-manager",
+				[]string{"--trust-domain", "example.org", "--source-ca-bundle", "/var/run/secrets/spiffe.io/bundle/ca.pem"},
+				mounts,
+				configMapVolume("spiffe-ca", corev1.KeyToPath{Key: "root.pem", Path: "bundle/ca.pem"}),
+			)},
+			expBundles: []*trustapi.Bundle{bundle("example.org", configMapSource("spiffe-ca", "root.pem"))},
+		},
+		"Secret volume mounted with subPath should be imported": {
+			daemonSets: []appsv1.DaemonSet{daemonSet("cert-manager",
+				[]string{"--trust-domain=example.org", "--source-ca-bundle=/etc/ca.crt"},
+				[]corev1.VolumeMount{{Name: "ca", MountPath: "/etc/ca.crt", SubPath: "tls.crt"}},
+				corev1.Volume{Name: "ca", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "spiffe-ca"}}},
+			)},
+			expBundles: []*trustapi.Bundle{bundle("example.org", trustapi.BundleSource{Secret: &trustapi.SourceObjectKeySelector{Name: "spiffe-ca", Key: "tls.crt"}})},
+		},
+		"DaemonSets of the same trust domain should be merged into one Bundle": {
+			daemonSets: []appsv1.DaemonSet{
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, configMapVolume("spiffe-ca")),
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, configMapVolume("spiffe-ca")),
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/next.crt"}, mounts, configMapVolume("spiffe-ca")),
+			},
+			expBundles: []*trustapi.Bundle{bundle("cluster.local", configMapSource("spiffe-ca", "ca.crt"), configMapSource("spiffe-ca", "next.crt"))},
+		},
+		"source outside of the trust Namespace should be imported with a warning": {
+			daemonSets:  []appsv1.DaemonSet{daemonSet("spiffe", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, configMapVolume("spiffe-ca"))},
+			expBundles:  []*trustapi.Bundle{bundle("cluster.local", configMapSource("spiffe-ca", "ca.crt"))},
+			expWarnings: []string{`DaemonSet spiffe/csi-driver-spiffe container "cert-manager-csi-driver-spiffe": source CA bundle is read from Namespace "spiffe", and must be copied to the trust Namespace "cert-manager"`},
+		},
+		"source CA bundle which cannot be resolved should be skipped with a warning": {
+			daemonSets: []appsv1.DaemonSet{
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/etc/ca.crt"}, mounts, configMapVolume("spiffe-ca")),
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, corev1.Volume{Name: "ca", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}),
+				daemonSet("cert-manager", []string{"--source-ca-bundle=/var/run/secrets/spiffe.io/ca.crt"}, mounts, configMapVolume("spiffe-ca", corev1.KeyToPath{Key: "root.pem", Path: "root.pem"})),
+			},
+			expBundles: []*trustapi.Bundle{},
+			expWarnings: []string{
+				`DaemonSet cert-manager/csi-driver-spiffe container "cert-manager-csi-driver-spiffe": cannot import source CA bundle "/etc/ca.crt": file is not in a mounted volume`,
+				`DaemonSet cert-manager/csi-driver-spiffe container "cert-manager-csi-driver-spiffe": cannot import source CA bundle "/var/run/secrets/spiffe.io/ca.crt": volume "ca" is not a ConfigMap or Secret volume`,
+				`DaemonSet cert-manager/csi-driver-spiffe container "cert-manager-csi-driver-spiffe": cannot import source CA bundle "/var/run/secrets/spiffe.io/ca.crt": path "ca.crt" does not match a key of the volume`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			bundles, warnings := FromCSIDriverSPIFFE(test.daemonSets, "cert-manager")
+			assert.Equal(t, test.expBundles, bundles)
+			assert.Equal(t, test.expWarnings, warnings)
+		})
+	}
+}
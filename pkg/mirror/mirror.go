@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mirror implements a controller which mirrors the certificates of
+// labeled Secrets in the trust Namespace into ConfigMaps, stripping private
+// keys and any other data. It runs separately from the Bundle controller, so
+// that only the mirror needs permission to read Secrets.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// AddController registers the Secret mirror controller with the given
+// Manager. The Manager's cache should be restricted to the trust Namespace,
+// and to Secrets labeled with trustapi.SecretMirrorLabelKey.
+func AddController(mgr manager.Manager, log logr.Logger) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("secret-mirror").
+		For(new(corev1.Secret)).
+		Owns(new(corev1.ConfigMap)).
+		Complete(&reconciler{client: mgr.GetClient(), scheme: mgr.GetScheme(), log: log})
+}
+
+// reconciler mirrors a labeled Secret into a ConfigMap of the same name.
+type reconciler struct {
+	client client.Client
+	scheme *runtime.Scheme
+	log    logr.Logger
+}
+
+// Reconcile creates or updates the mirror ConfigMap of a labeled Secret. If
+// the Secret no longer exists, or is no longer labeled, its mirror is
+// deleted. ConfigMaps which aren't controlled by the Secret are never
+// modified.
+func (r *reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.log.WithValues("secret", req.NamespacedName)
+
+	var configMap corev1.ConfigMap
+	err := r.client.Get(ctx, req.NamespacedName, &configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+	} else if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to get ConfigMap %s: %w", req.NamespacedName, err)
+	}
+	exists := err == nil
+
+	var secret corev1.Secret
+	err = r.client.Get(ctx, req.NamespacedName, &secret)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return ctrl.Result{}, fmt.Errorf("failed to get Secret %s: %w", req.NamespacedName, err)
+	}
+
+	// The cache only contains labeled Secrets, so a Secret which isn't found
+	// has either been deleted or is no longer labeled.
+	if apierrors.IsNotFound(err) || secret.Labels[trustapi.SecretMirrorLabelKey] != "true" {
+		if !exists || !isMirror(&configMap) {
+			return ctrl.Result{}, nil
+		}
+
+		log.V(2).Info("deleting mirror ConfigMap of unlabeled Secret")
+		if err := r.client.Delete(ctx, &configMap); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to delete ConfigMap %s: %w", req.NamespacedName, err)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if exists && !metav1.IsControlledBy(&configMap, &secret) {
+		log.Error(nil, "not mirroring Secret since a ConfigMap of the same name exists which is not its mirror")
+		return ctrl.Result{}, nil
+	}
+
+	data := mirrorData(&secret)
+	if exists && apiequality.Semantic.DeepEqual(configMap.Data, data) && len(configMap.BinaryData) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	configMap.Data = data
+	configMap.BinaryData = nil
+	if err := controllerutil.SetControllerReference(&secret, &configMap, r.scheme); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to set owner of ConfigMap %s: %w", req.NamespacedName, err)
+	}
+
+	if exists {
+		err = r.client.Update(ctx, &configMap)
+	} else {
+		err = r.client.Create(ctx, &configMap)
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to write ConfigMap %s: %w", req.NamespacedName, err)
+	}
+
+	log.V(2).Info("mirrored Secret to ConfigMap", "keys", len(data))
+	return ctrl.Result{}, nil
+}
+
+// isMirror returns true if the given ConfigMap is controlled by the Secret of
+// the same name.
+func isMirror(configMap *corev1.ConfigMap) bool {
+	owner := metav1.GetControllerOf(configMap)
+	return owner != nil && owner.APIVersion == "v1" && owner.Kind == "Secret" && owner.Name == configMap.Name
+}
+
+// mirrorData returns the certificates found in each key of the given Secret.
+// Keys which contain no certificates, such as "tls.key", are omitted.
+func mirrorData(secret *corev1.Secret) map[string]string {
+	data := make(map[string]string)
+	for key, value := range secret.Data {
+		if certificates := extractCertificates(value); len(certificates) > 0 {
+			data[key] = string(certificates)
+		}
+	}
+	return data
+}
+
+// extractCertificates returns the valid CERTIFICATE PEM blocks in the given
+// data, without headers. All other PEM blocks, including private keys, and
+// any data between blocks are stripped.
+func extractCertificates(data []byte) []byte {
+	var certificates [][]byte
+
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+		if b == nil {
+			break
+		}
+
+		if b.Type != "CERTIFICATE" {
+			continue
+		}
+		if _, err := x509.ParseCertificate(b.Bytes); err != nil {
+			continue
+		}
+
+		certificates = append(certificates, pem.EncodeToMemory(&pem.Block{Type: b.Type, Bytes: b.Bytes}))
+	}
+
+	return bytes.Join(certificates, nil)
+}
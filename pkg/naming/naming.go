@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package naming holds the conventions for the names, labels and annotations
+// of the objects which trust-manager creates. The conventions can be
+// configured at startup, so that organizations with strict naming policies
+// can comply with them.
+package naming
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+const (
+	// DefaultTargetNameTemplate names targets after their Bundle.
+	DefaultTargetNameTemplate = "{{ .Name }}"
+
+	// DefaultBundleLabelKey is the default label which is set to the name of
+	// the Bundle on objects created for it, such as ManifestWorks.
+	DefaultBundleLabelKey = "trust.cert-manager.io/bundle"
+
+	// DefaultTargetHashAnnotationKey is the default annotation written to
+	// targets with the hash of their bundle data.
+	DefaultTargetHashAnnotationKey = trustapi.TargetHashAnnotationKey
+)
+
+// Options configure the naming conventions.
+type Options struct {
+	// TargetNameTemplate is a Go template rendering the name of the targets
+	// of a Bundle. The name of the Bundle is available as `.Name`.
+	TargetNameTemplate string
+
+	// BundleLabelKey is the label which is set to the name of the Bundle on
+	// objects created for it.
+	BundleLabelKey string
+
+	// TargetHashAnnotationKey is the annotation written to targets with the
+	// hash of their bundle data.
+	TargetHashAnnotationKey string
+}
+
+// Conventions are validated naming conventions. A nil Conventions uses the
+// defaults.
+type Conventions struct {
+	targetName              *template.Template
+	bundleLabelKey          string
+	targetHashAnnotationKey string
+}
+
+// targetNameData is the data available to the target name template.
+type targetNameData struct {
+	Name string
+}
+
+// New validates the given Options and returns the Conventions they
+// configure. Unset options use the defaults.
+func New(opts Options) (*Conventions, error) {
+	if len(opts.TargetNameTemplate) == 0 {
+		opts.TargetNameTemplate = DefaultTargetNameTemplate
+	}
+	if len(opts.BundleLabelKey) == 0 {
+		opts.BundleLabelKey = DefaultBundleLabelKey
+	}
+	if len(opts.TargetHashAnnotationKey) == 0 {
+		opts.TargetHashAnnotationKey = DefaultTargetHashAnnotationKey
+	}
+
+	tmpl, err := template.New("target-name").Option("missingkey=error").Parse(opts.TargetNameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target name template %q: %w", opts.TargetNameTemplate, err)
+	}
+
+	// The template is rendered for an example Bundle, so that templates which
+	// fail to execute or render invalid names are rejected at startup.
+	var name strings.Builder
+	if err := tmpl.Execute(&name, targetNameData{Name: "example"}); err != nil {
+		return nil, fmt.Errorf("invalid target name template %q: %w", opts.TargetNameTemplate, err)
+	}
+	if errs := validation.IsDNS1123Subdomain(name.String()); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid target name template %q: rendered name %q is invalid: %s", opts.TargetNameTemplate, name.String(), strings.Join(errs, ", "))
+	}
+
+	if errs := validation.IsQualifiedName(opts.BundleLabelKey); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid bundle label key %q: %s", opts.BundleLabelKey, strings.Join(errs, ", "))
+	}
+
+	if errs := validation.IsQualifiedName(opts.TargetHashAnnotationKey); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid target hash annotation key %q: %s", opts.TargetHashAnnotationKey, strings.Join(errs, ", "))
+	}
+
+	return &Conventions{
+		targetName:              tmpl,
+		bundleLabelKey:          opts.BundleLabelKey,
+		targetHashAnnotationKey: opts.TargetHashAnnotationKey,
+	}, nil
+}
+
+// TargetName returns the name of the targets of the Bundle with the given
+// name.
+func (c *Conventions) TargetName(bundleName string) (string, error) {
+	if c == nil {
+		return bundleName, nil
+	}
+
+	var name strings.Builder
+	if err := c.targetName.Execute(&name, targetNameData{Name: bundleName}); err != nil {
+		return "", fmt.Errorf("failed to render target name of Bundle %q: %w", bundleName, err)
+	}
+
+	return name.String(), nil
+}
+
+// BundleTargetName returns the name of the targets of the Bundle with the
+// given name and target. This is the name set on the target's ConfigMap, or
+// otherwise the name rendered from the Bundle's name.
+func (c *Conventions) BundleTargetName(bundleName string, target trustapi.BundleTarget) (string, error) {
+	if target.ConfigMap != nil && len(target.ConfigMap.Name) > 0 {
+		return target.ConfigMap.Name, nil
+	}
+
+	return c.TargetName(bundleName)
+}
+
+// BundleLabelKey returns the label which is set to the name of the Bundle on
+// objects created for it.
+func (c *Conventions) BundleLabelKey() string {
+	if c == nil {
+		return DefaultBundleLabelKey
+	}
+	return c.bundleLabelKey
+}
+
+// TargetHashAnnotationKey returns the annotation written to targets with the
+// hash of their bundle data.
+func (c *Conventions) TargetHashAnnotationKey() string {
+	if c == nil {
+		return DefaultTargetHashAnnotationKey
+	}
+	return c.targetHashAnnotationKey
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package naming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_New(t *testing.T) {
+	tests := map[string]struct {
+		opts Options
+
+		expTargetName              string
+		expBundleLabelKey          string
+		expTargetHashAnnotationKey string
+		expError                   bool
+	}{
+		"unset options should use the defaults": {
+			expTargetName:              "my-bundle",
+			expBundleLabelKey:          DefaultBundleLabelKey,
+			expTargetHashAnnotationKey: DefaultTargetHashAnnotationKey,
+		},
+		"custom options should be used": {
+			opts: Options{
+				TargetNameTemplate:      "corp-{{ .Name }}-trust",
+				BundleLabelKey:          "example.com/bundle",
+				TargetHashAnnotationKey: "example.com/bundle-hash",
+			},
+			expTargetName:              "corp-my-bundle-trust",
+			expBundleLabelKey:          "example.com/bundle",
+			expTargetHashAnnotationKey: "example.com/bundle-hash",
+		},
+		"unparseable target name template should error": {
+			opts:     Options{TargetNameTemplate: "{{ .Name "},
+			expError: true,
+		},
+		"target name template referencing unknown fields should error": {
+			opts:     Options{TargetNameTemplate: "{{ .Namespace }}"},
+			expError: true,
+		},
+		"target name template rendering an invalid name should error": {
+			opts:     Options{TargetNameTemplate: "Trust_{{ .Name }}"},
+			expError: true,
+		},
+		"invalid bundle label key should error": {
+			opts:     Options{BundleLabelKey: "example.com/bundle/name"},
+			expError: true,
+		},
+		"invalid target hash annotation key should error": {
+			opts:     Options{TargetHashAnnotationKey: "-hash"},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			conventions, err := New(test.opts)
+			if test.expError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			targetName, err := conventions.TargetName("my-bundle")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expTargetName, targetName)
+			assert.Equal(t, test.expBundleLabelKey, conventions.BundleLabelKey())
+			assert.Equal(t, test.expTargetHashAnnotationKey, conventions.TargetHashAnnotationKey())
+		})
+	}
+}
+
+func Test_NilConventions(t *testing.T) {
+	var conventions *Conventions
+
+	targetName, err := conventions.TargetName("my-bundle")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-bundle", targetName)
+	assert.Equal(t, DefaultBundleLabelKey, conventions.BundleLabelKey())
+	assert.Equal(t, DefaultTargetHashAnnotationKey, conventions.TargetHashAnnotationKey())
+}
+
+func Test_BundleTargetName(t *testing.T) {
+	conventions, err := New(Options{TargetNameTemplate: "trust-{{ .Name }}"})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		target trustapi.BundleTarget
+
+		expTargetName string
+	}{
+		"no ConfigMap target should render the name from the template": {
+			target:        trustapi.BundleTarget{},
+			expTargetName: "trust-my-bundle",
+		},
+		"ConfigMap target without name should render the name from the template": {
+			target:        trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			expTargetName: "trust-my-bundle",
+		},
+		"ConfigMap target with name should use the name": {
+			target:        trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Name: "ca-certificates", Key: "ca.crt"}},
+			expTargetName: "ca-certificates",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			targetName, err := conventions.BundleTargetName("my-bundle", test.target)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expTargetName, targetName)
+		})
+	}
+}
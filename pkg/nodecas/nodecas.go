@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodecas publishes the system CA bundle of a node's operating system
+// into the trust Namespace, where Bundles can include it using the
+// useNodeOSCAs source. It is run by the "trust-manager publish-node-cas"
+// agent, which is pinned to the designated node and mounts the host's CA
+// certificate directory.
+package nodecas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the trust Namespace which
+	// the node's system CA bundle is published to.
+	ConfigMapName = "trust-manager-node-os-cas"
+
+	// Key is the key of the system CA bundle in the ConfigMapName ConfigMap.
+	Key = "ca-certificates.crt"
+
+	// NodeAnnotationKey is set on the ConfigMapName ConfigMap to the name of
+	// the node whose system CA bundle was published.
+	NodeAnnotationKey = "trust.cert-manager.io/node"
+
+	// PathAnnotationKey is set on the ConfigMapName ConfigMap to the path on
+	// the node which the system CA bundle was read from.
+	PathAnnotationKey = "trust.cert-manager.io/node-path"
+)
+
+// DefaultBundlePaths are the locations of the system CA bundle used by common
+// Linux distributions, in the order they are tried.
+var DefaultBundlePaths = []string{
+	"/etc/ssl/certs/ca-certificates.crt",                // Debian, Ubuntu, Alpine, Arch
+	"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem", // Fedora, RHEL 7+
+	"/etc/pki/tls/certs/ca-bundle.crt",                  // Older Fedora, RHEL, CentOS
+	"/etc/ssl/ca-bundle.pem",                            // openSUSE, SLES
+	"/etc/ssl/cert.pem",                                 // Flatcar, Bottlerocket
+}
+
+// ReadBundle reads the system CA bundle from the first of the given paths
+// which exists, relative to root. The bundle is validated and sanitized, so
+// that a broken host bundle is never published. The path which the bundle was
+// read from is returned along with the bundle.
+func ReadBundle(root string, paths []string) ([]byte, string, error) {
+	for _, path := range paths {
+		data, err := os.ReadFile(root + path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read system CA bundle %q: %w", path, err)
+		}
+
+		sanitized, err := util.ValidateAndSanitizePEMBundle(data)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid system CA bundle %q: %w", path, err)
+		}
+
+		return sanitized, path, nil
+	}
+
+	return nil, "", fmt.Errorf("no system CA bundle found at any of %q", paths)
+}
+
+// Publish writes the given system CA bundle of the named node to the
+// ConfigMapName ConfigMap in the given Namespace, creating the ConfigMap if
+// it doesn't exist. Returns true if the ConfigMap was created or updated.
+func Publish(ctx context.Context, cl client.Client, namespace, nodeName, path string, data []byte) (bool, error) {
+	var configMap corev1.ConfigMap
+	err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ConfigMapName}, &configMap)
+	if apierrors.IsNotFound(err) {
+		configMap = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ConfigMapName,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					NodeAnnotationKey: nodeName,
+					PathAnnotationKey: path,
+				},
+			},
+			Data: map[string]string{Key: string(data)},
+		}
+		if err := cl.Create(ctx, &configMap); err != nil {
+			return false, fmt.Errorf("failed to create ConfigMap %s/%s: %w", namespace, ConfigMapName, err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, ConfigMapName, err)
+	}
+
+	if configMap.Data[Key] == string(data) &&
+		configMap.Annotations[NodeAnnotationKey] == nodeName &&
+		configMap.Annotations[PathAnnotationKey] == path {
+		return false, nil
+	}
+
+	if configMap.Annotations == nil {
+		configMap.Annotations = make(map[string]string)
+	}
+	configMap.Annotations[NodeAnnotationKey] = nodeName
+	configMap.Annotations[PathAnnotationKey] = path
+	configMap.Data = map[string]string{Key: string(data)}
+
+	if err := cl.Update(ctx, &configMap); err != nil {
+		return false, fmt.Errorf("failed to update ConfigMap %s/%s: %w", namespace, ConfigMapName, err)
+	}
+
+	return true, nil
+}
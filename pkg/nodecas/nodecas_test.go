@@ -0,0 +1,170 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodecas
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_ReadBundle(t *testing.T) {
+	tests := map[string]struct {
+		files map[string]string
+
+		expData  string
+		expPath  string
+		expError bool
+	}{
+		"no bundle at any path should error": {
+			files:    map[string]string{"/etc/other.crt": dummy.TestCertificate1},
+			expError: true,
+		},
+		"first existing path should be read": {
+			files: map[string]string{
+				"/etc/pki/tls/certs/ca-bundle.crt": dummy.TestCertificate1,
+				"/etc/ssl/cert.pem":                dummy.TestCertificate2,
+			},
+			expData: strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1)),
+			expPath: "/etc/pki/tls/certs/ca-bundle.crt",
+		},
+		"bundle should be sanitized": {
+			files: map[string]string{
+				"/etc/ssl/certs/ca-certificates.crt": "# Comment\n" + dummy.TestCertificate1 + "\n\n" + dummy.TestCertificate2,
+			},
+			expData: strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)),
+			expPath: "/etc/ssl/certs/ca-certificates.crt",
+		},
+		"invalid bundle should error rather than fall back to a later path": {
+			files: map[string]string{
+				"/etc/ssl/certs/ca-certificates.crt": "not a certificate",
+				"/etc/ssl/cert.pem":                  dummy.TestCertificate2,
+			},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			for path, data := range test.files {
+				if err := os.MkdirAll(filepath.Dir(root+path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(root+path, []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			data, path, err := ReadBundle(root, DefaultBundlePaths)
+			assert.Equal(t, test.expError, err != nil, "%v", err)
+			assert.Equal(t, test.expData, string(data))
+			assert.Equal(t, test.expPath, path)
+		})
+	}
+}
+
+func Test_Publish(t *testing.T) {
+	const (
+		namespace = "trust-namespace"
+		node      = "node-1"
+		path      = "/etc/ssl/certs/ca-certificates.crt"
+	)
+
+	data := dummy.JoinCerts(dummy.TestCertificate1)
+
+	tests := map[string]struct {
+		existing *corev1.ConfigMap
+
+		expPublished bool
+	}{
+		"missing ConfigMap should be created": {
+			expPublished: true,
+		},
+		"ConfigMap with different data should be updated": {
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ConfigMapName,
+					Namespace:   namespace,
+					Annotations: map[string]string{NodeAnnotationKey: node, PathAnnotationKey: path},
+				},
+				Data: map[string]string{Key: dummy.JoinCerts(dummy.TestCertificate2), "other": "data"},
+			},
+			expPublished: true,
+		},
+		"ConfigMap of a different node should be updated": {
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ConfigMapName,
+					Namespace:   namespace,
+					Annotations: map[string]string{NodeAnnotationKey: "node-2", PathAnnotationKey: path},
+				},
+				Data: map[string]string{Key: data},
+			},
+			expPublished: true,
+		},
+		"up to date ConfigMap should not be updated": {
+			existing: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        ConfigMapName,
+					Namespace:   namespace,
+					Annotations: map[string]string{NodeAnnotationKey: node, PathAnnotationKey: path},
+				},
+				Data: map[string]string{Key: data},
+			},
+			expPublished: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var objects []runtime.Object
+			if test.existing != nil {
+				objects = append(objects, test.existing)
+			}
+
+			cl := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(objects...).
+				Build()
+
+			published, err := Publish(context.TODO(), cl, namespace, node, path, []byte(data))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expPublished, published)
+
+			var configMap corev1.ConfigMap
+			if err := cl.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: ConfigMapName}, &configMap); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, map[string]string{Key: data}, configMap.Data)
+			assert.Equal(t, node, configMap.Annotations[NodeAnnotationKey])
+			assert.Equal(t, path, configMap.Annotations[PathAnnotationKey])
+		})
+	}
+}
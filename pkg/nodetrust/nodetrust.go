@@ -0,0 +1,184 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nodetrust writes the content of Bundles into the trust store of a
+// node's operating system, so that container runtimes and host services trust
+// the same anchors as workloads. It is run by the "trust-manager
+// write-node-trust" agent, which runs on every node as a DaemonSet and mounts
+// the host's trust anchor directory.
+package nodetrust
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// FilePrefix is the prefix of the names of the files written to the host's
+// trust anchor directory, so that files written for Bundles which are no
+// longer selected can be removed without touching other anchors.
+const FilePrefix = "trust-manager-"
+
+// Layout is a layout of a host operating system's trust store.
+type Layout struct {
+	// Directory is the directory on the host which trust anchors are read
+	// from when the host's trust store is updated.
+	Directory string
+
+	// Extension is the file extension which the host's trust store tooling
+	// requires of trust anchors.
+	Extension string
+}
+
+// Layouts are the trust store layouts of common Linux distributions. After
+// anchors are written, the host regenerates its trust store, such as
+// /etc/ssl/certs, using the tool named in the comment.
+var Layouts = map[string]Layout{
+	// Debian, Ubuntu, Alpine: update-ca-certificates
+	"debian": {Directory: "/usr/local/share/ca-certificates", Extension: ".crt"},
+	// Fedora, RHEL, CentOS: update-ca-trust
+	"rhel": {Directory: "/etc/pki/ca-trust/source/anchors", Extension: ".pem"},
+}
+
+// ReadBundles reads the data of the targets of the named Bundles in the given
+// Namespace, keyed by the name of the Bundle. The data is validated and
+// sanitized, so that a broken target is never written to the host.
+func ReadBundles(ctx context.Context, cl client.Reader, conventions *naming.Conventions, namespace string, bundleNames []string) (map[string][]byte, error) {
+	bundles := make(map[string][]byte, len(bundleNames))
+	for _, name := range bundleNames {
+		var bundle trustapi.Bundle
+		if err := cl.Get(ctx, client.ObjectKey{Name: name}, &bundle); err != nil {
+			return nil, fmt.Errorf("failed to get Bundle %q: %w", name, err)
+		}
+
+		if bundle.Spec.Target.ConfigMap == nil {
+			return nil, fmt.Errorf("no ConfigMap target defined for Bundle %q", name)
+		}
+
+		targetName, err := conventions.BundleTargetName(bundle.Name, bundle.Spec.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		var configMap corev1.ConfigMap
+		if err := cl.Get(ctx, client.ObjectKey{Namespace: namespace, Name: targetName}, &configMap); err != nil {
+			return nil, fmt.Errorf("failed to get target ConfigMap %s/%s of Bundle %q: %w", namespace, targetName, name, err)
+		}
+
+		data, ok := util.TargetData(&configMap, bundle.Spec.Target.ConfigMap.Key, bundle.Spec.Target.ConfigMap.Format)
+		if !ok {
+			return nil, fmt.Errorf("target ConfigMap %s/%s of Bundle %q has no key %q", namespace, targetName, name, bundle.Spec.Target.ConfigMap.Key)
+		}
+
+		sanitized, err := util.ValidateAndSanitizePEMBundle([]byte(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid target of Bundle %q: %w", name, err)
+		}
+
+		bundles[name] = sanitized
+	}
+
+	return bundles, nil
+}
+
+// Write writes the given bundles, keyed by the name of the Bundle, into the
+// given layout's directory relative to root. Files written for Bundles which
+// aren't given are removed. Files are replaced atomically, so that the host
+// never reads a partially written anchor. Returns the sorted paths of the
+// files which were written or removed.
+func Write(root string, layout Layout, bundles map[string][]byte) ([]string, error) {
+	dir := filepath.Join(root, layout.Directory)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust anchor directory %q: %w", layout.Directory, err)
+	}
+
+	var changed []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, FilePrefix) || !strings.HasSuffix(name, layout.Extension) {
+			continue
+		}
+
+		if _, ok := bundles[strings.TrimSuffix(strings.TrimPrefix(name, FilePrefix), layout.Extension)]; ok {
+			continue
+		}
+
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale trust anchor %q: %w", name, err)
+		}
+		changed = append(changed, filepath.Join(layout.Directory, name))
+	}
+
+	for bundleName, data := range bundles {
+		name := FilePrefix + bundleName + layout.Extension
+		path := filepath.Join(dir, name)
+
+		existing, err := os.ReadFile(path)
+		if err == nil && string(existing) == string(data) {
+			continue
+		}
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read trust anchor %q: %w", name, err)
+		}
+
+		if err := writeFileAtomic(dir, name, data); err != nil {
+			return nil, fmt.Errorf("failed to write trust anchor %q: %w", name, err)
+		}
+		changed = append(changed, filepath.Join(layout.Directory, name))
+	}
+
+	sort.Strings(changed)
+
+	return changed, nil
+}
+
+// writeFileAtomic writes data to the named file in dir by renaming a
+// temporary file over it.
+func writeFileAtomic(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nodetrust
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func Test_ReadBundles(t *testing.T) {
+	const namespace = "trust-namespace"
+
+	bundle := func(name string, target *trustapi.TargetKeySelector) runtime.Object {
+		return &trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: target}},
+		}
+	}
+	configMap := func(name string, data map[string]string) runtime.Object {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Data:       data,
+		}
+	}
+
+	tests := map[string]struct {
+		objects []runtime.Object
+
+		expBundles map[string]string
+		expError   bool
+	}{
+		"targets of all Bundles should be read": {
+			objects: []runtime.Object{
+				bundle("bundle-a", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				bundle("bundle-b", &trustapi.TargetKeySelector{Name: "ca-certificates", Key: "trust.pem"}),
+				configMap("bundle-a", map[string]string{"ca.crt": "# Comment\n" + dummy.TestCertificate1}),
+				configMap("ca-certificates", map[string]string{"trust.pem": dummy.TestCertificate2}),
+			},
+			expBundles: map[string]string{
+				"bundle-a": strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1)),
+				"bundle-b": strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate2)),
+			},
+		},
+		"missing Bundle should error": {
+			objects: []runtime.Object{
+				bundle("bundle-a", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				configMap("bundle-a", map[string]string{"ca.crt": dummy.TestCertificate1}),
+			},
+			expError: true,
+		},
+		"missing target key should error": {
+			objects: []runtime.Object{
+				bundle("bundle-a", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				bundle("bundle-b", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				configMap("bundle-a", map[string]string{"ca.crt": dummy.TestCertificate1}),
+				configMap("bundle-b", map[string]string{"other.crt": dummy.TestCertificate2}),
+			},
+			expError: true,
+		},
+		"invalid target should error": {
+			objects: []runtime.Object{
+				bundle("bundle-a", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				bundle("bundle-b", &trustapi.TargetKeySelector{Key: "ca.crt"}),
+				configMap("bundle-a", map[string]string{"ca.crt": dummy.TestCertificate1}),
+				configMap("bundle-b", map[string]string{"ca.crt": "not a certificate"}),
+			},
+			expError: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cl := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(test.objects...).
+				Build()
+
+			bundles, err := ReadBundles(context.TODO(), cl, nil, namespace, []string{"bundle-a", "bundle-b"})
+			assert.Equal(t, test.expError, err != nil, "%v", err)
+
+			var gotBundles map[string]string
+			for name, data := range bundles {
+				if gotBundles == nil {
+					gotBundles = make(map[string]string)
+				}
+				gotBundles[name] = string(data)
+			}
+			assert.Equal(t, test.expBundles, gotBundles)
+		})
+	}
+}
+
+func Test_Write(t *testing.T) {
+	layout := Layouts["debian"]
+
+	tests := map[string]struct {
+		files   map[string]string
+		bundles map[string]string
+
+		expFiles   map[string]string
+		expChanged []string
+	}{
+		"bundles should be written to the anchor directory": {
+			bundles: map[string]string{"bundle-a": dummy.TestCertificate1, "bundle-b": dummy.TestCertificate2},
+			expFiles: map[string]string{
+				"trust-manager-bundle-a.crt": dummy.TestCertificate1,
+				"trust-manager-bundle-b.crt": dummy.TestCertificate2,
+			},
+			expChanged: []string{
+				"/usr/local/share/ca-certificates/trust-manager-bundle-a.crt",
+				"/usr/local/share/ca-certificates/trust-manager-bundle-b.crt",
+			},
+		},
+		"unchanged bundles should not be rewritten": {
+			files:      map[string]string{"trust-manager-bundle-a.crt": dummy.TestCertificate1},
+			bundles:    map[string]string{"bundle-a": dummy.TestCertificate1},
+			expFiles:   map[string]string{"trust-manager-bundle-a.crt": dummy.TestCertificate1},
+			expChanged: nil,
+		},
+		"changed bundles should be rewritten": {
+			files:      map[string]string{"trust-manager-bundle-a.crt": dummy.TestCertificate1},
+			bundles:    map[string]string{"bundle-a": dummy.TestCertificate2},
+			expFiles:   map[string]string{"trust-manager-bundle-a.crt": dummy.TestCertificate2},
+			expChanged: []string{"/usr/local/share/ca-certificates/trust-manager-bundle-a.crt"},
+		},
+		"stale anchors should be removed without touching other anchors": {
+			files: map[string]string{
+				"trust-manager-bundle-a.crt": dummy.TestCertificate1,
+				"trust-manager-bundle-b.crt": dummy.TestCertificate2,
+				"corporate-root.crt":         dummy.TestCertificate3,
+				"trust-manager-notes.txt":    "notes",
+			},
+			bundles: map[string]string{"bundle-a": dummy.TestCertificate1},
+			expFiles: map[string]string{
+				"trust-manager-bundle-a.crt": dummy.TestCertificate1,
+				"corporate-root.crt":         dummy.TestCertificate3,
+				"trust-manager-notes.txt":    "notes",
+			},
+			expChanged: []string{"/usr/local/share/ca-certificates/trust-manager-bundle-b.crt"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			dir := filepath.Join(root, layout.Directory)
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for name, data := range test.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			bundles := make(map[string][]byte, len(test.bundles))
+			for name, data := range test.bundles {
+				bundles[name] = []byte(data)
+			}
+
+			changed, err := Write(root, layout, bundles)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expChanged, changed)
+
+			entries, err := os.ReadDir(dir)
+			assert.NoError(t, err)
+
+			files := make(map[string]string, len(entries))
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+				assert.NoError(t, err)
+				files[entry.Name()] = string(data)
+			}
+			assert.Equal(t, test.expFiles, files)
+		})
+	}
+}
+
+func Test_WriteMissingDirectory(t *testing.T) {
+	_, err := Write(t.TempDir(), Layouts["rhel"], map[string][]byte{"bundle-a": []byte(dummy.TestCertificate1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"bytes"
+	"fmt"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
+)
+
+// Options describe the trust-manager controller configuration which RBAC
+// manifests are generated for.
+type Options struct {
+	// Name is the name given to all generated RBAC objects. It must match the
+	// name of the ServiceAccount trust-manager runs as.
+	Name string
+
+	// Namespace is the Namespace of the ServiceAccount trust-manager runs as.
+	Namespace string
+
+	// TrustNamespace is the trust Namespace that source data is read from,
+	// and where leader election takes place.
+	TrustNamespace string
+
+	// ClusterPlacement is whether trust-manager distributes Bundles to managed
+	// clusters using Open Cluster Management.
+	ClusterPlacement bool
+
+	// CAInjection is whether trust-manager writes the data of Bundles to the
+	// caBundle fields of annotated webhook configurations,
+	// CustomResourceDefinitions and APIServices.
+	CAInjection bool
+
+	// ConsumerRestart is whether trust-manager rolls out the pods of annotated
+	// workloads whenever the Bundles they consume change.
+	ConsumerRestart bool
+}
+
+// Generate returns the minimal set of RBAC objects required for trust-manager
+// to run with the given configuration.
+func Generate(opts Options) []client.Object {
+	subjects := []rbacv1.Subject{{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      opts.Name,
+		Namespace: opts.Namespace,
+	}}
+
+	return []client.Object{
+		&rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+			Rules:      clusterRules(opts),
+		},
+		&rbacv1.ClusterRoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: opts.Name},
+			Subjects:   subjects,
+		},
+		&rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.TrustNamespace},
+			Rules:      trustNamespaceRules(opts),
+		},
+		&rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Name: opts.Name, Namespace: opts.TrustNamespace},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: opts.Name},
+			Subjects:   subjects,
+		},
+	}
+}
+
+// clusterRules returns the cluster scoped rules required by trust-manager.
+func clusterRules(opts Options) []rbacv1.PolicyRule {
+	rules := []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{trust.GroupName},
+			Resources: []string{"bundles"},
+			Verbs:     []string{"get", "list", "watch", "update"},
+		},
+		// Permissions to update finalizers are required for trust-manager to
+		// work correctly on OpenShift.
+		{
+			APIGroups: []string{trust.GroupName},
+			Resources: []string{"bundles/finalizers"},
+			Verbs:     []string{"update"},
+		},
+		{
+			APIGroups: []string{trust.GroupName},
+			Resources: []string{"bundles/status"},
+			Verbs:     []string{"update"},
+		},
+		{
+			APIGroups: []string{trust.GroupName},
+			Resources: []string{"bundlechecks"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{trust.GroupName},
+			Resources: []string{"bundlechecks/status"},
+			Verbs:     []string{"update"},
+		},
+		// ConfigMaps are Bundle targets in all Namespaces.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"configmaps"},
+			Verbs:     []string{"get", "list", "create", "update", "watch", "delete"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"namespaces"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{""},
+			Resources: []string{"events"},
+			Verbs:     []string{"create", "patch"},
+		},
+		// The installed Bundle CRD is checked for compatibility at startup.
+		{
+			APIGroups:     []string{"apiextensions.k8s.io"},
+			Resources:     []string{"customresourcedefinitions"},
+			ResourceNames: []string{"bundles." + trust.GroupName},
+			Verbs:         []string{"get"},
+		},
+		// Permission checks requested on Bundles are made with
+		// SelfSubjectAccessReviews.
+		{
+			APIGroups: []string{"authorization.k8s.io"},
+			Resources: []string{"selfsubjectaccessreviews"},
+			Verbs:     []string{"create"},
+		},
+	}
+
+	if opts.ClusterPlacement {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{"cluster.open-cluster-management.io"},
+				Resources: []string{"placementdecisions"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+			// ManifestWorks distribute Bundles to the Namespaces of managed
+			// clusters.
+			rbacv1.PolicyRule{
+				APIGroups: []string{"work.open-cluster-management.io"},
+				Resources: []string{"manifestworks"},
+				Verbs:     []string{"get", "list", "create", "update", "watch", "delete"},
+			},
+		)
+	}
+
+	if opts.CAInjection {
+		rules = append(rules,
+			rbacv1.PolicyRule{
+				APIGroups: []string{"admissionregistration.k8s.io"},
+				Resources: []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"apiextensions.k8s.io"},
+				Resources: []string{"customresourcedefinitions"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+			rbacv1.PolicyRule{
+				APIGroups: []string{"apiregistration.k8s.io"},
+				Resources: []string{"apiservices"},
+				Verbs:     []string{"get", "list", "watch", "update"},
+			},
+		)
+	}
+
+	if opts.ConsumerRestart {
+		// Workloads are rolled out by patching their pod template.
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{"apps"},
+			Resources: []string{"deployments", "statefulsets", "daemonsets"},
+			Verbs:     []string{"get", "list", "watch", "patch"},
+		})
+	}
+
+	return rules
+}
+
+// trustNamespaceRules returns the rules required by trust-manager in the trust
+// Namespace.
+func trustNamespaceRules(_ Options) []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		// Secrets are only ever read as sources from the trust Namespace.
+		{
+			APIGroups: []string{""},
+			Resources: []string{"secrets"},
+			Verbs:     []string{"get", "list", "watch"},
+		},
+		{
+			APIGroups: []string{"coordination.k8s.io"},
+			Resources: []string{"leases"},
+			Verbs:     []string{"get", "create", "update", "watch", "list"},
+		},
+	}
+}
+
+// Encode returns the given objects as a multi-document YAML stream.
+func Encode(objs []client.Object) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, obj := range objs {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %T %q: %w", obj, obj.GetName(), err)
+		}
+
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(data)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_Generate(t *testing.T) {
+	objs := Generate(Options{Name: "trust-manager", Namespace: "install-ns", TrustNamespace: "trust-ns"})
+	if !assert.Len(t, objs, 4) {
+		return
+	}
+
+	clusterRole := objs[0].(*rbacv1.ClusterRole)
+	assert.True(t, hasRule(clusterRole.Rules, "selfsubjectaccessreviews", "create"),
+		"permission checks must be able to create SelfSubjectAccessReviews")
+
+	clusterRoleBinding := objs[1].(*rbacv1.ClusterRoleBinding)
+	assert.Equal(t, "ClusterRole", clusterRoleBinding.RoleRef.Kind)
+	assert.Equal(t, []rbacv1.Subject{{Kind: "ServiceAccount", Name: "trust-manager", Namespace: "install-ns"}}, clusterRoleBinding.Subjects)
+
+	role := objs[2].(*rbacv1.Role)
+	assert.Equal(t, "trust-ns", role.Namespace)
+	assert.Equal(t, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list", "watch"}}, role.Rules[0],
+		"secrets must only be readable in the trust namespace")
+
+	roleBinding := objs[3].(*rbacv1.RoleBinding)
+	assert.Equal(t, "trust-ns", roleBinding.Namespace)
+	assert.Equal(t, "Role", roleBinding.RoleRef.Kind)
+	assert.Equal(t, clusterRoleBinding.Subjects, roleBinding.Subjects)
+}
+
+func Test_Generate_clusterPlacement(t *testing.T) {
+	hasManifestWorks := func(rules []rbacv1.PolicyRule) bool {
+		for _, rule := range rules {
+			for _, resource := range rule.Resources {
+				if resource == "manifestworks" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	objs := Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager"})
+	assert.False(t, hasManifestWorks(objs[0].(*rbacv1.ClusterRole).Rules), "ManifestWork permissions must only be granted with cluster placement")
+
+	objs = Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager", ClusterPlacement: true})
+	assert.True(t, hasManifestWorks(objs[0].(*rbacv1.ClusterRole).Rules))
+}
+
+func Test_Generate_caInjection(t *testing.T) {
+	objs := Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager"})
+	rules := objs[0].(*rbacv1.ClusterRole).Rules
+	for _, resource := range []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations", "customresourcedefinitions", "apiservices"} {
+		assert.False(t, hasRule(rules, resource, "update"), "%s must only be updatable with CA injection", resource)
+	}
+
+	objs = Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager", CAInjection: true})
+	rules = objs[0].(*rbacv1.ClusterRole).Rules
+	for _, resource := range []string{"validatingwebhookconfigurations", "mutatingwebhookconfigurations", "customresourcedefinitions", "apiservices"} {
+		assert.True(t, hasRule(rules, resource, "watch"), resource)
+		assert.True(t, hasRule(rules, resource, "update"), resource)
+	}
+}
+
+func Test_Generate_consumerRestart(t *testing.T) {
+	objs := Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager"})
+	rules := objs[0].(*rbacv1.ClusterRole).Rules
+	for _, resource := range []string{"deployments", "statefulsets", "daemonsets"} {
+		assert.False(t, hasRule(rules, resource, "patch"), "%s must only be patchable with consumer restarts", resource)
+	}
+
+	objs = Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager", ConsumerRestart: true})
+	rules = objs[0].(*rbacv1.ClusterRole).Rules
+	for _, resource := range []string{"deployments", "statefulsets", "daemonsets"} {
+		assert.True(t, hasRule(rules, resource, "watch"), resource)
+		assert.True(t, hasRule(rules, resource, "patch"), resource)
+	}
+}
+
+// hasRule returns true if any of the given rules grants the verb on the
+// resource.
+func hasRule(rules []rbacv1.PolicyRule, resource, verb string) bool {
+	for _, rule := range rules {
+		for _, r := range rule.Resources {
+			if r != resource {
+				continue
+			}
+			for _, v := range rule.Verbs {
+				if v == verb {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func Test_Encode(t *testing.T) {
+	objs := Generate(Options{Name: "trust-manager", Namespace: "cert-manager", TrustNamespace: "cert-manager"})
+
+	data, err := Encode(objs)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	docs := strings.Split(string(data), "---\n")
+	if !assert.Len(t, docs, len(objs)) {
+		return
+	}
+
+	var clusterRole rbacv1.ClusterRole
+	if err := yaml.Unmarshal([]byte(docs[0]), &clusterRole); err != nil {
+		t.Fatalf("failed to decode ClusterRole: %s", err)
+	}
+	assert.Equal(t, objs[0].(*rbacv1.ClusterRole).Rules, clusterRole.Rules)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package storagemigration migrates the stored objects of trust-manager's
+// CustomResourceDefinitions to the storage version of the installed CRDs.
+// Objects are rewritten without changes, so that the API server stores them
+// in the current storage version, after which older versions are removed
+// from the CRD's stored versions. Once migrated, versions which are no longer
+// stored can be dropped from the CRDs without manual intervention.
+package storagemigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cert-manager/trust-manager/pkg/apis/trust"
+)
+
+// CRDNames are the names of the trust-manager CustomResourceDefinitions whose
+// objects are migrated.
+var CRDNames = []string{
+	"bundles." + trust.GroupName,
+	"bundlechecks." + trust.GroupName,
+}
+
+// listChunkSize is the number of objects listed per request, so that
+// clusters with many objects aren't listed at once.
+const listChunkSize = 500
+
+// crdGVK is the GroupVersionKind of CustomResourceDefinitions, which are read
+// as unstructured objects.
+var crdGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// Result is the result of migrating the objects of a single CRD.
+type Result struct {
+	// CRD is the name of the migrated CRD.
+	CRD string
+
+	// StorageVersion is the storage version of the CRD.
+	StorageVersion string
+
+	// Migrated is the number of objects which were rewritten.
+	Migrated int
+
+	// UpToDate is true if all objects were already stored in the storage
+	// version, so that nothing was rewritten.
+	UpToDate bool
+}
+
+// Migrate rewrites all objects of the CRD with the given name, so that they
+// are stored in the CRD's storage version, and then sets the CRD's stored
+// versions to only the storage version. Objects which are deleted or
+// concurrently modified while migrating don't need to be rewritten, since
+// concurrent writes already store them in the storage version. Migrating is
+// idempotent, so a failed migration may be retried.
+func Migrate(ctx context.Context, c client.Client, log logr.Logger, crdName string) (Result, error) {
+	result := Result{CRD: crdName}
+
+	crd := new(unstructured.Unstructured)
+	crd.SetGroupVersionKind(crdGVK)
+	if err := c.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
+		return result, fmt.Errorf("failed to get CRD %q: %w", crdName, err)
+	}
+
+	gvk, err := storageGVK(crd)
+	if err != nil {
+		return result, fmt.Errorf("invalid CRD %q: %w", crdName, err)
+	}
+	result.StorageVersion = gvk.Version
+
+	storedVersions, _, err := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+	if err != nil {
+		return result, fmt.Errorf("invalid stored versions of CRD %q: %w", crdName, err)
+	}
+	if len(storedVersions) == 1 && storedVersions[0] == gvk.Version {
+		result.UpToDate = true
+		return result, nil
+	}
+
+	log = log.WithValues("crd", crdName, "storage_version", gvk.Version)
+	log.Info("migrating stored objects", "stored_versions", storedVersions)
+
+	list := new(unstructured.UnstructuredList)
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	for {
+		if err := c.List(ctx, list, client.Limit(listChunkSize), client.Continue(list.GetContinue())); err != nil {
+			return result, fmt.Errorf("failed to list objects of CRD %q: %w", crdName, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			err := c.Update(ctx, obj)
+			if apierrors.IsNotFound(err) || apierrors.IsConflict(err) {
+				log.V(2).Info("object was deleted or modified while migrating", "name", obj.GetName(), "namespace", obj.GetNamespace())
+				continue
+			}
+			if err != nil {
+				return result, fmt.Errorf("failed to migrate %s %q: %w", gvk.Kind, client.ObjectKeyFromObject(obj), err)
+			}
+
+			result.Migrated++
+		}
+
+		if len(list.GetContinue()) == 0 {
+			break
+		}
+	}
+
+	if err := unstructured.SetNestedStringSlice(crd.Object, []string{gvk.Version}, "status", "storedVersions"); err != nil {
+		return result, fmt.Errorf("failed to set stored versions of CRD %q: %w", crdName, err)
+	}
+	if err := c.Status().Update(ctx, crd); err != nil {
+		return result, fmt.Errorf("failed to update stored versions of CRD %q: %w", crdName, err)
+	}
+
+	log.Info("migrated stored objects", "migrated", result.Migrated)
+
+	return result, nil
+}
+
+// storageGVK returns the GroupVersionKind of the storage version of the given
+// CRD.
+func storageGVK(crd *unstructured.Unstructured) (schema.GroupVersionKind, error) {
+	group, _, err := unstructured.NestedString(crd.Object, "spec", "group")
+	if err != nil || len(group) == 0 {
+		return schema.GroupVersionKind{}, errors.New("group is not defined")
+	}
+
+	kind, _, err := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	if err != nil || len(kind) == 0 {
+		return schema.GroupVersionKind{}, errors.New("kind is not defined")
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid versions: %w", err)
+	}
+
+	for _, v := range versions {
+		version, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if storage, _ := version["storage"].(bool); storage {
+			name, _ := version["name"].(string)
+			return schema.GroupVersionKind{Group: group, Version: name, Kind: kind}, nil
+		}
+	}
+
+	return schema.GroupVersionKind{}, errors.New("no storage version is defined")
+}
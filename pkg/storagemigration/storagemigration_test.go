@@ -0,0 +1,110 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package storagemigration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_Migrate(t *testing.T) {
+	const crdName = "bundles.trust.cert-manager.io"
+
+	newCRD := func(storedVersions ...any) *unstructured.Unstructured {
+		crd := &unstructured.Unstructured{Object: map[string]any{
+			"metadata": map[string]any{"name": crdName},
+			"spec": map[string]any{
+				"group": "trust.cert-manager.io",
+				"names": map[string]any{"kind": "Bundle"},
+				"versions": []any{
+					map[string]any{"name": "v1alpha1", "served": true, "storage": true},
+				},
+			},
+			"status": map[string]any{"storedVersions": storedVersions},
+		}}
+		crd.SetGroupVersionKind(crdGVK)
+		return crd
+	}
+
+	bundles := []runtime.Object{
+		&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "bundle-1"}},
+		&trustapi.Bundle{ObjectMeta: metav1.ObjectMeta{Name: "bundle-2"}},
+	}
+
+	tests := map[string]struct {
+		crd *unstructured.Unstructured
+
+		expResult         Result
+		expStoredVersions []string
+		expError          bool
+	}{
+		"CRD which only stores the storage version should not be migrated": {
+			crd:               newCRD("v1alpha1"),
+			expResult:         Result{CRD: crdName, StorageVersion: "v1alpha1", UpToDate: true},
+			expStoredVersions: []string{"v1alpha1"},
+		},
+		"CRD which stores other versions should have all objects migrated": {
+			crd:               newCRD("v1alpha0", "v1alpha1"),
+			expResult:         Result{CRD: crdName, StorageVersion: "v1alpha1", Migrated: 2},
+			expStoredVersions: []string{"v1alpha1"},
+		},
+		"CRD without a storage version should error": {
+			crd: func() *unstructured.Unstructured {
+				crd := newCRD("v1alpha0", "v1alpha1")
+				assert.NoError(t, unstructured.SetNestedSlice(crd.Object, []any{
+					map[string]any{"name": "v1alpha1", "served": true, "storage": false},
+				}, "spec", "versions"))
+				return crd
+			}(),
+			expResult:         Result{CRD: crdName},
+			expStoredVersions: []string{"v1alpha0", "v1alpha1"},
+			expError:          true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := fakeclient.NewClientBuilder().
+				WithScheme(trustapi.GlobalScheme).
+				WithRuntimeObjects(bundles...).
+				WithObjects(test.crd).
+				Build()
+
+			result, err := Migrate(context.TODO(), c, klogr.New(), crdName)
+			assert.Equal(t, test.expError, err != nil, "unexpected error: %v", err)
+			assert.Equal(t, test.expResult, result)
+
+			crd := new(unstructured.Unstructured)
+			crd.SetGroupVersionKind(crdGVK)
+			assert.NoError(t, c.Get(context.TODO(), client.ObjectKey{Name: crdName}, crd))
+
+			storedVersions, _, err := unstructured.NestedStringSlice(crd.Object, "status", "storedVersions")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expStoredVersions, storedVersions)
+		})
+	}
+}
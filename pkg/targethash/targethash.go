@@ -0,0 +1,46 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package targethash computes the hash which trust-manager writes to the
+// "trust.cert-manager.io/hash" annotation of the targets of Bundles which
+// track acknowledgments. External tools can use it to independently verify
+// that the data of a target is the data which trust-manager distributed, for
+// example to alert on drift.
+//
+// The hash is the lower case hex encoded SHA-256 digest of the bundle data
+// exactly as written to the target's key, including the trailing newline. No
+// further canonicalization is applied: trust-manager already writes bundle
+// data deterministically, with the certificates of each source sorted and the
+// sources ordered by weight. The hash is stable across trust-manager releases.
+package targethash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Sum returns the hash of the given bundle data, as written to the hash
+// annotation of a target containing the data.
+func Sum(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
+
+// Verify returns true if the given bundle data matches the given hash, as read
+// from the hash annotation of a target.
+func Verify(data, hash string) bool {
+	return Sum(data) == hash
+}
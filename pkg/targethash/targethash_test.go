@@ -0,0 +1,54 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package targethash
+
+import (
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	cases := map[string]struct {
+		data string
+
+		expHash string
+	}{
+		"empty data": {
+			data:    "",
+			expHash: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		"trailing newline is part of the data": {
+			data:    "a\n",
+			expHash: "87428fc522803d31065e7bce3cf03fe475096631e5e07bbd7a0fde60c4cf25c7",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			if hash := Sum(test.data); hash != test.expHash {
+				t.Errorf("unexpected hash, exp=%q got=%q", test.expHash, hash)
+			}
+
+			if !Verify(test.data, test.expHash) {
+				t.Errorf("expected data to match hash %q", test.expHash)
+			}
+		})
+	}
+
+	if Verify("a", Sum("a\n")) {
+		t.Error("expected data without trailing newline not to match")
+	}
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ParseFingerprint parses the given hex encoded SHA-256 certificate
+// fingerprint, which may be in upper or lower case and may separate bytes with
+// colons as printed by openssl. Returns the fingerprint in lower case without
+// separators.
+func ParseFingerprint(fingerprint string) (string, error) {
+	normalized := strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))
+
+	decoded, err := hex.DecodeString(normalized)
+	if err != nil {
+		return "", fmt.Errorf("fingerprint must be hex encoded: %w", err)
+	}
+
+	if len(decoded) != sha256.Size {
+		return "", fmt.Errorf("fingerprint must be a SHA-256 digest of %d bytes, got %d bytes", sha256.Size, len(decoded))
+	}
+
+	return normalized, nil
+}
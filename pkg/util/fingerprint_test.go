@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestParseFingerprint(t *testing.T) {
+	const fingerprint = "96bcec06264976f37460779acf28c5a7cfe8a3c0aae11a8ffcee05c0bddf08c6"
+
+	cases := map[string]struct {
+		input string
+
+		expFingerprint string
+		expErr         bool
+	}{
+		"lower case fingerprint": {
+			input:          fingerprint,
+			expFingerprint: fingerprint,
+		},
+		"upper case fingerprint with colons": {
+			input:          "96:BC:EC:06:26:49:76:F3:74:60:77:9A:CF:28:C5:A7:CF:E8:A3:C0:AA:E1:1A:8F:FC:EE:05:C0:BD:DF:08:C6",
+			expFingerprint: fingerprint,
+		},
+		"non-hex fingerprint": {
+			input:  "not a fingerprint",
+			expErr: true,
+		},
+		"SHA-1 fingerprint": {
+			input:  "cabd2a79a1076a31f21d253635cb039d4329a5e8",
+			expErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseFingerprint(test.input)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if parsed != test.expFingerprint {
+				t.Errorf("unexpected fingerprint, exp=%q got=%q", test.expFingerprint, parsed)
+			}
+		})
+	}
+}
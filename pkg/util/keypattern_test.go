@@ -0,0 +1,52 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestValidateKeyPattern(t *testing.T) {
+	cases := map[string]struct {
+		pattern string
+
+		expErr bool
+	}{
+		"literal key": {
+			pattern: "ca.crt",
+		},
+		"glob pattern": {
+			pattern: "*.crt",
+		},
+		"character class": {
+			pattern: "ca-[0-9].pem",
+		},
+		"unterminated character class": {
+			pattern: "ca-[0-9.pem",
+			expErr:  true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateKeyPattern(test.pattern)
+			if (err != nil) != test.expErr {
+				t.Errorf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+		})
+	}
+}
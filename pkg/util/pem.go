@@ -18,9 +18,13 @@ package util
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"crypto/x509"
 	"encoding/pem"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
 // ValidateAndSanitizePEMBundle strictly validates a given input PEM bundle to confirm it contains
@@ -71,24 +75,222 @@ func ValidateAndSplitPEMBundle(data []byte) ([][]byte, error) {
 			break
 		}
 
-		if b.Type != "CERTIFICATE" {
-			// only certificates are allowed in a bundle
-			return nil, fmt.Errorf("invalid PEM block in bundle: only CERTIFICATE blocks are permitted but found '%s'", b.Type)
+		if err := validateCertificateBlock(b); err != nil {
+			return nil, err
 		}
 
-		if len(b.Headers) != 0 {
-			return nil, fmt.Errorf("invalid PEM block in bundle; blocks are not permitted to have PEM headers")
+		certificates = append(certificates, pem.EncodeToMemory(b))
+	}
+
+	return certificates, nil
+}
+
+// SplitValidPEMBundle leniently splits a PEM bundle, returning the PEM blocks
+// which pass the validation of ValidateAndSplitPEMBundle, and the number of
+// PEM blocks which were skipped since they failed it.
+func SplitValidPEMBundle(data []byte) ([][]byte, int) {
+	var certificates [][]byte
+	var skipped int
+
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+
+		if b == nil {
+			break
 		}
 
-		_, err := x509.ParseCertificate(b.Bytes)
-		if err != nil {
-			// the presence of an invalid cert (including things which aren't certs)
-			// should cause the bundle to be rejected
-			return nil, fmt.Errorf("invalid PEM block in bundle; invalid PEM certificate: %w", err)
+		if err := validateCertificateBlock(b); err != nil {
+			skipped++
+			continue
 		}
 
 		certificates = append(certificates, pem.EncodeToMemory(b))
 	}
 
-	return certificates, nil
+	return certificates, skipped
+}
+
+// validateCertificateBlock returns an error if the given PEM block isn't a
+// CERTIFICATE block without headers holding a valid certificate.
+func validateCertificateBlock(b *pem.Block) error {
+	if b.Type != "CERTIFICATE" {
+		// only certificates are allowed in a bundle
+		return fmt.Errorf("invalid PEM block in bundle: only CERTIFICATE blocks are permitted but found '%s'", b.Type)
+	}
+
+	if len(b.Headers) != 0 {
+		return fmt.Errorf("invalid PEM block in bundle; blocks are not permitted to have PEM headers")
+	}
+
+	_, err := x509.ParseCertificate(b.Bytes)
+	if err != nil {
+		// the presence of an invalid cert (including things which aren't certs)
+		// should cause the bundle to be rejected
+		return fmt.Errorf("invalid PEM block in bundle; invalid PEM certificate: %w", err)
+	}
+
+	return nil
+}
+
+// isPrivateKeyBlock returns true if the given PEM block holds a private key,
+// such as a "PRIVATE KEY", "RSA PRIVATE KEY" or "ENCRYPTED PRIVATE KEY" block.
+func isPrivateKeyBlock(b *pem.Block) bool {
+	return strings.HasSuffix(b.Type, "PRIVATE KEY")
+}
+
+// ContainsPrivateKey returns true if the given PEM data contains any private
+// key blocks.
+func ContainsPrivateKey(data []byte) bool {
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+
+		if b == nil {
+			return false
+		}
+
+		if isPrivateKeyBlock(b) {
+			return true
+		}
+	}
+}
+
+// StripPrivateKeys returns the given PEM data with all private key blocks
+// removed. Any data which isn't PEM encoded is removed as well.
+func StripPrivateKeys(data []byte) []byte {
+	var stripped [][]byte
+
+	for {
+		var b *pem.Block
+		b, data = pem.Decode(data)
+
+		if b == nil {
+			break
+		}
+
+		if isPrivateKeyBlock(b) {
+			continue
+		}
+
+		stripped = append(stripped, pem.EncodeToMemory(b))
+	}
+
+	return bytes.Join(stripped, nil)
+}
+
+// SortPEMBundle returns the certificates in the given PEM bundle sorted by the
+// SHA-256 digest of their DER encoding, so that the order of the certificates
+// doesn't depend on the order they were provided in. The bundle is validated
+// as described for ValidateAndSanitizePEMBundle.
+func SortPEMBundle(data []byte) ([]byte, error) {
+	certificates, err := ValidateAndSplitPEMBundle(data)
+	if err != nil {
+		return nil, err
+	}
+
+	type digestedCertificate struct {
+		digest [sha256.Size]byte
+		pem    []byte
+	}
+
+	digested := make([]digestedCertificate, len(certificates))
+	for i, certificate := range certificates {
+		block, _ := pem.Decode(certificate)
+		digested[i] = digestedCertificate{digest: sha256.Sum256(block.Bytes), pem: certificate}
+	}
+
+	sort.SliceStable(digested, func(i, j int) bool {
+		return bytes.Compare(digested[i].digest[:], digested[j].digest[:]) < 0
+	})
+
+	for i := range digested {
+		certificates[i] = digested[i].pem
+	}
+
+	return bytes.TrimSpace(bytes.Join(certificates, nil)), nil
+}
+
+// CommentPEMBundle prefixes each certificate in the given sanitized PEM bundle
+// with comment lines stating its subject, issuer, expiry and SHA-256
+// fingerprint. Text outside of PEM blocks is ignored by PEM parsers, and the
+// comments can be removed again with StripPEMComments.
+func CommentPEMBundle(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+
+		fingerprint := sha256.Sum256(certificate.Raw)
+		hexFingerprint := make([]string, len(fingerprint))
+		for i, b := range fingerprint {
+			hexFingerprint[i] = fmt.Sprintf("%02X", b)
+		}
+
+		fmt.Fprintf(&buf, "# Subject: %s\n", certificate.Subject)
+		fmt.Fprintf(&buf, "# Issuer: %s\n", certificate.Issuer)
+		fmt.Fprintf(&buf, "# Not After: %s\n", certificate.NotAfter.UTC().Format(time.RFC3339))
+		fmt.Fprintf(&buf, "# SHA-256 Fingerprint: %s\n", strings.Join(hexFingerprint, ":"))
+		buf.Write(pem.EncodeToMemory(block))
+	}
+
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
+
+// StripPEMComments removes the comment lines, which start with "#", from the
+// given PEM bundle.
+func StripPEMComments(data []byte) []byte {
+	if !bytes.Contains(data, []byte("#")) {
+		return data
+	}
+
+	var stripped []byte
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("#")) {
+			stripped = append(stripped, line...)
+		}
+	}
+
+	return stripped
+}
+
+// EncodeDERBundle returns the concatenated DER encoding of the certificates
+// in the given PEM bundle, in the same order.
+func EncodeDERBundle(data []byte) []byte {
+	var der []byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		der = append(der, block.Bytes...)
+	}
+
+	return der
+}
+
+// DecodeDERBundle attempts to parse the given data as one or more concatenated
+// DER-encoded X.509 certificates. If successful, returns the certificates as a
+// PEM bundle and true. If the data contains any PEM blocks or can't be parsed
+// as DER-encoded certificates, returns false.
+func DecodeDERBundle(data []byte) ([]byte, bool) {
+	if block, _ := pem.Decode(data); block != nil {
+		return nil, false
+	}
+
+	certificates, err := x509.ParseCertificates(data)
+	if err != nil || len(certificates) == 0 {
+		return nil, false
+	}
+
+	return encodeCertificatesPEM(certificates), true
 }
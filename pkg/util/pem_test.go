@@ -18,6 +18,7 @@ package util
 
 import (
 	"bytes"
+	"encoding/pem"
 	"strings"
 	"testing"
 	"unicode/utf8"
@@ -118,6 +119,54 @@ func TestValidateAndSanitizePEMBundle(t *testing.T) {
 	}
 }
 
+func TestSplitValidPEMBundle(t *testing.T) {
+	cases := map[string]struct {
+		parts []string
+
+		expCertificates []string
+		expSkipped      int
+	}{
+		"valid bundle returns all certificates": {
+			parts:           []string{dummy.TestCertificate1, randomComment, dummy.TestCertificate2},
+			expCertificates: []string{dummy.TestCertificate1, dummy.TestCertificate2},
+		},
+		"invalid blocks are skipped and counted": {
+			parts:           []string{dummy.TestCertificate1, dummyCertificateWithHeader, invalidCertificate, dummy.TestCertificate3},
+			expCertificates: []string{dummy.TestCertificate1, dummy.TestCertificate3},
+			expSkipped:      2,
+		},
+		"blocks which aren't certificates are skipped and counted": {
+			parts:           []string{privateKey, dummy.TestCertificate2},
+			expCertificates: []string{dummy.TestCertificate2},
+			expSkipped:      1,
+		},
+		"bundle without valid certificates returns none": {
+			parts:      []string{invalidCertificate},
+			expSkipped: 1,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			certificates, skipped := SplitValidPEMBundle([]byte(strings.Join(test.parts, "\n")))
+
+			if skipped != test.expSkipped {
+				t.Errorf("unexpected number of skipped blocks, exp=%d got=%d", test.expSkipped, skipped)
+			}
+
+			if len(certificates) != len(test.expCertificates) {
+				t.Fatalf("unexpected number of certificates, exp=%d got=%d", len(test.expCertificates), len(certificates))
+			}
+
+			for i, certificate := range certificates {
+				if strings.TrimSpace(string(certificate)) != strings.TrimSpace(test.expCertificates[i]) {
+					t.Errorf("unexpected certificate %d, exp=%q got=%q", i, test.expCertificates[i], certificate)
+				}
+			}
+		})
+	}
+}
+
 const randomComment = `some random commentary`
 
 const dummyCertificateWithHeader = `-----BEGIN CERTIFICATE-----
@@ -152,3 +201,295 @@ MHcCAQEEIHThSpdYMjW1k4K2r8RwhIGmknKrr0XKQLOJeL2fVoxToAoGCCqGSM49
 AwEHoUQDQgAEoMocv03WW/kCmyYM7CN7Ge7J5NOhJOKUYjF15NRBevWbxd8GYsvj
 9yCaAWu1mIQpIuWI4pXHU9s4V0FDlIKerQ==
 -----END EC PRIVATE KEY-----`
+
+func TestCommentPEMBundle(t *testing.T) {
+	data := strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3))
+
+	commented, err := CommentPEMBundle([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expData := strings.Join([]string{
+		"# Subject: CN=cmct-test-root,O=cert-manager",
+		"# Issuer: CN=cmct-test-root,O=cert-manager",
+		"# Not After: 2032-11-22T13:03:54Z",
+		"# SHA-256 Fingerprint: 54:8B:98:8F:4B:AD:7B:DD:0D:3B:75:23:DE:37:15:4E:E4:7F:28:5E:EE:36:D3:B1:F5:3F:AA:27:20:FC:A3:07",
+		dummy.TestCertificate1,
+		"# Subject: CN=ISRG Root X1,O=Internet Security Research Group,C=US",
+		"# Issuer: CN=ISRG Root X1,O=Internet Security Research Group,C=US",
+		"# Not After: 2035-06-04T11:04:38Z",
+		"# SHA-256 Fingerprint: 96:BC:EC:06:26:49:76:F3:74:60:77:9A:CF:28:C5:A7:CF:E8:A3:C0:AA:E1:1A:8F:FC:EE:05:C0:BD:DF:08:C6",
+		dummy.TestCertificate3,
+	}, "\n")
+	if string(commented) != expData {
+		t.Errorf("unexpected data, exp=%q got=%q", expData, commented)
+	}
+
+	sanitized, err := ValidateAndSanitizePEMBundle(commented)
+	if err != nil {
+		t.Fatalf("unexpected error sanitizing commented bundle: %s", err)
+	}
+	if string(sanitized) != data {
+		t.Errorf("expected comments to be ignored when sanitizing, exp=%q got=%q", data, sanitized)
+	}
+
+	if stripped := StripPEMComments(commented); string(stripped) != data {
+		t.Errorf("expected stripping comments to restore the bundle, exp=%q got=%q", data, stripped)
+	}
+}
+
+func TestEncodeDERBundle(t *testing.T) {
+	cases := map[string]struct {
+		data string
+
+		expData []byte
+	}{
+		"single PEM certificate is converted to DER": {
+			data:    dummy.TestCertificate1,
+			expData: dummy.JoinCertsDER(dummy.TestCertificate1),
+		},
+		"PEM certificates are concatenated in order": {
+			data:    dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1),
+			expData: dummy.JoinCertsDER(dummy.TestCertificate3, dummy.TestCertificate1),
+		},
+		"empty data is converted to no certificates": {
+			data: "",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			data := EncodeDERBundle([]byte(test.data))
+			if !bytes.Equal(data, test.expData) {
+				t.Errorf("unexpected data, exp=%x got=%x", test.expData, data)
+			}
+		})
+	}
+}
+
+func TestDecodeDERBundle(t *testing.T) {
+	cases := map[string]struct {
+		data []byte
+
+		expData []byte
+		expOK   bool
+	}{
+		"single DER certificate is converted to PEM": {
+			data:    dummy.JoinCertsDER(dummy.TestCertificate1),
+			expData: []byte(dummy.TestCertificate1 + "\n"),
+			expOK:   true,
+		},
+		"concatenated DER certificates are converted to PEM": {
+			data:    dummy.JoinCertsDER(dummy.TestCertificate1, dummy.TestCertificate2),
+			expData: []byte(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)),
+			expOK:   true,
+		},
+		"PEM data is not converted": {
+			data:  []byte(dummy.TestCertificate1),
+			expOK: false,
+		},
+		"random data is not converted": {
+			data:  []byte(randomComment),
+			expOK: false,
+		},
+		"empty data is not converted": {
+			data:  nil,
+			expOK: false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, ok := DecodeDERBundle(test.data)
+			if ok != test.expOK {
+				t.Fatalf("unexpected ok, exp=%t got=%t", test.expOK, ok)
+			}
+
+			if !bytes.Equal(data, test.expData) {
+				t.Errorf("unexpected data, exp=%q got=%q", test.expData, data)
+			}
+		})
+	}
+}
+
+func TestDecodePKCS7Bundle(t *testing.T) {
+	pkcs7DER, _ := pem.Decode([]byte(dummy.TestPKCS7Bundle))
+
+	cases := map[string]struct {
+		data []byte
+
+		expData []byte
+		expOK   bool
+	}{
+		"DER PKCS#7 bundle is converted to PEM": {
+			data:    pkcs7DER.Bytes,
+			expData: []byte(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)),
+			expOK:   true,
+		},
+		"PEM PKCS#7 bundle is converted to PEM certificates": {
+			data:    []byte(dummy.TestPKCS7Bundle),
+			expData: []byte(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2)),
+			expOK:   true,
+		},
+		"PEM PKCS#7 bundle alongside PEM certificates keeps all certificates in order": {
+			data:    []byte(dummy.JoinCerts(dummy.TestCertificate3, dummy.TestPKCS7Bundle)),
+			expData: []byte(dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate2)),
+			expOK:   true,
+		},
+		"PEM certificates without a PKCS#7 bundle are not converted": {
+			data:  []byte(dummy.TestCertificate1),
+			expOK: false,
+		},
+		"DER certificates are not converted": {
+			data:  dummy.JoinCertsDER(dummy.TestCertificate1),
+			expOK: false,
+		},
+		"random data is not converted": {
+			data:  []byte(randomComment),
+			expOK: false,
+		},
+		"empty data is not converted": {
+			data:  nil,
+			expOK: false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, ok := DecodePKCS7Bundle(test.data)
+			if ok != test.expOK {
+				t.Fatalf("unexpected ok, exp=%t got=%t", test.expOK, ok)
+			}
+
+			if !bytes.Equal(data, test.expData) {
+				t.Errorf("unexpected data, exp=%q got=%q", test.expData, data)
+			}
+		})
+	}
+}
+
+func TestEncodePKCS7Bundle(t *testing.T) {
+	pkcs7DER, _ := pem.Decode([]byte(dummy.TestPKCS7Bundle))
+
+	cases := map[string]struct {
+		data string
+
+		expData []byte
+	}{
+		"single certificate": {
+			data: dummy.TestCertificate1,
+		},
+		"multiple certificates are encoded in order": {
+			data:    dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expData: pkcs7DER.Bytes,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			p7b, err := EncodePKCS7Bundle([]byte(test.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if test.expData != nil && !bytes.Equal(p7b, test.expData) {
+				t.Errorf("unexpected PKCS#7 bundle, exp=%x got=%x", test.expData, p7b)
+			}
+
+			data, ok := DecodePKCS7Bundle(p7b)
+			if !ok {
+				t.Fatalf("failed to decode encoded PKCS#7 bundle")
+			}
+			if strings.TrimSpace(string(data)) != strings.TrimSpace(test.data) {
+				t.Errorf("unexpected data, exp=%q got=%q", test.data, data)
+			}
+		})
+	}
+}
+
+func TestSortPEMBundle(t *testing.T) {
+	cases := map[string]struct {
+		data []byte
+
+		expData []byte
+		expErr  bool
+	}{
+		"certificates are sorted by digest": {
+			data:    []byte(dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2, dummy.TestCertificate3)),
+			expData: []byte(strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate1, dummy.TestCertificate3))),
+		},
+		"order of the input doesn't change the output": {
+			data:    []byte(dummy.JoinCerts(dummy.TestCertificate3, dummy.TestCertificate1, dummy.TestCertificate2)),
+			expData: []byte(strings.TrimSpace(dummy.JoinCerts(dummy.TestCertificate2, dummy.TestCertificate1, dummy.TestCertificate3))),
+		},
+		"single certificate is unchanged": {
+			data:    []byte(dummy.TestCertificate1),
+			expData: []byte(dummy.TestCertificate1),
+		},
+		"non-certificate block returns an error": {
+			data:   []byte(privateKey),
+			expErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, err := SortPEMBundle(test.data)
+			if (err != nil) != test.expErr {
+				t.Fatalf("unexpected error, exp=%t got=%v", test.expErr, err)
+			}
+
+			if !bytes.Equal(data, test.expData) {
+				t.Errorf("unexpected data, exp=%q got=%q", test.expData, data)
+			}
+		})
+	}
+}
+
+func TestStripPrivateKeys(t *testing.T) {
+	cases := map[string]struct {
+		data string
+
+		expContains bool
+		expData     string
+	}{
+		"bundle without private keys is unchanged": {
+			data:        dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+			expContains: false,
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+		},
+		"private key is removed": {
+			data:        dummy.JoinCerts(dummy.TestCertificate1, privateKey, dummy.TestCertificate2),
+			expContains: true,
+			expData:     dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate2),
+		},
+		"comments are removed with the private key": {
+			data:        dummy.JoinCerts(privateKey, randomComment, dummy.TestCertificate1),
+			expContains: true,
+			expData:     dummy.JoinCerts(dummy.TestCertificate1),
+		},
+		"data with only a private key is emptied": {
+			data:        privateKey,
+			expContains: true,
+			expData:     "",
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			if contains := ContainsPrivateKey([]byte(test.data)); contains != test.expContains {
+				t.Errorf("unexpected ContainsPrivateKey result, exp=%t got=%t", test.expContains, contains)
+			}
+
+			data := StripPrivateKeys([]byte(test.data))
+			if ContainsPrivateKey(data) {
+				t.Errorf("expected stripped data not to contain a private key")
+			}
+
+			if strings.TrimSpace(string(data)) != strings.TrimSpace(test.expData) {
+				t.Errorf("unexpected data, exp=%q got=%q", test.expData, data)
+			}
+		})
+	}
+}
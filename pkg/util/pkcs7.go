@@ -0,0 +1,186 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+var (
+	// oidData is the PKCS#7 data content type, which is the type of the
+	// empty content of certificate-only ".p7b" bundles.
+	oidData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+
+	// oidSignedData is the PKCS#7 signedData content type, which is used as a
+	// container for certificate-only ".p7b" bundles.
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+)
+
+// pkcs7ContentInfo is the top level PKCS#7 structure, as defined in RFC 2315.
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// pkcs7SignedData is the PKCS#7 SignedData structure, as defined in RFC 2315.
+// Only the certificates are of interest to trust-manager.
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      asn1.RawValue
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      asn1.RawValue
+}
+
+// DecodePKCS7Bundle converts PKCS#7 certificate bundles in the given data to
+// PEM-encoded certificates. PEM "PKCS7" blocks are replaced with the
+// certificates they contain, leaving other PEM blocks untouched. If data
+// contains no PEM blocks, it is parsed as a DER-encoded PKCS#7 bundle.
+// Returns false if no PKCS#7 bundle was found in the data.
+func DecodePKCS7Bundle(data []byte) ([]byte, bool) {
+	if block, _ := pem.Decode(data); block == nil {
+		certificates, err := parsePKCS7Certificates(data)
+		if err != nil {
+			return nil, false
+		}
+
+		return encodeCertificatesPEM(certificates), true
+	}
+
+	var (
+		buf   bytes.Buffer
+		found bool
+	)
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "PKCS7" {
+			// Writing to a bytes.Buffer can't fail.
+			_ = pem.Encode(&buf, block)
+			continue
+		}
+
+		certificates, err := parsePKCS7Certificates(block.Bytes)
+		if err != nil {
+			return nil, false
+		}
+
+		found = true
+		buf.Write(encodeCertificatesPEM(certificates))
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	return buf.Bytes(), true
+}
+
+// EncodePKCS7Bundle returns a DER-encoded, certificate-only PKCS#7 bundle of
+// the certificates in the given PEM bundle, in the same order. The encoding is
+// deterministic, so the same PEM bundle is always encoded to the same bytes.
+func EncodePKCS7Bundle(data []byte) ([]byte, error) {
+	var certificates []byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		certificates = append(certificates, block.Bytes...)
+	}
+
+	contentInfo, err := asn1.Marshal(pkcs7ContentInfo{ContentType: oidData})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PKCS#7 content info: %w", err)
+	}
+
+	// A certificate-only bundle is a SignedData structure without signers.
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      asn1.RawValue{FullBytes: contentInfo},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode PKCS#7 signed data: %w", err)
+	}
+
+	// Raw values are encoded as they are, so the explicit tag of the content
+	// is written here.
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// parsePKCS7Certificates returns the certificates contained in the given
+// DER-encoded PKCS#7 SignedData structure.
+func parsePKCS7Certificates(der []byte) ([]*x509.Certificate, error) {
+	var info pkcs7ContentInfo
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS#7 content info: %w", err)
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("trailing data after PKCS#7 content info")
+	}
+
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, fmt.Errorf("unsupported PKCS#7 content type %s", info.ContentType)
+	}
+
+	var signedData pkcs7SignedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &signedData); err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS#7 signed data: %w", err)
+	}
+
+	certificates, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse PKCS#7 certificates: %w", err)
+	}
+
+	if len(certificates) == 0 {
+		return nil, errors.New("PKCS#7 bundle contains no certificates")
+	}
+
+	return certificates, nil
+}
+
+// encodeCertificatesPEM returns the given certificates as a PEM bundle.
+func encodeCertificatesPEM(certificates []*x509.Certificate) []byte {
+	var buf bytes.Buffer
+	for _, cert := range certificates {
+		// Writing to a bytes.Buffer can't fail.
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+
+	return buf.Bytes()
+}
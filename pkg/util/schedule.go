@@ -0,0 +1,49 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// ParseSchedule parses the given standard 5-field cron expression. Unless the
+// expression is prefixed with a "CRON_TZ=" or "TZ=" time zone, the schedule
+// is evaluated in UTC.
+func ParseSchedule(schedule string) (cron.Schedule, error) {
+	if !strings.HasPrefix(schedule, "CRON_TZ=") && !strings.HasPrefix(schedule, "TZ=") {
+		schedule = "CRON_TZ=UTC " + schedule
+	}
+
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse schedule: %w", err)
+	}
+
+	return sched, nil
+}
+
+// WindowOpen returns true if the window opening at the given schedule and
+// remaining open for the given duration is open at the given time.
+func WindowOpen(schedule cron.Schedule, duration time.Duration, now time.Time) bool {
+	// The window is open if it last opened within duration of now, i.e. if
+	// the first opening after now-duration is not after now.
+	return !schedule.Next(now.Add(-duration)).After(now)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowOpen(t *testing.T) {
+	// Saturday 2021-01-02.
+	saturday := func(hour, minute int) time.Time {
+		return time.Date(2021, 01, 02, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		schedule string
+		duration time.Duration
+		now      time.Time
+
+		expOpen     bool
+		expParseErr bool
+	}{
+		"before the window opens": {
+			schedule: "0 2 * * SAT",
+			duration: 4 * time.Hour,
+			now:      saturday(1, 59),
+			expOpen:  false,
+		},
+		"when the window opens": {
+			schedule: "0 2 * * SAT",
+			duration: 4 * time.Hour,
+			now:      saturday(2, 0),
+			expOpen:  true,
+		},
+		"while the window is open": {
+			schedule: "0 2 * * SAT",
+			duration: 4 * time.Hour,
+			now:      saturday(5, 59),
+			expOpen:  true,
+		},
+		"when the window closes": {
+			schedule: "0 2 * * SAT",
+			duration: 4 * time.Hour,
+			now:      saturday(6, 0),
+			expOpen:  false,
+		},
+		"with a time zone, while the window is open": {
+			schedule: "CRON_TZ=Europe/Oslo 0 2 * * SAT",
+			duration: time.Hour,
+			now:      saturday(1, 30),
+			expOpen:  true,
+		},
+		"with a time zone, when the window would be open in UTC": {
+			schedule: "CRON_TZ=Europe/Oslo 0 2 * * SAT",
+			duration: time.Hour,
+			now:      saturday(2, 30),
+			expOpen:  false,
+		},
+		"invalid schedule": {
+			schedule:    "every saturday",
+			expParseErr: true,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			schedule, err := ParseSchedule(test.schedule)
+			if (err != nil) != test.expParseErr {
+				t.Fatalf("unexpected parse error, exp=%t got=%v", test.expParseErr, err)
+			}
+			if err != nil {
+				return
+			}
+
+			if open := WindowOpen(schedule, test.duration, test.now); open != test.expOpen {
+				t.Errorf("unexpected open, exp=%t got=%t", test.expOpen, open)
+			}
+		})
+	}
+}
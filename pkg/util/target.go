@@ -0,0 +1,49 @@
+/*
+Copyright 2022 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// TargetData returns the PEM-encoded bundle data written to the given key of a
+// target ConfigMap in the given format. Bundles written in the DER format are
+// read from the binaryData field and converted to PEM, in the same form as the
+// bundle data written in the PEM format, so that both have the same content
+// hash as the bundle data they were written from. Comments written before the
+// certificates of the bundle are removed. Returns false if the ConfigMap
+// contains no bundle data at the key.
+func TargetData(configMap *corev1.ConfigMap, key string, format trustapi.TargetFormat) (string, bool) {
+	if format != trustapi.TargetFormatDER {
+		data, ok := configMap.Data[key]
+		return string(StripPEMComments([]byte(data))), ok
+	}
+
+	der, ok := configMap.BinaryData[key]
+	if !ok {
+		return "", false
+	}
+
+	data, ok := DecodeDERBundle(der)
+	if !ok {
+		return "", false
+	}
+
+	return string(data), true
+}
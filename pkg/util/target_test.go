@@ -0,0 +1,82 @@
+/*
+Copyright 2022 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/test/dummy"
+)
+
+func TestTargetData(t *testing.T) {
+	data := dummy.JoinCerts(dummy.TestCertificate1, dummy.TestCertificate3)
+
+	cases := map[string]struct {
+		configMap *corev1.ConfigMap
+		format    trustapi.TargetFormat
+
+		expData string
+		expOK   bool
+	}{
+		"PEM data is read from the data field": {
+			configMap: &corev1.ConfigMap{Data: map[string]string{"ca.crt": data}},
+			expData:   data,
+			expOK:     true,
+		},
+		"comments are removed from PEM data": {
+			configMap: &corev1.ConfigMap{Data: map[string]string{"ca.crt": "# Subject: CN=test\n" + dummy.TestCertificate1 + "\n# Subject: CN=test\n" + dummy.TestCertificate3 + "\n"}},
+			expData:   data,
+			expOK:     true,
+		},
+		"PEM data is not read from the binaryData field": {
+			configMap: &corev1.ConfigMap{BinaryData: map[string][]byte{"ca.crt": []byte(data)}},
+			expOK:     false,
+		},
+		"DER data is read from the binaryData field and converted to PEM": {
+			configMap: &corev1.ConfigMap{BinaryData: map[string][]byte{"ca.crt": dummy.JoinCertsDER(dummy.TestCertificate1, dummy.TestCertificate3)}},
+			format:    trustapi.TargetFormatDER,
+			expData:   data,
+			expOK:     true,
+		},
+		"DER data is not read from the data field": {
+			configMap: &corev1.ConfigMap{Data: map[string]string{"ca.crt": data}},
+			format:    trustapi.TargetFormatDER,
+			expOK:     false,
+		},
+		"invalid DER data is not read": {
+			configMap: &corev1.ConfigMap{BinaryData: map[string][]byte{"ca.crt": []byte("not DER")}},
+			format:    trustapi.TargetFormatDER,
+			expOK:     false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			data, ok := TargetData(test.configMap, "ca.crt", test.format)
+			if ok != test.expOK {
+				t.Fatalf("unexpected ok, exp=%t got=%t", test.expOK, ok)
+			}
+
+			if data != test.expData {
+				t.Errorf("unexpected data, exp=%q got=%q", test.expData, data)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// KeyUsages maps the key usages which certificates can be filtered by to
+// their x509 values.
+var KeyUsages = map[trustapi.KeyUsage]x509.KeyUsage{
+	trustapi.KeyUsageDigitalSignature:  x509.KeyUsageDigitalSignature,
+	trustapi.KeyUsageContentCommitment: x509.KeyUsageContentCommitment,
+	trustapi.KeyUsageKeyEncipherment:   x509.KeyUsageKeyEncipherment,
+	trustapi.KeyUsageDataEncipherment:  x509.KeyUsageDataEncipherment,
+	trustapi.KeyUsageKeyAgreement:      x509.KeyUsageKeyAgreement,
+	trustapi.KeyUsageCertSign:          x509.KeyUsageCertSign,
+	trustapi.KeyUsageCRLSign:           x509.KeyUsageCRLSign,
+	trustapi.KeyUsageEncipherOnly:      x509.KeyUsageEncipherOnly,
+	trustapi.KeyUsageDecipherOnly:      x509.KeyUsageDecipherOnly,
+}
+
+// ExtendedKeyUsages maps the extended key usages which certificates can be
+// filtered by to their x509 values.
+var ExtendedKeyUsages = map[trustapi.ExtendedKeyUsage]x509.ExtKeyUsage{
+	trustapi.ExtendedKeyUsageServerAuth:      x509.ExtKeyUsageServerAuth,
+	trustapi.ExtendedKeyUsageClientAuth:      x509.ExtKeyUsageClientAuth,
+	trustapi.ExtendedKeyUsageCodeSigning:     x509.ExtKeyUsageCodeSigning,
+	trustapi.ExtendedKeyUsageEmailProtection: x509.ExtKeyUsageEmailProtection,
+	trustapi.ExtendedKeyUsageTimeStamping:    x509.ExtKeyUsageTimeStamping,
+	trustapi.ExtendedKeyUsageOCSPSigning:     x509.ExtKeyUsageOCSPSigning,
+}
+
+// HasKeyUsages returns true if the given certificate is valid for all of the
+// given key usages. Certificates without the key usage extension are valid
+// for any key usage.
+func HasKeyUsages(cert *x509.Certificate, usages []trustapi.KeyUsage) bool {
+	if cert.KeyUsage == 0 {
+		return true
+	}
+
+	for _, usage := range usages {
+		if cert.KeyUsage&KeyUsages[usage] == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HasExtendedKeyUsages returns true if the given certificate is valid for all
+// of the given extended key usages. Certificates without the extended key
+// usage extension, or with the any extended key usage, are valid for any
+// extended key usage.
+func HasExtendedKeyUsages(cert *x509.Certificate, usages []trustapi.ExtendedKeyUsage) bool {
+	if len(cert.ExtKeyUsage) == 0 && len(cert.UnknownExtKeyUsage) == 0 {
+		return true
+	}
+
+	for _, usage := range usages {
+		found := false
+		for _, certUsage := range cert.ExtKeyUsage {
+			if certUsage == x509.ExtKeyUsageAny || certUsage == ExtendedKeyUsages[usage] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
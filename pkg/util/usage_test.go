@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/x509"
+	"testing"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func TestHasKeyUsages(t *testing.T) {
+	cases := map[string]struct {
+		keyUsage x509.KeyUsage
+		usages   []trustapi.KeyUsage
+
+		expValid bool
+	}{
+		"certificate without key usages is valid for any usage": {
+			usages:   []trustapi.KeyUsage{trustapi.KeyUsageDigitalSignature},
+			expValid: true,
+		},
+		"certificate with all usages is valid": {
+			keyUsage: x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+			usages:   []trustapi.KeyUsage{trustapi.KeyUsageCertSign, trustapi.KeyUsageCRLSign},
+			expValid: true,
+		},
+		"certificate missing a usage is invalid": {
+			keyUsage: x509.KeyUsageCertSign,
+			usages:   []trustapi.KeyUsage{trustapi.KeyUsageCertSign, trustapi.KeyUsageCRLSign},
+			expValid: false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			valid := HasKeyUsages(&x509.Certificate{KeyUsage: test.keyUsage}, test.usages)
+			if valid != test.expValid {
+				t.Errorf("unexpected validity, exp=%t got=%t", test.expValid, valid)
+			}
+		})
+	}
+}
+
+func TestHasExtendedKeyUsages(t *testing.T) {
+	cases := map[string]struct {
+		extKeyUsage []x509.ExtKeyUsage
+		usages      []trustapi.ExtendedKeyUsage
+
+		expValid bool
+	}{
+		"certificate without extended key usages is valid for any usage": {
+			usages:   []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageServerAuth},
+			expValid: true,
+		},
+		"certificate with any extended key usage is valid for any usage": {
+			extKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+			usages:      []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageCodeSigning},
+			expValid:    true,
+		},
+		"certificate with all usages is valid": {
+			extKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+			usages:      []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageServerAuth},
+			expValid:    true,
+		},
+		"certificate restricted to other usages is invalid": {
+			extKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning, x509.ExtKeyUsageEmailProtection},
+			usages:      []trustapi.ExtendedKeyUsage{trustapi.ExtendedKeyUsageServerAuth},
+			expValid:    false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			valid := HasExtendedKeyUsages(&x509.Certificate{ExtKeyUsage: test.extKeyUsage}, test.usages)
+			if valid != test.expValid {
+				t.Errorf("unexpected validity, exp=%t got=%t", test.expValid, valid)
+			}
+		})
+	}
+}
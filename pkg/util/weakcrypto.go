@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+)
+
+// DefaultMinRSAKeySize is the minimum size in bits of the RSA keys of
+// certificates which are not considered weak, unless configured otherwise.
+const DefaultMinRSAKeySize = 2048
+
+// SignatureAlgorithms maps the names of the signature algorithms which can be
+// configured as weak, as named by the x509 package, to their x509 values.
+var SignatureAlgorithms = map[string]x509.SignatureAlgorithm{
+	x509.MD5WithRSA.String():       x509.MD5WithRSA,
+	x509.SHA1WithRSA.String():      x509.SHA1WithRSA,
+	x509.SHA256WithRSA.String():    x509.SHA256WithRSA,
+	x509.SHA384WithRSA.String():    x509.SHA384WithRSA,
+	x509.SHA512WithRSA.String():    x509.SHA512WithRSA,
+	x509.DSAWithSHA1.String():      x509.DSAWithSHA1,
+	x509.DSAWithSHA256.String():    x509.DSAWithSHA256,
+	x509.ECDSAWithSHA1.String():    x509.ECDSAWithSHA1,
+	x509.ECDSAWithSHA256.String():  x509.ECDSAWithSHA256,
+	x509.ECDSAWithSHA384.String():  x509.ECDSAWithSHA384,
+	x509.ECDSAWithSHA512.String():  x509.ECDSAWithSHA512,
+	x509.SHA256WithRSAPSS.String(): x509.SHA256WithRSAPSS,
+	x509.SHA384WithRSAPSS.String(): x509.SHA384WithRSAPSS,
+	x509.SHA512WithRSAPSS.String(): x509.SHA512WithRSAPSS,
+	x509.PureEd25519.String():      x509.PureEd25519,
+}
+
+// DefaultWeakSignatureAlgorithms are the signature algorithms which are
+// considered weak, unless configured otherwise: those using MD5 or SHA-1.
+var DefaultWeakSignatureAlgorithms = []x509.SignatureAlgorithm{
+	x509.MD5WithRSA,
+	x509.SHA1WithRSA,
+	x509.DSAWithSHA1,
+	x509.ECDSAWithSHA1,
+}
+
+// HasWeakCrypto returns true if the given certificate has an RSA key smaller
+// than minRSAKeySize bits, or is signed using one of the given weak signature
+// algorithms.
+func HasWeakCrypto(cert *x509.Certificate, minRSAKeySize int, weakSignatureAlgorithms []x509.SignatureAlgorithm) bool {
+	if key, ok := cert.PublicKey.(*rsa.PublicKey); ok && key.N.BitLen() < minRSAKeySize {
+		return true
+	}
+
+	for _, algorithm := range weakSignatureAlgorithms {
+		if cert.SignatureAlgorithm == algorithm {
+			return true
+		}
+	}
+
+	return false
+}
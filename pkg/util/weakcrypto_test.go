@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"math/big"
+	"testing"
+)
+
+func TestHasWeakCrypto(t *testing.T) {
+	rsaKey := func(bits uint) *rsa.PublicKey {
+		return &rsa.PublicKey{N: new(big.Int).Lsh(big.NewInt(1), bits-1), E: 65537}
+	}
+
+	cases := map[string]struct {
+		cert          *x509.Certificate
+		minRSAKeySize int
+
+		expWeak bool
+	}{
+		"RSA key of the minimum size is not weak": {
+			cert:          &x509.Certificate{PublicKey: rsaKey(2048), SignatureAlgorithm: x509.SHA256WithRSA},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			expWeak:       false,
+		},
+		"RSA key smaller than the minimum size is weak": {
+			cert:          &x509.Certificate{PublicKey: rsaKey(1024), SignatureAlgorithm: x509.SHA256WithRSA},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			expWeak:       true,
+		},
+		"RSA key smaller than a larger configured minimum size is weak": {
+			cert:          &x509.Certificate{PublicKey: rsaKey(2048), SignatureAlgorithm: x509.SHA256WithRSA},
+			minRSAKeySize: 3072,
+			expWeak:       true,
+		},
+		"SHA-1 signature is weak": {
+			cert:          &x509.Certificate{PublicKey: rsaKey(4096), SignatureAlgorithm: x509.SHA1WithRSA},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			expWeak:       true,
+		},
+		"ECDSA key with SHA-1 signature is weak": {
+			cert:          &x509.Certificate{PublicKey: new(ecdsa.PublicKey), SignatureAlgorithm: x509.ECDSAWithSHA1},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			expWeak:       true,
+		},
+		"ECDSA key with SHA-256 signature is not weak": {
+			cert:          &x509.Certificate{PublicKey: new(ecdsa.PublicKey), SignatureAlgorithm: x509.ECDSAWithSHA256},
+			minRSAKeySize: DefaultMinRSAKeySize,
+			expWeak:       false,
+		},
+	}
+
+	for name, test := range cases {
+		t.Run(name, func(t *testing.T) {
+			weak := HasWeakCrypto(test.cert, test.minRSAKeySize, DefaultWeakSignatureAlgorithms)
+			if weak != test.expWeak {
+				t.Errorf("unexpected weakness, exp=%t got=%t", test.expWeak, weak)
+			}
+		})
+	}
+}
+
+func TestSignatureAlgorithms(t *testing.T) {
+	for name, algorithm := range SignatureAlgorithms {
+		if name != algorithm.String() {
+			t.Errorf("signature algorithm %s has unexpected name %q", algorithm, name)
+		}
+	}
+	for _, algorithm := range DefaultWeakSignatureAlgorithms {
+		if _, ok := SignatureAlgorithms[algorithm.String()]; !ok {
+			t.Errorf("default weak signature algorithm %s is not configurable", algorithm)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+// clientCARefreshPeriod is the period at which the webhook client CAs are
+// reloaded from the client CA Bundle.
+const clientCARefreshPeriod = time.Minute
+
+// clientCALoader loads the CAs used to verify the client certificates
+// presented to the webhook from the target ConfigMap of a Bundle in the trust
+// Namespace, and reloads them periodically to pick up changes.
+type clientCALoader struct {
+	log    logr.Logger
+	reader client.Reader
+
+	// namespace is the trust Namespace containing the Bundle's target.
+	namespace string
+
+	// bundle is the name of the Bundle supplying the client CAs.
+	bundle string
+
+	// naming are the conventions used to resolve the name of the Bundle's
+	// target.
+	naming *naming.Conventions
+
+	// pool holds the last successfully loaded client CAs.
+	pool atomic.Pointer[x509.CertPool]
+}
+
+// Start loads the client CAs and reloads them periodically until the context
+// is cancelled.
+func (l *clientCALoader) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := l.load(ctx); err != nil {
+			l.log.Error(err, "failed to load webhook client CAs", "bundle", l.bundle)
+		}
+	}, clientCARefreshPeriod)
+
+	return nil
+}
+
+// NeedLeaderElection returns false, since every replica serves the webhook.
+func (l *clientCALoader) NeedLeaderElection() bool {
+	return false
+}
+
+// load reads the client CAs from the Bundle's target ConfigMap in the trust
+// Namespace.
+func (l *clientCALoader) load(ctx context.Context) error {
+	var bundle trustapi.Bundle
+	if err := l.reader.Get(ctx, client.ObjectKey{Name: l.bundle}, &bundle); err != nil {
+		return fmt.Errorf("failed to get client CA Bundle: %w", err)
+	}
+
+	if bundle.Spec.Target.ConfigMap == nil {
+		return fmt.Errorf("client CA Bundle %q has no ConfigMap target", l.bundle)
+	}
+
+	name, err := l.naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+	if err != nil {
+		return err
+	}
+
+	var configMap corev1.ConfigMap
+	if err := l.reader.Get(ctx, client.ObjectKey{Namespace: l.namespace, Name: name}, &configMap); err != nil {
+		return fmt.Errorf("failed to get client CA Bundle target in the trust namespace: %w", err)
+	}
+
+	data, ok := util.TargetData(&configMap, bundle.Spec.Target.ConfigMap.Key, bundle.Spec.Target.ConfigMap.Format)
+	if !ok {
+		return fmt.Errorf("no data found in client CA Bundle target %s/%s at key %q", l.namespace, name, bundle.Spec.Target.ConfigMap.Key)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(data)) {
+		return fmt.Errorf("no certificates found in client CA Bundle target %s/%s", l.namespace, name)
+	}
+
+	l.pool.Store(pool)
+
+	return nil
+}
+
+// configureTLS configures the webhook server to require client certificates,
+// verified against the loaded client CAs.
+func (l *clientCALoader) configureTLS(config *tls.Config) {
+	config.ClientAuth = tls.RequireAnyClientCert
+	config.VerifyPeerCertificate = l.verifyPeerCertificate
+}
+
+// verifyPeerCertificate verifies that the client certificate chain presented
+// by a client was issued by one of the loaded client CAs.
+func (l *clientCALoader) verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	pool := l.pool.Load()
+	if pool == nil {
+		return errors.New("webhook client CAs have not been loaded")
+	}
+
+	if len(rawCerts) == 0 {
+		return errors.New("no client certificate presented")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		certificate, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// check is a readiness check which fails until the client CAs have been
+// loaded, since all webhook requests are rejected until then.
+func (l *clientCALoader) check(_ *http.Request) error {
+	if l.pool.Load() == nil {
+		return errors.New("webhook client CAs have not been loaded")
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/klogr"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+)
+
+func Test_clientCALoader(t *testing.T) {
+	caKey, caCert := newTestCertificate(t, "webhook-client-ca", nil, nil, x509.ExtKeyUsageAny)
+	_, clientCert := newTestCertificate(t, "kube-apiserver", caKey, caCert, x509.ExtKeyUsageClientAuth)
+	_, serverCert := newTestCertificate(t, "server", caKey, caCert, x509.ExtKeyUsageServerAuth)
+	_, untrustedCert := newTestCertificate(t, "untrusted", nil, nil, x509.ExtKeyUsageClientAuth)
+
+	conventions, err := naming.New(naming.Options{TargetNameTemplate: "trust-{{ .Name }}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loader := &clientCALoader{
+		log: klogr.New(),
+		reader: fakeclient.NewClientBuilder().
+			WithScheme(trustapi.GlobalScheme).
+			WithObjects(
+				&trustapi.Bundle{
+					ObjectMeta: metav1.ObjectMeta{Name: "webhook-client-ca"},
+					Spec:       trustapi.BundleSpec{Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}}},
+				},
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "trust-webhook-client-ca", Namespace: "trust"},
+					Data:       map[string]string{"ca.crt": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caCert.Raw}))},
+				},
+			).
+			Build(),
+		namespace: "trust",
+		bundle:    "webhook-client-ca",
+		naming:    conventions,
+	}
+
+	assert.Error(t, loader.check(nil), "loader should not be ready before the client CAs are loaded")
+	assert.Error(t, loader.verifyPeerCertificate([][]byte{clientCert.Raw}, nil), "all clients should be rejected before the client CAs are loaded")
+
+	if err := loader.load(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, loader.check(nil))
+	assert.NoError(t, loader.verifyPeerCertificate([][]byte{clientCert.Raw}, nil))
+	assert.Error(t, loader.verifyPeerCertificate([][]byte{serverCert.Raw}, nil), "certificates without client auth usage should be rejected")
+	assert.Error(t, loader.verifyPeerCertificate([][]byte{untrustedCert.Raw}, nil), "certificates from other CAs should be rejected")
+	assert.Error(t, loader.verifyPeerCertificate(nil, nil), "clients without a certificate should be rejected")
+
+	// A missing client CA target keeps the previously loaded CAs.
+	loader.bundle = "missing"
+	assert.Error(t, loader.load(context.TODO()))
+	assert.NoError(t, loader.verifyPeerCertificate([][]byte{clientCert.Raw}, nil))
+}
+
+// newTestCertificate returns a new key and certificate with the given common
+// name, signed by the given parent. If parent is nil, a self-signed CA
+// certificate is returned.
+func newTestCertificate(t *testing.T, commonName string, parentKey *ecdsa.PrivateKey, parent *x509.Certificate, usage x509.ExtKeyUsage) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key, certificate
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/go-logr/logr"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+// ParseDefaultAdditionalFormats parses the default additional formats of
+// Bundles, given as a map of format names to the key the format is written
+// to, such as "jks" to "cacerts".
+func ParseDefaultAdditionalFormats(formats map[string]string) (*trustapi.AdditionalFormats, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+
+	var defaults trustapi.AdditionalFormats
+	for name, key := range formats {
+		if len(key) == 0 {
+			return nil, fmt.Errorf("key of default additional format %q must be defined", name)
+		}
+
+		selector := trustapi.KeySelector{Key: key}
+		switch name {
+		case "gzip":
+			defaults.Gzip = &trustapi.Gzip{KeySelector: selector}
+		case "jks":
+			defaults.JKS = &trustapi.JKS{KeySelector: selector}
+		case "metadata":
+			defaults.Metadata = &trustapi.Metadata{KeySelector: selector}
+		case "pkcs7":
+			defaults.PKCS7 = &selector
+		case "pkcs12":
+			defaults.PKCS12 = &trustapi.PKCS12{KeySelector: selector}
+		case "provenance":
+			defaults.Provenance = &selector
+		case "spiffe":
+			defaults.SPIFFE = &selector
+		case "sst":
+			defaults.SST = &selector
+		default:
+			return nil, fmt.Errorf("unsupported default additional format %q, must be one of gzip, jks, metadata, pkcs7, pkcs12, provenance, spiffe or sst", name)
+		}
+	}
+
+	return &defaults, nil
+}
+
+// defaulter is a mutating webhook which sets the cluster-wide default
+// additional formats on Bundles, so that platform teams don't need to repeat
+// the same format configuration in every Bundle. Bundles are only defaulted
+// when created, so that a default format removed from a Bundle afterwards
+// stays removed.
+type defaulter struct {
+	log     logr.Logger
+	formats *trustapi.AdditionalFormats
+
+	decoder *admission.Decoder
+
+	lock sync.RWMutex
+}
+
+// Handle is a mutating webhook handler for Bundles.
+func (d *defaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	log := d.log.WithValues("name", req.Name)
+
+	if req.Operation != admissionv1.Create {
+		return admission.Allowed("additional formats are only defaulted on create")
+	}
+
+	var bundle trustapi.Bundle
+
+	d.lock.RLock()
+	err := d.decoder.Decode(req, &bundle)
+	d.lock.RUnlock()
+
+	if err != nil {
+		log.Error(err, "failed to decode Bundle")
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if !defaultAdditionalFormats(&bundle, d.formats) {
+		return admission.Allowed("no additional formats to default")
+	}
+
+	marshaled, err := json.Marshal(&bundle)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	log.V(2).Info("defaulted additional formats")
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// defaultAdditionalFormats sets each of the given default formats which the
+// Bundle doesn't set itself. A default format isn't set if its key is already
+// used by the Bundle's target, so that defaults never make a Bundle invalid.
+// Bundles without a ConfigMap target aren't defaulted. Returns whether the
+// Bundle was changed.
+func defaultAdditionalFormats(bundle *trustapi.Bundle, defaults *trustapi.AdditionalFormats) bool {
+	target := &bundle.Spec.Target
+	if defaults == nil || target.ConfigMap == nil {
+		return false
+	}
+
+	formats := target.AdditionalFormats
+	if formats == nil {
+		formats = new(trustapi.AdditionalFormats)
+	}
+
+	usedKeys := newTargetKeys(*target)
+	changed := false
+	setDefault := func(isSet bool, key string, set func()) {
+		if isSet || len(key) == 0 || usedKeys.has(key) {
+			return
+		}
+
+		set()
+		usedKeys.add(key)
+		changed = true
+	}
+
+	if d := defaults.JKS; d != nil {
+		setDefault(formats.JKS != nil, d.Key, func() { formats.JKS = d.DeepCopy() })
+	}
+	if d := defaults.PKCS12; d != nil {
+		setDefault(formats.PKCS12 != nil, d.Key, func() { formats.PKCS12 = d.DeepCopy() })
+	}
+	if d := defaults.PKCS7; d != nil {
+		setDefault(formats.PKCS7 != nil, d.Key, func() { formats.PKCS7 = d.DeepCopy() })
+	}
+	if d := defaults.SST; d != nil {
+		setDefault(formats.SST != nil, d.Key, func() { formats.SST = d.DeepCopy() })
+	}
+	if d := defaults.Gzip; d != nil {
+		setDefault(formats.Gzip != nil, d.Key, func() { formats.Gzip = d.DeepCopy() })
+	}
+	if d := defaults.Metadata; d != nil {
+		setDefault(formats.Metadata != nil, d.Key, func() { formats.Metadata = d.DeepCopy() })
+	}
+	if d := defaults.SPIFFE; d != nil {
+		setDefault(formats.SPIFFE != nil, d.Key, func() { formats.SPIFFE = d.DeepCopy() })
+	}
+	if d := defaults.Provenance; d != nil {
+		setDefault(formats.Provenance != nil, d.Key, func() { formats.Provenance = d.DeepCopy() })
+	}
+
+	if changed {
+		target.AdditionalFormats = formats
+	}
+
+	return changed
+}
+
+// targetKeys are the keys of the entries written to the target of a Bundle.
+// The keys of the certificate entries of directory formats depend on the
+// bundle data, so are matched by patterns.
+type targetKeys struct {
+	keys     map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+// newTargetKeys returns the keys of the entries written to the given target,
+// which are the keys the validator requires not to overlap. The DER format of
+// the ConfigMap target is written to the ConfigMap key.
+func newTargetKeys(target trustapi.BundleTarget) *targetKeys {
+	k := &targetKeys{keys: make(map[string]struct{})}
+	if target.ConfigMap != nil {
+		k.add(target.ConfigMap.Key)
+	}
+
+	if buildInfo := target.BuildInfo; buildInfo != nil && buildInfo.Mode == trustapi.BuildInfoModeInformative {
+		if len(buildInfo.TimestampKey) > 0 {
+			k.add(buildInfo.TimestampKey)
+		} else {
+			k.add(trustapi.DefaultBuildTimestampKey)
+		}
+	}
+
+	formats := target.AdditionalFormats
+	if formats == nil {
+		return k
+	}
+
+	if formats.JKS != nil {
+		k.add(formats.JKS.Key)
+	}
+	if formats.PKCS12 != nil {
+		k.add(formats.PKCS12.Key)
+	}
+	if formats.PKCS7 != nil {
+		k.add(formats.PKCS7.Key)
+	}
+	if formats.SST != nil {
+		k.add(formats.SST.Key)
+	}
+	if formats.Gzip != nil {
+		k.add(formats.Gzip.Key)
+	}
+	if formats.Metadata != nil {
+		k.add(formats.Metadata.Key)
+	}
+	if formats.SPIFFE != nil {
+		k.add(formats.SPIFFE.Key)
+	}
+	if formats.Provenance != nil {
+		k.add(formats.Provenance.Key)
+	}
+
+	for _, profile := range formats.Profiles {
+		k.add(profile.Key)
+	}
+
+	if pemDirectory := formats.PEMDirectory; pemDirectory != nil {
+		indexKey := pemDirectory.IndexKey
+		if len(indexKey) == 0 {
+			indexKey = trustapi.DefaultPEMDirectoryIndexKey
+		}
+		k.add(indexKey)
+
+		prefix := pemDirectory.KeyPrefix
+		if len(prefix) == 0 {
+			prefix = trustapi.DefaultPEMDirectoryKeyPrefix
+		}
+		if _, namePattern, ok := pemDirectoryKeyNaming(pemDirectory.KeyNaming); ok {
+			k.patterns = append(k.patterns, pemDirectoryEntryKey(prefix, namePattern))
+		}
+	}
+
+	if hashedDirectory := formats.HashedDirectory; hashedDirectory != nil {
+		switch hashedDirectory.Packaging {
+		case "", trustapi.HashedDirectoryPackagingKeys:
+			indexKey := hashedDirectory.IndexKey
+			if len(indexKey) == 0 {
+				indexKey = trustapi.DefaultHashedDirectoryIndexKey
+			}
+			k.add(indexKey)
+			k.patterns = append(k.patterns, hashedDirectoryEntryKey)
+		case trustapi.HashedDirectoryPackagingTarball:
+			tarballKey := hashedDirectory.TarballKey
+			if len(tarballKey) == 0 {
+				tarballKey = trustapi.DefaultHashedDirectoryTarballKey
+			}
+			k.add(tarballKey)
+		}
+	}
+
+	return k
+}
+
+// add adds the given key to the keys of the target.
+func (k *targetKeys) add(key string) {
+	k.keys[key] = struct{}{}
+}
+
+// has returns whether the given key is written to the target.
+func (k *targetKeys) has(key string) bool {
+	if _, ok := k.keys[key]; ok {
+		return true
+	}
+
+	for _, pattern := range k.patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InjectDecoder is used by the controller-runtime manager to inject an object
+// decoder to convert into Bundles.
+func (d *defaulter) InjectDecoder(decoder *admission.Decoder) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	d.decoder = decoder
+	return nil
+}
+
+// check is used by the shared readiness manager to expose whether the server
+// is ready.
+func (d *defaulter) check(_ *http.Request) error {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	if d.decoder != nil {
+		return nil
+	}
+
+	return errors.New("not ready")
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_ParseDefaultAdditionalFormats(t *testing.T) {
+	tests := map[string]struct {
+		formats    map[string]string
+		expFormats *trustapi.AdditionalFormats
+		expErr     bool
+	}{
+		"no formats should not be defaulted": {
+			formats:    nil,
+			expFormats: nil,
+		},
+		"supported formats should be parsed": {
+			formats: map[string]string{"jks": "cacerts", "pkcs7": "bundle.p7b", "metadata": "metadata.json"},
+			expFormats: &trustapi.AdditionalFormats{
+				JKS:      &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "cacerts"}},
+				PKCS7:    &trustapi.KeySelector{Key: "bundle.p7b"},
+				Metadata: &trustapi.Metadata{KeySelector: trustapi.KeySelector{Key: "metadata.json"}},
+			},
+		},
+		"unsupported format should error": {
+			formats: map[string]string{"pemDirectory": "certs"},
+			expErr:  true,
+		},
+		"empty key should error": {
+			formats: map[string]string{"jks": ""},
+			expErr:  true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			formats, err := ParseDefaultAdditionalFormats(test.formats)
+			assert.Equal(t, test.expErr, err != nil, err)
+			assert.Equal(t, test.expFormats, formats)
+		})
+	}
+}
+
+func Test_defaultAdditionalFormats(t *testing.T) {
+	defaults := &trustapi.AdditionalFormats{
+		JKS:   &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "cacerts"}},
+		PKCS7: &trustapi.KeySelector{Key: "bundle.p7b"},
+	}
+
+	tests := map[string]struct {
+		defaults   *trustapi.AdditionalFormats
+		target     trustapi.BundleTarget
+		expTarget  trustapi.BundleTarget
+		expChanged bool
+	}{
+		"Bundle without ConfigMap target should not be defaulted": {
+			target:    trustapi.BundleTarget{},
+			expTarget: trustapi.BundleTarget{},
+		},
+		"Bundle without additional formats should have defaults set": {
+			target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: defaults,
+			},
+			expChanged: true,
+		},
+		"formats set by the Bundle should not be overridden": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "truststore.jks"}},
+				},
+			},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					JKS:   &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "truststore.jks"}},
+					PKCS7: &trustapi.KeySelector{Key: "bundle.p7b"},
+				},
+			},
+			expChanged: true,
+		},
+		"defaults whose key is already used should not be set": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "cacerts"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					SST: &trustapi.KeySelector{Key: "bundle.p7b"},
+				},
+			},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "cacerts"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					SST: &trustapi.KeySelector{Key: "bundle.p7b"},
+				},
+			},
+		},
+		"defaults whose key is a directory format index or tarball key should not be set": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					PEMDirectory:    &trustapi.PEMDirectory{IndexKey: "cacerts"},
+					HashedDirectory: &trustapi.HashedDirectory{Packaging: trustapi.HashedDirectoryPackagingTarball, TarballKey: "bundle.p7b"},
+				},
+			},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					PEMDirectory:    &trustapi.PEMDirectory{IndexKey: "cacerts"},
+					HashedDirectory: &trustapi.HashedDirectory{Packaging: trustapi.HashedDirectoryPackagingTarball, TarballKey: "bundle.p7b"},
+				},
+			},
+		},
+		"defaults whose key may be a directory format certificate key should not be set": {
+			defaults: &trustapi.AdditionalFormats{
+				JKS:   &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "ca-0.pem"}},
+				PKCS7: &trustapi.KeySelector{Key: "5ed36f99.0"},
+			},
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					PEMDirectory:    &trustapi.PEMDirectory{},
+					HashedDirectory: &trustapi.HashedDirectory{},
+				},
+			},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{
+					PEMDirectory:    &trustapi.PEMDirectory{},
+					HashedDirectory: &trustapi.HashedDirectory{},
+				},
+			},
+		},
+		"defaults whose key is the build timestamp key should not be set": {
+			target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"},
+				BuildInfo: &trustapi.BuildInfo{Mode: trustapi.BuildInfoModeInformative, TimestampKey: "cacerts"},
+			},
+			expTarget: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: "ca.crt"},
+				BuildInfo:         &trustapi.BuildInfo{Mode: trustapi.BuildInfoModeInformative, TimestampKey: "cacerts"},
+				AdditionalFormats: &trustapi.AdditionalFormats{PKCS7: &trustapi.KeySelector{Key: "bundle.p7b"}},
+			},
+			expChanged: true,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			formats := defaults
+			if test.defaults != nil {
+				formats = test.defaults
+			}
+
+			bundle := &trustapi.Bundle{Spec: trustapi.BundleSpec{Target: test.target}}
+			changed := defaultAdditionalFormats(bundle, formats)
+			assert.Equal(t, test.expChanged, changed)
+			assert.Equal(t, test.expTarget, bundle.Spec.Target)
+		})
+	}
+}
+
+func Test_defaulter(t *testing.T) {
+	defaults := &trustapi.AdditionalFormats{
+		JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "cacerts"}},
+	}
+
+	tests := map[string]struct {
+		operation  admissionv1.Operation
+		target     trustapi.BundleTarget
+		expPatched bool
+	}{
+		"created Bundle should be defaulted": {
+			operation:  admissionv1.Create,
+			target:     trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			expPatched: true,
+		},
+		"update removing a defaulted format should not be defaulted again": {
+			operation:  admissionv1.Update,
+			target:     trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "ca.crt"}},
+			expPatched: false,
+		},
+	}
+
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			decoder, err := admission.NewDecoder(trustapi.GlobalScheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			d := &defaulter{
+				log:     klogr.New(),
+				formats: defaults,
+				decoder: decoder,
+			}
+
+			bundle := &trustapi.Bundle{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "trust.cert-manager.io/v1alpha1", Kind: "Bundle"},
+				ObjectMeta: metav1.ObjectMeta{Name: "bundle"},
+				Spec:       trustapi.BundleSpec{Target: test.target},
+			}
+			raw, err := json.Marshal(bundle)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp := d.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: test.operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			assert.True(t, resp.Allowed, resp.Result)
+			assert.Equal(t, test.expPatched, len(resp.Patches) > 0, resp.Patches)
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+)
+
+// injectVolumeName is the name of the volume which injected Bundles are
+// mounted from. Pods which already have a volume of this name are not
+// mutated, so that injection is idempotent.
+const injectVolumeName = "trust-manager-bundle"
+
+// injector is a mutating webhook which mounts the target ConfigMap of the
+// Bundle named by the inject annotation of a Pod into all of its containers,
+// so that workloads don't need to declare the volume themselves.
+type injector struct {
+	log    logr.Logger
+	reader client.Reader
+	naming *naming.Conventions
+
+	decoder *admission.Decoder
+
+	lock sync.RWMutex
+}
+
+// Handle is a mutating webhook handler for Pods.
+func (i *injector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	log := i.log.WithValues("name", req.Name, "namespace", req.Namespace)
+
+	var pod corev1.Pod
+
+	i.lock.RLock()
+	err := i.decoder.Decode(req, &pod)
+	i.lock.RUnlock()
+
+	if err != nil {
+		log.Error(err, "failed to decode Pod")
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	bundleName := pod.Annotations[trustapi.PodInjectBundleAnnotationKey]
+	if len(bundleName) == 0 {
+		return admission.Allowed("no Bundle to inject")
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == injectVolumeName {
+			return admission.Allowed("Bundle already injected")
+		}
+	}
+
+	volume, mountPath, err := i.injectVolume(ctx, bundleName, pod.Annotations)
+	if errors.As(err, &injectError{}) {
+		return admission.Denied(err.Error())
+	}
+	if err != nil {
+		log.Error(err, "internal error occurred injecting Bundle")
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	injectBundle(&pod, volume, mountPath)
+
+	marshaled, err := json.Marshal(&pod)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	log.V(2).Info("injected Bundle", "bundle", bundleName)
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// injectError is an error caused by the annotations of a Pod, which denies
+// the Pod.
+type injectError struct{ error }
+
+// injectVolume returns the volume projecting the requested format of the
+// named Bundle's target ConfigMap, and the directory to mount it at.
+func (i *injector) injectVolume(ctx context.Context, bundleName string, annotations map[string]string) (corev1.Volume, string, error) {
+	mountPath := annotations[trustapi.PodInjectMountPathAnnotationKey]
+	if len(mountPath) == 0 {
+		mountPath = trustapi.DefaultInjectMountPath
+	}
+	if !path.IsAbs(mountPath) {
+		return corev1.Volume{}, "", injectError{fmt.Errorf("annotation %s must be an absolute path, got %q", trustapi.PodInjectMountPathAnnotationKey, mountPath)}
+	}
+
+	var bundle trustapi.Bundle
+	err := i.reader.Get(ctx, client.ObjectKey{Name: bundleName}, &bundle)
+	if apierrors.IsNotFound(err) {
+		return corev1.Volume{}, "", injectError{fmt.Errorf("Bundle %q requested by annotation %s does not exist", bundleName, trustapi.PodInjectBundleAnnotationKey)}
+	}
+	if err != nil {
+		return corev1.Volume{}, "", fmt.Errorf("failed to get Bundle %q: %w", bundleName, err)
+	}
+
+	target := bundle.Spec.Target
+	if target.ConfigMap == nil {
+		return corev1.Volume{}, "", injectError{fmt.Errorf("Bundle %q has no ConfigMap target to inject", bundleName)}
+	}
+
+	var key string
+	switch format := annotations[trustapi.PodInjectFormatAnnotationKey]; format {
+	case "", "pem":
+		key = target.ConfigMap.Key
+	case "jks":
+		if target.AdditionalFormats == nil || target.AdditionalFormats.JKS == nil {
+			return corev1.Volume{}, "", injectError{fmt.Errorf("Bundle %q does not write the JKS format", bundleName)}
+		}
+		key = target.AdditionalFormats.JKS.Key
+	default:
+		return corev1.Volume{}, "", injectError{fmt.Errorf("annotation %s must be one of pem or jks, got %q", trustapi.PodInjectFormatAnnotationKey, format)}
+	}
+
+	targetName, err := i.naming.BundleTargetName(bundle.Name, target)
+	if err != nil {
+		return corev1.Volume{}, "", err
+	}
+
+	return corev1.Volume{
+		Name: injectVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: targetName},
+				Items:                []corev1.KeyToPath{{Key: key, Path: key}},
+			},
+		},
+	}, mountPath, nil
+}
+
+// injectBundle adds the volume to the Pod, and mounts it read-only at the
+// mount path of every container which doesn't already mount a volume there.
+func injectBundle(pod *corev1.Pod, volume corev1.Volume, mountPath string) {
+	pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+
+	mount := func(containers []corev1.Container) {
+		for i := range containers {
+			mounted := false
+			for _, volumeMount := range containers[i].VolumeMounts {
+				if path.Clean(volumeMount.MountPath) == path.Clean(mountPath) {
+					mounted = true
+					break
+				}
+			}
+			if !mounted {
+				containers[i].VolumeMounts = append(containers[i].VolumeMounts, corev1.VolumeMount{
+					Name:      volume.Name,
+					MountPath: mountPath,
+					ReadOnly:  true,
+				})
+			}
+		}
+	}
+
+	mount(pod.Spec.InitContainers)
+	mount(pod.Spec.Containers)
+}
+
+// InjectDecoder is used by the controller-runtime manager to inject an object
+// decoder to convert into Pods.
+func (i *injector) InjectDecoder(d *admission.Decoder) error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	i.decoder = d
+	return nil
+}
+
+// check is used by the shared readiness manager to expose whether the server
+// is ready.
+func (i *injector) check(_ *http.Request) error {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	if i.decoder != nil {
+		return nil
+	}
+
+	return errors.New("not ready")
+}
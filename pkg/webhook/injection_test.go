@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The cert-manager Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2/klogr"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+)
+
+func Test_injector(t *testing.T) {
+	bundles := []runtime.Object{
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-a"},
+			Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap:         &trustapi.TargetKeySelector{Key: "ca.crt"},
+				AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "ca.jks"}}},
+			}},
+		},
+		&trustapi.Bundle{
+			ObjectMeta: metav1.ObjectMeta{Name: "bundle-b"},
+			Spec: trustapi.BundleSpec{Target: trustapi.BundleTarget{
+				ConfigMap: &trustapi.TargetKeySelector{Name: "custom-target", Key: "ca.crt"},
+			}},
+		},
+	}
+
+	volume := func(name, key string) corev1.Volume {
+		return corev1.Volume{
+			Name: injectVolumeName,
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: name},
+				Items:                []corev1.KeyToPath{{Key: key, Path: key}},
+			}},
+		}
+	}
+	mount := func(path string) corev1.VolumeMount {
+		return corev1.VolumeMount{Name: injectVolumeName, MountPath: path, ReadOnly: true}
+	}
+
+	tests := map[string]struct {
+		annotations map[string]string
+		spec        corev1.PodSpec
+
+		expAllowed bool
+		expPatched bool
+		expSpec    corev1.PodSpec
+	}{
+		"Pod without annotation should not be mutated": {
+			spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			expAllowed: true,
+		},
+		"Pod with annotation should have Bundle mounted at the default path": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-a"},
+			spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "init"}},
+				Containers:     []corev1.Container{{Name: "app"}, {Name: "sidecar"}},
+			},
+			expAllowed: true,
+			expPatched: true,
+			expSpec: corev1.PodSpec{
+				Volumes:        []corev1.Volume{volume("bundle-a", "ca.crt")},
+				InitContainers: []corev1.Container{{Name: "init", VolumeMounts: []corev1.VolumeMount{mount(trustapi.DefaultInjectMountPath)}}},
+				Containers: []corev1.Container{
+					{Name: "app", VolumeMounts: []corev1.VolumeMount{mount(trustapi.DefaultInjectMountPath)}},
+					{Name: "sidecar", VolumeMounts: []corev1.VolumeMount{mount(trustapi.DefaultInjectMountPath)}},
+				},
+			},
+		},
+		"Pod requesting JKS format and mount path should have JKS mounted at the path": {
+			annotations: map[string]string{
+				trustapi.PodInjectBundleAnnotationKey:    "bundle-a",
+				trustapi.PodInjectFormatAnnotationKey:    "jks",
+				trustapi.PodInjectMountPathAnnotationKey: "/etc/pki/java",
+			},
+			spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			expAllowed: true,
+			expPatched: true,
+			expSpec: corev1.PodSpec{
+				Volumes:    []corev1.Volume{volume("bundle-a", "ca.jks")},
+				Containers: []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{mount("/etc/pki/java")}}},
+			},
+		},
+		"Bundle with target name should mount the named ConfigMap": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-b"},
+			spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			expAllowed:  true,
+			expPatched:  true,
+			expSpec: corev1.PodSpec{
+				Volumes:    []corev1.Volume{volume("custom-target", "ca.crt")},
+				Containers: []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{mount(trustapi.DefaultInjectMountPath)}}},
+			},
+		},
+		"container already mounting the path should not be mounted again": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-a"},
+			spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "app", VolumeMounts: []corev1.VolumeMount{{Name: "own", MountPath: trustapi.DefaultInjectMountPath + "/"}}},
+				{Name: "sidecar"},
+			}},
+			expAllowed: true,
+			expPatched: true,
+			expSpec: corev1.PodSpec{
+				Volumes: []corev1.Volume{volume("bundle-a", "ca.crt")},
+				Containers: []corev1.Container{
+					{Name: "app", VolumeMounts: []corev1.VolumeMount{{Name: "own", MountPath: trustapi.DefaultInjectMountPath + "/"}}},
+					{Name: "sidecar", VolumeMounts: []corev1.VolumeMount{mount(trustapi.DefaultInjectMountPath)}},
+				},
+			},
+		},
+		"Pod which already has the volume should not be mutated": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-a"},
+			spec: corev1.PodSpec{
+				Volumes:    []corev1.Volume{volume("bundle-a", "ca.crt")},
+				Containers: []corev1.Container{{Name: "app"}},
+			},
+			expAllowed: true,
+		},
+		"JKS format of Bundle without JKS should be denied": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-b", trustapi.PodInjectFormatAnnotationKey: "jks"},
+			spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+		"unknown format should be denied": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-a", trustapi.PodInjectFormatAnnotationKey: "p7b"},
+			spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+		"relative mount path should be denied": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-a", trustapi.PodInjectMountPathAnnotationKey: "certs"},
+			spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+		"missing Bundle should be denied": {
+			annotations: map[string]string{trustapi.PodInjectBundleAnnotationKey: "bundle-c"},
+			spec:        corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			decoder, err := admission.NewDecoder(trustapi.GlobalScheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			i := &injector{
+				log:     klogr.New(),
+				reader:  fakeclient.NewClientBuilder().WithScheme(trustapi.GlobalScheme).WithRuntimeObjects(bundles...).Build(),
+				decoder: decoder,
+			}
+
+			pod := &corev1.Pod{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "app", Annotations: test.annotations},
+				Spec:       test.spec,
+			}
+			raw, err := json.Marshal(pod)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp := i.Handle(context.TODO(), admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: raw},
+			}})
+
+			assert.Equal(t, test.expAllowed, resp.Allowed, resp.Result)
+			assert.Equal(t, test.expPatched, len(resp.Patches) > 0, resp.Patches)
+
+			if !test.expPatched {
+				return
+			}
+
+			patch, err := json.Marshal(resp.Patches)
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded, err := jsonpatch.DecodePatch(patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			patched, err := decoded.Apply(raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got corev1.Pod
+			if err := json.Unmarshal(patched, &got); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.expSpec, got.Spec)
+		})
+	}
+}
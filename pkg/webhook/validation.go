@@ -18,25 +18,69 @@ package webhook
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/cert-manager/trust-manager/pkg/apis/trust"
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/pkg/util"
+)
+
+var (
+	// supportedKeyUsages are the key usages which certificates can be
+	// filtered by, for use in validation errors.
+	supportedKeyUsages = supportedValues(util.KeyUsages)
+
+	// supportedExtendedKeyUsages are the extended key usages which
+	// certificates can be filtered by, for use in validation errors.
+	supportedExtendedKeyUsages = supportedValues(util.ExtendedKeyUsages)
+
+	// supportedSignatureAlgorithms are the signature algorithms which can be
+	// configured as weak, for use in validation errors.
+	supportedSignatureAlgorithms = supportedValues(util.SignatureAlgorithms)
+
+	// supportedTrustPurposes are the purposes which certificates can be
+	// trusted for, for use in validation errors.
+	supportedTrustPurposes = []string{
+		string(trustapi.TrustPurposeServerAuth), string(trustapi.TrustPurposeClientAuth), string(trustapi.TrustPurposeAny),
+	}
+
+	// ociRepositoryName and ociTag match the repository names and tags
+	// accepted by the OCI distribution specification.
+	ociRepositoryName = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*(/[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*)*$`)
+	ociTag            = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+	// hashedDirectoryEntryKey matches the keys of the certificate entries of
+	// a hashed directory written in Keys packaging.
+	hashedDirectoryEntryKey = regexp.MustCompile(`^[0-9a-f]{8}\.[0-9]+$`)
 )
 
 // validator validates against trust.cert-manager.io resources.
 type validator struct {
 	log logr.Logger
 
+	// naming are the conventions used to resolve the names of the targets of
+	// Bundles.
+	naming *naming.Conventions
+
 	decoder *admission.Decoder
 
 	lock sync.RWMutex
@@ -53,8 +97,10 @@ func (v *validator) Handle(ctx context.Context, req admission.Request) admission
 	}
 
 	var (
-		el  field.ErrorList
-		err error
+		el       field.ErrorList
+		warnings []string
+		message  string
+		err      error
 	)
 
 	switch *req.RequestKind {
@@ -71,6 +117,22 @@ func (v *validator) Handle(ctx context.Context, req admission.Request) admission
 		}
 
 		el, err = v.validateBundle(ctx, &bundle)
+		message = "Bundle validated"
+
+	case metav1.GroupVersionKind{Group: corev1.GroupName, Version: "v1", Kind: "Namespace"}:
+		var namespace corev1.Namespace
+
+		v.lock.RLock()
+		err := v.decoder.Decode(req, &namespace)
+		v.lock.RUnlock()
+
+		if err != nil {
+			log.Error(err, "failed to decode Namespace")
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+
+		el, warnings = validateNamespace(&namespace)
+		message = "Namespace validated"
 
 	default:
 		return admission.Denied(fmt.Sprintf("validation request for unrecognised resource type: %s/%s %s", req.RequestKind.Group, req.RequestKind.Version, req.RequestKind.Kind))
@@ -83,11 +145,53 @@ func (v *validator) Handle(ctx context.Context, req admission.Request) admission
 
 	if err := el.ToAggregate(); err != nil {
 		v.log.V(2).Info("denied request", "reason", el.ToAggregate().Error())
-		return admission.Denied(el.ToAggregate().Error())
+		return admission.Denied(el.ToAggregate().Error()).WithWarnings(warnings...)
 	}
 
 	log.V(2).Info("allowed request")
-	return admission.Allowed("Bundle validated")
+	return admission.Allowed(message).WithWarnings(warnings...)
+}
+
+// validateNamespace validates the trust.cert-manager.io annotations of the
+// incoming Namespace object. Returns any invalid annotation values as errors,
+// and any unknown trust.cert-manager.io annotations, which are likely to be
+// typos, as warnings.
+func validateNamespace(namespace *corev1.Namespace) (field.ErrorList, []string) {
+	var (
+		el       field.ErrorList
+		warnings []string
+	)
+	path := field.NewPath("metadata", "annotations")
+
+	keys := make([]string, 0, len(namespace.Annotations))
+	for key := range namespace.Annotations {
+		if strings.HasPrefix(key, trust.GroupName+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := namespace.Annotations[key]
+
+		switch key {
+		case trustapi.NamespaceSkipTargetsAnnotationKey:
+			if value != "true" && value != "false" {
+				el = append(el, field.NotSupported(path.Key(key), value, []string{"true", "false"}))
+			}
+
+		case trustapi.NamespaceTargetKeyAnnotationKey:
+			for _, msg := range validation.IsConfigMapKey(value) {
+				el = append(el, field.Invalid(path.Key(key), value, msg))
+			}
+
+		default:
+			warnings = append(warnings, fmt.Sprintf("unknown annotation %q is ignored by trust-manager; supported Namespace annotations are %q and %q",
+				key, trustapi.NamespaceSkipTargetsAnnotationKey, trustapi.NamespaceTargetKeyAnnotationKey))
+		}
+	}
+
+	return el, warnings
 }
 
 // validateBundle validates the incoming Bundle object and returns any
@@ -102,6 +206,7 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 		path := path.Child("sources")
 
 		defaultCAsCount := 0
+		namedDefaultCAs := make(map[string]struct{})
 
 		for i, source := range bundle.Spec.Sources {
 			path := path.Child("[" + strconv.Itoa(i) + "]")
@@ -115,9 +220,7 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 				if len(configMap.Name) == 0 {
 					el = append(el, field.Invalid(path.Child("name"), configMap.Name, "source configMap name must be defined"))
 				}
-				if len(configMap.Key) == 0 {
-					el = append(el, field.Invalid(path.Child("key"), configMap.Key, "source configMap key must be defined"))
-				}
+				el = append(el, validateSourceObjectKey(path, configMap, "source configMap")...)
 			}
 
 			if secret := source.Secret; secret != nil {
@@ -127,8 +230,126 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 				if len(secret.Name) == 0 {
 					el = append(el, field.Invalid(path.Child("name"), secret.Name, "source secret name must be defined"))
 				}
-				if len(secret.Key) == 0 {
-					el = append(el, field.Invalid(path.Child("key"), secret.Key, "source secret key must be defined"))
+				el = append(el, validateSourceObjectKey(path, secret, "source secret")...)
+			}
+
+			if tlsSecret := source.TLSSecret; tlsSecret != nil {
+				path := path.Child("tlsSecret")
+				unionCount++
+
+				if len(tlsSecret.Name) == 0 {
+					el = append(el, field.Invalid(path.Child("name"), tlsSecret.Name, "source tlsSecret name must be defined"))
+				}
+			}
+
+			if istioCACerts := source.IstioCACertsSecret; istioCACerts != nil {
+				path := path.Child("istioCACertsSecret")
+				unionCount++
+
+				if len(istioCACerts.Name) == 0 {
+					el = append(el, field.Invalid(path.Child("name"), istioCACerts.Name, "source istioCACertsSecret name must be defined"))
+				}
+			}
+
+			if truststore := source.TruststoreSecret; truststore != nil {
+				path := path.Child("truststoreSecret")
+				unionCount++
+
+				if len(truststore.Name) == 0 {
+					el = append(el, field.Invalid(path.Child("name"), truststore.Name, "source truststoreSecret name must be defined"))
+				}
+				if len(truststore.Key) == 0 {
+					el = append(el, field.Invalid(path.Child("key"), truststore.Key, "source truststoreSecret key must be defined"))
+				}
+				if len(truststore.KeyPattern) > 0 {
+					el = append(el, field.Forbidden(path.Child("keyPattern"), "source truststoreSecret does not support keyPattern"))
+				}
+				if len(truststore.ExcludeKeys) > 0 {
+					el = append(el, field.Forbidden(path.Child("excludeKeys"), "source truststoreSecret does not support excludeKeys"))
+				}
+
+				switch truststore.Format {
+				case trustapi.TruststoreFormatJKS, trustapi.TruststoreFormatPKCS12:
+				default:
+					el = append(el, field.NotSupported(path.Child("format"), truststore.Format, []string{string(trustapi.TruststoreFormatJKS), string(trustapi.TruststoreFormatPKCS12)}))
+				}
+
+				if len(truststore.PasswordKey) > 0 && truststore.PasswordKey == truststore.Key {
+					el = append(el, field.Invalid(path.Child("passwordKey"), truststore.PasswordKey, "source truststoreSecret passwordKey must be different to key"))
+				}
+			}
+
+			if objectStorage := source.ObjectStorage; objectStorage != nil {
+				path := path.Child("objectStorage")
+				unionCount++
+
+				switch objectStorage.Provider {
+				case trustapi.ObjectStorageProviderS3, trustapi.ObjectStorageProviderGCS, trustapi.ObjectStorageProviderAzureBlob:
+				default:
+					el = append(el, field.NotSupported(path.Child("provider"), objectStorage.Provider, []string{
+						string(trustapi.ObjectStorageProviderS3), string(trustapi.ObjectStorageProviderGCS), string(trustapi.ObjectStorageProviderAzureBlob),
+					}))
+				}
+
+				if len(objectStorage.Bucket) == 0 {
+					el = append(el, field.Invalid(path.Child("bucket"), objectStorage.Bucket, "source objectStorage bucket must be defined"))
+				}
+				if len(objectStorage.Key) == 0 {
+					el = append(el, field.Invalid(path.Child("key"), objectStorage.Key, "source objectStorage key must be defined"))
+				}
+				if objectStorage.Provider == trustapi.ObjectStorageProviderAzureBlob && len(objectStorage.Account) == 0 && len(objectStorage.Endpoint) == 0 {
+					el = append(el, field.Invalid(path.Child("account"), objectStorage.Account, "source objectStorage account must be defined for AzureBlob"))
+				}
+				if len(objectStorage.Endpoint) > 0 {
+					if endpoint, err := url.Parse(objectStorage.Endpoint); err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || len(endpoint.Host) == 0 {
+						el = append(el, field.Invalid(path.Child("endpoint"), objectStorage.Endpoint, "source objectStorage endpoint must be an absolute http or https URL"))
+					}
+				}
+				if objectStorage.RefreshInterval != nil && objectStorage.RefreshInterval.Duration <= 0 {
+					el = append(el, field.Invalid(path.Child("refreshInterval"), objectStorage.RefreshInterval.Duration.String(), "source objectStorage refreshInterval must be positive"))
+				}
+			}
+
+			if remote := source.RemoteCluster; remote != nil {
+				path := path.Child("remoteCluster")
+				unionCount++
+
+				if len(remote.KubeconfigSecret.Name) == 0 {
+					el = append(el, field.Invalid(path.Child("kubeconfigSecret", "name"), remote.KubeconfigSecret.Name, "source remoteCluster kubeconfigSecret name must be defined"))
+				}
+				if len(remote.KubeconfigSecret.Key) == 0 {
+					el = append(el, field.Invalid(path.Child("kubeconfigSecret", "key"), remote.KubeconfigSecret.Key, "source remoteCluster kubeconfigSecret key must be defined"))
+				}
+				if len(remote.KubeconfigSecret.KeyPattern) > 0 {
+					el = append(el, field.Forbidden(path.Child("kubeconfigSecret", "keyPattern"), "source remoteCluster kubeconfigSecret does not support keyPattern"))
+				}
+				if len(remote.KubeconfigSecret.ExcludeKeys) > 0 {
+					el = append(el, field.Forbidden(path.Child("kubeconfigSecret", "excludeKeys"), "source remoteCluster kubeconfigSecret does not support excludeKeys"))
+				}
+				if len(remote.Namespace) == 0 {
+					el = append(el, field.Invalid(path.Child("namespace"), remote.Namespace, "source remoteCluster namespace must be defined"))
+				}
+
+				var objectCount int
+				for _, object := range []struct {
+					name string
+					ref  *trustapi.SourceObjectKeySelector
+				}{{"configMap", remote.ConfigMap}, {"secret", remote.Secret}} {
+					if object.ref == nil {
+						continue
+					}
+					objectCount++
+
+					if len(object.ref.Name) == 0 {
+						el = append(el, field.Invalid(path.Child(object.name, "name"), object.ref.Name, fmt.Sprintf("source remoteCluster %s name must be defined", object.name)))
+					}
+					el = append(el, validateSourceObjectKey(path.Child(object.name), object.ref, "source remoteCluster "+object.name)...)
+				}
+				if objectCount != 1 {
+					el = append(el, field.Forbidden(path, fmt.Sprintf("must define exactly one of configMap or secret but found %d", objectCount)))
+				}
+				if remote.RefreshInterval != nil && remote.RefreshInterval.Duration <= 0 {
+					el = append(el, field.Invalid(path.Child("refreshInterval"), remote.RefreshInterval.Duration.String(), "source remoteCluster refreshInterval must be positive"))
 				}
 			}
 
@@ -136,16 +357,96 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 				unionCount++
 			}
 
+			if len(source.InLineDER) > 0 {
+				unionCount++
+
+				for i, der := range source.InLineDER {
+					if _, err := x509.ParseCertificate(der); err != nil {
+						el = append(el, field.Invalid(path.Child("inLineDER", "["+strconv.Itoa(i)+"]"), fmt.Sprintf("%d bytes", len(der)), "source inLineDER entry must be a DER-encoded certificate"))
+					}
+				}
+			}
+
 			if source.UseDefaultCAs != nil && *source.UseDefaultCAs {
 				unionCount++
 				defaultCAsCount++
 			}
 
+			if source.UseClusterAPIServerCA != nil && *source.UseClusterAPIServerCA {
+				unionCount++
+			}
+
+			if source.UseNodeOSCAs != nil && *source.UseNodeOSCAs {
+				unionCount++
+			}
+
+			if source.UseOpenShiftTrustedCA != nil && *source.UseOpenShiftTrustedCA {
+				unionCount++
+			}
+
+			if defaultCAs := source.DefaultCAs; defaultCAs != nil {
+				unionCount++
+
+				if len(defaultCAs.Package) == 0 {
+					defaultCAsCount++
+				} else if _, ok := namedDefaultCAs[defaultCAs.Package]; ok {
+					el = append(el, field.Duplicate(path.Child("defaultCAs", "package"), defaultCAs.Package))
+				} else {
+					namedDefaultCAs[defaultCAs.Package] = struct{}{}
+				}
+
+				chain := map[string]struct{}{defaultCAs.Package: {}}
+				for j, fallback := range defaultCAs.Fallback {
+					path := path.Child("defaultCAs", "fallback", "["+strconv.Itoa(j)+"]")
+
+					if len(fallback) == 0 {
+						el = append(el, field.Invalid(path, fallback, "source defaultCAs fallback package name must be defined"))
+					} else if _, ok := chain[fallback]; ok {
+						el = append(el, field.Duplicate(path, fallback))
+					}
+					chain[fallback] = struct{}{}
+				}
+
+				if exclude := defaultCAs.Exclude; exclude != nil {
+					for j, commonName := range exclude.CommonNames {
+						if len(commonName) == 0 {
+							el = append(el, field.Invalid(path.Child("defaultCAs", "exclude", "commonNames", "["+strconv.Itoa(j)+"]"), commonName, "source defaultCAs excluded common name must be defined"))
+						}
+					}
+					for j, fingerprint := range exclude.Fingerprints {
+						if _, err := util.ParseFingerprint(fingerprint); err != nil {
+							el = append(el, field.Invalid(path.Child("defaultCAs", "exclude", "fingerprints", "["+strconv.Itoa(j)+"]"), fingerprint, err.Error()))
+						}
+					}
+				}
+			}
+
 			if unionCount != 1 {
 				el = append(el, field.Forbidden(
 					path, fmt.Sprintf("must define exactly one source type for each item but found %d defined types", unionCount),
 				))
 			}
+
+			if len(source.Labels) > 0 {
+				el = append(el, metav1validation.ValidateLabels(source.Labels, path.Child("labels"))...)
+			}
+
+			for j, purpose := range source.Purposes {
+				el = append(el, validateTrustPurpose(path.Child("purposes", "["+strconv.Itoa(j)+"]"), purpose)...)
+			}
+
+			if filters := source.Filters; filters != nil {
+				path := path.Child("filters")
+
+				el = append(el, validateFilters(path, filters)...)
+
+				if filters.DeduplicateByPublicKey {
+					el = append(el, field.Forbidden(path.Child("deduplicateByPublicKey"), "deduplicateByPublicKey filter applies across sources and may only be set on the Bundle"))
+				}
+				if len(filters.CrossSigned) > 0 {
+					el = append(el, field.Forbidden(path.Child("crossSigned"), "crossSigned filter applies across sources and may only be set on the Bundle"))
+				}
+			}
 		}
 
 		if defaultCAsCount > 1 {
@@ -157,9 +458,14 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 	}
 
 	if target := bundle.Spec.Target.ConfigMap; target != nil {
+		targetName, err := v.naming.BundleTargetName(bundle.Name, bundle.Spec.Target)
+		if err != nil {
+			return nil, err
+		}
+
 		path := path.Child("sources")
 		for i, source := range bundle.Spec.Sources {
-			if source.ConfigMap != nil && source.ConfigMap.Name == bundle.Name && source.ConfigMap.Key == target.Key {
+			if source.ConfigMap != nil && source.ConfigMap.Name == targetName && source.ConfigMap.Key == target.Key {
 				el = append(el, field.Forbidden(path.Child(fmt.Sprintf("[%d]", i), "configMap", source.ConfigMap.Name, source.ConfigMap.Key), "cannot define the same source as target"))
 			}
 		}
@@ -175,11 +481,800 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 		}
 	}
 
+	if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && len(configMap.Name) > 0 {
+		for _, msg := range validation.IsDNS1123Subdomain(configMap.Name) {
+			el = append(el, field.Invalid(path.Child("target", "configMap", "name"), configMap.Name, msg))
+		}
+	}
+
+	if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+		path := path.Child("target", "configMap", "format")
+
+		switch configMap.Format {
+		case "", trustapi.TargetFormatPEM:
+		case trustapi.TargetFormatDER:
+			// Partitions are written as PEM to the data field of the target.
+			if sizeLimit := bundle.Spec.Target.SizeLimit; sizeLimit != nil && sizeLimit.Policy == trustapi.TargetSizeLimitPolicyPartition {
+				el = append(el, field.Forbidden(path, "target configMap DER format cannot be used with the Partition sizeLimit policy"))
+			}
+		default:
+			el = append(el, field.NotSupported(path, configMap.Format, []string{string(trustapi.TargetFormatPEM), string(trustapi.TargetFormatDER)}))
+		}
+	}
+
+	if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Comments {
+		path := path.Child("target", "configMap", "comments")
+
+		if configMap.Format == trustapi.TargetFormatDER {
+			el = append(el, field.Forbidden(path, "target configMap comments can only be used with the PEM format"))
+		}
+
+		if sizeLimit := bundle.Spec.Target.SizeLimit; sizeLimit != nil && sizeLimit.Policy == trustapi.TargetSizeLimitPolicyPartition {
+			el = append(el, field.Forbidden(path, "target configMap comments cannot be used with the Partition sizeLimit policy"))
+		}
+	}
+
+	if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.PreviousKeyRetention != nil {
+		path := path.Child("target", "configMap", "previousKeyRetention")
+
+		if configMap.PreviousKeyRetention.Duration <= 0 {
+			el = append(el, field.Invalid(path, configMap.PreviousKeyRetention.Duration.String(), "target configMap previousKeyRetention must be positive"))
+		}
+
+		if configMap.Format == trustapi.TargetFormatDER {
+			el = append(el, field.Forbidden(path, "target configMap previousKeyRetention can only be used with the PEM format"))
+		}
+
+		if sizeLimit := bundle.Spec.Target.SizeLimit; sizeLimit != nil && sizeLimit.Policy == trustapi.TargetSizeLimitPolicyPartition {
+			el = append(el, field.Forbidden(path, "target configMap previousKeyRetention cannot be used with the Partition sizeLimit policy"))
+		}
+
+		if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Gzip != nil && formats.Gzip.OmitUncompressed {
+			el = append(el, field.Forbidden(path, "target configMap previousKeyRetention cannot be used with gzip omitUncompressed"))
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.JKS != nil {
+		path := path.Child("target", "additionalFormats", "jks")
+
+		if formats.JKS.Password != nil && formats.JKS.PasswordFrom != nil {
+			el = append(el, field.Forbidden(path.Child("passwordFrom"), "target JKS password and passwordFrom are mutually exclusive"))
+		}
+
+		if passwordFrom := formats.JKS.PasswordFrom; passwordFrom != nil {
+			el = append(el, validatePasswordSource(path.Child("passwordFrom"), passwordFrom)...)
+		}
+
+		switch formats.JKS.AliasNaming {
+		case "", trustapi.JKSAliasNamingFingerprint, trustapi.JKSAliasNamingSubjectCN, trustapi.JKSAliasNamingSequential:
+		default:
+			el = append(el, field.NotSupported(path.Child("aliasNaming"), formats.JKS.AliasNaming, []string{string(trustapi.JKSAliasNamingFingerprint), string(trustapi.JKSAliasNamingSubjectCN), string(trustapi.JKSAliasNamingSequential)}))
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PKCS12 != nil {
+		if formats.PKCS12.Password != nil && formats.PKCS12.PasswordFrom != nil {
+			el = append(el, field.Forbidden(path.Child("target", "additionalFormats", "pkcs12", "passwordFrom"), "target PKCS12 password and passwordFrom are mutually exclusive"))
+		}
+
+		if passwordFrom := formats.PKCS12.PasswordFrom; passwordFrom != nil {
+			el = append(el, validatePasswordSource(path.Child("target", "additionalFormats", "pkcs12", "passwordFrom"), passwordFrom)...)
+		}
+
+		switch formats.PKCS12.Profile {
+		case "", trustapi.PKCS12ProfileLegacyRC2, trustapi.PKCS12ProfileLegacyDES, trustapi.PKCS12ProfileModern2023:
+		default:
+			el = append(el, field.NotSupported(path.Child("target", "additionalFormats", "pkcs12", "profile"), formats.PKCS12.Profile, []string{string(trustapi.PKCS12ProfileLegacyRC2), string(trustapi.PKCS12ProfileLegacyDES), string(trustapi.PKCS12ProfileModern2023)}))
+		}
+
+		path := path.Child("target", "additionalFormats", "pkcs12", "key")
+		pkcs12Key := formats.PKCS12.Key
+
+		// The PKCS#12 truststore is written to the binaryData field, whose
+		// keys must not be used by any of the entries of the data field.
+		if len(pkcs12Key) == 0 {
+			el = append(el, field.Invalid(path, pkcs12Key, "target PKCS12 key must be defined"))
+		} else {
+			type targetKey struct{ name, key string }
+			var otherKeys []targetKey
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+				otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+			}
+			if formats.JKS != nil {
+				otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+			}
+			if formats.Metadata != nil {
+				otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+			}
+			if formats.SPIFFE != nil {
+				otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+			}
+			if formats.Provenance != nil {
+				otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+			}
+			for _, profile := range formats.Profiles {
+				otherKeys = append(otherKeys, targetKey{"profile", profile.Key})
+			}
+			for _, other := range otherKeys {
+				if other.key == pkcs12Key {
+					el = append(el, field.Invalid(path, pkcs12Key, fmt.Sprintf("target PKCS12 key must be different to %s key", other.name)))
+				}
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PKCS7 != nil {
+		path := path.Child("target", "additionalFormats", "pkcs7", "key")
+		pkcs7Key := formats.PKCS7.Key
+
+		// As for PKCS#12, the PKCS#7 bundle is written to the binaryData
+		// field.
+		if len(pkcs7Key) == 0 {
+			el = append(el, field.Invalid(path, pkcs7Key, "target PKCS7 key must be defined"))
+		} else {
+			type targetKey struct{ name, key string }
+			var otherKeys []targetKey
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+				otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+			}
+			if formats.JKS != nil {
+				otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+			}
+			if formats.PKCS12 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS12", formats.PKCS12.Key})
+			}
+			if formats.Gzip != nil {
+				otherKeys = append(otherKeys, targetKey{"gzip", formats.Gzip.Key})
+			}
+			if formats.Metadata != nil {
+				otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+			}
+			if formats.SPIFFE != nil {
+				otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+			}
+			if formats.Provenance != nil {
+				otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+			}
+			for _, profile := range formats.Profiles {
+				otherKeys = append(otherKeys, targetKey{"profile", profile.Key})
+			}
+			for _, other := range otherKeys {
+				if other.key == pkcs7Key {
+					el = append(el, field.Invalid(path, pkcs7Key, fmt.Sprintf("target PKCS7 key must be different to %s key", other.name)))
+				}
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.SST != nil {
+		path := path.Child("target", "additionalFormats", "sst", "key")
+		sstKey := formats.SST.Key
+
+		// As for PKCS#12, the serialized certificate store is written to the
+		// binaryData field.
+		if len(sstKey) == 0 {
+			el = append(el, field.Invalid(path, sstKey, "target SST key must be defined"))
+		} else {
+			type targetKey struct{ name, key string }
+			var otherKeys []targetKey
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+				otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+			}
+			if formats.JKS != nil {
+				otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+			}
+			if formats.PKCS12 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS12", formats.PKCS12.Key})
+			}
+			if formats.PKCS7 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS7", formats.PKCS7.Key})
+			}
+			if formats.Gzip != nil {
+				otherKeys = append(otherKeys, targetKey{"gzip", formats.Gzip.Key})
+			}
+			if formats.Metadata != nil {
+				otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+			}
+			if formats.SPIFFE != nil {
+				otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+			}
+			if formats.Provenance != nil {
+				otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+			}
+			for _, profile := range formats.Profiles {
+				otherKeys = append(otherKeys, targetKey{"profile", profile.Key})
+			}
+			for _, other := range otherKeys {
+				if other.key == sstKey {
+					el = append(el, field.Invalid(path, sstKey, fmt.Sprintf("target SST key must be different to %s key", other.name)))
+				}
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Gzip != nil {
+		path := path.Child("target", "additionalFormats", "gzip")
+		gzipKey := formats.Gzip.Key
+
+		// As for PKCS#12, the compressed data is written to the binaryData
+		// field.
+		if len(gzipKey) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), gzipKey, "target gzip key must be defined"))
+		} else {
+			type targetKey struct{ name, key string }
+			var otherKeys []targetKey
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+				otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+			}
+			if formats.JKS != nil {
+				otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+			}
+			if formats.PKCS12 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS12", formats.PKCS12.Key})
+			}
+			if formats.Metadata != nil {
+				otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+			}
+			if formats.SPIFFE != nil {
+				otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+			}
+			if formats.Provenance != nil {
+				otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+			}
+			for _, profile := range formats.Profiles {
+				otherKeys = append(otherKeys, targetKey{"profile", profile.Key})
+			}
+			for _, other := range otherKeys {
+				if other.key == gzipKey {
+					el = append(el, field.Invalid(path.Child("key"), gzipKey, fmt.Sprintf("target gzip key must be different to %s key", other.name)))
+				}
+			}
+		}
+
+		// The Truncate and Partition policies split the uncompressed data,
+		// whose size doesn't apply when only the compressed data is written.
+		if sizeLimit := bundle.Spec.Target.SizeLimit; formats.Gzip.OmitUncompressed && sizeLimit != nil &&
+			(sizeLimit.Policy == trustapi.TargetSizeLimitPolicyTruncate || sizeLimit.Policy == trustapi.TargetSizeLimitPolicyPartition) {
+			el = append(el, field.Forbidden(path.Child("omitUncompressed"), fmt.Sprintf("target gzip omitUncompressed cannot be used with the %s sizeLimit policy", sizeLimit.Policy)))
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Metadata != nil {
+		path := path.Child("target", "additionalFormats", "metadata", "key")
+		metadataKey := formats.Metadata.Key
+
+		if len(metadataKey) == 0 {
+			el = append(el, field.Invalid(path, metadataKey, "target metadata key must be defined"))
+		} else {
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == metadataKey {
+				el = append(el, field.Invalid(path, metadataKey, "target metadata key must be different to configMap key"))
+			}
+			if formats.JKS != nil && formats.JKS.Key == metadataKey {
+				el = append(el, field.Invalid(path, metadataKey, "target metadata key must be different to JKS key"))
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.SPIFFE != nil {
+		path := path.Child("target", "additionalFormats", "spiffe", "key")
+		spiffeKey := formats.SPIFFE.Key
+
+		if len(spiffeKey) == 0 {
+			el = append(el, field.Invalid(path, spiffeKey, "target SPIFFE key must be defined"))
+		} else {
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == spiffeKey {
+				el = append(el, field.Invalid(path, spiffeKey, "target SPIFFE key must be different to configMap key"))
+			}
+			if formats.JKS != nil && formats.JKS.Key == spiffeKey {
+				el = append(el, field.Invalid(path, spiffeKey, "target SPIFFE key must be different to JKS key"))
+			}
+			if formats.Metadata != nil && formats.Metadata.Key == spiffeKey {
+				el = append(el, field.Invalid(path, spiffeKey, "target SPIFFE key must be different to metadata key"))
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Provenance != nil {
+		path := path.Child("target", "additionalFormats", "provenance", "key")
+		provenanceKey := formats.Provenance.Key
+
+		if len(provenanceKey) == 0 {
+			el = append(el, field.Invalid(path, provenanceKey, "target provenance key must be defined"))
+		} else {
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == provenanceKey {
+				el = append(el, field.Invalid(path, provenanceKey, "target provenance key must be different to configMap key"))
+			}
+			if formats.JKS != nil && formats.JKS.Key == provenanceKey {
+				el = append(el, field.Invalid(path, provenanceKey, "target provenance key must be different to JKS key"))
+			}
+			if formats.Metadata != nil && formats.Metadata.Key == provenanceKey {
+				el = append(el, field.Invalid(path, provenanceKey, "target provenance key must be different to metadata key"))
+			}
+			if formats.SPIFFE != nil && formats.SPIFFE.Key == provenanceKey {
+				el = append(el, field.Invalid(path, provenanceKey, "target provenance key must be different to SPIFFE key"))
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil {
+		profileKeys := make(map[string]struct{}, len(formats.Profiles))
+		for i, profile := range formats.Profiles {
+			path := path.Child("target", "additionalFormats", "profiles", "["+strconv.Itoa(i)+"]")
+
+			el = append(el, validateTrustPurpose(path.Child("purpose"), profile.Purpose)...)
+
+			path = path.Child("key")
+			if len(profile.Key) == 0 {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be defined"))
+				continue
+			}
+			for _, msg := range validation.IsConfigMapKey(profile.Key) {
+				el = append(el, field.Invalid(path, profile.Key, msg))
+			}
+
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == profile.Key {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be different to configMap key"))
+			}
+			if formats.JKS != nil && formats.JKS.Key == profile.Key {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be different to JKS key"))
+			}
+			if formats.Metadata != nil && formats.Metadata.Key == profile.Key {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be different to metadata key"))
+			}
+			if formats.SPIFFE != nil && formats.SPIFFE.Key == profile.Key {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be different to SPIFFE key"))
+			}
+			if formats.Provenance != nil && formats.Provenance.Key == profile.Key {
+				el = append(el, field.Invalid(path, profile.Key, "target profile key must be different to provenance key"))
+			}
+			if _, ok := profileKeys[profile.Key]; ok {
+				el = append(el, field.Duplicate(path, profile.Key))
+			}
+			profileKeys[profile.Key] = struct{}{}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.PEMDirectory != nil {
+		path := path.Child("target", "additionalFormats", "pemDirectory")
+
+		prefix := formats.PEMDirectory.KeyPrefix
+		if len(prefix) == 0 {
+			prefix = trustapi.DefaultPEMDirectoryKeyPrefix
+		}
+
+		// The names of the certificates are validated using an example name
+		// of the longest length, and matched by a pattern below.
+		exampleName, namePattern, ok := pemDirectoryKeyNaming(formats.PEMDirectory.KeyNaming)
+		if !ok {
+			el = append(el, field.NotSupported(path.Child("keyNaming"), formats.PEMDirectory.KeyNaming, []string{
+				string(trustapi.PEMDirectoryKeyNamingPosition), string(trustapi.PEMDirectoryKeyNamingFingerprint), string(trustapi.PEMDirectoryKeyNamingSubjectHash),
+			}))
+		}
+
+		for _, msg := range validation.IsConfigMapKey(prefix + exampleName + ".pem") {
+			el = append(el, field.Invalid(path.Child("keyPrefix"), formats.PEMDirectory.KeyPrefix, msg))
+		}
+
+		indexKey := formats.PEMDirectory.IndexKey
+		if len(indexKey) == 0 {
+			indexKey = trustapi.DefaultPEMDirectoryIndexKey
+		}
+		for _, msg := range validation.IsConfigMapKey(indexKey) {
+			el = append(el, field.Invalid(path.Child("indexKey"), formats.PEMDirectory.IndexKey, msg))
+		}
+
+		// The index and the certificate entries must not overwrite the other
+		// entries of the target.
+		pemDirectoryKey := pemDirectoryEntryKey(prefix, namePattern)
+		type targetKey struct{ name, key string }
+		var otherKeys []targetKey
+		if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+			otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+		}
+		if formats.JKS != nil {
+			otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+		}
+		if formats.Metadata != nil {
+			otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+		}
+		if formats.SPIFFE != nil {
+			otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+		}
+		if formats.Provenance != nil {
+			otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+		}
+		for _, other := range otherKeys {
+			if other.key == indexKey {
+				el = append(el, field.Invalid(path.Child("indexKey"), indexKey, fmt.Sprintf("target pemDirectory indexKey must be different to %s key", other.name)))
+			}
+			if pemDirectoryKey.MatchString(other.key) {
+				el = append(el, field.Invalid(path.Child("keyPrefix"), prefix, fmt.Sprintf("target pemDirectory keys must not overwrite the %s key", other.name)))
+			}
+		}
+	}
+
+	if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.HashedDirectory != nil {
+		path := path.Child("target", "additionalFormats", "hashedDirectory")
+		hashedDirectory := formats.HashedDirectory
+
+		// The index key is only written in Keys packaging, and the tarball key
+		// only in Tarball packaging.
+		var key, keyField string
+		switch hashedDirectory.Packaging {
+		case "", trustapi.HashedDirectoryPackagingKeys:
+			if len(hashedDirectory.TarballKey) > 0 {
+				el = append(el, field.Forbidden(path.Child("tarballKey"), "target hashedDirectory tarballKey can only be set with Tarball packaging"))
+			}
+			key, keyField = hashedDirectory.IndexKey, "indexKey"
+			if len(key) == 0 {
+				key = trustapi.DefaultHashedDirectoryIndexKey
+			}
+		case trustapi.HashedDirectoryPackagingTarball:
+			if len(hashedDirectory.IndexKey) > 0 {
+				el = append(el, field.Forbidden(path.Child("indexKey"), "target hashedDirectory indexKey can only be set with Keys packaging"))
+			}
+			key, keyField = hashedDirectory.TarballKey, "tarballKey"
+			if len(key) == 0 {
+				key = trustapi.DefaultHashedDirectoryTarballKey
+			}
+		default:
+			el = append(el, field.NotSupported(path.Child("packaging"), hashedDirectory.Packaging, []string{string(trustapi.HashedDirectoryPackagingKeys), string(trustapi.HashedDirectoryPackagingTarball)}))
+		}
+
+		if len(keyField) > 0 {
+			for _, msg := range validation.IsConfigMapKey(key) {
+				el = append(el, field.Invalid(path.Child(keyField), key, msg))
+			}
+
+			// The index or tarball and the certificate entries must not
+			// overwrite the other entries of the target.
+			type targetKey struct{ name, key string }
+			var otherKeys []targetKey
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil {
+				otherKeys = append(otherKeys, targetKey{"configMap", configMap.Key})
+			}
+			if formats.JKS != nil {
+				otherKeys = append(otherKeys, targetKey{"JKS", formats.JKS.Key})
+			}
+			if formats.PKCS12 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS12", formats.PKCS12.Key})
+			}
+			if formats.PKCS7 != nil {
+				otherKeys = append(otherKeys, targetKey{"PKCS7", formats.PKCS7.Key})
+			}
+			if formats.Gzip != nil {
+				otherKeys = append(otherKeys, targetKey{"gzip", formats.Gzip.Key})
+			}
+			if formats.Metadata != nil {
+				otherKeys = append(otherKeys, targetKey{"metadata", formats.Metadata.Key})
+			}
+			if formats.SPIFFE != nil {
+				otherKeys = append(otherKeys, targetKey{"SPIFFE", formats.SPIFFE.Key})
+			}
+			if formats.Provenance != nil {
+				otherKeys = append(otherKeys, targetKey{"provenance", formats.Provenance.Key})
+			}
+			if formats.PEMDirectory != nil {
+				indexKey := formats.PEMDirectory.IndexKey
+				if len(indexKey) == 0 {
+					indexKey = trustapi.DefaultPEMDirectoryIndexKey
+				}
+				otherKeys = append(otherKeys, targetKey{"pemDirectory index", indexKey})
+			}
+			for _, profile := range formats.Profiles {
+				otherKeys = append(otherKeys, targetKey{"profile", profile.Key})
+			}
+			for _, other := range otherKeys {
+				if other.key == key {
+					el = append(el, field.Invalid(path.Child(keyField), key, fmt.Sprintf("target hashedDirectory %s must be different to %s key", keyField, other.name)))
+				}
+				if hashedDirectory.Packaging != trustapi.HashedDirectoryPackagingTarball && hashedDirectoryEntryKey.MatchString(other.key) {
+					el = append(el, field.Invalid(path, other.key, fmt.Sprintf("target hashedDirectory keys must not overwrite the %s key", other.name)))
+				}
+			}
+		}
+	}
+
+	if buildInfo := bundle.Spec.Target.BuildInfo; buildInfo != nil && buildInfo.Mode == trustapi.BuildInfoModeInformative {
+		path := path.Child("target", "buildInfo", "timestampKey")
+
+		timestampKey := buildInfo.TimestampKey
+		if len(timestampKey) == 0 {
+			timestampKey = trustapi.DefaultBuildTimestampKey
+		}
+
+		if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == timestampKey {
+			el = append(el, field.Invalid(path, timestampKey, "target buildInfo timestampKey must be different to configMap key"))
+		}
+		if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.JKS != nil && formats.JKS.Key == timestampKey {
+			el = append(el, field.Invalid(path, timestampKey, "target buildInfo timestampKey must be different to JKS key"))
+		}
+		if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Metadata != nil && formats.Metadata.Key == timestampKey {
+			el = append(el, field.Invalid(path, timestampKey, "target buildInfo timestampKey must be different to metadata key"))
+		}
+		if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.SPIFFE != nil && formats.SPIFFE.Key == timestampKey {
+			el = append(el, field.Invalid(path, timestampKey, "target buildInfo timestampKey must be different to SPIFFE key"))
+		}
+		if formats := bundle.Spec.Target.AdditionalFormats; formats != nil && formats.Provenance != nil && formats.Provenance.Key == timestampKey {
+			el = append(el, field.Invalid(path, timestampKey, "target buildInfo timestampKey must be different to provenance key"))
+		}
+	}
+
+	switch bundle.Spec.Target.Mode {
+	case "", trustapi.TargetModeNamespaces:
+	case trustapi.TargetModeLocal:
+		path := path.Child("target")
+
+		if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil && (len(nsSel.MatchLabels) > 0 || len(nsSel.MatchExpressions) > 0) {
+			el = append(el, field.Forbidden(path.Child("namespaceSelector"), "target namespaceSelector cannot be used in Local mode"))
+		}
+		if len(bundle.Spec.Target.Namespaces) > 0 {
+			el = append(el, field.Forbidden(path.Child("namespaces"), "target namespaces cannot be used in Local mode"))
+		}
+		if bundle.Spec.Target.NamespaceExcludeSelector != nil {
+			el = append(el, field.Forbidden(path.Child("namespaceExcludeSelector"), "target namespaceExcludeSelector cannot be used in Local mode"))
+		}
+	default:
+		el = append(el, field.NotSupported(path.Child("target", "mode"), bundle.Spec.Target.Mode, []string{
+			string(trustapi.TargetModeNamespaces), string(trustapi.TargetModeLocal),
+		}))
+	}
+
+	switch bundle.Spec.Target.DeletionPolicy {
+	case "", trustapi.TargetDeletionPolicyDelete, trustapi.TargetDeletionPolicyOrphan:
+	default:
+		el = append(el, field.NotSupported(path.Child("target", "deletionPolicy"), bundle.Spec.Target.DeletionPolicy, []string{
+			string(trustapi.TargetDeletionPolicyDelete), string(trustapi.TargetDeletionPolicyOrphan),
+		}))
+	}
+
+	switch bundle.Spec.Target.ConflictPolicy {
+	case "", trustapi.TargetConflictPolicyFail, trustapi.TargetConflictPolicyAdopt, trustapi.TargetConflictPolicyOverwrite:
+	default:
+		el = append(el, field.NotSupported(path.Child("target", "conflictPolicy"), bundle.Spec.Target.ConflictPolicy, []string{
+			string(trustapi.TargetConflictPolicyFail), string(trustapi.TargetConflictPolicyAdopt), string(trustapi.TargetConflictPolicyOverwrite),
+		}))
+	}
+
 	if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil && len(nsSel.MatchLabels) > 0 {
 		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSel.MatchLabels}); err != nil {
 			el = append(el, field.Invalid(path.Child("target", "namespaceSelector", "matchLabels"), nsSel.MatchLabels, err.Error()))
 		}
 	}
+	if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil && len(nsSel.MatchExpressions) > 0 {
+		if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: nsSel.MatchExpressions}); err != nil {
+			el = append(el, field.Invalid(path.Child("target", "namespaceSelector", "matchExpressions"), nsSel.MatchExpressions, err.Error()))
+		}
+	}
+
+	if nsSel := bundle.Spec.Target.NamespaceExcludeSelector; nsSel != nil {
+		path := path.Child("target", "namespaceExcludeSelector")
+
+		if len(nsSel.MatchLabels) == 0 && len(nsSel.MatchExpressions) == 0 {
+			el = append(el, field.Invalid(path, nsSel, "target namespaceExcludeSelector must define matchLabels or matchExpressions"))
+		}
+		if len(nsSel.MatchLabels) > 0 {
+			if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: nsSel.MatchLabels}); err != nil {
+				el = append(el, field.Invalid(path.Child("matchLabels"), nsSel.MatchLabels, err.Error()))
+			}
+		}
+		if len(nsSel.MatchExpressions) > 0 {
+			if _, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchExpressions: nsSel.MatchExpressions}); err != nil {
+				el = append(el, field.Invalid(path.Child("matchExpressions"), nsSel.MatchExpressions, err.Error()))
+			}
+		}
+	}
+
+	if namespaces := bundle.Spec.Target.Namespaces; len(namespaces) > 0 {
+		path := path.Child("target", "namespaces")
+
+		if nsSel := bundle.Spec.Target.NamespaceSelector; nsSel != nil && (len(nsSel.MatchLabels) > 0 || len(nsSel.MatchExpressions) > 0) {
+			el = append(el, field.Forbidden(path, "target namespaces and namespaceSelector are mutually exclusive"))
+		}
+
+		seen := make(map[string]struct{}, len(namespaces))
+		for i, namespace := range namespaces {
+			path := path.Child("[" + strconv.Itoa(i) + "]")
+			for _, msg := range validation.IsDNS1123Label(namespace) {
+				el = append(el, field.Invalid(path, namespace, msg))
+			}
+			if _, ok := seen[namespace]; ok {
+				el = append(el, field.Duplicate(path, namespace))
+			}
+			seen[namespace] = struct{}{}
+		}
+	}
+
+	if sizeLimit := bundle.Spec.Target.SizeLimit; sizeLimit != nil {
+		path := path.Child("target", "sizeLimit")
+
+		if sizeLimit.MaxBytes < 0 {
+			el = append(el, field.Invalid(path.Child("maxBytes"), sizeLimit.MaxBytes, "target sizeLimit maxBytes must not be negative"))
+		}
+		if sizeLimit.MaxCertificates < 0 {
+			el = append(el, field.Invalid(path.Child("maxCertificates"), sizeLimit.MaxCertificates, "target sizeLimit maxCertificates must not be negative"))
+		}
+
+		switch sizeLimit.Policy {
+		case "", trustapi.TargetSizeLimitPolicyFail, trustapi.TargetSizeLimitPolicyWarn, trustapi.TargetSizeLimitPolicyTruncate, trustapi.TargetSizeLimitPolicyPartition:
+		default:
+			el = append(el, field.NotSupported(path.Child("policy"), sizeLimit.Policy, []string{
+				string(trustapi.TargetSizeLimitPolicyFail), string(trustapi.TargetSizeLimitPolicyWarn), string(trustapi.TargetSizeLimitPolicyTruncate),
+				string(trustapi.TargetSizeLimitPolicyPartition),
+			}))
+		}
+
+		if len(sizeLimit.PartitionIndexKey) > 0 {
+			for _, msg := range validation.IsConfigMapKey(sizeLimit.PartitionIndexKey) {
+				el = append(el, field.Invalid(path.Child("partitionIndexKey"), sizeLimit.PartitionIndexKey, msg))
+			}
+			if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Key == sizeLimit.PartitionIndexKey {
+				el = append(el, field.Invalid(path.Child("partitionIndexKey"), sizeLimit.PartitionIndexKey, "target sizeLimit partitionIndexKey must be different to configMap key"))
+			}
+		}
+	}
+
+	if immutable := bundle.Spec.Target.Immutable; immutable != nil {
+		path := path.Child("target", "immutable")
+
+		if immutable.HistoryLimit < 0 {
+			el = append(el, field.Invalid(path.Child("historyLimit"), immutable.HistoryLimit, "target immutable historyLimit must not be negative"))
+		}
+
+		// Partitions are written to ConfigMaps of their own, which an
+		// immutable version of the target would only refer to.
+		if sizeLimit := bundle.Spec.Target.SizeLimit; sizeLimit != nil && sizeLimit.Policy == trustapi.TargetSizeLimitPolicyPartition {
+			el = append(el, field.Forbidden(path, "target immutable cannot be used with the Partition sizeLimit policy"))
+		}
+	}
+
+	if metadata := bundle.Spec.Target.Metadata; metadata != nil {
+		path := path.Child("target", "metadata")
+
+		el = append(el, metav1validation.ValidateLabels(metadata.Labels, path.Child("labels"))...)
+		el = append(el, apivalidation.ValidateAnnotations(metadata.Annotations, path.Child("annotations"))...)
+
+		// The trust.cert-manager.io domain is reserved for the labels and
+		// annotations managed by trust-manager itself.
+		reserved := func(path *field.Path, kind string, values map[string]string) {
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				if strings.HasPrefix(key, trust.GroupName+"/") {
+					el = append(el, field.Invalid(path.Key(key), key, fmt.Sprintf("target metadata %s in the %s domain are reserved", kind, trust.GroupName)))
+				}
+			}
+		}
+		reserved(path.Child("labels"), "labels", metadata.Labels)
+		reserved(path.Child("annotations"), "annotations", metadata.Annotations)
+
+		for i, key := range metadata.PropagateLabels {
+			path := path.Child("propagateLabels").Index(i)
+			for _, msg := range validation.IsQualifiedName(key) {
+				el = append(el, field.Invalid(path, key, msg))
+			}
+			if strings.HasPrefix(key, trust.GroupName+"/") {
+				el = append(el, field.Invalid(path, key, fmt.Sprintf("target metadata propagateLabels in the %s domain are reserved", trust.GroupName)))
+			}
+		}
+	}
+
+	if bundle.Spec.Target.Istio != nil {
+		if configMap := bundle.Spec.Target.ConfigMap; configMap != nil && configMap.Name == trustapi.IstioRootCertConfigMapName {
+			el = append(el, field.Invalid(path.Child("target", "configMap", "name"), configMap.Name, "target configMap name must be different to the Istio root certificate ConfigMap name"))
+		}
+	}
+
+	if openShift := bundle.Spec.Target.OpenShiftTrustedCA; openShift != nil {
+		path := path.Child("target", "openShiftTrustedCA")
+
+		if len(openShift.Name) > 0 {
+			for _, msg := range validation.IsDNS1123Subdomain(openShift.Name) {
+				el = append(el, field.Invalid(path.Child("name"), openShift.Name, msg))
+			}
+		}
+
+		// The OpenShift trusted CA bundle includes the CAs of the Proxy's
+		// trustedCA ConfigMap, so a Bundle both reading and writing it would
+		// keep CAs which were removed from its other sources.
+		for i, source := range bundle.Spec.Sources {
+			if source.UseOpenShiftTrustedCA != nil && *source.UseOpenShiftTrustedCA {
+				el = append(el, field.Forbidden(field.NewPath("spec", "sources", "["+strconv.Itoa(i)+"]", "useOpenShiftTrustedCA"), "useOpenShiftTrustedCA source cannot be used with the openShiftTrustedCA target"))
+			}
+		}
+	}
+
+	if oci := bundle.Spec.Target.OCI; oci != nil {
+		path := path.Child("target", "oci")
+
+		host, name, ok := strings.Cut(oci.Repository, "/")
+		if !ok || len(host) == 0 || !ociRepositoryName.MatchString(name) {
+			el = append(el, field.Invalid(path.Child("repository"), oci.Repository, "target oci repository must be a registry host followed by a repository name, without a tag or digest"))
+		}
+
+		if len(oci.Tag) > 0 && !ociTag.MatchString(oci.Tag) {
+			el = append(el, field.Invalid(path.Child("tag"), oci.Tag, "target oci tag must be a valid OCI tag"))
+		}
+
+		if len(oci.CredentialsSecret) > 0 {
+			for _, msg := range validation.IsDNS1123Subdomain(oci.CredentialsSecret) {
+				el = append(el, field.Invalid(path.Child("credentialsSecret"), oci.CredentialsSecret, msg))
+			}
+		}
+	}
+
+	if objectStorage := bundle.Spec.Target.ObjectStorage; objectStorage != nil {
+		path := path.Child("target", "objectStorage")
+
+		switch objectStorage.Provider {
+		case trustapi.ObjectStorageProviderS3, trustapi.ObjectStorageProviderGCS, trustapi.ObjectStorageProviderAzureBlob:
+		default:
+			el = append(el, field.NotSupported(path.Child("provider"), objectStorage.Provider, []string{
+				string(trustapi.ObjectStorageProviderS3), string(trustapi.ObjectStorageProviderGCS), string(trustapi.ObjectStorageProviderAzureBlob),
+			}))
+		}
+
+		if len(objectStorage.Bucket) == 0 {
+			el = append(el, field.Invalid(path.Child("bucket"), objectStorage.Bucket, "target objectStorage bucket must be defined"))
+		}
+		if len(objectStorage.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), objectStorage.Key, "target objectStorage key must be defined"))
+		}
+		if objectStorage.Provider == trustapi.ObjectStorageProviderAzureBlob && len(objectStorage.Account) == 0 && len(objectStorage.Endpoint) == 0 {
+			el = append(el, field.Invalid(path.Child("account"), objectStorage.Account, "target objectStorage account must be defined for AzureBlob"))
+		}
+		if len(objectStorage.Endpoint) > 0 {
+			if endpoint, err := url.Parse(objectStorage.Endpoint); err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || len(endpoint.Host) == 0 {
+				el = append(el, field.Invalid(path.Child("endpoint"), objectStorage.Endpoint, "target objectStorage endpoint must be an absolute http or https URL"))
+			}
+		}
+	}
+
+	if filters := bundle.Spec.Filters; filters != nil {
+		el = append(el, validateFilters(path.Child("filters"), filters)...)
+	}
+
+	switch bundle.Spec.PriorityClass {
+	case "", trustapi.BundlePriorityClassCritical, trustapi.BundlePriorityClassNormal, trustapi.BundlePriorityClassLow:
+	default:
+		el = append(el, field.NotSupported(path.Child("priorityClass"), bundle.Spec.PriorityClass, []string{
+			string(trustapi.BundlePriorityClassCritical), string(trustapi.BundlePriorityClassNormal), string(trustapi.BundlePriorityClassLow),
+		}))
+	}
+
+	switch bundle.Spec.ParsingPolicy {
+	case "", trustapi.PEMParsingPolicyStrict, trustapi.PEMParsingPolicyLenient:
+	default:
+		el = append(el, field.NotSupported(path.Child("parsingPolicy"), bundle.Spec.ParsingPolicy, []string{
+			string(trustapi.PEMParsingPolicyStrict), string(trustapi.PEMParsingPolicyLenient),
+		}))
+	}
+
+	for i, window := range bundle.Spec.MaintenanceWindows {
+		path := path.Child("maintenanceWindows", "["+strconv.Itoa(i)+"]")
+
+		if _, err := util.ParseSchedule(window.Schedule); err != nil {
+			el = append(el, field.Invalid(path.Child("schedule"), window.Schedule, err.Error()))
+		}
+		if window.Duration.Duration <= 0 {
+			el = append(el, field.Invalid(path.Child("duration"), window.Duration.Duration.String(), "maintenance window duration must be positive"))
+		}
+	}
+
+	if placement := bundle.Spec.Placement; placement != nil {
+		path := path.Child("placement")
+
+		if len(placement.Name) == 0 {
+			el = append(el, field.Invalid(path.Child("name"), placement.Name, "placement name must be defined"))
+		}
+		if len(placement.Namespace) == 0 {
+			el = append(el, field.Invalid(path.Child("namespace"), placement.Namespace, "placement namespace must be defined"))
+		}
+	}
 
 	path = field.NewPath("status")
 
@@ -194,6 +1289,225 @@ func (v *validator) validateBundle(ctx context.Context, bundle *trustapi.Bundle)
 	return el, nil
 }
 
+// pemDirectoryKeyNaming returns an example certificate name of the longest
+// length, and a pattern matching the certificate names, of the keys of a PEM
+// directory using the given key naming. Returns false if the key naming isn't
+// supported, along with the example and pattern of the default key naming.
+func pemDirectoryKeyNaming(keyNaming trustapi.PEMDirectoryKeyNaming) (string, string, bool) {
+	switch keyNaming {
+	case "", trustapi.PEMDirectoryKeyNamingPosition:
+		return "0000", "[0-9]+", true
+	case trustapi.PEMDirectoryKeyNamingFingerprint:
+		return strings.Repeat("0", 64), "[0-9a-f]{64}", true
+	case trustapi.PEMDirectoryKeyNamingSubjectHash:
+		return "00000000.0", "[0-9a-f]{8}\\.[0-9]+", true
+	default:
+		return "0000", "[0-9]+", false
+	}
+}
+
+// pemDirectoryEntryKey returns a pattern matching the keys of the certificate
+// entries of a PEM directory with the given key prefix and name pattern.
+func pemDirectoryEntryKey(prefix, namePattern string) *regexp.Regexp {
+	return regexp.MustCompile("^" + regexp.QuoteMeta(prefix) + namePattern + "\\.pem$")
+}
+
+// validateFilters validates the filters of a Bundle or of one of its sources.
+func validateFilters(path *field.Path, filters *trustapi.BundleFilters) field.ErrorList {
+	var el field.ErrorList
+
+	if filters.ExcludeExpiringWithin != nil && filters.ExcludeExpiringWithin.Duration <= 0 {
+		el = append(el, field.Invalid(path.Child("excludeExpiringWithin"), filters.ExcludeExpiringWithin.Duration.String(), "excludeExpiringWithin filter must be positive"))
+	}
+
+	for i, rule := range filters.Include {
+		el = append(el, validateCertificateMatch(path.Child("include", "["+strconv.Itoa(i)+"]"), rule)...)
+	}
+	for i, rule := range filters.Exclude {
+		el = append(el, validateCertificateMatch(path.Child("exclude", "["+strconv.Itoa(i)+"]"), rule)...)
+	}
+	for i, fingerprint := range filters.AllowFingerprints {
+		if _, err := util.ParseFingerprint(fingerprint); err != nil {
+			el = append(el, field.Invalid(path.Child("allowFingerprints", "["+strconv.Itoa(i)+"]"), fingerprint, err.Error()))
+		}
+	}
+	for i, fingerprint := range filters.DenyFingerprints {
+		if _, err := util.ParseFingerprint(fingerprint); err != nil {
+			el = append(el, field.Invalid(path.Child("denyFingerprints", "["+strconv.Itoa(i)+"]"), fingerprint, err.Error()))
+		}
+	}
+
+	for i, usage := range filters.KeyUsages {
+		if _, ok := util.KeyUsages[usage]; !ok {
+			el = append(el, field.NotSupported(path.Child("keyUsages", "["+strconv.Itoa(i)+"]"), usage, supportedKeyUsages))
+		}
+	}
+	for i, usage := range filters.ExtendedKeyUsages {
+		if _, ok := util.ExtendedKeyUsages[usage]; !ok {
+			el = append(el, field.NotSupported(path.Child("extendedKeyUsages", "["+strconv.Itoa(i)+"]"), usage, supportedExtendedKeyUsages))
+		}
+	}
+
+	switch filters.NonCACertificates {
+	case "", trustapi.NonCACertificatePolicyWarn, trustapi.NonCACertificatePolicyEnforce:
+	default:
+		el = append(el, field.NotSupported(path.Child("nonCACertificates"), filters.NonCACertificates, []string{
+			string(trustapi.NonCACertificatePolicyWarn), string(trustapi.NonCACertificatePolicyEnforce),
+		}))
+	}
+
+	switch filters.CrossSigned {
+	case "", trustapi.CrossSignedPolicyKeepAll, trustapi.CrossSignedPolicyKeepNewest, trustapi.CrossSignedPolicyKeepLongestValidity:
+	default:
+		el = append(el, field.NotSupported(path.Child("crossSigned"), filters.CrossSigned, []string{
+			string(trustapi.CrossSignedPolicyKeepAll), string(trustapi.CrossSignedPolicyKeepNewest), string(trustapi.CrossSignedPolicyKeepLongestValidity),
+		}))
+	}
+
+	if weakCrypto := filters.WeakCrypto; weakCrypto != nil {
+		path := path.Child("weakCrypto")
+
+		switch weakCrypto.Action {
+		case "", trustapi.WeakCryptoActionIgnore, trustapi.WeakCryptoActionWarn, trustapi.WeakCryptoActionEnforce:
+		default:
+			el = append(el, field.NotSupported(path.Child("action"), weakCrypto.Action, []string{
+				string(trustapi.WeakCryptoActionIgnore), string(trustapi.WeakCryptoActionWarn), string(trustapi.WeakCryptoActionEnforce),
+			}))
+		}
+
+		if weakCrypto.MinRSAKeySize < 0 {
+			el = append(el, field.Invalid(path.Child("minRSAKeySize"), weakCrypto.MinRSAKeySize, "weakCrypto minRSAKeySize must not be negative"))
+		}
+
+		for i, algorithm := range weakCrypto.WeakSignatureAlgorithms {
+			if _, ok := util.SignatureAlgorithms[algorithm]; !ok {
+				el = append(el, field.NotSupported(path.Child("weakSignatureAlgorithms", "["+strconv.Itoa(i)+"]"), algorithm, supportedSignatureAlgorithms))
+			}
+		}
+	}
+
+	return el
+}
+
+// validateTrustPurpose validates a purpose for which certificates are
+// trusted.
+func validateTrustPurpose(path *field.Path, purpose trustapi.TrustPurpose) field.ErrorList {
+	switch purpose {
+	case trustapi.TrustPurposeServerAuth, trustapi.TrustPurposeClientAuth, trustapi.TrustPurposeAny:
+		return nil
+	default:
+		return field.ErrorList{field.NotSupported(path, purpose, supportedTrustPurposes)}
+	}
+}
+
+// validateCertificateMatch validates a certificate include or exclude rule.
+func validateCertificateMatch(path *field.Path, rule trustapi.CertificateMatch) field.ErrorList {
+	var el field.ErrorList
+
+	if rule.Subject == nil && rule.Issuer == nil {
+		el = append(el, field.Forbidden(path, "must define at least one of subject or issuer"))
+	}
+	if rule.Subject != nil {
+		el = append(el, validateDistinguishedNameMatch(path.Child("subject"), *rule.Subject)...)
+	}
+	if rule.Issuer != nil {
+		el = append(el, validateDistinguishedNameMatch(path.Child("issuer"), *rule.Issuer)...)
+	}
+
+	return el
+}
+
+// validateDistinguishedNameMatch validates that exactly one of exact or regex
+// is defined, and that the regex compiles.
+func validateDistinguishedNameMatch(path *field.Path, match trustapi.DistinguishedNameMatch) field.ErrorList {
+	var el field.ErrorList
+
+	if (len(match.Exact) > 0) == (len(match.Regex) > 0) {
+		el = append(el, field.Forbidden(path, "must define exactly one of exact or regex"))
+	}
+
+	if len(match.Regex) > 0 {
+		if _, err := regexp.Compile(match.Regex); err != nil {
+			el = append(el, field.Invalid(path.Child("regex"), match.Regex, err.Error()))
+		}
+	}
+
+	return el
+}
+
+// validateSourceObjectKey validates that exactly one of the key or key pattern
+// of the given source object reference is defined.
+func validateSourceObjectKey(path *field.Path, ref *trustapi.SourceObjectKeySelector, source string) field.ErrorList {
+	var el field.ErrorList
+
+	switch {
+	case len(ref.Key) > 0 && len(ref.KeyPattern) > 0:
+		el = append(el, field.Forbidden(path.Child("keyPattern"), fmt.Sprintf("%s key and keyPattern are mutually exclusive", source)))
+	case len(ref.KeyPattern) > 0:
+		if err := util.ValidateKeyPattern(ref.KeyPattern); err != nil {
+			el = append(el, field.Invalid(path.Child("keyPattern"), ref.KeyPattern, err.Error()))
+		}
+	case len(ref.Key) == 0:
+		el = append(el, field.Invalid(path.Child("key"), ref.Key, fmt.Sprintf("%s key must be defined", source)))
+	}
+
+	if len(ref.ExcludeKeys) > 0 && len(ref.KeyPattern) == 0 {
+		el = append(el, field.Forbidden(path.Child("excludeKeys"), fmt.Sprintf("%s excludeKeys may only be set with keyPattern", source)))
+	}
+	for i, key := range ref.ExcludeKeys {
+		for _, msg := range validation.IsConfigMapKey(key) {
+			el = append(el, field.Invalid(path.Child("excludeKeys", "["+strconv.Itoa(i)+"]"), key, msg))
+		}
+	}
+
+	return el
+}
+
+// validatePasswordSource validates the given target PasswordSource.
+func validatePasswordSource(path *field.Path, source *trustapi.PasswordSource) field.ErrorList {
+	var el field.ErrorList
+
+	unionCount := 0
+
+	if secret := source.Secret; secret != nil {
+		path := path.Child("secret")
+		unionCount++
+
+		if len(secret.Name) == 0 {
+			el = append(el, field.Invalid(path.Child("name"), secret.Name, "password secret name must be defined"))
+		}
+		if len(secret.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), secret.Key, "password secret key must be defined"))
+		}
+		if len(secret.KeyPattern) > 0 {
+			el = append(el, field.Forbidden(path.Child("keyPattern"), "password secret does not support keyPattern"))
+		}
+		if len(secret.ExcludeKeys) > 0 {
+			el = append(el, field.Forbidden(path.Child("excludeKeys"), "password secret does not support excludeKeys"))
+		}
+	}
+
+	if provider := source.Provider; provider != nil {
+		path := path.Child("provider")
+		unionCount++
+
+		if len(provider.Name) == 0 {
+			el = append(el, field.Invalid(path.Child("name"), provider.Name, "password provider name must be defined"))
+		}
+		if len(provider.Key) == 0 {
+			el = append(el, field.Invalid(path.Child("key"), provider.Key, "password provider key must be defined"))
+		}
+	}
+
+	if unionCount != 1 {
+		el = append(el, field.Forbidden(
+			path, fmt.Sprintf("must define exactly one password source type but found %d defined types", unionCount),
+		))
+	}
+
+	return el
+}
+
 // InjectDecoder is used by the controller-runtime manager to inject an object
 // decoder to convert into know trust.cert-manager.io types.
 func (v *validator) InjectDecoder(d *admission.Decoder) error {
@@ -216,3 +1530,14 @@ func (v *validator) check(_ *http.Request) error {
 
 	return errors.New("not ready")
 }
+
+// supportedValues returns the keys of the given map of supported values in
+// alphabetical order.
+func supportedValues[T ~string, V any](values map[T]V) []string {
+	supported := make([]string, 0, len(values))
+	for value := range values {
+		supported = append(supported, string(value))
+	}
+	sort.Strings(supported)
+	return supported
+}
@@ -19,8 +19,11 @@ package webhook
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -31,6 +34,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
+	"github.com/cert-manager/trust-manager/test/dummy"
 )
 
 func Test_Handle(t *testing.T) {
@@ -299,6 +304,73 @@ func Test_Handle(t *testing.T) {
 				},
 			},
 		},
+		"a Namespace with an unknown trust.cert-manager.io annotation should return an Allowed response with a warning": {
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID: types.UID("abc"),
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   "",
+						Version: "v1",
+						Kind:    "Namespace",
+					},
+					Operation: admissionv1.Update,
+					Object: runtime.RawExtension{
+						Raw: []byte(`
+{
+	"apiVersion": "v1",
+	"kind": "Namespace",
+	"metadata": {
+		"name": "testing",
+		"annotations": {
+			"trust.cert-manager.io/target-keys": "ca.crt"
+		}
+	}
+}
+`),
+					},
+				},
+			},
+			expResp: admission.Response{
+				AdmissionResponse: admissionv1.AdmissionResponse{
+					Allowed:  true,
+					Result:   &metav1.Status{Reason: "Namespace validated", Code: 200},
+					Warnings: []string{`unknown annotation "trust.cert-manager.io/target-keys" is ignored by trust-manager; supported Namespace annotations are "trust.cert-manager.io/skip-targets" and "trust.cert-manager.io/target-key"`},
+				},
+			},
+		},
+		"a Namespace with an invalid annotation value should return a Denied response": {
+			req: admission.Request{
+				AdmissionRequest: admissionv1.AdmissionRequest{
+					UID: types.UID("abc"),
+					RequestKind: &metav1.GroupVersionKind{
+						Group:   "",
+						Version: "v1",
+						Kind:    "Namespace",
+					},
+					Operation: admissionv1.Create,
+					Object: runtime.RawExtension{
+						Raw: []byte(`
+{
+	"apiVersion": "v1",
+	"kind": "Namespace",
+	"metadata": {
+		"name": "testing",
+		"annotations": {
+			"trust.cert-manager.io/skip-targets": "yes"
+		}
+	}
+}
+`),
+					},
+				},
+			},
+			expResp: admission.Response{
+				AdmissionResponse: admissionv1.AdmissionResponse{
+					Allowed: false,
+					Result:  &metav1.Status{Reason: `metadata.annotations[trust.cert-manager.io/skip-targets]: Unsupported value: "yes": supported values: "true", "false"`, Code: 403},
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -319,7 +391,7 @@ func Test_Handle(t *testing.T) {
 
 func Test_validateBundle(t *testing.T) {
 	var (
-		nilKeySelector *trustapi.KeySelector
+		nilKeySelector *trustapi.TargetKeySelector
 	)
 
 	tests := map[string]struct {
@@ -331,118 +403,1467 @@ func Test_validateBundle(t *testing.T) {
 				Spec: trustapi.BundleSpec{},
 			},
 			expEl: field.ErrorList{
-				field.Forbidden(field.NewPath("spec", "sources"), "must define at least one source"),
-				field.Invalid(field.NewPath("spec", "target", "configMap"), nilKeySelector, "target configMap must be defined"),
+				field.Forbidden(field.NewPath("spec", "sources"), "must define at least one source"),
+				field.Invalid(field.NewPath("spec", "target", "configMap"), nilKeySelector, "target configMap must be defined"),
+			},
+		},
+		"sources with multiple types defined in items": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{
+							ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+							InLine:    pointer.String("test"),
+						},
+						{InLine: pointer.String("test")},
+						{
+							ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+							Secret:    &trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+						},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 2 defined types"),
+				field.Forbidden(field.NewPath("spec", "sources", "[2]"), "must define exactly one source type for each item but found 2 defined types"),
+			},
+		},
+		"empty source with no defined types": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 0 defined types"),
+			},
+		},
+		"useDefaultCAs false, with no other defined sources": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{
+							UseDefaultCAs: pointer.Bool(false),
+						},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 0 defined types"),
+			},
+		},
+		"useClusterAPIServerCA combined with another source type": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{UseClusterAPIServerCA: pointer.Bool(true)},
+						{UseClusterAPIServerCA: pointer.Bool(true), InLine: pointer.String("test")},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[1]"), "must define exactly one source type for each item but found 2 defined types"),
+			},
+		},
+		"useNodeOSCAs combined with another source type": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{UseNodeOSCAs: pointer.Bool(true)},
+						{UseNodeOSCAs: pointer.Bool(true), UseClusterAPIServerCA: pointer.Bool(true)},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[1]"), "must define exactly one source type for each item but found 2 defined types"),
+			},
+		},
+		"inLineDER with invalid certificates": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{InLineDER: [][]byte{dummy.JoinCertsDER(dummy.TestCertificate1), []byte("test")}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "inLineDER", "[1]"), "4 bytes", "source inLineDER entry must be a DER-encoded certificate"),
+			},
+		},
+		"objectStorage with invalid fields": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: "FTP"}},
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Bucket: "certs", Key: "ca.pem"}},
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", Endpoint: "minio:9000"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "sources", "[0]", "objectStorage", "provider"), trustapi.ObjectStorageProvider("FTP"), []string{"S3", "GCS", "AzureBlob"}),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "objectStorage", "bucket"), "", "source objectStorage bucket must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "objectStorage", "key"), "", "source objectStorage key must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "objectStorage", "account"), "", "source objectStorage account must be defined for AzureBlob"),
+				field.Invalid(field.NewPath("spec", "sources", "[2]", "objectStorage", "endpoint"), "minio:9000", "source objectStorage endpoint must be an absolute http or https URL"),
+			},
+		},
+		"valid objectStorage sources": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", Region: "eu-west-1", CredentialsSecret: "s3-creds"}},
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderGCS, Bucket: "certs", Key: "ca.pem"}},
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Account: "corp", Bucket: "certs", Key: "ca.pem"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: nil,
+		},
+		"source with invalid labels": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{InLine: pointer.String("test"), Labels: map[string]string{"purpose": "mtls-internal"}},
+						{InLine: pointer.String("test"), Labels: map[string]string{"purpose": "not valid"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "labels"), "not valid", "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')"),
+			},
+		},
+		"target metadata key clashing with other keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Metadata: &trustapi.Metadata{KeySelector: trustapi.KeySelector{Key: "test"}},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "metadata", "key"), "test", "target metadata key must be different to configMap key"),
+			},
+		},
+		"target metadata key undefined": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Metadata: &trustapi.Metadata{},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "metadata", "key"), "", "target metadata key must be defined"),
+			},
+		},
+		"target SPIFFE key clashing with other keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Metadata: &trustapi.Metadata{KeySelector: trustapi.KeySelector{Key: "metadata.json"}},
+							SPIFFE:   &trustapi.KeySelector{Key: "metadata.json"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "spiffe", "key"), "metadata.json", "target SPIFFE key must be different to metadata key"),
+			},
+		},
+		"target SPIFFE key undefined": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							SPIFFE: &trustapi.KeySelector{},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "spiffe", "key"), "", "target SPIFFE key must be defined"),
+			},
+		},
+		"target provenance key clashing with other keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							SPIFFE:     &trustapi.KeySelector{Key: "trust.json"},
+							Provenance: &trustapi.KeySelector{Key: "trust.json"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "provenance", "key"), "trust.json", "target provenance key must be different to SPIFFE key"),
+			},
+		},
+		"target provenance key undefined": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Provenance: &trustapi.KeySelector{},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "provenance", "key"), "", "target provenance key must be defined"),
+			},
+		},
+		"target profiles with invalid fields": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{
+						InLine:   pointer.String("test"),
+						Purposes: []trustapi.TrustPurpose{trustapi.TrustPurposeServerAuth, "EmailProtection"},
+					}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Profiles: []trustapi.TrustProfile{
+								{Key: "server.pem", Purpose: trustapi.TrustPurposeServerAuth},
+								{Key: "server.pem", Purpose: trustapi.TrustPurposeClientAuth},
+								{Key: "test", Purpose: "CodeSigning"},
+								{Purpose: trustapi.TrustPurposeAny},
+							},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "sources", "[0]", "purposes", "[1]"), trustapi.TrustPurpose("EmailProtection"), []string{"ServerAuth", "ClientAuth", "Any"}),
+				field.Duplicate(field.NewPath("spec", "target", "additionalFormats", "profiles", "[1]", "key"), "server.pem"),
+				field.NotSupported(field.NewPath("spec", "target", "additionalFormats", "profiles", "[2]", "purpose"), trustapi.TrustPurpose("CodeSigning"), []string{"ServerAuth", "ClientAuth", "Any"}),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "profiles", "[2]", "key"), "test", "target profile key must be different to configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "profiles", "[3]", "key"), "", "target profile key must be defined"),
+			},
+		},
+		"remoteCluster with invalid fields": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{RemoteCluster: &trustapi.SourceRemoteCluster{}},
+						{RemoteCluster: &trustapi.SourceRemoteCluster{
+							KubeconfigSecret: trustapi.SourceObjectKeySelector{Name: "spoke", Key: "kubeconfig"},
+							Namespace:        "cert-manager",
+							ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca"},
+							Secret:           &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "remoteCluster", "kubeconfigSecret", "name"), "", "source remoteCluster kubeconfigSecret name must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "remoteCluster", "kubeconfigSecret", "key"), "", "source remoteCluster kubeconfigSecret key must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "remoteCluster", "namespace"), "", "source remoteCluster namespace must be defined"),
+				field.Forbidden(field.NewPath("spec", "sources", "[0]", "remoteCluster"), "must define exactly one of configMap or secret but found 0"),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "remoteCluster", "configMap", "key"), "", "source remoteCluster configMap key must be defined"),
+				field.Forbidden(field.NewPath("spec", "sources", "[1]", "remoteCluster"), "must define exactly one of configMap or secret but found 2"),
+			},
+		},
+		"external sources with non-positive refreshInterval": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ObjectStorage: &trustapi.SourceObjectStorage{Provider: trustapi.ObjectStorageProviderS3, Bucket: "certs", Key: "ca.pem", RefreshInterval: &metav1.Duration{}}},
+						{RemoteCluster: &trustapi.SourceRemoteCluster{
+							KubeconfigSecret: trustapi.SourceObjectKeySelector{Name: "spoke", Key: "kubeconfig"},
+							Namespace:        "cert-manager",
+							ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+							RefreshInterval:  &metav1.Duration{Duration: -time.Minute},
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "objectStorage", "refreshInterval"), "0s", "source objectStorage refreshInterval must be positive"),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "remoteCluster", "refreshInterval"), "-1m0s", "source remoteCluster refreshInterval must be positive"),
+			},
+		},
+		"valid remoteCluster source": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{RemoteCluster: &trustapi.SourceRemoteCluster{
+							KubeconfigSecret: trustapi.SourceObjectKeySelector{Name: "spoke", Key: "kubeconfig"},
+							Namespace:        "cert-manager",
+							ConfigMap:        &trustapi.SourceObjectKeySelector{Name: "ca", Key: "ca.crt"},
+							RefreshInterval:  &metav1.Duration{Duration: 5 * time.Minute},
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: nil,
+		},
+		"defaultCAs with invalid fallback packages": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{DefaultCAs: &trustapi.DefaultCAsSource{Package: "corp", Fallback: []string{"mozilla", "", "corp", "mozilla"}}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "defaultCAs", "fallback", "[1]"), "", "source defaultCAs fallback package name must be defined"),
+				field.Duplicate(field.NewPath("spec", "sources", "[0]", "defaultCAs", "fallback", "[2]"), "corp"),
+				field.Duplicate(field.NewPath("spec", "sources", "[0]", "defaultCAs", "fallback", "[3]"), "mozilla"),
+			},
+		},
+		"defaultCAs with invalid exclusions": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{DefaultCAs: &trustapi.DefaultCAsSource{Exclude: &trustapi.DefaultCAsExclusions{
+							CommonNames:  []string{"DST Root CA X3", ""},
+							Fingerprints: []string{"96:BC:EC:06:26:49:76:F3:74:60:77:9A:CF:28:C5:A7:CF:E8:A3:C0:AA:E1:1A:8F:FC:EE:05:C0:BD:DF:08:C6", "cabd2a79a1076a31f21d253635cb039d4329a5e8"},
+						}}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "defaultCAs", "exclude", "commonNames", "[1]"), "", "source defaultCAs excluded common name must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "defaultCAs", "exclude", "fingerprints", "[1]"), "cabd2a79a1076a31f21d253635cb039d4329a5e8", "fingerprint must be a SHA-256 digest of 32 bytes, got 20 bytes"),
+			},
+		},
+		"useDefaultCAs requested twice": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{
+							UseDefaultCAs: pointer.Bool(true),
+						},
+						{
+							UseDefaultCAs: pointer.Bool(true),
+						},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources"), "must request default CAs either once or not at all but got 2 requests"),
+			},
+		},
+		"sources no names and keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "", Key: ""}},
+						{InLine: pointer.String("test")},
+						{Secret: &trustapi.SourceObjectKeySelector{Name: "", Key: ""}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "configMap", "name"), "", "source configMap name must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "configMap", "key"), "", "source configMap key must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[2]", "secret", "name"), "", "source secret name must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[2]", "secret", "key"), "", "source secret key must be defined"),
+			},
+		},
+		"sources with invalid key patterns": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", Key: "ca.crt", KeyPattern: "*.crt"}},
+						{Secret: &trustapi.SourceObjectKeySelector{Name: "test", KeyPattern: "[.crt"}},
+						{Secret: &trustapi.SourceObjectKeySelector{Name: "test", KeyPattern: "*.crt"}},
+						{TruststoreSecret: &trustapi.SourceTruststoreSelector{
+							SourceObjectKeySelector: trustapi.SourceObjectKeySelector{Name: "test", Key: "truststore.jks", KeyPattern: "*.jks"},
+							Format:                  trustapi.TruststoreFormatJKS,
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[0]", "configMap", "keyPattern"), "source configMap key and keyPattern are mutually exclusive"),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "secret", "keyPattern"), "[.crt", "invalid glob pattern: syntax error in pattern"),
+				field.Forbidden(field.NewPath("spec", "sources", "[3]", "truststoreSecret", "keyPattern"), "source truststoreSecret does not support keyPattern"),
+			},
+		},
+		"sources with invalid excluded keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", Key: "ca.crt", ExcludeKeys: []string{"tls.key"}}},
+						{Secret: &trustapi.SourceObjectKeySelector{Name: "test", KeyPattern: "*", ExcludeKeys: []string{"tls.key", "not/valid"}}},
+						{TruststoreSecret: &trustapi.SourceTruststoreSelector{
+							SourceObjectKeySelector: trustapi.SourceObjectKeySelector{Name: "test", Key: "truststore.jks", ExcludeKeys: []string{"tls.key"}},
+							Format:                  trustapi.TruststoreFormatJKS,
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[0]", "configMap", "excludeKeys"), "source configMap excludeKeys may only be set with keyPattern"),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "secret", "excludeKeys", "[1]"), "not/valid", "a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')"),
+				field.Forbidden(field.NewPath("spec", "sources", "[2]", "truststoreSecret", "excludeKeys"), "source truststoreSecret does not support excludeKeys"),
+			},
+		},
+		"tlsSecret source with no name": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{TLSSecret: &trustapi.SourceObjectSelector{Name: ""}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "tlsSecret", "name"), "", "source tlsSecret name must be defined"),
+			},
+		},
+		"istioCACertsSecret source with no name": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{IstioCACertsSecret: &trustapi.SourceObjectSelector{Name: ""}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "istioCACertsSecret", "name"), "", "source istioCACertsSecret name must be defined"),
+			},
+		},
+		"useDefaultCAs and unnamed defaultCAs requested together": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{UseDefaultCAs: pointer.Bool(true)},
+						{DefaultCAs: &trustapi.DefaultCAsSource{}},
+						{DefaultCAs: &trustapi.DefaultCAsSource{Package: "corp"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources"), "must request default CAs either once or not at all but got 2 requests"),
+			},
+		},
+		"named defaultCAs package requested twice": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{DefaultCAs: &trustapi.DefaultCAsSource{Package: "corp"}},
+						{DefaultCAs: &trustapi.DefaultCAsSource{Package: "mozilla"}},
+						{DefaultCAs: &trustapi.DefaultCAsSource{Package: "corp"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Duplicate(field.NewPath("spec", "sources", "[2]", "defaultCAs", "package"), "corp"),
+			},
+		},
+		"truststoreSecret source with no name, key or format": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{TruststoreSecret: &trustapi.SourceTruststoreSelector{}},
+						{TruststoreSecret: &trustapi.SourceTruststoreSelector{
+							SourceObjectKeySelector: trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+							Format:                  trustapi.TruststoreFormatJKS,
+							PasswordKey:             "test",
+						}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "truststoreSecret", "name"), "", "source truststoreSecret name must be defined"),
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "truststoreSecret", "key"), "", "source truststoreSecret key must be defined"),
+				field.NotSupported(field.NewPath("spec", "sources", "[0]", "truststoreSecret", "format"), trustapi.TruststoreFormat(""), []string{"JKS", "PKCS12"}),
+				field.Invalid(field.NewPath("spec", "sources", "[1]", "truststoreSecret", "passwordKey"), "test", "source truststoreSecret passwordKey must be different to key"),
+			},
+		},
+		"target JKS with both password and passwordFrom": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+							KeySelector: trustapi.KeySelector{Key: "test.jks"},
+							Password:    pointer.String("test"),
+							PasswordFrom: &trustapi.PasswordSource{
+								Secret: &trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+							},
+						}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom"), "target JKS password and passwordFrom are mutually exclusive"),
+			},
+		},
+		"target JKS passwordFrom with no source": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+							KeySelector:  trustapi.KeySelector{Key: "test.jks"},
+							PasswordFrom: &trustapi.PasswordSource{},
+						}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom"), "must define exactly one password source type but found 0 defined types"),
+			},
+		},
+		"target JKS passwordFrom with multiple incomplete sources": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+							KeySelector: trustapi.KeySelector{Key: "test.jks"},
+							PasswordFrom: &trustapi.PasswordSource{
+								Secret:   &trustapi.SourceObjectKeySelector{},
+								Provider: &trustapi.PasswordProviderSelector{},
+							},
+						}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom", "secret", "name"), "", "password secret name must be defined"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom", "secret", "key"), "", "password secret key must be defined"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom", "provider", "name"), "", "password provider name must be defined"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom", "provider", "key"), "", "password provider key must be defined"),
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "jks", "passwordFrom"), "must define exactly one password source type but found 2 defined types"),
+			},
+		},
+		"target configMap comments with DER format and Partition sizeLimit policy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test", Comments: true, Format: trustapi.TargetFormatDER},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyPartition},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "format"), "target configMap DER format cannot be used with the Partition sizeLimit policy"),
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "comments"), "target configMap comments can only be used with the PEM format"),
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "comments"), "target configMap comments cannot be used with the Partition sizeLimit policy"),
+			},
+		},
+		"target configMap previousKeyRetention with DER format and Partition sizeLimit policy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test", Format: trustapi.TargetFormatDER, PreviousKeyRetention: &metav1.Duration{Duration: -time.Hour}},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyPartition},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "format"), "target configMap DER format cannot be used with the Partition sizeLimit policy"),
+				field.Invalid(field.NewPath("spec", "target", "configMap", "previousKeyRetention"), "-1h0m0s", "target configMap previousKeyRetention must be positive"),
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "previousKeyRetention"), "target configMap previousKeyRetention can only be used with the PEM format"),
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "previousKeyRetention"), "target configMap previousKeyRetention cannot be used with the Partition sizeLimit policy"),
+			},
+		},
+		"target configMap previousKeyRetention with gzip omitUncompressed": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test", PreviousKeyRetention: &metav1.Duration{Duration: time.Hour}},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Gzip: &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: "test.gz"}, OmitUncompressed: true},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "previousKeyRetention"), "target configMap previousKeyRetention cannot be used with gzip omitUncompressed"),
+			},
+		},
+		"target PKCS12 with both password and passwordFrom": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PKCS12: &trustapi.PKCS12{
+							KeySelector: trustapi.KeySelector{Key: "test.p12"},
+							Password:    pointer.String("test"),
+							PasswordFrom: &trustapi.PasswordSource{
+								Secret: &trustapi.SourceObjectKeySelector{Name: "test", Key: "test"},
+							},
+						}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "pkcs12", "passwordFrom"), "target PKCS12 password and passwordFrom are mutually exclusive"),
+			},
+		},
+		"target PKCS12 passwordFrom with no source": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PKCS12: &trustapi.PKCS12{
+							KeySelector:  trustapi.KeySelector{Key: "test.p12"},
+							PasswordFrom: &trustapi.PasswordSource{},
+						}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "pkcs12", "passwordFrom"), "must define exactly one password source type but found 0 defined types"),
+			},
+		},
+		"target buildInfo timestampKey defaults to the same key as configMap and JKS": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "build-timestamp"},
+						AdditionalFormats: &trustapi.AdditionalFormats{JKS: &trustapi.JKS{
+							KeySelector: trustapi.KeySelector{Key: "build-timestamp"},
+						}},
+						BuildInfo: &trustapi.BuildInfo{Mode: trustapi.BuildInfoModeInformative},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "key"), "build-timestamp", "target JKS key must be different to configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "buildInfo", "timestampKey"), "build-timestamp", "target buildInfo timestampKey must be different to configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "buildInfo", "timestampKey"), "build-timestamp", "target buildInfo timestampKey must be different to JKS key"),
+			},
+		},
+		"target buildInfo timestampKey is ignored in Reproducible mode": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						BuildInfo: &trustapi.BuildInfo{Mode: trustapi.BuildInfoModeReproducible, TimestampKey: "test"},
+					},
+				},
+			},
+			expEl: nil,
+		},
+		"non-positive excludeExpiringWithin filter": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{ExcludeExpiringWithin: &metav1.Duration{Duration: -time.Hour}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "filters", "excludeExpiringWithin"), "-1h0m0s", "excludeExpiringWithin filter must be positive"),
+			},
+		},
+		"invalid include and exclude filters": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{
+						Include: []trustapi.CertificateMatch{
+							{Subject: &trustapi.DistinguishedNameMatch{Regex: "Corp Root"}},
+							{},
+						},
+						Exclude: []trustapi.CertificateMatch{
+							{Issuer: &trustapi.DistinguishedNameMatch{Exact: "CN=Vendor", Regex: "Vendor"}},
+							{Subject: &trustapi.DistinguishedNameMatch{Regex: "CN=(Vendor"}},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "filters", "include", "[1]"), "must define at least one of subject or issuer"),
+				field.Forbidden(field.NewPath("spec", "filters", "exclude", "[0]", "issuer"), "must define exactly one of exact or regex"),
+				field.Invalid(field.NewPath("spec", "filters", "exclude", "[1]", "subject", "regex"), "CN=(Vendor", "error parsing regexp: missing closing ): `CN=(Vendor`"),
+			},
+		},
+		"invalid fingerprint filters": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{
+						AllowFingerprints: []string{"96:BC:EC:06:26:49:76:F3:74:60:77:9A:CF:28:C5:A7:CF:E8:A3:C0:AA:E1:1A:8F:FC:EE:05:C0:BD:DF:08:C6", "zz"},
+						DenyFingerprints:  []string{"cabd2a79a1076a31f21d253635cb039d4329a5e8"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "filters", "allowFingerprints", "[1]"), "zz", "fingerprint must be hex encoded: encoding/hex: invalid byte: U+007A 'z'"),
+				field.Invalid(field.NewPath("spec", "filters", "denyFingerprints", "[0]"), "cabd2a79a1076a31f21d253635cb039d4329a5e8", "fingerprint must be a SHA-256 digest of 32 bytes, got 20 bytes"),
+			},
+		},
+		"unsupported nonCACertificates filter": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{NonCACertificates: "Reject"},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "filters", "nonCACertificates"), trustapi.NonCACertificatePolicy("Reject"), []string{"Warn", "Enforce"}),
+			},
+		},
+		"valid source filters": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{
+						InLine:  pointer.String("test"),
+						Filters: &trustapi.BundleFilters{ExcludeExpired: true, NonCACertificates: trustapi.NonCACertificatePolicyEnforce},
+					}},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: nil,
+		},
+		"invalid source filters": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{
+						InLine: pointer.String("test"),
+						Filters: &trustapi.BundleFilters{
+							DenyFingerprints:       []string{"abc"},
+							DeduplicateByPublicKey: true,
+							CrossSigned:            trustapi.CrossSignedPolicyKeepNewest,
+						},
+					}},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "sources", "[0]", "filters", "denyFingerprints", "[0]"), "abc", "fingerprint must be hex encoded: encoding/hex: odd length hex string"),
+				field.Forbidden(field.NewPath("spec", "sources", "[0]", "filters", "deduplicateByPublicKey"), "deduplicateByPublicKey filter applies across sources and may only be set on the Bundle"),
+				field.Forbidden(field.NewPath("spec", "sources", "[0]", "filters", "crossSigned"), "crossSigned filter applies across sources and may only be set on the Bundle"),
+			},
+		},
+		"unsupported crossSigned filter": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{CrossSigned: "KeepOldest"},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "filters", "crossSigned"), trustapi.CrossSignedPolicy("KeepOldest"), []string{"KeepAll", "KeepNewest", "KeepLongestValidity"}),
+			},
+		},
+		"valid target pemDirectory with default keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "ca-certificates.crt"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PEMDirectory: &trustapi.PEMDirectory{}},
+					},
+				},
+			},
+			expEl: nil,
+		},
+		"target pemDirectory keys colliding with other target keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "anchor-1.pem"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Metadata:     &trustapi.Metadata{KeySelector: trustapi.KeySelector{Key: "anchors"}},
+							PEMDirectory: &trustapi.PEMDirectory{KeyPrefix: "anchor-", IndexKey: "anchors"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "keyPrefix"), "anchor-", "target pemDirectory keys must not overwrite the configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "indexKey"), "anchors", "target pemDirectory indexKey must be different to metadata key"),
+			},
+		},
+		"invalid target pemDirectory keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PEMDirectory: &trustapi.PEMDirectory{KeyPrefix: "ca/", IndexKey: "index/"}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "keyPrefix"), "ca/", "a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "indexKey"), "index/", "a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')"),
+			},
+		},
+		"target PKCS12 unsupported profile": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "test.p12"}, Profile: "Modern2019"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "additionalFormats", "pkcs12", "profile"), trustapi.PKCS12Profile("Modern2019"), []string{"LegacyRC2", "LegacyDES", "Modern2023"}),
+			},
+		},
+		"target JKS unsupported aliasNaming": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "test.jks"}, AliasNaming: "Issuer"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "additionalFormats", "jks", "aliasNaming"), trustapi.JKSAliasNaming("Issuer"), []string{"Fingerprint", "SubjectCN", "Sequential"}),
+			},
+		},
+		"target pemDirectory unsupported keyNaming": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PEMDirectory: &trustapi.PEMDirectory{KeyNaming: "Serial"}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "keyNaming"), trustapi.PEMDirectoryKeyNaming("Serial"), []string{"Position", "Fingerprint", "SubjectHash"}),
+			},
+		},
+		"target pemDirectory SubjectHash keys overwrite configMap key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "ca-5ed36f99.0.pem"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							PEMDirectory: &trustapi.PEMDirectory{KeyNaming: trustapi.PEMDirectoryKeyNamingSubjectHash},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pemDirectory", "keyPrefix"), "ca-", "target pemDirectory keys must not overwrite the configMap key"),
+			},
+		},
+		"target PKCS12 key same as configMap and JKS keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							JKS:    &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "test"}},
+							PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "test"}},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "jks", "key"), "test", "target JKS key must be different to configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pkcs12", "key"), "test", "target PKCS12 key must be different to configMap key"),
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pkcs12", "key"), "test", "target PKCS12 key must be different to JKS key"),
+			},
+		},
+		"target configMap DER format with Partition sizeLimit policy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test", Format: trustapi.TargetFormatDER},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyPartition},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "configMap", "format"), "target configMap DER format cannot be used with the Partition sizeLimit policy"),
+			},
+		},
+		"target configMap unknown format": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test", Format: "CER"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "configMap", "format"), trustapi.TargetFormat("CER"), []string{"PEM", "DER"}),
+			},
+		},
+		"target hashedDirectory tarballKey with Keys packaging": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{HashedDirectory: &trustapi.HashedDirectory{TarballKey: "certs.tar"}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "hashedDirectory", "tarballKey"), "target hashedDirectory tarballKey can only be set with Tarball packaging"),
+			},
+		},
+		"target hashedDirectory tarballKey same as configMap key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							HashedDirectory: &trustapi.HashedDirectory{Packaging: trustapi.HashedDirectoryPackagingTarball, TarballKey: "test"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "hashedDirectory", "tarballKey"), "test", "target hashedDirectory tarballKey must be different to configMap key"),
+			},
+		},
+		"target hashedDirectory keys overwrite configMap key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "5ed36f99.0"},
+						AdditionalFormats: &trustapi.AdditionalFormats{HashedDirectory: &trustapi.HashedDirectory{}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "hashedDirectory"), "5ed36f99.0", "target hashedDirectory keys must not overwrite the configMap key"),
+			},
+		},
+		"target PKCS7 key same as PKCS12 key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}},
+							PKCS7:  &trustapi.KeySelector{Key: "bundle.p12"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pkcs7", "key"), "bundle.p12", "target PKCS7 key must be different to PKCS12 key"),
+			},
+		},
+		"target PKCS7 key not defined": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{PKCS7: &trustapi.KeySelector{}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "pkcs7", "key"), "", "target PKCS7 key must be defined"),
+			},
+		},
+		"target SST key same as PKCS7 key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							PKCS7: &trustapi.KeySelector{Key: "bundle.p7b"},
+							SST:   &trustapi.KeySelector{Key: "bundle.p7b"},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "sst", "key"), "bundle.p7b", "target SST key must be different to PKCS7 key"),
+			},
+		},
+		"target SST key not defined": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:         &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{SST: &trustapi.KeySelector{}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "sst", "key"), "", "target SST key must be defined"),
+			},
+		},
+		"target gzip key same as configMap and PKCS12 keys": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							PKCS12: &trustapi.PKCS12{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}},
+							Gzip:   &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: "bundle.p12"}},
+						},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "additionalFormats", "gzip", "key"), "bundle.p12", "target gzip key must be different to PKCS12 key"),
 			},
 		},
-		"sources with multiple types defined in items": {
+		"target gzip omitUncompressed with the Truncate sizeLimit policy": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
-					Sources: []trustapi.BundleSource{
-						{
-							ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", KeySelector: trustapi.KeySelector{Key: "test"}},
-							InLine:    pointer.String("test"),
-						},
-						{InLine: pointer.String("test")},
-						{
-							ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test", KeySelector: trustapi.KeySelector{Key: "test"}},
-							Secret:    &trustapi.SourceObjectKeySelector{Name: "test", KeySelector: trustapi.KeySelector{Key: "test"}},
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						AdditionalFormats: &trustapi.AdditionalFormats{
+							Gzip: &trustapi.Gzip{KeySelector: trustapi.KeySelector{Key: "test.gz"}, OmitUncompressed: true},
 						},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyTruncate},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
-				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 2 defined types"),
-				field.Forbidden(field.NewPath("spec", "sources", "[2]"), "must define exactly one source type for each item but found 2 defined types"),
+				field.Forbidden(field.NewPath("spec", "target", "additionalFormats", "gzip", "omitUncompressed"), "target gzip omitUncompressed cannot be used with the Truncate sizeLimit policy"),
 			},
 		},
-		"empty source with no defined types": {
+		"target configMap named as the Istio root certificate ConfigMap": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
-					Sources: []trustapi.BundleSource{
-						{},
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Name: "istio-ca-root-cert", Key: "test"},
+						Istio:     &trustapi.TargetIstio{},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
-				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 0 defined types"),
+				field.Invalid(field.NewPath("spec", "target", "configMap", "name"), "istio-ca-root-cert", "target configMap name must be different to the Istio root certificate ConfigMap name"),
 			},
 		},
-		"useDefaultCAs false, with no other defined sources": {
+		"Local target mode with namespace selection": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
-					Sources: []trustapi.BundleSource{
-						{
-							UseDefaultCAs: pointer.Bool(false),
-						},
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:                &trustapi.TargetKeySelector{Key: "test"},
+						Mode:                     trustapi.TargetModeLocal,
+						NamespaceSelector:        &trustapi.NamespaceSelector{MatchLabels: map[string]string{"foo": "bar"}},
+						Namespaces:               []string{"team-a"},
+						NamespaceExcludeSelector: &trustapi.NamespaceSelector{MatchLabels: map[string]string{"foo": "baz"}},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
-				field.Forbidden(field.NewPath("spec", "sources", "[0]"), "must define exactly one source type for each item but found 0 defined types"),
+				field.Forbidden(field.NewPath("spec", "target", "namespaceSelector"), "target namespaceSelector cannot be used in Local mode"),
+				field.Forbidden(field.NewPath("spec", "target", "namespaces"), "target namespaces cannot be used in Local mode"),
+				field.Forbidden(field.NewPath("spec", "target", "namespaceExcludeSelector"), "target namespaceExcludeSelector cannot be used in Local mode"),
+				field.Forbidden(field.NewPath("spec", "target", "namespaces"), "target namespaces and namespaceSelector are mutually exclusive"),
 			},
 		},
-		"useDefaultCAs requested twice": {
+		"unsupported target mode": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
-					Sources: []trustapi.BundleSource{
-						{
-							UseDefaultCAs: pointer.Bool(true),
-						},
-						{
-							UseDefaultCAs: pointer.Bool(true),
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						Mode:      "Cluster",
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "mode"), trustapi.TargetMode("Cluster"), []string{"Namespaces", "Local"}),
+			},
+		},
+		"unsupported target deletionPolicy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:      &trustapi.TargetKeySelector{Key: "test"},
+						DeletionPolicy: "Retain",
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "deletionPolicy"), trustapi.TargetDeletionPolicy("Retain"), []string{"Delete", "Orphan"}),
+			},
+		},
+		"unsupported target conflictPolicy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:      &trustapi.TargetKeySelector{Key: "test"},
+						ConflictPolicy: "Ignore",
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "target", "conflictPolicy"), trustapi.TargetConflictPolicy("Ignore"), []string{"Fail", "Adopt", "Overwrite"}),
+			},
+		},
+		"invalid target immutable": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyPartition},
+						Immutable: &trustapi.TargetImmutable{HistoryLimit: -1},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "immutable", "historyLimit"), int32(-1), "target immutable historyLimit must not be negative"),
+				field.Forbidden(field.NewPath("spec", "target", "immutable"), "target immutable cannot be used with the Partition sizeLimit policy"),
+			},
+		},
+		"invalid target metadata": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						Metadata: &trustapi.TargetMetadata{
+							Labels:          map[string]string{"backup": "not valid", "trust.cert-manager.io/bundle": "test"},
+							Annotations:     map[string]string{"reloader.stakater.com/match": "true", "trust.cert-manager.io/hash": "test"},
+							PropagateLabels: []string{"team", "trust.cert-manager.io/bundle", "not valid"},
 						},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
-				field.Forbidden(field.NewPath("spec", "sources"), "must request default CAs either once or not at all but got 2 requests"),
+				field.Invalid(field.NewPath("spec", "target", "metadata", "labels"), "not valid", "a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')"),
+				field.Invalid(field.NewPath("spec", "target", "metadata", "labels").Key("trust.cert-manager.io/bundle"), "trust.cert-manager.io/bundle", "target metadata labels in the trust.cert-manager.io domain are reserved"),
+				field.Invalid(field.NewPath("spec", "target", "metadata", "annotations").Key("trust.cert-manager.io/hash"), "trust.cert-manager.io/hash", "target metadata annotations in the trust.cert-manager.io domain are reserved"),
+				field.Invalid(field.NewPath("spec", "target", "metadata", "propagateLabels").Index(1), "trust.cert-manager.io/bundle", "target metadata propagateLabels in the trust.cert-manager.io domain are reserved"),
+				field.Invalid(field.NewPath("spec", "target", "metadata", "propagateLabels").Index(2), "not valid", "name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')"),
 			},
 		},
-		"sources no names and keys": {
+		"invalid target openShiftTrustedCA name": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:          &trustapi.TargetKeySelector{Key: "test"},
+						OpenShiftTrustedCA: &trustapi.TargetOpenShiftTrustedCA{Name: "Invalid_Name"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "openShiftTrustedCA", "name"), "Invalid_Name", "a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')"),
+			},
+		},
+		"useOpenShiftTrustedCA source combined with openShiftTrustedCA target": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
 					Sources: []trustapi.BundleSource{
-						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "", KeySelector: trustapi.KeySelector{Key: ""}}},
 						{InLine: pointer.String("test")},
-						{Secret: &trustapi.SourceObjectKeySelector{Name: "", KeySelector: trustapi.KeySelector{Key: ""}}},
+						{UseOpenShiftTrustedCA: pointer.Bool(true)},
+					},
+					Target: trustapi.BundleTarget{
+						ConfigMap:          &trustapi.TargetKeySelector{Key: "test"},
+						OpenShiftTrustedCA: &trustapi.TargetOpenShiftTrustedCA{},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
-				field.Invalid(field.NewPath("spec", "sources", "[0]", "configMap", "name"), "", "source configMap name must be defined"),
-				field.Invalid(field.NewPath("spec", "sources", "[0]", "configMap", "key"), "", "source configMap key must be defined"),
-				field.Invalid(field.NewPath("spec", "sources", "[2]", "secret", "name"), "", "source secret name must be defined"),
-				field.Invalid(field.NewPath("spec", "sources", "[2]", "secret", "key"), "", "source secret key must be defined"),
+				field.Forbidden(field.NewPath("spec", "sources", "[1]", "useOpenShiftTrustedCA"), "useOpenShiftTrustedCA source cannot be used with the openShiftTrustedCA target"),
+			},
+		},
+		"invalid target sizeLimit": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						SizeLimit: &trustapi.TargetSizeLimit{MaxBytes: -1, MaxCertificates: -1, Policy: "Drop"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "sizeLimit", "maxBytes"), int32(-1), "target sizeLimit maxBytes must not be negative"),
+				field.Invalid(field.NewPath("spec", "target", "sizeLimit", "maxCertificates"), int32(-1), "target sizeLimit maxCertificates must not be negative"),
+				field.NotSupported(field.NewPath("spec", "target", "sizeLimit", "policy"), trustapi.TargetSizeLimitPolicy("Drop"), []string{"Fail", "Warn", "Truncate", "Partition"}),
+			},
+		},
+		"target sizeLimit partitionIndexKey same as configMap key": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						SizeLimit: &trustapi.TargetSizeLimit{Policy: trustapi.TargetSizeLimitPolicyPartition, PartitionIndexKey: "test"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "sizeLimit", "partitionIndexKey"), "test", "target sizeLimit partitionIndexKey must be different to configMap key"),
+			},
+		},
+		"invalid target oci": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						OCI:       &trustapi.TargetOCI{Repository: "registry.example.com/trust/Bundle:v1", Tag: "-v1"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "oci", "repository"), "registry.example.com/trust/Bundle:v1", "target oci repository must be a registry host followed by a repository name, without a tag or digest"),
+				field.Invalid(field.NewPath("spec", "target", "oci", "tag"), "-v1", "target oci tag must be a valid OCI tag"),
+			},
+		},
+		"invalid target objectStorage": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap:     &trustapi.TargetKeySelector{Key: "test"},
+						ObjectStorage: &trustapi.TargetObjectStorage{Provider: trustapi.ObjectStorageProviderAzureBlob, Endpoint: "blob.example.com"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "objectStorage", "bucket"), "", "target objectStorage bucket must be defined"),
+				field.Invalid(field.NewPath("spec", "target", "objectStorage", "key"), "", "target objectStorage key must be defined"),
+				field.Invalid(field.NewPath("spec", "target", "objectStorage", "endpoint"), "blob.example.com", "target objectStorage endpoint must be an absolute http or https URL"),
+			},
+		},
+		"valid target oci": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						OCI:       &trustapi.TargetOCI{Repository: "registry.example.com:5000/trust/ca-bundle", Tag: "v1.0", CredentialsSecret: "registry-credentials"},
+					},
+				},
+			},
+			expEl: nil,
+		},
+		"invalid weakCrypto filter": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{WeakCrypto: &trustapi.WeakCryptoFilter{
+						Action:                  "Reject",
+						MinRSAKeySize:           -1,
+						WeakSignatureAlgorithms: []string{"SHA1-RSA", "SHA1"},
+					}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "filters", "weakCrypto", "action"), trustapi.WeakCryptoAction("Reject"), []string{"Ignore", "Warn", "Enforce"}),
+				field.Invalid(field.NewPath("spec", "filters", "weakCrypto", "minRSAKeySize"), int32(-1), "weakCrypto minRSAKeySize must not be negative"),
+				field.NotSupported(field.NewPath("spec", "filters", "weakCrypto", "weakSignatureAlgorithms", "[1]"), "SHA1", []string{
+					"DSA-SHA1", "DSA-SHA256", "ECDSA-SHA1", "ECDSA-SHA256", "ECDSA-SHA384", "ECDSA-SHA512", "Ed25519", "MD5-RSA",
+					"SHA1-RSA", "SHA256-RSA", "SHA256-RSAPSS", "SHA384-RSA", "SHA384-RSAPSS", "SHA512-RSA", "SHA512-RSAPSS",
+				}),
+			},
+		},
+		"unsupported key usage filters": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Filters: &trustapi.BundleFilters{
+						KeyUsages:         []trustapi.KeyUsage{trustapi.KeyUsageCertSign, "Signing"},
+						ExtendedKeyUsages: []trustapi.ExtendedKeyUsage{"serverAuth"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "filters", "keyUsages", "[1]"), trustapi.KeyUsage("Signing"), []string{
+					"CRLSign", "CertSign", "ContentCommitment", "DataEncipherment", "DecipherOnly", "DigitalSignature", "EncipherOnly", "KeyAgreement", "KeyEncipherment",
+				}),
+				field.NotSupported(field.NewPath("spec", "filters", "extendedKeyUsages", "[0]"), trustapi.ExtendedKeyUsage("serverAuth"), []string{
+					"ClientAuth", "CodeSigning", "EmailProtection", "OCSPSigning", "ServerAuth", "TimeStamping",
+				}),
+			},
+		},
+		"unsupported priorityClass": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources:       []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:        trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					PriorityClass: "High",
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "priorityClass"), trustapi.BundlePriorityClass("High"), []string{"Critical", "Normal", "Low"}),
+			},
+		},
+		"unsupported parsingPolicy": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources:       []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:        trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					ParsingPolicy: "Permissive",
+				},
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(field.NewPath("spec", "parsingPolicy"), trustapi.PEMParsingPolicy("Permissive"), []string{"Strict", "Lenient"}),
 			},
 		},
+		"invalid maintenance windows": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					MaintenanceWindows: []trustapi.MaintenanceWindow{
+						{Schedule: "0 2 * * SAT", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+						{Schedule: "0 2 * *", Duration: metav1.Duration{}},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "maintenanceWindows", "[1]", "schedule"), "0 2 * *", "failed to parse schedule: expected exactly 5 fields, found 4: [0 2 * *]"),
+				field.Invalid(field.NewPath("spec", "maintenanceWindows", "[1]", "duration"), "0s", "maintenance window duration must be positive"),
+			},
+		},
+		"placement with undefined fields": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources:   []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:    trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Placement: &trustapi.PlacementReference{},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "placement", "name"), "", "placement name must be defined"),
+				field.Invalid(field.NewPath("spec", "placement", "namespace"), "", "placement namespace must be defined"),
+			},
+		},
+		"valid placement": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources:   []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:    trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+					Placement: &trustapi.PlacementReference{Name: "all-clusters", Namespace: "trust"},
+				},
+			},
+			expEl: nil,
+		},
 		"sources defines the same configMap target": {
 			bundle: &trustapi.Bundle{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
 				Spec: trustapi.BundleSpec{
 					Sources: []trustapi.BundleSource{
 						{InLine: pointer.String("test")},
-						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test-bundle", KeySelector: trustapi.KeySelector{Key: "test"}}},
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test-bundle", Key: "test"}},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test"}},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
 				},
 			},
 			expEl: field.ErrorList{
 				field.Forbidden(field.NewPath("spec", "sources", "[1]", "configMap", "test-bundle", "test"), "cannot define the same source as target"),
 			},
 		},
+		"sources defines the same named configMap target": {
+			bundle: &trustapi.Bundle{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test-bundle", Key: "test"}},
+						{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "ca-certificates", Key: "test"}},
+					},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Name: "ca-certificates", Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "sources", "[1]", "configMap", "ca-certificates", "test"), "cannot define the same source as target"),
+			},
+		},
+		"target configMap name invalid": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target:  trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Name: "CA_Certificates", Key: "test"}},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "configMap", "name"), "CA_Certificates", `a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+			},
+		},
 		"target configMap key not defined": {
 			bundle: &trustapi.Bundle{
 				Spec: trustapi.BundleSpec{
 					Sources: []trustapi.BundleSource{
 						{InLine: pointer.String("test")},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: ""}},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: ""}},
 				},
 			},
 			expEl: field.ErrorList{
@@ -456,7 +1877,7 @@ func Test_validateBundle(t *testing.T) {
 					Sources: []trustapi.BundleSource{
 						{InLine: pointer.String("test-1")},
 					},
-					Target: trustapi.BundleTarget{ConfigMap: &trustapi.KeySelector{Key: "test-1"}},
+					Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test-1"}},
 				},
 				Status: trustapi.BundleStatus{
 					Conditions: []trustapi.BundleCondition{
@@ -483,7 +1904,7 @@ func Test_validateBundle(t *testing.T) {
 						{InLine: pointer.String("test-1")},
 					},
 					Target: trustapi.BundleTarget{
-						ConfigMap: &trustapi.KeySelector{Key: "test-1"},
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test-1"},
 						NamespaceSelector: &trustapi.NamespaceSelector{
 							MatchLabels: map[string]string{"@@@@": ""},
 						},
@@ -502,6 +1923,45 @@ func Test_validateBundle(t *testing.T) {
 				field.Invalid(field.NewPath("spec", "target", "namespaceSelector", "matchLabels"), map[string]string{"@@@@": ""}, `key: Invalid value: "@@@@": name part must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]')`),
 			},
 		},
+		"target namespaces with namespaceSelector and invalid names": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						NamespaceSelector: &trustapi.NamespaceSelector{
+							MatchLabels: map[string]string{"team": "a"},
+						},
+						Namespaces: []string{"team-a", "team-a", "Team_B"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Forbidden(field.NewPath("spec", "target", "namespaces"), "target namespaces and namespaceSelector are mutually exclusive"),
+				field.Duplicate(field.NewPath("spec", "target", "namespaces", "[1]"), "team-a"),
+				field.Invalid(field.NewPath("spec", "target", "namespaces", "[2]"), "Team_B", `a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')`),
+			},
+		},
+		"target namespaceExcludeSelector with invalid fields": {
+			bundle: &trustapi.Bundle{
+				Spec: trustapi.BundleSpec{
+					Sources: []trustapi.BundleSource{{InLine: pointer.String("test")}},
+					Target: trustapi.BundleTarget{
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test"},
+						NamespaceSelector: &trustapi.NamespaceSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: metav1.LabelSelectorOpIn}},
+						},
+						NamespaceExcludeSelector: &trustapi.NamespaceSelector{},
+						Namespaces:               []string{"team-a"},
+					},
+				},
+			},
+			expEl: field.ErrorList{
+				field.Invalid(field.NewPath("spec", "target", "namespaceSelector", "matchExpressions"), []metav1.LabelSelectorRequirement{{Key: "team", Operator: metav1.LabelSelectorOpIn}}, "values: Invalid value: []string(nil): for 'in', 'notin' operators, values set can't be empty"),
+				field.Invalid(field.NewPath("spec", "target", "namespaceExcludeSelector"), &trustapi.NamespaceSelector{}, "target namespaceExcludeSelector must define matchLabels or matchExpressions"),
+				field.Forbidden(field.NewPath("spec", "target", "namespaces"), "target namespaces and namespaceSelector are mutually exclusive"),
+			},
+		},
 		"valid bundle": {
 			bundle: &trustapi.Bundle{
 				ObjectMeta: metav1.ObjectMeta{Name: "test-bundle-1"},
@@ -510,7 +1970,7 @@ func Test_validateBundle(t *testing.T) {
 						{InLine: pointer.String("test-1")},
 					},
 					Target: trustapi.BundleTarget{
-						ConfigMap: &trustapi.KeySelector{Key: "test-1"},
+						ConfigMap: &trustapi.TargetKeySelector{Key: "test-1"},
 						NamespaceSelector: &trustapi.NamespaceSelector{
 							MatchLabels: map[string]string{"foo": "bar"},
 						},
@@ -546,3 +2006,93 @@ func Test_validateBundle(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateBundle_targetNameTemplate(t *testing.T) {
+	conventions, err := naming.New(naming.Options{TargetNameTemplate: "trust-{{ .Name }}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := &trustapi.Bundle{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-bundle"},
+		Spec: trustapi.BundleSpec{
+			Sources: []trustapi.BundleSource{
+				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "test-bundle", Key: "test"}},
+				{ConfigMap: &trustapi.SourceObjectKeySelector{Name: "trust-test-bundle", Key: "test"}},
+			},
+			Target: trustapi.BundleTarget{ConfigMap: &trustapi.TargetKeySelector{Key: "test"}},
+		},
+	}
+
+	// The target is named from the template, so only the source with the
+	// rendered name is the same as the target.
+	el, err := (&validator{naming: conventions}).validateBundle(context.TODO(), bundle)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	assert.Equal(t, field.ErrorList{
+		field.Forbidden(field.NewPath("spec", "sources", "[1]", "configMap", "trust-test-bundle", "test"), "cannot define the same source as target"),
+	}, el)
+}
+
+func Test_validateNamespace(t *testing.T) {
+	path := field.NewPath("metadata", "annotations")
+
+	tests := map[string]struct {
+		annotations map[string]string
+		expEl       field.ErrorList
+		expWarnings []string
+	}{
+		"no annotations": {
+			annotations: nil,
+			expEl:       nil,
+			expWarnings: nil,
+		},
+		"unrelated annotations are ignored": {
+			annotations: map[string]string{"example.com/foo": "bar", "cert-manager.io/foo": "bar"},
+			expEl:       nil,
+			expWarnings: nil,
+		},
+		"valid annotations": {
+			annotations: map[string]string{
+				trustapi.NamespaceSkipTargetsAnnotationKey: "false",
+				trustapi.NamespaceTargetKeyAnnotationKey:   "ca-bundle.crt",
+			},
+			expEl:       nil,
+			expWarnings: nil,
+		},
+		"invalid annotation values": {
+			annotations: map[string]string{
+				trustapi.NamespaceSkipTargetsAnnotationKey: "yes",
+				trustapi.NamespaceTargetKeyAnnotationKey:   "not/a/key",
+			},
+			expEl: field.ErrorList{
+				field.NotSupported(path.Key(trustapi.NamespaceSkipTargetsAnnotationKey), "yes", []string{"true", "false"}),
+				field.Invalid(path.Key(trustapi.NamespaceTargetKeyAnnotationKey), "not/a/key", "a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')"),
+			},
+			expWarnings: nil,
+		},
+		"unknown trust.cert-manager.io annotations are warned about": {
+			annotations: map[string]string{
+				"trust.cert-manager.io/skip-target": "true",
+			},
+			expEl: nil,
+			expWarnings: []string{
+				`unknown annotation "trust.cert-manager.io/skip-target" is ignored by trust-manager; supported Namespace annotations are "trust.cert-manager.io/skip-targets" and "trust.cert-manager.io/target-key"`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			el, warnings := validateNamespace(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test", Annotations: test.annotations},
+			})
+
+			if !apiequality.Semantic.DeepEqual(test.expEl, el) {
+				t.Errorf("unexpected errorList: exp=%v got=%v", test.expEl, el)
+			}
+			assert.Equal(t, test.expWarnings, warnings)
+		})
+	}
+}
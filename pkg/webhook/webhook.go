@@ -17,21 +17,83 @@ limitations under the License.
 package webhook
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	trustapi "github.com/cert-manager/trust-manager/pkg/apis/trust/v1alpha1"
+	"github.com/cert-manager/trust-manager/pkg/naming"
 )
 
 // Options are options for running the wehook.
 type Options struct {
 	Log logr.Logger
+
+	// Namespace is the trust Namespace.
+	Namespace string
+
+	// ClientCABundle, if set, is the name of a Bundle whose target ConfigMap
+	// in the trust Namespace contains the CAs used to verify client
+	// certificates. If set, clients must present a certificate issued by one
+	// of these CAs.
+	ClientCABundle string
+
+	// Naming are the conventions for the names of the targets of Bundles,
+	// used to resolve the target ConfigMaps of validated, injected and client
+	// CA Bundles.
+	Naming *naming.Conventions
+
+	// DefaultAdditionalFormats, if set, are the additional formats which are
+	// set on Bundles which don't set them themselves.
+	DefaultAdditionalFormats *trustapi.AdditionalFormats
 }
 
 // Register the webhook endpoints against the Manager.
-func Register(mgr manager.Manager, opts Options) {
+func Register(mgr manager.Manager, opts Options) error {
 	opts.Log.Info("registering webhook endpoints")
 
-	validator := &validator{log: opts.Log.WithName("validation")}
+	if len(opts.ClientCABundle) > 0 {
+		loader := &clientCALoader{
+			log:       opts.Log.WithName("client-ca"),
+			reader:    mgr.GetAPIReader(),
+			namespace: opts.Namespace,
+			bundle:    opts.ClientCABundle,
+			naming:    opts.Naming,
+		}
+
+		if err := mgr.Add(loader); err != nil {
+			return fmt.Errorf("failed to add webhook client CA loader: %w", err)
+		}
+		if err := mgr.AddReadyzCheck("client_ca", loader.check); err != nil {
+			return fmt.Errorf("failed to add webhook client CA readiness check: %w", err)
+		}
+
+		server := mgr.GetWebhookServer()
+		server.TLSOpts = append(server.TLSOpts, loader.configureTLS)
+	}
+
+	validator := &validator{log: opts.Log.WithName("validation"), naming: opts.Naming}
 	mgr.GetWebhookServer().Register("/validate", &webhook.Admission{Handler: validator})
 	mgr.AddReadyzCheck("validator", validator.check)
+
+	if opts.DefaultAdditionalFormats != nil {
+		defaulter := &defaulter{
+			log:     opts.Log.WithName("defaulting"),
+			formats: opts.DefaultAdditionalFormats,
+		}
+		mgr.GetWebhookServer().Register("/default", &webhook.Admission{Handler: defaulter})
+		mgr.AddReadyzCheck("defaulter", defaulter.check)
+	}
+
+	injector := &injector{
+		log:    opts.Log.WithName("injection"),
+		reader: mgr.GetClient(),
+		naming: opts.Naming,
+	}
+	mgr.GetWebhookServer().Register("/inject", &webhook.Admission{Handler: injector})
+	mgr.AddReadyzCheck("injector", injector.check)
+
+	return nil
 }
@@ -19,6 +19,7 @@ limitations under the License.
 package dummy
 
 import (
+	"encoding/pem"
 	"strings"
 )
 
@@ -290,6 +291,110 @@ Z6tGn6D/Qqc6f1zLXbBwHSs09dR2CQzreExZBfMzQsNhFRAbd03OIozUhfJFfbdT
 2tIMPNuzjsmhDYAPexZ3FL//2wmUspO8IFgV6dtxQ/PeEMMA3KgqlbbC1j+Qa3bb
 bP6MvPJwNQzcmRk13NfIRmPVNnGuV/u3gm3c
 -----END CERTIFICATE-----`
+
+	// NB: TestLeafCertificate is expected to have the following properties:
+	// 1. Not a CA (basicConstraints CA:FALSE)
+	// 2. Self signed (issuer == subject)
+	// Certificate:
+	//     Data:
+	//         Version: 3 (0x2)
+	//         Serial Number: 64095347637903 (0x3a4b5c6d7e8f)
+	//         Signature Algorithm: ecdsa-with-SHA256
+	//         Issuer: O = cert-manager, CN = cmct-test-leaf
+	//         Validity
+	//             Not Before: Oct 14 18:52:12 2026 GMT
+	//             Not After : Oct 11 18:52:12 2036 GMT
+	//         Subject: O = cert-manager, CN = cmct-test-leaf
+	//         Subject Public Key Info:
+	//             Public Key Algorithm: id-ecPublicKey
+	//                 Public-Key: (256 bit)
+	//                 ASN1 OID: prime256v1
+	//                 NIST CURVE: P-256
+	//         X509v3 extensions:
+	//             X509v3 Subject Key Identifier:
+	//                 39:BC:48:2F:EC:97:9D:9B:5D:56:E4:83:A6:FB:FA:A4:AE:71:BC:BF
+	//             X509v3 Basic Constraints: critical
+	//                 CA:FALSE
+	//             X509v3 Key Usage: critical
+	//                 Digital Signature
+	//             X509v3 Extended Key Usage:
+	//                 TLS Web Server Authentication
+	TestLeafCertificate = `-----BEGIN CERTIFICATE-----
+MIIByjCCAW+gAwIBAgIGOktcbX6PMAoGCCqGSM49BAMCMDAxFTATBgNVBAoMDGNl
+cnQtbWFuYWdlcjEXMBUGA1UEAwwOY21jdC10ZXN0LWxlYWYwHhcNMjYxMDE0MTg1
+MjEyWhcNMzYxMDExMTg1MjEyWjAwMRUwEwYDVQQKDAxjZXJ0LW1hbmFnZXIxFzAV
+BgNVBAMMDmNtY3QtdGVzdC1sZWFmMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE
+UZj+SBbFDiIxwVwcZvZaN/eE0C9OWH6sXj41D4jL/rFPxn+KNFsOVBAKgwl/JD3O
+ZyaL04SuauAiRw3/b31qBaN1MHMwHQYDVR0OBBYEFDm8SC/sl52bXVbkg6b7+qSu
+cby/MB8GA1UdIwQYMBaAFDm8SC/sl52bXVbkg6b7+qSucby/MAwGA1UdEwEB/wQC
+MAAwDgYDVR0PAQH/BAQDAgeAMBMGA1UdJQQMMAoGCCsGAQUFBwMBMAoGCCqGSM49
+BAMCA0kAMEYCIQCOX0sNIlBeoE37dTECGaoVK6tZCUTfJBnLfraptmyTUAIhAODq
+fsQxF+XjaDG5bABkw25sAo9NMbpp/8Hqj3vsZnLo
+-----END CERTIFICATE-----`
+
+	// NB: TestIstioRootCertificate is expected to have the following properties:
+	// 1. A CA (basicConstraints CA:TRUE)
+	// 2. Self signed (issuer == subject)
+	// 3. The issuer of TestIntermediateCertificate
+	//         Issuer: O = cert-manager, CN = cmct-test-istio-root
+	//         Subject: O = cert-manager, CN = cmct-test-istio-root
+	TestIstioRootCertificate = `-----BEGIN CERTIFICATE-----
+MIIBwzCCAWmgAwIBAgIUMXEDxelPlb4bzJ2q/UAkyYJBI10wCgYIKoZIzj0EAwIw
+NjEVMBMGA1UECgwMY2VydC1tYW5hZ2VyMR0wGwYDVQQDDBRjbWN0LXRlc3QtaXN0
+aW8tcm9vdDAgFw0yNjEwMTQxOTM1NThaGA8yMTI2MDkyMDE5MzU1OFowNjEVMBMG
+A1UECgwMY2VydC1tYW5hZ2VyMR0wGwYDVQQDDBRjbWN0LXRlc3QtaXN0aW8tcm9v
+dDBZMBMGByqGSM49AgEGCCqGSM49AwEHA0IABIiCRJbpjwWxAirlfKzFj95cm6Qb
+TXOB9QDVyHXUu6K1v8eNRYQYdw8fZrxsLB5UJVRM8huGmdEwBfz7kiyL7TujUzBR
+MB0GA1UdDgQWBBRVtAuf4BL1YkqpvhicE+/Dp2DXMTAfBgNVHSMEGDAWgBRVtAuf
+4BL1YkqpvhicE+/Dp2DXMTAPBgNVHRMBAf8EBTADAQH/MAoGCCqGSM49BAMCA0gA
+MEUCIQDc1kvHmzBpFcixNBISnufpJemRd7Mgy3+jzERRCNHAdAIgeUASZRiRMJWZ
+isHfyzhtLXVhfmpjlAPB/9P4JMB3tv8=
+-----END CERTIFICATE-----`
+
+	// NB: TestIntermediateCertificate is expected to have the following properties:
+	// 1. A CA (basicConstraints CA:TRUE)
+	// 2. Not self signed, issued by TestIstioRootCertificate
+	//         Issuer: O = cert-manager, CN = cmct-test-istio-root
+	//         Subject: O = cert-manager, CN = cmct-test-intermediate
+	//         X509v3 Basic Constraints: critical
+	//             CA:TRUE
+	//         X509v3 Key Usage: critical
+	//             Certificate Sign, CRL Sign
+	TestIntermediateCertificate = `-----BEGIN CERTIFICATE-----
+MIIB1jCCAXugAwIBAgIUaNF36JCN1HbR4P4q8/h3L89MNlIwCgYIKoZIzj0EAwIw
+NjEVMBMGA1UECgwMY2VydC1tYW5hZ2VyMR0wGwYDVQQDDBRjbWN0LXRlc3QtaXN0
+aW8tcm9vdDAgFw0yNjEwMTQxOTM1NThaGA8yMTI2MDkyMDE5MzU1OFowODEVMBMG
+A1UECgwMY2VydC1tYW5hZ2VyMR8wHQYDVQQDDBZjbWN0LXRlc3QtaW50ZXJtZWRp
+YXRlMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEJXYtYeNJzX6Ca11bfF/lJ6Uk
+XGCtM2LF6DF8e7C11VsfYNiLzUdG/yQzLyRB/4TqXfDSi6LnFALmYFyr5f1RPqNj
+MGEwDwYDVR0TAQH/BAUwAwEB/zAOBgNVHQ8BAf8EBAMCAQYwHQYDVR0OBBYEFK3k
+rzTLen3PfkZWkMYzyq605jAVMB8GA1UdIwQYMBaAFFW0C5/gEvViSqm+GJwT78On
+YNcxMAoGCCqGSM49BAMCA0kAMEYCIQDxbcQBysTrgIy97xeqhSvkxCkEZgB6Z/J5
+Mr/a6VfEOgIhAK5e8gd399hzv5Cg5nLZy2h74vOgt9id+98MID/jUBtw
+-----END CERTIFICATE-----`
+
+	// TestPKCS7Bundle is a certificate-only PKCS#7 bundle containing
+	// TestCertificate1 and TestCertificate2, generated with:
+	// openssl crl2pkcs7 -nocrl -certfile cert1.pem -certfile cert2.pem
+	TestPKCS7Bundle = `-----BEGIN PKCS7-----
+MIIDGgYJKoZIhvcNAQcCoIIDCzCCAwcCAQExADALBgkqhkiG9w0BBwGgggLvMIIB
+kzCCATmgAwIBAgIQD3oJqHEJAjT25rEGY6kLgTAKBggqhkjOPQQDAjAwMRUwEwYD
+VQQKEwxjZXJ0LW1hbmFnZXIxFzAVBgNVBAMTDmNtY3QtdGVzdC1yb290MB4XDTIy
+MTEyNTEzMDM1NFoXDTMyMTEyMjEzMDM1NFowMDEVMBMGA1UEChMMY2VydC1tYW5h
+Z2VyMRcwFQYDVQQDEw5jbWN0LXRlc3Qtcm9vdDBZMBMGByqGSM49AgEGCCqGSM49
+AwEHA0IABG0axFSG2TE+I2BP2vwdXc79oUCTUewsddgZOq2f+dKjWU5XyPNcEAxM
+p37tVjQvsC4cRYEo+uYSmMVcQi4kkVGjNTAzMBIGA1UdEwEB/wQIMAYBAf8CAQMw
+HQYDVR0OBBYEFNcEG2uzzT9bczLSnPuEe98nJkVQMAoGCCqGSM49BAMCA0gAMEUC
+IQCeN2/Z7jSJJK7m7kcZ/UgJIqbzKS1ktycUQ50+dhqNogIgaTYRjIxZFJ3uVhGz
+jAqH8YyuEObapwh4bTZkapwoDZQwggFUMIIBBqADAgECAhEA1yizVzXYJdMKbyrJ
+m2jYuzAFBgMrZXAwMDEVMBMGA1UEChMMY2VydC1tYW5hZ2VyMRcwFQYDVQQDEw5j
+bWN0LXRlc3Qtcm9vdDAeFw0yMjEyMDUxNjIyNDJaFw0zMjEyMDIxNjIyNDJaMDAx
+FTATBgNVBAoTDGNlcnQtbWFuYWdlcjEXMBUGA1UEAxMOY21jdC10ZXN0LXJvb3Qw
+KjAFBgMrZXADIQBaNUO73j3kpniDRgUn3iOCAau3c0VdaToxvnWkIHKVLKM1MDMw
+EgYDVR0TAQH/BAgwBgEB/wIBAzAdBgNVHQ4EFgQUWMKqtNVWlBF0EA0vOB0rHdqB
+bEgwBQYDK2VwA0EASpvk+aFe2UCDJKKEbmCUHI7llqMUE0EiGM4Xr7J83UGjleMn
+tsbBUiEahE8cK1u+yd+5DnJLP3kIUPUEi1GdAzEA
+-----END PKCS7-----`
 )
 
 func DefaultJoinedCerts() string {
@@ -303,3 +408,15 @@ func DefaultJoinedCerts() string {
 func JoinCerts(certs ...string) string {
 	return strings.Join(certs, "\n") + "\n"
 }
+
+// JoinCertsDER returns the concatenated DER encoding of the given PEM-encoded
+// certificates.
+func JoinCertsDER(certs ...string) []byte {
+	var der []byte
+	for _, cert := range certs {
+		block, _ := pem.Decode([]byte(cert))
+		der = append(der, block.Bytes...)
+	}
+
+	return der
+}
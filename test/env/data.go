@@ -60,7 +60,7 @@ type TestData struct {
 		}
 	}
 
-	Target trustapi.KeySelector
+	Target trustapi.TargetKeySelector
 }
 
 // DefaultTrustData returns a well-known set of default data for a test.
@@ -111,15 +111,15 @@ func NewTestBundle(ctx context.Context, cl client.Client, opts bundle.Options, t
 			Sources: []trustapi.BundleSource{
 				{
 					ConfigMap: &trustapi.SourceObjectKeySelector{
-						Name:        configMap.Name,
-						KeySelector: trustapi.KeySelector{Key: td.Sources.ConfigMap.Key},
+						Name: configMap.Name,
+						Key:  td.Sources.ConfigMap.Key,
 					},
 				},
 
 				{
 					Secret: &trustapi.SourceObjectKeySelector{
-						Name:        secret.Name,
-						KeySelector: trustapi.KeySelector{Key: td.Sources.Secret.Key},
+						Name: secret.Name,
+						Key:  td.Sources.Secret.Key,
 					},
 				},
 
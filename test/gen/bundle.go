@@ -85,6 +85,22 @@ func SetBundleTargetNamespaceSelectorMatchLabels(matchLabels map[string]string)
 	}
 }
 
+// SetBundleMaintenanceWindows sets the Bundle object's spec maintenance
+// windows.
+func SetBundleMaintenanceWindows(windows ...trustapi.MaintenanceWindow) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.MaintenanceWindows = windows
+	}
+}
+
+// SetBundleTargetConflictPolicy sets the Bundle object's spec target conflict
+// policy.
+func SetBundleTargetConflictPolicy(policy trustapi.TargetConflictPolicy) BundleModifier {
+	return func(bundle *trustapi.Bundle) {
+		bundle.Spec.Target.ConflictPolicy = policy
+	}
+}
+
 // AppendBundleUsesDefaultPackage appends a source to the bundle which requests the default bundle package.
 func AppendBundleUsesDefaultPackage() BundleModifier {
 	return func(bundle *trustapi.Bundle) {
@@ -166,7 +166,7 @@ var _ = Describe("Integration", func() {
 
 		Expect(cl.Get(ctx, client.ObjectKeyFromObject(testBundle), testBundle)).ToNot(HaveOccurred())
 		testBundle.Spec.Sources = append(testBundle.Spec.Sources, trustapi.BundleSource{
-			ConfigMap: &trustapi.SourceObjectKeySelector{Name: "new-bundle-source", KeySelector: trustapi.KeySelector{Key: "new-source-key"}},
+			ConfigMap: &trustapi.SourceObjectKeySelector{Name: "new-bundle-source", Key: "new-source-key"},
 		})
 		Expect(cl.Update(ctx, testBundle)).NotTo(HaveOccurred())
 
@@ -189,7 +189,7 @@ var _ = Describe("Integration", func() {
 		Expect(cl.Get(ctx, client.ObjectKeyFromObject(testBundle), testBundle)).ToNot(HaveOccurred())
 
 		testBundle.Spec.Sources = append(testBundle.Spec.Sources, trustapi.BundleSource{
-			Secret: &trustapi.SourceObjectKeySelector{Name: "new-bundle-source", KeySelector: trustapi.KeySelector{Key: "new-source-key"}},
+			Secret: &trustapi.SourceObjectKeySelector{Name: "new-bundle-source", Key: "new-source-key"},
 		})
 		Expect(cl.Update(ctx, testBundle)).NotTo(HaveOccurred())
 
@@ -293,7 +293,7 @@ var _ = Describe("Integration", func() {
 
 	It("should delete old targets and update to new ones when the Spec.Target is modified", func() {
 		testBundle.Spec.Target = trustapi.BundleTarget{
-			ConfigMap: &trustapi.KeySelector{Key: "changed-target-key"},
+			ConfigMap: &trustapi.TargetKeySelector{Key: "changed-target-key"},
 		}
 
 		Expect(cl.Update(ctx, testBundle)).ToNot(HaveOccurred())
@@ -319,9 +319,9 @@ var _ = Describe("Integration", func() {
 
 	It("should delete old targets and update to new ones when a JKS file is requested in the target", func() {
 		testBundle.Spec.Target = trustapi.BundleTarget{
-			ConfigMap: &trustapi.KeySelector{Key: testData.Target.Key},
+			ConfigMap: &trustapi.TargetKeySelector{Key: testData.Target.Key},
 			AdditionalFormats: &trustapi.AdditionalFormats{
-				JKS: &trustapi.KeySelector{Key: "myfile.jks"},
+				JKS: &trustapi.JKS{KeySelector: trustapi.KeySelector{Key: "myfile.jks"}},
 			},
 		}
 